@@ -20,12 +20,14 @@ import (
 	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
 	apmv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1beta1"
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	esv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1beta1"
 	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
 	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
 	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/agent"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/apmserver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
@@ -47,6 +49,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/kibana"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/license"
 	licensetrial "github.com/elastic/cloud-on-k8s/pkg/controller/license/trial"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/maps"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/remoteca"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/webhook"
 	"github.com/elastic/cloud-on-k8s/pkg/dev"
@@ -128,6 +131,16 @@ func Command() *cobra.Command {
 		RunE: doRun,
 	}
 
+	cmd.Flags().Duration(
+		operator.APIKeyRotateBeforeFlag,
+		association.DefaultAPIKeyRotateBefore,
+		"Duration representing how long before expiration API keys created for associations should be replaced",
+	)
+	cmd.Flags().Duration(
+		operator.APIKeyValidityFlag,
+		association.DefaultAPIKeyValidity,
+		"Duration representing how long before a newly created API key for an association expires",
+	)
 	cmd.Flags().Bool(
 		operator.AutoPortForwardFlag,
 		false,
@@ -144,6 +157,16 @@ func Command() *cobra.Command {
 		certificates.DefaultCertValidity,
 		"Duration representing how long before a newly created CA cert expires",
 	)
+	cmd.Flags().String(
+		operator.CertKeyAlgorithmFlag,
+		string(certificates.DefaultPrivateKeyOptions.Algorithm),
+		fmt.Sprintf("Algorithm of the private keys generated for TLS certificates, either %s or %s", commonv1.RSAPrivateKeyAlgorithm, commonv1.ECDSAPrivateKeyAlgorithm),
+	)
+	cmd.Flags().Int(
+		operator.CertKeySizeFlag,
+		0,
+		fmt.Sprintf("Size in bits of the private keys generated for TLS certificates (defaults to %d for RSA, %d for ECDSA)", certificates.DefaultRSAKeySize, certificates.DefaultECDSAKeySize),
+	)
 	cmd.Flags().Duration(
 		operator.CertRotateBeforeFlag,
 		certificates.DefaultRotateBefore,
@@ -508,6 +531,25 @@ func startOperator(ctx context.Context) error {
 
 	log.V(1).Info("Using certificate rotation parameters", operator.CertValidityFlag, certValidity, operator.CertRotateBeforeFlag, certRotateBefore)
 
+	apiKeyValidity, apiKeyRotateBefore, err := validateCertExpirationFlags(operator.APIKeyValidityFlag, operator.APIKeyRotateBeforeFlag)
+	if err != nil {
+		log.Error(err, "Invalid API key rotation parameters")
+		return err
+	}
+
+	log.V(1).Info("Using API key rotation parameters", operator.APIKeyValidityFlag, apiKeyValidity, operator.APIKeyRotateBeforeFlag, apiKeyRotateBefore)
+
+	privateKeyOptions := certificates.WithDefaults(commonv1.PrivateKeyOptions{
+		Algorithm: commonv1.PrivateKeyAlgorithm(viper.GetString(operator.CertKeyAlgorithmFlag)),
+		Size:      viper.GetInt(operator.CertKeySizeFlag),
+	})
+	if err := certificates.ValidatePrivateKeyOptions(privateKeyOptions); err != nil {
+		log.Error(err, "Invalid certificate private key parameters")
+		return err
+	}
+
+	log.V(1).Info("Using certificate private key parameters", operator.CertKeyAlgorithmFlag, privateKeyOptions.Algorithm, operator.CertKeySizeFlag, privateKeyOptions.Size)
+
 	ipFamily, err := chooseAndValidateIPFamily(viper.GetString(operator.IPFamilyFlag), net.ToIPFamily(os.Getenv(settings.EnvPodIP)))
 	if err != nil {
 		log.Error(err, "Invalid IP family parameter")
@@ -546,6 +588,11 @@ func startOperator(ctx context.Context) error {
 			Validity:     certValidity,
 			RotateBefore: certRotateBefore,
 		},
+		APIKeyRotation: certificates.RotationParams{
+			Validity:     apiKeyValidity,
+			RotateBefore: apiKeyRotateBefore,
+		},
+		PrivateKeyOptions:         privateKeyOptions,
 		MaxConcurrentReconciles:   viper.GetInt(operator.MaxConcurrentReconcilesFlag),
 		SetDefaultSecurityContext: viper.GetBool(operator.SetDefaultSecurityContextFlag),
 		ValidateStorageClass:      viper.GetBool(operator.ValidateStorageClassFlag),
@@ -553,7 +600,7 @@ func startOperator(ctx context.Context) error {
 	}
 
 	if viper.GetBool(operator.EnableWebhookFlag) {
-		setupWebhook(mgr, params.CertRotation, params.ValidateStorageClass, clientset)
+		setupWebhook(mgr, params.CertRotation, params.PrivateKeyOptions, params.ValidateStorageClass, clientset)
 	}
 
 	enforceRbacOnRefs := viper.GetBool(operator.EnforceRBACOnRefsFlag)
@@ -652,6 +699,7 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "License", registerFunc: license.Add},
 		{name: "LicenseTrial", registerFunc: licensetrial.Add},
 		{name: "Agent", registerFunc: agent.Add},
+		{name: "Maps", registerFunc: maps.Add},
 	}
 
 	for _, c := range controllers {
@@ -673,6 +721,16 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "BEAT-ES", registerFunc: associationctl.AddBeatES},
 		{name: "BEAT-KB", registerFunc: associationctl.AddBeatKibana},
 		{name: "AGENT-ES", registerFunc: associationctl.AddAgentES},
+		{name: "AGENT-KB", registerFunc: associationctl.AddAgentKibana},
+		{name: "AGENT-FS", registerFunc: associationctl.AddAgentFleetServer},
+		{name: "EMS-ES", registerFunc: associationctl.AddMapsES},
+		{name: "KB-EMS", registerFunc: associationctl.AddKibanaMaps},
+		{name: "KB-ENT", registerFunc: associationctl.AddKibanaEnt},
+		{name: "ES-MONITORING", registerFunc: associationctl.AddEsMonitoring},
+		{name: "KB-MONITORING", registerFunc: associationctl.AddKbMonitoring},
+		{name: "APM-MONITORING", registerFunc: associationctl.AddApmMonitoring},
+		{name: "ENT-MONITORING", registerFunc: associationctl.AddEntMonitoring},
+		{name: "BEAT-MONITORING", registerFunc: associationctl.AddBeatMonitoring},
 	}
 
 	for _, c := range assocControllers {
@@ -708,6 +766,12 @@ func garbageCollectUsers(cfg *rest.Config, managedNamespaces []string) {
 		For(&entv1.EnterpriseSearchList{}, associationctl.EntESAssociationLabelNamespace, associationctl.EntESAssociationLabelName).
 		For(&beatv1beta1.BeatList{}, associationctl.BeatAssociationLabelNamespace, associationctl.BeatAssociationLabelName).
 		For(&agentv1alpha1.AgentList{}, associationctl.AgentAssociationLabelNamespace, associationctl.AgentAssociationLabelName).
+		For(&emsv1alpha1.ElasticMapsServerList{}, associationctl.MapsESAssociationLabelNamespace, associationctl.MapsESAssociationLabelName).
+		For(&esv1.ElasticsearchList{}, associationctl.EsMonitoringAssociationLabelNamespace, associationctl.EsMonitoringAssociationLabelName).
+		For(&kbv1.KibanaList{}, associationctl.KbMonitoringAssociationLabelNamespace, associationctl.KbMonitoringAssociationLabelName).
+		For(&apmv1.ApmServerList{}, associationctl.ApmMonitoringAssociationLabelNamespace, associationctl.ApmMonitoringAssociationLabelName).
+		For(&entv1.EnterpriseSearchList{}, associationctl.EntMonitoringAssociationLabelNamespace, associationctl.EntMonitoringAssociationLabelName).
+		For(&beatv1beta1.BeatList{}, associationctl.BeatMonitoringAssociationLabelNamespace, associationctl.BeatMonitoringAssociationLabelName).
 		DoGarbageCollection()
 	if err != nil {
 		log.Error(err, "user garbage collector failed")
@@ -723,6 +787,7 @@ func garbageCollectSoftOwnedSecrets(k8sClient k8s.Client) {
 		entv1.Kind:         &entv1.EnterpriseSearch{},
 		beatv1beta1.Kind:   &beatv1beta1.Beat{},
 		agentv1alpha1.Kind: &agentv1alpha1.Agent{},
+		emsv1alpha1.Kind:   &emsv1alpha1.ElasticMapsServer{},
 	}); err != nil {
 		log.Error(err, "Orphan secrets garbage collection failed, will be attempted again at next operator restart.")
 		return
@@ -730,16 +795,23 @@ func garbageCollectSoftOwnedSecrets(k8sClient k8s.Client) {
 	log.Info("Orphan secrets garbage collection complete")
 }
 
-func setupWebhook(mgr manager.Manager, certRotation certificates.RotationParams, validateStorageClass bool, clientset kubernetes.Interface) {
+func setupWebhook(
+	mgr manager.Manager,
+	certRotation certificates.RotationParams,
+	privateKeyOptions commonv1.PrivateKeyOptions,
+	validateStorageClass bool,
+	clientset kubernetes.Interface,
+) {
 	manageWebhookCerts := viper.GetBool(operator.ManageWebhookCertsFlag)
 	if manageWebhookCerts {
 		log.Info("Automatic management of the webhook certificates enabled")
 		// Ensure that all the certificates needed by the webhook server are already created
 		webhookParams := webhook.Params{
-			Name:       viper.GetString(operator.WebhookNameFlag),
-			Namespace:  viper.GetString(operator.OperatorNamespaceFlag),
-			SecretName: viper.GetString(operator.WebhookSecretFlag),
-			Rotation:   certRotation,
+			Name:              viper.GetString(operator.WebhookNameFlag),
+			Namespace:         viper.GetString(operator.OperatorNamespaceFlag),
+			SecretName:        viper.GetString(operator.WebhookSecretFlag),
+			Rotation:          certRotation,
+			PrivateKeyOptions: privateKeyOptions,
 		}
 
 		// Force a first reconciliation to create the resources before the server is started
@@ -763,6 +835,7 @@ func setupWebhook(mgr manager.Manager, certRotation certificates.RotationParams,
 		&apmv1.ApmServer{},
 		&apmv1beta1.ApmServer{},
 		&beatv1beta1.Beat{},
+		&emsv1alpha1.ElasticMapsServer{},
 		&entv1.EnterpriseSearch{},
 		&entv1beta1.EnterpriseSearch{},
 		&esv1beta1.Elasticsearch{},
@@ -67,8 +67,43 @@ type AgentSpec struct {
 	// Cannot be used along with `daemonSet`.
 	// +kubebuilder:validation:Optional
 	Deployment *DeploymentSpec `json:"deployment,omitempty"`
+
+	// HTTP holds the HTTP layer configuration for the Agent in Fleet mode with Fleet Server enabled.
+	// +kubebuilder:validation:Optional
+	HTTP commonv1.HTTPConfig `json:"http,omitempty"`
+
+	// Mode specifies the source of configuration for the Agent. The configuration can be specified locally through
+	// `config` or `configRef` (`standalone` mode), or come from Fleet during runtime (`fleet` mode).
+	// Defaults to `standalone` mode.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=standalone;fleet
+	Mode AgentMode `json:"mode,omitempty"`
+
+	// FleetServerEnabled determines whether this Agent will launch Fleet Server. Don't set unless `mode` is set to `fleet`.
+	// +kubebuilder:validation:Optional
+	FleetServerEnabled bool `json:"fleetServerEnabled,omitempty"`
+
+	// KibanaRef is a reference to Kibana where Fleet should be set up and this Agent should be enrolled. Don't set
+	// unless `mode` is set to `fleet`.
+	// +kubebuilder:validation:Optional
+	KibanaRef commonv1.ObjectSelector `json:"kibanaRef,omitempty"`
+
+	// FleetServerRef is a reference to Fleet Server that this Agent should connect to to obtain its configuration.
+	// Don't set unless `mode` is set to `fleet`.
+	// +kubebuilder:validation:Optional
+	FleetServerRef commonv1.ObjectSelector `json:"fleetServerRef,omitempty"`
 }
 
+// AgentMode specifies the source of configuration for the Agent.
+type AgentMode string
+
+const (
+	// AgentStandaloneMode denotes running the Agent as standalone, with the configuration provided in the resource.
+	AgentStandaloneMode AgentMode = "standalone"
+	// AgentFleetMode denotes running the Agent with Fleet, the configuration is provided by Fleet Server.
+	AgentFleetMode AgentMode = "fleet"
+)
+
 type Output struct {
 	commonv1.ObjectSelector `json:",omitempty,inline"`
 	OutputName              string `json:"outputName,omitempty"`
@@ -106,6 +141,22 @@ type AgentStatus struct {
 
 	// +kubebuilder:validation:Optional
 	ElasticsearchAssociationsStatus commonv1.AssociationStatusMap `json:"elasticsearchAssociationsStatus,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	KibanaAssociationStatus commonv1.AssociationStatus `json:"kibanaAssociationStatus,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	FleetServerAssociationStatus commonv1.AssociationStatus `json:"fleetServerAssociationStatus,omitempty"`
+
+	// Conditions holds the latest available observations of the state of the Agent.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the metadata generation of the Agent last processed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 type AgentHealth string
@@ -140,9 +191,11 @@ type Agent struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec       AgentSpec                                         `json:"spec,omitempty"`
-	Status     AgentStatus                                       `json:"status,omitempty"`
-	assocConfs map[types.NamespacedName]commonv1.AssociationConf `json:"-"` // nolint:govet
+	Spec        AgentSpec                                         `json:"spec,omitempty"`
+	Status      AgentStatus                                       `json:"status,omitempty"`
+	assocConfs  map[types.NamespacedName]commonv1.AssociationConf `json:"-"` // nolint:govet
+	kbAssocConf *commonv1.AssociationConf                         `json:"-"` // nolint:govet
+	fsAssocConf *commonv1.AssociationConf                         `json:"-"` // nolint:govet
 }
 
 // +kubebuilder:object:root=true
@@ -154,13 +207,45 @@ func (a *Agent) GetAssociations() []commonv1.Association {
 	for _, ref := range a.Spec.ElasticsearchRefs {
 		associations = append(associations, &AgentESAssociation{
 			Agent: a,
-			ref:   ref.WithDefaultNamespace(a.Namespace).NamespacedName(),
+			ref:   ref.WithDefaultNamespace(a.Namespace),
+		})
+	}
+
+	if a.Spec.KibanaRef.IsDefined() {
+		associations = append(associations, &AgentKibanaAssociation{
+			Agent: a,
+		})
+	}
+
+	if a.Spec.FleetServerRef.IsDefined() {
+		associations = append(associations, &AgentFleetServerAssociation{
+			Agent: a,
 		})
 	}
 
 	return associations
 }
 
+// KibanaAssociation returns the association to Kibana used to set up Fleet and enroll this Agent.
+func (a *Agent) KibanaAssociation() *AgentKibanaAssociation {
+	return &AgentKibanaAssociation{Agent: a}
+}
+
+// FleetServerAssociation returns the association to the Fleet Server this Agent enrolls into.
+func (a *Agent) FleetServerAssociation() *AgentFleetServerAssociation {
+	return &AgentFleetServerAssociation{Agent: a}
+}
+
+// FleetModeEnabled returns true if the Agent is configured to run in Fleet mode.
+func (a *Agent) FleetModeEnabled() bool {
+	return a.Spec.Mode == AgentFleetMode
+}
+
+// StandaloneModeEnabled returns true if the Agent is configured to run in standalone mode, which is the default.
+func (a *Agent) StandaloneModeEnabled() bool {
+	return a.Spec.Mode == "" || a.Spec.Mode == AgentStandaloneMode
+}
+
 func (a *Agent) ServiceAccountName() string {
 	return a.Spec.ServiceAccountName
 }
@@ -171,20 +256,44 @@ func (a *Agent) IsMarkedForDeletion() bool {
 }
 
 func (a *Agent) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
-	if typ != commonv1.ElasticsearchAssociationType {
-		return commonv1.AssociationStatusMap{}
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		return a.Status.ElasticsearchAssociationsStatus
+	case commonv1.KibanaAssociationType:
+		if a.Spec.KibanaRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(a.Status.KibanaAssociationStatus)
+		}
+	case commonv1.FleetServerAssociationType:
+		if a.Spec.FleetServerRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(a.Status.FleetServerAssociationStatus)
+		}
 	}
 
-	return a.Status.ElasticsearchAssociationsStatus
+	return commonv1.AssociationStatusMap{}
 }
 
 func (a *Agent) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
-	if typ != commonv1.ElasticsearchAssociationType {
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		a.Status.ElasticsearchAssociationsStatus = status
+		return nil
+	case commonv1.KibanaAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
+		a.Status.KibanaAssociationStatus = single
+		return nil
+	case commonv1.FleetServerAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
+		a.Status.FleetServerAssociationStatus = single
+		return nil
+	default:
 		return fmt.Errorf("association type %s not known", typ)
 	}
-
-	a.Status.ElasticsearchAssociationsStatus = status
-	return nil
 }
 
 func (a *Agent) SecureSettings() []commonv1.SecretSource {
@@ -193,12 +302,12 @@ func (a *Agent) SecureSettings() []commonv1.SecretSource {
 
 type AgentESAssociation struct {
 	*Agent
-	// ref is the namespaced name of the Elasticsearch used in Association
-	ref types.NamespacedName
+	// ref is the reference to the Elasticsearch used in Association, with a default namespace
+	ref commonv1.ObjectSelector
 }
 
 func (aea *AgentESAssociation) AssociationID() string {
-	return fmt.Sprintf("%s-%s", aea.ref.Namespace, aea.ref.Name)
+	return fmt.Sprintf("%s-%s", aea.ref.Namespace, aea.ref.NameOrSecretName())
 }
 
 var _ commonv1.Association = &AgentESAssociation{}
@@ -218,10 +327,7 @@ func (aea *AgentESAssociation) AssociationType() commonv1.AssociationType {
 }
 
 func (aea *AgentESAssociation) AssociationRef() commonv1.ObjectSelector {
-	return commonv1.ObjectSelector{
-		Name:      aea.ref.Name,
-		Namespace: aea.ref.Namespace,
-	}
+	return aea.ref
 }
 
 func (aea *AgentESAssociation) AssociationConfAnnotationName() string {
@@ -230,7 +336,7 @@ func (aea *AgentESAssociation) AssociationConfAnnotationName() string {
 
 	nsNameHash := sha256.New224()
 	// concat with dot to avoid collisions, as namespace can't contain dots
-	_, _ = nsNameHash.Write([]byte(fmt.Sprintf("%s.%s", aea.ref.Namespace, aea.ref.Name)))
+	_, _ = nsNameHash.Write([]byte(fmt.Sprintf("%s.%s", aea.ref.Namespace, aea.ref.NameOrSecretName())))
 	// base32 to encode and limit the length, as using Sprintf with "%x" encodes with base16 which happens to
 	// give too long output
 	// no padding to avoid illegal '=' character in the annotation name
@@ -246,7 +352,7 @@ func (aea *AgentESAssociation) AssociationConf() *commonv1.AssociationConf {
 	if aea.assocConfs == nil {
 		return nil
 	}
-	assocConf, found := aea.assocConfs[aea.ref]
+	assocConf, found := aea.assocConfs[aea.ref.NamespacedName()]
 	if !found {
 		return nil
 	}
@@ -259,10 +365,92 @@ func (aea *AgentESAssociation) SetAssociationConf(conf *commonv1.AssociationConf
 		aea.assocConfs = make(map[types.NamespacedName]commonv1.AssociationConf)
 	}
 	if conf != nil {
-		aea.assocConfs[aea.ref] = *conf
+		aea.assocConfs[aea.ref.NamespacedName()] = *conf
 	}
 }
 
+// AgentKibanaAssociation helps to manage the Agent / Kibana association used to set up Fleet.
+type AgentKibanaAssociation struct {
+	*Agent
+}
+
+var _ commonv1.Association = &AgentKibanaAssociation{}
+
+func (a *AgentKibanaAssociation) Associated() commonv1.Associated {
+	if a == nil {
+		return nil
+	}
+	if a.Agent == nil {
+		a.Agent = &Agent{}
+	}
+	return a.Agent
+}
+
+func (a *AgentKibanaAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.KibanaAssociationType
+}
+
+func (a *AgentKibanaAssociation) AssociationRef() commonv1.ObjectSelector {
+	return a.Spec.KibanaRef.WithDefaultNamespace(a.Namespace)
+}
+
+func (a *AgentKibanaAssociation) AssociationConfAnnotationName() string {
+	return commonv1.FormatNameWithID(commonv1.KibanaConfigAnnotationNameBase+"%s", a.AssociationID())
+}
+
+func (a *AgentKibanaAssociation) AssociationConf() *commonv1.AssociationConf {
+	return a.kbAssocConf
+}
+
+func (a *AgentKibanaAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	a.kbAssocConf = conf
+}
+
+func (a *AgentKibanaAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+// AgentFleetServerAssociation helps to manage the Agent / Fleet Server association.
+type AgentFleetServerAssociation struct {
+	*Agent
+}
+
+var _ commonv1.Association = &AgentFleetServerAssociation{}
+
+func (a *AgentFleetServerAssociation) Associated() commonv1.Associated {
+	if a == nil {
+		return nil
+	}
+	if a.Agent == nil {
+		a.Agent = &Agent{}
+	}
+	return a.Agent
+}
+
+func (a *AgentFleetServerAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.FleetServerAssociationType
+}
+
+func (a *AgentFleetServerAssociation) AssociationRef() commonv1.ObjectSelector {
+	return a.Spec.FleetServerRef.WithDefaultNamespace(a.Namespace)
+}
+
+func (a *AgentFleetServerAssociation) AssociationConfAnnotationName() string {
+	return commonv1.FormatNameWithID(commonv1.FleetServerConfigAnnotationNameBase+"%s", a.AssociationID())
+}
+
+func (a *AgentFleetServerAssociation) AssociationConf() *commonv1.AssociationConf {
+	return a.fsAssocConf
+}
+
+func (a *AgentFleetServerAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	a.fsAssocConf = conf
+}
+
+func (a *AgentFleetServerAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
 var _ commonv1.Associated = &Agent{}
 
 // +kubebuilder:object:root=true
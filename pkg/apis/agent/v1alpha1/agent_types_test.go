@@ -8,24 +8,24 @@ import (
 	"strings"
 	"testing"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/stretchr/testify/require"
-	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestAgentESAssociation_AssociationConfAnnotationName(t *testing.T) {
 	for _, tt := range []struct {
 		name string
-		ref  types.NamespacedName
+		ref  commonv1.ObjectSelector
 		want string
 	}{
 		{
 			name: "average length names",
-			ref:  types.NamespacedName{Namespace: "namespace1", Name: "elasticsearch1"},
+			ref:  commonv1.ObjectSelector{Namespace: "namespace1", Name: "elasticsearch1"},
 			want: "association.k8s.elastic.co/es-conf-XUQW524DHLS7APSORQVR76XZV6TVXVG623SITM7UUARH6",
 		},
 		{
 			name: "max length namespace and name (63 and 36 respectively)",
-			ref: types.NamespacedName{
+			ref: commonv1.ObjectSelector{
 				Namespace: "longnamespacelongnamespacelongnamespacelongnamespacelongnamespa",
 				Name:      "elasticsearch1elasticsearch1elastics"},
 			want: "association.k8s.elastic.co/es-conf-UT4MHW5EYE7CSF2BR4NBSAM4JHY3NEFSDPOCBXQPIZ6BK",
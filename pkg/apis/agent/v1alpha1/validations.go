@@ -6,6 +6,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
@@ -22,6 +23,12 @@ var (
 		checkESRefsNamed,
 		checkSingleConfigSource,
 		checkSpec,
+		checkEmptyConfigForFleetMode,
+		checkFleetServerOnlyInFleetMode,
+		checkHTTPConfigOnlyForFleetServer,
+		checkReferenceSetForMode,
+		checkAssociations,
+		checkPrivateKey,
 	}
 
 	updateChecks = []func(old, curr *Agent) field.ErrorList{
@@ -38,6 +45,9 @@ func checkNameLength(ent *Agent) field.ErrorList {
 }
 
 func checkSupportedVersion(b *Agent) field.ErrorList {
+	if b.FleetModeEnabled() {
+		return commonv1.CheckSupportedStackVersion(b.Spec.Version, version.SupportedFleetModeAgentVersions)
+	}
 	return commonv1.CheckSupportedStackVersion(b.Spec.Version, version.SupportedAgentVersions)
 }
 
@@ -112,3 +122,134 @@ func checkSpec(b *Agent) field.ErrorList {
 	}
 	return nil
 }
+
+func checkEmptyConfigForFleetMode(b *Agent) field.ErrorList {
+	var errs field.ErrorList
+	if b.FleetModeEnabled() {
+		if b.Spec.Config != nil {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("config"),
+				b.Spec.Config,
+				"remove config, it can't be set in the fleet mode",
+			))
+		}
+
+		if b.Spec.ConfigRef != nil {
+			errs = append(errs, field.Invalid(
+				field.NewPath("spec").Child("configRef"),
+				b.Spec.ConfigRef,
+				"remove configRef, it can't be set in the fleet mode",
+			))
+		}
+	}
+
+	return errs
+}
+
+func checkFleetServerOnlyInFleetMode(b *Agent) field.ErrorList {
+	if b.StandaloneModeEnabled() && b.Spec.FleetServerEnabled {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec").Child("fleetServerEnabled"),
+				b.Spec.FleetServerEnabled,
+				"remove fleetServerEnabled or set it to false, it can't be enabled in the standalone mode",
+			),
+		}
+	}
+
+	return nil
+}
+
+func checkHTTPConfigOnlyForFleetServer(b *Agent) field.ErrorList {
+	if !b.Spec.FleetServerEnabled && !reflect.DeepEqual(b.Spec.HTTP, commonv1.HTTPConfig{}) {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec").Child("http"),
+				b.Spec.HTTP,
+				"don't specify http configuration, it can't be set when fleetServerEnabled is false",
+			),
+		}
+	}
+
+	return nil
+}
+
+func checkReferenceSetForMode(b *Agent) field.ErrorList {
+	var errorList field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if b.StandaloneModeEnabled() {
+		if b.Spec.KibanaRef.IsDefined() {
+			errorList = append(errorList, field.Invalid(
+				specPath.Child("kibanaRef"),
+				b.Spec.KibanaRef,
+				"remove kibanaRef, it can't be set in the standalone mode",
+			))
+		}
+		if b.Spec.FleetServerRef.IsDefined() {
+			errorList = append(errorList, field.Invalid(
+				specPath.Child("fleetServerRef"),
+				b.Spec.FleetServerRef,
+				"remove fleetServerRef, it can't be set in the standalone mode",
+			))
+		}
+		return errorList
+	}
+
+	if !b.Spec.KibanaRef.IsDefined() {
+		errorList = append(errorList, field.Required(
+			specPath.Child("kibanaRef"),
+			"kibanaRef is required in the fleet mode to set up Fleet and enroll the Agent",
+		))
+	}
+
+	if b.Spec.FleetServerEnabled {
+		if b.Spec.FleetServerRef.IsDefined() {
+			errorList = append(errorList, field.Invalid(
+				specPath.Child("fleetServerRef"),
+				b.Spec.FleetServerRef,
+				"remove fleetServerRef, an Agent running Fleet Server enrolls into itself",
+			))
+		}
+		if len(b.Spec.ElasticsearchRefs) != 1 {
+			errorList = append(errorList, field.Invalid(
+				specPath.Child("elasticsearchRefs"),
+				b.Spec.ElasticsearchRefs,
+				"exactly one elasticsearchRef is required when fleetServerEnabled is true",
+			))
+		}
+		return errorList
+	}
+
+	if !b.Spec.FleetServerRef.IsDefined() {
+		errorList = append(errorList, field.Required(
+			specPath.Child("fleetServerRef"),
+			"fleetServerRef is required in the fleet mode when fleetServerEnabled is false",
+		))
+	}
+	if len(b.Spec.ElasticsearchRefs) > 0 {
+		errorList = append(errorList, field.Invalid(
+			specPath.Child("elasticsearchRefs"),
+			b.Spec.ElasticsearchRefs,
+			"remove elasticsearchRefs, outputs are configured through Fleet when fleetServerEnabled is false",
+		))
+	}
+
+	return errorList
+}
+
+func checkAssociations(b *Agent) field.ErrorList {
+	var errs field.ErrorList
+	for i, ref := range b.Spec.ElasticsearchRefs {
+		errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("elasticsearchRefs").Index(i), ref.ObjectSelector)...)
+	}
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("kibanaRef"), b.Spec.KibanaRef)...)
+	errs = append(errs, commonv1.CheckNoSecretName(field.NewPath("spec").Child("kibanaRef"), b.Spec.KibanaRef)...)
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("fleetServerRef"), b.Spec.FleetServerRef)...)
+	errs = append(errs, commonv1.CheckNoSecretName(field.NewPath("spec").Child("fleetServerRef"), b.Spec.FleetServerRef)...)
+	return errs
+}
+
+func checkPrivateKey(b *Agent) field.ErrorList {
+	return commonv1.CheckPrivateKey(b.Spec.HTTP)
+}
@@ -223,3 +223,203 @@ func Test_checkESRefsNamed(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkEmptyConfigForFleetMode(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		agent   Agent
+		wantErr bool
+	}{
+		{
+			name:    "standalone mode with config: OK",
+			agent:   Agent{Spec: AgentSpec{Config: &commonv1.Config{}}},
+			wantErr: false,
+		},
+		{
+			name:    "fleet mode without config: OK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode}},
+			wantErr: false,
+		},
+		{
+			name:    "fleet mode with config: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, Config: &commonv1.Config{}}},
+			wantErr: true,
+		},
+		{
+			name:    "fleet mode with configRef: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, ConfigRef: &commonv1.ConfigSource{}}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkEmptyConfigForFleetMode(&tt.agent)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func Test_checkFleetServerOnlyInFleetMode(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		agent   Agent
+		wantErr bool
+	}{
+		{
+			name:    "fleet mode with Fleet Server: OK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, FleetServerEnabled: true}},
+			wantErr: false,
+		},
+		{
+			name:    "default mode with Fleet Server: NOK",
+			agent:   Agent{Spec: AgentSpec{FleetServerEnabled: true}},
+			wantErr: true,
+		},
+		{
+			name:    "standalone mode with Fleet Server: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentStandaloneMode, FleetServerEnabled: true}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkFleetServerOnlyInFleetMode(&tt.agent)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func Test_checkHTTPConfigOnlyForFleetServer(t *testing.T) {
+	httpConfig := commonv1.HTTPConfig{TLS: commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}}}
+	for _, tt := range []struct {
+		name    string
+		agent   Agent
+		wantErr bool
+	}{
+		{
+			name:    "no http config: OK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode}},
+			wantErr: false,
+		},
+		{
+			name:    "http config with Fleet Server: OK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, FleetServerEnabled: true, HTTP: httpConfig}},
+			wantErr: false,
+		},
+		{
+			name:    "http config without Fleet Server: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, HTTP: httpConfig}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkHTTPConfigOnlyForFleetServer(&tt.agent)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func Test_checkReferenceSetForMode(t *testing.T) {
+	esRefs := []Output{{ObjectSelector: commonv1.ObjectSelector{Name: "es"}}}
+	kbRef := commonv1.ObjectSelector{Name: "kb"}
+	fsRef := commonv1.ObjectSelector{Name: "fs"}
+	for _, tt := range []struct {
+		name    string
+		agent   Agent
+		wantErr bool
+	}{
+		{
+			name:    "standalone mode with elasticsearchRefs: OK",
+			agent:   Agent{Spec: AgentSpec{ElasticsearchRefs: esRefs}},
+			wantErr: false,
+		},
+		{
+			name:    "standalone mode with kibanaRef: NOK",
+			agent:   Agent{Spec: AgentSpec{KibanaRef: kbRef}},
+			wantErr: true,
+		},
+		{
+			name:    "standalone mode with fleetServerRef: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentStandaloneMode, FleetServerRef: fsRef}},
+			wantErr: true,
+		},
+		{
+			name: "Fleet Server with kibanaRef and elasticsearchRefs: OK",
+			agent: Agent{Spec: AgentSpec{
+				Mode: AgentFleetMode, FleetServerEnabled: true, KibanaRef: kbRef, ElasticsearchRefs: esRefs,
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "Fleet Server without elasticsearchRefs: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, FleetServerEnabled: true, KibanaRef: kbRef}},
+			wantErr: true,
+		},
+		{
+			name: "Fleet Server with fleetServerRef: NOK",
+			agent: Agent{Spec: AgentSpec{
+				Mode: AgentFleetMode, FleetServerEnabled: true, KibanaRef: kbRef, ElasticsearchRefs: esRefs, FleetServerRef: fsRef,
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "fleet mode with kibanaRef and fleetServerRef: OK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, KibanaRef: kbRef, FleetServerRef: fsRef}},
+			wantErr: false,
+		},
+		{
+			name:    "fleet mode without kibanaRef: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, FleetServerRef: fsRef}},
+			wantErr: true,
+		},
+		{
+			name:    "fleet mode without fleetServerRef: NOK",
+			agent:   Agent{Spec: AgentSpec{Mode: AgentFleetMode, KibanaRef: kbRef}},
+			wantErr: true,
+		},
+		{
+			name: "fleet mode with elasticsearchRefs: NOK",
+			agent: Agent{Spec: AgentSpec{
+				Mode: AgentFleetMode, KibanaRef: kbRef, FleetServerRef: fsRef, ElasticsearchRefs: esRefs,
+			}},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkReferenceSetForMode(&tt.agent)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func Test_checkPrivateKey(t *testing.T) {
+	withPrivateKey := func(privateKey *commonv1.PrivateKeyOptions) Agent {
+		return Agent{Spec: AgentSpec{Mode: AgentFleetMode, FleetServerEnabled: true, HTTP: commonv1.HTTPConfig{
+			TLS: commonv1.TLSOptions{PrivateKey: privateKey},
+		}}}
+	}
+	for _, tt := range []struct {
+		name    string
+		agent   Agent
+		wantErr bool
+	}{
+		{
+			name:    "no private key options: OK",
+			agent:   withPrivateKey(nil),
+			wantErr: false,
+		},
+		{
+			name:    "supported private key options: OK",
+			agent:   withPrivateKey(&commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 384}),
+			wantErr: false,
+		},
+		{
+			name:    "unsupported private key size: NOK",
+			agent:   withPrivateKey(&commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 4096}),
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkPrivateKey(&tt.agent)
+			assert.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
@@ -10,6 +10,7 @@ package v1alpha1
 
 import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -28,6 +29,16 @@ func (in *Agent) DeepCopyInto(out *Agent) {
 			(*out)[key] = val
 		}
 	}
+	if in.kbAssocConf != nil {
+		in, out := &in.kbAssocConf, &out.kbAssocConf
+		*out = new(v1.AssociationConf)
+		**out = **in
+	}
+	if in.fsAssocConf != nil {
+		in, out := &in.fsAssocConf, &out.fsAssocConf
+		*out = new(v1.AssociationConf)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Agent.
@@ -69,6 +80,46 @@ func (in *AgentESAssociation) DeepCopy() *AgentESAssociation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentFleetServerAssociation) DeepCopyInto(out *AgentFleetServerAssociation) {
+	*out = *in
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(Agent)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentFleetServerAssociation.
+func (in *AgentFleetServerAssociation) DeepCopy() *AgentFleetServerAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentFleetServerAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentKibanaAssociation) DeepCopyInto(out *AgentKibanaAssociation) {
+	*out = *in
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(Agent)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentKibanaAssociation.
+func (in *AgentKibanaAssociation) DeepCopy() *AgentKibanaAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentKibanaAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentList) DeepCopyInto(out *AgentList) {
 	*out = *in
@@ -135,6 +186,9 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		*out = new(DeploymentSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	in.HTTP.DeepCopyInto(&out.HTTP)
+	out.KibanaRef = in.KibanaRef
+	out.FleetServerRef = in.FleetServerRef
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSpec.
@@ -157,6 +211,13 @@ func (in *AgentStatus) DeepCopyInto(out *AgentStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentStatus.
@@ -10,6 +10,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 )
@@ -56,6 +57,13 @@ type ApmServerSpec struct {
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Monitoring enables you to collect and ship monitoring data of this APM Server.
+	// See https://www.elastic.co/guide/en/apm/server/current/monitoring-metricbeat-collection.html.
+	// Metricbeat is deployed in the same Pod as a sidecar and sends data to an Elasticsearch monitoring cluster
+	// running in the same Kubernetes cluster.
+	// +kubebuilder:validation:Optional
+	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
 }
 
 // ApmServerStatus defines the observed state of ApmServer
@@ -69,6 +77,8 @@ type ApmServerStatus struct {
 	ElasticsearchAssociationStatus commonv1.AssociationStatus `json:"elasticsearchAssociationStatus,omitempty"`
 	// KibanaAssociationStatus is the status of any auto-linking to Kibana.
 	KibanaAssociationStatus commonv1.AssociationStatus `json:"kibanaAssociationStatus,omitempty"`
+	// MonitoringAssociationsStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
+	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -85,10 +95,11 @@ type ApmServer struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec            ApmServerSpec             `json:"spec,omitempty"`
-	Status          ApmServerStatus           `json:"status,omitempty"`
-	esAssocConf     *commonv1.AssociationConf `json:"-"` //nolint:govet
-	kibanaAssocConf *commonv1.AssociationConf `json:"-"` //nolint:govet
+	Spec                 ApmServerSpec                                     `json:"spec,omitempty"`
+	Status               ApmServerStatus                                   `json:"status,omitempty"`
+	esAssocConf          *commonv1.AssociationConf                         `json:"-"` //nolint:govet
+	kibanaAssocConf      *commonv1.AssociationConf                         `json:"-"` //nolint:govet
+	monitoringAssocConfs map[types.NamespacedName]commonv1.AssociationConf `json:"-"` //nolint:govet
 }
 
 // +kubebuilder:object:root=true
@@ -135,6 +146,15 @@ func (as *ApmServer) GetAssociations() []commonv1.Association {
 			ApmServer: as,
 		})
 	}
+	seen := make(map[types.NamespacedName]bool)
+	for _, ref := range append(as.GetMonitoringMetricsRefs(), as.GetMonitoringLogsRefs()...) {
+		nsRef := ref.WithDefaultNamespace(as.Namespace).NamespacedName()
+		if seen[nsRef] {
+			continue
+		}
+		seen[nsRef] = true
+		associations = append(associations, as.MonitoringAssociation(ref))
+	}
 
 	return associations
 }
@@ -149,29 +169,55 @@ func (as *ApmServer) AssociationStatusMap(typ commonv1.AssociationType) commonv1
 		if as.Spec.KibanaRef.IsDefined() {
 			return commonv1.NewSingleAssociationStatusMap(as.Status.KibanaAssociationStatus)
 		}
+	case commonv1.EsMonitoringAssociationType:
+		return as.Status.MonitoringAssociationsStatus
 	}
 
 	return commonv1.AssociationStatusMap{}
 }
 
 func (as *ApmServer) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
-	single, err := status.Single()
-	if err != nil {
-		return err
-	}
-
 	switch typ {
 	case commonv1.ElasticsearchAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
 		as.Status.ElasticsearchAssociationStatus = single
 		return nil
 	case commonv1.KibanaAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
 		as.Status.KibanaAssociationStatus = single
 		return nil
+	case commonv1.EsMonitoringAssociationType:
+		as.Status.MonitoringAssociationsStatus = status
+		return nil
 	default:
 		return fmt.Errorf("association type %s not known", typ)
 	}
 }
 
+// GetMonitoringMetricsRefs returns the references to the Elasticsearch clusters receiving the metrics of this APM Server.
+func (as *ApmServer) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
+	return as.Spec.Monitoring.Metrics.ElasticsearchRefs
+}
+
+// GetMonitoringLogsRefs returns the references to the Elasticsearch clusters receiving the logs of this APM Server.
+func (as *ApmServer) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
+	return as.Spec.Monitoring.Logs.ElasticsearchRefs
+}
+
+// MonitoringAssociation returns the association to the monitoring Elasticsearch cluster identified by the given reference.
+func (as *ApmServer) MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association {
+	return &ApmMonitoringAssociation{
+		ApmServer: as,
+		ref:       ref.WithDefaultNamespace(as.Namespace),
+	}
+}
+
 // ApmEsAssociation helps to manage the APMServer / Elasticsearch association
 type ApmEsAssociation struct {
 	*ApmServer
@@ -267,3 +313,60 @@ func (akb *ApmKibanaAssociation) AssociationID() string {
 }
 
 var _ commonv1.Associated = &ApmServer{}
+
+// ApmMonitoringAssociation helps to manage the APMServer+Metricbeat <-> Elasticsearch(es) association.
+type ApmMonitoringAssociation struct {
+	// The monitored APM Server from where are collected monitoring metrics
+	*ApmServer
+	// ref is the reference to the Elasticsearch used in Association, with a default namespace
+	ref commonv1.ObjectSelector
+}
+
+var _ commonv1.Association = &ApmMonitoringAssociation{}
+
+func (amon *ApmMonitoringAssociation) AssociationID() string {
+	return fmt.Sprintf("%s-%s", amon.ref.Namespace, amon.ref.NameOrSecretName())
+}
+
+func (amon *ApmMonitoringAssociation) Associated() commonv1.Associated {
+	if amon == nil {
+		return nil
+	}
+	if amon.ApmServer == nil {
+		amon.ApmServer = &ApmServer{}
+	}
+	return amon.ApmServer
+}
+
+func (amon *ApmMonitoringAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EsMonitoringAssociationType
+}
+
+func (amon *ApmMonitoringAssociation) AssociationRef() commonv1.ObjectSelector {
+	return amon.ref
+}
+
+func (amon *ApmMonitoringAssociation) AssociationConfAnnotationName() string {
+	return commonv1.EsMonitoringConfAnnotationName(amon.ref.NamespacedName())
+}
+
+func (amon *ApmMonitoringAssociation) AssociationConf() *commonv1.AssociationConf {
+	if amon.monitoringAssocConfs == nil {
+		return nil
+	}
+	assocConf, found := amon.monitoringAssocConfs[amon.ref.NamespacedName()]
+	if !found {
+		return nil
+	}
+
+	return &assocConf
+}
+
+func (amon *ApmMonitoringAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	if amon.monitoringAssocConfs == nil {
+		amon.monitoringAssocConfs = make(map[types.NamespacedName]commonv1.AssociationConf)
+	}
+	if conf != nil {
+		amon.monitoringAssocConfs[amon.ref.NamespacedName()] = *conf
+	}
+}
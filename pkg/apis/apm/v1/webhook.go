@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,7 +31,10 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkMonitoring,
 		checkAgentConfigurationMinVersion,
+		checkAssociations,
+		checkPrivateKey,
 	}
 
 	updateChecks = []func(old, curr *ApmServer) field.ErrorList{
@@ -110,6 +114,12 @@ func checkNoDowngrade(prev, curr *ApmServer) field.ErrorList {
 	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
 }
 
+func checkMonitoring(as *ApmServer) field.ErrorList {
+	errs := validations.Validate(as, as.Spec.Version)
+	// only the metrics can be collected, APM Servers do not write their logs to disk
+	return append(errs, validations.ValidateMetricsOnly(as, "APM Server")...)
+}
+
 func checkAgentConfigurationMinVersion(as *ApmServer) field.ErrorList {
 	if !as.Spec.KibanaRef.IsDefined() {
 		return nil
@@ -132,3 +142,15 @@ func checkAgentConfigurationMinVersion(as *ApmServer) field.ErrorList {
 	}
 	return nil
 }
+
+func checkAssociations(as *ApmServer) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("elasticsearchRef"), as.Spec.ElasticsearchRef)...)
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("kibanaRef"), as.Spec.KibanaRef)...)
+	errs = append(errs, commonv1.CheckNoSecretName(field.NewPath("spec").Child("kibanaRef"), as.Spec.KibanaRef)...)
+	return errs
+}
+
+func checkPrivateKey(as *ApmServer) field.ErrorList {
+	return commonv1.CheckPrivateKey(as.Spec.HTTP)
+}
@@ -115,6 +115,55 @@ func TestWebhook(t *testing.T) {
 			},
 			Check: test.ValidationWebhookSucceeded,
 		},
+		{
+			Name:      "invalid-service-name-for-kibana-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				apm := mkApmServer(uid)
+				apm.Spec.KibanaRef = commonv1.ObjectSelector{Name: "kbname", Namespace: "kbns", ServiceName: "kb.http"}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef.serviceName: Invalid value: "kb.http": a DNS-1035 label must consist of lower case alphanumeric characters`,
+			),
+		},
+		{
+			Name:      "monitoring-metrics",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				apm := mkApmServer(uid)
+				apm.Spec.Version = "7.14.0"
+				apm.Spec.Monitoring.Metrics.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring"}}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "monitoring-logs-not-supported",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				apm := mkApmServer(uid)
+				apm.Spec.Version = "7.14.0"
+				apm.Spec.Monitoring.Logs.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring"}}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.monitoring.logs.elasticsearchRefs: Invalid value: .*: Stack Monitoring of logs is not supported for APM Server`,
+			),
+		},
+		{
+			Name:      "monitoring-unsupported-version",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				apm := mkApmServer(uid)
+				apm.Spec.Version = "7.13.0"
+				apm.Spec.Monitoring.Metrics.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring"}}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Invalid value: "7.13.0": Unsupported version for Stack Monitoring. Required >= 7.14.0`,
+			),
+		},
 		{
 			Name:      "update-valid",
 			Operation: admissionv1beta1.Update,
@@ -147,6 +196,18 @@ func TestWebhook(t *testing.T) {
 				`spec.version: Forbidden: Version downgrades are not supported`,
 			),
 		},
+		{
+			Name:      "invalid-private-key",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				apm := mkApmServer(uid)
+				apm.Spec.HTTP.TLS.PrivateKey = &commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 1024}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.http.tls.privateKey: Invalid value: .*: unsupported RSA private key size 1024`,
+			),
+		},
 	}
 
 	validator := &apmv1.ApmServer{}
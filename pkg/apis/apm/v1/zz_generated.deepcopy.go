@@ -11,6 +11,7 @@ package v1
 import (
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -53,13 +54,34 @@ func (in *ApmKibanaAssociation) DeepCopy() *ApmKibanaAssociation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApmMonitoringAssociation) DeepCopyInto(out *ApmMonitoringAssociation) {
+	*out = *in
+	if in.ApmServer != nil {
+		in, out := &in.ApmServer, &out.ApmServer
+		*out = new(ApmServer)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ref = in.ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApmMonitoringAssociation.
+func (in *ApmMonitoringAssociation) DeepCopy() *ApmMonitoringAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(ApmMonitoringAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApmServer) DeepCopyInto(out *ApmServer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.esAssocConf != nil {
 		in, out := &in.esAssocConf, &out.esAssocConf
 		*out = new(commonv1.AssociationConf)
@@ -70,6 +92,13 @@ func (in *ApmServer) DeepCopyInto(out *ApmServer) {
 		*out = new(commonv1.AssociationConf)
 		**out = **in
 	}
+	if in.monitoringAssocConfs != nil {
+		in, out := &in.monitoringAssocConfs, &out.monitoringAssocConfs
+		*out = make(map[types.NamespacedName]commonv1.AssociationConf, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApmServer.
@@ -140,6 +169,7 @@ func (in *ApmServerSpec) DeepCopyInto(out *ApmServerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApmServerSpec.
@@ -155,7 +185,14 @@ func (in *ApmServerSpec) DeepCopy() *ApmServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApmServerStatus) DeepCopyInto(out *ApmServerStatus) {
 	*out = *in
-	out.DeploymentStatus = in.DeploymentStatus
+	in.DeploymentStatus.DeepCopyInto(&out.DeploymentStatus)
+	if in.MonitoringAssociationsStatus != nil {
+		in, out := &in.MonitoringAssociationsStatus, &out.MonitoringAssociationsStatus
+		*out = make(commonv1.AssociationStatusMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApmServerStatus.
@@ -10,6 +10,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 )
@@ -78,6 +79,13 @@ type BeatSpec struct {
 	// Cannot be used along with `daemonSet`. If both are absent a default for the Type is used.
 	// +kubebuilder:validation:Optional
 	Deployment *DeploymentSpec `json:"deployment,omitempty"`
+
+	// Monitoring enables you to collect and ship monitoring data of this Beat.
+	// See https://www.elastic.co/guide/en/beats/metricbeat/current/monitoring-metricbeat-collection.html.
+	// Metricbeat is deployed in the same Pod as a sidecar and sends data to an Elasticsearch monitoring cluster
+	// running in the same Kubernetes cluster.
+	// +kubebuilder:validation:Optional
+	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
 }
 
 type DaemonSetSpec struct {
@@ -112,6 +120,19 @@ type BeatStatus struct {
 
 	// +kubebuilder:validation:Optional
 	KibanaAssociationStatus commonv1.AssociationStatus `json:"kibanaAssociationStatus,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
+
+	// Conditions holds the latest available observations of the state of the Beat.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the metadata generation of the Beat last processed by the operator.
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 type BeatHealth string
@@ -147,10 +168,11 @@ type Beat struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec        BeatSpec                  `json:"spec,omitempty"`
-	Status      BeatStatus                `json:"status,omitempty"`
-	esAssocConf *commonv1.AssociationConf `json:"-"` // nolint:govet
-	kbAssocConf *commonv1.AssociationConf `json:"-"` // nolint:govet
+	Spec                 BeatSpec                                          `json:"spec,omitempty"`
+	Status               BeatStatus                                        `json:"status,omitempty"`
+	esAssocConf          *commonv1.AssociationConf                         `json:"-"` // nolint:govet
+	kbAssocConf          *commonv1.AssociationConf                         `json:"-"` // nolint:govet
+	monitoringAssocConfs map[types.NamespacedName]commonv1.AssociationConf `json:"-"` // nolint:govet
 }
 
 func (b *Beat) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
@@ -163,24 +185,32 @@ func (b *Beat) AssociationStatusMap(typ commonv1.AssociationType) commonv1.Assoc
 		if b.Spec.KibanaRef.IsDefined() {
 			return commonv1.NewSingleAssociationStatusMap(b.Status.KibanaAssociationStatus)
 		}
+	case commonv1.EsMonitoringAssociationType:
+		return b.Status.MonitoringAssociationsStatus
 	}
 
 	return commonv1.AssociationStatusMap{}
 }
 
 func (b *Beat) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
-	single, err := status.Single()
-	if err != nil {
-		return err
-	}
-
 	switch typ {
 	case commonv1.ElasticsearchAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
 		b.Status.ElasticsearchAssociationStatus = single
 		return nil
 	case commonv1.KibanaAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
 		b.Status.KibanaAssociationStatus = single
 		return nil
+	case commonv1.EsMonitoringAssociationType:
+		b.Status.MonitoringAssociationsStatus = status
+		return nil
 	default:
 		return fmt.Errorf("association type %s not known", typ)
 	}
@@ -201,6 +231,15 @@ func (b *Beat) GetAssociations() []commonv1.Association {
 			Beat: b,
 		})
 	}
+	seen := make(map[types.NamespacedName]bool)
+	for _, ref := range append(b.GetMonitoringMetricsRefs(), b.GetMonitoringLogsRefs()...) {
+		nsRef := ref.WithDefaultNamespace(b.Namespace).NamespacedName()
+		if seen[nsRef] {
+			continue
+		}
+		seen[nsRef] = true
+		associations = append(associations, b.MonitoringAssociation(ref))
+	}
 
 	return associations
 }
@@ -218,6 +257,24 @@ func (b *Beat) ElasticsearchRef() commonv1.ObjectSelector {
 	return b.Spec.ElasticsearchRef
 }
 
+// GetMonitoringMetricsRefs returns the references to the Elasticsearch clusters receiving the metrics of this Beat.
+func (b *Beat) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
+	return b.Spec.Monitoring.Metrics.ElasticsearchRefs
+}
+
+// GetMonitoringLogsRefs returns the references to the Elasticsearch clusters receiving the logs of this Beat.
+func (b *Beat) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
+	return b.Spec.Monitoring.Logs.ElasticsearchRefs
+}
+
+// MonitoringAssociation returns the association to the monitoring Elasticsearch cluster identified by the given reference.
+func (b *Beat) MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association {
+	return &BeatMonitoringAssociation{
+		Beat: b,
+		ref:  ref.WithDefaultNamespace(b.Namespace),
+	}
+}
+
 type BeatESAssociation struct {
 	*Beat
 }
@@ -298,6 +355,63 @@ func (b *BeatKibanaAssociation) AssociationID() string {
 	return commonv1.SingletonAssociationID
 }
 
+// BeatMonitoringAssociation helps to manage the Beat+Metricbeat <-> Elasticsearch(es) association.
+type BeatMonitoringAssociation struct {
+	// The monitored Beat from where are collected monitoring metrics
+	*Beat
+	// ref is the reference to the Elasticsearch used in Association, with a default namespace
+	ref commonv1.ObjectSelector
+}
+
+var _ commonv1.Association = &BeatMonitoringAssociation{}
+
+func (b *BeatMonitoringAssociation) AssociationID() string {
+	return fmt.Sprintf("%s-%s", b.ref.Namespace, b.ref.NameOrSecretName())
+}
+
+func (b *BeatMonitoringAssociation) Associated() commonv1.Associated {
+	if b == nil {
+		return nil
+	}
+	if b.Beat == nil {
+		b.Beat = &Beat{}
+	}
+	return b.Beat
+}
+
+func (b *BeatMonitoringAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EsMonitoringAssociationType
+}
+
+func (b *BeatMonitoringAssociation) AssociationRef() commonv1.ObjectSelector {
+	return b.ref
+}
+
+func (b *BeatMonitoringAssociation) AssociationConfAnnotationName() string {
+	return commonv1.EsMonitoringConfAnnotationName(b.ref.NamespacedName())
+}
+
+func (b *BeatMonitoringAssociation) AssociationConf() *commonv1.AssociationConf {
+	if b.monitoringAssocConfs == nil {
+		return nil
+	}
+	assocConf, found := b.monitoringAssocConfs[b.ref.NamespacedName()]
+	if !found {
+		return nil
+	}
+
+	return &assocConf
+}
+
+func (b *BeatMonitoringAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	if b.monitoringAssocConfs == nil {
+		b.monitoringAssocConfs = make(map[types.NamespacedName]commonv1.AssociationConf)
+	}
+	if conf != nil {
+		b.monitoringAssocConfs[b.ref.NamespacedName()] = *conf
+	}
+}
+
 func (b *Beat) SecureSettings() []commonv1.SecretSource {
 	return b.Spec.SecureSettings
 }
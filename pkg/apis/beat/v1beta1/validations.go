@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 )
 
@@ -23,6 +24,8 @@ var (
 		checkBeatType,
 		checkSingleConfigSource,
 		checkSpec,
+		checkMonitoring,
+		checkAssociations,
 	}
 
 	updateChecks = []func(old, curr *Beat) field.ErrorList{
@@ -84,6 +87,12 @@ func checkNoDowngrade(prev, curr *Beat) field.ErrorList {
 	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
 }
 
+func checkMonitoring(b *Beat) field.ErrorList {
+	errs := validations.Validate(b, b.Spec.Version)
+	// Beats logs are not collected, only the metrics exposed by the local HTTP endpoint
+	return append(errs, validations.ValidateMetricsOnly(b, "Beat")...)
+}
+
 func checkSingleConfigSource(b *Beat) field.ErrorList {
 	if b.Spec.Config != nil && b.Spec.ConfigRef != nil {
 		msg := "Specify at most one of [`config`, `configRef`], not both"
@@ -104,3 +113,11 @@ func checkSpec(b *Beat) field.ErrorList {
 	}
 	return nil
 }
+
+func checkAssociations(b *Beat) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("elasticsearchRef"), b.Spec.ElasticsearchRef)...)
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("kibanaRef"), b.Spec.KibanaRef)...)
+	errs = append(errs, commonv1.CheckNoSecretName(field.NewPath("spec").Child("kibanaRef"), b.Spec.KibanaRef)...)
+	return errs
+}
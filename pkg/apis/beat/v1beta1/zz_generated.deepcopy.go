@@ -10,7 +10,9 @@ package v1beta1
 
 import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -19,7 +21,7 @@ func (in *Beat) DeepCopyInto(out *Beat) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.esAssocConf != nil {
 		in, out := &in.esAssocConf, &out.esAssocConf
 		*out = new(v1.AssociationConf)
@@ -30,6 +32,13 @@ func (in *Beat) DeepCopyInto(out *Beat) {
 		*out = new(v1.AssociationConf)
 		**out = **in
 	}
+	if in.monitoringAssocConfs != nil {
+		in, out := &in.monitoringAssocConfs, &out.monitoringAssocConfs
+		*out = make(map[types.NamespacedName]v1.AssociationConf, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Beat.
@@ -122,6 +131,27 @@ func (in *BeatList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BeatMonitoringAssociation) DeepCopyInto(out *BeatMonitoringAssociation) {
+	*out = *in
+	if in.Beat != nil {
+		in, out := &in.Beat, &out.Beat
+		*out = new(Beat)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ref = in.ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeatMonitoringAssociation.
+func (in *BeatMonitoringAssociation) DeepCopy() *BeatMonitoringAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(BeatMonitoringAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BeatSpec) DeepCopyInto(out *BeatSpec) {
 	*out = *in
@@ -153,6 +183,7 @@ func (in *BeatSpec) DeepCopyInto(out *BeatSpec) {
 		*out = new(DeploymentSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeatSpec.
@@ -168,6 +199,20 @@ func (in *BeatSpec) DeepCopy() *BeatSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BeatStatus) DeepCopyInto(out *BeatStatus) {
 	*out = *in
+	if in.MonitoringAssociationsStatus != nil {
+		in, out := &in.MonitoringAssociationsStatus, &out.MonitoringAssociationsStatus
+		*out = make(v1.AssociationStatusMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BeatStatus.
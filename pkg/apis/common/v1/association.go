@@ -5,12 +5,15 @@
 package v1
 
 import (
+	"crypto/sha256"
+	"encoding/base32"
 	"fmt"
 	"sort"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -96,6 +99,22 @@ const (
 	KibanaConfigAnnotationNameBase = "association.k8s.elastic.co/kb-conf"
 	KibanaAssociationType          = "kibana"
 
+	EsMonitoringConfigAnnotationNameBase = "association.k8s.elastic.co/esmon-conf"
+	EsMonitoringAssociationType          = "es-monitoring"
+
+	EMSConfigAnnotationNameBase = "association.k8s.elastic.co/ems-conf"
+	EMSAssociationType          = "ems"
+
+	EntConfigAnnotationNameBase = "association.k8s.elastic.co/ent-conf"
+	EntAssociationType          = "ent"
+
+	FleetServerConfigAnnotationNameBase = "association.k8s.elastic.co/fs-conf"
+	FleetServerAssociationType          = "fleet-server"
+
+	// NoAuthRequiredValue is the value of AuthSecretName in the configuration of an association which does not require
+	// any credentials to connect to the referenced resource.
+	NoAuthRequiredValue = "-"
+
 	AssociationUnknown     AssociationStatus = ""
 	AssociationPending     AssociationStatus = "Pending"
 	AssociationEstablished AssociationStatus = "Established"
@@ -115,6 +134,9 @@ const (
 // - EnterpriseSearch can be associated with Elasticsearch
 // - Beat can be associated with Elasticsearch and Kibana
 // - Agent can be associated with multiple Elasticsearches
+// - Elasticsearch can be associated with multiple Elasticsearches for stack monitoring
+// - Elastic Maps Server can be associated with Elasticsearch
+// - Kibana can be associated with Elastic Maps Server
 // +kubebuilder:object:generate=false
 type Associated interface {
 	metav1.Object
@@ -172,13 +194,35 @@ func FormatNameWithID(template string, id string) string {
 	return fmt.Sprintf(template, id)
 }
 
+// EsMonitoringConfAnnotationName returns the name of the annotation holding the configuration of the association
+// between a monitored resource and the given monitoring Elasticsearch cluster.
+// The annotation key must be stable to allow controllers to only pick up the ones they expect, based on the
+// monitoring ElasticsearchRefs.
+func EsMonitoringConfAnnotationName(esRef types.NamespacedName) string {
+	nsNameHash := sha256.New224()
+	// concat with dot to avoid collisions, as namespace can't contain dots
+	_, _ = nsNameHash.Write([]byte(fmt.Sprintf("%s.%s", esRef.Namespace, esRef.Name)))
+	// base32 to encode and limit the length, as using Sprintf with "%x" encodes with base16 which happens to
+	// give too long output
+	// no padding to avoid illegal '=' character in the annotation name
+	hash := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(nsNameHash.Sum(nil))
+
+	return FormatNameWithID(EsMonitoringConfigAnnotationNameBase+"%s", hash)
+}
+
 // AssociationConf holds the association configuration of a referenced resource in an association.
 type AssociationConf struct {
 	AuthSecretName string `json:"authSecretName"`
 	AuthSecretKey  string `json:"authSecretKey"`
-	CACertProvided bool   `json:"caCertProvided"`
-	CASecretName   string `json:"caSecretName"`
-	URL            string `json:"url"`
+	// AuthAPIKey is true if the value of AuthSecretKey in the auth secret is an Elasticsearch API key encoded as
+	// id:api_key, rather than the password of the user named after AuthSecretKey.
+	AuthAPIKey bool `json:"authApiKey,omitempty"`
+	// AuthServiceAccountToken is true if the value of AuthSecretKey in the auth secret is an Elasticsearch service
+	// account token, rather than the password of the user named after AuthSecretKey.
+	AuthServiceAccountToken bool   `json:"authServiceAccountToken,omitempty"`
+	CACertProvided          bool   `json:"caCertProvided"`
+	CASecretName            string `json:"caSecretName"`
+	URL                     string `json:"url"`
 	// Version of the referenced resource. If a version upgrade is in progress,
 	// matches the lowest running version. May be empty if unknown.
 	Version string `json:"version"`
@@ -190,7 +234,16 @@ func (ac *AssociationConf) IsConfigured() bool {
 		return false
 	}
 
-	return ac.AuthIsConfigured() && ac.URLIsConfigured()
+	return (ac.AuthIsConfigured() || ac.NoAuthRequired()) && ac.URLIsConfigured()
+}
+
+// NoAuthRequired returns true if the association does not require any credentials to connect to the referenced
+// resource.
+func (ac *AssociationConf) NoAuthRequired() bool {
+	if ac == nil {
+		return false
+	}
+	return ac.AuthSecretName == NoAuthRequiredValue
 }
 
 // AuthIsConfigured returns true if all the auth fields are set.
@@ -201,6 +254,22 @@ func (ac *AssociationConf) AuthIsConfigured() bool {
 	return ac.AuthSecretName != "" && ac.AuthSecretKey != ""
 }
 
+// AuthIsAPIKey returns true if the auth secret holds an API key rather than a password.
+func (ac *AssociationConf) AuthIsAPIKey() bool {
+	if ac == nil {
+		return false
+	}
+	return ac.AuthAPIKey
+}
+
+// AuthIsServiceAccountToken returns true if the auth secret holds a service account token rather than a password.
+func (ac *AssociationConf) AuthIsServiceAccountToken() bool {
+	if ac == nil {
+		return false
+	}
+	return ac.AuthServiceAccountToken
+}
+
 // CAIsConfigured returns true if the CA field is set.
 func (ac *AssociationConf) CAIsConfigured() bool {
 	if ac == nil {
@@ -5,6 +5,7 @@
 package v1
 
 import (
+	"fmt"
 	"reflect"
 
 	v1 "k8s.io/api/core/v1"
@@ -30,6 +31,16 @@ type DeploymentStatus struct {
 	Version string `json:"version,omitempty"`
 	// Health of the deployment.
 	Health DeploymentHealth `json:"health,omitempty"`
+	// Conditions holds the latest available observations of the state of the resource.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the most recent generation observed for this resource. It corresponds to the metadata
+	// generation, which is updated by the API server on mutation of the spec. The operator sets it each time it updates
+	// the status at the end of a reconciliation attempt: a lower value means the status does not reflect the latest spec yet.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // IsDegraded returns true if the current status is worse than the previous.
@@ -43,12 +54,22 @@ type SecretRef struct {
 	SecretName string `json:"secretName,omitempty"`
 }
 
-// ObjectSelector defines a reference to a Kubernetes object.
+// ObjectSelector defines a reference to a Kubernetes object which can be an Elastic resource managed by the operator
+// or a Secret describing an Elasticsearch cluster not managed by the operator.
 type ObjectSelector struct {
 	// Name of the Kubernetes object.
-	Name string `json:"name"`
+	Name string `json:"name,omitempty"`
 	// Namespace of the Kubernetes object. If empty, defaults to the current namespace.
 	Namespace string `json:"namespace,omitempty"`
+	// ServiceName is the name of an existing Kubernetes service which is used to make requests to the referenced
+	// object. It has to be in the same namespace as the referenced resource. If left empty, the default HTTP service of
+	// the referenced resource is used.
+	ServiceName string `json:"serviceName,omitempty"`
+	// SecretName is the name of an existing Kubernetes secret that contains connection information for associating an
+	// Elasticsearch cluster not managed by the operator. The referenced secret must be in the namespace of the
+	// associated resource and contain the following: `url`, `username` and `password` or `api-key`, and `ca.crt`
+	// (optional). This field cannot be used in combination with the other fields name, namespace or serviceName.
+	SecretName string `json:"secretName,omitempty"`
 }
 
 // WithDefaultNamespace adds a default namespace to a given ObjectSelector if none is set.
@@ -57,23 +78,40 @@ func (o ObjectSelector) WithDefaultNamespace(defaultNamespace string) ObjectSele
 		return o
 	}
 	return ObjectSelector{
-		Namespace: defaultNamespace,
-		Name:      o.Name,
+		Namespace:   defaultNamespace,
+		Name:        o.Name,
+		ServiceName: o.ServiceName,
+		SecretName:  o.SecretName,
 	}
 }
 
 // NamespacedName is a convenience method to turn an ObjectSelector into a NamespacedName.
+// The name of a reference to a resource not managed by the operator is the name of the secret describing it.
 func (o ObjectSelector) NamespacedName() types.NamespacedName {
 	return types.NamespacedName{
-		Name:      o.Name,
+		Name:      o.NameOrSecretName(),
 		Namespace: o.Namespace,
 	}
 }
 
-// IsDefined checks if the object selector is not nil and has a name.
+// NameOrSecretName returns the name of the referenced resource, or the name of the secret describing it if it is not
+// managed by the operator.
+func (o ObjectSelector) NameOrSecretName() string {
+	if o.IsExternal() {
+		return o.SecretName
+	}
+	return o.Name
+}
+
+// IsExternal returns true if the reference targets a resource not managed by the operator, described by a secret.
+func (o ObjectSelector) IsExternal() bool {
+	return o.SecretName != ""
+}
+
+// IsDefined checks if the object selector is not nil and has a name or a secret name.
 // Namespace is not mandatory as it may be inherited by the parent object.
 func (o *ObjectSelector) IsDefined() bool {
-	return o != nil && o.Name != ""
+	return o != nil && (o.Name != "" || o.SecretName != "")
 }
 
 // HTTPConfig holds the HTTP layer configuration for resources.
@@ -104,12 +142,27 @@ type TLSOptions struct {
 	// - `tls.crt`: The certificate (or a chain).
 	// - `tls.key`: The private key to the first certificate in the certificate chain.
 	Certificate SecretRef `json:"certificate,omitempty"`
+
+	// PrivateKey allows configuring the private keys generated by the operator for the self-signed certificate and its
+	// certificate authority. Defaults to the operator configuration.
+	PrivateKey *PrivateKeyOptions `json:"privateKey,omitempty"`
+
+	// IssuerRef is a reference to an external issuer, for example a cert-manager Issuer or ClusterIssuer, used to sign
+	// the HTTP certificate instead of the self-signed certificate authority of the operator.
+	// Mutually exclusive with Certificate.
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
 }
 
 // Enabled returns true when TLS is enabled based on this option struct.
 func (tls TLSOptions) Enabled() bool {
 	selfSigned := tls.SelfSignedCertificate
-	return selfSigned == nil || !selfSigned.Disabled || tls.Certificate.SecretName != ""
+	return selfSigned == nil || !selfSigned.Disabled || tls.Certificate.SecretName != "" || tls.IssuerRef != nil
+}
+
+// UsesIssuer returns true when the HTTP certificate is signed by an external issuer. A user-provided certificate takes
+// precedence over the issuer.
+func (tls TLSOptions) UsesIssuer() bool {
+	return tls.IssuerRef != nil && tls.Certificate.SecretName == ""
 }
 
 // SelfSignedCertificate holds configuration for the self-signed certificate generated by the operator.
@@ -120,6 +173,78 @@ type SelfSignedCertificate struct {
 	Disabled bool `json:"disabled,omitempty"`
 }
 
+// PrivateKeyAlgorithm is the public key algorithm of a private key.
+type PrivateKeyAlgorithm string
+
+const (
+	// RSAPrivateKeyAlgorithm is the RSA public key algorithm.
+	RSAPrivateKeyAlgorithm PrivateKeyAlgorithm = "RSA"
+	// ECDSAPrivateKeyAlgorithm is the ECDSA public key algorithm.
+	ECDSAPrivateKeyAlgorithm PrivateKeyAlgorithm = "ECDSA"
+)
+
+// PrivateKeyOptions holds configuration for the private keys generated by the operator.
+type PrivateKeyOptions struct {
+	// Algorithm is the public key algorithm of the private key, either RSA or ECDSA.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=RSA;ECDSA
+	Algorithm PrivateKeyAlgorithm `json:"algorithm,omitempty"`
+	// Size is the size of the private key in bits: 2048, 3072 or 4096 for RSA (defaults to 2048),
+	// 256, 384 or 521 for ECDSA (defaults to 256).
+	// +kubebuilder:validation:Optional
+	Size int `json:"size,omitempty"`
+}
+
+var (
+	// SupportedRSAKeySizes are the supported sizes in bits of the RSA private keys.
+	SupportedRSAKeySizes = []int{2048, 3072, 4096}
+	// SupportedECDSAKeySizes are the supported sizes in bits of the ECDSA private keys.
+	SupportedECDSAKeySizes = []int{256, 384, 521}
+)
+
+// Validate returns an error if the algorithm or the size of the private key options are not supported. A missing
+// algorithm stands for RSA and a missing size for the default size of the algorithm.
+func (o PrivateKeyOptions) Validate() error {
+	switch o.Algorithm {
+	case "", RSAPrivateKeyAlgorithm:
+		if o.Size != 0 && !containsInt(SupportedRSAKeySizes, o.Size) {
+			return fmt.Errorf("unsupported RSA private key size %d, must be one of %v", o.Size, SupportedRSAKeySizes)
+		}
+		return nil
+	case ECDSAPrivateKeyAlgorithm:
+		if o.Size != 0 && !containsInt(SupportedECDSAKeySizes, o.Size) {
+			return fmt.Errorf("unsupported ECDSA private key size %d, must be one of %v", o.Size, SupportedECDSAKeySizes)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported private key algorithm %s, must be one of [%s %s]",
+			o.Algorithm, RSAPrivateKeyAlgorithm, ECDSAPrivateKeyAlgorithm)
+	}
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IssuerReference is a reference to an issuer able to sign cert-manager CertificateRequests.
+type IssuerReference struct {
+	// Name of the issuer.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Kind of the issuer, for example Issuer or ClusterIssuer. Defaults to Issuer.
+	// +kubebuilder:validation:Optional
+	Kind string `json:"kind,omitempty"`
+	// Group of the issuer. Defaults to cert-manager.io.
+	// +kubebuilder:validation:Optional
+	Group string `json:"group,omitempty"`
+}
+
 // SubjectAlternativeName represents a SAN entry in a x509 certificate.
 type SubjectAlternativeName struct {
 	// DNS is the DNS name of the subject.
@@ -227,3 +352,32 @@ type ConfigSource struct {
 	// ---
 	SecretRef `json:",inline"`
 }
+
+// Monitoring holds references to both the metrics, and logs Elasticsearch clusters for
+// configuring stack monitoring.
+type Monitoring struct {
+	// Metrics holds references to Elasticsearch clusters which will receive monitoring data from this resource.
+	// +kubebuilder:validation:Optional
+	Metrics MetricsMonitoring `json:"metrics,omitempty"`
+	// Logs holds references to Elasticsearch clusters which will receive log data from this resource.
+	// +kubebuilder:validation:Optional
+	Logs LogsMonitoring `json:"logs,omitempty"`
+}
+
+// MetricsMonitoring holds a list of Elasticsearch clusters which receive monitoring data from
+// associated resources.
+type MetricsMonitoring struct {
+	// ElasticsearchRefs is a reference to a list of monitoring Elasticsearch clusters running in the same Kubernetes cluster.
+	// Due to existing limitations, only a single Elasticsearch cluster is currently supported.
+	// +kubebuilder:validation:Required
+	ElasticsearchRefs []ObjectSelector `json:"elasticsearchRefs,omitempty"`
+}
+
+// LogsMonitoring holds a list of Elasticsearch clusters which receive logs data from
+// associated resources.
+type LogsMonitoring struct {
+	// ElasticsearchRefs is a reference to a list of monitoring Elasticsearch clusters running in the same Kubernetes cluster.
+	// Due to existing limitations, only a single Elasticsearch cluster is currently supported.
+	// +kubebuilder:validation:Required
+	ElasticsearchRefs []ObjectSelector `json:"elasticsearchRefs,omitempty"`
+}
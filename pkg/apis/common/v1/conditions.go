@@ -0,0 +1,28 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1
+
+// Condition types reported in the status of all the resources managed by the operator.
+const (
+	// ReconciliationComplete is true when the last reconciliation of the resource completed without error and
+	// without any pending operation.
+	ReconciliationComplete = "ReconciliationComplete"
+	// RunningDesiredVersion is true when all the instances of the resource run the version specified in the spec.
+	RunningDesiredVersion = "RunningDesiredVersion"
+)
+
+// Reasons of the ReconciliationComplete condition.
+const (
+	ReconciledReason               = "Reconciled"
+	ReconciliationErrorReason      = "ReconciliationError"
+	ReconciliationInProgressReason = "ReconciliationInProgress"
+)
+
+// Reasons of the RunningDesiredVersion condition.
+const (
+	DesiredVersionRunningReason = "DesiredVersionRunning"
+	VersionMismatchReason       = "VersionMismatch"
+	VersionUnknownReason        = "VersionUnknown"
+)
@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -60,6 +61,47 @@ func CheckNameLength(obj runtime.Object) field.ErrorList {
 	return nil
 }
 
+// CheckAssociationRef checks that the service name of an association reference, if any, is a valid service name and
+// that it is not specified without the name of the referenced resource. A reference to a resource not managed by the
+// operator must only specify the name of the secret describing it.
+func CheckAssociationRef(path *field.Path, ref ObjectSelector) field.ErrorList {
+	var errs field.ErrorList
+	if ref.IsExternal() {
+		for _, f := range []struct{ name, value string }{
+			{name: "name", value: ref.Name},
+			{name: "namespace", value: ref.Namespace},
+			{name: "serviceName", value: ref.ServiceName},
+		} {
+			if f.value != "" {
+				errs = append(errs, field.Forbidden(path.Child(f.name), f.name+" cannot be specified along with secretName"))
+			}
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(ref.SecretName) {
+			errs = append(errs, field.Invalid(path.Child("secretName"), ref.SecretName, msg))
+		}
+		return errs
+	}
+	if ref.ServiceName == "" {
+		return nil
+	}
+	if ref.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), "name is required when serviceName is specified"))
+	}
+	for _, msg := range validation.IsDNS1035Label(ref.ServiceName) {
+		errs = append(errs, field.Invalid(path.Child("serviceName"), ref.ServiceName, msg))
+	}
+	return errs
+}
+
+// CheckNoSecretName checks that a reference to a resource other than Elasticsearch does not specify a secret name,
+// which is only supported to reference Elasticsearch clusters not managed by the operator.
+func CheckNoSecretName(path *field.Path, ref ObjectSelector) field.ErrorList {
+	if ref.IsExternal() {
+		return field.ErrorList{field.Forbidden(path.Child("secretName"), "secretName can only be used to reference an Elasticsearch cluster")}
+	}
+	return nil
+}
+
 // CheckSupportedStackVersion checks that the given version is a valid Stack version supported by ECK.
 func CheckSupportedStackVersion(ver string, supported version.MinMaxVersion) field.ErrorList {
 	v, err := ParseVersion(ver)
@@ -101,3 +143,15 @@ func ParseVersion(ver string) (*version.Version, field.ErrorList) {
 
 	return &v, nil
 }
+
+// CheckPrivateKey checks that the algorithm and size of the private key options of the HTTP layer, if any, are supported.
+func CheckPrivateKey(http HTTPConfig) field.ErrorList {
+	privateKey := http.TLS.PrivateKey
+	if privateKey == nil {
+		return nil
+	}
+	if err := privateKey.Validate(); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("http", "tls", "privateKey"), *privateKey, err.Error())}
+	}
+	return nil
+}
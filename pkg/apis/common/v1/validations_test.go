@@ -0,0 +1,116 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestCheckAssociationRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     ObjectSelector
+		wantErr bool
+	}{
+		{
+			name: "no reference",
+			ref:  ObjectSelector{},
+		},
+		{
+			name: "no service name",
+			ref:  ObjectSelector{Name: "es", Namespace: "ns"},
+		},
+		{
+			name: "valid service name",
+			ref:  ObjectSelector{Name: "es", Namespace: "ns", ServiceName: "coordinating-nodes"},
+		},
+		{
+			name:    "invalid service name",
+			ref:     ObjectSelector{Name: "es", Namespace: "ns", ServiceName: "Coordinating_Nodes"},
+			wantErr: true,
+		},
+		{
+			name: "secret name",
+			ref:  ObjectSelector{SecretName: "external-es"},
+		},
+		{
+			name:    "secret name with name",
+			ref:     ObjectSelector{Name: "es", SecretName: "external-es"},
+			wantErr: true,
+		},
+		{
+			name:    "secret name with namespace",
+			ref:     ObjectSelector{Namespace: "ns", SecretName: "external-es"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid secret name",
+			ref:     ObjectSelector{SecretName: "External_ES"},
+			wantErr: true,
+		},
+		{
+			name:    "service name without name",
+			ref:     ObjectSelector{Namespace: "ns", ServiceName: "coordinating-nodes"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CheckAssociationRef(field.NewPath("spec").Child("elasticsearchRef"), tt.ref)
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("CheckAssociationRef() errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPrivateKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		privateKey *PrivateKeyOptions
+		wantErr    bool
+	}{
+		{
+			name: "no private key options",
+		},
+		{
+			name:       "default options",
+			privateKey: &PrivateKeyOptions{},
+		},
+		{
+			name:       "default ECDSA size",
+			privateKey: &PrivateKeyOptions{Algorithm: ECDSAPrivateKeyAlgorithm},
+		},
+		{
+			name:       "RSA 3072",
+			privateKey: &PrivateKeyOptions{Algorithm: RSAPrivateKeyAlgorithm, Size: 3072},
+		},
+		{
+			name:       "default algorithm with an unsupported size",
+			privateKey: &PrivateKeyOptions{Size: 256},
+			wantErr:    true,
+		},
+		{
+			name:       "ECDSA 4096",
+			privateKey: &PrivateKeyOptions{Algorithm: ECDSAPrivateKeyAlgorithm, Size: 4096},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown algorithm",
+			privateKey: &PrivateKeyOptions{Algorithm: "DSA"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CheckPrivateKey(HTTPConfig{TLS: TLSOptions{PrivateKey: tt.privateKey}})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("CheckPrivateKey() errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1
+
+import (
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+)
+
+// ClusterResourcesSpec declares the index lifecycle management policies, templates and ingest pipelines managed
+// by the operator.
+type ClusterResourcesSpec struct {
+	// IndexLifecyclePolicies are the index lifecycle management policies to create in Elasticsearch.
+	// +kubebuilder:validation:Optional
+	IndexLifecyclePolicies []ClusterResource `json:"indexLifecyclePolicies,omitempty"`
+
+	// ComponentTemplates are the component templates to create in Elasticsearch.
+	// Component templates are available in Elasticsearch 7.8.0 and above.
+	// +kubebuilder:validation:Optional
+	ComponentTemplates []ClusterResource `json:"componentTemplates,omitempty"`
+
+	// IndexTemplates are the composable index templates to create in Elasticsearch.
+	// Composable index templates are available in Elasticsearch 7.8.0 and above.
+	// +kubebuilder:validation:Optional
+	IndexTemplates []ClusterResource `json:"indexTemplates,omitempty"`
+
+	// IngestPipelines are the ingest pipelines to create in Elasticsearch.
+	// +kubebuilder:validation:Optional
+	IngestPipelines []ClusterResource `json:"ingestPipelines,omitempty"`
+
+	// Prune enables the deletion from Elasticsearch of the resources removed from this specification.
+	// Defaults to false: resources removed from the specification are left untouched in Elasticsearch.
+	// +kubebuilder:validation:Optional
+	Prune bool `json:"prune,omitempty"`
+}
+
+// ClusterResource declares a resource created through the Elasticsearch API. Its definition is the body of the
+// request used to create it, either inline or read from a ConfigMap or a Secret.
+type ClusterResource struct {
+	// Name is the name of the resource in Elasticsearch. It must be unique among the resources of the same kind.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Definition is the inline body of the request used to create the resource.
+	// +kubebuilder:validation:Optional
+	Definition *commonv1.Config `json:"definition,omitempty"`
+
+	// DefinitionRef references a ConfigMap or a Secret holding the body of the request used to create the resource,
+	// in JSON or YAML. Mutually exclusive with Definition.
+	// +kubebuilder:validation:Optional
+	DefinitionRef *ClusterResourceDefinitionSource `json:"definitionRef,omitempty"`
+}
+
+// ClusterResourceDefinitionSource references a key of a ConfigMap or of a Secret in the same namespace as the
+// Elasticsearch resource.
+type ClusterResourceDefinitionSource struct {
+	// ConfigMapName is the name of the ConfigMap holding the definition. Mutually exclusive with SecretName.
+	// +kubebuilder:validation:Optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// SecretName is the name of the Secret holding the definition. Mutually exclusive with ConfigMapName.
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Key is the key of the ConfigMap or Secret entry holding the definition.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// ClusterResourceKind is the kind of a resource created through the Elasticsearch API.
+type ClusterResourceKind string
+
+const (
+	IndexLifecyclePolicyKind ClusterResourceKind = "IndexLifecyclePolicy"
+	ComponentTemplateKind    ClusterResourceKind = "ComponentTemplate"
+	IndexTemplateKind        ClusterResourceKind = "IndexTemplate"
+	IngestPipelineKind       ClusterResourceKind = "IngestPipeline"
+	NativeUserKind           ClusterResourceKind = "NativeUser"
+	RoleMappingKind          ClusterResourceKind = "RoleMapping"
+)
+
+// ClusterResourcePhase is the phase of a resource created through the Elasticsearch API.
+type ClusterResourcePhase string
+
+const (
+	// ClusterResourceAppliedPhase indicates that the resource matches its definition in Elasticsearch.
+	ClusterResourceAppliedPhase ClusterResourcePhase = "Applied"
+	// ClusterResourceInvalidPhase indicates that the definition of the resource cannot be read.
+	ClusterResourceInvalidPhase ClusterResourcePhase = "Invalid"
+	// ClusterResourceFailedPhase indicates that the resource cannot be created or updated in Elasticsearch.
+	ClusterResourceFailedPhase ClusterResourcePhase = "Failed"
+	// ClusterResourceConflictPhase indicates that a resource with the same name, which was not created by the
+	// operator, already exists in Elasticsearch and is left untouched.
+	ClusterResourceConflictPhase ClusterResourcePhase = "Conflict"
+)
+
+// ClusterResourceStatus reports the state of a resource declared in the specification.
+type ClusterResourceStatus struct {
+	// Kind is the kind of the resource.
+	Kind ClusterResourceKind `json:"kind"`
+
+	// Name is the name of the resource.
+	Name string `json:"name"`
+
+	// Phase is the outcome of the last reconciliation of the resource.
+	Phase ClusterResourcePhase `json:"phase"`
+
+	// Message describes the error encountered when reconciling the resource, if any.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// ByKind returns the resources declared in the specification, grouped by kind.
+func (s ClusterResourcesSpec) ByKind() map[ClusterResourceKind][]ClusterResource {
+	return map[ClusterResourceKind][]ClusterResource{
+		IndexLifecyclePolicyKind: s.IndexLifecyclePolicies,
+		ComponentTemplateKind:    s.ComponentTemplates,
+		IndexTemplateKind:        s.IndexTemplates,
+		IngestPipelineKind:       s.IngestPipelines,
+	}
+}
+
+// IsEmpty returns true if no resource is declared in the specification.
+func (s ClusterResourcesSpec) IsEmpty() bool {
+	return len(s.IndexLifecyclePolicies) == 0 && len(s.ComponentTemplates) == 0 &&
+		len(s.IndexTemplates) == 0 && len(s.IngestPipelines) == 0
+}
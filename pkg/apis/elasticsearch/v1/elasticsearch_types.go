@@ -5,8 +5,11 @@
 package v1
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
@@ -45,7 +48,8 @@ type ElasticsearchSpec struct {
 	UpdateStrategy UpdateStrategy `json:"updateStrategy,omitempty"`
 
 	// PodDisruptionBudget provides access to the default pod disruption budget for the Elasticsearch cluster.
-	// The default budget selects all cluster pods and sets `maxUnavailable` to 1. To disable, set `PodDisruptionBudget`
+	// The default budgets select the pods of each group of node roles (master nodes, each data tier, other nodes) and
+	// set `maxUnavailable` according to the cluster health and topology. To disable, set `PodDisruptionBudget`
 	// to the empty value (`{}` in YAML).
 	// +kubebuilder:validation:Optional
 	PodDisruptionBudget *commonv1.PodDisruptionBudgetTemplate `json:"podDisruptionBudget,omitempty"`
@@ -67,11 +71,33 @@ type ElasticsearchSpec struct {
 	// +optional
 	RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
 
+	// Snapshots declares the snapshot repositories and the snapshot lifecycle management policies to create in
+	// Elasticsearch. Repositories and policies removed from this section are deleted from Elasticsearch.
+	// +kubebuilder:validation:Optional
+	Snapshots SnapshotsSpec `json:"snapshots,omitempty"`
+
+	// ClusterResources declares the index lifecycle management policies, component and index templates and ingest
+	// pipelines to create in Elasticsearch.
+	// +kubebuilder:validation:Optional
+	ClusterResources ClusterResourcesSpec `json:"clusterResources,omitempty"`
+
 	// VolumeClaimDeletePolicy sets the policy for handling deletion of PersistentVolumeClaims for all NodeSets.
 	// Possible values are DeleteOnScaledownOnly and DeleteOnScaledownAndClusterDeletion. Defaults to DeleteOnScaledownAndClusterDeletion.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=DeleteOnScaledownOnly;DeleteOnScaledownAndClusterDeletion
 	VolumeClaimDeletePolicy VolumeClaimDeletePolicy `json:"volumeClaimDeletePolicy,omitempty"`
+
+	// ZoneAwareness enables shard allocation awareness based on a label of the Kubernetes nodes the Elasticsearch Pods
+	// are scheduled on, and spreads the Pods of each NodeSet across the values of that label.
+	// +kubebuilder:validation:Optional
+	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
+
+	// Monitoring enables you to collect and ship log and monitoring data of this Elasticsearch cluster.
+	// See https://www.elastic.co/guide/en/elasticsearch/reference/current/monitor-elasticsearch-cluster.html.
+	// Metricbeat and Filebeat are deployed in the same Pod as sidecars and each one sends data to one or two different
+	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
+	// +kubebuilder:validation:Optional
+	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
 }
 
 // VolumeClaimDeletePolicy describes the delete policy for handling PersistentVolumeClaims that hold Elasticsearch data.
@@ -86,6 +112,48 @@ const (
 	DeleteOnScaledownOnlyPolicy VolumeClaimDeletePolicy = "DeleteOnScaledownOnly"
 )
 
+const (
+	// DefaultZoneAwarenessTopologyKey is the node label used for zone awareness if none is specified.
+	DefaultZoneAwarenessTopologyKey = "topology.kubernetes.io/zone"
+	// DefaultZoneAwarenessAttribute is the Elasticsearch node attribute used for zone awareness if none is specified.
+	DefaultZoneAwarenessAttribute = "zone"
+)
+
+// ZoneAwareness configures shard allocation awareness from the topology of the Kubernetes nodes.
+type ZoneAwareness struct {
+	// TopologyKey is the label of the Kubernetes nodes whose value is set as an attribute of the Elasticsearch nodes
+	// running on them. Defaults to topology.kubernetes.io/zone.
+	// The Pods read this label from the Kubernetes API: their service account must be allowed to get Kubernetes nodes.
+	// +kubebuilder:validation:Optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// Attribute is the name of the Elasticsearch node attribute (node.attr.<attribute>) used for shard allocation
+	// awareness. Defaults to zone.
+	// +kubebuilder:validation:Optional
+	Attribute string `json:"attribute,omitempty"`
+
+	// ForcedValues are the expected values of the topology key. If set, forced awareness is enabled to prevent all
+	// the replicas of a shard from being allocated to the remaining locations when one of them is lost.
+	// +kubebuilder:validation:Optional
+	ForcedValues []string `json:"forcedValues,omitempty"`
+}
+
+// TopologyKeyOrDefault returns the node label used for zone awareness.
+func (za ZoneAwareness) TopologyKeyOrDefault() string {
+	if za.TopologyKey == "" {
+		return DefaultZoneAwarenessTopologyKey
+	}
+	return za.TopologyKey
+}
+
+// AttributeOrDefault returns the Elasticsearch node attribute used for zone awareness.
+func (za ZoneAwareness) AttributeOrDefault() string {
+	if za.Attribute == "" {
+		return DefaultZoneAwarenessAttribute
+	}
+	return za.Attribute
+}
+
 // TransportConfig holds the transport layer settings for Elasticsearch.
 type TransportConfig struct {
 	// Service defines the template for the associated Kubernetes Service object.
@@ -102,12 +170,38 @@ type TransportTLSOptions struct {
 	// - `tls.crt`: The CA certificate in PEM format.
 	// - `tls.key`: The private key for the CA certificate in PEM format.
 	Certificate commonv1.SecretRef `json:"certificate,omitempty"`
+
+	// IssuerRef is a reference to an external issuer, for example a cert-manager Issuer or ClusterIssuer, used to sign
+	// the transport certificates of the nodes instead of the certificate authority of the operator.
+	// Mutually exclusive with Certificate.
+	IssuerRef *commonv1.IssuerReference `json:"issuerRef,omitempty"`
+
+	// NodeCertificates is a reference to a Kubernetes secret that contains the transport certificates of the nodes,
+	// signed by a certificate authority the operator does not have access to.
+	// The referenced secret should contain the following:
+	//
+	// - `ca.crt`: The CA certificate(s) to trust, in PEM format.
+	// - `<pod-name>.tls.crt` and `<pod-name>.tls.key`: The certificate and private key of a given Pod, in PEM format.
+	// - `tls.crt` and `tls.key`: A wildcard certificate and its private key, used by the Pods without a dedicated certificate.
+	// Mutually exclusive with Certificate and IssuerRef.
+	NodeCertificates commonv1.SecretRef `json:"nodeCertificates,omitempty"`
 }
 
 func (tto TransportTLSOptions) UserDefinedCA() bool {
 	return tto.Certificate.SecretName != ""
 }
 
+// UserDefinedNodeCertificates returns true when the transport certificates of the nodes are provided by the user.
+func (tto TransportTLSOptions) UserDefinedNodeCertificates() bool {
+	return tto.NodeCertificates.SecretName != ""
+}
+
+// UsesIssuer returns true when the transport certificates are signed by an external issuer. A user-defined CA or
+// user-defined node certificates take precedence over the issuer.
+func (tto TransportTLSOptions) UsesIssuer() bool {
+	return tto.IssuerRef != nil && !tto.UserDefinedCA() && !tto.UserDefinedNodeCertificates()
+}
+
 // RemoteCluster declares a remote Elasticsearch cluster connection.
 type RemoteCluster struct {
 	// Name is the name of the remote cluster as it is set in the Elasticsearch settings.
@@ -119,10 +213,36 @@ type RemoteCluster struct {
 	// ElasticsearchRef is a reference to an Elasticsearch cluster running within the same k8s cluster.
 	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
 
+	// Seeds is a list of transport addresses (host:port) of a remote Elasticsearch cluster not managed by ECK in
+	// the same k8s cluster, to connect to in sniff mode. Mutually exclusive with ElasticsearchRef and ProxyAddress.
+	// +kubebuilder:validation:Optional
+	Seeds []string `json:"seeds,omitempty"`
+
+	// ProxyAddress is the transport address (host:port) used to connect to a remote Elasticsearch cluster in proxy
+	// mode, for example through a load balancer. Requires Elasticsearch 7.7.0 or later.
+	// Mutually exclusive with ElasticsearchRef and Seeds.
+	// +kubebuilder:validation:Optional
+	ProxyAddress string `json:"proxyAddress,omitempty"`
+
+	// ServerName is the server name sent in the TLS SNI extension when connecting in proxy mode.
+	// +kubebuilder:validation:Optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// CertificateAuthorities references a Secret in the same namespace, containing the CA certificate of the
+	// transport layer of a remote cluster not managed by ECK in the same k8s cluster under the `ca.crt` key.
+	// The CA is added to the certificates trusted by this cluster.
+	// +kubebuilder:validation:Optional
+	CertificateAuthorities commonv1.SecretRef `json:"certificateAuthorities,omitempty"`
+
 	// TODO: Allow the user to specify some options (transport.compress, transport.ping_schedule)
 
 }
 
+// IsExternal returns true if the remote cluster is not referenced as an Elasticsearch resource.
+func (r RemoteCluster) IsExternal() bool {
+	return !r.ElasticsearchRef.IsDefined() && (len(r.Seeds) > 0 || r.ProxyAddress != "")
+}
+
 func (r RemoteCluster) ConfigHash() string {
 	return hash.HashObject(r)
 }
@@ -149,6 +269,62 @@ type Auth struct {
 	Roles []RoleSource `json:"roles,omitempty"`
 	// FileRealm to propagate to the Elasticsearch cluster.
 	FileRealm []FileRealmSource `json:"fileRealm,omitempty"`
+	// NativeUsers to create in the native realm of the Elasticsearch cluster.
+	// +kubebuilder:validation:Optional
+	NativeUsers []NativeUser `json:"nativeUsers,omitempty"`
+	// RoleMappings to create in the Elasticsearch cluster, to map the users authenticated by external realms
+	// such as SAML, OpenID Connect or LDAP to roles.
+	// +kubebuilder:validation:Optional
+	RoleMappings []RoleMapping `json:"roleMappings,omitempty"`
+	// PasswordRotationInterval is the interval at which the passwords generated by the operator are rotated: the
+	// passwords of the elastic user, of the internal users of the operator and of the users created for associations.
+	// Passwords are not rotated periodically if not set. It must be at least 1h.
+	// +kubebuilder:validation:Optional
+	PasswordRotationInterval *metav1.Duration `json:"passwordRotationInterval,omitempty"`
+	// ElasticUser configures the elastic superuser. By default, the operator creates it with a generated password
+	// stored in the <name>-es-elastic-user Secret.
+	// +kubebuilder:validation:Optional
+	ElasticUser *ElasticUser `json:"elasticUser,omitempty"`
+}
+
+// ElasticUserDisabled returns true if the elastic user must not be created.
+func (a Auth) ElasticUserDisabled() bool {
+	return a.ElasticUser != nil && a.ElasticUser.Disabled
+}
+
+// ElasticUserPasswordHashSource returns the user-provided source of the password hash of the elastic user, or nil if
+// the operator generates its password.
+func (a Auth) ElasticUserPasswordHashSource() *PasswordHashSource {
+	if a.ElasticUser == nil || a.ElasticUser.Disabled {
+		return nil
+	}
+	return a.ElasticUser.PasswordHashSecretRef
+}
+
+// ElasticUser configures the elastic superuser created by the operator in the file realm.
+type ElasticUser struct {
+	// Disabled prevents the operator from creating the elastic user. No default credentials are then available to
+	// access the cluster: users must be declared by other means, for example in the native realm.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+	// PasswordHashSecretRef references the key of a Secret, in the same namespace as the Elasticsearch resource,
+	// holding the hash of the password of the elastic user, in a format supported by the file realm such as bcrypt.
+	// The operator neither generates the password of the elastic user nor stores it in a Secret if set.
+	// +kubebuilder:validation:Optional
+	PasswordHashSecretRef *PasswordHashSource `json:"passwordHashSecretRef,omitempty"`
+}
+
+// PasswordHashSource references the key of a Secret holding a password hash.
+type PasswordHashSource struct {
+	// SecretName is the name of the Secret holding the password hash.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Key is the key of the Secret entry holding the password hash.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // RoleSource references roles to create in the Elasticsearch cluster.
@@ -235,6 +411,12 @@ type NodeSet struct {
 	// Items defined here take precedence over any default claims added by the operator with the same name.
 	// +kubebuilder:validation:Optional
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+
+	// Service defines the template for an optional Kubernetes Service dedicated to the HTTP layer of the Pods of
+	// this NodeSet, for example to send ingest or search requests only to the nodes of this NodeSet.
+	// The Service is named `<cluster-name>-es-<nodeset-name>-es-http`.
+	// +kubebuilder:validation:Optional
+	Service *commonv1.ServiceTemplate `json:"service,omitempty"`
 }
 
 // +kubebuilder:object:generate=false
@@ -364,8 +546,77 @@ type ElasticsearchStatus struct {
 	Version string                          `json:"version,omitempty"`
 	Health  ElasticsearchHealth             `json:"health,omitempty"`
 	Phase   ElasticsearchOrchestrationPhase `json:"phase,omitempty"`
+
+	// MonitoringAssociationsStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
+	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
+
+	// Conditions holds the latest available observations of the state of the Elasticsearch cluster.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// SnapshotLifecyclePolicies reports the time of the last successful and failed snapshots of each snapshot
+	// lifecycle management policy declared in the specification.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SnapshotLifecyclePolicies []SnapshotLifecyclePolicyStatus `json:"snapshotLifecyclePolicies,omitempty"`
+
+	// ClusterResources reports the state of the index lifecycle management policies, templates and ingest pipelines
+	// declared in the specification.
+	// +listType=map
+	// +listMapKey=kind
+	// +listMapKey=name
+	// +optional
+	ClusterResources []ClusterResourceStatus `json:"clusterResources,omitempty"`
+
+	// Auth reports the state of the native realm users and of the role mappings declared in the specification.
+	// +listType=map
+	// +listMapKey=kind
+	// +listMapKey=name
+	// +optional
+	Auth []ClusterResourceStatus `json:"auth,omitempty"`
+
+	// LastPasswordRotationTime is the time of the last rotation of the passwords of the elastic user and of the internal
+	// users of the operator.
+	// +optional
+	LastPasswordRotationTime *metav1.Time `json:"lastPasswordRotationTime,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this Elasticsearch cluster.
+	// If it diverges from the metadata generation, the Elasticsearch controller has not yet processed the latest
+	// changes to the specification.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// Condition types specific to Elasticsearch, in addition to the ones common to all the resources.
+const (
+	// ElasticsearchIsReachable is true when the Elasticsearch HTTP service has ready endpoints.
+	ElasticsearchIsReachable = "ElasticsearchIsReachable"
+	// UpgradeBlocked is true when some Pods cannot be restarted to apply a spec change because some predicates
+	// of the rolling upgrade failed.
+	UpgradeBlocked = "UpgradeBlocked"
+	// TransportCertificatesValid is false when the user-provided transport certificates of some Pods are missing,
+	// invalid or about to expire.
+	TransportCertificatesValid = "TransportCertificatesValid"
+	// DefaultCredentialsAvailable is true when the operator generates the password of the elastic user and stores it
+	// in a Secret. It is false when the elastic user is disabled or its password hash is provided by the user.
+	DefaultCredentialsAvailable = "DefaultCredentialsAvailable"
+)
+
+// Reasons of the Elasticsearch specific conditions.
+const (
+	ServiceReadyReason         = "ServiceReady"
+	ServiceNotReadyReason      = "ServiceNotReady"
+	PredicatesFailedReason     = "PredicatesFailed"
+	NoPredicateFailureReason   = "NoPredicateFailure"
+	ValidCertificatesReason    = "ValidCertificates"
+	InvalidCertificatesReason  = "InvalidCertificates"
+	GeneratedPasswordReason    = "GeneratedPassword"
+	ElasticUserDisabledReason  = "ElasticUserDisabled"
+	PasswordHashProvidedReason = "PasswordHashProvided"
+)
+
 type ZenDiscoveryStatus struct {
 	MinimumMasterNodes int `json:"minimumMasterNodes,omitempty"`
 }
@@ -390,8 +641,9 @@ type Elasticsearch struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   ElasticsearchSpec   `json:"spec,omitempty"`
-	Status ElasticsearchStatus `json:"status,omitempty"`
+	Spec       ElasticsearchSpec                                 `json:"spec,omitempty"`
+	Status     ElasticsearchStatus                               `json:"status,omitempty"`
+	assocConfs map[types.NamespacedName]commonv1.AssociationConf `json:"-"` // nolint:govet
 }
 
 // IsMarkedForDeletion returns true if the Elasticsearch is going to be deleted
@@ -411,7 +663,130 @@ func (es Elasticsearch) AutoscalingSpec() string {
 }
 
 func (es Elasticsearch) SecureSettings() []commonv1.SecretSource {
-	return es.Spec.SecureSettings
+	// the credentials of the snapshot repositories are also stored in the keystore
+	snapshotSecureSettings := es.Spec.Snapshots.SnapshotSecureSettings()
+	if len(snapshotSecureSettings) == 0 {
+		return es.Spec.SecureSettings
+	}
+	secureSettings := make([]commonv1.SecretSource, 0, len(es.Spec.SecureSettings)+len(snapshotSecureSettings))
+	secureSettings = append(secureSettings, es.Spec.SecureSettings...)
+	return append(secureSettings, snapshotSecureSettings...)
+}
+
+// -- associations
+
+var _ commonv1.Associated = &Elasticsearch{}
+
+func (es *Elasticsearch) ServiceAccountName() string {
+	return es.Spec.ServiceAccountName
+}
+
+// GetMonitoringMetricsRefs returns the references to the Elasticsearch clusters receiving the metrics of this cluster.
+func (es *Elasticsearch) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
+	return es.Spec.Monitoring.Metrics.ElasticsearchRefs
+}
+
+// GetMonitoringLogsRefs returns the references to the Elasticsearch clusters receiving the logs of this cluster.
+func (es *Elasticsearch) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
+	return es.Spec.Monitoring.Logs.ElasticsearchRefs
+}
+
+// MonitoringAssociation returns the association to the monitoring Elasticsearch cluster identified by the given reference.
+func (es *Elasticsearch) MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association {
+	return &EsMonitoringAssociation{
+		Elasticsearch: es,
+		ref:           ref.WithDefaultNamespace(es.Namespace),
+	}
+}
+
+// GetAssociations returns one association per distinct monitoring Elasticsearch cluster referenced
+// for either metrics or logs.
+func (es *Elasticsearch) GetAssociations() []commonv1.Association {
+	associations := make([]commonv1.Association, 0)
+	seen := make(map[types.NamespacedName]bool)
+	for _, ref := range append(es.GetMonitoringMetricsRefs(), es.GetMonitoringLogsRefs()...) {
+		nsRef := ref.WithDefaultNamespace(es.Namespace).NamespacedName()
+		if seen[nsRef] {
+			continue
+		}
+		seen[nsRef] = true
+		associations = append(associations, es.MonitoringAssociation(ref))
+	}
+	return associations
+}
+
+func (es *Elasticsearch) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
+	if typ != commonv1.EsMonitoringAssociationType {
+		return commonv1.AssociationStatusMap{}
+	}
+
+	return es.Status.MonitoringAssociationsStatus
+}
+
+func (es *Elasticsearch) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
+	if typ != commonv1.EsMonitoringAssociationType {
+		return fmt.Errorf("association type %s not known", typ)
+	}
+
+	es.Status.MonitoringAssociationsStatus = status
+	return nil
+}
+
+// EsMonitoringAssociation helps to manage the Elasticsearch+Metricbeat+Filebeat <-> Elasticsearch(es) association.
+type EsMonitoringAssociation struct {
+	// The monitored Elasticsearch cluster from where are collected logs and monitoring metrics
+	*Elasticsearch
+	// ref is the reference to the Elasticsearch used in Association, with a default namespace
+	ref commonv1.ObjectSelector
+}
+
+var _ commonv1.Association = &EsMonitoringAssociation{}
+
+func (ema *EsMonitoringAssociation) AssociationID() string {
+	return fmt.Sprintf("%s-%s", ema.ref.Namespace, ema.ref.NameOrSecretName())
+}
+
+func (ema *EsMonitoringAssociation) Associated() commonv1.Associated {
+	if ema == nil {
+		return nil
+	}
+	if ema.Elasticsearch == nil {
+		ema.Elasticsearch = &Elasticsearch{}
+	}
+	return ema.Elasticsearch
+}
+
+func (ema *EsMonitoringAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EsMonitoringAssociationType
+}
+
+func (ema *EsMonitoringAssociation) AssociationRef() commonv1.ObjectSelector {
+	return ema.ref
+}
+
+func (ema *EsMonitoringAssociation) AssociationConfAnnotationName() string {
+	return commonv1.EsMonitoringConfAnnotationName(ema.ref.NamespacedName())
+}
+
+func (ema *EsMonitoringAssociation) AssociationConf() *commonv1.AssociationConf {
+	if ema.assocConfs == nil {
+		return nil
+	}
+	assocConf, found := ema.assocConfs[ema.ref.NamespacedName()]
+	if !found {
+		return nil
+	}
+
+	return &assocConf
+}
+
+func (ema *EsMonitoringAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	if ema.assocConfs == nil {
+		ema.assocConfs = make(map[types.NamespacedName]commonv1.AssociationConf)
+	}
+	if conf != nil {
+		ema.assocConfs[ema.ref.NamespacedName()] = *conf
+	}
 }
 
 // +kubebuilder:object:root=true
@@ -23,6 +23,7 @@ const (
 	PathLogs = "path.logs"
 
 	ShardAwarenessAttributes = "cluster.routing.allocation.awareness.attributes"
+	ShardAwarenessForce      = "cluster.routing.allocation.awareness.force"
 	NodeAttr                 = "node.attr"
 
 	XPackSecurityAuthcRealmsFileFile1Order     = "xpack.security.authc.realms.file.file1.order"     // 7.x realm syntax
@@ -45,6 +46,9 @@ const (
 	XPackSecurityTransportSslVerificationMode       = "xpack.security.transport.ssl.verification_mode"
 
 	XPackLicenseUploadTypes = "xpack.license.upload.types" // supported >= 7.6.0 used as of 7.8.1
+
+	XPackMonitoringCollectionEnabled              = "xpack.monitoring.collection.enabled"
+	XPackMonitoringElasticsearchCollectionEnabled = "xpack.monitoring.elasticsearch.collection.enabled"
 )
 
 var UnsupportedSettings = []string{
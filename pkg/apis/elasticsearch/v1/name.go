@@ -21,6 +21,7 @@ const (
 	transportServiceSuffix                       = "transport"
 	elasticUserSecretSuffix                      = "elastic-user"
 	internalUsersSecretSuffix                    = "internal-users"
+	pendingPasswordsSecretSuffix                 = "pending-passwords"
 	unicastHostsConfigMapSuffix                  = "unicast-hosts"
 	licenseSecretSuffix                          = "license"
 	defaultPodDisruptionBudget                   = "default"
@@ -49,6 +50,7 @@ var (
 		elasticUserSecretSuffix,
 		rolesAndFileRealmSecretSuffix,
 		internalUsersSecretSuffix,
+		pendingPasswordsSecretSuffix,
 		unicastHostsConfigMapSuffix,
 		licenseSecretSuffix,
 		defaultPodDisruptionBudget,
@@ -131,6 +133,11 @@ func HTTPService(esName string) string {
 	return ESNamer.Suffix(esName, httpServiceSuffix)
 }
 
+// StatefulSetHTTPService returns the name of the optional HTTP service dedicated to the Pods of a StatefulSet.
+func StatefulSetHTTPService(ssetName string) string {
+	return ESNamer.Suffix(ssetName, httpServiceSuffix)
+}
+
 func ElasticUserSecret(esName string) string {
 	return ESNamer.Suffix(esName, elasticUserSecretSuffix)
 }
@@ -143,6 +150,12 @@ func InternalUsersSecret(esName string) string {
 	return ESNamer.Suffix(esName, internalUsersSecretSuffix)
 }
 
+// PendingPasswordsSecret returns the name of the Secret holding the passwords generated by an ongoing rotation, until
+// all the nodes of the cluster accept them.
+func PendingPasswordsSecret(esName string) string {
+	return ESNamer.Suffix(esName, pendingPasswordsSecretSuffix)
+}
+
 // UnicastHostsConfigMap returns the name of the ConfigMap that holds the list of seed nodes for a given cluster.
 func UnicastHostsConfigMap(esName string) string {
 	return ESNamer.Suffix(esName, unicastHostsConfigMapSuffix)
@@ -160,6 +173,11 @@ func DefaultPodDisruptionBudget(esName string) string {
 	return ESNamer.Suffix(esName, defaultPodDisruptionBudget)
 }
 
+// RoleGroupPodDisruptionBudget returns the name of the default PDB covering the given group of node roles.
+func RoleGroupPodDisruptionBudget(esName string, group string) string {
+	return ESNamer.Suffix(esName, defaultPodDisruptionBudget, group)
+}
+
 func RemoteCaSecretName(esName string) string {
 	return ESNamer.Suffix(esName, remoteCaNameSuffix)
 }
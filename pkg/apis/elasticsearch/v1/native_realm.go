@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1
+
+import (
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+)
+
+// NativeUser declares a user of the native realm, created through the Elasticsearch security API.
+// Unlike the users of the file realm, native users are stored in the cluster and can be managed from Kibana.
+type NativeUser struct {
+	// Username is the name of the user. It must be unique among the native users.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Username string `json:"username"`
+
+	// PasswordSecretRef references the key of a Secret, in the same namespace as the Elasticsearch resource, holding
+	// the password of the user. The password is updated in Elasticsearch when the Secret changes.
+	// +kubebuilder:validation:Required
+	PasswordSecretRef NativeUserPasswordSource `json:"passwordSecretRef"`
+
+	// Roles are the roles granted to the user.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles,omitempty"`
+
+	// FullName is the full name of the user.
+	// +kubebuilder:validation:Optional
+	FullName string `json:"fullName,omitempty"`
+
+	// Email is the email address of the user.
+	// +kubebuilder:validation:Optional
+	Email string `json:"email,omitempty"`
+
+	// Metadata is arbitrary metadata attached to the user.
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1.Config `json:"metadata,omitempty"`
+}
+
+// NativeUserPasswordSource references a key of a Secret in the same namespace as the Elasticsearch resource.
+type NativeUserPasswordSource struct {
+	// SecretName is the name of the Secret holding the password.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Key is the key of the Secret entry holding the password.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// RoleMapping declares a role mapping, created through the Elasticsearch security API, granting roles to the users
+// authenticated by realms which do not assign roles themselves, such as SAML, OpenID Connect, LDAP or PKI.
+type RoleMapping struct {
+	// Name is the name of the role mapping. It must be unique among the role mappings.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Roles are the roles granted to the users matching the rules.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+
+	// Rules are the rules determining which users the role mapping applies to, as described in
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/role-mapping-resources.html.
+	// +kubebuilder:validation:Required
+	Rules *commonv1.Config `json:"rules"`
+
+	// Enabled indicates whether the role mapping is applied. Defaults to true.
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Metadata is arbitrary metadata attached to the role mapping.
+	// +kubebuilder:validation:Optional
+	Metadata *commonv1.Config `json:"metadata,omitempty"`
+}
+
+// IsEnabled returns true if the role mapping is applied.
+func (m RoleMapping) IsEnabled() bool {
+	return m.Enabled == nil || *m.Enabled
+}
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1
+
+import (
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotsSpec declares the snapshot repositories and the snapshot lifecycle management (SLM) policies managed
+// by the operator.
+type SnapshotsSpec struct {
+	// Repositories are the snapshot repositories to register in Elasticsearch.
+	// +kubebuilder:validation:Optional
+	Repositories []SnapshotRepository `json:"repositories,omitempty"`
+
+	// Policies are the snapshot lifecycle management policies to create in Elasticsearch.
+	// Snapshot lifecycle management is available in Elasticsearch 7.4.0 and above.
+	// +kubebuilder:validation:Optional
+	Policies []SnapshotLifecyclePolicy `json:"policies,omitempty"`
+}
+
+// SnapshotRepository declares a snapshot repository.
+type SnapshotRepository struct {
+	// Name is the name of the snapshot repository. It must be unique among the repositories.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type is the type of the repository, for example `fs`, `url`, `s3`, `gcs` or `azure`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// Settings holds the settings of the repository, specific to its type.
+	// +kubebuilder:validation:Optional
+	Settings *commonv1.Config `json:"settings,omitempty"`
+
+	// SecureSettings is a list of references to Kubernetes secrets containing the credentials used by the repository,
+	// for example `s3.client.default.access_key`. They are added to the Elasticsearch keystore.
+	// +kubebuilder:validation:Optional
+	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
+}
+
+// SnapshotLifecyclePolicy declares a snapshot lifecycle management policy.
+type SnapshotLifecyclePolicy struct {
+	// Name is the name of the policy. It must be unique among the policies.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Schedule is the periodic or absolute schedule at which the policy creates snapshots, as a cron expression.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// SnapshotName is the name automatically assigned to each snapshot created by the policy. Date math is supported.
+	// Defaults to `<{policy name}-{now/d}>`.
+	// +kubebuilder:validation:Optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// Repository is the name of the repository used to store the snapshots. It can be one of the repositories
+	// declared in the specification, or a repository registered by other means.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// Config holds the configuration of the snapshots created by the policy, for example `indices`
+	// or `include_global_state`.
+	// +kubebuilder:validation:Optional
+	Config *commonv1.Config `json:"config,omitempty"`
+
+	// Retention holds the retention rules used to delete the snapshots created by the policy, for example
+	// `expire_after`, `min_count` or `max_count`.
+	// +kubebuilder:validation:Optional
+	Retention *commonv1.Config `json:"retention,omitempty"`
+}
+
+// SnapshotLifecyclePolicyStatus reports the outcome of the most recent snapshots of a snapshot lifecycle policy.
+type SnapshotLifecyclePolicyStatus struct {
+	// Name is the name of the policy.
+	Name string `json:"name"`
+
+	// LastSuccess is the time of the last successful snapshot created by the policy.
+	// +kubebuilder:validation:Optional
+	LastSuccess *metav1.Time `json:"lastSuccess,omitempty"`
+
+	// LastFailure is the time of the last failed snapshot attempted by the policy.
+	// +kubebuilder:validation:Optional
+	LastFailure *metav1.Time `json:"lastFailure,omitempty"`
+}
+
+// SnapshotSecureSettings returns the secure settings of all the snapshot repositories.
+func (s SnapshotsSpec) SnapshotSecureSettings() []commonv1.SecretSource {
+	var secureSettings []commonv1.SecretSource
+	for _, repository := range s.Repositories {
+		secureSettings = append(secureSettings, repository.SecureSettings...)
+	}
+	return secureSettings
+}
@@ -10,6 +10,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -53,6 +54,13 @@ type EnterpriseSearchSpec struct {
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Monitoring enables you to collect and ship monitoring data of this Enterprise Search.
+	// See https://www.elastic.co/guide/en/enterprise-search/current/monitoring.html.
+	// Metricbeat is deployed in the same Pod as a sidecar and sends data to an Elasticsearch monitoring cluster
+	// running in the same Kubernetes cluster.
+	// +kubebuilder:validation:Optional
+	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
 }
 
 // EnterpriseSearchStatus defines the observed state of EnterpriseSearch
@@ -62,6 +70,8 @@ type EnterpriseSearchStatus struct {
 	ExternalService string `json:"service,omitempty"`
 	// Association is the status of any auto-linking to Elasticsearch clusters.
 	Association commonv1.AssociationStatus `json:"associationStatus,omitempty"`
+	// MonitoringAssociationsStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
+	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 }
 
 // IsMarkedForDeletion returns true if the EnterpriseSearch is going to be deleted
@@ -101,7 +111,7 @@ func (ent *EnterpriseSearch) SetAssociationConf(assocConf *commonv1.AssociationC
 }
 
 func (ent *EnterpriseSearch) RequiresAssociation() bool {
-	return ent.Spec.ElasticsearchRef.Name != ""
+	return ent.Spec.ElasticsearchRef.IsDefined()
 }
 
 func (ent *EnterpriseSearch) GetAssociations() []commonv1.Association {
@@ -109,6 +119,15 @@ func (ent *EnterpriseSearch) GetAssociations() []commonv1.Association {
 	if ent.Spec.ElasticsearchRef.IsDefined() {
 		associations = append(associations, ent)
 	}
+	seen := make(map[types.NamespacedName]bool)
+	for _, ref := range append(ent.GetMonitoringMetricsRefs(), ent.GetMonitoringLogsRefs()...) {
+		nsRef := ref.WithDefaultNamespace(ent.Namespace).NamespacedName()
+		if seen[nsRef] {
+			continue
+		}
+		seen[nsRef] = true
+		associations = append(associations, ent.MonitoringAssociation(ref))
+	}
 	return associations
 }
 
@@ -117,27 +136,53 @@ func (ent *EnterpriseSearch) AssociationID() string {
 }
 
 func (ent *EnterpriseSearch) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
-	single, err := status.Single()
-	if err != nil {
-		return err
-	}
-
-	if typ != commonv1.ElasticsearchAssociationType {
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
+		ent.Status.Association = single
+		return nil
+	case commonv1.EsMonitoringAssociationType:
+		ent.Status.MonitoringAssociationsStatus = status
+		return nil
+	default:
 		return fmt.Errorf("association type %s not known", typ)
 	}
-
-	ent.Status.Association = single
-	return nil
 }
 
 func (ent *EnterpriseSearch) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
-	if typ == commonv1.ElasticsearchAssociationType && ent.Spec.ElasticsearchRef.IsDefined() {
-		return commonv1.NewSingleAssociationStatusMap(ent.Status.Association)
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		if ent.Spec.ElasticsearchRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(ent.Status.Association)
+		}
+	case commonv1.EsMonitoringAssociationType:
+		return ent.Status.MonitoringAssociationsStatus
 	}
 
 	return commonv1.AssociationStatusMap{}
 }
 
+// GetMonitoringMetricsRefs returns the references to the Elasticsearch clusters receiving the metrics of this Enterprise Search.
+func (ent *EnterpriseSearch) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
+	return ent.Spec.Monitoring.Metrics.ElasticsearchRefs
+}
+
+// GetMonitoringLogsRefs returns the references to the Elasticsearch clusters receiving the logs of this Enterprise Search.
+func (ent *EnterpriseSearch) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
+	return ent.Spec.Monitoring.Logs.ElasticsearchRefs
+}
+
+// MonitoringAssociation returns the association to the monitoring Elasticsearch cluster identified by the given reference.
+func (ent *EnterpriseSearch) MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association {
+	return &EntMonitoringAssociation{
+		EnterpriseSearch: ent,
+		ref:              ref.WithDefaultNamespace(ent.Namespace),
+	}
+}
+
 var _ commonv1.Associated = &EnterpriseSearch{}
 var _ commonv1.Association = &EnterpriseSearch{}
 
@@ -155,9 +200,67 @@ type EnterpriseSearch struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec      EnterpriseSearchSpec      `json:"spec,omitempty"`
-	Status    EnterpriseSearchStatus    `json:"status,omitempty"`
-	assocConf *commonv1.AssociationConf `json:"-"` //nolint:govet
+	Spec                 EnterpriseSearchSpec                              `json:"spec,omitempty"`
+	Status               EnterpriseSearchStatus                            `json:"status,omitempty"`
+	assocConf            *commonv1.AssociationConf                         `json:"-"` //nolint:govet
+	monitoringAssocConfs map[types.NamespacedName]commonv1.AssociationConf `json:"-"` //nolint:govet
+}
+
+// EntMonitoringAssociation helps to manage the EnterpriseSearch+Metricbeat <-> Elasticsearch(es) association.
+type EntMonitoringAssociation struct {
+	// The monitored Enterprise Search from where are collected monitoring metrics
+	*EnterpriseSearch
+	// ref is the reference to the Elasticsearch used in Association, with a default namespace
+	ref commonv1.ObjectSelector
+}
+
+var _ commonv1.Association = &EntMonitoringAssociation{}
+
+func (entmon *EntMonitoringAssociation) AssociationID() string {
+	return fmt.Sprintf("%s-%s", entmon.ref.Namespace, entmon.ref.NameOrSecretName())
+}
+
+func (entmon *EntMonitoringAssociation) Associated() commonv1.Associated {
+	if entmon == nil {
+		return nil
+	}
+	if entmon.EnterpriseSearch == nil {
+		entmon.EnterpriseSearch = &EnterpriseSearch{}
+	}
+	return entmon.EnterpriseSearch
+}
+
+func (entmon *EntMonitoringAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EsMonitoringAssociationType
+}
+
+func (entmon *EntMonitoringAssociation) AssociationRef() commonv1.ObjectSelector {
+	return entmon.ref
+}
+
+func (entmon *EntMonitoringAssociation) AssociationConfAnnotationName() string {
+	return commonv1.EsMonitoringConfAnnotationName(entmon.ref.NamespacedName())
+}
+
+func (entmon *EntMonitoringAssociation) AssociationConf() *commonv1.AssociationConf {
+	if entmon.monitoringAssocConfs == nil {
+		return nil
+	}
+	assocConf, found := entmon.monitoringAssocConfs[entmon.ref.NamespacedName()]
+	if !found {
+		return nil
+	}
+
+	return &assocConf
+}
+
+func (entmon *EntMonitoringAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	if entmon.monitoringAssocConfs == nil {
+		entmon.monitoringAssocConfs = make(map[types.NamespacedName]commonv1.AssociationConf)
+	}
+	if conf != nil {
+		entmon.monitoringAssocConfs[entmon.ref.NamespacedName()] = *conf
+	}
 }
 
 // +kubebuilder:object:root=true
@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,6 +27,9 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkMonitoring,
+		checkAssociations,
+		checkPrivateKey,
 	}
 
 	updateChecks = []func(old, curr *EnterpriseSearch) field.ErrorList{
@@ -104,3 +108,17 @@ func checkSupportedVersion(ent *EnterpriseSearch) field.ErrorList {
 func checkNoDowngrade(prev, curr *EnterpriseSearch) field.ErrorList {
 	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
 }
+
+func checkMonitoring(ent *EnterpriseSearch) field.ErrorList {
+	errs := validations.Validate(ent, ent.Spec.Version)
+	// log collection is not implemented for Enterprise Search
+	return append(errs, validations.ValidateMetricsOnly(ent, "Enterprise Search")...)
+}
+
+func checkAssociations(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckAssociationRef(field.NewPath("spec").Child("elasticsearchRef"), ent.Spec.ElasticsearchRef)
+}
+
+func checkPrivateKey(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckPrivateKey(ent.Spec.HTTP)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/test"
 	"github.com/stretchr/testify/require"
@@ -123,6 +124,18 @@ func TestWebhook(t *testing.T) {
 				`spec.version: Forbidden: Version downgrades are not supported`,
 			),
 		},
+		{
+			Name:      "invalid-private-key",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ent := mkEnterpriseSearch(uid)
+				ent.Spec.HTTP.TLS.PrivateKey = &commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 1024}
+				return serialize(t, ent)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.http.tls.privateKey: Invalid value: .*: unsupported RSA private key size 1024`,
+			),
+		},
 	}
 
 	validator := &entv1.EnterpriseSearch{}
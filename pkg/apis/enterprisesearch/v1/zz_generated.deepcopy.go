@@ -11,20 +11,49 @@ package v1
 import (
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EntMonitoringAssociation) DeepCopyInto(out *EntMonitoringAssociation) {
+	*out = *in
+	if in.EnterpriseSearch != nil {
+		in, out := &in.EnterpriseSearch, &out.EnterpriseSearch
+		*out = new(EnterpriseSearch)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ref = in.ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EntMonitoringAssociation.
+func (in *EntMonitoringAssociation) DeepCopy() *EntMonitoringAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(EntMonitoringAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnterpriseSearch) DeepCopyInto(out *EnterpriseSearch) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.assocConf != nil {
 		in, out := &in.assocConf, &out.assocConf
 		*out = new(commonv1.AssociationConf)
 		**out = **in
 	}
+	if in.monitoringAssocConfs != nil {
+		in, out := &in.monitoringAssocConfs, &out.monitoringAssocConfs
+		*out = make(map[types.NamespacedName]commonv1.AssociationConf, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnterpriseSearch.
@@ -92,6 +121,7 @@ func (in *EnterpriseSearchSpec) DeepCopyInto(out *EnterpriseSearchSpec) {
 	in.HTTP.DeepCopyInto(&out.HTTP)
 	out.ElasticsearchRef = in.ElasticsearchRef
 	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnterpriseSearchSpec.
@@ -107,7 +137,14 @@ func (in *EnterpriseSearchSpec) DeepCopy() *EnterpriseSearchSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnterpriseSearchStatus) DeepCopyInto(out *EnterpriseSearchStatus) {
 	*out = *in
-	out.DeploymentStatus = in.DeploymentStatus
+	in.DeploymentStatus.DeepCopyInto(&out.DeploymentStatus)
+	if in.MonitoringAssociationsStatus != nil {
+		in, out := &in.MonitoringAssociationsStatus, &out.MonitoringAssociationsStatus
+		*out = make(commonv1.AssociationStatusMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnterpriseSearchStatus.
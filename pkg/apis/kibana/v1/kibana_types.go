@@ -10,6 +10,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -33,6 +34,14 @@ type KibanaSpec struct {
 	// ElasticsearchRef is a reference to an Elasticsearch cluster running in the same Kubernetes cluster.
 	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
 
+	// MapsRef is a reference to an Elastic Maps Server running in the same Kubernetes cluster.
+	// The URL of the Elastic Maps Server is used to configure the map.emsUrl setting of Kibana.
+	MapsRef commonv1.ObjectSelector `json:"mapsRef,omitempty"`
+
+	// EnterpriseSearchRef is a reference to an Enterprise Search running in the same Kubernetes cluster.
+	// Kibana provides the default Enterprise Search UI starting version 7.14.
+	EnterpriseSearchRef commonv1.ObjectSelector `json:"enterpriseSearchRef,omitempty"`
+
 	// Config holds the Kibana configuration. See: https://www.elastic.co/guide/en/kibana/current/settings.html
 	Config *commonv1.Config `json:"config,omitempty"`
 
@@ -50,12 +59,28 @@ type KibanaSpec struct {
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Monitoring enables you to collect and ship log and monitoring data of this Kibana.
+	// See https://www.elastic.co/guide/en/kibana/current/xpack-monitoring.html.
+	// Metricbeat and Filebeat are deployed in the same Pod as sidecars and each one sends data to one or two different
+	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
+	// +kubebuilder:validation:Optional
+	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
 }
 
 // KibanaStatus defines the observed state of Kibana
 type KibanaStatus struct {
 	commonv1.DeploymentStatus `json:",inline"`
 	AssociationStatus         commonv1.AssociationStatus `json:"associationStatus,omitempty"`
+
+	// MonitoringAssociationsStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
+	MonitoringAssociationsStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
+
+	// MapsAssociationStatus is the status of any auto-linking to an Elastic Maps Server.
+	MapsAssociationStatus commonv1.AssociationStatus `json:"mapsAssociationStatus,omitempty"`
+
+	// EnterpriseSearchAssociationStatus is the status of any auto-linking to Enterprise Search.
+	EnterpriseSearchAssociationStatus commonv1.AssociationStatus `json:"enterpriseSearchAssociationStatus,omitempty"`
 }
 
 // IsMarkedForDeletion returns true if the Kibana is going to be deleted
@@ -97,29 +122,59 @@ func (k *Kibana) SetAssociationConf(assocConf *commonv1.AssociationConf) {
 
 // RequiresAssociation returns true if the spec specifies an Elasticsearch reference.
 func (k *Kibana) RequiresAssociation() bool {
-	return k.Spec.ElasticsearchRef.Name != ""
+	return k.Spec.ElasticsearchRef.IsDefined()
 }
 
 func (k *Kibana) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
-	if typ == commonv1.ElasticsearchAssociationType && k.Spec.ElasticsearchRef.IsDefined() {
-		return commonv1.NewSingleAssociationStatusMap(k.Status.AssociationStatus)
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		if k.Spec.ElasticsearchRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(k.Status.AssociationStatus)
+		}
+	case commonv1.EsMonitoringAssociationType:
+		return k.Status.MonitoringAssociationsStatus
+	case commonv1.EMSAssociationType:
+		if k.Spec.MapsRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(k.Status.MapsAssociationStatus)
+		}
+	case commonv1.EntAssociationType:
+		if k.Spec.EnterpriseSearchRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(k.Status.EnterpriseSearchAssociationStatus)
+		}
 	}
 
 	return commonv1.AssociationStatusMap{}
 }
 
 func (k *Kibana) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
-	single, err := status.Single()
-	if err != nil {
-		return err
-	}
-
-	if typ != commonv1.ElasticsearchAssociationType {
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
+		k.Status.AssociationStatus = single
+		return nil
+	case commonv1.EsMonitoringAssociationType:
+		k.Status.MonitoringAssociationsStatus = status
+		return nil
+	case commonv1.EMSAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
+		k.Status.MapsAssociationStatus = single
+		return nil
+	case commonv1.EntAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
+		k.Status.EnterpriseSearchAssociationStatus = single
+		return nil
+	default:
 		return fmt.Errorf("association type %s not known", typ)
 	}
-
-	k.Status.AssociationStatus = single
-	return nil
 }
 
 func (k *Kibana) GetAssociations() []commonv1.Association {
@@ -127,6 +182,25 @@ func (k *Kibana) GetAssociations() []commonv1.Association {
 	if k.Spec.ElasticsearchRef.IsDefined() {
 		associations = append(associations, k)
 	}
+	if k.Spec.MapsRef.IsDefined() {
+		associations = append(associations, &KibanaMapsAssociation{
+			Kibana: k,
+		})
+	}
+	if k.Spec.EnterpriseSearchRef.IsDefined() {
+		associations = append(associations, &KibanaEntAssociation{
+			Kibana: k,
+		})
+	}
+	seen := make(map[types.NamespacedName]bool)
+	for _, ref := range append(k.GetMonitoringMetricsRefs(), k.GetMonitoringLogsRefs()...) {
+		nsRef := ref.WithDefaultNamespace(k.Namespace).NamespacedName()
+		if seen[nsRef] {
+			continue
+		}
+		seen[nsRef] = true
+		associations = append(associations, k.MonitoringAssociation(ref))
+	}
 	return associations
 }
 
@@ -134,6 +208,24 @@ func (k *Kibana) AssociationID() string {
 	return commonv1.SingletonAssociationID
 }
 
+// GetMonitoringMetricsRefs returns the references to the Elasticsearch clusters receiving the metrics of this Kibana.
+func (k *Kibana) GetMonitoringMetricsRefs() []commonv1.ObjectSelector {
+	return k.Spec.Monitoring.Metrics.ElasticsearchRefs
+}
+
+// GetMonitoringLogsRefs returns the references to the Elasticsearch clusters receiving the logs of this Kibana.
+func (k *Kibana) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
+	return k.Spec.Monitoring.Logs.ElasticsearchRefs
+}
+
+// MonitoringAssociation returns the association to the monitoring Elasticsearch cluster identified by the given reference.
+func (k *Kibana) MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association {
+	return &KbMonitoringAssociation{
+		Kibana: k,
+		ref:    ref.WithDefaultNamespace(k.Namespace),
+	}
+}
+
 var _ commonv1.Associated = &Kibana{}
 var _ commonv1.Association = &Kibana{}
 
@@ -151,9 +243,167 @@ type Kibana struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec      KibanaSpec                `json:"spec,omitempty"`
-	Status    KibanaStatus              `json:"status,omitempty"`
-	assocConf *commonv1.AssociationConf `json:"-"` //nolint:govet
+	Spec                 KibanaSpec                                        `json:"spec,omitempty"`
+	Status               KibanaStatus                                      `json:"status,omitempty"`
+	assocConf            *commonv1.AssociationConf                         `json:"-"` //nolint:govet
+	mapsAssocConf        *commonv1.AssociationConf                         `json:"-"` //nolint:govet
+	entAssocConf         *commonv1.AssociationConf                         `json:"-"` //nolint:govet
+	monitoringAssocConfs map[types.NamespacedName]commonv1.AssociationConf `json:"-"` //nolint:govet
+}
+
+// KibanaMapsAssociation helps to manage the Kibana / Elastic Maps Server association.
+type KibanaMapsAssociation struct {
+	*Kibana
+}
+
+var _ commonv1.Association = &KibanaMapsAssociation{}
+
+func NewKibanaMapsAssociation(kb *Kibana) *KibanaMapsAssociation {
+	return &KibanaMapsAssociation{Kibana: kb}
+}
+
+func (kbems *KibanaMapsAssociation) Associated() commonv1.Associated {
+	if kbems == nil {
+		return nil
+	}
+	if kbems.Kibana == nil {
+		kbems.Kibana = &Kibana{}
+	}
+	return kbems.Kibana
+}
+
+func (kbems *KibanaMapsAssociation) AssociationConfAnnotationName() string {
+	return commonv1.FormatNameWithID(commonv1.EMSConfigAnnotationNameBase+"%s", kbems.AssociationID())
+}
+
+func (kbems *KibanaMapsAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EMSAssociationType
+}
+
+func (kbems *KibanaMapsAssociation) AssociationRef() commonv1.ObjectSelector {
+	return kbems.Spec.MapsRef.WithDefaultNamespace(kbems.Namespace)
+}
+
+func (kbems *KibanaMapsAssociation) RequiresAssociation() bool {
+	return kbems.Spec.MapsRef.Name != ""
+}
+
+func (kbems *KibanaMapsAssociation) AssociationConf() *commonv1.AssociationConf {
+	return kbems.mapsAssocConf
+}
+
+func (kbems *KibanaMapsAssociation) SetAssociationConf(assocConf *commonv1.AssociationConf) {
+	kbems.mapsAssocConf = assocConf
+}
+
+func (kbems *KibanaMapsAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+// KibanaEntAssociation helps to manage the Kibana / Enterprise Search association.
+type KibanaEntAssociation struct {
+	*Kibana
+}
+
+var _ commonv1.Association = &KibanaEntAssociation{}
+
+func NewKibanaEntAssociation(kb *Kibana) *KibanaEntAssociation {
+	return &KibanaEntAssociation{Kibana: kb}
+}
+
+func (kbent *KibanaEntAssociation) Associated() commonv1.Associated {
+	if kbent == nil {
+		return nil
+	}
+	if kbent.Kibana == nil {
+		kbent.Kibana = &Kibana{}
+	}
+	return kbent.Kibana
+}
+
+func (kbent *KibanaEntAssociation) AssociationConfAnnotationName() string {
+	return commonv1.FormatNameWithID(commonv1.EntConfigAnnotationNameBase+"%s", kbent.AssociationID())
+}
+
+func (kbent *KibanaEntAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EntAssociationType
+}
+
+func (kbent *KibanaEntAssociation) AssociationRef() commonv1.ObjectSelector {
+	return kbent.Spec.EnterpriseSearchRef.WithDefaultNamespace(kbent.Namespace)
+}
+
+func (kbent *KibanaEntAssociation) RequiresAssociation() bool {
+	return kbent.Spec.EnterpriseSearchRef.Name != ""
+}
+
+func (kbent *KibanaEntAssociation) AssociationConf() *commonv1.AssociationConf {
+	return kbent.entAssocConf
+}
+
+func (kbent *KibanaEntAssociation) SetAssociationConf(assocConf *commonv1.AssociationConf) {
+	kbent.entAssocConf = assocConf
+}
+
+func (kbent *KibanaEntAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+// KbMonitoringAssociation helps to manage the Kibana+Metricbeat+Filebeat <-> Elasticsearch(es) association.
+type KbMonitoringAssociation struct {
+	// The monitored Kibana from where are collected logs and monitoring metrics
+	*Kibana
+	// ref is the reference to the Elasticsearch used in Association, with a default namespace
+	ref commonv1.ObjectSelector
+}
+
+var _ commonv1.Association = &KbMonitoringAssociation{}
+
+func (kbmon *KbMonitoringAssociation) AssociationID() string {
+	return fmt.Sprintf("%s-%s", kbmon.ref.Namespace, kbmon.ref.NameOrSecretName())
+}
+
+func (kbmon *KbMonitoringAssociation) Associated() commonv1.Associated {
+	if kbmon == nil {
+		return nil
+	}
+	if kbmon.Kibana == nil {
+		kbmon.Kibana = &Kibana{}
+	}
+	return kbmon.Kibana
+}
+
+func (kbmon *KbMonitoringAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EsMonitoringAssociationType
+}
+
+func (kbmon *KbMonitoringAssociation) AssociationRef() commonv1.ObjectSelector {
+	return kbmon.ref
+}
+
+func (kbmon *KbMonitoringAssociation) AssociationConfAnnotationName() string {
+	return commonv1.EsMonitoringConfAnnotationName(kbmon.ref.NamespacedName())
+}
+
+func (kbmon *KbMonitoringAssociation) AssociationConf() *commonv1.AssociationConf {
+	if kbmon.monitoringAssocConfs == nil {
+		return nil
+	}
+	assocConf, found := kbmon.monitoringAssocConfs[kbmon.ref.NamespacedName()]
+	if !found {
+		return nil
+	}
+
+	return &assocConf
+}
+
+func (kbmon *KbMonitoringAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	if kbmon.monitoringAssocConfs == nil {
+		kbmon.monitoringAssocConfs = make(map[types.NamespacedName]commonv1.AssociationConf)
+	}
+	if conf != nil {
+		kbmon.monitoringAssocConfs[kbmon.ref.NamespacedName()] = *conf
+	}
 }
 
 // +kubebuilder:object:root=true
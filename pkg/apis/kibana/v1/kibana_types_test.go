@@ -13,3 +13,13 @@ func TestApmEsAssociation_AssociationConfAnnotationName(t *testing.T) {
 	k := Kibana{}
 	require.Equal(t, "association.k8s.elastic.co/es-conf", k.AssociationConfAnnotationName())
 }
+
+func TestKibanaMapsAssociation_AssociationConfAnnotationName(t *testing.T) {
+	k := KibanaMapsAssociation{}
+	require.Equal(t, "association.k8s.elastic.co/ems-conf", k.AssociationConfAnnotationName())
+}
+
+func TestKibanaEntAssociation_AssociationConfAnnotationName(t *testing.T) {
+	k := KibanaEntAssociation{}
+	require.Equal(t, "association.k8s.elastic.co/ent-conf", k.AssociationConfAnnotationName())
+}
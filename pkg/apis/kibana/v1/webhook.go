@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,6 +27,9 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkMonitoring,
+		checkAssociations,
+		checkPrivateKey,
 	}
 
 	updateChecks = []func(old, curr *Kibana) field.ErrorList{
@@ -104,3 +108,21 @@ func checkSupportedVersion(k *Kibana) field.ErrorList {
 func checkNoDowngrade(prev, curr *Kibana) field.ErrorList {
 	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
 }
+
+func checkMonitoring(k *Kibana) field.ErrorList {
+	return validations.Validate(k, k.Spec.Version)
+}
+
+func checkAssociations(k *Kibana) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("elasticsearchRef"), k.Spec.ElasticsearchRef)...)
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("enterpriseSearchRef"), k.Spec.EnterpriseSearchRef)...)
+	errs = append(errs, commonv1.CheckNoSecretName(field.NewPath("spec").Child("enterpriseSearchRef"), k.Spec.EnterpriseSearchRef)...)
+	errs = append(errs, commonv1.CheckAssociationRef(field.NewPath("spec").Child("mapsRef"), k.Spec.MapsRef)...)
+	errs = append(errs, commonv1.CheckNoSecretName(field.NewPath("spec").Child("mapsRef"), k.Spec.MapsRef)...)
+	return errs
+}
+
+func checkPrivateKey(k *Kibana) field.ErrorList {
+	return commonv1.CheckPrivateKey(k.Spec.HTTP)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/test"
 	"github.com/stretchr/testify/require"
@@ -91,6 +92,62 @@ func TestWebhook(t *testing.T) {
 				`spec.version: Invalid value: "300.1.2": Unsupported version: version 300.1.2 is higher than the highest supported version`,
 			),
 		},
+		{
+			Name:      "service-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "esname", ServiceName: "coordinating-nodes"}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-service-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				k := mkKibana(uid)
+				k.Spec.EnterpriseSearchRef = commonv1.ObjectSelector{Name: "entname", ServiceName: "Ent_Search"}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.enterpriseSearchRef.serviceName: Invalid value: "Ent_Search": a DNS-1035 label must consist of lower case alphanumeric characters`,
+			),
+		},
+		{
+			Name:      "service-name-without-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{ServiceName: "coordinating-nodes"}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef.name: Required value: name is required when serviceName is specified`,
+			),
+		},
+		{
+			Name:      "external-elasticsearch",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				k := mkKibana(uid)
+				k.Spec.ElasticsearchRef = commonv1.ObjectSelector{SecretName: "external-es"}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "external-enterprise-search",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				k := mkKibana(uid)
+				k.Spec.EnterpriseSearchRef = commonv1.ObjectSelector{SecretName: "external-ent"}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.enterpriseSearchRef.secretName: Forbidden: secretName can only be used to reference an Elasticsearch cluster`,
+			),
+		},
 		{
 			Name:      "update-valid",
 			Operation: admissionv1beta1.Update,
@@ -123,6 +180,18 @@ func TestWebhook(t *testing.T) {
 				`spec.version: Forbidden: Version downgrades are not supported`,
 			),
 		},
+		{
+			Name:      "invalid-private-key",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				k := mkKibana(uid)
+				k.Spec.HTTP.TLS.PrivateKey = &commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 1024}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.http.tls.privateKey: Invalid value: .*: unsupported RSA private key size 1024`,
+			),
+		},
 	}
 
 	validator := &kbv1.Kibana{}
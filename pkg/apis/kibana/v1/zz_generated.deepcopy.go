@@ -11,20 +11,59 @@ package v1
 import (
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KbMonitoringAssociation) DeepCopyInto(out *KbMonitoringAssociation) {
+	*out = *in
+	if in.Kibana != nil {
+		in, out := &in.Kibana, &out.Kibana
+		*out = new(Kibana)
+		(*in).DeepCopyInto(*out)
+	}
+	out.ref = in.ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KbMonitoringAssociation.
+func (in *KbMonitoringAssociation) DeepCopy() *KbMonitoringAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(KbMonitoringAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kibana) DeepCopyInto(out *Kibana) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.assocConf != nil {
 		in, out := &in.assocConf, &out.assocConf
 		*out = new(commonv1.AssociationConf)
 		**out = **in
 	}
+	if in.mapsAssocConf != nil {
+		in, out := &in.mapsAssocConf, &out.mapsAssocConf
+		*out = new(commonv1.AssociationConf)
+		**out = **in
+	}
+	if in.entAssocConf != nil {
+		in, out := &in.entAssocConf, &out.entAssocConf
+		*out = new(commonv1.AssociationConf)
+		**out = **in
+	}
+	if in.monitoringAssocConfs != nil {
+		in, out := &in.monitoringAssocConfs, &out.monitoringAssocConfs
+		*out = make(map[types.NamespacedName]commonv1.AssociationConf, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kibana.
@@ -45,6 +84,26 @@ func (in *Kibana) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaEntAssociation) DeepCopyInto(out *KibanaEntAssociation) {
+	*out = *in
+	if in.Kibana != nil {
+		in, out := &in.Kibana, &out.Kibana
+		*out = new(Kibana)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaEntAssociation.
+func (in *KibanaEntAssociation) DeepCopy() *KibanaEntAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaEntAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KibanaList) DeepCopyInto(out *KibanaList) {
 	*out = *in
@@ -77,10 +136,32 @@ func (in *KibanaList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaMapsAssociation) DeepCopyInto(out *KibanaMapsAssociation) {
+	*out = *in
+	if in.Kibana != nil {
+		in, out := &in.Kibana, &out.Kibana
+		*out = new(Kibana)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaMapsAssociation.
+func (in *KibanaMapsAssociation) DeepCopy() *KibanaMapsAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaMapsAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 	*out = *in
 	out.ElasticsearchRef = in.ElasticsearchRef
+	out.MapsRef = in.MapsRef
+	out.EnterpriseSearchRef = in.EnterpriseSearchRef
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -94,6 +175,7 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
@@ -109,7 +191,14 @@ func (in *KibanaSpec) DeepCopy() *KibanaSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
 	*out = *in
-	out.DeploymentStatus = in.DeploymentStatus
+	in.DeploymentStatus.DeepCopyInto(&out.DeploymentStatus)
+	if in.MonitoringAssociationsStatus != nil {
+		in, out := &in.MonitoringAssociationsStatus, &out.MonitoringAssociationsStatus
+		*out = make(commonv1.AssociationStatusMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
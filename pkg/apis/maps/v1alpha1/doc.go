@@ -0,0 +1,8 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package v1alpha1 contains API schema definitions for managing Elastic Maps Server resources.
+// +kubebuilder:object:generate=true
+// +groupName=maps.k8s.elastic.co
+package v1alpha1
@@ -0,0 +1,116 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("ems-v1alpha1-validation")
+
+	defaultChecks = []func(*ElasticMapsServer) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		checkSupportedVersion,
+		checkAssociations,
+		checkPrivateKey,
+	}
+
+	updateChecks = []func(old, curr *ElasticMapsServer) field.ErrorList{
+		checkNoDowngrade,
+	}
+)
+
+// +kubebuilder:webhook:path=/validate-ems-k8s-elastic-co-v1alpha1-elasticmapsserver,mutating=false,failurePolicy=ignore,groups=maps.k8s.elastic.co,resources=elasticmapsservers,verbs=create;update,versions=v1alpha1,name=elastic-ems-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1;v1beta1,matchPolicy=Exact
+
+var _ webhook.Validator = &ElasticMapsServer{}
+
+func (m *ElasticMapsServer) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(m).
+		Complete()
+}
+
+func (m *ElasticMapsServer) ValidateCreate() error {
+	validationLog.V(1).Info("Validate create", "name", m.Name)
+	return m.validate(nil)
+}
+
+func (m *ElasticMapsServer) ValidateDelete() error {
+	validationLog.V(1).Info("Validate delete", "name", m.Name)
+	return nil
+}
+
+func (m *ElasticMapsServer) ValidateUpdate(old runtime.Object) error {
+	validationLog.V(1).Info("Validate update", "name", m.Name)
+	oldObj, ok := old.(*ElasticMapsServer)
+	if !ok {
+		return errors.New("cannot cast old object to ElasticMapsServer type")
+	}
+
+	return m.validate(oldObj)
+}
+
+func (m *ElasticMapsServer) validate(old *ElasticMapsServer) error {
+	var errors field.ErrorList
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, m); err != nil {
+				errors = append(errors, err...)
+			}
+		}
+
+		if len(errors) > 0 {
+			return apierrors.NewInvalid(groupKind, m.Name, errors)
+		}
+	}
+
+	for _, dc := range defaultChecks {
+		if err := dc(m); err != nil {
+			errors = append(errors, err...)
+		}
+	}
+
+	if len(errors) > 0 {
+		return apierrors.NewInvalid(groupKind, m.Name, errors)
+	}
+	return nil
+}
+
+func checkNoUnknownFields(m *ElasticMapsServer) field.ErrorList {
+	return commonv1.NoUnknownFields(m, m.ObjectMeta)
+}
+
+func checkNameLength(m *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckNameLength(m)
+}
+
+func checkSupportedVersion(m *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckSupportedStackVersion(m.Spec.Version, version.SupportedMapsVersions)
+}
+
+func checkNoDowngrade(prev, curr *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
+}
+
+func checkAssociations(m *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckAssociationRef(field.NewPath("spec").Child("elasticsearchRef"), m.Spec.ElasticsearchRef)
+}
+
+func checkPrivateKey(m *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckPrivateKey(m.Spec.HTTP)
+}
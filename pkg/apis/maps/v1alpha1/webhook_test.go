@@ -0,0 +1,165 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package v1alpha1_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/test"
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestWebhook(t *testing.T) {
+	testCases := []test.ValidationWebhookTestCase{
+		{
+			Name:      "create-valid",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "unknown-field",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.SetAnnotations(map[string]string{
+					corev1.LastAppliedConfigAnnotation: `{"metadata":{"name": "ekesn", "namespace": "default", "uid": "e7a18cfb-b017-475c-8da2-1ec941b1f285", "creationTimestamp":"2020-03-24T13:43:20Z" },"spec":{"version":"7.6.1", "unknown": "UNKNOWN"}}`,
+				})
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookFailed(
+				`"unknown": unknown field found in the kubectl.kubernetes.io/last-applied-configuration annotation is unknown`,
+			),
+		},
+		{
+			Name:      "long-name",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.SetName(strings.Repeat("x", 100))
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookFailed(
+				`metadata.name: Too long: must have at most 36 bytes`,
+			),
+		},
+		{
+			Name:      "invalid-version",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.Version = "7.x"
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Invalid value: "7.x": Invalid version: No Major.Minor.Patch elements found`,
+			),
+		},
+		{
+			Name:      "unsupported-version-lower",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.Version = "3.1.2"
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Invalid value: "3.1.2": Unsupported version: version 3.1.2 is lower than the lowest supported version`,
+			),
+		},
+		{
+			Name:      "unsupported-version-higher",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.Version = "300.1.2"
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Invalid value: "300.1.2": Unsupported version: version 300.1.2 is higher than the highest supported version`,
+			),
+		},
+		{
+			Name:      "update-valid",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.Version = "7.12.0"
+				return serialize(t, ems)
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.Version = "7.12.1"
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "version-downgrade",
+			Operation: admissionv1beta1.Update,
+			OldObject: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.Version = "7.12.1"
+				return serialize(t, ems)
+			},
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.Version = "7.12.0"
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Forbidden: Version downgrades are not supported`,
+			),
+		},
+		{
+			Name:      "invalid-private-key",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				ems := mkMapsServer(uid)
+				ems.Spec.HTTP.TLS.PrivateKey = &commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 1024}
+				return serialize(t, ems)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.http.tls.privateKey: Invalid value: .*: unsupported RSA private key size 1024`,
+			),
+		},
+	}
+
+	validator := &emsv1alpha1.ElasticMapsServer{}
+	gvk := metav1.GroupVersionKind{Group: emsv1alpha1.GroupVersion.Group, Version: emsv1alpha1.GroupVersion.Version, Kind: emsv1alpha1.Kind}
+	test.RunValidationWebhookTests(t, gvk, validator, testCases...)
+}
+
+func mkMapsServer(uid string) *emsv1alpha1.ElasticMapsServer {
+	return &emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "webhook-test",
+			UID:  types.UID(uid),
+		},
+		Spec: emsv1alpha1.MapsSpec{
+			Version: "7.12.0",
+		},
+	}
+}
+
+func serialize(t *testing.T, ems *emsv1alpha1.ElasticMapsServer) []byte {
+	t.Helper()
+
+	objBytes, err := json.Marshal(ems)
+	require.NoError(t, err)
+
+	return objBytes
+}
@@ -12,6 +12,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
@@ -72,7 +74,7 @@ func buildConfig(params Params) ([]byte, error) {
 }
 
 func buildOutputConfig(params Params) (*settings.CanonicalConfig, error) {
-	associations := params.Agent.GetAssociations()
+	associations := esAssociations(params.Agent)
 
 	for _, assoc := range associations {
 		if !assoc.AssociationConf().IsConfigured() {
@@ -82,17 +84,14 @@ func buildOutputConfig(params Params) (*settings.CanonicalConfig, error) {
 
 	outputs := map[string]interface{}{}
 	for i, assoc := range associations {
-		username, password, err := association.ElasticsearchAuthSettings(params.Client, assoc)
+		credentials, err := association.ElasticsearchCredentials(params.Client, assoc)
 		if err != nil {
 			return settings.NewCanonicalConfig(), err
 		}
 
-		output := map[string]interface{}{
-			"type":     "elasticsearch",
-			"username": username,
-			"password": password,
-			"hosts":    []string{assoc.AssociationConf().GetURL()},
-		}
+		output := credentials.OutputSettings()
+		output["type"] = "elasticsearch"
+		output["hosts"] = []string{assoc.AssociationConf().GetURL()}
 		if assoc.AssociationConf().GetCACertProvided() {
 			output["ssl.certificate_authorities"] = []string{path.Join(certificatesDir(assoc), CAFileName)}
 		}
@@ -112,6 +111,17 @@ func buildOutputConfig(params Params) (*settings.CanonicalConfig, error) {
 	})
 }
 
+// esAssociations returns the Elasticsearch associations of the Agent, in the order of the Elasticsearch references.
+func esAssociations(agent agentv1alpha1.Agent) []commonv1.Association {
+	var associations []commonv1.Association
+	for _, assoc := range agent.GetAssociations() {
+		if assoc.AssociationType() == commonv1.ElasticsearchAssociationType {
+			associations = append(associations, assoc)
+		}
+	}
+	return associations
+}
+
 // getUserConfig extracts the config either from the spec `config` field or from the Secret referenced by spec
 // `configRef` field.
 func getUserConfig(params Params) (*settings.CanonicalConfig, error) {
@@ -15,6 +15,7 @@ import (
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	commonassociation "github.com/elastic/cloud-on-k8s/pkg/controller/common/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
@@ -31,6 +32,8 @@ type Params struct {
 	Watches       watches.DynamicWatches
 
 	Agent agentv1alpha1.Agent
+
+	OperatorParams operator.Parameters
 }
 
 func (p Params) K8sClient() k8s.Client {
@@ -69,16 +72,33 @@ func internalReconcile(params Params) *reconciler.Results {
 		return results // will eventually retry
 	}
 
+	fleetCerts, res := reconcileFleetServer(params)
+	if results.WithResults(res).HasError() {
+		return results
+	}
+
 	configHash := sha256.New224()
-	if res := reconcileConfig(params, configHash); res.HasError() {
+	var fleetToken EnrollmentAPIKey
+	if params.Agent.FleetModeEnabled() {
+		// Agents in Fleet mode are configured through Fleet, they only need an enrollment token
+		if fleetToken, err = reconcileEnrollmentToken(params); err != nil {
+			return results.WithError(err)
+		}
+		_, _ = configHash.Write([]byte(fleetToken.APIKey))
+	} else if res := reconcileConfig(params, configHash); res.HasError() {
 		return results.WithResults(res)
 	}
 
+	// rotate Fleet Server Pods on certificate changes
+	if fleetCerts != nil && params.Agent.Spec.HTTP.TLS.Enabled() {
+		_, _ = configHash.Write(fleetCerts.CertPem())
+	}
+
 	// we need to deref the secret here (if any) to include it in the configHash otherwise Agent will not be rolled on content changes
 	if err := commonassociation.WriteAssocsToConfigHash(params.Client, params.Agent.GetAssociations(), configHash); err != nil {
 		return results.WithError(err)
 	}
 
-	podTemplate := buildPodTemplate(params /*keystoreResources,*/, configHash)
+	podTemplate := buildPodTemplate(params, fleetToken, configHash)
 	return results.WithResults(reconcilePodVehicle(params, podTemplate))
 }
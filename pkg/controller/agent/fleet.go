@@ -0,0 +1,319 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+)
+
+const (
+	// FleetServerPort is the port Fleet Server listens on.
+	FleetServerPort = 8220
+
+	// FleetAPIReqTimeout is the duration after which a request to the Kibana Fleet API should be canceled.
+	FleetAPIReqTimeout = 1 * time.Minute
+
+	// EnrollmentTokenKey is the key of the enrollment token in the enrollment token Secret.
+	EnrollmentTokenKey = "FLEET_ENROLLMENT_TOKEN"
+	// FleetTokenIDAnnotationName stores the ID of the enrollment token held in the enrollment token Secret.
+	FleetTokenIDAnnotationName = "agent.k8s.elastic.co/fleet-token-id"
+	// FleetPolicyIDAnnotationName stores the ID of the Fleet policy the enrollment token belongs to.
+	FleetPolicyIDAnnotationName = "agent.k8s.elastic.co/fleet-policy-id"
+)
+
+// EnrollmentAPIKey is a Fleet enrollment token as returned by the Kibana Fleet API.
+type EnrollmentAPIKey struct {
+	ID       string `json:"id"`
+	Active   bool   `json:"active"`
+	APIKey   string `json:"api_key"`
+	PolicyID string `json:"policy_id"`
+}
+
+func (k EnrollmentAPIKey) isEmpty() bool {
+	return k.APIKey == ""
+}
+
+type enrollmentAPIKeyResult struct {
+	Item EnrollmentAPIKey `json:"item"`
+}
+
+type enrollmentAPIKeyList struct {
+	List  []EnrollmentAPIKey `json:"list"`
+	Items []EnrollmentAPIKey `json:"items"`
+}
+
+type agentPolicy struct {
+	ID                   string `json:"id"`
+	IsDefault            bool   `json:"is_default"`
+	IsDefaultFleetServer bool   `json:"is_default_fleet_server"`
+}
+
+type agentPolicyList struct {
+	Items []agentPolicy `json:"items"`
+}
+
+// fleetAPIError is returned when the Kibana Fleet API responds with an unexpected status code.
+type fleetAPIError struct {
+	statusCode int
+	msg        string
+}
+
+func (e *fleetAPIError) Error() string {
+	return fmt.Sprintf("invalid Fleet API response (status code %d): %s", e.statusCode, e.msg)
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*fleetAPIError)
+	return ok && apiErr.statusCode == http.StatusNotFound
+}
+
+// fleetAPI is a minimal client for the Kibana Fleet API.
+type fleetAPI struct {
+	client   *http.Client
+	endpoint string
+	username string
+	password string
+}
+
+// newFleetAPI builds a client for the Fleet API of the Kibana referenced by the Agent.
+func newFleetAPI(params Params) (fleetAPI, error) {
+	kbAssociation := params.Agent.KibanaAssociation()
+	assocConf := kbAssociation.AssociationConf()
+
+	username, password, err := association.ElasticsearchAuthSettings(params.Client, kbAssociation)
+	if err != nil {
+		return fleetAPI{}, err
+	}
+
+	var caCerts []*x509.Certificate
+	if assocConf.CAIsConfigured() {
+		var caSecret corev1.Secret
+		nsn := types.NamespacedName{Namespace: params.Agent.Namespace, Name: assocConf.GetCASecretName()}
+		if err := params.Client.Get(context.Background(), nsn, &caSecret); err != nil {
+			return fleetAPI{}, err
+		}
+		caData, exists := caSecret.Data[certificates.CAFileName]
+		if !exists {
+			return fleetAPI{}, fmt.Errorf("no %s found in secret %s", certificates.CAFileName, caSecret.Name)
+		}
+		if caCerts, err = certificates.ParsePEMCerts(caData); err != nil {
+			return fleetAPI{}, err
+		}
+	}
+
+	return fleetAPI{
+		client:   common.HTTPClient(params.OperatorParams.Dialer, caCerts, FleetAPIReqTimeout),
+		endpoint: assocConf.GetURL(),
+		username: username,
+		password: password,
+	}, nil
+}
+
+// request performs an HTTP request against the Fleet API and decodes the JSON response body into out, if not nil.
+func (f fleetAPI) request(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		outData, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(outData)
+	}
+
+	request, err := http.NewRequest(method, stringsutil.Concat(f.endpoint, "/api/fleet/", path), reqBody)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	// required by Kibana for any request modifying data
+	request.Header.Set("kbn-xsrf", "true")
+	request.SetBasicAuth(f.username, f.password)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, FleetAPIReqTimeout)
+	defer cancel()
+
+	resp, err := f.client.Do(request.WithContext(timeoutCtx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &fleetAPIError{statusCode: resp.StatusCode, msg: string(respBody)}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// setupFleet initializes Fleet in Kibana, creating the default policies if they do not exist yet.
+func (f fleetAPI) setupFleet(ctx context.Context) error {
+	return f.request(ctx, http.MethodPost, "setup", nil, nil)
+}
+
+// defaultPolicyID returns the ID of the default Fleet Server policy, or of the default Agent policy.
+func (f fleetAPI) defaultPolicyID(ctx context.Context, fleetServer bool) (string, error) {
+	var policies agentPolicyList
+	if err := f.request(ctx, http.MethodGet, "agent_policies?perPage=100", nil, &policies); err != nil {
+		return "", err
+	}
+	for _, policy := range policies.Items {
+		if (fleetServer && policy.IsDefaultFleetServer) || (!fleetServer && policy.IsDefault) {
+			return policy.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no default agent policy found (fleet server: %t)", fleetServer)
+}
+
+// getEnrollmentAPIKey returns the enrollment token with the given ID, including its secret value.
+func (f fleetAPI) getEnrollmentAPIKey(ctx context.Context, keyID string) (EnrollmentAPIKey, error) {
+	var result enrollmentAPIKeyResult
+	err := f.request(ctx, http.MethodGet, "enrollment-api-keys/"+keyID, nil, &result)
+	return result.Item, err
+}
+
+// createEnrollmentAPIKey creates a new enrollment token for the given policy.
+func (f fleetAPI) createEnrollmentAPIKey(ctx context.Context, policyID string) (EnrollmentAPIKey, error) {
+	var result enrollmentAPIKeyResult
+	err := f.request(ctx, http.MethodPost, "enrollment-api-keys", map[string]string{"policy_id": policyID}, &result)
+	return result.Item, err
+}
+
+// findEnrollmentAPIKey returns an active enrollment token for the given policy, if any.
+func (f fleetAPI) findEnrollmentAPIKey(ctx context.Context, policyID string) (EnrollmentAPIKey, error) {
+	var keys enrollmentAPIKeyList
+	if err := f.request(ctx, http.MethodGet, "enrollment-api-keys?perPage=100", nil, &keys); err != nil {
+		return EnrollmentAPIKey{}, err
+	}
+	// older Kibana versions return the tokens in the deprecated list attribute
+	for _, key := range append(keys.Items, keys.List...) {
+		if key.Active && key.PolicyID == policyID {
+			// the list API does not return the token value
+			return f.getEnrollmentAPIKey(ctx, key.ID)
+		}
+	}
+	return EnrollmentAPIKey{}, nil
+}
+
+// reconcileEnrollmentToken ensures an active Fleet enrollment token for the default policy of the Agent
+// is stored in a Secret referenced by the Agent Pods. An existing token is reused as long as it remains active.
+func reconcileEnrollmentToken(params Params) (EnrollmentAPIKey, error) {
+	defer tracing.Span(&params.Context)()
+	agent := params.Agent
+
+	api, err := newFleetAPI(params)
+	if err != nil {
+		return EnrollmentAPIKey{}, err
+	}
+	defer api.client.CloseIdleConnections()
+
+	token, err := existingEnrollmentToken(params, api)
+	if err != nil {
+		return EnrollmentAPIKey{}, err
+	}
+
+	if token.isEmpty() {
+		params.Logger().Info("Fetching Fleet enrollment token", "namespace", agent.Namespace, "agent_name", agent.Name)
+		if token, err = fetchEnrollmentToken(params.Context, api, agent.Spec.FleetServerEnabled); err != nil {
+			return EnrollmentAPIKey{}, err
+		}
+	}
+
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: agent.Namespace,
+			Name:      EnrollmentTokenSecretName(agent.Name),
+			Labels:    common.AddCredentialsLabel(NewLabels(agent)),
+			Annotations: map[string]string{
+				FleetTokenIDAnnotationName:  token.ID,
+				FleetPolicyIDAnnotationName: token.PolicyID,
+			},
+		},
+		Data: map[string][]byte{
+			EnrollmentTokenKey: []byte(token.APIKey),
+		},
+	}
+
+	if _, err := reconciler.ReconcileSecret(params.Client, expected, &params.Agent); err != nil {
+		return EnrollmentAPIKey{}, err
+	}
+	return token, nil
+}
+
+// existingEnrollmentToken returns the enrollment token stored in the enrollment token Secret if it is still active,
+// or an empty token otherwise.
+func existingEnrollmentToken(params Params, api fleetAPI) (EnrollmentAPIKey, error) {
+	var secret corev1.Secret
+	nsn := types.NamespacedName{Namespace: params.Agent.Namespace, Name: EnrollmentTokenSecretName(params.Agent.Name)}
+	if err := params.Client.Get(context.Background(), nsn, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return EnrollmentAPIKey{}, nil
+		}
+		return EnrollmentAPIKey{}, err
+	}
+
+	tokenID, exists := secret.Annotations[FleetTokenIDAnnotationName]
+	if !exists || tokenID == "" {
+		return EnrollmentAPIKey{}, nil
+	}
+
+	token, err := api.getEnrollmentAPIKey(params.Context, tokenID)
+	if err != nil {
+		if isNotFound(err) {
+			// the token has been deleted, a new one must be fetched
+			return EnrollmentAPIKey{}, nil
+		}
+		return EnrollmentAPIKey{}, err
+	}
+	if !token.Active {
+		return EnrollmentAPIKey{}, nil
+	}
+	return token, nil
+}
+
+// fetchEnrollmentToken sets up Fleet and returns an active enrollment token for the default policy,
+// creating a new token if none exists.
+func fetchEnrollmentToken(ctx context.Context, api fleetAPI, fleetServer bool) (EnrollmentAPIKey, error) {
+	if err := api.setupFleet(ctx); err != nil {
+		return EnrollmentAPIKey{}, err
+	}
+
+	policyID, err := api.defaultPolicyID(ctx, fleetServer)
+	if err != nil {
+		return EnrollmentAPIKey{}, err
+	}
+
+	token, err := api.findEnrollmentAPIKey(ctx, policyID)
+	if err != nil || !token.isEmpty() {
+		return token, err
+	}
+
+	return api.createEnrollmentAPIKey(ctx, policyID)
+}
@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package agent
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+)
+
+// reconcileFleetServer reconciles the Service and the HTTP certificates of Fleet Server, if enabled.
+// The returned certificates are nil if Fleet Server is not enabled.
+func reconcileFleetServer(params Params) (*certificates.CertificatesSecret, *reconciler.Results) {
+	defer tracing.Span(&params.Context)()
+	results := reconciler.NewResult(params.Context)
+
+	if !params.Agent.Spec.FleetServerEnabled {
+		return nil, results
+	}
+
+	for _, assoc := range esAssociations(params.Agent) {
+		if assoc.AssociationConf().AuthIsAPIKey() {
+			return nil, results.WithError(errors.New(
+				"a username and a password are required for Fleet Server to connect to Elasticsearch, API keys are not supported",
+			))
+		}
+	}
+
+	svc, err := common.ReconcileService(params.Context, params.Client, NewService(params.Agent), &params.Agent)
+	if err != nil {
+		return nil, results.WithError(err)
+	}
+
+	fleetCerts, res := certificates.Reconciler{
+		K8sClient:             params.Client,
+		DynamicWatches:        params.Watches,
+		Owner:                 &params.Agent,
+		TLSOptions:            params.Agent.Spec.HTTP.TLS,
+		Namer:                 Namer,
+		Labels:                NewLabels(params.Agent),
+		Services:              []corev1.Service{*svc},
+		CACertRotation:        params.OperatorParams.CACertRotation,
+		CertRotation:          params.OperatorParams.CertRotation,
+		PrivateKeyOptions:     params.OperatorParams.PrivateKeyOptions,
+		GarbageCollectSecrets: true,
+	}.ReconcileCAAndHTTPCerts(params.Context)
+
+	return fleetCerts, results.WithResults(res)
+}
+
+// NewService returns the Service exposing Fleet Server.
+func NewService(agent agentv1alpha1.Agent) *corev1.Service {
+	svc := corev1.Service{
+		ObjectMeta: agent.Spec.HTTP.Service.ObjectMeta,
+		Spec:       agent.Spec.HTTP.Service.Spec,
+	}
+
+	svc.ObjectMeta.Namespace = agent.Namespace
+	svc.ObjectMeta.Name = HTTPServiceName(agent.Name)
+
+	labels := NewLabels(agent)
+	ports := []corev1.ServicePort{
+		{
+			Name:     agent.Spec.HTTP.Protocol(),
+			Protocol: corev1.ProtocolTCP,
+			Port:     FleetServerPort,
+		},
+	}
+
+	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+}
@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	controllerscheme "github.com/elastic/cloud-on-k8s/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func Test_reconcileFleetServer(t *testing.T) {
+	controllerscheme.SetupScheme()
+
+	rotation := certificates.RotationParams{Validity: certificates.DefaultCertValidity, RotateBefore: certificates.DefaultRotateBefore}
+	newParams := func(fleetServerEnabled bool, esConf commonv1.AssociationConf) Params {
+		agent := agentv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent"},
+			Spec: agentv1alpha1.AgentSpec{
+				Mode:               agentv1alpha1.AgentFleetMode,
+				FleetServerEnabled: fleetServerEnabled,
+				ElasticsearchRefs:  []agentv1alpha1.Output{{ObjectSelector: commonv1.ObjectSelector{Name: "es"}}},
+			},
+		}
+		agent.GetAssociations()[0].SetAssociationConf(&esConf)
+		return Params{
+			Context: context.Background(),
+			Client:  k8s.NewFakeClient(),
+			Watches: watches.NewDynamicWatches(),
+			Agent:   agent,
+			OperatorParams: operator.Parameters{
+				CACertRotation:    rotation,
+				CertRotation:      rotation,
+				PrivateKeyOptions: certificates.DefaultPrivateKeyOptions,
+			},
+		}
+	}
+	userConf := commonv1.AssociationConf{AuthSecretName: "agent-es-user", AuthSecretKey: "ns-agent-agent-es-user"}
+
+	t.Run("Fleet Server disabled", func(t *testing.T) {
+		params := newParams(false, userConf)
+		certs, results := reconcileFleetServer(params)
+		require.Nil(t, certs)
+		reconciled, _ := results.IsReconciled()
+		require.True(t, reconciled)
+
+		var svc corev1.Service
+		err := params.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: HTTPServiceName("agent")}, &svc)
+		require.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("API keys are not supported", func(t *testing.T) {
+		params := newParams(true, commonv1.AssociationConf{AuthSecretName: "agent-es-api-key", AuthSecretKey: "api-key", AuthAPIKey: true})
+		certs, results := reconcileFleetServer(params)
+		require.Nil(t, certs)
+		_, err := results.Aggregate()
+		require.Error(t, err)
+	})
+
+	t.Run("Fleet Server enabled", func(t *testing.T) {
+		params := newParams(true, userConf)
+		certs, results := reconcileFleetServer(params)
+		_, err := results.Aggregate()
+		require.NoError(t, err)
+		require.NotNil(t, certs)
+		require.NotEmpty(t, certs.Data[certificates.CertFileName])
+
+		var svc corev1.Service
+		err = params.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: HTTPServiceName("agent")}, &svc)
+		require.NoError(t, err)
+		require.Len(t, svc.Spec.Ports, 1)
+		require.Equal(t, corev1.ServicePort{Name: "https", Protocol: corev1.ProtocolTCP, Port: FleetServerPort}, svc.Spec.Ports[0])
+	})
+}
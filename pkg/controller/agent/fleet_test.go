@@ -0,0 +1,374 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	controllerscheme "github.com/elastic/cloud-on-k8s/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	testKibanaUser     = "ns-agent-agent-kb-user"
+	testKibanaPassword = "kb-password"
+
+	defaultPolicyID            = "default-policy"
+	defaultFleetServerPolicyID = "default-fleet-server-policy"
+)
+
+// fakeFleetServer is an in-memory implementation of the subset of the Kibana Fleet API used by the operator.
+type fakeFleetServer struct {
+	mu sync.Mutex
+
+	policies []agentPolicy
+	keys     []EnrollmentAPIKey
+	// failures holds the status code to respond with for a given method and path
+	failures map[string]int
+	// requests counts the requests received for a given method and path
+	requests map[string]int
+}
+
+func newFakeFleetServer(keys ...EnrollmentAPIKey) *fakeFleetServer {
+	return &fakeFleetServer{
+		policies: []agentPolicy{
+			{ID: defaultPolicyID, IsDefault: true},
+			{ID: defaultFleetServerPolicyID, IsDefaultFleetServer: true},
+		},
+		keys:     keys,
+		failures: map[string]int{},
+		requests: map[string]int{},
+	}
+}
+
+func (f *fakeFleetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/fleet/")
+	route := r.Method + " " + path
+	f.requests[route]++
+
+	if username, password, ok := r.BasicAuth(); !ok || username != testKibanaUser || password != testKibanaPassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("kbn-xsrf") != "true" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if statusCode, failing := f.failures[route]; failing {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte("failure"))
+		return
+	}
+
+	switch {
+	case route == "POST setup":
+		f.write(w, map[string]bool{"isInitialized": true})
+	case route == "GET agent_policies":
+		f.write(w, agentPolicyList{Items: f.policies})
+	case route == "GET enrollment-api-keys":
+		// the list API does not return the token value
+		items := make([]EnrollmentAPIKey, len(f.keys))
+		for i, key := range f.keys {
+			key.APIKey = ""
+			items[i] = key
+		}
+		f.write(w, enrollmentAPIKeyList{Items: items})
+	case route == "POST enrollment-api-keys":
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		key := EnrollmentAPIKey{
+			ID:       fmt.Sprintf("created-%d", len(f.keys)),
+			Active:   true,
+			APIKey:   fmt.Sprintf("created-api-key-%d", len(f.keys)),
+			PolicyID: body["policy_id"],
+		}
+		f.keys = append(f.keys, key)
+		f.write(w, enrollmentAPIKeyResult{Item: key})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "enrollment-api-keys/"):
+		for _, key := range f.keys {
+			if key.ID == strings.TrimPrefix(path, "enrollment-api-keys/") {
+				f.write(w, enrollmentAPIKeyResult{Item: key})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeFleetServer) write(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (f *fakeFleetServer) requestCount(route string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[route]
+}
+
+// startFleetAPI starts an HTTP server backed by the given fake and returns a client for it.
+func startFleetAPI(t *testing.T, fake *fakeFleetServer) (fleetAPI, string) {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fleetAPI{
+		client:   server.Client(),
+		endpoint: server.URL,
+		username: testKibanaUser,
+		password: testKibanaPassword,
+	}, server.URL
+}
+
+func Test_fleetAPI_request(t *testing.T) {
+	fake := newFakeFleetServer()
+	fake.failures["GET agent_policies"] = http.StatusInternalServerError
+	api, _ := startFleetAPI(t, fake)
+
+	// successful request with a JSON body
+	key, err := api.createEnrollmentAPIKey(context.Background(), defaultPolicyID)
+	require.NoError(t, err)
+	require.Equal(t, defaultPolicyID, key.PolicyID)
+	require.Equal(t, "created-api-key-0", key.APIKey)
+
+	// unexpected status code
+	_, err = api.defaultPolicyID(context.Background(), false)
+	require.EqualError(t, err, "invalid Fleet API response (status code 500): failure")
+	require.False(t, isNotFound(err))
+
+	// not found
+	_, err = api.getEnrollmentAPIKey(context.Background(), "unknown")
+	require.Error(t, err)
+	require.True(t, isNotFound(err))
+
+	// invalid credentials
+	api.password = "wrong"
+	err = api.setupFleet(context.Background())
+	require.EqualError(t, err, "invalid Fleet API response (status code 401): ")
+}
+
+func Test_fetchEnrollmentToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		fake        func() *fakeFleetServer
+		fleetServer bool
+		want        EnrollmentAPIKey
+		wantCreated bool
+		wantErr     bool
+	}{
+		{
+			name: "reuse the active token of the default policy",
+			fake: func() *fakeFleetServer {
+				return newFakeFleetServer(
+					EnrollmentAPIKey{ID: "other", Active: true, APIKey: "other-api-key", PolicyID: "other-policy"},
+					EnrollmentAPIKey{ID: "default", Active: true, APIKey: "default-api-key", PolicyID: defaultPolicyID},
+				)
+			},
+			want: EnrollmentAPIKey{ID: "default", Active: true, APIKey: "default-api-key", PolicyID: defaultPolicyID},
+		},
+		{
+			name: "reuse the active token of the default Fleet Server policy",
+			fake: func() *fakeFleetServer {
+				return newFakeFleetServer(
+					EnrollmentAPIKey{ID: "default", Active: true, APIKey: "default-api-key", PolicyID: defaultPolicyID},
+					EnrollmentAPIKey{ID: "fleet-server", Active: true, APIKey: "fleet-server-api-key", PolicyID: defaultFleetServerPolicyID},
+				)
+			},
+			fleetServer: true,
+			want:        EnrollmentAPIKey{ID: "fleet-server", Active: true, APIKey: "fleet-server-api-key", PolicyID: defaultFleetServerPolicyID},
+		},
+		{
+			name: "create a token if the token of the default policy is inactive",
+			fake: func() *fakeFleetServer {
+				return newFakeFleetServer(
+					EnrollmentAPIKey{ID: "default", Active: false, APIKey: "default-api-key", PolicyID: defaultPolicyID},
+				)
+			},
+			want:        EnrollmentAPIKey{ID: "created-1", Active: true, APIKey: "created-api-key-1", PolicyID: defaultPolicyID},
+			wantCreated: true,
+		},
+		{
+			name:        "create a token if none exists",
+			fake:        func() *fakeFleetServer { return newFakeFleetServer() },
+			fleetServer: true,
+			want:        EnrollmentAPIKey{ID: "created-0", Active: true, APIKey: "created-api-key-0", PolicyID: defaultFleetServerPolicyID},
+			wantCreated: true,
+		},
+		{
+			name: "no default policy",
+			fake: func() *fakeFleetServer {
+				fake := newFakeFleetServer()
+				fake.policies = []agentPolicy{{ID: "other-policy"}}
+				return fake
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fleet setup failure",
+			fake: func() *fakeFleetServer {
+				fake := newFakeFleetServer()
+				fake.failures["POST setup"] = http.StatusInternalServerError
+				return fake
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := tt.fake()
+			api, _ := startFleetAPI(t, fake)
+
+			got, err := fetchEnrollmentToken(context.Background(), api, tt.fleetServer)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, 1, fake.requestCount("POST setup"))
+			wantCreations := 0
+			if tt.wantCreated {
+				wantCreations = 1
+			}
+			require.Equal(t, wantCreations, fake.requestCount("POST enrollment-api-keys"))
+		})
+	}
+}
+
+func Test_reconcileEnrollmentToken(t *testing.T) {
+	controllerscheme.SetupScheme()
+
+	authSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent-kb-user"},
+		Data:       map[string][]byte{testKibanaUser: []byte(testKibanaPassword)},
+	}
+	tokenSecret := func(tokenID string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        EnrollmentTokenSecretName("agent"),
+				Annotations: map[string]string{FleetTokenIDAnnotationName: tokenID},
+			},
+			Data: map[string][]byte{EnrollmentTokenKey: []byte("previous-api-key")},
+		}
+	}
+	defaultKey := EnrollmentAPIKey{ID: "default", Active: true, APIKey: "default-api-key", PolicyID: defaultPolicyID}
+	createdKey := EnrollmentAPIKey{ID: "created-1", Active: true, APIKey: "created-api-key-1", PolicyID: defaultPolicyID}
+
+	tests := []struct {
+		name          string
+		existing      *corev1.Secret
+		keys          []EnrollmentAPIKey
+		want          EnrollmentAPIKey
+		wantFleetCall bool
+	}{
+		{
+			name:          "no enrollment token secret",
+			keys:          []EnrollmentAPIKey{defaultKey},
+			want:          defaultKey,
+			wantFleetCall: true,
+		},
+		{
+			name:          "enrollment token secret without token ID",
+			existing:      tokenSecret(""),
+			keys:          []EnrollmentAPIKey{defaultKey},
+			want:          defaultKey,
+			wantFleetCall: true,
+		},
+		{
+			name:     "reuse the active token of the enrollment token secret",
+			existing: tokenSecret("previous"),
+			keys: []EnrollmentAPIKey{
+				defaultKey,
+				{ID: "previous", Active: true, APIKey: "previous-api-key", PolicyID: "previous-policy"},
+			},
+			want: EnrollmentAPIKey{ID: "previous", Active: true, APIKey: "previous-api-key", PolicyID: "previous-policy"},
+		},
+		{
+			name:     "replace the inactive token of the enrollment token secret",
+			existing: tokenSecret("previous"),
+			keys: []EnrollmentAPIKey{
+				{ID: "previous", Active: false, APIKey: "previous-api-key", PolicyID: defaultPolicyID},
+			},
+			want:          createdKey,
+			wantFleetCall: true,
+		},
+		{
+			name:          "replace the deleted token of the enrollment token secret",
+			existing:      tokenSecret("deleted"),
+			keys:          []EnrollmentAPIKey{defaultKey},
+			want:          defaultKey,
+			wantFleetCall: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeFleetServer(tt.keys...)
+			_, url := startFleetAPI(t, fake)
+
+			agent := agentv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent"},
+				Spec: agentv1alpha1.AgentSpec{
+					Mode:      agentv1alpha1.AgentFleetMode,
+					KibanaRef: commonv1.ObjectSelector{Name: "kb"},
+				},
+			}
+			agent.KibanaAssociation().SetAssociationConf(&commonv1.AssociationConf{
+				AuthSecretName: authSecret.Name,
+				AuthSecretKey:  testKibanaUser,
+				URL:            url,
+			})
+			objects := []runtime.Object{authSecret.DeepCopy()}
+			if tt.existing != nil {
+				objects = append(objects, tt.existing.DeepCopy())
+			}
+			params := Params{
+				Context: context.Background(),
+				Client:  k8s.NewFakeClient(objects...),
+				Agent:   agent,
+			}
+
+			got, err := reconcileEnrollmentToken(params)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			wantSetupCalls := 0
+			if tt.wantFleetCall {
+				wantSetupCalls = 1
+			}
+			require.Equal(t, wantSetupCalls, fake.requestCount("POST setup"))
+
+			var secret corev1.Secret
+			nsn := types.NamespacedName{Namespace: "ns", Name: EnrollmentTokenSecretName("agent")}
+			require.NoError(t, params.Client.Get(context.Background(), nsn, &secret))
+			require.Equal(t, tt.want.APIKey, string(secret.Data[EnrollmentTokenKey]))
+			require.Equal(t, tt.want.ID, secret.Annotations[FleetTokenIDAnnotationName])
+			require.Equal(t, tt.want.PolicyID, secret.Annotations[FleetPolicyIDAnnotationName])
+			require.Len(t, secret.OwnerReferences, 1)
+			require.Equal(t, "agent", secret.OwnerReferences[0].Name)
+		})
+	}
+}
@@ -13,7 +13,8 @@ const (
 	// Type represents the Agent type.
 	TypeLabelValue = "agent"
 
-	NameLabelName = "agent.k8s.elastic.co/name"
+	NameLabelName      = "agent.k8s.elastic.co/name"
+	NamespaceLabelName = "agent.k8s.elastic.co/namespace"
 )
 
 func NewLabels(agent agentv1alpha1.Agent) map[string]string {
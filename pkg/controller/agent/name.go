@@ -6,13 +6,23 @@ package agent
 
 import common_name "github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
 
-// namer is a Namer that is configured with the defaults for resources related to an Agent resource.
-var namer = common_name.NewNamer("agent")
+// Namer is a Namer that is configured with the defaults for resources related to an Agent resource.
+var Namer = common_name.NewNamer("agent")
 
 func ConfigSecretName(name string) string {
-	return namer.Suffix(name, "config")
+	return Namer.Suffix(name, "config")
 }
 
 func Name(name string) string {
-	return namer.Suffix(name)
+	return Namer.Suffix(name)
+}
+
+// HTTPServiceName returns the name of the service exposing Fleet Server.
+func HTTPServiceName(name string) string {
+	return Namer.Suffix(name, "http")
+}
+
+// EnrollmentTokenSecretName returns the name of the secret holding the Fleet enrollment token.
+func EnrollmentTokenSecretName(name string) string {
+	return Namer.Suffix(name, "envvars")
 }
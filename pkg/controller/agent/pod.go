@@ -7,16 +7,20 @@ package agent
 import (
 	"fmt"
 	"hash"
+	"path"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
 )
 
 const (
@@ -37,6 +41,22 @@ const (
 
 	// VersionLabelName is a label used to track the version of a Agent Pod.
 	VersionLabelName = "agent.k8s.elastic.co/version"
+
+	// environment variables used to enroll the Agent into Fleet
+	FleetEnroll                      = "FLEET_ENROLL"
+	FleetEnrollmentToken             = "FLEET_ENROLLMENT_TOKEN"
+	FleetURL                         = "FLEET_URL"
+	FleetCA                          = "FLEET_CA"
+	FleetServerEnable                = "FLEET_SERVER_ENABLE"
+	FleetServerPolicyID              = "FLEET_SERVER_POLICY_ID"
+	FleetServerCert                  = "FLEET_SERVER_CERT"
+	FleetServerCertKey               = "FLEET_SERVER_CERT_KEY"
+	FleetServerInsecureHTTP          = "FLEET_SERVER_INSECURE_HTTP"
+	FleetServerElasticsearchHost     = "FLEET_SERVER_ELASTICSEARCH_HOST"
+	FleetServerElasticsearchUsername = "FLEET_SERVER_ELASTICSEARCH_USERNAME"
+	FleetServerElasticsearchPassword = "FLEET_SERVER_ELASTICSEARCH_PASSWORD" //nolint:gosec
+	FleetServerElasticsearchCA       = "FLEET_SERVER_ELASTICSEARCH_CA"
+	FleetServerServiceToken          = "FLEET_SERVER_SERVICE_TOKEN" //nolint:gosec
 )
 
 var (
@@ -52,7 +72,7 @@ var (
 	}
 )
 
-func buildPodTemplate(params Params, configHash hash.Hash) corev1.PodTemplateSpec {
+func buildPodTemplate(params Params, fleetToken EnrollmentAPIKey, configHash hash.Hash) corev1.PodTemplateSpec {
 	defer tracing.Span(&params.Context)()
 
 	podTemplate := params.GetPodTemplate()
@@ -64,14 +84,18 @@ func buildPodTemplate(params Params, configHash hash.Hash) corev1.PodTemplateSpe
 		VersionLabelName:    spec.Version})
 
 	dataVolume := createDataVolume(params)
-	vols := []volume.VolumeLike{
-		volume.NewSecretVolume(
+	vols := []volume.VolumeLike{dataVolume}
+	if params.Agent.StandaloneModeEnabled() {
+		vols = append(vols, volume.NewSecretVolume(
 			ConfigSecretName(params.Agent.Name),
 			ConfigVolumeName,
 			ConfigMountPath,
 			ConfigFileName,
-			0440),
-		dataVolume,
+			0440))
+	}
+
+	if spec.FleetServerEnabled && spec.HTTP.TLS.Enabled() {
+		vols = append(vols, certificates.HTTPCertSecretVolume(Namer, params.Agent.Name))
 	}
 
 	for i, association := range params.Agent.GetAssociations() {
@@ -100,15 +124,101 @@ func buildPodTemplate(params Params, configHash hash.Hash) corev1.PodTemplateSpe
 		WithLabels(labels).
 		WithResources(defaultResources).
 		WithDockerImage(spec.Image, container.ImageRepository(container.AgentImage, spec.Version)).
-		WithArgs("-e", "-c", ConfigMountPath).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithInitContainers(initContainers...).
 		WithInitContainerDefaults()
 
+	if params.Agent.FleetModeEnabled() {
+		// the Agent enrolls into Fleet at startup, configured through environment variables
+		builder = builder.WithEnv(fleetEnvVars(params, fleetToken)...)
+	} else {
+		builder = builder.WithArgs("-e", "-c", ConfigMountPath)
+	}
+
+	if spec.FleetServerEnabled {
+		builder = builder.WithPorts([]corev1.ContainerPort{
+			{Name: spec.HTTP.Protocol(), ContainerPort: int32(FleetServerPort), Protocol: corev1.ProtocolTCP},
+		})
+	}
+
 	return builder.PodTemplate
 }
 
+// fleetEnvVars returns the environment variables used by the Agent container to enroll into Fleet and,
+// if enabled, to run Fleet Server.
+func fleetEnvVars(params Params, fleetToken EnrollmentAPIKey) []corev1.EnvVar {
+	agent := params.Agent
+	envVars := []corev1.EnvVar{
+		{Name: FleetEnroll, Value: "true"},
+		{
+			Name: FleetEnrollmentToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: EnrollmentTokenSecretName(agent.Name)},
+					Key:                  EnrollmentTokenKey,
+				},
+			},
+		},
+	}
+
+	if !agent.Spec.FleetServerEnabled {
+		// enroll into the referenced Fleet Server
+		fsAssociation := agent.FleetServerAssociation()
+		envVars = append(envVars, corev1.EnvVar{Name: FleetURL, Value: fsAssociation.AssociationConf().GetURL()})
+		if fsAssociation.AssociationConf().GetCACertProvided() {
+			envVars = append(envVars, corev1.EnvVar{Name: FleetCA, Value: path.Join(certificatesDir(fsAssociation), CAFileName)})
+		}
+		return envVars
+	}
+
+	// run Fleet Server and enroll into it
+	envVars = append(envVars,
+		corev1.EnvVar{Name: FleetServerEnable, Value: "true"},
+		corev1.EnvVar{Name: FleetServerPolicyID, Value: fleetToken.PolicyID},
+		corev1.EnvVar{
+			Name: FleetURL,
+			Value: stringsutil.Concat(
+				agent.Spec.HTTP.Protocol(), "://", HTTPServiceName(agent.Name), ".", agent.Namespace, ".svc:", strconv.Itoa(FleetServerPort),
+			),
+		},
+	)
+	if agent.Spec.HTTP.TLS.Enabled() {
+		envVars = append(envVars,
+			corev1.EnvVar{Name: FleetCA, Value: path.Join(certificates.HTTPCertificatesSecretVolumeMountPath, certificates.CAFileName)},
+			corev1.EnvVar{Name: FleetServerCert, Value: path.Join(certificates.HTTPCertificatesSecretVolumeMountPath, certificates.CertFileName)},
+			corev1.EnvVar{Name: FleetServerCertKey, Value: path.Join(certificates.HTTPCertificatesSecretVolumeMountPath, certificates.KeyFileName)},
+		)
+	} else {
+		envVars = append(envVars, corev1.EnvVar{Name: FleetServerInsecureHTTP, Value: "true"})
+	}
+
+	// Fleet Server connects to the single referenced Elasticsearch cluster
+	for _, assoc := range esAssociations(agent) {
+		assocConf := assoc.AssociationConf()
+		authSecretRef := &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: assocConf.AuthSecretName},
+				Key:                  assocConf.AuthSecretKey,
+			},
+		}
+		envVars = append(envVars, corev1.EnvVar{Name: FleetServerElasticsearchHost, Value: assocConf.GetURL()})
+		if assocConf.AuthIsServiceAccountToken() {
+			envVars = append(envVars, corev1.EnvVar{Name: FleetServerServiceToken, ValueFrom: authSecretRef})
+		} else {
+			envVars = append(envVars,
+				corev1.EnvVar{Name: FleetServerElasticsearchUsername, Value: assocConf.AuthSecretKey},
+				corev1.EnvVar{Name: FleetServerElasticsearchPassword, ValueFrom: authSecretRef},
+			)
+		}
+		if assocConf.GetCACertProvided() {
+			envVars = append(envVars, corev1.EnvVar{Name: FleetServerElasticsearchCA, Value: path.Join(certificatesDir(assoc), CAFileName)})
+		}
+	}
+
+	return envVars
+}
+
 func createDataVolume(params Params) volume.VolumeLike {
 	dataMountHostPath := fmt.Sprintf(DataMountHostPathTemplate, params.Agent.Namespace, params.Agent.Name)
 
@@ -126,6 +236,6 @@ func certificatesDir(association commonv1.Association) string {
 		"/mnt/elastic-internal/%s-association/%s/%s/certs",
 		association.AssociationType(),
 		ref.Namespace,
-		ref.Name,
+		ref.NameOrSecretName(),
 	)
 }
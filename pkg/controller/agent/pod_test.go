@@ -0,0 +1,150 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+)
+
+func Test_fleetEnvVars(t *testing.T) {
+	enrollmentTokenEnvVar := corev1.EnvVar{
+		Name: FleetEnrollmentToken,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "agent-agent-envvars"},
+				Key:                  EnrollmentTokenKey,
+			},
+		},
+	}
+	esAuthSecretRef := &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "agent-es-user"},
+			Key:                  "ns-agent-agent-es-user",
+		},
+	}
+	fleetServerToken := EnrollmentAPIKey{ID: "token", Active: true, APIKey: "api-key", PolicyID: "fleet-server-policy"}
+
+	agentWith := func(spec agentv1alpha1.AgentSpec, fsConf *commonv1.AssociationConf, esConf *commonv1.AssociationConf) agentv1alpha1.Agent {
+		spec.Mode = agentv1alpha1.AgentFleetMode
+		agent := agentv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent"},
+			Spec:       spec,
+		}
+		if fsConf != nil {
+			agent.FleetServerAssociation().SetAssociationConf(fsConf)
+		}
+		if esConf != nil {
+			agent.GetAssociations()[0].SetAssociationConf(esConf)
+		}
+		return agent
+	}
+	fleetServerSpec := func(http commonv1.HTTPConfig) agentv1alpha1.AgentSpec {
+		return agentv1alpha1.AgentSpec{
+			FleetServerEnabled: true,
+			HTTP:               http,
+			ElasticsearchRefs:  []agentv1alpha1.Output{{ObjectSelector: commonv1.ObjectSelector{Name: "es"}}},
+			KibanaRef:          commonv1.ObjectSelector{Name: "kb"},
+		}
+	}
+	tlsDisabled := commonv1.HTTPConfig{TLS: commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}}}
+
+	tests := []struct {
+		name  string
+		agent agentv1alpha1.Agent
+		want  []corev1.EnvVar
+	}{
+		{
+			name: "enroll into a referenced Fleet Server",
+			agent: agentWith(
+				agentv1alpha1.AgentSpec{FleetServerRef: commonv1.ObjectSelector{Name: "fs"}},
+				&commonv1.AssociationConf{URL: "https://fs-agent-http.ns.svc:8220"},
+				nil,
+			),
+			want: []corev1.EnvVar{
+				{Name: FleetEnroll, Value: "true"},
+				enrollmentTokenEnvVar,
+				{Name: FleetURL, Value: "https://fs-agent-http.ns.svc:8220"},
+			},
+		},
+		{
+			name: "enroll into a referenced Fleet Server with a CA",
+			agent: agentWith(
+				agentv1alpha1.AgentSpec{FleetServerRef: commonv1.ObjectSelector{Name: "fs"}},
+				&commonv1.AssociationConf{URL: "https://fs-agent-http.ns.svc:8220", CACertProvided: true, CASecretName: "agent-fs-ca"},
+				nil,
+			),
+			want: []corev1.EnvVar{
+				{Name: FleetEnroll, Value: "true"},
+				enrollmentTokenEnvVar,
+				{Name: FleetURL, Value: "https://fs-agent-http.ns.svc:8220"},
+				{Name: FleetCA, Value: "/mnt/elastic-internal/fleet-server-association/ns/fs/certs/ca.crt"},
+			},
+		},
+		{
+			name: "run Fleet Server with TLS, authenticating with a user",
+			agent: agentWith(
+				fleetServerSpec(commonv1.HTTPConfig{}),
+				nil,
+				&commonv1.AssociationConf{
+					AuthSecretName: "agent-es-user",
+					AuthSecretKey:  "ns-agent-agent-es-user",
+					URL:            "https://es-es-http.ns.svc:9200",
+					CACertProvided: true,
+					CASecretName:   "agent-es-ca",
+				},
+			),
+			want: []corev1.EnvVar{
+				{Name: FleetEnroll, Value: "true"},
+				enrollmentTokenEnvVar,
+				{Name: FleetServerEnable, Value: "true"},
+				{Name: FleetServerPolicyID, Value: "fleet-server-policy"},
+				{Name: FleetURL, Value: "https://agent-agent-http.ns.svc:8220"},
+				{Name: FleetCA, Value: "/mnt/elastic-internal/http-certs/ca.crt"},
+				{Name: FleetServerCert, Value: "/mnt/elastic-internal/http-certs/tls.crt"},
+				{Name: FleetServerCertKey, Value: "/mnt/elastic-internal/http-certs/tls.key"},
+				{Name: FleetServerElasticsearchHost, Value: "https://es-es-http.ns.svc:9200"},
+				{Name: FleetServerElasticsearchUsername, Value: "ns-agent-agent-es-user"},
+				{Name: FleetServerElasticsearchPassword, ValueFrom: esAuthSecretRef},
+				{Name: FleetServerElasticsearchCA, Value: "/mnt/elastic-internal/elasticsearch-association/ns/es/certs/ca.crt"},
+			},
+		},
+		{
+			name: "run Fleet Server without TLS, authenticating with a service account token",
+			agent: agentWith(
+				fleetServerSpec(tlsDisabled),
+				nil,
+				&commonv1.AssociationConf{
+					AuthSecretName:          "agent-es-user",
+					AuthSecretKey:           "ns-agent-agent-es-user",
+					AuthServiceAccountToken: true,
+					URL:                     "http://es-es-http.ns.svc:9200",
+				},
+			),
+			want: []corev1.EnvVar{
+				{Name: FleetEnroll, Value: "true"},
+				enrollmentTokenEnvVar,
+				{Name: FleetServerEnable, Value: "true"},
+				{Name: FleetServerPolicyID, Value: "fleet-server-policy"},
+				{Name: FleetURL, Value: "http://agent-agent-http.ns.svc:8220"},
+				{Name: FleetServerInsecureHTTP, Value: "true"},
+				{Name: FleetServerElasticsearchHost, Value: "http://es-es-http.ns.svc:9200"},
+				{Name: FleetServerServiceToken, ValueFrom: esAuthSecretRef},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fleetEnvVars(Params{Agent: tt.agent}, fleetServerToken)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
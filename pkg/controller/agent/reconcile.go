@@ -74,7 +74,7 @@ func reconcilePodVehicle(params Params, podTemplate corev1.PodTemplateSpec) *rec
 		results.WithError(err)
 	}
 
-	err = updateStatus(params, ready, desired)
+	err = updateStatus(params, ready, desired, results)
 	if err != nil && apierrors.IsConflict(err) {
 		params.Logger().V(1).Info("Conflict while updating status")
 		return results.WithResult(reconcile.Result{Requeue: true})
@@ -133,7 +133,7 @@ type ReconciliationParams struct {
 	podTemplate corev1.PodTemplateSpec
 }
 
-func updateStatus(params Params, ready, desired int32) error {
+func updateStatus(params Params, ready, desired int32, results *reconciler.Results) error {
 	agent := params.Agent
 
 	pods, err := k8s.PodsMatchingLabels(params.Client, agent.Namespace, map[string]string{NameLabelName: agent.Name})
@@ -144,6 +144,8 @@ func updateStatus(params Params, ready, desired int32) error {
 	agent.Status.ExpectedNodes = desired
 	agent.Status.Health = CalculateHealth(agent.GetAssociations(), ready, desired)
 	agent.Status.Version = common.LowestVersionFromPods(agent.Status.Version, pods, VersionLabelName)
+	common.UpdateConditions(&agent.Status.Conditions, agent.Generation, results, agent.Status.Version, agent.Spec.Version)
+	agent.Status.ObservedGeneration = agent.Generation
 
 	return params.Client.Status().Update(context.Background(), &agent)
 }
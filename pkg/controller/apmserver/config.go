@@ -18,6 +18,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
@@ -95,6 +96,7 @@ func newConfigFromSpec(c k8s.Client, as *apmv1.ApmServer) (*settings.CanonicalCo
 		esConfig,
 		kibanaConfig,
 		settings.MustCanonicalConfig(tlsSettings(as)),
+		stackmon.LibbeatMonitoringConfig(as),
 		userSettings,
 	)
 	if err != nil {
@@ -109,16 +111,17 @@ func newElasticsearchConfigFromSpec(c k8s.Client, esAssociation apmv1.ApmEsAssoc
 		return settings.NewCanonicalConfig(), nil
 	}
 
-	// Get username and password
-	username, password, err := association.ElasticsearchAuthSettings(c, &esAssociation)
+	// Get username and password, or API key
+	credentials, err := association.ElasticsearchCredentials(c, &esAssociation)
 	if err != nil {
 		return nil, err
 	}
 
 	tmpOutputCfg := map[string]interface{}{
-		"output.elasticsearch.hosts":    []string{esAssociation.AssociationConf().GetURL()},
-		"output.elasticsearch.username": username,
-		"output.elasticsearch.password": password,
+		"output.elasticsearch.hosts": []string{esAssociation.AssociationConf().GetURL()},
+	}
+	for key, value := range credentials.OutputSettings() {
+		tmpOutputCfg["output.elasticsearch."+key] = value
 	}
 	if esAssociation.AssociationConf().GetCACertProvided() {
 		tmpOutputCfg["output.elasticsearch.ssl.certificate_authorities"] = []string{filepath.Join(certificatesDir(esAssociation.AssociationType()), certificates.CAFileName)}
@@ -249,6 +249,7 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, request reconcile.
 		Services:              []corev1.Service{*svc},
 		CACertRotation:        r.CACertRotation,
 		CertRotation:          r.CertRotation,
+		PrivateKeyOptions:     r.PrivateKeyOptions,
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
@@ -277,6 +278,8 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, request reconcile.
 	}
 
 	state.UpdateApmServerExternalService(*svc)
+	common.UpdateConditions(&state.ApmServer.Status.Conditions, as.Generation, results, state.ApmServer.Status.Version, as.Spec.Version)
+	state.ApmServer.Status.ObservedGeneration = as.Generation
 
 	// update status
 	err = r.updateStatus(ctx, state)
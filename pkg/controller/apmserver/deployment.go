@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/deployment"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
@@ -42,6 +43,10 @@ func (r *ReconcileApmServer) reconcileApmServerDeployment(
 		return state, err
 	}
 
+	if err := reconcileMonitoringConfigSecrets(r.Client, *as); err != nil {
+		return state, err
+	}
+
 	keystoreResources, err := keystore.NewResources(
 		r,
 		as,
@@ -88,7 +93,10 @@ func (r *ReconcileApmServer) deploymentParams(
 	params PodSpecParams,
 ) (deployment.Params, error) {
 
-	podSpec := newPodSpec(as, params)
+	podSpec, err := newPodSpec(r.Client, as, params)
+	if err != nil {
+		return deployment.Params{}, err
+	}
 
 	// Build a checksum of the configuration, the keystore, and the cert files used by ES and Kibana.
 	// The checksum is added to the pod labels so a change triggers a rolling update. This is done because Apm Server
@@ -100,6 +108,10 @@ func (r *ReconcileApmServer) deploymentParams(
 	}
 
 	for _, association := range as.GetAssociations() {
+		if association.AssociationType() == commonv1.EsMonitoringAssociationType {
+			// the monitoring CA is mounted in the Metricbeat sidecar
+			continue
+		}
 		if association.AssociationConf().CAIsConfigured() {
 			caSecretName := association.AssociationConf().GetCASecretName()
 
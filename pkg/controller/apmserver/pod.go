@@ -17,6 +17,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
 const (
@@ -84,7 +85,7 @@ type PodSpecParams struct {
 	keystoreResources *keystore.Resources
 }
 
-func newPodSpec(as *apmv1.ApmServer, p PodSpecParams) corev1.PodTemplateSpec {
+func newPodSpec(client k8s.Client, as *apmv1.ApmServer, p PodSpecParams) (corev1.PodTemplateSpec, error) {
 	labels := NewLabels(as.Name)
 	labels[APMVersionLabelName] = p.Version
 
@@ -133,7 +134,12 @@ func newPodSpec(as *apmv1.ApmServer, p PodSpecParams) corev1.PodTemplateSpec {
 		WithInitContainers(initContainers...).
 		WithInitContainerDefaults()
 
-	return builder.PodTemplate
+	builder, err := withMonitoring(client, builder, *as)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	return builder.PodTemplate, nil
 }
 
 func getDefaultContainerPorts(as apmv1.ApmServer) []corev1.ContainerPort {
@@ -15,8 +15,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewPodSpec(t *testing.T) {
@@ -123,7 +125,8 @@ func TestNewPodSpec(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := newPodSpec(&tt.as, tt.p)
+			got, err := newPodSpec(k8s.NewFakeClient(), &tt.as, tt.p)
+			require.NoError(t, err)
 			diff := deep.Equal(tt.want, got)
 			assert.Empty(t, diff)
 		})
@@ -206,7 +209,8 @@ func Test_newPodSpec_withInitContainers(t *testing.T) {
 				CustomImageName: tt.as.Spec.Image,
 				PodTemplate:     tt.as.Spec.PodTemplate,
 			}
-			got := newPodSpec(&tt.as, params)
+			got, err := newPodSpec(k8s.NewFakeClient(), &tt.as, params)
+			require.NoError(t, err)
 			tt.assertions(got)
 		})
 	}
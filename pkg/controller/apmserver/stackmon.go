@@ -0,0 +1,47 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package apmserver
+
+import (
+	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// monitoringSidecars returns the Beat sidecars to deploy in the APM Server Pods according to the monitoring spec.
+// Only the collection of metrics is supported, APM Server logs to stderr.
+func monitoringSidecars(client k8s.Client, as apmv1.ApmServer) ([]stackmon.BeatSidecar, error) {
+	if !monitoring.IsMetricsDefined(&as) {
+		return nil, nil
+	}
+	metricbeat, err := stackmon.NewLibbeatMetricBeatSidecar(client, &as, Namer, as.Spec.Version)
+	if err != nil {
+		return nil, err
+	}
+	return []stackmon.BeatSidecar{metricbeat}, nil
+}
+
+// reconcileMonitoringConfigSecrets reconciles the secrets holding the configuration of the Beat sidecars.
+func reconcileMonitoringConfigSecrets(client k8s.Client, as apmv1.ApmServer) error {
+	sidecars, err := monitoringSidecars(client, as)
+	if err != nil {
+		return err
+	}
+	return stackmon.ReconcileConfigSecrets(client, &as, NewLabels(as.Name), sidecars...)
+}
+
+// withMonitoring updates the APM Server Pod template builder to deploy Metricbeat in a sidecar container.
+func withMonitoring(client k8s.Client, builder *defaults.PodTemplateBuilder, as apmv1.ApmServer) (*defaults.PodTemplateBuilder, error) {
+	if !monitoring.IsDefined(&as) {
+		return builder, nil
+	}
+	sidecars, err := monitoringSidecars(client, as)
+	if err != nil {
+		return nil, err
+	}
+	return stackmon.WithMonitoring(builder, sidecars...), nil
+}
@@ -0,0 +1,219 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
+)
+
+const (
+	// ElasticsearchCredentialsAnnotation can be set on an associated resource to select the credentials used to connect
+	// to the Elasticsearch clusters it is associated with. If set to APIKeyCredentials, an API key is created for each
+	// association instead of a user of the file realm. It is ignored by the associated resources which do not support
+	// API keys.
+	ElasticsearchCredentialsAnnotation = "association.k8s.elastic.co/es-credentials"
+	// APIKeyCredentials is the value of ElasticsearchCredentialsAnnotation selecting API keys.
+	APIKeyCredentials = "api-key"
+
+	// apiKeyExpirationAnnotation is set on the secret holding an API key in the namespace of the associated resource,
+	// to the expiration time of the API key.
+	apiKeyExpirationAnnotation = "association.k8s.elastic.co/api-key-expiration"
+
+	// DefaultAPIKeyValidity is the default validity period of the API keys created for associations.
+	DefaultAPIKeyValidity = 30 * 24 * time.Hour
+	// DefaultAPIKeyRotateBefore defines how long before expiration an API key is replaced by a new one.
+	DefaultAPIKeyRotateBefore = 24 * time.Hour
+)
+
+// esClientProvider returns a client for an Elasticsearch cluster managed by the operator.
+type esClientProvider func(ctx context.Context, c k8s.Client, dialer net.Dialer, es esv1.Elasticsearch) (esclient.Client, error)
+
+// usesAPIKey returns true if the associated resource connects to Elasticsearch with an API key.
+func (r *Reconciler) usesAPIKey(association commonv1.Association) bool {
+	return r.ElasticsearchUserCreation != nil && r.ElasticsearchUserCreation.APIKeySupported &&
+		association.Associated().GetAnnotations()[ElasticsearchCredentialsAnnotation] == APIKeyCredentials
+}
+
+// apiKeyLabelSelector returns labels selecting the secrets which keep track of the API keys of an association.
+func (a AssociationInfo) apiKeyLabelSelector(
+	associated types.NamespacedName,
+	association types.NamespacedName,
+) client.MatchingLabels {
+	return maps.Merge(
+		map[string]string{common.TypeLabelName: esuser.AssociatedAPIKeyType},
+		a.AssociationResourceLabels(associated, association),
+	)
+}
+
+// reconcileAPIKey creates an API key for the association, with the privileges of the given comma separated roles, and
+// stores it in the association secret in the namespace of the associated resource. The API key is replaced by a new
+// one when it is about to expire, the previous one remains valid until its expiration. A secret in the namespace of
+// the Elasticsearch cluster, named after the API keys, keeps track of them so that they can be invalidated when the
+// association is removed. It returns the duration after which the API key must be rotated.
+func (r *Reconciler) reconcileAPIKey(
+	ctx context.Context,
+	association commonv1.Association,
+	labels map[string]string,
+	userRoles string,
+	es esv1.Elasticsearch,
+) (time.Duration, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_api_key", tracing.SpanTypeApp)
+	defer span.End()
+
+	// Add the Elasticsearch name, this is only intended to help the user to filter on these resources
+	labels[eslabel.ClusterNameLabelName] = es.Name
+
+	secKey := secretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix)
+	usrKey := UserKey(association, es.Namespace, r.ElasticsearchUserCreation.UserSecretSuffix)
+
+	// the secret tracking the API keys replaces the file realm user the association may have used before
+	apiKeySecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      usrKey.Name,
+			Namespace: usrKey.Namespace,
+			Labels:    maps.Merge(map[string]string{common.TypeLabelName: esuser.AssociatedAPIKeyType}, labels),
+		},
+		Data: map[string][]byte{
+			esuser.UserNameField: []byte(usrKey.Name),
+		},
+	}
+	owner := es // API keys are owned by the es resource in es namespace
+	if _, err := reconciler.ReconcileSecret(r.Client, apiKeySecret, &owner); err != nil {
+		return 0, err
+	}
+
+	// reuse the existing API key if it is not about to expire
+	var existingSecret corev1.Secret
+	if err := r.Get(context.Background(), secKey, &existingSecret); err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	if _, exists := existingSecret.Data[usrKey.Name]; exists {
+		if expiration, err := time.Parse(time.RFC3339, existingSecret.Annotations[apiKeyExpirationAnnotation]); err == nil {
+			if rotateIn := certificates.ShouldRotateIn(time.Now(), expiration, r.APIKeyRotation.RotateBefore); rotateIn > 0 {
+				return rotateIn, nil
+			}
+		}
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, es)
+	if err != nil {
+		return 0, err
+	}
+	defer esClient.Close()
+
+	roleDescriptors, err := apiKeyRoleDescriptors(ctx, esClient, userRoles)
+	if err != nil {
+		return 0, err
+	}
+	apiKey, err := esClient.CreateAPIKey(ctx, esclient.APIKeyRequest{
+		Name:            usrKey.Name,
+		Expiration:      r.APIKeyRotation.Validity,
+		RoleDescriptors: roleDescriptors,
+	})
+	if err != nil {
+		return 0, err
+	}
+	r.log(k8s.ExtractNamespacedName(association.Associated())).Info("Created API key", "api_key_name", apiKey.Name, "api_key_id", apiKey.ID)
+
+	expectedSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secKey.Name,
+			Namespace:   secKey.Namespace,
+			Labels:      common.AddCredentialsLabel(labels),
+			Annotations: map[string]string{apiKeyExpirationAnnotation: apiKey.ExpirationTime().Format(time.RFC3339)},
+		},
+		Data: map[string][]byte{
+			usrKey.Name: []byte(apiKey.Encoded()),
+		},
+	}
+	if _, err := reconciler.ReconcileSecret(r.Client, expectedSecret, association.Associated()); err != nil {
+		return 0, err
+	}
+	return certificates.ShouldRotateIn(time.Now(), apiKey.ExpirationTime(), r.APIKeyRotation.RotateBefore), nil
+}
+
+// apiKeyRoleDescriptors returns the descriptors of the given comma separated roles, to be granted to an API key.
+// The roles predefined by the operator are only defined in the roles file of the file realm, the other ones are
+// retrieved from Elasticsearch.
+func apiKeyRoleDescriptors(ctx context.Context, esClient esclient.APIKeyClient, roles string) (map[string]interface{}, error) {
+	descriptors := make(map[string]interface{})
+	for _, role := range strings.Split(roles, ",") {
+		if predefined, exists := esuser.PredefinedRoles[role]; exists {
+			descriptors[role] = predefined
+			continue
+		}
+		descriptor, err := esClient.GetRole(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		// the metadata of built-in roles is reserved to Elasticsearch and cannot be part of a role descriptor
+		delete(descriptor, "metadata")
+		delete(descriptor, "transient_metadata")
+		descriptors[role] = descriptor
+	}
+	return descriptors, nil
+}
+
+// invalidateAPIKeys invalidates the API keys tracked by the given secret in the namespace of the Elasticsearch cluster.
+func (r *Reconciler) invalidateAPIKeys(ctx context.Context, apiKeySecret corev1.Secret) error {
+	var es esv1.Elasticsearch
+	esKey := types.NamespacedName{Namespace: apiKeySecret.Namespace, Name: apiKeySecret.Labels[eslabel.ClusterNameLabelName]}
+	if err := r.Get(context.Background(), esKey, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the API keys have been deleted along with the cluster
+			return nil
+		}
+		return err
+	}
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+	log.Info("Invalidating API keys", "namespace", es.Namespace, "es_name", es.Name, "api_key_name", apiKeySecret.Name)
+	return esClient.InvalidateAPIKeys(ctx, apiKeySecret.Name)
+}
+
+// deleteAPIKeys invalidates the API keys of the association and deletes the secrets tracking them.
+func (r *Reconciler) deleteAPIKeys(ctx context.Context, association commonv1.Association) error {
+	var apiKeySecrets corev1.SecretList
+	if err := r.List(context.Background(), &apiKeySecrets, r.apiKeyLabelSelector(
+		k8s.ExtractNamespacedName(association),
+		association.AssociationRef().NamespacedName(),
+	)); err != nil {
+		return err
+	}
+	for _, secret := range apiKeySecrets.Items {
+		if err := r.invalidateAPIKeys(ctx, secret); err != nil {
+			return err
+		}
+		if err := r.Delete(context.Background(), &secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
+)
+
+// fakeAPIKeyClient creates and invalidates API keys in memory.
+type fakeAPIKeyClient struct {
+	esclient.Client
+	created     []esclient.APIKeyRequest
+	invalidated []string
+}
+
+func (f *fakeAPIKeyClient) GetRole(_ context.Context, name string) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"cluster":  []interface{}{"manage_pipeline"},
+		"metadata": map[string]interface{}{"_reserved": true},
+	}, nil
+}
+
+func (f *fakeAPIKeyClient) CreateAPIKey(_ context.Context, request esclient.APIKeyRequest) (esclient.APIKey, error) {
+	f.created = append(f.created, request)
+	return esclient.APIKey{
+		ID:         fmt.Sprintf("id%d", len(f.created)),
+		Name:       request.Name,
+		Expiration: time.Now().Add(request.Expiration).UnixNano() / int64(time.Millisecond),
+		APIKey:     "key",
+	}, nil
+}
+
+func (f *fakeAPIKeyClient) InvalidateAPIKeys(_ context.Context, name string) error {
+	f.invalidated = append(f.invalidated, name)
+	return nil
+}
+
+func (f *fakeAPIKeyClient) Close() {}
+
+func (f *fakeAPIKeyClient) provider(_ context.Context, _ k8s.Client, _ net.Dialer, _ esv1.Elasticsearch) (esclient.Client, error) {
+	return f, nil
+}
+
+func apiKeyTestReconciler(esClient *fakeAPIKeyClient, objs ...runtime.Object) *Reconciler {
+	return &Reconciler{
+		AssociationInfo: AssociationInfo{
+			AssociationType: commonv1.ElasticsearchAssociationType,
+			ElasticsearchUserCreation: &ElasticsearchUserCreation{
+				UserSecretSuffix: "apm-user",
+				APIKeySupported:  true,
+			},
+			Labels: func(associated types.NamespacedName) map[string]string {
+				return map[string]string{"apmassociation.k8s.elastic.co/name": associated.Name}
+			},
+			AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
+			AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		},
+		Client:                      k8s.NewFakeClient(objs...),
+		esClientProvider:            esClient.provider,
+		credentialsVerifierProvider: acceptCredentialsProvider,
+		Parameters: operator.Parameters{
+			APIKeyRotation: certificates.RotationParams{Validity: 10 * 24 * time.Hour, RotateBefore: 24 * time.Hour},
+		},
+		logger: log.WithName("test"),
+	}
+}
+
+func TestReconciler_reconcileAPIKey(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "es-ns", Name: "es"}}
+	apm := &apmv1.ApmServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "apm-ns",
+			Name:        "apm",
+			Annotations: map[string]string{ElasticsearchCredentialsAnnotation: APIKeyCredentials},
+		},
+		Spec: apmv1.ApmServerSpec{ElasticsearchRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"}},
+	}
+	association := apmv1.NewApmEsAssociation(apm)
+	secKey := secretKey(association, "apm-user")
+	usrKey := UserKey(association, es.Namespace, "apm-user")
+	apiKeySecret := func(expiration time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   secKey.Namespace,
+				Name:        secKey.Name,
+				Annotations: map[string]string{apiKeyExpirationAnnotation: expiration.Format(time.RFC3339)},
+			},
+			Data: map[string][]byte{usrKey.Name: []byte("id0:key")},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		existing      []runtime.Object
+		wantCreated   bool
+		wantAPIKey    string
+		wantRotateMin time.Duration
+		wantRotateMax time.Duration
+	}{
+		{
+			name:          "create an API key",
+			wantCreated:   true,
+			wantAPIKey:    "id1:key",
+			wantRotateMin: 8 * 24 * time.Hour,
+			wantRotateMax: 10 * 24 * time.Hour,
+		},
+		{
+			name: "replace the password of a file realm user by an API key",
+			existing: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: secKey.Namespace, Name: secKey.Name},
+				Data:       map[string][]byte{usrKey.Name: []byte("password")},
+			}},
+			wantCreated:   true,
+			wantAPIKey:    "id1:key",
+			wantRotateMin: 8 * 24 * time.Hour,
+			wantRotateMax: 10 * 24 * time.Hour,
+		},
+		{
+			name:          "reuse a valid API key",
+			existing:      []runtime.Object{apiKeySecret(time.Now().Add(5 * 24 * time.Hour))},
+			wantAPIKey:    "id0:key",
+			wantRotateMin: 3 * 24 * time.Hour,
+			wantRotateMax: 5 * 24 * time.Hour,
+		},
+		{
+			name:          "rotate an API key about to expire",
+			existing:      []runtime.Object{apiKeySecret(time.Now().Add(time.Hour))},
+			wantCreated:   true,
+			wantAPIKey:    "id1:key",
+			wantRotateMin: 8 * 24 * time.Hour,
+			wantRotateMax: 10 * 24 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeAPIKeyClient{}
+			r := apiKeyTestReconciler(esClient, append(tt.existing, apm, &es)...)
+			rotateIn, err := r.reconcileAPIKey(context.Background(), association, map[string]string{}, esuser.ApmUserRoleV75+",ingest_admin", es)
+			require.NoError(t, err)
+			require.True(t, rotateIn >= tt.wantRotateMin && rotateIn <= tt.wantRotateMax, "unexpected rotation delay %s", rotateIn)
+
+			if tt.wantCreated {
+				require.Equal(t, []esclient.APIKeyRequest{{
+					Name:       usrKey.Name,
+					Expiration: 10 * 24 * time.Hour,
+					RoleDescriptors: map[string]interface{}{
+						esuser.ApmUserRoleV75: esuser.PredefinedRoles[esuser.ApmUserRoleV75],
+						"ingest_admin":        map[string]interface{}{"cluster": []interface{}{"manage_pipeline"}},
+					},
+				}}, esClient.created)
+			} else {
+				require.Empty(t, esClient.created)
+			}
+
+			var secret corev1.Secret
+			require.NoError(t, r.Get(context.Background(), secKey, &secret))
+			require.Equal(t, tt.wantAPIKey, string(secret.Data[usrKey.Name]))
+
+			// the API keys are tracked in the namespace of Elasticsearch
+			var tracking corev1.Secret
+			require.NoError(t, r.Get(context.Background(), usrKey, &tracking))
+			require.Equal(t, esuser.AssociatedAPIKeyType, tracking.Labels[common.TypeLabelName])
+			require.Equal(t, es.Name, tracking.Labels[eslabel.ClusterNameLabelName])
+		})
+	}
+}
+
+func TestReconciler_deleteAPIKeys(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "es-ns", Name: "es"}}
+	apm := &apmv1.ApmServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apm-ns", Name: "apm"},
+		Spec:       apmv1.ApmServerSpec{ElasticsearchRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"}},
+	}
+	association := apmv1.NewApmEsAssociation(apm)
+	usrKey := UserKey(association, es.Namespace, "apm-user")
+	esClient := &fakeAPIKeyClient{}
+	r := apiKeyTestReconciler(esClient, apm, &es)
+	require.NoError(t, r.Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: usrKey.Namespace,
+			Name:      usrKey.Name,
+			Labels: r.apiKeyLabelSelector(
+				k8s.ExtractNamespacedName(association),
+				association.AssociationRef().NamespacedName(),
+			),
+		},
+	}))
+
+	require.NoError(t, r.deleteAPIKeys(context.Background(), association))
+	require.Equal(t, []string{usrKey.Name}, esClient.invalidated)
+	err := r.Get(context.Background(), usrKey, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+}
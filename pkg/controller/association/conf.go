@@ -78,47 +78,105 @@ func IsConfiguredIfSet(association commonv1.Association, r record.EventRecorder)
 			"namespace", association.GetNamespace(),
 			"name", association.GetName(),
 			"ref_namespace", ref.Namespace,
-			"ref_name", ref.Name,
+			"ref_name", ref.NameOrSecretName(),
 		)
 		return false
 	}
 	return true
 }
 
-// ElasticsearchAuthSettings returns the user and the password to be used by an associated object to authenticate
-// against an Elasticsearch cluster.
-// This is also used for transitive authentication that relies on Elasticsearch native realm (eg. APMServer -> Kibana)
-func ElasticsearchAuthSettings(c k8s.Client, association commonv1.Association) (username, password string, err error) {
+// Credentials are the credentials used by an associated object to authenticate against an Elasticsearch cluster,
+// either a user and its password, an API key or a service account token.
+type Credentials struct {
+	Username string
+	Password string
+	// APIKey is encoded as id:api_key.
+	APIKey string
+	// ServiceAccountToken is a bearer token of a service account.
+	ServiceAccountToken string
+}
+
+// OutputSettings returns the settings used to authenticate in the Elasticsearch output of Beats, APM Server or
+// Elastic Agent.
+func (c Credentials) OutputSettings() map[string]interface{} {
+	if c.APIKey != "" {
+		return map[string]interface{}{"api_key": c.APIKey}
+	}
+	return map[string]interface{}{
+		"username": c.Username,
+		"password": c.Password,
+	}
+}
+
+// ElasticsearchCredentials returns the credentials to be used by an associated object to authenticate against an
+// Elasticsearch cluster, which may be an API key or a service account token.
+func ElasticsearchCredentials(c k8s.Client, association commonv1.Association) (Credentials, error) {
 	assocConf := association.AssociationConf()
 	if !assocConf.AuthIsConfigured() {
-		return "", "", nil
+		return Credentials{}, nil
 	}
 
 	secretObjKey := types.NamespacedName{Namespace: association.GetNamespace(), Name: assocConf.AuthSecretName}
 	var secret corev1.Secret
 	if err := c.Get(context.Background(), secretObjKey, &secret); err != nil {
-		return "", "", err
+		return Credentials{}, err
 	}
 
 	data, ok := secret.Data[assocConf.AuthSecretKey]
 	if !ok {
-		return "", "", errors.Errorf("auth secret key %s doesn't exist", assocConf.AuthSecretKey)
+		return Credentials{}, errors.Errorf("auth secret key %s doesn't exist", assocConf.AuthSecretKey)
+	}
+
+	if assocConf.AuthIsAPIKey() {
+		return Credentials{APIKey: string(data)}, nil
+	}
+	if assocConf.AuthIsServiceAccountToken() {
+		return Credentials{ServiceAccountToken: string(data)}, nil
 	}
+	return Credentials{Username: assocConf.AuthSecretKey, Password: string(data)}, nil
+}
 
-	return assocConf.AuthSecretKey, string(data), nil
+// ElasticsearchAuthSettings returns the user and the password to be used by an associated object to authenticate
+// against an Elasticsearch cluster. It returns an error if the association is configured with an API key or a service
+// account token, which cannot be used by the associated object.
+// This is also used for transitive authentication that relies on Elasticsearch native realm (eg. APMServer -> Kibana)
+func ElasticsearchAuthSettings(c k8s.Client, association commonv1.Association) (username, password string, err error) {
+	credentials, err := ElasticsearchCredentials(c, association)
+	if err != nil {
+		return "", "", err
+	}
+	if credentials.APIKey != "" {
+		return "", "", fmt.Errorf("the %s association of %s/%s requires a username and a password, API keys are not supported",
+			association.AssociationType(), association.GetNamespace(), association.GetName())
+	}
+	if credentials.ServiceAccountToken != "" {
+		return "", "", fmt.Errorf("the %s association of %s/%s requires a username and a password, service account tokens are not supported",
+			association.AssociationType(), association.GetNamespace(), association.GetName())
+	}
+	return credentials.Username, credentials.Password, nil
 }
 
 // AllowVersion returns true if the given resourceVersion is lower or equal to the associations' versions.
 // For example: Kibana in version 7.8.0 cannot be deployed if its Elasticsearch association reports version 7.7.0.
 // A difference in the patch version is ignored: Kibana 7.8.1+ can be deployed alongside Elasticsearch 7.8.0.
 // Referenced resources version is parsed from the association conf annotation.
+// Stack Monitoring associations are ignored since the version of a monitoring cluster is independent of the
+// version of the monitored resource. Associations to resources not managed by the operator are also ignored since
+// their version is unknown.
 func AllowVersion(resourceVersion version.Version, associated commonv1.Associated, logger logr.Logger, recorder record.EventRecorder) bool {
 	for _, assoc := range associated.GetAssociations() {
+		if assoc.AssociationType() == commonv1.EsMonitoringAssociationType {
+			continue
+		}
 		assocRef := assoc.AssociationRef()
 		if !assocRef.IsDefined() {
 			// no association specified, move on
 			continue
 		}
+		if assocRef.IsExternal() {
+			// the version of a referenced resource not managed by the operator is unknown
+			continue
+		}
 		if assoc.AssociationConf() == nil || assoc.AssociationConf().Version == "" {
 			// no conf reported yet, this may be the initial resource creation
 			logger.Info("Delaying version deployment since the version of an associated resource is not reported yet",
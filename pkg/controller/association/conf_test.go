@@ -341,6 +341,58 @@ func TestElasticsearchAuthSettings(t *testing.T) {
 	}
 }
 
+func TestElasticsearchCredentials(t *testing.T) {
+	apmEsAssociation := apmv1.ApmEsAssociation{
+		ApmServer: &apmv1.ApmServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "apm-server-sample",
+				Namespace: "default",
+			},
+		},
+	}
+	client := k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "apm-server-sample-apm-user",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{"api-key": []byte("id:key")},
+	})
+	apmEsAssociation.SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "apm-server-sample-apm-user",
+		AuthSecretKey:  "api-key",
+		AuthAPIKey:     true,
+		URL:            "https://es.example.com:9200",
+	})
+
+	credentials, err := ElasticsearchCredentials(client, &apmEsAssociation)
+	require.NoError(t, err)
+	require.Equal(t, Credentials{APIKey: "id:key"}, credentials)
+	require.Equal(t, map[string]interface{}{"api_key": "id:key"}, credentials.OutputSettings())
+
+	// API keys cannot be used where a username and a password are expected
+	_, _, err = ElasticsearchAuthSettings(client, &apmEsAssociation)
+	require.Error(t, err)
+
+	kb := mkKibana(true)
+	client = k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kb-test-kibana-user", Namespace: kb.Namespace},
+		Data:       map[string][]byte{"kb-ns-kb-test-kibana-user": []byte("token")},
+	})
+	kb.SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName:          "kb-test-kibana-user",
+		AuthSecretKey:           "kb-ns-kb-test-kibana-user",
+		AuthServiceAccountToken: true,
+		URL:                     "https://es.example.com:9200",
+	})
+	credentials, err = ElasticsearchCredentials(client, kb)
+	require.NoError(t, err)
+	require.Equal(t, Credentials{ServiceAccountToken: "token"}, credentials)
+
+	// neither service account tokens
+	_, _, err = ElasticsearchAuthSettings(client, kb)
+	require.Error(t, err)
+}
+
 func TestUpdateAssociationConf(t *testing.T) {
 	kb := mkKibana(true)
 	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "kb-test", Namespace: "kb-ns"}}
@@ -9,6 +9,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
 	corev1 "k8s.io/api/core/v1"
@@ -31,12 +32,14 @@ func AddAssociationController(
 ) error {
 	controllerName := associationInfo.AssociationName + "-association-controller"
 	r := &Reconciler{
-		AssociationInfo: associationInfo,
-		Client:          mgr.GetClient(),
-		accessReviewer:  accessReviewer,
-		watches:         watches.NewDynamicWatches(),
-		recorder:        mgr.GetEventRecorderFor(controllerName),
-		Parameters:      params,
+		AssociationInfo:             associationInfo,
+		Client:                      mgr.GetClient(),
+		accessReviewer:              accessReviewer,
+		watches:                     watches.NewDynamicWatches(),
+		recorder:                    mgr.GetEventRecorderFor(controllerName),
+		Parameters:                  params,
+		esClientProvider:            esuser.NewElasticsearchClient,
+		credentialsVerifierProvider: esuser.NewCredentialsVerifier,
 		// override the default logger to be specialized with the association name
 		logger: log.WithName(controllerName),
 	}
@@ -58,6 +61,13 @@ func addWatches(c controller.Controller, r *Reconciler) error {
 		return err
 	}
 
+	// Watch the referenced resources if they are not Elasticsearch clusters
+	if r.ReferencedObjTemplate != nil {
+		if err := c.Watch(&source.Kind{Type: r.ReferencedObjTemplate()}, r.watches.ReferencedResources); err != nil {
+			return err
+		}
+	}
+
 	// Dynamically watch Elasticsearch public CA secrets for referenced ES clusters
 	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, r.watches.Secrets); err != nil {
 		return err
@@ -6,11 +6,13 @@ package controller
 
 import (
 	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	pkgerrors "github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/agent"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
@@ -25,15 +27,15 @@ const (
 	AgentAssociationLabelNamespace = "agentassociation.k8s.elastic.co/namespace"
 	// AgentAssociationLabelType marks the type of association
 	AgentAssociationLabelType = "agentassociation.k8s.elastic.co/type"
+
+	// FleetServerServiceAccount is the name of the Elasticsearch service account Fleet Server authenticates as.
+	FleetServerServiceAccount = "elastic/fleet-server"
 )
 
 func AddAgentES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
 	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
-		AssociationType:       commonv1.ElasticsearchAssociationType,
-		AssociatedObjTemplate: func() commonv1.Associated { return &agentv1alpha1.Agent{} },
-		ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
-			return true, association.AssociationRef(), nil
-		},
+		AssociationType:           commonv1.ElasticsearchAssociationType,
+		AssociatedObjTemplate:     func() commonv1.Associated { return &agentv1alpha1.Agent{} },
 		ReferencedResourceVersion: referencedElasticsearchStatusVersion,
 		ExternalServiceURL:        getElasticsearchExternalURL,
 		AssociatedNamer:           esv1.ESNamer,
@@ -46,12 +48,38 @@ func AddAgentES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params
 				AgentAssociationLabelType:      commonv1.ElasticsearchAssociationType,
 			}
 		},
-		AssociationConfAnnotationNameBase: commonv1.ElasticsearchConfigAnnotationNameBase,
-		UserSecretSuffix:                  "agent-user",
-		ESUserRole: func(associated commonv1.Associated) (string, error) {
-			return "superuser", nil
-		},
+		AssociationConfAnnotationNameBase:     commonv1.ElasticsearchConfigAnnotationNameBase,
 		AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
 		AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix:       "agent-user",
+			AssociatedPodLabelName: agent.NameLabelName,
+			ESUserRole: func(associated commonv1.Associated) (string, error) {
+				return "superuser", nil
+			},
+			// Fleet Server does not support API keys, they can only be used by standalone Elastic Agents
+			APIKeySupported: true,
+			ServiceAccount:  fleetServerServiceAccount,
+		},
 	})
 }
+
+// fleetServerServiceAccount returns the service account Fleet Server authenticates as, if supported by its version.
+// Elastic Agents which do not run Fleet Server do not use a service account.
+func fleetServerServiceAccount(associated commonv1.Associated) (string, error) {
+	agent, ok := associated.(*agentv1alpha1.Agent)
+	if !ok {
+		return "", pkgerrors.Errorf(
+			"Agent expected, got %s/%s",
+			associated.GetObjectKind().GroupVersionKind().Group,
+			associated.GetObjectKind().GroupVersionKind().Kind,
+		)
+	}
+	if !agent.Spec.FleetServerEnabled {
+		return "", nil
+	}
+	return association.ServiceAccountForVersion(agent.Spec.Version, FleetServerServiceAccount)
+}
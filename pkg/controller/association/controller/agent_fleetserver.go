@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/agent"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func AddAgentFleetServer(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &agentv1alpha1.Agent{} },
+		ReferencedObjTemplate:     func() client.Object { return &agentv1alpha1.Agent{} },
+		ReferencedResourceVersion: referencedFleetServerStatusVersion,
+		ExternalServiceURL:        getFleetServerExternalURL,
+		AssociationType:           commonv1.FleetServerAssociationType,
+		AssociatedNamer:           agent.Namer,
+		AssociationName:           "agent-fleetserver",
+		AssociatedShortName:       "agent",
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				AgentAssociationLabelName:      associated.Name,
+				AgentAssociationLabelNamespace: associated.Namespace,
+				AgentAssociationLabelType:      commonv1.FleetServerAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.FleetServerConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      agent.NameLabelName,
+		AssociationResourceNamespaceLabelName: agent.NamespaceLabelName,
+		// Agents enroll in Fleet Server with an enrollment token, no Elasticsearch user is required
+		ElasticsearchUserCreation: nil,
+	})
+}
+
+func getFleetServerExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	fleetServerRef := assoc.AssociationRef()
+	if !fleetServerRef.IsDefined() {
+		return "", nil
+	}
+	fleetServer := agentv1alpha1.Agent{}
+	if err := c.Get(context.Background(), fleetServerRef.NamespacedName(), &fleetServer); err != nil {
+		return "", err
+	}
+	if fleetServerRef.ServiceName != "" {
+		serviceNSN := types.NamespacedName{Namespace: fleetServer.Namespace, Name: fleetServerRef.ServiceName}
+		return association.ServiceURL(c, serviceNSN, fleetServer.Spec.HTTP.Protocol())
+	}
+	return stringsutil.Concat(fleetServer.Spec.HTTP.Protocol(), "://", agent.HTTPServiceName(fleetServer.Name), ".", fleetServer.Namespace, ".svc:", strconv.Itoa(agent.FleetServerPort)), nil
+}
+
+// referencedFleetServerStatusVersion returns the currently running version of Fleet Server
+// reported in its status.
+func referencedFleetServerStatusVersion(c k8s.Client, fsRef types.NamespacedName) (string, error) {
+	var fleetServer agentv1alpha1.Agent
+	if err := c.Get(context.Background(), fsRef, &fleetServer); err != nil {
+		return "", err
+	}
+	return fleetServer.Status.Version, nil
+}
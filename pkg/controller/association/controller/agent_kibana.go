@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"fmt"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/agent"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/kibana"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	agentKibanaWatchNameTemplate = "%s-%s-agent-kibana-watch"
+)
+
+func AddAgentKibana(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &agentv1alpha1.Agent{} },
+		ExternalServiceURL:        getKibanaExternalURL,
+		ReferencedResourceVersion: referencedKibanaStatusVersion,
+		AssociatedNamer:           kibana.Namer,
+		AssociationName:           "agent-kibana",
+		AssociatedShortName:       "agent",
+		AssociationType:           commonv1.KibanaAssociationType,
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				AgentAssociationLabelName:      associated.Name,
+				AgentAssociationLabelNamespace: associated.Namespace,
+				AgentAssociationLabelType:      commonv1.KibanaAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase: commonv1.KibanaConfigAnnotationNameBase,
+		// The generic association controller watches Elasticsearch by default but we are interested in changes to
+		// Kibana as well for the purposes of establishing the association.
+		SetDynamicWatches: func(associated types.NamespacedName, associations []commonv1.Association, w watches.DynamicWatches) error {
+			return association.ReconcileWatch(
+				associated,
+				associations,
+				w.Kibanas,
+				fmt.Sprintf(agentKibanaWatchNameTemplate, associated.Namespace, associated.Name),
+				func(association commonv1.Association) types.NamespacedName {
+					return association.AssociationRef().NamespacedName()
+				},
+			)
+		},
+		ClearDynamicWatches: func(associated types.NamespacedName, w watches.DynamicWatches) {
+			association.RemoveWatch(w.Kibanas, fmt.Sprintf(agentKibanaWatchNameTemplate, associated.Namespace, associated.Name))
+		},
+		AssociationResourceNameLabelName:      kibana.KibanaNameLabelName,
+		AssociationResourceNamespaceLabelName: kibana.KibanaNamespaceLabelName,
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef:       getElasticsearchFromKibana,
+			UserSecretSuffix:       "agent-kb-user",
+			AssociatedPodLabelName: agent.NameLabelName,
+			// setting up Fleet and managing enrollment tokens through the Kibana Fleet API requires a superuser
+			ESUserRole: func(associated commonv1.Associated) (string, error) {
+				return "superuser", nil
+			},
+		},
+	})
+}
@@ -11,6 +11,7 @@ import (
 	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/apmserver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
@@ -35,12 +36,9 @@ const (
 
 func AddApmES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
 	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
-		AssociatedShortName:   "apm",
-		AssociatedObjTemplate: func() commonv1.Associated { return &apmv1.ApmServer{} },
-		AssociationType:       commonv1.ElasticsearchAssociationType,
-		ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
-			return true, association.AssociationRef(), nil
-		},
+		AssociatedShortName:       "apm",
+		AssociatedObjTemplate:     func() commonv1.Associated { return &apmv1.ApmServer{} },
+		AssociationType:           commonv1.ElasticsearchAssociationType,
 		ReferencedResourceVersion: referencedElasticsearchStatusVersion,
 		ExternalServiceURL:        getElasticsearchExternalURL,
 		AssociatedNamer:           esv1.ESNamer,
@@ -53,15 +51,22 @@ func AddApmES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params op
 			}
 		},
 		AssociationConfAnnotationNameBase:     commonv1.ElasticsearchConfigAnnotationNameBase,
-		UserSecretSuffix:                      "apm-user",
-		ESUserRole:                            getAPMElasticsearchRoles,
 		AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
 		AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix:       "apm-user",
+			AssociatedPodLabelName: apmserver.ApmServerNameLabelName,
+			ESUserRole:             getAPMElasticsearchRoles,
+			APIKeySupported:        true,
+		},
 	})
 }
 
-func getElasticsearchExternalURL(c k8s.Client, association commonv1.Association) (string, error) {
-	esRef := association.AssociationRef()
+func getElasticsearchExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	esRef := assoc.AssociationRef()
 	if !esRef.IsDefined() {
 		return "", nil
 	}
@@ -69,6 +74,10 @@ func getElasticsearchExternalURL(c k8s.Client, association commonv1.Association)
 	if err := c.Get(context.Background(), esRef.NamespacedName(), &es); err != nil {
 		return "", err
 	}
+	if esRef.ServiceName != "" {
+		serviceNSN := types.NamespacedName{Namespace: es.Namespace, Name: esRef.ServiceName}
+		return association.ServiceURL(c, serviceNSN, es.Spec.HTTP.Protocol())
+	}
 	return services.ExternalServiceURL(es), nil
 }
 
@@ -9,6 +9,12 @@ import (
 
 	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_getAPMElasticsearchRoles(t *testing.T) {
@@ -89,3 +95,48 @@ func Test_getAPMElasticsearchRoles(t *testing.T) {
 		})
 	}
 }
+
+func Test_getElasticsearchExternalURL(t *testing.T) {
+	es := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "es-ns", Name: "es"}}
+	coordinating := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "es-ns", Name: "coordinating"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 9200}}},
+	}
+	tests := []struct {
+		name    string
+		esRef   commonv1.ObjectSelector
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "default HTTP service",
+			esRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"},
+			want:  "https://es-es-http.es-ns.svc:9200",
+		},
+		{
+			name:  "custom service",
+			esRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es", ServiceName: "coordinating"},
+			want:  "https://coordinating.es-ns.svc:9200",
+		},
+		{
+			name:    "custom service does not exist",
+			esRef:   commonv1.ObjectSelector{Namespace: "es-ns", Name: "es", ServiceName: "missing"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := &kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+				Spec:       kbv1.KibanaSpec{ElasticsearchRef: tt.esRef},
+			}
+			got, err := getElasticsearchExternalURL(k8s.NewFakeClient(es, coordinating), kb)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
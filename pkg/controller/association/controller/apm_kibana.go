@@ -12,6 +12,7 @@ import (
 	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/apmserver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
@@ -35,7 +36,6 @@ func AddApmKibana(mgr manager.Manager, accessReviewer rbac.AccessReviewer, param
 		AssociatedObjTemplate:     func() commonv1.Associated { return &apmv1.ApmServer{} },
 		ExternalServiceURL:        getKibanaExternalURL,
 		ReferencedResourceVersion: referencedKibanaStatusVersion,
-		ElasticsearchRef:          getElasticsearchFromKibana,
 		AssociatedNamer:           kibana.Namer,
 		AssociationName:           "apm-kibana",
 		AssociationType:           commonv1.KibanaAssociationType,
@@ -47,10 +47,6 @@ func AddApmKibana(mgr manager.Manager, accessReviewer rbac.AccessReviewer, param
 			}
 		},
 		AssociationConfAnnotationNameBase: commonv1.KibanaConfigAnnotationNameBase,
-		UserSecretSuffix:                  "apm-kb-user",
-		ESUserRole: func(_ commonv1.Associated) (string, error) {
-			return user.ApmAgentUserRole, nil
-		},
 		SetDynamicWatches: func(associated types.NamespacedName, associations []commonv1.Association, w watches.DynamicWatches) error {
 			return association.ReconcileWatch(
 				associated,
@@ -67,11 +63,19 @@ func AddApmKibana(mgr manager.Manager, accessReviewer rbac.AccessReviewer, param
 		},
 		AssociationResourceNameLabelName:      kibana.KibanaNameLabelName,
 		AssociationResourceNamespaceLabelName: kibana.KibanaNamespaceLabelName,
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef:       getElasticsearchFromKibana,
+			UserSecretSuffix:       "apm-kb-user",
+			AssociatedPodLabelName: apmserver.ApmServerNameLabelName,
+			ESUserRole: func(_ commonv1.Associated) (string, error) {
+				return user.ApmAgentUserRole, nil
+			},
+		},
 	})
 }
 
-func getKibanaExternalURL(c k8s.Client, association commonv1.Association) (string, error) {
-	kibanaRef := association.AssociationRef()
+func getKibanaExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	kibanaRef := assoc.AssociationRef()
 	if !kibanaRef.IsDefined() {
 		return "", nil
 	}
@@ -79,6 +83,10 @@ func getKibanaExternalURL(c k8s.Client, association commonv1.Association) (strin
 	if err := c.Get(context.Background(), kibanaRef.NamespacedName(), &kb); err != nil {
 		return "", err
 	}
+	if kibanaRef.ServiceName != "" {
+		serviceNSN := types.NamespacedName{Namespace: kb.Namespace, Name: kibanaRef.ServiceName}
+		return association.ServiceURL(c, serviceNSN, kb.Spec.HTTP.Protocol())
+	}
 	return stringsutil.Concat(kb.Spec.HTTP.Protocol(), "://", kibana.HTTPService(kb.Name), ".", kb.Namespace, ".svc:", strconv.Itoa(kibana.HTTPPort)), nil
 }
 
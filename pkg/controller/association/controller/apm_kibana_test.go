@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"testing"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getKibanaExternalURL(t *testing.T) {
+	kb := &kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"}}
+	internal := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb-internal"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 5601}}},
+	}
+	tests := []struct {
+		name    string
+		kbRef   commonv1.ObjectSelector
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "default HTTP service",
+			kbRef: commonv1.ObjectSelector{Namespace: "kb-ns", Name: "kb"},
+			want:  "https://kb-kb-http.kb-ns.svc:5601",
+		},
+		{
+			name:  "custom service",
+			kbRef: commonv1.ObjectSelector{Namespace: "kb-ns", Name: "kb", ServiceName: "kb-internal"},
+			want:  "https://kb-internal.kb-ns.svc:5601",
+		},
+		{
+			name:    "custom service does not exist",
+			kbRef:   commonv1.ObjectSelector{Namespace: "kb-ns", Name: "kb", ServiceName: "missing"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apm := &apmv1.ApmServer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apm-ns", Name: "apm"},
+				Spec:       apmv1.ApmServerSpec{KibanaRef: tt.kbRef},
+			}
+			got, err := getKibanaExternalURL(k8s.NewFakeClient(kb, internal), &apmv1.ApmKibanaAssociation{ApmServer: apm})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	entctl "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch"
+	entname "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// KibanaEntAssociationLabelName marks resources created by this controller for easier retrieval.
+	KibanaEntAssociationLabelName = "kibanaentassociation.k8s.elastic.co/name"
+	// KibanaEntAssociationLabelNamespace marks resources created by this controller for easier retrieval.
+	KibanaEntAssociationLabelNamespace = "kibanaentassociation.k8s.elastic.co/namespace"
+	// KibanaEntAssociationLabelType marks the type of association
+	KibanaEntAssociationLabelType = "kibanaentassociation.k8s.elastic.co/type"
+)
+
+func AddKibanaEnt(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, kibanaEntAssociationInfo())
+}
+
+// kibanaEntAssociationInfo describes the association of Kibana with Enterprise Search.
+func kibanaEntAssociationInfo() association.AssociationInfo {
+	return association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &kbv1.Kibana{} },
+		ReferencedObjTemplate:     func() client.Object { return &entv1.EnterpriseSearch{} },
+		ReferencedResourceVersion: referencedEntStatusVersion,
+		ExternalServiceURL:        getEntExternalURL,
+		AssociationType:           commonv1.EntAssociationType,
+		AssociatedNamer:           entname.EntNamer,
+		AssociationName:           "kb-ent",
+		AssociatedShortName:       "kb",
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				KibanaEntAssociationLabelName:      associated.Name,
+				KibanaEntAssociationLabelNamespace: associated.Namespace,
+				KibanaEntAssociationLabelType:      commonv1.EntAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.EntConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      entctl.EnterpriseSearchNameLabelName,
+		AssociationResourceNamespaceLabelName: entctl.EnterpriseSearchNamespaceLabelName,
+		// Kibana connects to Enterprise Search on behalf of the logged in user, no dedicated user is required
+		ElasticsearchUserCreation: nil,
+	}
+}
+
+func getEntExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	entRef := assoc.AssociationRef()
+	if !entRef.IsDefined() {
+		return "", nil
+	}
+	ent := entv1.EnterpriseSearch{}
+	if err := c.Get(context.Background(), entRef.NamespacedName(), &ent); err != nil {
+		return "", err
+	}
+	if entRef.ServiceName != "" {
+		serviceNSN := types.NamespacedName{Namespace: ent.Namespace, Name: entRef.ServiceName}
+		return association.ServiceURL(c, serviceNSN, ent.Spec.HTTP.Protocol())
+	}
+	return stringsutil.Concat(ent.Spec.HTTP.Protocol(), "://", entname.HTTPService(ent.Name), ".", ent.Namespace, ".svc:", strconv.Itoa(entctl.HTTPPort)), nil
+}
+
+// referencedEntStatusVersion returns the currently running version of Enterprise Search
+// reported in its status.
+func referencedEntStatusVersion(c k8s.Client, entRef types.NamespacedName) (string, error) {
+	var ent entv1.EnterpriseSearch
+	if err := c.Get(context.Background(), entRef, &ent); err != nil {
+		return "", err
+	}
+	return ent.Status.Version, nil
+}
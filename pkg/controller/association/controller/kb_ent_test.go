@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"testing"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_getEntExternalURL(t *testing.T) {
+	internal := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ent-ns", Name: "ent-internal"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 3002}}},
+	}
+	tlsDisabled := commonv1.HTTPConfig{
+		TLS: commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}},
+	}
+	tests := []struct {
+		name    string
+		http    commonv1.HTTPConfig
+		entRef  commonv1.ObjectSelector
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "no reference",
+			entRef: commonv1.ObjectSelector{},
+			want:   "",
+		},
+		{
+			name:   "default HTTP service",
+			entRef: commonv1.ObjectSelector{Namespace: "ent-ns", Name: "ent"},
+			want:   "https://ent-ent-http.ent-ns.svc:3002",
+		},
+		{
+			name:   "default HTTP service without TLS",
+			http:   tlsDisabled,
+			entRef: commonv1.ObjectSelector{Namespace: "ent-ns", Name: "ent"},
+			want:   "http://ent-ent-http.ent-ns.svc:3002",
+		},
+		{
+			name:   "custom service",
+			entRef: commonv1.ObjectSelector{Namespace: "ent-ns", Name: "ent", ServiceName: "ent-internal"},
+			want:   "https://ent-internal.ent-ns.svc:3002",
+		},
+		{
+			name:    "custom service does not exist",
+			entRef:  commonv1.ObjectSelector{Namespace: "ent-ns", Name: "ent", ServiceName: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "Enterprise Search does not exist",
+			entRef:  commonv1.ObjectSelector{Namespace: "ent-ns", Name: "missing"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ent := &entv1.EnterpriseSearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ent-ns", Name: "ent"},
+				Spec:       entv1.EnterpriseSearchSpec{HTTP: tt.http},
+			}
+			kb := &kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+				Spec:       kbv1.KibanaSpec{EnterpriseSearchRef: tt.entRef},
+			}
+			got, err := getEntExternalURL(k8s.NewFakeClient(ent, internal), kbv1.NewKibanaEntAssociation(kb))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_referencedEntStatusVersion(t *testing.T) {
+	ent := &entv1.EnterpriseSearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ent-ns", Name: "ent"},
+		Spec:       entv1.EnterpriseSearchSpec{Version: "7.15.0"},
+		Status:     entv1.EnterpriseSearchStatus{DeploymentStatus: commonv1.DeploymentStatus{Version: "7.14.0"}},
+	}
+	c := k8s.NewFakeClient(ent)
+
+	version, err := referencedEntStatusVersion(c, types.NamespacedName{Namespace: "ent-ns", Name: "ent"})
+	require.NoError(t, err)
+	// the running version is reported rather than the expected one
+	require.Equal(t, "7.14.0", version)
+
+	_, err = referencedEntStatusVersion(c, types.NamespacedName{Namespace: "ent-ns", Name: "missing"})
+	require.Error(t, err)
+}
+
+func Test_kibanaEntAssociationInfo(t *testing.T) {
+	info := kibanaEntAssociationInfo()
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{EnterpriseSearchRef: commonv1.ObjectSelector{Namespace: "ent-ns", Name: "ent"}},
+	}
+	assoc := kbv1.NewKibanaEntAssociation(kb)
+
+	require.Equal(t, commonv1.AssociationType(commonv1.EntAssociationType), info.AssociationType)
+	require.IsType(t, &kbv1.Kibana{}, info.AssociatedObjTemplate())
+	require.IsType(t, &entv1.EnterpriseSearch{}, info.ReferencedObjTemplate())
+	require.Equal(t, map[string]string{
+		"kibanaentassociation.k8s.elastic.co/name":      "kb",
+		"kibanaentassociation.k8s.elastic.co/namespace": "kb-ns",
+		"kibanaentassociation.k8s.elastic.co/type":      "ent",
+	}, info.Labels(k8s.ExtractNamespacedName(kb)))
+	require.Equal(t, client.MatchingLabels{
+		"kibanaentassociation.k8s.elastic.co/name":      "kb",
+		"kibanaentassociation.k8s.elastic.co/namespace": "kb-ns",
+		"kibanaentassociation.k8s.elastic.co/type":      "ent",
+		"enterprisesearch.k8s.elastic.co/name":          "ent",
+		"enterprisesearch.k8s.elastic.co/namespace":     "ent-ns",
+	}, info.AssociationResourceLabels(k8s.ExtractNamespacedName(kb), assoc.AssociationRef().NamespacedName()))
+	require.Equal(t, "association.k8s.elastic.co/ent-conf", assoc.AssociationConfAnnotationName())
+	// the CA of Enterprise Search is copied from its public certificates to a Secret in the Kibana namespace
+	require.Equal(t, "ent-ent-http-certs-public", certificates.PublicCertsSecretName(info.AssociatedNamer, "ent"))
+	require.Equal(t, "kb-kb-ent-ca", association.CACertSecretName(assoc, info.AssociationName))
+	// Kibana connects to Enterprise Search on behalf of the logged in user: no user nor user Secret is created
+	require.Nil(t, info.ElasticsearchUserCreation)
+}
@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/maps"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// KibanaMapsAssociationLabelName marks resources created by this controller for easier retrieval.
+	KibanaMapsAssociationLabelName = "kibanamapsassociation.k8s.elastic.co/name"
+	// KibanaMapsAssociationLabelNamespace marks resources created by this controller for easier retrieval.
+	KibanaMapsAssociationLabelNamespace = "kibanamapsassociation.k8s.elastic.co/namespace"
+	// KibanaMapsAssociationLabelType marks the type of association
+	KibanaMapsAssociationLabelType = "kibanamapsassociation.k8s.elastic.co/type"
+)
+
+func AddKibanaMaps(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, kibanaMapsAssociationInfo())
+}
+
+// kibanaMapsAssociationInfo describes the association of Kibana with Elastic Maps Server.
+func kibanaMapsAssociationInfo() association.AssociationInfo {
+	return association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &kbv1.Kibana{} },
+		ReferencedObjTemplate:     func() client.Object { return &emsv1alpha1.ElasticMapsServer{} },
+		ReferencedResourceVersion: referencedMapsStatusVersion,
+		ExternalServiceURL:        getMapsExternalURL,
+		AssociationType:           commonv1.EMSAssociationType,
+		AssociatedNamer:           maps.EMSNamer,
+		AssociationName:           "kb-ems",
+		AssociatedShortName:       "kb",
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				KibanaMapsAssociationLabelName:      associated.Name,
+				KibanaMapsAssociationLabelNamespace: associated.Namespace,
+				KibanaMapsAssociationLabelType:      commonv1.EMSAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.EMSConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      maps.NameLabelName,
+		AssociationResourceNamespaceLabelName: maps.NamespaceLabelName,
+		// Elastic Maps Server does not require any credentials
+		ElasticsearchUserCreation: nil,
+	}
+}
+
+func getMapsExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	mapsRef := assoc.AssociationRef()
+	if !mapsRef.IsDefined() {
+		return "", nil
+	}
+	ems := emsv1alpha1.ElasticMapsServer{}
+	if err := c.Get(context.Background(), mapsRef.NamespacedName(), &ems); err != nil {
+		return "", err
+	}
+	if mapsRef.ServiceName != "" {
+		serviceNSN := types.NamespacedName{Namespace: ems.Namespace, Name: mapsRef.ServiceName}
+		return association.ServiceURL(c, serviceNSN, ems.Spec.HTTP.Protocol())
+	}
+	return stringsutil.Concat(ems.Spec.HTTP.Protocol(), "://", maps.HTTPService(ems.Name), ".", ems.Namespace, ".svc:", strconv.Itoa(maps.HTTPPort)), nil
+}
+
+// referencedMapsStatusVersion returns the currently running version of Elastic Maps Server
+// reported in its status.
+func referencedMapsStatusVersion(c k8s.Client, emsRef types.NamespacedName) (string, error) {
+	var ems emsv1alpha1.ElasticMapsServer
+	if err := c.Get(context.Background(), emsRef, &ems); err != nil {
+		return "", err
+	}
+	return ems.Status.Version, nil
+}
@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"testing"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_getMapsExternalURL(t *testing.T) {
+	internal := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ems-ns", Name: "ems-internal"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 8080}}},
+	}
+	tlsDisabled := commonv1.HTTPConfig{
+		TLS: commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}},
+	}
+	tests := []struct {
+		name    string
+		http    commonv1.HTTPConfig
+		mapsRef commonv1.ObjectSelector
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no reference",
+			mapsRef: commonv1.ObjectSelector{},
+			want:    "",
+		},
+		{
+			name:    "default HTTP service",
+			mapsRef: commonv1.ObjectSelector{Namespace: "ems-ns", Name: "ems"},
+			want:    "https://ems-ems-http.ems-ns.svc:8080",
+		},
+		{
+			name:    "default HTTP service without TLS",
+			http:    tlsDisabled,
+			mapsRef: commonv1.ObjectSelector{Namespace: "ems-ns", Name: "ems"},
+			want:    "http://ems-ems-http.ems-ns.svc:8080",
+		},
+		{
+			name:    "custom service",
+			mapsRef: commonv1.ObjectSelector{Namespace: "ems-ns", Name: "ems", ServiceName: "ems-internal"},
+			want:    "https://ems-internal.ems-ns.svc:8080",
+		},
+		{
+			name:    "custom service does not exist",
+			mapsRef: commonv1.ObjectSelector{Namespace: "ems-ns", Name: "ems", ServiceName: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "Elastic Maps Server does not exist",
+			mapsRef: commonv1.ObjectSelector{Namespace: "ems-ns", Name: "missing"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ems := &emsv1alpha1.ElasticMapsServer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ems-ns", Name: "ems"},
+				Spec:       emsv1alpha1.MapsSpec{HTTP: tt.http},
+			}
+			kb := &kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+				Spec:       kbv1.KibanaSpec{MapsRef: tt.mapsRef},
+			}
+			got, err := getMapsExternalURL(k8s.NewFakeClient(ems, internal), kbv1.NewKibanaMapsAssociation(kb))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_referencedMapsStatusVersion(t *testing.T) {
+	ems := &emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ems-ns", Name: "ems"},
+		Spec:       emsv1alpha1.MapsSpec{Version: "7.15.0"},
+		Status:     emsv1alpha1.MapsStatus{DeploymentStatus: commonv1.DeploymentStatus{Version: "7.14.0"}},
+	}
+	c := k8s.NewFakeClient(ems)
+
+	version, err := referencedMapsStatusVersion(c, types.NamespacedName{Namespace: "ems-ns", Name: "ems"})
+	require.NoError(t, err)
+	// the running version is reported rather than the expected one
+	require.Equal(t, "7.14.0", version)
+
+	_, err = referencedMapsStatusVersion(c, types.NamespacedName{Namespace: "ems-ns", Name: "missing"})
+	require.Error(t, err)
+}
+
+func Test_kibanaMapsAssociationInfo(t *testing.T) {
+	info := kibanaMapsAssociationInfo()
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{MapsRef: commonv1.ObjectSelector{Namespace: "ems-ns", Name: "ems"}},
+	}
+	assoc := kbv1.NewKibanaMapsAssociation(kb)
+
+	require.Equal(t, commonv1.AssociationType(commonv1.EMSAssociationType), info.AssociationType)
+	require.IsType(t, &kbv1.Kibana{}, info.AssociatedObjTemplate())
+	require.IsType(t, &emsv1alpha1.ElasticMapsServer{}, info.ReferencedObjTemplate())
+	require.Equal(t, map[string]string{
+		"kibanamapsassociation.k8s.elastic.co/name":      "kb",
+		"kibanamapsassociation.k8s.elastic.co/namespace": "kb-ns",
+		"kibanamapsassociation.k8s.elastic.co/type":      "ems",
+	}, info.Labels(k8s.ExtractNamespacedName(kb)))
+	require.Equal(t, client.MatchingLabels{
+		"kibanamapsassociation.k8s.elastic.co/name":      "kb",
+		"kibanamapsassociation.k8s.elastic.co/namespace": "kb-ns",
+		"kibanamapsassociation.k8s.elastic.co/type":      "ems",
+		"maps.k8s.elastic.co/name":                       "ems",
+		"maps.k8s.elastic.co/namespace":                  "ems-ns",
+	}, info.AssociationResourceLabels(k8s.ExtractNamespacedName(kb), assoc.AssociationRef().NamespacedName()))
+	require.Equal(t, "association.k8s.elastic.co/ems-conf", assoc.AssociationConfAnnotationName())
+	// the CA of Elastic Maps Server is copied from its public certificates to a Secret in the Kibana namespace
+	require.Equal(t, "ems-ems-http-certs-public", certificates.PublicCertsSecretName(info.AssociatedNamer, "ems"))
+	require.Equal(t, "kb-kb-ems-ca", association.CACertSecretName(assoc, info.AssociationName))
+	// Elastic Maps Server does not require any credentials: no user nor user Secret is created
+	require.Nil(t, info.ElasticsearchUserCreation)
+}
@@ -5,6 +5,7 @@
 package controller
 
 import (
+	pkgerrors "github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/kibana"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
 )
@@ -28,14 +30,13 @@ const (
 
 	// KibanaSystemUserBuiltinRole is the name of the built-in role for the Kibana system user.
 	KibanaSystemUserBuiltinRole = "kibana_system"
+	// KibanaServiceAccount is the name of the Elasticsearch service account Kibana authenticates as.
+	KibanaServiceAccount = "elastic/kibana"
 )
 
 func AddKibanaES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
 	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
-		AssociatedObjTemplate: func() commonv1.Associated { return &kbv1.Kibana{} },
-		ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
-			return true, association.AssociationRef(), nil
-		},
+		AssociatedObjTemplate:     func() commonv1.Associated { return &kbv1.Kibana{} },
 		ReferencedResourceVersion: referencedElasticsearchStatusVersion,
 		ExternalServiceURL:        getElasticsearchExternalURL,
 		AssociationType:           commonv1.ElasticsearchAssociationType,
@@ -49,12 +50,32 @@ func AddKibanaES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params
 				KibanaESAssociationLabelType:      commonv1.ElasticsearchAssociationType,
 			}
 		},
-		AssociationConfAnnotationNameBase: commonv1.ElasticsearchConfigAnnotationNameBase,
-		UserSecretSuffix:                  "kibana-user",
-		ESUserRole: func(associated commonv1.Associated) (string, error) {
-			return KibanaSystemUserBuiltinRole, nil
-		},
+		AssociationConfAnnotationNameBase:     commonv1.ElasticsearchConfigAnnotationNameBase,
 		AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
 		AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix:       "kibana-user",
+			AssociatedPodLabelName: kibana.KibanaNameLabelName,
+			ESUserRole: func(associated commonv1.Associated) (string, error) {
+				return KibanaSystemUserBuiltinRole, nil
+			},
+			ServiceAccount: kibanaServiceAccount,
+		},
 	})
 }
+
+// kibanaServiceAccount returns the service account Kibana authenticates as, if supported by its version.
+func kibanaServiceAccount(associated commonv1.Associated) (string, error) {
+	kb, ok := associated.(*kbv1.Kibana)
+	if !ok {
+		return "", pkgerrors.Errorf(
+			"Kibana expected, got %s/%s",
+			associated.GetObjectKind().GroupVersionKind().Group,
+			associated.GetObjectKind().GroupVersionKind().Kind,
+		)
+	}
+	return association.ServiceAccountForVersion(kb.Spec.Version, KibanaServiceAccount)
+}
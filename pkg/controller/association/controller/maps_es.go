@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/maps"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
+)
+
+const (
+	// MapsESAssociationLabelName marks resources created by this controller for easier retrieval.
+	MapsESAssociationLabelName = "mapsassociation.k8s.elastic.co/name"
+	// MapsESAssociationLabelNamespace marks resources created by this controller for easier retrieval.
+	MapsESAssociationLabelNamespace = "mapsassociation.k8s.elastic.co/namespace"
+	// MapsESAssociationLabelType marks resources created for an association originating from Elastic Maps Server.
+	MapsESAssociationLabelType = "mapsassociation.k8s.elastic.co/type"
+)
+
+func AddMapsES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, mapsESAssociationInfo())
+}
+
+// mapsESAssociationInfo describes the association of Elastic Maps Server with Elasticsearch.
+func mapsESAssociationInfo() association.AssociationInfo {
+	return association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &emsv1alpha1.ElasticMapsServer{} },
+		ReferencedResourceVersion: referencedElasticsearchStatusVersion,
+		AssociationType:           commonv1.ElasticsearchAssociationType,
+		ExternalServiceURL:        getElasticsearchExternalURL,
+		AssociatedNamer:           esv1.ESNamer,
+		AssociationName:           "ems-es",
+		AssociatedShortName:       "ems",
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				MapsESAssociationLabelName:      associated.Name,
+				MapsESAssociationLabelNamespace: associated.Namespace,
+				MapsESAssociationLabelType:      commonv1.ElasticsearchAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.ElasticsearchConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
+		AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix:       "maps-user",
+			AssociatedPodLabelName: maps.NameLabelName,
+			ESUserRole: func(_ commonv1.Associated) (string, error) {
+				return esuser.MapsUserRole, nil
+			},
+		},
+	}
+}
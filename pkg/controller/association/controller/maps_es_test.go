@@ -0,0 +1,61 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"testing"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_mapsESAssociationInfo(t *testing.T) {
+	info := mapsESAssociationInfo()
+	ems := &emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ems-ns", Name: "ems"},
+		Spec:       emsv1alpha1.MapsSpec{ElasticsearchRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"}},
+	}
+
+	require.Equal(t, commonv1.AssociationType(commonv1.ElasticsearchAssociationType), info.AssociationType)
+	require.IsType(t, &emsv1alpha1.ElasticMapsServer{}, info.AssociatedObjTemplate())
+	require.Equal(t, map[string]string{
+		"mapsassociation.k8s.elastic.co/name":      "ems",
+		"mapsassociation.k8s.elastic.co/namespace": "ems-ns",
+		"mapsassociation.k8s.elastic.co/type":      "elasticsearch",
+	}, info.Labels(k8s.ExtractNamespacedName(ems)))
+	require.Equal(t, client.MatchingLabels{
+		"mapsassociation.k8s.elastic.co/name":            "ems",
+		"mapsassociation.k8s.elastic.co/namespace":       "ems-ns",
+		"mapsassociation.k8s.elastic.co/type":            "elasticsearch",
+		"elasticsearch.k8s.elastic.co/cluster-name":      "es",
+		"elasticsearch.k8s.elastic.co/cluster-namespace": "es-ns",
+	}, info.AssociationResourceLabels(k8s.ExtractNamespacedName(ems), ems.AssociationRef().NamespacedName()))
+	require.Equal(t, "association.k8s.elastic.co/es-conf", ems.AssociationConfAnnotationName())
+	// the CA of Elasticsearch is copied from its public certificates to a Secret in the Elastic Maps Server namespace
+	require.Equal(t, "es-es-http-certs-public", certificates.PublicCertsSecretName(info.AssociatedNamer, "es"))
+	require.Equal(t, "ems-ems-es-ca", association.CACertSecretName(ems, info.AssociationName))
+
+	// a user is created in Elasticsearch for Elastic Maps Server
+	userCreation := info.ElasticsearchUserCreation
+	require.NotNil(t, userCreation)
+	require.Equal(t, "maps-user", userCreation.UserSecretSuffix)
+	require.Equal(t, "maps.k8s.elastic.co/name", userCreation.AssociatedPodLabelName)
+	require.Equal(t, types.NamespacedName{Namespace: "es-ns", Name: "ems-ns-ems-maps-user"}, association.UserKey(ems, "es-ns", userCreation.UserSecretSuffix))
+	role, err := userCreation.ESUserRole(ems)
+	require.NoError(t, err)
+	require.Equal(t, "eck_maps_server_user_role", role)
+	// the user is created in the referenced Elasticsearch cluster
+	found, esRef, err := userCreation.ElasticsearchRef(k8s.NewFakeClient(), ems)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"}, esRef)
+}
@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/apmserver"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	beatcommon "github.com/elastic/cloud-on-k8s/pkg/controller/beat/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	entctl "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/kibana"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/rbac"
+)
+
+const (
+	// EsMonitoringAssociationLabelName marks resources created for an association originating from a monitored Elasticsearch.
+	EsMonitoringAssociationLabelName = "esmonitoringassociation.k8s.elastic.co/name"
+	// EsMonitoringAssociationLabelNamespace marks resources created for an association originating from a monitored Elasticsearch.
+	EsMonitoringAssociationLabelNamespace = "esmonitoringassociation.k8s.elastic.co/namespace"
+	// EsMonitoringAssociationLabelType marks resources created for an association originating from a monitored Elasticsearch.
+	EsMonitoringAssociationLabelType = "esmonitoringassociation.k8s.elastic.co/type"
+
+	// KbMonitoringAssociationLabelName marks resources created for an association originating from a monitored Kibana.
+	KbMonitoringAssociationLabelName = "kbmonitoringassociation.k8s.elastic.co/name"
+	// KbMonitoringAssociationLabelNamespace marks resources created for an association originating from a monitored Kibana.
+	KbMonitoringAssociationLabelNamespace = "kbmonitoringassociation.k8s.elastic.co/namespace"
+	// KbMonitoringAssociationLabelType marks resources created for an association originating from a monitored Kibana.
+	KbMonitoringAssociationLabelType = "kbmonitoringassociation.k8s.elastic.co/type"
+
+	// ApmMonitoringAssociationLabelName marks resources created for an association originating from a monitored APM Server.
+	ApmMonitoringAssociationLabelName = "apmmonitoringassociation.k8s.elastic.co/name"
+	// ApmMonitoringAssociationLabelNamespace marks resources created for an association originating from a monitored APM Server.
+	ApmMonitoringAssociationLabelNamespace = "apmmonitoringassociation.k8s.elastic.co/namespace"
+	// ApmMonitoringAssociationLabelType marks resources created for an association originating from a monitored APM Server.
+	ApmMonitoringAssociationLabelType = "apmmonitoringassociation.k8s.elastic.co/type"
+
+	// EntMonitoringAssociationLabelName marks resources created for an association originating from a monitored Enterprise Search.
+	EntMonitoringAssociationLabelName = "entmonitoringassociation.k8s.elastic.co/name"
+	// EntMonitoringAssociationLabelNamespace marks resources created for an association originating from a monitored Enterprise Search.
+	EntMonitoringAssociationLabelNamespace = "entmonitoringassociation.k8s.elastic.co/namespace"
+	// EntMonitoringAssociationLabelType marks resources created for an association originating from a monitored Enterprise Search.
+	EntMonitoringAssociationLabelType = "entmonitoringassociation.k8s.elastic.co/type"
+
+	// BeatMonitoringAssociationLabelName marks resources created for an association originating from a monitored Beat.
+	BeatMonitoringAssociationLabelName = "beatmonitoringassociation.k8s.elastic.co/name"
+	// BeatMonitoringAssociationLabelNamespace marks resources created for an association originating from a monitored Beat.
+	BeatMonitoringAssociationLabelNamespace = "beatmonitoringassociation.k8s.elastic.co/namespace"
+	// BeatMonitoringAssociationLabelType marks resources created for an association originating from a monitored Beat.
+	BeatMonitoringAssociationLabelType = "beatmonitoringassociation.k8s.elastic.co/type"
+)
+
+// AddEsMonitoring reconciles an association between two Elasticsearch clusters for Stack Monitoring.
+// Beats are configured to collect monitoring metrics and logs data of the associated Elasticsearch and send
+// them to the Elasticsearch referenced in the association.
+func AddEsMonitoring(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return addMonitoringAssociationController(mgr, accessReviewer, params, monitoringAssociation{
+		associatedObjTemplate: func() commonv1.Associated { return &esv1.Elasticsearch{} },
+		associationName:       "es-monitoring",
+		associatedShortName:   "es-mon",
+		userSecretSuffix:      "beat-es-mon-user",
+		podLabelName:          eslabel.ClusterNameLabelName,
+		labelName:             EsMonitoringAssociationLabelName,
+		labelNamespace:        EsMonitoringAssociationLabelNamespace,
+		labelType:             EsMonitoringAssociationLabelType,
+	})
+}
+
+// AddKbMonitoring reconciles an association between Kibana and Elasticsearch for Stack Monitoring.
+// Beats are configured to collect monitoring metrics and logs data of the associated Kibana and send
+// them to the Elasticsearch referenced in the association.
+func AddKbMonitoring(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return addMonitoringAssociationController(mgr, accessReviewer, params, monitoringAssociation{
+		associatedObjTemplate: func() commonv1.Associated { return &kbv1.Kibana{} },
+		associationName:       "kb-monitoring",
+		associatedShortName:   "kb-mon",
+		userSecretSuffix:      "beat-kb-mon-user",
+		podLabelName:          kibana.KibanaNameLabelName,
+		labelName:             KbMonitoringAssociationLabelName,
+		labelNamespace:        KbMonitoringAssociationLabelNamespace,
+		labelType:             KbMonitoringAssociationLabelType,
+	})
+}
+
+// AddApmMonitoring reconciles an association between APM Server and Elasticsearch for Stack Monitoring.
+// Metricbeat is configured to collect monitoring metrics of the associated APM Server and send
+// them to the Elasticsearch referenced in the association.
+func AddApmMonitoring(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return addMonitoringAssociationController(mgr, accessReviewer, params, monitoringAssociation{
+		associatedObjTemplate: func() commonv1.Associated { return &apmv1.ApmServer{} },
+		associationName:       "apm-monitoring",
+		associatedShortName:   "apm-mon",
+		userSecretSuffix:      "beat-apm-mon-user",
+		podLabelName:          apmserver.ApmServerNameLabelName,
+		labelName:             ApmMonitoringAssociationLabelName,
+		labelNamespace:        ApmMonitoringAssociationLabelNamespace,
+		labelType:             ApmMonitoringAssociationLabelType,
+	})
+}
+
+// AddEntMonitoring reconciles an association between Enterprise Search and Elasticsearch for Stack Monitoring.
+// Metricbeat is configured to collect monitoring metrics of the associated Enterprise Search and send
+// them to the Elasticsearch referenced in the association.
+func AddEntMonitoring(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return addMonitoringAssociationController(mgr, accessReviewer, params, monitoringAssociation{
+		associatedObjTemplate: func() commonv1.Associated { return &entv1.EnterpriseSearch{} },
+		associationName:       "ent-monitoring",
+		associatedShortName:   "ent-mon",
+		userSecretSuffix:      "beat-ent-mon-user",
+		podLabelName:          entctl.EnterpriseSearchNameLabelName,
+		labelName:             EntMonitoringAssociationLabelName,
+		labelNamespace:        EntMonitoringAssociationLabelNamespace,
+		labelType:             EntMonitoringAssociationLabelType,
+	})
+}
+
+// AddBeatMonitoring reconciles an association between a Beat and Elasticsearch for Stack Monitoring.
+// Metricbeat is configured to collect monitoring metrics of the associated Beat and send
+// them to the Elasticsearch referenced in the association.
+func AddBeatMonitoring(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return addMonitoringAssociationController(mgr, accessReviewer, params, monitoringAssociation{
+		associatedObjTemplate: func() commonv1.Associated { return &beatv1beta1.Beat{} },
+		associationName:       "beat-monitoring",
+		associatedShortName:   "beat-mon",
+		userSecretSuffix:      "beat-beat-mon-user",
+		podLabelName:          beatcommon.NameLabelName,
+		labelName:             BeatMonitoringAssociationLabelName,
+		labelNamespace:        BeatMonitoringAssociationLabelNamespace,
+		labelType:             BeatMonitoringAssociationLabelType,
+	})
+}
+
+// monitoringAssociation holds what differs between the Stack Monitoring association controllers of each resource kind.
+type monitoringAssociation struct {
+	associatedObjTemplate func() commonv1.Associated
+	associationName       string
+	associatedShortName   string
+	userSecretSuffix      string
+	podLabelName          string
+	labelName             string
+	labelNamespace        string
+	labelType             string
+}
+
+func addMonitoringAssociationController(
+	mgr manager.Manager,
+	accessReviewer rbac.AccessReviewer,
+	params operator.Parameters,
+	m monitoringAssociation,
+) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociationType:           commonv1.EsMonitoringAssociationType,
+		AssociatedObjTemplate:     m.associatedObjTemplate,
+		ReferencedResourceVersion: referencedElasticsearchStatusVersion,
+		ExternalServiceURL:        getElasticsearchExternalURL,
+		AssociatedNamer:           esv1.ESNamer,
+		AssociationName:           m.associationName,
+		AssociatedShortName:       m.associatedShortName,
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				m.labelName:      associated.Name,
+				m.labelNamespace: associated.Namespace,
+				m.labelType:      commonv1.EsMonitoringAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.EsMonitoringConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
+		AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix:       m.userSecretSuffix,
+			AssociatedPodLabelName: m.podLabelName,
+			ESUserRole: func(associated commonv1.Associated) (string, error) {
+				return user.StackMonitoringUserRole, nil
+			},
+			APIKeySupported: true,
+		},
+	})
+}
@@ -18,6 +18,12 @@ func esWatchName(associated types.NamespacedName) string {
 	return fmt.Sprintf("%s-%s-es-watch", associated.Namespace, associated.Name)
 }
 
+// referencedResourceWatchName returns the name of the watch setup on the referenced resource, for associations
+// that do not target Elasticsearch.
+func referencedResourceWatchName(associated types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-referenced-resource-watch", associated.Namespace, associated.Name)
+}
+
 // esUserWatchName returns the name of the watch setup on the ES user secret.
 func esUserWatchName(associated types.NamespacedName) string {
 	return fmt.Sprintf("%s-%s-es-user-watch", associated.Namespace, associated.Name)
@@ -29,25 +35,41 @@ func associatedCAWatchName(associated types.NamespacedName) string {
 	return fmt.Sprintf("%s-%s-ca-watch", associated.Namespace, associated.Name)
 }
 
+// unmanagedSecretWatchName returns the name of the watch setup on the secrets describing referenced resources not
+// managed by the operator.
+func unmanagedSecretWatchName(associated types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-unmanaged-secret-watch", associated.Namespace, associated.Name)
+}
+
 // reconcileWatches sets up dynamic watches related to:
 // * The referenced Elasticsearch resource
 // * The user created in the Elasticsearch namespace
 // * The CA of the target service (can be Kibana or Elasticsearch in the case of the APM)
+// * The secret describing the referenced resource if it is not managed by the operator
 // All watches for all Associations are set under the same watch name for associated resource and replaced
 // with each reconciliation.
 func (r *Reconciler) reconcileWatches(associated types.NamespacedName, associations []commonv1.Association) error {
-	// watch the referenced ES cluster for future reconciliations
-	if err := ReconcileWatch(associated, associations, r.watches.ElasticsearchClusters, esWatchName(associated), func(association commonv1.Association) types.NamespacedName {
-		return association.AssociationRef().NamespacedName()
-	}); err != nil {
-		return err
-	}
+	if r.ElasticsearchUserCreation == nil {
+		// watch the referenced resource for future reconciliations
+		if err := ReconcileWatch(associated, associations, r.watches.ReferencedResources, referencedResourceWatchName(associated), func(association commonv1.Association) types.NamespacedName {
+			return association.AssociationRef().NamespacedName()
+		}); err != nil {
+			return err
+		}
+	} else {
+		// watch the referenced ES cluster for future reconciliations
+		if err := ReconcileWatch(associated, associations, r.watches.ElasticsearchClusters, esWatchName(associated), func(association commonv1.Association) types.NamespacedName {
+			return association.AssociationRef().NamespacedName()
+		}); err != nil {
+			return err
+		}
 
-	// watch the user secret in the ES namespace
-	if err := ReconcileWatch(associated, associations, r.watches.Secrets, esUserWatchName(associated), func(association commonv1.Association) types.NamespacedName {
-		return UserKey(association, association.AssociationRef().Namespace, r.UserSecretSuffix)
-	}); err != nil {
-		return err
+		// watch the user secret in the ES namespace
+		if err := ReconcileWatch(associated, associations, r.watches.Secrets, esUserWatchName(associated), func(association commonv1.Association) types.NamespacedName {
+			return UserKey(association, association.AssociationRef().Namespace, r.ElasticsearchUserCreation.UserSecretSuffix)
+		}); err != nil {
+			return err
+		}
 	}
 
 	// watch the CA secret in the targeted service namespace
@@ -62,6 +84,19 @@ func (r *Reconciler) reconcileWatches(associated types.NamespacedName, associati
 		return err
 	}
 
+	// watch the secrets describing the referenced resources not managed by the operator
+	var unmanagedAssociations []commonv1.Association
+	for _, association := range associations {
+		if association.AssociationRef().IsExternal() {
+			unmanagedAssociations = append(unmanagedAssociations, association)
+		}
+	}
+	if err := ReconcileWatch(associated, unmanagedAssociations, r.watches.Secrets, unmanagedSecretWatchName(associated), func(association commonv1.Association) types.NamespacedName {
+		return association.AssociationRef().NamespacedName()
+	}); err != nil {
+		return err
+	}
+
 	// set additional watches, in the case of a transitive Elasticsearch reference we must watch the intermediate resource
 	if r.SetDynamicWatches != nil {
 		if err := r.SetDynamicWatches(associated, associations, r.watches); err != nil {
@@ -107,8 +142,12 @@ func RemoveWatch(dynamicRequest *watches.DynamicEnqueueRequest, watchName string
 func (r *Reconciler) removeWatches(associated types.NamespacedName) {
 	// - ES resource
 	RemoveWatch(r.watches.ElasticsearchClusters, esWatchName(associated))
+	// - referenced resource if not Elasticsearch
+	RemoveWatch(r.watches.ReferencedResources, referencedResourceWatchName(associated))
 	// - user in the ES namespace
 	RemoveWatch(r.watches.Secrets, esUserWatchName(associated))
 	// - ES CA Secret in the ES namespace
 	RemoveWatch(r.watches.Secrets, associatedCAWatchName(associated))
+	// - secrets describing referenced resources not managed by the operator
+	RemoveWatch(r.watches.Secrets, unmanagedSecretWatchName(associated))
 }
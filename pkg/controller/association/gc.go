@@ -85,13 +85,19 @@ func (ugc *UsersGarbageCollector) getUserSecrets() ([]v1.Secret, error) {
 	return userSecrets, nil
 }
 
+// getUserSecretsInNamespace returns the secrets of the users, of the API keys and of the service account tokens created
+// for associations. The API keys of orphaned secrets are not invalidated, they remain valid until their expiration.
 func getUserSecretsInNamespace(c k8s.Client, namespace string) ([]v1.Secret, error) {
-	userSecrets := v1.SecretList{}
-	matchingLabels := client.MatchingLabels(map[string]string{common.TypeLabelName: esuser.AssociatedUserType})
-	if err := c.List(context.Background(), &userSecrets, client.InNamespace(namespace), matchingLabels); err != nil {
-		return nil, err
+	var secrets []v1.Secret
+	for _, secretType := range []string{esuser.AssociatedUserType, esuser.AssociatedAPIKeyType, esuser.ServiceAccountTokenType} {
+		userSecrets := v1.SecretList{}
+		matchingLabels := client.MatchingLabels(map[string]string{common.TypeLabelName: secretType})
+		if err := c.List(context.Background(), &userSecrets, client.InNamespace(namespace), matchingLabels); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, userSecrets.Items...)
 	}
-	return userSecrets.Items, nil
+	return secrets, nil
 }
 
 // DoGarbageCollection runs the User garbage collector.
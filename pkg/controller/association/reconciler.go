@@ -47,9 +47,13 @@ type AssociationInfo struct {
 	AssociationType commonv1.AssociationType
 	// AssociatedObjTemplate builds an empty typed associated object (eg. &Kibana{} for Kibana to Elasticsearch association).
 	AssociatedObjTemplate func() commonv1.Associated
-	// ElasticsearchRef is a function which returns the maybe transitive Elasticsearch reference (eg. APMServer -> Kibana -> Elasticsearch).
-	// In the case of a transitive reference this is used to create the Elasticsearch user.
-	ElasticsearchRef func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error)
+	// ReferencedObjTemplate builds an empty referenced object (eg. &ElasticMapsServer{} for Kibana to Elastic Maps Server
+	// association). It is only required if ElasticsearchUserCreation is nil, to check the existence of the referenced
+	// resource and to watch it.
+	ReferencedObjTemplate func() client.Object
+	// ElasticsearchUserCreation specifies settings to create an Elasticsearch user as part of the association.
+	// May be nil if no Elasticsearch user is required to connect to the referenced resource.
+	ElasticsearchUserCreation *ElasticsearchUserCreation
 	// AssociatedNamer is used to build the name of the Secret which contains the CA of the target.
 	AssociatedNamer name.Namer
 	// ExternalServiceURL is used to build the external service url as it will be set in the resource configuration.
@@ -67,10 +71,6 @@ type AssociationInfo struct {
 	// the controller which is managing the associated resource to build the appropriate configuration. The annotation
 	// base is used to recognize annotations eligible for removal when association is removed.
 	AssociationConfAnnotationNameBase string
-	// UserSecretSuffix is used as a suffix in the name of the secret holding user data in the associated namespace.
-	UserSecretSuffix string
-	// ESUserRole is the role to use for the Elasticsearch user created by the association.
-	ESUserRole func(commonv1.Associated) (string, error)
 	// SetDynamicWatches allows to set some specific watches.
 	SetDynamicWatches func(associated types.NamespacedName, associations []commonv1.Association, watches watches.DynamicWatches) error
 	// ClearDynamicWatches is called when the controller needs to clear the specific watches set for the associated resource.
@@ -88,6 +88,28 @@ type AssociationInfo struct {
 	AssociationResourceNamespaceLabelName string
 }
 
+// ElasticsearchUserCreation groups the settings required to create an Elasticsearch user for an association.
+type ElasticsearchUserCreation struct {
+	// ElasticsearchRef is a function which returns the maybe transitive Elasticsearch reference (eg. APMServer -> Kibana -> Elasticsearch).
+	// In the case of a transitive reference this is used to create the Elasticsearch user.
+	ElasticsearchRef func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error)
+	// UserSecretSuffix is used as a suffix in the name of the secret holding user data in the associated namespace.
+	UserSecretSuffix string
+	// ESUserRole is the role to use for the Elasticsearch user created by the association.
+	ESUserRole func(commonv1.Associated) (string, error)
+	// AssociatedPodLabelName is the name of the label set to the name of the associated resource on its Pods, which
+	// use the credentials of the association. The credentials replaced by a rotation are deleted once all these Pods
+	// have been recreated.
+	AssociatedPodLabelName string
+	// APIKeySupported is true if the associated resource can connect to Elasticsearch with an API key, created instead
+	// of the Elasticsearch user if the associated resource is annotated with ElasticsearchCredentialsAnnotation.
+	APIKeySupported bool
+	// ServiceAccount returns the name of the Elasticsearch service account (eg. elastic/kibana) the associated resource
+	// authenticates as with a token, instead of the Elasticsearch user. It may be nil, or return an empty name if the
+	// associated resource does not support service accounts, for example because of its version.
+	ServiceAccount func(commonv1.Associated) (string, error)
+}
+
 // AssociationResourceLabels returns all labels required by a resource to allow identifying both its Associated resource
 // (ie. Kibana for Kibana-ES association) and its Association resource (ie. ES for APM-ES association).
 func (a AssociationInfo) AssociationResourceLabels(
@@ -119,9 +141,12 @@ type Reconciler struct {
 	AssociationInfo
 
 	k8s.Client
-	accessReviewer rbac.AccessReviewer
-	recorder       record.EventRecorder
-	watches        watches.DynamicWatches
+	accessReviewer   rbac.AccessReviewer
+	recorder         record.EventRecorder
+	watches          watches.DynamicWatches
+	esClientProvider esClientProvider
+	// credentialsVerifierProvider checks that the rotated credentials are accepted by Elasticsearch
+	credentialsVerifierProvider credentialsVerifierProvider
 	operator.Parameters
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
@@ -178,7 +203,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	associations := associated.GetAssociations()
 
 	// garbage collect leftover resources that are not required anymore
-	if err := deleteOrphanedResources(ctx, r.Client, r.AssociationInfo, associatedKey, associations); err != nil {
+	if err := deleteOrphanedResources(ctx, r.Client, r.AssociationInfo, associatedKey, associations, r.invalidateAPIKeys); err != nil {
 		r.log(associatedKey).Error(err, "Error while trying to delete orphaned resources. Continuing.")
 	}
 
@@ -199,7 +224,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 			continue
 		}
 
-		newStatus, err := r.reconcileAssociation(ctx, association)
+		newStatus, err := r.reconcileAssociation(ctx, association, results)
 		if err != nil {
 			results.WithError(err)
 		}
@@ -217,48 +242,46 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		Aggregate()
 }
 
-func (r *Reconciler) reconcileAssociation(ctx context.Context, association commonv1.Association) (commonv1.AssociationStatus, error) {
-	// retrieve the Elasticsearch resource, since it can be a transitive reference we need to use the provided ElasticsearchRef function
-	associatedResourceFound, esRef, err := r.ElasticsearchRef(r.Client, association)
-	if err != nil {
-		return commonv1.AssociationFailed, err
-	}
-
-	// the associated resource does not exist yet, set status to Pending
-	if !associatedResourceFound {
-		return commonv1.AssociationPending, RemoveAssociationConf(r.Client, association)
-	}
-
-	es, associationStatus, err := r.getElasticsearch(ctx, association, esRef)
-	if associationStatus != "" || err != nil {
-		return associationStatus, err
-	}
-
-	// from this point we have checked that all the associated resources are set and have been found.
-
-	// check if reference to Elasticsearch is allowed to be established
-	if allowed, err := CheckAndUnbind(ctx, r.accessReviewer, association, &es, r, r.recorder); err != nil || !allowed {
-		return commonv1.AssociationPending, err
-	}
-
+func (r *Reconciler) reconcileAssociation(
+	ctx context.Context,
+	association commonv1.Association,
+	results *reconciler.Results,
+) (commonv1.AssociationStatus, error) {
 	associationRef := association.AssociationRef()
+	if associationRef.IsExternal() {
+		// the referenced resource is not managed by the operator, its connection information is read from a secret
+		return r.reconcileUnmanagedAssociation(ctx, association)
+	}
 
-	userRole, err := r.ESUserRole(association.Associated())
-	if err != nil {
-		return commonv1.AssociationFailed, err
+	// the Elasticsearch user is optional, some associations do not require any credentials
+	authSecretRef := corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: commonv1.NoAuthRequiredValue}}
+	var authAPIKey, authServiceAccountToken bool
+	if r.ElasticsearchUserCreation != nil {
+		serviceAccount, err := r.serviceAccount(association)
+		if err != nil {
+			return commonv1.AssociationFailed, err
+		}
+		authSecretKey, status, err := r.reconcileElasticsearchUser(ctx, association, serviceAccount, results)
+		if status != "" || err != nil {
+			return status, err
+		}
+		authSecretRef = corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: secretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix).Name,
+			},
+			Key: authSecretKey,
+		}
+		authServiceAccountToken = serviceAccount != ""
+		authAPIKey = !authServiceAccountToken && r.usesAPIKey(association)
+	} else {
+		status, err := r.checkReferencedResource(ctx, association)
+		if status != "" || err != nil {
+			return status, err
+		}
 	}
 
-	assocLabels := r.AssociationResourceLabels(k8s.ExtractNamespacedName(association.Associated()), association.AssociationRef().NamespacedName())
-	if err := ReconcileEsUser(
-		ctx,
-		r.Client,
-		association,
-		assocLabels,
-		userRole,
-		r.UserSecretSuffix,
-		es,
-	); err != nil {
-		return commonv1.AssociationPending, err
+	if status, err := r.checkReferencedService(ctx, association); status != "" || err != nil {
+		return status, err
 	}
 
 	caSecret, err := r.ReconcileCASecret(
@@ -283,20 +306,155 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 	}
 
 	// construct the expected association configuration
-	authSecretRef := UserSecretKeySelector(association, r.UserSecretSuffix)
 	expectedAssocConf := &commonv1.AssociationConf{
-		AuthSecretName: authSecretRef.Name,
-		AuthSecretKey:  authSecretRef.Key,
-		CACertProvided: caSecret.CACertProvided,
-		CASecretName:   caSecret.Name,
-		URL:            url,
-		Version:        ver,
+		AuthSecretName:          authSecretRef.Name,
+		AuthSecretKey:           authSecretRef.Key,
+		AuthAPIKey:              authAPIKey,
+		AuthServiceAccountToken: authServiceAccountToken,
+		CACertProvided:          caSecret.CACertProvided,
+		CASecretName:            caSecret.Name,
+		URL:                     url,
+		Version:                 ver,
 	}
 
 	// update the association configuration if necessary
 	return r.updateAssocConf(ctx, expectedAssocConf, association)
 }
 
+// reconcileElasticsearchUser retrieves the maybe transitive Elasticsearch reference of the association, and creates
+// the Elasticsearch user, the API key or the token of the given service account the associated resource uses to
+// connect. It returns the key of the credentials in the association secret, or a non-empty status if the association
+// cannot be established yet.
+func (r *Reconciler) reconcileElasticsearchUser(
+	ctx context.Context,
+	association commonv1.Association,
+	serviceAccount string,
+	results *reconciler.Results,
+) (string, commonv1.AssociationStatus, error) {
+	// retrieve the Elasticsearch resource, since it can be a transitive reference we need to use the provided ElasticsearchRef function
+	associatedResourceFound, esRef, err := r.ElasticsearchUserCreation.ElasticsearchRef(r.Client, association)
+	if err != nil {
+		return "", commonv1.AssociationFailed, err
+	}
+
+	// the associated resource does not exist yet, set status to Pending
+	if !associatedResourceFound {
+		return "", commonv1.AssociationPending, RemoveAssociationConf(r.Client, association)
+	}
+
+	es, associationStatus, err := r.getElasticsearch(ctx, association, esRef)
+	if associationStatus != "" || err != nil {
+		return "", associationStatus, err
+	}
+
+	// from this point we have checked that all the associated resources are set and have been found.
+
+	// check if reference to Elasticsearch is allowed to be established
+	if allowed, err := CheckAndUnbind(ctx, r.accessReviewer, association, &es, r, r.recorder); err != nil || !allowed {
+		return "", commonv1.AssociationPending, err
+	}
+
+	assocLabels := r.AssociationResourceLabels(k8s.ExtractNamespacedName(association.Associated()), association.AssociationRef().NamespacedName())
+	verify := r.credentialsVerifierProvider(ctx, r.Client, r.Dialer, es)
+	var credentials associationCredentials
+	if serviceAccount != "" {
+		// invalidate the API keys the association may have used before
+		if err := r.deleteAPIKeys(ctx, association); err != nil {
+			return "", commonv1.AssociationPending, err
+		}
+		credentials, err = r.reconcileServiceAccountToken(ctx, association, assocLabels, serviceAccount, es, verify)
+		if err != nil {
+			return "", commonv1.AssociationPending, err
+		}
+	} else {
+		userRole, err := r.ElasticsearchUserCreation.ESUserRole(association.Associated())
+		if err != nil {
+			return "", commonv1.AssociationFailed, err
+		}
+
+		if r.usesAPIKey(association) {
+			rotateIn, err := r.reconcileAPIKey(ctx, association, assocLabels, userRole, es)
+			if err != nil {
+				return "", commonv1.AssociationPending, err
+			}
+			// requeue to rotate the API key before it expires
+			results.WithResult(reconcile.Result{RequeueAfter: rotateIn})
+			return UserKey(association, es.Namespace, r.ElasticsearchUserCreation.UserSecretSuffix).Name, "", nil
+		}
+
+		// invalidate the API keys the association may have used before
+		if err := r.deleteAPIKeys(ctx, association); err != nil {
+			return "", commonv1.AssociationPending, err
+		}
+		credentials, err = reconcileEsUser(
+			ctx,
+			r.Client,
+			association,
+			assocLabels,
+			userRole,
+			r.ElasticsearchUserCreation.UserSecretSuffix,
+			es,
+			verify,
+		)
+		if err != nil {
+			return "", commonv1.AssociationPending, err
+		}
+	}
+	// requeue to rotate the credentials along with the passwords of the Elasticsearch cluster
+	results.WithResult(reconcile.Result{RequeueAfter: credentials.Rotation.RotateIn})
+
+	obsoleteLeft, err := r.deleteObsoleteCredentials(association, credentials)
+	if err != nil {
+		return "", commonv1.AssociationPending, err
+	}
+	if obsoleteLeft {
+		// requeue to delete the rotated credentials once the associated resource does not use them anymore
+		results.WithResult(reconcile.Result{RequeueAfter: user.RotationCheckInterval})
+	}
+	return credentials.Current, "", nil
+}
+
+// checkReferencedResource checks that the referenced resource exists and that the association is allowed to be
+// established. It returns a non-empty status if the association cannot be established yet.
+func (r *Reconciler) checkReferencedResource(ctx context.Context, association commonv1.Association) (commonv1.AssociationStatus, error) {
+	ref := association.AssociationRef()
+	referenced := r.ReferencedObjTemplate()
+	if err := r.Get(ctx, ref.NamespacedName(), referenced); err != nil {
+		k8s.EmitErrorEvent(r.recorder, err, association, events.EventAssociationError,
+			"Failed to find referenced backend %s: %v", ref.NamespacedName(), err)
+		if apierrors.IsNotFound(err) {
+			// referenced resource not found, remove any existing configuration and retry in a bit
+			return commonv1.AssociationPending, RemoveAssociationConf(r.Client, association)
+		}
+		return commonv1.AssociationFailed, err
+	}
+	if allowed, err := CheckAndUnbind(ctx, r.accessReviewer, association, referenced, r, r.recorder); err != nil || !allowed {
+		return commonv1.AssociationPending, err
+	}
+	return "", nil
+}
+
+// checkReferencedService checks that the service targeted by the association, if any, exists in the namespace of the
+// referenced resource. It returns a non-empty status if the association cannot be established yet.
+func (r *Reconciler) checkReferencedService(ctx context.Context, association commonv1.Association) (commonv1.AssociationStatus, error) {
+	ref := association.AssociationRef()
+	if ref.ServiceName == "" {
+		return "", nil
+	}
+	serviceNSN := types.NamespacedName{Namespace: ref.Namespace, Name: ref.ServiceName}
+	var svc corev1.Service
+	if err := r.Get(ctx, serviceNSN, &svc); err != nil {
+		k8s.EmitErrorEvent(r.recorder, err, association, events.EventAssociationError,
+			"Failed to find referenced service %s: %v", serviceNSN, err)
+		if apierrors.IsNotFound(err) {
+			// service not found, remove any existing configuration and retry in a bit
+			return commonv1.AssociationPending, RemoveAssociationConf(r.Client, association)
+		}
+		return commonv1.AssociationFailed, err
+	}
+	return "", nil
+}
+
 // isCompatible returns true if the given resource can be reconciled by the current controller.
 func (r *Reconciler) isCompatible(ctx context.Context, associated commonv1.Associated) (bool, error) {
 	compat, err := annotation.ReconcileCompatibility(ctx, r.Client, associated, r.Labels(k8s.ExtractNamespacedName(associated)), r.OperatorInfo.BuildInfo.Version)
@@ -345,6 +503,14 @@ func (r *Reconciler) Unbind(association commonv1.Association) error {
 		)); err != nil {
 		return err
 	}
+	// Same for the API keys
+	if err := r.deleteAPIKeys(context.Background(), association); err != nil {
+		return err
+	}
+	// And for the service account tokens
+	if err := r.deleteServiceAccountToken(association); err != nil {
+		return err
+	}
 	// Also remove the association configuration
 	return RemoveAssociationConf(r.Client, association)
 }
@@ -424,7 +590,7 @@ func (r *Reconciler) onDelete(ctx context.Context, associated types.NamespacedNa
 	r.removeWatches(associated)
 
 	// delete user Secret in the Elasticsearch namespace
-	if err := deleteOrphanedResources(ctx, r.Client, r.AssociationInfo, associated, nil); err != nil {
+	if err := deleteOrphanedResources(ctx, r.Client, r.AssociationInfo, associated, nil, r.invalidateAPIKeys); err != nil {
 		r.log(associated).Error(err, "Error while trying to delete orphaned resources. Continuing.")
 	}
 }
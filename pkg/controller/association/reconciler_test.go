@@ -42,10 +42,7 @@ var (
 	// but tests are the same for any resource type.
 	kbAssociationInfo = AssociationInfo{
 		AssociatedObjTemplate: func() commonv1.Associated { return &kbv1.Kibana{} },
-		ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
-			return true, association.AssociationRef(), nil
-		},
-		AssociatedNamer: esv1.ESNamer,
+		AssociatedNamer:       esv1.ESNamer,
 		ExternalServiceURL: func(c k8s.Client, association commonv1.Association) (string, error) {
 			esRef := association.AssociationRef()
 			es := esv1.Elasticsearch{
@@ -61,10 +58,6 @@ var (
 				"kibanaassociation.k8s.elastic.co/namespace": associated.Namespace,
 			}
 		},
-		UserSecretSuffix: "kibana-user",
-		ESUserRole: func(associated commonv1.Associated) (string, error) {
-			return "kibana_system", nil
-		},
 		SetDynamicWatches:   nil,
 		ClearDynamicWatches: nil,
 		ReferencedResourceVersion: func(c k8s.Client, esRef types.NamespacedName) (string, error) {
@@ -78,6 +71,15 @@ var (
 		AssociationConfAnnotationNameBase:     "association.k8s.elastic.co/es-conf",
 		AssociationResourceNameLabelName:      "elasticsearch.k8s.elastic.co/cluster-name",
 		AssociationResourceNamespaceLabelName: "elasticsearch.k8s.elastic.co/cluster-namespace",
+		ElasticsearchUserCreation: &ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix: "kibana-user",
+			ESUserRole: func(associated commonv1.Associated) (string, error) {
+				return "kibana_system", nil
+			},
+		},
 	}
 
 	kibanaNamespace = "kbns"
@@ -213,11 +215,12 @@ func (a denyAllAccessReviewer) AccessAllowed(_ context.Context, _ string, _ stri
 
 func testReconciler(runtimeObjs ...runtime.Object) Reconciler {
 	return Reconciler{
-		AssociationInfo: kbAssociationInfo,
-		Client:          k8s.NewFakeClient(runtimeObjs...),
-		accessReviewer:  rbac.NewPermissiveAccessReviewer(),
-		watches:         watches.NewDynamicWatches(),
-		recorder:        record.NewFakeRecorder(10),
+		AssociationInfo:             kbAssociationInfo,
+		Client:                      k8s.NewFakeClient(runtimeObjs...),
+		accessReviewer:              rbac.NewPermissiveAccessReviewer(),
+		watches:                     watches.NewDynamicWatches(),
+		recorder:                    record.NewFakeRecorder(10),
+		credentialsVerifierProvider: acceptCredentialsProvider,
 		Parameters: operator.Parameters{
 			OperatorInfo: about.OperatorInfo{
 				BuildInfo: about.BuildInfo{
@@ -363,6 +366,25 @@ func TestReconciler_Reconcile_NoES(t *testing.T) {
 	require.Empty(t, updatedKibana.Annotations[kb.AssociationConfAnnotationName()])
 }
 
+func TestReconciler_Reconcile_NoService(t *testing.T) {
+	kb := sampleAssociatedKibana()
+	kb.Spec.ElasticsearchRef.ServiceName = "coordinating-nodes"
+	require.NotEmpty(t, kb.Annotations[kb.AssociationConfAnnotationName()])
+	// the referenced service does not exist
+	r := testReconciler(&kb, &sampleES, &kibanaUserInESNamespace, &kibanaUserInKibanaNamespace, &esHTTPPublicCertsSecret)
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	// should requeue until the service is created
+	require.Equal(t, defaultRequeue, res)
+	// association status should become pending
+	var updatedKibana kbv1.Kibana
+	err = r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana)
+	require.NoError(t, err)
+	require.Equal(t, commonv1.AssociationPending, updatedKibana.Status.AssociationStatus)
+	// association conf should have been removed
+	require.Empty(t, updatedKibana.Annotations[kb.AssociationConfAnnotationName()])
+}
+
 func TestReconciler_Reconcile_RBACNotAllowed(t *testing.T) {
 	kb := sampleAssociatedKibana()
 	require.NotEmpty(t, kb.Annotations[kb.AssociationConfAnnotationName()])
@@ -556,9 +578,6 @@ func TestReconciler_Reconcile_MultiRef(t *testing.T) {
 	agentAssociationInfo := AssociationInfo{
 		AssociationType:       commonv1.ElasticsearchAssociationType,
 		AssociatedObjTemplate: func() commonv1.Associated { return &agentv1alpha1.Agent{} },
-		ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
-			return true, association.AssociationRef(), nil
-		},
 		ReferencedResourceVersion: func(c k8s.Client, esRef types.NamespacedName) (string, error) {
 			var es esv1.Elasticsearch
 			if err := c.Get(context.Background(), esRef, &es); err != nil {
@@ -587,13 +606,18 @@ func TestReconciler_Reconcile_MultiRef(t *testing.T) {
 				"agentassociation.k8s.elastic.co/type":      commonv1.ElasticsearchAssociationType,
 			}
 		},
-		AssociationConfAnnotationNameBase: commonv1.ElasticsearchConfigAnnotationNameBase,
-		UserSecretSuffix:                  "agent-user",
-		ESUserRole: func(associated commonv1.Associated) (string, error) {
-			return "superuser", nil
-		},
+		AssociationConfAnnotationNameBase:     commonv1.ElasticsearchConfigAnnotationNameBase,
 		AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
 		AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		ElasticsearchUserCreation: &ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix: "agent-user",
+			ESUserRole: func(associated commonv1.Associated) (string, error) {
+				return "superuser", nil
+			},
+		},
 	}
 
 	// Agent with two refs
@@ -654,9 +678,10 @@ func TestReconciler_Reconcile_MultiRef(t *testing.T) {
 				},
 			},
 		),
-		accessReviewer: rbac.NewPermissiveAccessReviewer(),
-		watches:        watches.NewDynamicWatches(),
-		recorder:       record.NewFakeRecorder(10),
+		accessReviewer:              rbac.NewPermissiveAccessReviewer(),
+		watches:                     watches.NewDynamicWatches(),
+		recorder:                    record.NewFakeRecorder(10),
+		credentialsVerifierProvider: acceptCredentialsProvider,
 		Parameters: operator.Parameters{
 			OperatorInfo: about.OperatorInfo{
 				BuildInfo: about.BuildInfo{
@@ -14,7 +14,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
@@ -22,12 +24,14 @@ import (
 // attempts. If a user changes namespace on a vertex of an association (eg. reference to an Elasticsearch resource in
 // a different namespace) the standard reconcile mechanism will not delete the now redundant old user object/secret.
 // This function lists all resources that don't match the current name/namespace combinations and deletes them.
+// The API keys tracked by the deleted secrets are invalidated with invalidateAPIKeys.
 func deleteOrphanedResources(
 	ctx context.Context,
 	c k8s.Client,
 	info AssociationInfo,
 	associated types.NamespacedName,
 	associations []commonv1.Association,
+	invalidateAPIKeys func(ctx context.Context, apiKeySecret corev1.Secret) error,
 ) error {
 	span, _ := apm.StartSpan(ctx, "delete_orphaned_resources", tracing.SpanTypeApp)
 	defer span.End()
@@ -48,6 +52,12 @@ func deleteOrphanedResources(
 		}
 
 		// Secret for the `associated` resource doesn't match any `association` - it's not needed anymore and should be deleted.
+		if secret.Labels[common.TypeLabelName] == esuser.AssociatedAPIKeyType {
+			// invalidate the API keys before losing track of them
+			if err := invalidateAPIKeys(ctx, secret); err != nil {
+				return err
+			}
+		}
 		log.Info("Deleting secret", "namespace", secret.Namespace, "secret_name", secret.Name, "associated_name", associated.Name)
 		if err := c.Delete(context.Background(), &secret); err != nil && !apierrors.IsNotFound(err) {
 			return err
@@ -64,7 +74,7 @@ func isSecretForAssociation(info AssociationInfo, secret corev1.Secret, associat
 
 	// grab name from label (eg. elasticsearch.k8s.elastic.co/cluster-name=elasticsearch1 or kibana.k8s.elastic.co/name=kibana1)
 	resourceName, ok := secret.Labels[info.AssociationResourceNameLabelName]
-	if !ok || resourceName != ref.Name {
+	if !ok || resourceName != ref.NameOrSecretName() {
 		// name points to a resource not involved in this `association`
 		return false
 	}
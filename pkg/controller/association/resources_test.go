@@ -56,6 +56,7 @@ func Test_deleteOrphanedResources(t *testing.T) {
 	}
 
 	userSecretLabels := maps.Merge(map[string]string{common.TypeLabelName: esuser.AssociatedUserType}, associationLabels)
+	apiKeySecretLabels := maps.Merge(map[string]string{common.TypeLabelName: esuser.AssociatedAPIKeyType}, associationLabels)
 
 	assertExpectObjectsExist := func(t *testing.T, c k8s.Client) {
 		// user secret should be in ES namespace
@@ -82,12 +83,13 @@ func Test_deleteOrphanedResources(t *testing.T) {
 	}
 
 	tests := []struct {
-		name           string
-		kibana         kbv1.Kibana
-		es             esv1.Elasticsearch
-		initialObjects []runtime.Object
-		postCondition  func(c k8s.Client)
-		wantErr        bool
+		name            string
+		kibana          kbv1.Kibana
+		es              esv1.Elasticsearch
+		initialObjects  []runtime.Object
+		postCondition   func(c k8s.Client)
+		wantInvalidated []string
+		wantErr         bool
 	}{
 		{
 			name: "Do not delete if there's no namespace in the ref",
@@ -305,13 +307,56 @@ func Test_deleteOrphanedResources(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "No more es ref in Kibana, API keys for previous es ref are invalidated",
+			kibana: kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kibana-foo",
+					Namespace: "ns2",
+				},
+			},
+			initialObjects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kibana-foo-kibana-user",
+						Namespace: "ns2",
+						Labels:    associationLabels,
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ns2-kibana-foo-kibana-user",
+						Namespace: "ns1",
+						Labels:    apiKeySecretLabels,
+					},
+				},
+			},
+			postCondition: func(c k8s.Client) {
+				assert.Error(t, c.Get(context.Background(), types.NamespacedName{
+					Namespace: "ns2",
+					Name:      "kibana-foo-kibana-user",
+				}, &corev1.Secret{}))
+				assert.Error(t, c.Get(context.Background(), types.NamespacedName{
+					Namespace: "ns1",
+					Name:      "ns2-kibana-foo-kibana-user",
+				}, &corev1.Secret{}))
+			},
+			wantInvalidated: []string{"ns2-kibana-foo-kibana-user"},
+			wantErr:         false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient(tt.initialObjects...)
-			if err := deleteOrphanedResources(context.Background(), c, info, tt.kibana.AssociationRef().WithDefaultNamespace(tt.kibana.Namespace).NamespacedName(), tt.kibana.GetAssociations()); (err != nil) != tt.wantErr {
+			var invalidated []string
+			invalidateAPIKeys := func(_ context.Context, apiKeySecret corev1.Secret) error {
+				invalidated = append(invalidated, apiKeySecret.Name)
+				return nil
+			}
+			if err := deleteOrphanedResources(context.Background(), c, info, tt.kibana.AssociationRef().WithDefaultNamespace(tt.kibana.Namespace).NamespacedName(), tt.kibana.GetAssociations(), invalidateAPIKeys); (err != nil) != tt.wantErr {
 				t.Errorf("deleteOrphanedResources() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			assert.Equal(t, tt.wantInvalidated, invalidated)
 			if tt.postCondition != nil {
 				tt.postCondition(c)
 			}
@@ -0,0 +1,216 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
+)
+
+// credentialsVerifierProvider returns a verifier of the credentials accepted by all the nodes of an Elasticsearch
+// cluster managed by the operator.
+type credentialsVerifierProvider func(ctx context.Context, c k8s.Client, dialer net.Dialer, es esv1.Elasticsearch) esuser.CredentialsVerifier
+
+// associationCredentials are the credentials of an association held in the association secret, the password of a user
+// or a service account token, indexed by the name of the user or of the token in Elasticsearch. Rotated credentials get
+// a new name, so that Elasticsearch accepts both the current and the new credentials while the associated resource
+// switches to the new ones.
+type associationCredentials struct {
+	// Current is the name of the credentials the associated resource uses.
+	Current string
+	// Pending is the name of the credentials generated by an ongoing rotation, if any. They replace the current ones
+	// once all the Elasticsearch nodes accept them.
+	Pending string
+	// Data holds the current and the pending credentials by name.
+	Data map[string][]byte
+	// Rotation is the rotation of the current credentials.
+	Rotation esuser.PasswordRotation
+}
+
+// credentialsSource generates and checks the credentials of an association.
+type credentialsSource struct {
+	// generate returns new credentials with the given name.
+	generate func(name string) []byte
+	// valid returns true if the given existing credentials with the given name can be reused.
+	valid func(name string, credentials []byte) bool
+	// authorization returns the value of the Authorization header of the requests authenticated with the given credentials.
+	authorization func(name string, credentials []byte) string
+}
+
+// rotatedCredentialsName returns the name of the credentials generated by a rotation started at the given time.
+func rotatedCredentialsName(baseName string, now time.Time) string {
+	return fmt.Sprintf("%s-%d", baseName, now.Unix())
+}
+
+// credentialsGeneration returns the time of the rotation which generated the credentials with the given name, 0 for
+// credentials which were never rotated. It returns false if the name is not derived from the given base name.
+func credentialsGeneration(baseName string, name string) (int64, bool) {
+	if name == baseName {
+		return 0, true
+	}
+	if !strings.HasPrefix(name, baseName+"-") {
+		return 0, false
+	}
+	generation, err := strconv.ParseInt(strings.TrimPrefix(name, baseName+"-"), 10, 64)
+	return generation, err == nil
+}
+
+// credentialsNames returns the names of the current and of the pending credentials held in the given association
+// secret. The pending credentials are the most recent ones, they are only held along with the current ones.
+func credentialsNames(existing corev1.Secret, baseName string) (string, string) {
+	names := make([]string, 0, len(existing.Data))
+	generations := make(map[string]int64, len(existing.Data))
+	for name := range existing.Data {
+		if generation, ok := credentialsGeneration(baseName, name); ok {
+			names = append(names, name)
+			generations[name] = generation
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return generations[names[i]] < generations[names[j]]
+	})
+	switch len(names) {
+	case 0:
+		return "", ""
+	case 1:
+		return names[0], ""
+	default:
+		return names[0], names[len(names)-1]
+	}
+}
+
+// rotateCredentials returns the credentials to be held in the association secret, given the existing secret. New
+// credentials are generated under a new name if the existing ones are due for rotation. They are returned as pending
+// along with the current ones until all the Elasticsearch nodes accept them, and then replace the current ones.
+func rotateCredentials(
+	es esv1.Elasticsearch,
+	existing corev1.Secret,
+	baseName string,
+	source credentialsSource,
+	verify esuser.CredentialsVerifier,
+) (associationCredentials, error) {
+	now := time.Now()
+	current, pending := credentialsNames(existing, baseName)
+	if current == "" || !source.valid(current, existing.Data[current]) {
+		// no credentials to reuse, there is nothing to rotate
+		if current == "" {
+			current = baseName
+		}
+		return associationCredentials{
+			Current:  current,
+			Data:     map[string][]byte{current: source.generate(current)},
+			Rotation: esuser.NewPasswordRotation(es, now),
+		}, nil
+	}
+
+	lastRotation := esuser.PasswordRotationTime(existing)
+	due, rotateIn := esuser.PasswordRotationDue(es, lastRotation, now)
+	credentials := associationCredentials{
+		Current:  current,
+		Data:     map[string][]byte{current: existing.Data[current]},
+		Rotation: esuser.PasswordRotation{Time: lastRotation, RotateIn: rotateIn},
+	}
+
+	switch {
+	case pending != "" && source.valid(pending, existing.Data[pending]):
+		accepted, err := verify(source.authorization(pending, existing.Data[pending]))
+		if err != nil {
+			return associationCredentials{}, err
+		}
+		if !accepted {
+			// keep using the current credentials, and check again soon
+			credentials.Pending = pending
+			credentials.Data[pending] = existing.Data[pending]
+			credentials.Rotation.RotateIn = esuser.RotationCheckInterval
+			return credentials, nil
+		}
+		// switch to the new credentials, the current ones are deleted once the associated resource stops using them
+		return associationCredentials{
+			Current:  pending,
+			Data:     map[string][]byte{pending: existing.Data[pending]},
+			Rotation: esuser.NewPasswordRotation(es, now),
+		}, nil
+	case due:
+		credentials.Pending = rotatedCredentialsName(baseName, now)
+		credentials.Data[credentials.Pending] = source.generate(credentials.Pending)
+		// the associated resource switches to the new credentials once the Elasticsearch nodes accept them
+		credentials.Rotation.RotateIn = esuser.RotationCheckInterval
+	}
+	return credentials, nil
+}
+
+// deleteObsoleteCredentials deletes the users and the service account tokens of the association in the Elasticsearch
+// namespace, other than the given current and pending ones. They are only deleted once all the Pods of the associated
+// resource have been created after the last rotation, as the associated resource may use them until then. It returns
+// true if obsolete credentials are left.
+func (r *Reconciler) deleteObsoleteCredentials(association commonv1.Association, credentials associationCredentials) (bool, error) {
+	associated := k8s.ExtractNamespacedName(association.Associated())
+	var obsolete []corev1.Secret
+	for _, selector := range []client.MatchingLabels{
+		r.userLabelSelector(associated, association.AssociationRef().NamespacedName()),
+		r.serviceAccountTokenLabelSelector(associated, association.AssociationRef().NamespacedName()),
+	} {
+		var secrets corev1.SecretList
+		if err := r.List(context.Background(), &secrets, selector); err != nil {
+			return false, err
+		}
+		for _, secret := range secrets.Items {
+			if _, exists := credentials.Data[secret.Name]; !exists {
+				obsolete = append(obsolete, secret)
+			}
+		}
+	}
+	if len(obsolete) == 0 {
+		return false, nil
+	}
+
+	rolled, err := r.associatedPodsCreatedSince(association.Associated(), credentials.Rotation.Time)
+	if err != nil || !rolled {
+		return true, err
+	}
+	for i := range obsolete {
+		r.log(associated).Info("Deleting rotated credentials", "secret_name", obsolete[i].Name)
+		if err := r.Delete(context.Background(), &obsolete[i]); err != nil && !apierrors.IsNotFound(err) {
+			return true, err
+		}
+	}
+	return false, nil
+}
+
+// associatedPodsCreatedSince returns true if all the Pods of the associated resource have been created since the given
+// time.
+func (r *Reconciler) associatedPodsCreatedSince(associated commonv1.Associated, since time.Time) (bool, error) {
+	podLabelName := r.ElasticsearchUserCreation.AssociatedPodLabelName
+	if podLabelName == "" {
+		return true, nil
+	}
+	var pods corev1.PodList
+	if err := r.List(context.Background(), &pods,
+		client.InNamespace(associated.GetNamespace()),
+		client.MatchingLabels{podLabelName: associated.GetName()},
+	); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if pod.CreationTimestamp.Time.Before(since) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
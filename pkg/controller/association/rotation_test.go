@@ -0,0 +1,222 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
+)
+
+// acceptCredentials is a CredentialsVerifier for a cluster whose nodes accept all the credentials.
+func acceptCredentials(string) (bool, error) {
+	return true, nil
+}
+
+func acceptCredentialsProvider(context.Context, k8s.Client, net.Dialer, esv1.Elasticsearch) esuser.CredentialsVerifier {
+	return acceptCredentials
+}
+
+func Test_credentialsNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string][]byte
+		wantCurrent string
+		wantPending string
+	}{
+		{
+			name: "no credentials",
+			data: map[string][]byte{"other": nil},
+		},
+		{
+			name:        "credentials never rotated",
+			data:        map[string][]byte{"user": nil},
+			wantCurrent: "user",
+		},
+		{
+			name:        "rotated credentials",
+			data:        map[string][]byte{"user-1622628000": nil, "other": nil},
+			wantCurrent: "user-1622628000",
+		},
+		{
+			name:        "ongoing rotation",
+			data:        map[string][]byte{"user-1622714400": nil, "user": nil, "user-other": nil},
+			wantCurrent: "user",
+			wantPending: "user-1622714400",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, pending := credentialsNames(corev1.Secret{Data: tt.data}, "user")
+			require.Equal(t, tt.wantCurrent, current)
+			require.Equal(t, tt.wantPending, pending)
+		})
+	}
+}
+
+func Test_rotateCredentials(t *testing.T) {
+	now := time.Now()
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{esuser.RotatePasswordsAnnotation: now.Add(-time.Minute).UTC().Format(time.RFC3339)},
+	}}
+	rotatedBefore := esuser.PasswordRotation{Time: now.Add(-time.Hour)}.Annotations()
+	rotatedSince := esuser.PasswordRotation{Time: now.Add(-time.Second)}.Annotations()
+	source := credentialsSource{
+		generate: func(name string) []byte {
+			return []byte("generated")
+		},
+		valid: func(name string, credentials []byte) bool {
+			return string(credentials) != "invalid"
+		},
+		authorization: func(name string, credentials []byte) string {
+			return name
+		},
+	}
+	verifier := func(accepted bool) (esuser.CredentialsVerifier, *[]string) {
+		var verified []string
+		return func(authorization string) (bool, error) {
+			verified = append(verified, authorization)
+			return accepted, nil
+		}, &verified
+	}
+
+	t.Run("generate credentials", func(t *testing.T) {
+		verify, verified := verifier(true)
+		credentials, err := rotateCredentials(es, corev1.Secret{}, "user", source, verify)
+		require.NoError(t, err)
+		require.Equal(t, "user", credentials.Current)
+		require.Empty(t, credentials.Pending)
+		require.Equal(t, map[string][]byte{"user": []byte("generated")}, credentials.Data)
+		require.Empty(t, *verified)
+	})
+
+	t.Run("replace invalid credentials", func(t *testing.T) {
+		verify, _ := verifier(true)
+		existing := corev1.Secret{Data: map[string][]byte{"user-1622628000": []byte("invalid")}}
+		credentials, err := rotateCredentials(es, existing, "user", source, verify)
+		require.NoError(t, err)
+		require.Equal(t, "user-1622628000", credentials.Current)
+		require.Equal(t, map[string][]byte{"user-1622628000": []byte("generated")}, credentials.Data)
+	})
+
+	t.Run("reuse credentials not due for rotation", func(t *testing.T) {
+		verify, _ := verifier(true)
+		existing := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Annotations: rotatedSince},
+			Data:       map[string][]byte{"user": []byte("password")},
+		}
+		credentials, err := rotateCredentials(es, existing, "user", source, verify)
+		require.NoError(t, err)
+		require.Equal(t, "user", credentials.Current)
+		require.Empty(t, credentials.Pending)
+		require.Equal(t, existing.Data, credentials.Data)
+	})
+
+	t.Run("generate pending credentials under a new name", func(t *testing.T) {
+		verify, verified := verifier(true)
+		existing := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Annotations: rotatedBefore},
+			Data:       map[string][]byte{"user": []byte("password")},
+		}
+		credentials, err := rotateCredentials(es, existing, "user", source, verify)
+		require.NoError(t, err)
+		require.Equal(t, "user", credentials.Current)
+		generation, ok := credentialsGeneration("user", credentials.Pending)
+		require.True(t, ok)
+		require.NotZero(t, generation)
+		require.Equal(t, map[string][]byte{"user": []byte("password"), credentials.Pending: []byte("generated")}, credentials.Data)
+		require.Equal(t, esuser.RotationCheckInterval, credentials.Rotation.RotateIn)
+		require.Empty(t, *verified)
+	})
+
+	existing := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Annotations: rotatedBefore},
+		Data:       map[string][]byte{"user": []byte("password"), "user-1622714400": []byte("new-password")},
+	}
+
+	t.Run("keep the current credentials until the pending ones are accepted", func(t *testing.T) {
+		verify, verified := verifier(false)
+		credentials, err := rotateCredentials(es, existing, "user", source, verify)
+		require.NoError(t, err)
+		require.Equal(t, "user", credentials.Current)
+		require.Equal(t, "user-1622714400", credentials.Pending)
+		require.Equal(t, existing.Data, credentials.Data)
+		require.Equal(t, esuser.RotationCheckInterval, credentials.Rotation.RotateIn)
+		require.Equal(t, []string{"user-1622714400"}, *verified)
+	})
+
+	t.Run("switch to the pending credentials once accepted", func(t *testing.T) {
+		verify, verified := verifier(true)
+		credentials, err := rotateCredentials(es, existing, "user", source, verify)
+		require.NoError(t, err)
+		require.Equal(t, "user-1622714400", credentials.Current)
+		require.Empty(t, credentials.Pending)
+		require.Equal(t, map[string][]byte{"user-1622714400": []byte("new-password")}, credentials.Data)
+		require.False(t, credentials.Rotation.Time.Before(now.Truncate(time.Second)))
+		require.Equal(t, []string{"user-1622714400"}, *verified)
+	})
+}
+
+func TestReconciler_deleteObsoleteCredentials(t *testing.T) {
+	rotationTime := time.Date(2021, 6, 3, 10, 0, 0, 0, time.UTC)
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{ElasticsearchRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"}},
+	}
+	current := types.NamespacedName{Namespace: "es-ns", Name: "kb-ns-kb-kibana-user-1622714400"}
+	obsolete := types.NamespacedName{Namespace: "es-ns", Name: "kb-ns-kb-kibana-user"}
+	credentials := associationCredentials{
+		Current:  current.Name,
+		Data:     map[string][]byte{current.Name: []byte("password")},
+		Rotation: esuser.PasswordRotation{Time: rotationTime},
+	}
+
+	newReconciler := func(podCreationTime time.Time) *Reconciler {
+		r := serviceAccountTestReconciler()
+		r.ElasticsearchUserCreation.AssociatedPodLabelName = "kibana.k8s.elastic.co/name"
+		labels := r.userLabelSelector(k8s.ExtractNamespacedName(kb), kb.AssociationRef().NamespacedName())
+		r.Client = k8s.NewFakeClient(
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: current.Namespace, Name: current.Name, Labels: labels}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: obsolete.Namespace, Name: obsolete.Name, Labels: labels}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace:         kb.Namespace,
+				Name:              "kb-pod",
+				Labels:            map[string]string{"kibana.k8s.elastic.co/name": kb.Name},
+				CreationTimestamp: metav1.Time{Time: podCreationTime},
+			}},
+		)
+		return r
+	}
+
+	t.Run("keep the obsolete credentials while Pods may use them", func(t *testing.T) {
+		r := newReconciler(rotationTime.Add(-time.Hour))
+		left, err := r.deleteObsoleteCredentials(kb, credentials)
+		require.NoError(t, err)
+		require.True(t, left)
+		require.NoError(t, r.Get(context.Background(), obsolete, &corev1.Secret{}))
+	})
+
+	t.Run("delete the obsolete credentials once the Pods are recreated", func(t *testing.T) {
+		r := newReconciler(rotationTime.Add(time.Minute))
+		left, err := r.deleteObsoleteCredentials(kb, credentials)
+		require.NoError(t, err)
+		require.False(t, left)
+		require.True(t, apierrors.IsNotFound(r.Get(context.Background(), obsolete, &corev1.Secret{})))
+		require.NoError(t, r.Get(context.Background(), current, &corev1.Secret{}))
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"strings"
+
+	"go.elastic.co/apm"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+)
+
+// ServiceAccountMinVersion is the minimal version of the Elastic Stack from which Kibana and Fleet Server authenticate
+// to Elasticsearch with a service account token rather than with a user of the file realm.
+var ServiceAccountMinVersion = version.From(8, 0, 0)
+
+// ServiceAccountForVersion returns the given service account if service accounts are supported by the given version
+// of the associated resource, or an empty string otherwise.
+func ServiceAccountForVersion(ver string, serviceAccount string) (string, error) {
+	v, err := version.Parse(ver)
+	if err != nil {
+		return "", err
+	}
+	if !v.GTE(ServiceAccountMinVersion) {
+		return "", nil
+	}
+	return serviceAccount, nil
+}
+
+// serviceAccount returns the name of the service account the associated resource authenticates as, or an empty string
+// if it uses an Elasticsearch user or an API key.
+func (r *Reconciler) serviceAccount(association commonv1.Association) (string, error) {
+	if r.ElasticsearchUserCreation == nil || r.ElasticsearchUserCreation.ServiceAccount == nil {
+		return "", nil
+	}
+	return r.ElasticsearchUserCreation.ServiceAccount(association.Associated())
+}
+
+// serviceAccountTokenName returns the name of the token of the association, given the name of the secret holding its
+// hash. Unlike the names of the Kubernetes resources, the names of the service account tokens cannot contain dots.
+func serviceAccountTokenName(secretName string) string {
+	return strings.ReplaceAll(secretName, ".", "_")
+}
+
+// serviceAccountTokenLabelSelector returns labels selecting the secret holding the hash of the service account token
+// of an association.
+func (a AssociationInfo) serviceAccountTokenLabelSelector(
+	associated types.NamespacedName,
+	association types.NamespacedName,
+) client.MatchingLabels {
+	return maps.Merge(
+		map[string]string{common.TypeLabelName: esuser.ServiceAccountTokenType},
+		a.AssociationResourceLabels(associated, association),
+	)
+}
+
+// reconcileServiceAccountToken creates a token of the given service account for the association, and stores it in the
+// association secret in the namespace of the associated resource. Its hash is stored in a secret in the namespace of
+// the Elasticsearch cluster, from which the Elasticsearch controller builds the service_tokens file. Tokens are rotated
+// along with the passwords of the Elasticsearch cluster under a new name: the new token replaces the current one in the
+// association secret once all the Elasticsearch nodes accept it. It returns the tokens held in the association secret.
+func (r *Reconciler) reconcileServiceAccountToken(
+	ctx context.Context,
+	association commonv1.Association,
+	labels map[string]string,
+	serviceAccount string,
+	es esv1.Elasticsearch,
+	verify esuser.CredentialsVerifier,
+) (associationCredentials, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_service_account_token", tracing.SpanTypeApp)
+	defer span.End()
+
+	// Add the Elasticsearch name, this is only intended to help the user to filter on these resources
+	labels[eslabel.ClusterNameLabelName] = es.Name
+
+	secKey := secretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix)
+	usrKey := UserKey(association, es.Namespace, r.ElasticsearchUserCreation.UserSecretSuffix)
+
+	// reuse the existing tokens if valid and not due for rotation, the association secret may hold the password of a user
+	var existingSecret corev1.Secret
+	if err := r.Get(context.Background(), secKey, &existingSecret); err != nil && !apierrors.IsNotFound(err) {
+		return associationCredentials{}, err
+	}
+	credentials, err := rotateCredentials(es, existingSecret, usrKey.Name, credentialsSource{
+		generate: func(name string) []byte {
+			return esuser.NewServiceAccountToken(serviceAccount, serviceAccountTokenName(name)).Bearer()
+		},
+		valid: func(name string, bearer []byte) bool {
+			token, err := esuser.ParseServiceAccountToken(bearer)
+			return err == nil && token.ServiceAccount == serviceAccount && token.Name == serviceAccountTokenName(name)
+		},
+		authorization: func(_ string, bearer []byte) string {
+			return esclient.BearerAuthorization(bearer)
+		},
+	}, verify)
+	if err != nil {
+		return associationCredentials{}, err
+	}
+
+	// the tokens must exist in Elasticsearch before the associated resource uses them
+	for name, bearer := range credentials.Data {
+		token, err := esuser.ParseServiceAccountToken(bearer)
+		if err != nil {
+			return associationCredentials{}, err
+		}
+		if err := r.reconcileServiceAccountTokenSecret(types.NamespacedName{Namespace: usrKey.Namespace, Name: name}, labels, token, es); err != nil {
+			return associationCredentials{}, err
+		}
+	}
+
+	expectedSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secKey.Name,
+			Namespace:   secKey.Namespace,
+			Labels:      common.AddCredentialsLabel(labels),
+			Annotations: credentials.Rotation.Annotations(),
+		},
+		Data: credentials.Data,
+	}
+	_, err = reconciler.ReconcileSecret(r.Client, expectedSecret, association.Associated())
+	return credentials, err
+}
+
+// reconcileServiceAccountTokenSecret reconciles the secret in the Elasticsearch namespace holding the hash of the given
+// token, reusing the existing hash if valid.
+func (r *Reconciler) reconcileServiceAccountTokenSecret(
+	tokenKey types.NamespacedName,
+	labels map[string]string,
+	token esuser.ServiceAccountToken,
+	es esv1.Elasticsearch,
+) error {
+	var existingTokenSecret corev1.Secret
+	if err := r.Get(context.Background(), tokenKey, &existingTokenSecret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	hash := existingTokenSecret.Data[esuser.ServiceAccountTokenHashField]
+	if !token.MatchesHash(hash) {
+		hash = token.Hash()
+	}
+
+	// the secret holding the hash of the token replaces the file realm user the association may have used before
+	expectedTokenSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tokenKey.Name,
+			Namespace: tokenKey.Namespace,
+			Labels:    maps.Merge(esuser.ServiceAccountTokenLabels(es), labels),
+		},
+		Data: map[string][]byte{
+			esuser.ServiceAccountField:          []byte(token.ServiceAccount),
+			esuser.ServiceAccountTokenNameField: []byte(token.Name),
+			esuser.ServiceAccountTokenHashField: hash,
+		},
+	}
+	owner := es // the token is owned by the es resource in es namespace
+	_, err := reconciler.ReconcileSecret(r.Client, expectedTokenSecret, &owner)
+	return err
+}
+
+// deleteServiceAccountToken deletes the secrets holding the hashes of the service account tokens of the association, if
+// any. The tokens are revoked once the Elasticsearch controller removes them from the service_tokens file.
+func (r *Reconciler) deleteServiceAccountToken(association commonv1.Association) error {
+	return k8s.DeleteSecretMatching(
+		r.Client,
+		r.serviceAccountTokenLabelSelector(
+			k8s.ExtractNamespacedName(association),
+			association.AssociationRef().NamespacedName(),
+		))
+}
@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func TestServiceAccountForVersion(t *testing.T) {
+	for ver, want := range map[string]string{
+		"7.15.0":         "",
+		"8.0.0":          "elastic/kibana",
+		"8.1.2":          "elastic/kibana",
+		"8.0.0-SNAPSHOT": "",
+		"not-a-version":  "",
+	} {
+		got, err := ServiceAccountForVersion(ver, "elastic/kibana")
+		require.Equal(t, ver == "not-a-version", err != nil, ver)
+		require.Equal(t, want, got, ver)
+	}
+}
+
+func serviceAccountTestReconciler(objs ...runtime.Object) *Reconciler {
+	return &Reconciler{
+		AssociationInfo: AssociationInfo{
+			AssociationType: commonv1.ElasticsearchAssociationType,
+			ElasticsearchUserCreation: &ElasticsearchUserCreation{
+				UserSecretSuffix: "kibana-user",
+			},
+			Labels: func(associated types.NamespacedName) map[string]string {
+				return map[string]string{"kibanaassociation.k8s.elastic.co/name": associated.Name}
+			},
+			AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
+			AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+		},
+		Client:                      k8s.NewFakeClient(objs...),
+		credentialsVerifierProvider: acceptCredentialsProvider,
+		logger:                      log.WithName("test"),
+	}
+}
+
+func TestReconciler_reconcileServiceAccountToken(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "es-ns", Name: "es"}}
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+		Spec: kbv1.KibanaSpec{
+			Version:          "8.0.0",
+			ElasticsearchRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"},
+		},
+	}
+	secKey := secretKey(kb, "kibana-user")
+	usrKey := UserKey(kb, es.Namespace, "kibana-user")
+
+	reconcileToken := func(r *Reconciler) (esuser.ServiceAccountToken, corev1.Secret) {
+		credentials, err := r.reconcileServiceAccountToken(context.Background(), kb, map[string]string{}, "elastic/kibana", es, acceptCredentials)
+		require.NoError(t, err)
+		require.Equal(t, usrKey.Name, credentials.Current)
+
+		var secret corev1.Secret
+		require.NoError(t, r.Get(context.Background(), secKey, &secret))
+		token, err := esuser.ParseServiceAccountToken(secret.Data[usrKey.Name])
+		require.NoError(t, err)
+		require.Equal(t, "elastic/kibana", token.ServiceAccount)
+		require.Equal(t, usrKey.Name, token.Name)
+
+		// the hash of the token is stored in the namespace of Elasticsearch
+		var tokenSecret corev1.Secret
+		require.NoError(t, r.Get(context.Background(), usrKey, &tokenSecret))
+		require.Equal(t, esuser.ServiceAccountTokenType, tokenSecret.Labels[common.TypeLabelName])
+		require.Equal(t, es.Name, tokenSecret.Labels[eslabel.ClusterNameLabelName])
+		require.Equal(t, "elastic/kibana", string(tokenSecret.Data[esuser.ServiceAccountField]))
+		require.Equal(t, usrKey.Name, string(tokenSecret.Data[esuser.ServiceAccountTokenNameField]))
+		require.True(t, token.MatchesHash(tokenSecret.Data[esuser.ServiceAccountTokenHashField]))
+		return token, tokenSecret
+	}
+
+	t.Run("create and reuse a token", func(t *testing.T) {
+		r := serviceAccountTestReconciler(kb, &es)
+		token, tokenSecret := reconcileToken(r)
+
+		// the token and its hash are reused
+		reusedToken, reusedTokenSecret := reconcileToken(r)
+		require.Equal(t, token, reusedToken)
+		require.Equal(t, tokenSecret.Data, reusedTokenSecret.Data)
+	})
+
+	t.Run("replace a file realm user by a token", func(t *testing.T) {
+		r := serviceAccountTestReconciler(kb, &es,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: secKey.Namespace, Name: secKey.Name},
+				Data:       map[string][]byte{usrKey.Name: []byte("password")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: usrKey.Namespace,
+					Name:      usrKey.Name,
+					Labels:    esuser.AssociatedUserLabels(es),
+				},
+				Data: map[string][]byte{
+					esuser.UserNameField:     []byte(usrKey.Name),
+					esuser.PasswordHashField: []byte("hash"),
+					esuser.UserRolesField:    []byte("kibana_system"),
+				},
+			},
+		)
+		_, tokenSecret := reconcileToken(r)
+		require.NotContains(t, tokenSecret.Data, esuser.PasswordHashField)
+	})
+}
+
+func TestReconciler_deleteServiceAccountToken(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "es-ns", Name: "es"}}
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kb-ns", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{ElasticsearchRef: commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"}},
+	}
+	usrKey := UserKey(kb, es.Namespace, "kibana-user")
+	r := serviceAccountTestReconciler(kb, &es)
+	require.NoError(t, r.Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: usrKey.Namespace,
+			Name:      usrKey.Name,
+			Labels: r.serviceAccountTokenLabelSelector(
+				k8s.ExtractNamespacedName(kb),
+				kb.AssociationRef().NamespacedName(),
+			),
+		},
+	}))
+
+	require.NoError(t, r.deleteServiceAccountToken(kb))
+	err := r.Get(context.Background(), usrKey, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+}
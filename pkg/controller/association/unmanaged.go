@@ -0,0 +1,192 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// UnmanagedURLKey is the key of the URL of the referenced resource in the secret describing it.
+	UnmanagedURLKey = "url"
+	// UnmanagedUsernameKey is the key of the name of the user to authenticate with in the secret describing the
+	// referenced resource.
+	UnmanagedUsernameKey = "username"
+	// UnmanagedPasswordKey is the key of the password of the user in the secret describing the referenced resource.
+	UnmanagedPasswordKey = "password"
+	// UnmanagedAPIKeyKey is the key of the API key to authenticate with, encoded as id:api_key, in the secret
+	// describing the referenced resource.
+	UnmanagedAPIKeyKey = "api-key"
+)
+
+// UnmanagedAssociationConnectionInfo holds the information to connect to a referenced resource not managed by the
+// operator, read from the secret describing it.
+type UnmanagedAssociationConnectionInfo struct {
+	URL      string
+	Username string
+	Password string
+	APIKey   string
+	CACert   []byte
+}
+
+// GetUnmanagedAssociationConnectionInfoFromSecret returns the connection information stored in the secret referenced
+// by an association to a resource not managed by the operator.
+func GetUnmanagedAssociationConnectionInfoFromSecret(c k8s.Client, ref commonv1.ObjectSelector) (UnmanagedAssociationConnectionInfo, error) {
+	var secret corev1.Secret
+	if err := c.Get(context.Background(), ref.NamespacedName(), &secret); err != nil {
+		return UnmanagedAssociationConnectionInfo{}, err
+	}
+	return UnmanagedAssociationConnectionInfo{
+		URL:      string(secret.Data[UnmanagedURLKey]),
+		Username: string(secret.Data[UnmanagedUsernameKey]),
+		Password: string(secret.Data[UnmanagedPasswordKey]),
+		APIKey:   string(secret.Data[UnmanagedAPIKeyKey]),
+		CACert:   secret.Data[certificates.CAFileName],
+	}, nil
+}
+
+// Validate checks that the connection information contains a valid URL, and either a username and a password or an
+// API key.
+func (i UnmanagedAssociationConnectionInfo) Validate() error {
+	if i.URL == "" {
+		return fmt.Errorf("%s is required", UnmanagedURLKey)
+	}
+	if u, err := url.Parse(i.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute URL", UnmanagedURLKey)
+	}
+	hasUser := i.Username != "" || i.Password != ""
+	switch {
+	case hasUser && i.APIKey != "":
+		return fmt.Errorf("%s cannot be specified along with %s and %s", UnmanagedAPIKeyKey, UnmanagedUsernameKey, UnmanagedPasswordKey)
+	case i.APIKey == "" && (i.Username == "" || i.Password == ""):
+		return fmt.Errorf("%s and %s, or %s, are required", UnmanagedUsernameKey, UnmanagedPasswordKey, UnmanagedAPIKeyKey)
+	}
+	// the username is used as a key of the user secret of the association
+	if msgs := validation.IsConfigMapKey(i.Username); hasUser && len(msgs) > 0 {
+		return fmt.Errorf("invalid %s: %s", UnmanagedUsernameKey, strings.Join(msgs, ", "))
+	}
+	if len(i.CACert) > 0 {
+		certs, err := certificates.ParsePEMCerts(i.CACert)
+		if err != nil {
+			return errors.Wrapf(err, "cannot parse %s", certificates.CAFileName)
+		}
+		if len(certs) == 0 {
+			return fmt.Errorf("no certificate found in %s", certificates.CAFileName)
+		}
+	}
+	return nil
+}
+
+// reconcileUnmanagedAssociation establishes an association with a resource not managed by the operator, from the
+// connection information stored in the referenced secret. The credentials and the CA certificate are copied to the
+// secrets usually created for the association, so that the associated resource does not need to distinguish the
+// referenced resources managed by the operator from the others. The version of the referenced resource is unknown.
+func (r *Reconciler) reconcileUnmanagedAssociation(ctx context.Context, association commonv1.Association) (commonv1.AssociationStatus, error) {
+	ref := association.AssociationRef()
+	if r.ElasticsearchUserCreation == nil {
+		err := fmt.Errorf("a secret cannot be used to reference a resource of type %s", r.AssociationType)
+		k8s.EmitErrorEvent(r.recorder, err, association, events.EventAssociationError, err.Error())
+		return commonv1.AssociationFailed, RemoveAssociationConf(r.Client, association)
+	}
+
+	info, err := GetUnmanagedAssociationConnectionInfoFromSecret(r.Client, ref)
+	if err != nil {
+		k8s.EmitErrorEvent(r.recorder, err, association, events.EventAssociationError,
+			"Failed to find referenced secret %s: %v", ref.NamespacedName(), err)
+		if apierrors.IsNotFound(err) {
+			// secret not found, remove any existing configuration and retry in a bit
+			return commonv1.AssociationPending, RemoveAssociationConf(r.Client, association)
+		}
+		return commonv1.AssociationFailed, err
+	}
+	if err := info.Validate(); err != nil {
+		// the secret is watched, the association is reconciled again once it is fixed
+		k8s.EmitErrorEvent(r.recorder, err, association, events.EventAssociationError,
+			"Invalid connection information in secret %s: %v", ref.NamespacedName(), err)
+		return commonv1.AssociationFailed, RemoveAssociationConf(r.Client, association)
+	}
+
+	authSecretRef, err := r.reconcileUnmanagedAuthSecret(association, info)
+	if err != nil {
+		return commonv1.AssociationPending, err
+	}
+	caSecret, err := r.reconcileUnmanagedCASecret(association, info)
+	if err != nil {
+		return commonv1.AssociationPending, err
+	}
+
+	expectedAssocConf := &commonv1.AssociationConf{
+		AuthSecretName: authSecretRef.Name,
+		AuthSecretKey:  authSecretRef.Key,
+		AuthAPIKey:     info.APIKey != "",
+		CACertProvided: caSecret.CACertProvided,
+		CASecretName:   caSecret.Name,
+		URL:            info.URL,
+	}
+	return r.updateAssocConf(ctx, expectedAssocConf, association)
+}
+
+// reconcileUnmanagedAuthSecret copies the credentials to connect to a resource not managed by the operator into the
+// user secret of the association, under the name of the user, or under UnmanagedAPIKeyKey for an API key.
+func (r *Reconciler) reconcileUnmanagedAuthSecret(association commonv1.Association, info UnmanagedAssociationConnectionInfo) (corev1.SecretKeySelector, error) {
+	key, value := info.Username, info.Password
+	if info.APIKey != "" {
+		key, value = UnmanagedAPIKeyKey, info.APIKey
+	}
+	secKey := secretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix)
+	expectedSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secKey.Name,
+			Namespace: secKey.Namespace,
+			Labels: common.AddCredentialsLabel(
+				r.AssociationResourceLabels(k8s.ExtractNamespacedName(association), association.AssociationRef().NamespacedName()),
+			),
+		},
+		Data: map[string][]byte{key: []byte(value)},
+	}
+	if _, err := reconciler.ReconcileSecret(r, expectedSecret, association.Associated()); err != nil {
+		return corev1.SecretKeySelector{}, err
+	}
+	return corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secKey.Name}, Key: key}, nil
+}
+
+// reconcileUnmanagedCASecret copies the CA certificate of a resource not managed by the operator, if any, into the
+// CA secret of the association.
+func (r *Reconciler) reconcileUnmanagedCASecret(association commonv1.Association, info UnmanagedAssociationConnectionInfo) (CASecret, error) {
+	if len(info.CACert) == 0 {
+		return CASecret{}, nil
+	}
+	expectedSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: association.GetNamespace(),
+			Name:      CACertSecretName(association, r.AssociationName),
+			Labels:    r.AssociationResourceLabels(k8s.ExtractNamespacedName(association), association.AssociationRef().NamespacedName()),
+		},
+		// some associated resources trust the certificate stored under tls.crt rather than the CA
+		Data: map[string][]byte{
+			certificates.CAFileName:   info.CACert,
+			certificates.CertFileName: info.CACert,
+		},
+	}
+	if _, err := reconciler.ReconcileSecret(r, expectedSecret, association.Associated()); err != nil {
+		return CASecret{}, err
+	}
+	return CASecret{Name: expectedSecret.Name, CACertProvided: true}, nil
+}
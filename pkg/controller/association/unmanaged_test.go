@@ -0,0 +1,171 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func testCACert(t *testing.T) []byte {
+	t.Helper()
+	ca, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{})
+	require.NoError(t, err)
+	return certificates.EncodePEMCert(ca.Cert.Raw)
+}
+
+func TestUnmanagedAssociationConnectionInfo_Validate(t *testing.T) {
+	caCert := testCACert(t)
+	tests := []struct {
+		name    string
+		info    UnmanagedAssociationConnectionInfo
+		wantErr bool
+	}{
+		{
+			name: "username and password",
+			info: UnmanagedAssociationConnectionInfo{URL: "https://es.example.com:9200", Username: "elastic", Password: "changeme"},
+		},
+		{
+			name: "API key and CA certificate",
+			info: UnmanagedAssociationConnectionInfo{URL: "https://es.example.com:9200", APIKey: "id:key", CACert: caCert},
+		},
+		{
+			name:    "no URL",
+			info:    UnmanagedAssociationConnectionInfo{Username: "elastic", Password: "changeme"},
+			wantErr: true,
+		},
+		{
+			name:    "relative URL",
+			info:    UnmanagedAssociationConnectionInfo{URL: "es.example.com", Username: "elastic", Password: "changeme"},
+			wantErr: true,
+		},
+		{
+			name:    "no credentials",
+			info:    UnmanagedAssociationConnectionInfo{URL: "https://es.example.com:9200"},
+			wantErr: true,
+		},
+		{
+			name:    "no password",
+			info:    UnmanagedAssociationConnectionInfo{URL: "https://es.example.com:9200", Username: "elastic"},
+			wantErr: true,
+		},
+		{
+			name:    "API key along with a username and a password",
+			info:    UnmanagedAssociationConnectionInfo{URL: "https://es.example.com:9200", Username: "elastic", Password: "changeme", APIKey: "id:key"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid username",
+			info:    UnmanagedAssociationConnectionInfo{URL: "https://es.example.com:9200", Username: "john doe", Password: "changeme"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid CA certificate",
+			info:    UnmanagedAssociationConnectionInfo{URL: "https://es.example.com:9200", Username: "elastic", Password: "changeme", CACert: []byte("not a certificate")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.info.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func sampleKibanaWithExternalESRef() kbv1.Kibana {
+	kb := sampleKibanaNoEsRef()
+	kb.Spec = kbv1.KibanaSpec{ElasticsearchRef: commonv1.ObjectSelector{SecretName: "external-es"}}
+	return kb
+}
+
+func TestReconciler_Reconcile_UnmanagedElasticsearch(t *testing.T) {
+	caCert := testCACert(t)
+	kb := sampleKibanaWithExternalESRef()
+	externalES := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kibanaNamespace, Name: "external-es"},
+		Data: map[string][]byte{
+			"url":      []byte("https://es.example.com:9200"),
+			"username": []byte("kibana-user"),
+			"password": []byte("changeme"),
+			"ca.crt":   caCert,
+		},
+	}
+	r := testReconciler(&kb, &externalES)
+	results, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, results)
+
+	// the credentials should be copied to the user secret of the association
+	var userSecret corev1.Secret
+	err = r.Get(context.Background(), k8s.ExtractNamespacedName(&kibanaUserInKibanaNamespace), &userSecret)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"kibana-user": []byte("changeme")}, userSecret.Data)
+
+	// the CA certificate should be copied to the CA secret of the association
+	var caSecret corev1.Secret
+	err = r.Get(context.Background(), k8s.ExtractNamespacedName(&esCertsInKibanaNamespace), &caSecret)
+	require.NoError(t, err)
+	require.Equal(t, caCert, caSecret.Data[certificates.CAFileName])
+
+	// the secret describing the cluster should be watched
+	require.Contains(t, r.watches.Secrets.Registrations(), unmanagedSecretWatchName(k8s.ExtractNamespacedName(&kb)))
+
+	var updatedKibana kbv1.Kibana
+	err = r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana)
+	require.NoError(t, err)
+	require.Equal(t, commonv1.AssociationEstablished, updatedKibana.Status.AssociationStatus)
+	var assocConf commonv1.AssociationConf
+	require.NoError(t, json.Unmarshal([]byte(updatedKibana.Annotations[kb.AssociationConfAnnotationName()]), &assocConf))
+	require.Equal(t, commonv1.AssociationConf{
+		AuthSecretName: "kbname-kibana-user",
+		AuthSecretKey:  "kibana-user",
+		CACertProvided: true,
+		CASecretName:   "kbname-kb-es-ca",
+		URL:            "https://es.example.com:9200",
+	}, assocConf)
+}
+
+func TestReconciler_Reconcile_UnmanagedElasticsearch_NoSecret(t *testing.T) {
+	kb := sampleKibanaWithExternalESRef()
+	r := testReconciler(&kb)
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	// should requeue until the secret is created
+	require.Equal(t, defaultRequeue, res)
+	var updatedKibana kbv1.Kibana
+	err = r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana)
+	require.NoError(t, err)
+	require.Equal(t, commonv1.AssociationPending, updatedKibana.Status.AssociationStatus)
+	require.Empty(t, updatedKibana.Annotations[kb.AssociationConfAnnotationName()])
+}
+
+func TestReconciler_Reconcile_UnmanagedElasticsearch_InvalidSecret(t *testing.T) {
+	kb := sampleKibanaWithExternalESRef()
+	externalES := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: kibanaNamespace, Name: "external-es"},
+		Data:       map[string][]byte{"url": []byte("https://es.example.com:9200")},
+	}
+	r := testReconciler(&kb, &externalES)
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&kb)})
+	require.NoError(t, err)
+	var updatedKibana kbv1.Kibana
+	err = r.Get(context.Background(), k8s.ExtractNamespacedName(&kb), &updatedKibana)
+	require.NoError(t, err)
+	require.Equal(t, commonv1.AssociationFailed, updatedKibana.Status.AssociationStatus)
+	require.Empty(t, updatedKibana.Annotations[kb.AssociationConfAnnotationName()])
+}
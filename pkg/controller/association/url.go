@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ServiceURL returns the URL used to reach the referenced resource through the given service, which must exist.
+// The port of the service is the one named after the protocol, or its only port.
+func ServiceURL(c k8s.Client, serviceNSN types.NamespacedName, protocol string) (string, error) {
+	var svc corev1.Service
+	if err := c.Get(context.Background(), serviceNSN, &svc); err != nil {
+		return "", err
+	}
+	port, err := servicePort(svc, protocol)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s://%s.%s.svc:%d", protocol, svc.Name, svc.Namespace, port), nil
+}
+
+func servicePort(svc corev1.Service, protocol string) (int32, error) {
+	for _, port := range svc.Spec.Ports {
+		if port.Name == protocol {
+			return port.Port, nil
+		}
+	}
+	if len(svc.Spec.Ports) == 1 {
+		return svc.Spec.Ports[0].Port, nil
+	}
+	return 0, fmt.Errorf("cannot find a port named %s in service %s/%s", protocol, svc.Namespace, svc.Name)
+}
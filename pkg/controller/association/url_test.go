@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package association
+
+import (
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestServiceURL(t *testing.T) {
+	serviceNSN := types.NamespacedName{Namespace: "ns", Name: "coordinating"}
+	tests := []struct {
+		name     string
+		ports    []corev1.ServicePort
+		protocol string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "port named after the protocol",
+			ports:    []corev1.ServicePort{{Name: "metrics", Port: 9100}, {Name: "https", Port: 9243}},
+			protocol: "https",
+			want:     "https://coordinating.ns.svc:9243",
+		},
+		{
+			name:     "single port",
+			ports:    []corev1.ServicePort{{Name: "custom", Port: 9200}},
+			protocol: "http",
+			want:     "http://coordinating.ns.svc:9200",
+		},
+		{
+			name:     "no port for the protocol",
+			ports:    []corev1.ServicePort{{Name: "metrics", Port: 9100}, {Name: "custom", Port: 9200}},
+			protocol: "https",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: serviceNSN.Namespace, Name: serviceNSN.Name},
+				Spec:       corev1.ServiceSpec{Ports: tt.ports},
+			})
+			got, err := ServiceURL(c, serviceNSN, tt.protocol)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("service does not exist", func(t *testing.T) {
+		_, err := ServiceURL(k8s.NewFakeClient(), serviceNSN, "https")
+		require.Error(t, err)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	eslabel "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	esuser "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -62,18 +63,11 @@ func secretKey(association commonv1.Association, userSuffix string) types.Namesp
 	}
 }
 
-// UserSecretKeySelector creates a SecretKeySelector for the associated user secret.
-func UserSecretKeySelector(association commonv1.Association, userSuffix string) *corev1.SecretKeySelector {
-	return &corev1.SecretKeySelector{
-		LocalObjectReference: corev1.LocalObjectReference{
-			Name: userSecretObjectName(association, userSuffix),
-		},
-		Key: elasticsearchUserName(association, userSuffix),
-	}
-}
-
-// ReconcileEsUser creates a User resource and a corresponding secret or updates those as appropriate.
-func ReconcileEsUser(
+// reconcileEsUser creates a User resource and a corresponding secret or updates those as appropriate. Passwords are
+// rotated along with the passwords of the Elasticsearch cluster under a new user name: the new user replaces the
+// current one in the association secret once all the Elasticsearch nodes accept it. It returns the credentials held in
+// the association secret.
+func reconcileEsUser(
 	ctx context.Context,
 	c k8s.Client,
 	association commonv1.Association,
@@ -81,7 +75,8 @@ func ReconcileEsUser(
 	userRoles string,
 	userObjectSuffix string,
 	es esv1.Elasticsearch,
-) error {
+	verify esuser.CredentialsVerifier,
+) (associationCredentials, error) {
 	span, _ := apm.StartSpan(ctx, "reconcile_es_user", tracing.SpanTypeApp)
 	defer span.End()
 
@@ -90,31 +85,25 @@ func ReconcileEsUser(
 
 	secKey := secretKey(association, userObjectSuffix)
 	usrKey := UserKey(association, es.Namespace, userObjectSuffix)
-	expectedSecret := corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      secKey.Name,
-			Namespace: secKey.Namespace,
-			Labels:    common.AddCredentialsLabel(labels),
-		},
-		Data: map[string][]byte{},
-	}
 
-	var password []byte
-	// reuse the existing password if there's one
+	// reuse the existing passwords, unless they must be rotated
 	var existingSecret corev1.Secret
-	err := c.Get(context.Background(), k8s.ExtractNamespacedName(&expectedSecret), &existingSecret)
-	if err != nil && !apierrors.IsNotFound(err) {
-		return err
+	if err := c.Get(context.Background(), secKey, &existingSecret); err != nil && !apierrors.IsNotFound(err) {
+		return associationCredentials{}, err
 	}
-	if existingPassword, exists := existingSecret.Data[usrKey.Name]; exists {
-		password = existingPassword
-	} else {
-		password = common.FixedLengthRandomPasswordBytes()
-	}
-	expectedSecret.Data[usrKey.Name] = password
-
-	if _, err := reconciler.ReconcileSecret(c, expectedSecret, association.Associated()); err != nil {
-		return err
+	credentials, err := rotateCredentials(es, existingSecret, usrKey.Name, credentialsSource{
+		generate: func(string) []byte {
+			return common.FixedLengthRandomPasswordBytes()
+		},
+		valid: func(string, []byte) bool {
+			return true
+		},
+		authorization: func(name string, password []byte) string {
+			return esclient.BasicAuthorization(esclient.BasicAuth{Name: name, Password: string(password)})
+		},
+	}, verify)
+	if err != nil {
+		return associationCredentials{}, err
 	}
 
 	// analogous to the association secret: a user Secret goes on the Elasticsearch side of the association
@@ -126,12 +115,42 @@ func ReconcileEsUser(
 	for key, value := range labels {
 		userLabels[key] = value
 	}
+	// the users must exist in Elasticsearch before the associated resource uses them
+	for username, password := range credentials.Data {
+		userKey := types.NamespacedName{Namespace: usrKey.Namespace, Name: username}
+		if err := reconcileEsUserSecret(c, es, userKey, userLabels, userRoles, password); err != nil {
+			return associationCredentials{}, err
+		}
+	}
+
+	expectedSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secKey.Name,
+			Namespace:   secKey.Namespace,
+			Labels:      common.AddCredentialsLabel(labels),
+			Annotations: credentials.Rotation.Annotations(),
+		},
+		Data: credentials.Data,
+	}
+	_, err = reconciler.ReconcileSecret(c, expectedSecret, association.Associated())
+	return credentials, err
+}
 
+// reconcileEsUserSecret reconciles the secret in the Elasticsearch namespace holding the hash of the password of the
+// given user.
+func reconcileEsUserSecret(
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	usrKey types.NamespacedName,
+	labels map[string]string,
+	userRoles string,
+	password []byte,
+) error {
 	expectedEsUser := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      usrKey.Name,
 			Namespace: usrKey.Namespace,
-			Labels:    userLabels,
+			Labels:    labels,
 		},
 		Data: map[string][]byte{
 			esuser.UserNameField:  []byte(usrKey.Name),
@@ -153,6 +172,7 @@ func ReconcileEsUser(
 	}
 
 	if bcryptHash == nil {
+		var err error
 		bcryptHash, err = bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
 		if err != nil {
 			return err
@@ -162,6 +182,6 @@ func ReconcileEsUser(
 	expectedEsUser.Data[esuser.PasswordHashField] = bcryptHash
 
 	owner := es // user is owned by the es resource in es namespace
-	_, err = reconciler.ReconcileSecret(c, expectedEsUser, &owner)
+	_, err := reconciler.ReconcileSecret(c, expectedEsUser, &owner)
 	return err
 }
@@ -249,7 +249,7 @@ func Test_reconcileEsUser(t *testing.T) {
 	for _, tt := range tests {
 		c := k8s.NewFakeClient(tt.args.initialObjects...)
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ReconcileEsUser(
+			if _, err := reconcileEsUser(
 				context.Background(),
 				c,
 				&tt.args.kibana,
@@ -260,6 +260,7 @@ func Test_reconcileEsUser(t *testing.T) {
 				"kibana_system",
 				"kibana-user",
 				tt.args.es,
+				acceptCredentials,
 			); (err != nil) != tt.wantErr {
 				t.Errorf("reconcileEsUser() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -6,29 +6,24 @@ package elasticsearch
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/autoscaling/elasticsearch/status"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
-	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
-	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
-	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	logconf "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -68,7 +63,7 @@ func NewReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileEl
 	return &ReconcileElasticsearch{
 		Client:           c,
 		Parameters:       params,
-		esClientProvider: newElasticsearchClient,
+		esClientProvider: user.NewElasticsearchClient,
 		recorder:         mgr.GetEventRecorderFor(controllerName),
 		licenseChecker:   license.NewLicenseChecker(c, params.OperatorNamespace),
 	}
@@ -178,59 +173,3 @@ func defaultResult(autoscalingSpecification esv1.AutoscalingSpec) reconcile.Resu
 		RequeueAfter: autoscalingSpecification.GetPollingPeriodOrDefault(),
 	}
 }
-
-func newElasticsearchClient(
-	ctx context.Context,
-	c k8s.Client,
-	dialer net.Dialer,
-	es esv1.Elasticsearch,
-) (esclient.Client, error) {
-	defer tracing.Span(&ctx)()
-	url := services.ExternalServiceURL(es)
-	v, err := version.Parse(es.Spec.Version)
-	if err != nil {
-		return nil, err
-	}
-	// Get user Secret
-	var controllerUserSecret corev1.Secret
-	key := types.NamespacedName{
-		Namespace: es.Namespace,
-		Name:      esv1.InternalUsersSecret(es.Name),
-	}
-	if err := c.Get(context.Background(), key, &controllerUserSecret); err != nil {
-		return nil, err
-	}
-	password, ok := controllerUserSecret.Data[user.ControllerUserName]
-	if !ok {
-		return nil, fmt.Errorf("controller user %s not found in Secret %s/%s", user.ControllerUserName, key.Namespace, key.Name)
-	}
-
-	// Get public certs
-	var caSecret corev1.Secret
-	key = types.NamespacedName{
-		Namespace: es.Namespace,
-		Name:      certificates.PublicCertsSecretName(esv1.ESNamer, es.Name),
-	}
-	if err := c.Get(context.Background(), key, &caSecret); err != nil {
-		return nil, err
-	}
-	trustedCerts, ok := caSecret.Data[certificates.CertFileName]
-	if !ok {
-		return nil, fmt.Errorf("%s not found in Secret %s/%s", certificates.CertFileName, key.Namespace, key.Name)
-	}
-	caCerts, err := certificates.ParsePEMCerts(trustedCerts)
-	if err != nil {
-		return nil, err
-	}
-	return esclient.NewElasticsearchClient(
-		dialer,
-		url,
-		esclient.BasicAuth{
-			Name:     user.ControllerUserName,
-			Password: string(password),
-		},
-		v,
-		caCerts,
-		esclient.Timeout(es),
-	), nil
-}
@@ -16,6 +16,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
@@ -25,17 +26,15 @@ func buildOutputConfig(client k8s.Client, associated beatv1beta1.BeatESAssociati
 		return settings.NewCanonicalConfig(), nil
 	}
 
-	username, password, err := association.ElasticsearchAuthSettings(client, &associated)
+	credentials, err := association.ElasticsearchCredentials(client, &associated)
 	if err != nil {
 		return settings.NewCanonicalConfig(), err
 	}
 
+	outputConfig := credentials.OutputSettings()
+	outputConfig["hosts"] = []string{associated.AssociationConf().GetURL()}
 	esOutput := map[string]interface{}{
-		"output.elasticsearch": map[string]interface{}{
-			"hosts":    []string{associated.AssociationConf().GetURL()},
-			"username": username,
-			"password": password,
-		},
+		"output.elasticsearch": outputConfig,
 	}
 
 	if associated.AssociationConf().GetCACertProvided() {
@@ -81,7 +80,7 @@ func buildBeatConfig(
 	if err != nil {
 		return nil, err
 	}
-	err = cfg.MergeWith(outputCfg, managedConfig)
+	err = cfg.MergeWith(outputCfg, managedConfig, stackmon.LibbeatMonitoringConfig(&params.Beat))
 	if err != nil {
 		return nil, err
 	}
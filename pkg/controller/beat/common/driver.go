@@ -104,7 +104,14 @@ func Reconcile(
 		return results.WithError(err)
 	}
 
-	podTemplate := buildPodTemplate(params, defaultImage, keystoreResources, configHash)
+	if err := reconcileMonitoringConfigSecrets(params); err != nil {
+		return results.WithError(err)
+	}
+
+	podTemplate, err := buildPodTemplate(params, defaultImage, keystoreResources, configHash)
+	if err != nil {
+		return results.WithError(err)
+	}
 	results.WithResults(reconcilePodVehicle(podTemplate, params))
 	return results
 }
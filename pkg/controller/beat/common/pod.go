@@ -71,7 +71,7 @@ func buildPodTemplate(
 	defaultImage container.Image,
 	keystoreResources *keystore.Resources,
 	configHash hash.Hash,
-) corev1.PodTemplateSpec {
+) (corev1.PodTemplateSpec, error) {
 	podTemplate := params.GetPodTemplate()
 
 	spec := &params.Beat.Spec
@@ -92,6 +92,10 @@ func buildPodTemplate(
 	}
 
 	for _, association := range params.Beat.GetAssociations() {
+		if association.AssociationType() == commonv1.EsMonitoringAssociationType {
+			// the monitoring CA is mounted in the Metricbeat sidecar
+			continue
+		}
 		if !association.AssociationConf().CAIsConfigured() {
 			continue
 		}
@@ -129,7 +133,12 @@ func buildPodTemplate(
 		WithInitContainers(initContainers...).
 		WithInitContainerDefaults()
 
-	return builder.PodTemplate
+	builder, err := withMonitoring(builder, params)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	return builder.PodTemplate, nil
 }
 
 func createDataVolume(dp DriverParams) volume.VolumeLike {
@@ -13,6 +13,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/apis/beat/v1beta1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/container"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_buildPodTemplate(t *testing.T) {
@@ -68,7 +69,8 @@ func Test_buildPodTemplate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			params := DriverParams{Beat: tt.beat}
-			got := buildPodTemplate(params, container.AuditbeatImage, nil, sha256.New224())
+			got, err := buildPodTemplate(params, container.AuditbeatImage, nil, sha256.New224())
+			require.NoError(t, err)
 			tt.assertions(got)
 		})
 	}
@@ -70,7 +70,7 @@ func reconcilePodVehicle(podTemplate corev1.PodTemplateSpec, params DriverParams
 		results.WithError(err)
 	}
 
-	err = updateStatus(params, ready, desired)
+	err = updateStatus(params, ready, desired, results)
 	if err != nil && apierrors.IsConflict(err) {
 		params.Logger.V(1).Info(
 			"Conflict while updating status",
@@ -132,7 +132,7 @@ func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
 	return reconciled.Status.NumberReady, reconciled.Status.DesiredNumberScheduled, nil
 }
 
-func updateStatus(params DriverParams, ready, desired int32) error {
+func updateStatus(params DriverParams, ready, desired int32, results *reconciler.Results) error {
 	beat := params.Beat
 
 	pods, err := k8s.PodsMatchingLabels(params.K8sClient(), beat.Namespace, map[string]string{NameLabelName: beat.Name})
@@ -143,6 +143,8 @@ func updateStatus(params DriverParams, ready, desired int32) error {
 	beat.Status.ExpectedNodes = desired
 	beat.Status.Health = CalculateHealth(beat.GetAssociations(), ready, desired)
 	beat.Status.Version = common.LowestVersionFromPods(beat.Status.Version, pods, VersionLabelName)
+	common.UpdateConditions(&beat.Status.Conditions, beat.Generation, results, beat.Status.Version, beat.Spec.Version)
+	beat.Status.ObservedGeneration = beat.Generation
 
 	return params.Client.Status().Update(context.Background(), &beat)
 }
@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package common
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+)
+
+// metricbeatMonitoringContainerName is the name of the Metricbeat sidecar container, distinct from "metricbeat" to
+// not collide with the main container of a Beat of type metricbeat.
+const metricbeatMonitoringContainerName = "metricbeat-monitoring"
+
+// monitoringSidecars returns the Beat sidecars to deploy in the Beat Pods according to the monitoring spec.
+// Only the collection of metrics is supported, Beats log to stderr.
+func monitoringSidecars(params DriverParams) ([]stackmon.BeatSidecar, error) {
+	if !monitoring.IsMetricsDefined(&params.Beat) {
+		return nil, nil
+	}
+	metricbeat, err := stackmon.NewLibbeatMetricBeatSidecar(params.Client, &params.Beat, namer, params.Beat.Spec.Version)
+	if err != nil {
+		return nil, err
+	}
+	metricbeat.Container.Name = metricbeatMonitoringContainerName
+	return []stackmon.BeatSidecar{metricbeat}, nil
+}
+
+// reconcileMonitoringConfigSecrets reconciles the secrets holding the configuration of the Beat sidecars.
+func reconcileMonitoringConfigSecrets(params DriverParams) error {
+	sidecars, err := monitoringSidecars(params)
+	if err != nil {
+		return err
+	}
+	return stackmon.ReconcileConfigSecrets(params.Client, &params.Beat, NewLabels(params.Beat), sidecars...)
+}
+
+// withMonitoring updates the Beat Pod template builder to deploy Metricbeat in a sidecar container.
+func withMonitoring(builder *defaults.PodTemplateBuilder, params DriverParams) (*defaults.PodTemplateBuilder, error) {
+	if !monitoring.IsDefined(&params.Beat) {
+		return builder, nil
+	}
+	sidecars, err := monitoringSidecars(params)
+	if err != nil {
+		return nil, err
+	}
+	return stackmon.WithMonitoring(builder, sidecars...), nil
+}
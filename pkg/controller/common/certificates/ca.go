@@ -5,13 +5,14 @@
 package certificates
 
 import (
+	"crypto"
 	cryptorand "crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"math/big"
 	"time"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/pkg/errors"
 )
 
@@ -23,7 +24,7 @@ var (
 // CA is a simple certificate authority
 type CA struct {
 	// PrivateKey is the CA private key
-	PrivateKey *rsa.PrivateKey
+	PrivateKey crypto.Signer
 	// Cert is the certificate used to issue new certificates
 	Cert *x509.Certificate
 }
@@ -33,7 +34,7 @@ type CA struct {
 type ValidatedCertificateTemplate x509.Certificate
 
 // NewCA returns a ca with the given private key and cert
-func NewCA(privateKey *rsa.PrivateKey, cert *x509.Certificate) *CA {
+func NewCA(privateKey crypto.Signer, cert *x509.Certificate) *CA {
 	return &CA{
 		PrivateKey: privateKey,
 		Cert:       cert,
@@ -45,7 +46,9 @@ type CABuilderOptions struct {
 	// Subject of the CA to build.
 	Subject pkix.Name
 	// PrivateKey to be used for signing certificates (auto-generated if not provided).
-	PrivateKey *rsa.PrivateKey
+	PrivateKey crypto.Signer
+	// PrivateKeyOptions are the options used to generate the private key if not provided.
+	PrivateKeyOptions commonv1.PrivateKeyOptions
 	// ExpireIn defines in how much time will the CA expire (defaults to DefaultCertValidity if not provided).
 	ExpireIn *time.Duration
 }
@@ -60,7 +63,7 @@ func NewSelfSignedCA(options CABuilderOptions) (*CA, error) {
 
 	privateKey := options.PrivateKey
 	if privateKey == nil {
-		privateKey, err = rsa.GenerateKey(cryptorand.Reader, 2048)
+		privateKey, err = GeneratePrivateKey(options.PrivateKeyOptions)
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to generate the private key")
 		}
@@ -76,7 +79,6 @@ func NewSelfSignedCA(options CABuilderOptions) (*CA, error) {
 		Subject:               options.Subject,
 		NotBefore:             time.Now().Add(-10 * time.Minute),
 		NotAfter:              notAfter,
-		SignatureAlgorithm:    x509.SHA256WithRSA,
 		IsCA:                  true,
 		BasicConstraintsValid: true,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -113,6 +115,8 @@ func (c *CA) CreateCertificate(
 	validatedCertificateTemplate.Issuer = c.Cert.Issuer
 
 	certTemplate := x509.Certificate(validatedCertificateTemplate)
+	// the signature algorithm depends on the CA private key, not on the one of the certificate request
+	certTemplate.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
 
 	certData, err := x509.CreateCertificate(
 		cryptorand.Reader,
@@ -10,6 +10,7 @@ import (
 	"crypto/x509/pkix"
 	"time"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -44,7 +45,8 @@ func CAInternalSecretName(namer name.Namer, ownerName string, caType CAType) str
 // The CA is persisted across operator restarts in the apiserver as a Secret for the CA certificate and private key:
 // `<clusterName>-<caType>-ca-internal`
 //
-// The CA cert and private key are rotated if they become invalid (or soon to expire).
+// The CA cert and private key are rotated if they become invalid (or soon to expire), or if the private key does not
+// have the algorithm and size of the given private key options.
 func ReconcileCAForOwner(
 	cl k8s.Client,
 	namer name.Namer,
@@ -52,6 +54,7 @@ func ReconcileCAForOwner(
 	labels map[string]string,
 	caType CAType,
 	rotationParams RotationParams,
+	privateKeyOptions commonv1.PrivateKeyOptions,
 ) (*CA, error) {
 
 	// retrieve current CA secret
@@ -66,20 +69,26 @@ func ReconcileCAForOwner(
 	}
 	if apierrors.IsNotFound(err) {
 		log.Info("No internal CA certificate Secret found, creating a new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
-		return renewCA(cl, namer, owner, labels, rotationParams.Validity, caType)
+		return renewCA(cl, namer, owner, labels, rotationParams.Validity, caType, privateKeyOptions)
 	}
 
 	// build CA
 	ca := BuildCAFromSecret(caInternalSecret)
 	if ca == nil {
 		log.Info("Cannot build CA from secret, creating a new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
-		return renewCA(cl, namer, owner, labels, rotationParams.Validity, caType)
+		return renewCA(cl, namer, owner, labels, rotationParams.Validity, caType, privateKeyOptions)
 	}
 
 	// renew if cannot reuse
 	if !CanReuseCA(ca, rotationParams.RotateBefore) {
 		log.Info("Cannot reuse existing CA, creating a new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
-		return renewCA(cl, namer, owner, labels, rotationParams.Validity, caType)
+		return renewCA(cl, namer, owner, labels, rotationParams.Validity, caType, privateKeyOptions)
+	}
+
+	// renew if the private key algorithm or size has changed
+	if !PrivateKeyMatchesOptions(ca.PrivateKey, privateKeyOptions) {
+		log.Info("Existing CA private key does not match the private key options, creating a new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
+		return renewCA(cl, namer, owner, labels, rotationParams.Validity, caType, privateKeyOptions)
 	}
 
 	// reuse existing CA
@@ -94,18 +103,23 @@ func renewCA(
 	labels map[string]string,
 	expireIn time.Duration,
 	caType CAType,
+	privateKeyOptions commonv1.PrivateKeyOptions,
 ) (*CA, error) {
 	ca, err := NewSelfSignedCA(CABuilderOptions{
 		Subject: pkix.Name{
 			CommonName:         owner.GetName() + "-" + string(caType),
 			OrganizationalUnit: []string{owner.GetName()},
 		},
-		ExpireIn: &expireIn,
+		ExpireIn:          &expireIn,
+		PrivateKeyOptions: privateKeyOptions,
 	})
 	if err != nil {
 		return nil, err
 	}
-	caInternalSecret := internalSecretForCA(ca, namer, owner, labels, caType)
+	caInternalSecret, err := internalSecretForCA(ca, namer, owner, labels, caType)
+	if err != nil {
+		return nil, err
+	}
 
 	// create or update internal secret
 	if _, err := reconciler.ReconcileSecret(client, caInternalSecret, owner); err != nil {
@@ -117,7 +131,7 @@ func renewCA(
 
 // CanReuseCA returns true if the given CA is valid for reuse
 func CanReuseCA(ca *CA, expirationSafetyMargin time.Duration) bool {
-	return PrivateMatchesPublicKey(ca.Cert.PublicKey, *ca.PrivateKey) && CertIsValid(*ca.Cert, expirationSafetyMargin)
+	return PrivateMatchesPublicKey(ca.Cert.PublicKey, ca.PrivateKey) && CertIsValid(*ca.Cert, expirationSafetyMargin)
 }
 
 // CertIsValid returns true if the given cert is valid,
@@ -142,7 +156,11 @@ func internalSecretForCA(
 	owner v1.Object,
 	labels map[string]string,
 	caType CAType,
-) corev1.Secret {
+) (corev1.Secret, error) {
+	privateKey, err := EncodePEMPrivateKey(ca.PrivateKey)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
 	return corev1.Secret{
 		ObjectMeta: v1.ObjectMeta{
 			Namespace: owner.GetNamespace(),
@@ -151,9 +169,9 @@ func internalSecretForCA(
 		},
 		Data: map[string][]byte{
 			CertFileName: EncodePEMCert(ca.Cert.Raw),
-			KeyFileName:  EncodePEMPrivateKey(*ca.PrivateKey),
+			KeyFileName:  privateKey,
 		},
-	}
+	}, nil
 }
 
 // BuildCAFromSecret parses the given secret into a CA.
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -161,8 +162,7 @@ func checkCASecrets(
 	// if an expected Ca was passed, it should match ca
 	if expectedCa != nil {
 		require.True(t, ca.Cert.Equal(expectedCa.Cert))
-		require.Equal(t, ca.PrivateKey.E, expectedCa.PrivateKey.E)
-		require.Equal(t, ca.PrivateKey.N, expectedCa.PrivateKey.N)
+		require.Equal(t, ca.PrivateKey.Public(), expectedCa.PrivateKey.Public())
 	}
 
 	// if a not expected Ca was passed, it should not match ca
@@ -185,14 +185,14 @@ func checkCASecrets(
 	require.NotNil(t, parsedCa)
 	// and return the ca
 	require.True(t, ca.Cert.Equal(parsedCa.Cert))
-	require.Equal(t, ca.PrivateKey.E, parsedCa.PrivateKey.E)
-	require.Equal(t, ca.PrivateKey.N, parsedCa.PrivateKey.N)
+	require.Equal(t, ca.PrivateKey.Public(), parsedCa.PrivateKey.Public())
 }
 
 func Test_renewCA(t *testing.T) {
 	testCa, err := NewSelfSignedCA(CABuilderOptions{})
 	require.NoError(t, err)
-	internalCASecret := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	internalCASecret, err := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	require.NoError(t, err)
 
 	tests := []struct {
 		name        string
@@ -214,7 +214,7 @@ func Test_renewCA(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ca, err := renewCA(tt.client, testNamer, &testCluster, nil, tt.expireIn, TransportCAType)
+			ca, err := renewCA(tt.client, testNamer, &testCluster, nil, tt.expireIn, TransportCAType, DefaultPrivateKeyOptions)
 			require.NoError(t, err)
 			require.NotNil(t, ca)
 			assert.Equal(t, ca.Cert.Issuer.CommonName, testName+"-"+string(TransportCAType))
@@ -226,7 +226,8 @@ func Test_renewCA(t *testing.T) {
 func TestReconcileCAForCluster(t *testing.T) {
 	validCa, err := NewSelfSignedCA(CABuilderOptions{})
 	require.NoError(t, err)
-	internalCASecret := internalSecretForCA(validCa, testNamer, &testCluster, nil, TransportCAType)
+	internalCASecret, err := internalSecretForCA(validCa, testNamer, &testCluster, nil, TransportCAType)
+	require.NoError(t, err)
 
 	internalCASecretWithoutPrivateKey := internalCASecret.DeepCopy()
 	delete(internalCASecretWithoutPrivateKey.Data, KeyFileName)
@@ -239,16 +240,24 @@ func TestReconcileCAForCluster(t *testing.T) {
 		ExpireIn: &soonToExpire,
 	})
 	require.NoError(t, err)
-	soonToExpireInternalCASecret := internalSecretForCA(
+	soonToExpireInternalCASecret, err := internalSecretForCA(
 		soonToExpireCa, testNamer, &testCluster, nil, TransportCAType,
 	)
+	require.NoError(t, err)
+
+	ecdsaOptions := commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm}
+	ecdsaCa, err := NewSelfSignedCA(CABuilderOptions{PrivateKeyOptions: ecdsaOptions})
+	require.NoError(t, err)
+	ecdsaInternalCASecret, err := internalSecretForCA(ecdsaCa, testNamer, &testCluster, nil, TransportCAType)
+	require.NoError(t, err)
 
 	tests := []struct {
-		name             string
-		cl               k8s.Client
-		caCertValidity   time.Duration
-		shouldReuseCa    *CA // ca that should be reused
-		shouldNotReuseCa *CA // ca that should not be reused
+		name              string
+		cl                k8s.Client
+		caCertValidity    time.Duration
+		privateKeyOptions commonv1.PrivateKeyOptions
+		shouldReuseCa     *CA // ca that should be reused
+		shouldNotReuseCa  *CA // ca that should not be reused
 	}{
 		{
 			name:           "no existing CA cert nor private key",
@@ -281,6 +290,29 @@ func TestReconcileCAForCluster(t *testing.T) {
 			shouldReuseCa:    nil,            // should create a new one
 			shouldNotReuseCa: soonToExpireCa, // and not reuse existing one
 		},
+		{
+			name:              "existing valid internal secret with the expected private key algorithm",
+			cl:                k8s.NewFakeClient(&ecdsaInternalCASecret),
+			caCertValidity:    DefaultCertValidity,
+			privateKeyOptions: ecdsaOptions,
+			shouldReuseCa:     ecdsaCa, // should reuse existing one
+		},
+		{
+			name:              "existing internal secret with a different private key algorithm",
+			cl:                k8s.NewFakeClient(internalCASecret.DeepCopy()),
+			caCertValidity:    DefaultCertValidity,
+			privateKeyOptions: ecdsaOptions,
+			shouldReuseCa:     nil,     // should create a new one
+			shouldNotReuseCa:  validCa, // and not reuse existing one
+		},
+		{
+			name:              "existing internal secret with a different private key size",
+			cl:                k8s.NewFakeClient(internalCASecret.DeepCopy()),
+			caCertValidity:    DefaultCertValidity,
+			privateKeyOptions: commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 3072},
+			shouldReuseCa:     nil,     // should create a new one
+			shouldNotReuseCa:  validCa, // and not reuse existing one
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -289,9 +321,11 @@ func TestReconcileCAForCluster(t *testing.T) {
 					Validity:     tt.caCertValidity,
 					RotateBefore: DefaultRotateBefore,
 				},
+				tt.privateKeyOptions,
 			)
 			require.NoError(t, err)
 			require.NotNil(t, ca)
+			require.True(t, PrivateKeyMatchesOptions(ca.PrivateKey, tt.privateKeyOptions))
 			checkCASecrets(
 				t, tt.cl, testCluster, TransportCAType, ca, tt.shouldReuseCa, tt.shouldNotReuseCa, tt.caCertValidity,
 			)
@@ -305,7 +339,8 @@ func Test_internalSecretForCA(t *testing.T) {
 
 	labels := map[string]string{"foo": "bar"}
 
-	internalSecret := internalSecretForCA(testCa, testNamer, &testCluster, labels, TransportCAType)
+	internalSecret, err := internalSecretForCA(testCa, testNamer, &testCluster, labels, TransportCAType)
+	require.NoError(t, err)
 
 	assert.Equal(t, testNamespace, internalSecret.Namespace)
 	assert.Equal(t, testName+"-test-transport-ca-internal", internalSecret.Name)
@@ -321,7 +356,8 @@ func Test_buildCAFromSecret(t *testing.T) {
 	testCa, err := NewSelfSignedCA(CABuilderOptions{})
 	require.NoError(t, err)
 
-	internalSecret := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	internalSecret, err := internalSecretForCA(testCa, testNamer, &testCluster, nil, TransportCAType)
+	require.NoError(t, err)
 
 	internalSecretMissingCert := internalSecret.DeepCopy()
 	delete(internalSecretMissingCert.Data, CertFileName)
@@ -5,9 +5,10 @@
 package certificates
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	cryptorand "crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"net"
@@ -52,17 +53,18 @@ func (r Reconciler) ReconcilePublicHTTPCerts(internalCerts *CertificatesSecret)
 	return err
 }
 
-// ReconcileInternalHTTPCerts reconciles the internal resources for the HTTP certificate.
-func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, error) {
+// ReconcileInternalHTTPCerts reconciles the internal resources for the HTTP certificate. It also returns true if the
+// HTTP certificate is still being issued by an external issuer.
+func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, bool, error) {
 	ownerNSN := k8s.ExtractNamespacedName(r.Owner)
 	customCertificates, err := getCustomCertificates(r.K8sClient, ownerNSN, r.TLSOptions)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	watchKey := CertificateWatchKey(r.Namer, ownerNSN.Name)
 	if err := ReconcileCustomCertWatch(r.DynamicWatches, watchKey, ownerNSN, r.TLSOptions.Certificate); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	secret := corev1.Secret{
@@ -74,7 +76,7 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, err
 
 	shouldCreateSecret := false
 	if err := r.K8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&secret), &secret); err != nil && !apierrors.IsNotFound(err) {
-		return nil, err
+		return nil, false, err
 	} else if apierrors.IsNotFound(err) {
 		shouldCreateSecret = true
 	}
@@ -95,7 +97,7 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, err
 	}
 
 	if err := controllerutil.SetControllerReference(r.Owner, &secret, scheme.Scheme); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// a placeholder secret may have nil entries, create them if needed
@@ -105,9 +107,10 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, err
 
 	// by default let's assume that the CA is provided, either by the ECK internal certificate authority or by the user
 	caCertProvided := true
+	pending := false
 	if customCertificates != nil {
 		if err := customCertificates.Validate(); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		expectedSecretData := make(map[string][]byte)
 		expectedSecretData[CertFileName] = customCertificates.CertPem()
@@ -128,12 +131,19 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, err
 			needsUpdate = true
 			secret.Data = expectedSecretData
 		}
+	} else if r.TLSOptions.IssuerRef != nil {
+		issuedNeedsUpdate, issued, err := r.ensureInternalIssuedCertificateSecretContents(&secret)
+		if err != nil {
+			return nil, false, err
+		}
+		needsUpdate = needsUpdate || issuedNeedsUpdate
+		pending = !issued
 	} else {
 		selfSignedNeedsUpdate, err := ensureInternalSelfSignedCertificateSecretContents(
-			&secret, ownerNSN, r.Namer, r.TLSOptions, r.ExtraHTTPSANs, r.Services, ca, r.CertRotation,
+			&secret, ownerNSN, r.Namer, r.TLSOptions, r.ExtraHTTPSANs, r.Services, ca, r.CertRotation, r.privateKeyOptions(),
 		)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		needsUpdate = needsUpdate || selfSignedNeedsUpdate
 	}
@@ -142,12 +152,12 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, err
 		if shouldCreateSecret {
 			log.Info("Creating HTTP internal certificate secret", "namespace", secret.Namespace, "secret_name", secret.Name)
 			if err := r.K8sClient.Create(context.Background(), &secret); err != nil {
-				return nil, err
+				return nil, false, err
 			}
 		} else {
 			log.Info("Updating HTTP internal certificate secret", "namespace", secret.Namespace, "secret_name", secret.Name)
 			if err := r.K8sClient.Update(context.Background(), &secret); err != nil {
-				return nil, err
+				return nil, false, err
 			}
 		}
 	}
@@ -158,7 +168,7 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ca *CA) (*CertificatesSecret, err
 	}
 
 	internalCerts := CertificatesSecret(secret)
-	return &internalCerts, nil
+	return &internalCerts, pending, nil
 }
 
 // ensureInternalSelfSignedCertificateSecretContents ensures that contents of a secret containing self-signed
@@ -174,17 +184,21 @@ func ensureInternalSelfSignedCertificateSecretContents(
 	svcs []corev1.Service,
 	ca *CA,
 	rotationParam RotationParams,
+	privateKeyOptions commonv1.PrivateKeyOptions,
 ) (bool, error) {
 	secretWasChanged := false
 
-	// verify that the secret contains a parsable private key, create if it does not exist
-	var privateKey *rsa.PrivateKey
+	// verify that the secret contains a parsable private key matching the private key options, create if it does not exist
+	var privateKey crypto.Signer
 	needsNewPrivateKey := true
 	if privateKeyData, ok := secret.Data[KeyFileName]; ok {
 		storedPrivateKey, err := ParsePEMPrivateKey(privateKeyData)
-		if err != nil {
+		switch {
+		case err != nil:
 			log.Error(err, "Unable to parse stored private key", "namespace", secret.Namespace, "secret_name", secret.Name)
-		} else {
+		case !PrivateKeyMatchesOptions(storedPrivateKey, privateKeyOptions):
+			log.Info("Stored private key does not match the private key options, generating a new one", "namespace", secret.Namespace, "secret_name", secret.Name)
+		default:
 			needsNewPrivateKey = false
 			privateKey = storedPrivateKey
 		}
@@ -192,18 +206,22 @@ func ensureInternalSelfSignedCertificateSecretContents(
 
 	// if we need a new private key, generate it
 	if needsNewPrivateKey {
-		generatedPrivateKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+		generatedPrivateKey, err := GeneratePrivateKey(privateKeyOptions)
+		if err != nil {
+			return secretWasChanged, err
+		}
+		encodedPrivateKey, err := EncodePEMPrivateKey(generatedPrivateKey)
 		if err != nil {
 			return secretWasChanged, err
 		}
 
 		privateKey = generatedPrivateKey
 		secretWasChanged = true
-		secret.Data[KeyFileName] = EncodePEMPrivateKey(*privateKey)
+		secret.Data[KeyFileName] = encodedPrivateKey
 	}
 
-	// check if the existing cert should be re-issued
-	if shouldIssueNewHTTPCertificate(owner, namer, tls, controllerSANs, secret, svcs, ca, rotationParam.RotateBefore) {
+	// check if the existing cert should be re-issued, a new private key always requires a new certificate
+	if needsNewPrivateKey || shouldIssueNewHTTPCertificate(owner, namer, tls, controllerSANs, secret, svcs, ca, rotationParam.RotateBefore) {
 		log.Info(
 			"Issuing new HTTP certificate",
 			"namespace", secret.Namespace,
@@ -242,6 +260,44 @@ func ensureInternalSelfSignedCertificateSecretContents(
 	return secretWasChanged, nil
 }
 
+// ensureInternalIssuedCertificateSecretContents ensures that the secret contains a certificate signed by the external
+// issuer referenced in the TLS options. The provided secret is updated in-place.
+//
+// Returns true if the secret was changed, and true if the certificate has been issued.
+func (r Reconciler) ensureInternalIssuedCertificateSecretContents(secret *corev1.Secret) (bool, bool, error) {
+	owner := k8s.ExtractNamespacedName(r.Owner)
+	template := createValidatedHTTPCertificateTemplate(
+		owner, r.Namer, r.TLSOptions, r.ExtraHTTPSANs, r.Services, &x509.CertificateRequest{}, r.CertRotation.Validity,
+	)
+	secretWasChanged, issued, err := ReconcileIssuedCertificate(
+		secret.Data,
+		IssuedCertificateKeys{PrivateKey: KeyFileName, PendingPrivateKey: PendingKeyFileName, Certificate: CertFileName},
+		r.privateKeyOptions(),
+		CertificateRequestParams{
+			Client:    r.K8sClient,
+			Owner:     r.Owner,
+			Name:      secret.Name,
+			Labels:    r.Labels,
+			IssuerRef: *r.TLSOptions.IssuerRef,
+			Template: x509.CertificateRequest{
+				Subject:     template.Subject,
+				DNSNames:    template.DNSNames,
+				IPAddresses: template.IPAddresses,
+			},
+			Rotation: r.CertRotation,
+		},
+	)
+	if err != nil || issued == nil {
+		return secretWasChanged, false, err
+	}
+
+	if !bytes.Equal(secret.Data[CAFileName], issued.CA) {
+		secret.Data[CAFileName] = issued.CA
+		secretWasChanged = true
+	}
+	return secretWasChanged, true, nil
+}
+
 // shouldIssueNewHTTPCertificate returns true if we should issue a new HTTP certificate.
 //
 // Reasons for reissuing a certificate:
@@ -0,0 +1,371 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	cryptorand "crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"time"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DefaultIssuerKind is the kind of the issuer if not specified in the issuer reference.
+	DefaultIssuerKind = "Issuer"
+	// DefaultIssuerGroup is the group of the issuer if not specified in the issuer reference.
+	DefaultIssuerGroup = "cert-manager.io"
+
+	// CertificateRequestPollInterval is the interval at which pending CertificateRequests are checked. They are not
+	// watched since the CertificateRequest CRD is only installed along with cert-manager.
+	CertificateRequestPollInterval = 5 * time.Second
+
+	certificateRequestReadyCondition          = "Ready"
+	certificateRequestDeniedCondition         = "Denied"
+	certificateRequestInvalidRequestCondition = "InvalidRequest"
+	certificateRequestFailedReason            = "Failed"
+)
+
+var (
+	// CertificateRequestGVK is the GroupVersionKind of the cert-manager CertificateRequests, also signed by
+	// external issuers.
+	CertificateRequestGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "CertificateRequest"}
+
+	certificateRequestUsages = []interface{}{"digital signature", "key encipherment", "server auth", "client auth"}
+)
+
+// CertificateRequestParams holds the parameters of a certificate to be signed by an external issuer.
+type CertificateRequestParams struct {
+	Client k8s.Client
+	Owner  client.Object // owner of the CertificateRequest
+
+	Name   string            // name of the CertificateRequest, in the namespace of the owner
+	Labels map[string]string // to set on the CertificateRequest
+
+	IssuerRef  commonv1.IssuerReference
+	PrivateKey crypto.Signer           // private key of the certificate, used to sign the certificate request
+	Template   x509.CertificateRequest // subject and SANs of the certificate
+	Rotation   RotationParams          // validity of the certificate, and when to request a new one
+}
+
+// IssuedCertificate holds a certificate signed by an external issuer.
+type IssuedCertificate struct {
+	// Chain is the PEM-encoded certificate chain, starting with the issued certificate.
+	Chain []byte
+	// CA is the PEM-encoded certificate of the issuer, as provided in the status of the CertificateRequest.
+	CA []byte
+	// Certificate is the parsed issued certificate.
+	Certificate *x509.Certificate
+}
+
+// IssuedCertificateKeys are the names of the Secret entries holding a certificate signed by an external issuer.
+type IssuedCertificateKeys struct {
+	PrivateKey        string // private key in use
+	PendingPrivateKey string // new private key, waiting for a certificate to be issued
+	Certificate       string // certificate chain in use
+}
+
+// ReconcileIssuedCertificate ensures that the given Secret data contains a private key matching the private key options,
+// along with a certificate signed by an external issuer for this private key. A new private key is kept aside until a
+// certificate is issued for it, so that the private key and certificate in use remain unchanged in the meantime.
+// The data is updated in-place. It returns true if the data was changed, and the issued certificate or nil if the
+// certificate is still being issued.
+func ReconcileIssuedCertificate(
+	data map[string][]byte,
+	keys IssuedCertificateKeys,
+	privateKeyOptions commonv1.PrivateKeyOptions,
+	params CertificateRequestParams,
+) (bool, *IssuedCertificate, error) {
+	dataWasChanged := false
+
+	privateKey := parseMatchingPrivateKey(data[keys.PrivateKey], privateKeyOptions)
+	newPrivateKey := privateKey == nil
+	if newPrivateKey {
+		privateKey = parseMatchingPrivateKey(data[keys.PendingPrivateKey], privateKeyOptions)
+		if privateKey == nil {
+			generatedPrivateKey, err := GeneratePrivateKey(privateKeyOptions)
+			if err != nil {
+				return dataWasChanged, nil, err
+			}
+			encodedPrivateKey, err := EncodePEMPrivateKey(generatedPrivateKey)
+			if err != nil {
+				return dataWasChanged, nil, err
+			}
+			privateKey = generatedPrivateKey
+			data[keys.PendingPrivateKey] = encodedPrivateKey
+			dataWasChanged = true
+		}
+	} else if _, exists := data[keys.PendingPrivateKey]; exists {
+		// the private key in use matches the options again, no need to keep the pending one
+		delete(data, keys.PendingPrivateKey)
+		dataWasChanged = true
+	}
+
+	params.PrivateKey = privateKey
+	issued, err := ReconcileCertificateRequest(params)
+	if err != nil || issued == nil {
+		return dataWasChanged, nil, err
+	}
+
+	if newPrivateKey {
+		data[keys.PrivateKey] = data[keys.PendingPrivateKey]
+		delete(data, keys.PendingPrivateKey)
+		dataWasChanged = true
+	}
+	if !bytes.Equal(data[keys.Certificate], issued.Chain) {
+		data[keys.Certificate] = issued.Chain
+		dataWasChanged = true
+	}
+	return dataWasChanged, issued, nil
+}
+
+// parseMatchingPrivateKey returns the PEM-encoded private key if it matches the given options, nil otherwise.
+func parseMatchingPrivateKey(data []byte, options commonv1.PrivateKeyOptions) crypto.Signer {
+	if len(data) == 0 {
+		return nil
+	}
+	privateKey, err := ParsePEMPrivateKey(data)
+	if err != nil || !PrivateKeyMatchesOptions(privateKey, options) {
+		return nil
+	}
+	return privateKey
+}
+
+// ReconcileCertificateRequest ensures that a CertificateRequest for the given private key and template is signed by
+// the given issuer. It returns the issued certificate, or nil if the request has not been signed yet.
+// The CertificateRequest is replaced if it does not match the expected one, if it was denied or failed, or if the
+// issued certificate is due for rotation.
+func ReconcileCertificateRequest(params CertificateRequestParams) (*IssuedCertificate, error) {
+	csr, err := x509.CreateCertificateRequest(cryptorand.Reader, &params.Template, params.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := newCertificateRequest(params, csr)
+	if err != nil {
+		return nil, err
+	}
+
+	actual := unstructured.Unstructured{}
+	actual.SetGroupVersionKind(CertificateRequestGVK)
+	err = params.Client.Get(context.Background(), types.NamespacedName{Namespace: expected.GetNamespace(), Name: expected.GetName()}, &actual)
+	if apierrors.IsNotFound(err) {
+		log.Info("Creating CertificateRequest", "namespace", expected.GetNamespace(), "name", expected.GetName())
+		return nil, params.Client.Create(context.Background(), expected)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !certificateRequestMatches(actual, csr, expected) {
+		log.Info("CertificateRequest does not match the expected one, replacing it",
+			"namespace", actual.GetNamespace(), "name", actual.GetName())
+		return nil, replaceCertificateRequest(params.Client, &actual, expected)
+	}
+
+	issued, err := issuedCertificate(actual)
+	if err != nil {
+		// delete the request so a new one is created on the next reconciliation
+		if deleteErr := deleteCertificateRequest(params.Client, &actual); deleteErr != nil {
+			return nil, deleteErr
+		}
+		return nil, err
+	}
+	if issued == nil {
+		// not signed yet
+		return nil, nil
+	}
+	if len(issued.CA) == 0 {
+		// the certificate cannot be trusted without the CA of the issuer, which a new request would not provide either
+		return nil, fmt.Errorf("CertificateRequest %s/%s was signed without the CA certificate of the issuer",
+			actual.GetNamespace(), actual.GetName())
+	}
+
+	if time.Now().After(issued.Certificate.NotAfter.Add(-params.Rotation.RotateBefore)) {
+		log.Info("Issued certificate soon to expire, replacing CertificateRequest",
+			"namespace", actual.GetNamespace(), "name", actual.GetName())
+		return nil, replaceCertificateRequest(params.Client, &actual, expected)
+	}
+	if !PrivateMatchesPublicKey(issued.Certificate.PublicKey, params.PrivateKey) {
+		return nil, fmt.Errorf("certificate issued for CertificateRequest %s/%s does not match the private key",
+			actual.GetNamespace(), actual.GetName())
+	}
+	return issued, nil
+}
+
+// DeleteCertificateRequest deletes the CertificateRequest with the given name, if it exists.
+func DeleteCertificateRequest(c k8s.Client, namespace string, name string) error {
+	certificateRequest := unstructured.Unstructured{}
+	certificateRequest.SetGroupVersionKind(CertificateRequestGVK)
+	certificateRequest.SetNamespace(namespace)
+	certificateRequest.SetName(name)
+	return deleteCertificateRequest(c, &certificateRequest)
+}
+
+func newCertificateRequest(params CertificateRequestParams, csr []byte) (*unstructured.Unstructured, error) {
+	issuerKind := params.IssuerRef.Kind
+	if issuerKind == "" {
+		issuerKind = DefaultIssuerKind
+	}
+	issuerGroup := params.IssuerRef.Group
+	if issuerGroup == "" {
+		issuerGroup = DefaultIssuerGroup
+	}
+
+	certificateRequest := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				// byte arrays are base64-encoded in the JSON representation of the CertificateRequest
+				"request": base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+				"issuerRef": map[string]interface{}{
+					"name":  params.IssuerRef.Name,
+					"kind":  issuerKind,
+					"group": issuerGroup,
+				},
+				"duration": params.Rotation.Validity.String(),
+				"usages":   certificateRequestUsages,
+			},
+		},
+	}
+	certificateRequest.SetGroupVersionKind(CertificateRequestGVK)
+	certificateRequest.SetNamespace(params.Owner.GetNamespace())
+	certificateRequest.SetName(params.Name)
+	certificateRequest.SetLabels(params.Labels)
+	if err := controllerutil.SetControllerReference(params.Owner, certificateRequest, scheme.Scheme); err != nil {
+		return nil, err
+	}
+	return certificateRequest, nil
+}
+
+// certificateRequestMatches returns true if the actual CertificateRequest has the expected issuer and duration, and
+// requests a certificate with the same subject, SANs and public key as the expected certificate request.
+func certificateRequestMatches(actual unstructured.Unstructured, expectedCSR []byte, expected *unstructured.Unstructured) bool {
+	for _, field := range [][]string{{"spec", "issuerRef"}, {"spec", "duration"}} {
+		actualValue, _, _ := unstructured.NestedFieldNoCopy(actual.Object, field...)
+		expectedValue, _, _ := unstructured.NestedFieldNoCopy(expected.Object, field...)
+		if !reflect.DeepEqual(actualValue, expectedValue) {
+			return false
+		}
+	}
+
+	encodedRequest, _, err := unstructured.NestedString(actual.Object, "spec", "request")
+	if err != nil {
+		return false
+	}
+	actualCSR, err := parseCertificateRequest(encodedRequest)
+	if err != nil {
+		return false
+	}
+	parsedExpectedCSR, err := x509.ParseCertificateRequest(expectedCSR)
+	if err != nil {
+		return false
+	}
+	// the signature of the request is not deterministic, compare its content only
+	return bytes.Equal(actualCSR.RawTBSCertificateRequest, parsedExpectedCSR.RawTBSCertificateRequest)
+}
+
+func parseCertificateRequest(encodedRequest string) (*x509.CertificateRequest, error) {
+	pemRequest, err := base64.StdEncoding.DecodeString(encodedRequest)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemRequest)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded certificate request found")
+	}
+	return x509.ParseCertificateRequest(block.Bytes)
+}
+
+// issuedCertificate returns the certificate issued for the given CertificateRequest, nil if it has not been signed yet,
+// or an error if the request was denied or failed.
+func issuedCertificate(certificateRequest unstructured.Unstructured) (*IssuedCertificate, error) {
+	conditions, _, err := unstructured.NestedSlice(certificateRequest.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	ready := false
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		switch {
+		case status == "True" && (conditionType == certificateRequestDeniedCondition || conditionType == certificateRequestInvalidRequestCondition),
+			status == "False" && conditionType == certificateRequestReadyCondition && reason == certificateRequestFailedReason:
+			return nil, fmt.Errorf("CertificateRequest %s/%s was not signed: %s",
+				certificateRequest.GetNamespace(), certificateRequest.GetName(), message)
+		case status == "True" && conditionType == certificateRequestReadyCondition:
+			ready = true
+		}
+	}
+	if !ready {
+		return nil, nil
+	}
+
+	chain, err := decodeStatusField(certificateRequest, "certificate")
+	if err != nil {
+		return nil, err
+	}
+	certs, err := ParsePEMCerts(chain)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found in CertificateRequest %s/%s",
+			certificateRequest.GetNamespace(), certificateRequest.GetName())
+	}
+	ca, err := decodeStatusField(certificateRequest, "ca")
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssuedCertificate{
+		Chain:       chain,
+		CA:          ca,
+		Certificate: certs[0],
+	}, nil
+}
+
+func decodeStatusField(certificateRequest unstructured.Unstructured, field string) ([]byte, error) {
+	encoded, _, err := unstructured.NestedString(certificateRequest.Object, "status", field)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+func replaceCertificateRequest(c k8s.Client, actual *unstructured.Unstructured, expected *unstructured.Unstructured) error {
+	// the spec of a CertificateRequest is immutable
+	if err := deleteCertificateRequest(c, actual); err != nil {
+		return err
+	}
+	return c.Create(context.Background(), expected)
+}
+
+func deleteCertificateRequest(c k8s.Client, certificateRequest *unstructured.Unstructured) error {
+	err := c.Delete(context.Background(), certificateRequest)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
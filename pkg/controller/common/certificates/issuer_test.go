@@ -0,0 +1,244 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var testIssuedCertificateKeys = IssuedCertificateKeys{
+	PrivateKey:        KeyFileName,
+	PendingPrivateKey: PendingKeyFileName,
+	Certificate:       CertFileName,
+}
+
+// fakeIssuer updates the status of CertificateRequests as an external issuer would do.
+type fakeIssuer struct {
+	ca       *CA
+	validity time.Duration
+	// withoutCA omits the CA certificate from the status of the signed CertificateRequests
+	withoutCA bool
+}
+
+func (f fakeIssuer) get(t *testing.T, c k8s.Client, name string) unstructured.Unstructured {
+	t.Helper()
+	certificateRequest := unstructured.Unstructured{}
+	certificateRequest.SetGroupVersionKind(CertificateRequestGVK)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testES.Namespace, Name: name}, &certificateRequest))
+	return certificateRequest
+}
+
+// sign signs the CertificateRequest with the CA of the issuer.
+func (f fakeIssuer) sign(t *testing.T, c k8s.Client, name string) {
+	t.Helper()
+	certificateRequest := f.get(t, c, name)
+	encodedRequest, _, err := unstructured.NestedString(certificateRequest.Object, "spec", "request")
+	require.NoError(t, err)
+	csr, err := parseCertificateRequest(encodedRequest)
+	require.NoError(t, err)
+
+	certData, err := f.ca.CreateCertificate(ValidatedCertificateTemplate(x509.Certificate{
+		Subject:            csr.Subject,
+		DNSNames:           csr.DNSNames,
+		IPAddresses:        csr.IPAddresses,
+		NotBefore:          time.Now().Add(-10 * time.Minute),
+		NotAfter:           time.Now().Add(f.validity),
+		PublicKeyAlgorithm: csr.PublicKeyAlgorithm,
+		PublicKey:          csr.PublicKey,
+	}))
+	require.NoError(t, err)
+
+	status := map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "reason": "Issued"},
+		},
+		"certificate": base64.StdEncoding.EncodeToString(EncodePEMCert(certData, f.ca.Cert.Raw)),
+	}
+	if !f.withoutCA {
+		status["ca"] = base64.StdEncoding.EncodeToString(EncodePEMCert(f.ca.Cert.Raw))
+	}
+	certificateRequest.Object["status"] = status
+	require.NoError(t, c.Update(context.Background(), &certificateRequest))
+}
+
+// deny marks the CertificateRequest as denied.
+func (f fakeIssuer) deny(t *testing.T, c k8s.Client, name string) {
+	t.Helper()
+	certificateRequest := f.get(t, c, name)
+	certificateRequest.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Denied", "status": "True", "reason": "Denied", "message": "denied by policy"},
+		},
+	}
+	require.NoError(t, c.Update(context.Background(), &certificateRequest))
+}
+
+func testCertificateRequestParams(c k8s.Client, dnsName string) CertificateRequestParams {
+	return CertificateRequestParams{
+		Client:    c,
+		Owner:     &testES,
+		Name:      "test-es-name-es-http-certs-internal",
+		IssuerRef: commonv1.IssuerReference{Name: "my-issuer", Kind: "ClusterIssuer"},
+		Template: x509.CertificateRequest{
+			Subject:  pkix.Name{CommonName: dnsName},
+			DNSNames: []string{dnsName},
+		},
+		Rotation: RotationParams{Validity: DefaultCertValidity, RotateBefore: DefaultRotateBefore},
+	}
+}
+
+func TestReconcileIssuedCertificate(t *testing.T) {
+	c := k8s.NewFakeClient()
+	issuer := fakeIssuer{ca: testCA, validity: DefaultCertValidity}
+	params := testCertificateRequestParams(c, "es.example.com")
+	data := map[string][]byte{}
+
+	// a pending private key is generated and a certificate is requested for it
+	changed, issued, err := ReconcileIssuedCertificate(data, testIssuedCertificateKeys, DefaultPrivateKeyOptions, params)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Nil(t, issued)
+	require.Contains(t, data, PendingKeyFileName)
+	require.NotContains(t, data, KeyFileName)
+	require.NotContains(t, data, CertFileName)
+	certificateRequest := issuer.get(t, c, params.Name)
+	issuerRef, _, err := unstructured.NestedStringMap(certificateRequest.Object, "spec", "issuerRef")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"name": "my-issuer", "kind": "ClusterIssuer", "group": "cert-manager.io"}, issuerRef)
+
+	// nothing changes until the request is signed
+	changed, issued, err = ReconcileIssuedCertificate(data, testIssuedCertificateKeys, DefaultPrivateKeyOptions, params)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Nil(t, issued)
+
+	// once signed, the pending private key is used along with the issued certificate
+	pendingPrivateKey := data[PendingKeyFileName]
+	issuer.sign(t, c, params.Name)
+	changed, issued, err = ReconcileIssuedCertificate(data, testIssuedCertificateKeys, DefaultPrivateKeyOptions, params)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.NotNil(t, issued)
+	require.Equal(t, pendingPrivateKey, data[KeyFileName])
+	require.NotContains(t, data, PendingKeyFileName)
+	require.Equal(t, issued.Chain, data[CertFileName])
+	require.Equal(t, EncodePEMCert(testCA.Cert.Raw), issued.CA)
+	require.Equal(t, []string{"es.example.com"}, issued.Certificate.DNSNames)
+
+	// the issued certificate is reused
+	changed, issued, err = ReconcileIssuedCertificate(data, testIssuedCertificateKeys, DefaultPrivateKeyOptions, params)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.NotNil(t, issued)
+
+	// a new certificate is requested for the same private key if the SANs change, the current one is kept meanwhile
+	currentCert := data[CertFileName]
+	params = testCertificateRequestParams(c, "es2.example.com")
+	changed, issued, err = ReconcileIssuedCertificate(data, testIssuedCertificateKeys, DefaultPrivateKeyOptions, params)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Nil(t, issued)
+	require.Equal(t, currentCert, data[CertFileName])
+	issuer.sign(t, c, params.Name)
+	changed, issued, err = ReconcileIssuedCertificate(data, testIssuedCertificateKeys, DefaultPrivateKeyOptions, params)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, []string{"es2.example.com"}, issued.Certificate.DNSNames)
+	require.Equal(t, pendingPrivateKey, data[KeyFileName])
+
+	// a new private key is generated if the private key options change, the current one is kept meanwhile
+	ecdsaOptions := commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm}
+	changed, issued, err = ReconcileIssuedCertificate(data, testIssuedCertificateKeys, ecdsaOptions, params)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Nil(t, issued)
+	require.Contains(t, data, PendingKeyFileName)
+	require.Equal(t, pendingPrivateKey, data[KeyFileName])
+	issuer.sign(t, c, params.Name)
+	_, issued, err = ReconcileIssuedCertificate(data, testIssuedCertificateKeys, ecdsaOptions, params)
+	require.NoError(t, err)
+	require.NotNil(t, issued)
+	privateKey, err := ParsePEMPrivateKey(data[KeyFileName])
+	require.NoError(t, err)
+	require.True(t, PrivateKeyMatchesOptions(privateKey, ecdsaOptions))
+	require.True(t, PrivateMatchesPublicKey(issued.Certificate.PublicKey, privateKey))
+}
+
+func TestReconcileCertificateRequest(t *testing.T) {
+	privateKey, err := GeneratePrivateKey(DefaultPrivateKeyOptions)
+	require.NoError(t, err)
+
+	t.Run("denied request is deleted", func(t *testing.T) {
+		c := k8s.NewFakeClient()
+		issuer := fakeIssuer{ca: testCA, validity: DefaultCertValidity}
+		params := testCertificateRequestParams(c, "es.example.com")
+		params.PrivateKey = privateKey
+
+		issued, err := ReconcileCertificateRequest(params)
+		require.NoError(t, err)
+		require.Nil(t, issued)
+		issuer.deny(t, c, params.Name)
+
+		_, err = ReconcileCertificateRequest(params)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "denied by policy")
+		certificateRequest := unstructured.Unstructured{}
+		certificateRequest.SetGroupVersionKind(CertificateRequestGVK)
+		err = c.Get(context.Background(), types.NamespacedName{Namespace: testES.Namespace, Name: params.Name}, &certificateRequest)
+		require.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("certificate signed without CA is rejected", func(t *testing.T) {
+		c := k8s.NewFakeClient()
+		issuer := fakeIssuer{ca: testCA, validity: DefaultCertValidity, withoutCA: true}
+		params := testCertificateRequestParams(c, "es.example.com")
+		params.PrivateKey = privateKey
+
+		_, err := ReconcileCertificateRequest(params)
+		require.NoError(t, err)
+		issuer.sign(t, c, params.Name)
+
+		issued, err := ReconcileCertificateRequest(params)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "without the CA certificate of the issuer")
+		require.Nil(t, issued)
+		// the request is kept as a new one would not be signed differently
+		certificateRequest := issuer.get(t, c, params.Name)
+		_, hasStatus := certificateRequest.Object["status"]
+		require.True(t, hasStatus)
+	})
+
+	t.Run("certificate due for rotation is requested again", func(t *testing.T) {
+		c := k8s.NewFakeClient()
+		issuer := fakeIssuer{ca: testCA, validity: time.Hour}
+		params := testCertificateRequestParams(c, "es.example.com")
+		params.PrivateKey = privateKey
+
+		_, err := ReconcileCertificateRequest(params)
+		require.NoError(t, err)
+		issuer.sign(t, c, params.Name)
+
+		// the issued certificate expires before the rotation window
+		issued, err := ReconcileCertificateRequest(params)
+		require.NoError(t, err)
+		require.Nil(t, issued)
+		certificateRequest := issuer.get(t, c, params.Name)
+		_, hasStatus := certificateRequest.Object["status"]
+		require.False(t, hasStatus)
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+)
+
+const (
+	// DefaultRSAKeySize is the size in bits of the RSA private keys generated if no size is specified.
+	DefaultRSAKeySize = 2048
+	// DefaultECDSAKeySize is the size in bits of the ECDSA private keys generated if no size is specified.
+	DefaultECDSAKeySize = 256
+)
+
+var (
+	// DefaultPrivateKeyOptions are the options used to generate private keys if not specified otherwise.
+	DefaultPrivateKeyOptions = commonv1.PrivateKeyOptions{
+		Algorithm: commonv1.RSAPrivateKeyAlgorithm,
+		Size:      DefaultRSAKeySize,
+	}
+
+	ellipticCurves = map[int]elliptic.Curve{
+		256: elliptic.P256(),
+		384: elliptic.P384(),
+		521: elliptic.P521(),
+	}
+)
+
+// WithDefaults returns the given private key options with the default algorithm and the default size of the
+// algorithm set if missing.
+func WithDefaults(options commonv1.PrivateKeyOptions) commonv1.PrivateKeyOptions {
+	if options.Algorithm == "" {
+		options.Algorithm = commonv1.RSAPrivateKeyAlgorithm
+	}
+	if options.Size == 0 {
+		options.Size = DefaultRSAKeySize
+		if options.Algorithm == commonv1.ECDSAPrivateKeyAlgorithm {
+			options.Size = DefaultECDSAKeySize
+		}
+	}
+	return options
+}
+
+// MergePrivateKeyOptions returns the options to generate the private keys of a resource. The options specified for the
+// resource take precedence over the operator ones. The size of the operator options is ignored if the resource
+// specifies a different algorithm.
+func MergePrivateKeyOptions(operatorOptions commonv1.PrivateKeyOptions, resourceOptions *commonv1.PrivateKeyOptions) commonv1.PrivateKeyOptions {
+	merged := WithDefaults(operatorOptions)
+	if resourceOptions == nil {
+		return merged
+	}
+	if resourceOptions.Algorithm != "" && resourceOptions.Algorithm != merged.Algorithm {
+		merged.Algorithm = resourceOptions.Algorithm
+		merged.Size = 0
+	}
+	if resourceOptions.Size != 0 {
+		merged.Size = resourceOptions.Size
+	}
+	return WithDefaults(merged)
+}
+
+// ValidatePrivateKeyOptions returns an error if the given algorithm or size are not supported.
+func ValidatePrivateKeyOptions(options commonv1.PrivateKeyOptions) error {
+	return WithDefaults(options).Validate()
+}
+
+// GeneratePrivateKey generates a new private key with the given algorithm and size.
+func GeneratePrivateKey(options commonv1.PrivateKeyOptions) (crypto.Signer, error) {
+	options = WithDefaults(options)
+	if err := ValidatePrivateKeyOptions(options); err != nil {
+		return nil, err
+	}
+	if options.Algorithm == commonv1.ECDSAPrivateKeyAlgorithm {
+		return ecdsa.GenerateKey(ellipticCurves[options.Size], cryptorand.Reader)
+	}
+	return rsa.GenerateKey(cryptorand.Reader, options.Size)
+}
+
+// PrivateKeyMatchesOptions returns true if the given private key has the algorithm and the size of the given options.
+func PrivateKeyMatchesOptions(privateKey crypto.Signer, options commonv1.PrivateKeyOptions) bool {
+	options = WithDefaults(options)
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return options.Algorithm == commonv1.RSAPrivateKeyAlgorithm && key.N.BitLen() == options.Size
+	case *ecdsa.PrivateKey:
+		return options.Algorithm == commonv1.ECDSAPrivateKeyAlgorithm && key.Curve.Params().BitSize == options.Size
+	default:
+		return false
+	}
+}
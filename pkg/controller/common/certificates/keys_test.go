@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package certificates
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"testing"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePrivateKeyOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		operatorOptions commonv1.PrivateKeyOptions
+		resourceOptions *commonv1.PrivateKeyOptions
+		want            commonv1.PrivateKeyOptions
+	}{
+		{
+			name: "no options",
+			want: commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 2048},
+		},
+		{
+			name:            "operator options only",
+			operatorOptions: commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm},
+			want:            commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 256},
+		},
+		{
+			name:            "resource algorithm overrides the operator algorithm and size",
+			operatorOptions: commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 4096},
+			resourceOptions: &commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm},
+			want:            commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 256},
+		},
+		{
+			name:            "resource with the same algorithm keeps the operator size",
+			operatorOptions: commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 384},
+			resourceOptions: &commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm},
+			want:            commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 384},
+		},
+		{
+			name:            "resource size overrides the operator size",
+			operatorOptions: commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm},
+			resourceOptions: &commonv1.PrivateKeyOptions{Size: 521},
+			want:            commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 521},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MergePrivateKeyOptions(tt.operatorOptions, tt.resourceOptions))
+		})
+	}
+}
+
+func TestValidatePrivateKeyOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options commonv1.PrivateKeyOptions
+		wantErr bool
+	}{
+		{
+			name:    "default options",
+			options: commonv1.PrivateKeyOptions{},
+		},
+		{
+			name:    "RSA 4096",
+			options: commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 4096},
+		},
+		{
+			name:    "ECDSA 384",
+			options: commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 384},
+		},
+		{
+			name:    "RSA 1024",
+			options: commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 1024},
+			wantErr: true,
+		},
+		{
+			name:    "ECDSA 2048",
+			options: commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 2048},
+			wantErr: true,
+		},
+		{
+			name:    "unknown algorithm",
+			options: commonv1.PrivateKeyOptions{Algorithm: "DSA"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePrivateKeyOptions(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePrivateKeyOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeneratePrivateKey(t *testing.T) {
+	rsaKey, err := GeneratePrivateKey(commonv1.PrivateKeyOptions{})
+	require.NoError(t, err)
+	require.IsType(t, &rsa.PrivateKey{}, rsaKey)
+	require.True(t, PrivateKeyMatchesOptions(rsaKey, DefaultPrivateKeyOptions))
+
+	ecdsaOptions := commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 384}
+	ecdsaKey, err := GeneratePrivateKey(ecdsaOptions)
+	require.NoError(t, err)
+	require.IsType(t, &ecdsa.PrivateKey{}, ecdsaKey)
+	require.True(t, PrivateKeyMatchesOptions(ecdsaKey, ecdsaOptions))
+	require.False(t, PrivateKeyMatchesOptions(ecdsaKey, commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm}))
+	require.False(t, PrivateKeyMatchesOptions(ecdsaKey, DefaultPrivateKeyOptions))
+	require.False(t, PrivateKeyMatchesOptions(rsaKey, ecdsaOptions))
+
+	_, err = GeneratePrivateKey(commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 128})
+	require.Error(t, err)
+}
@@ -6,6 +6,8 @@ package certificates
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -50,17 +52,33 @@ func EncodePEMCert(certBlocks ...[]byte) []byte {
 	return buf.Bytes()
 }
 
-// EncodePEMPrivateKey encodes the given private key in the PEM format
-func EncodePEMPrivateKey(privateKey rsa.PrivateKey) []byte {
-	return pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(&privateKey),
-	})
+// EncodePEMPrivateKey encodes the given private key in the PEM format.
+// RSA private keys are encoded in the PKCS #1 format, ECDSA private keys in the SEC 1 format.
+func EncodePEMPrivateKey(privateKey crypto.Signer) ([]byte, error) {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}), nil
+	case *ecdsa.PrivateKey:
+		keyBytes, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal private key")
+		}
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: keyBytes,
+		}), nil
+	default:
+		return nil, errors.Errorf("unsupported private key type %T", privateKey)
+	}
 }
 
-// ParsePEMPrivateKey parses the given private key in the PEM format
+// ParsePEMPrivateKey parses the given RSA or ECDSA private key in the PEM format.
+// PKCS #1 and PKCS #8 formats are supported for RSA private keys, SEC 1 and PKCS #8 formats for ECDSA private keys.
 // ErrEncryptedPrivateKey is returned as an error if the private key is encrypted.
-func ParsePEMPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+func ParsePEMPrivateKey(pemData []byte) (crypto.Signer, error) {
 	block, _ := pem.Decode(pemData)
 	if block == nil {
 		return nil, errors.New("failed to parse PEM block containing private key")
@@ -74,23 +92,27 @@ func ParsePEMPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
 		return parsePKCS8PrivateKey(block.Bytes)
 	case block.Type == "RSA PRIVATE KEY" && len(block.Headers) == 0:
 		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case block.Type == "EC PRIVATE KEY" && len(block.Headers) == 0:
+		return x509.ParseECPrivateKey(block.Bytes)
 	default:
-		return nil, errors.New("expected PEM block to contain an RSA private key")
+		return nil, errors.New("expected PEM block to contain an RSA or ECDSA private key")
 	}
 }
 
-func parsePKCS8PrivateKey(block []byte) (*rsa.PrivateKey, error) {
+func parsePKCS8PrivateKey(block []byte) (crypto.Signer, error) {
 	key, err := x509.ParsePKCS8PrivateKey(block)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse private key")
 	}
 
-	rsaKey, ok := key.(*rsa.PrivateKey)
-	if !ok {
-		return nil, errors.Errorf("expected an RSA private key but got %t", key)
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, errors.Errorf("expected an RSA or ECDSA private key but got %T", key)
 	}
-
-	return rsaKey, nil
 }
 
 // GetPrimaryCertificate returns the primary certificate (i.e. the actual subject, not a CA or intermediate) from a PEM certificate chain
@@ -108,15 +130,14 @@ func GetPrimaryCertificate(pemBytes []byte) (*x509.Certificate, error) {
 }
 
 // PrivateMatchesPublicKey returns true if the public and private keys correspond to each other.
-func PrivateMatchesPublicKey(publicKey interface{}, privateKey rsa.PrivateKey) bool {
-	pubKey, ok := publicKey.(*rsa.PublicKey)
-	if !ok {
-		log.Error(errors.New("Public key is not an RSA public key"), "")
-		return false
-	}
-	// check that public and private keys share the same modulus and exponent
-	if pubKey.N.Cmp(privateKey.N) != 0 || pubKey.E != privateKey.E {
+func PrivateMatchesPublicKey(publicKey interface{}, privateKey crypto.Signer) bool {
+	switch pubKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		return pubKey.Equal(privateKey.Public())
+	case *ecdsa.PublicKey:
+		return pubKey.Equal(privateKey.Public())
+	default:
+		log.Error(errors.New("Public key is neither an RSA nor an ECDSA public key"), "")
 		return false
 	}
-	return true
 }
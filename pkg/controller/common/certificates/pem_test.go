@@ -7,8 +7,13 @@
 package certificates
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	cryptorand "crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -19,22 +24,44 @@ func Test_PrivateMatchesPublicKey(t *testing.T) {
 	require.NoError(t, err)
 	privateKey2, err := rsa.GenerateKey(cryptorand.Reader, 2048)
 	require.NoError(t, err)
+	ecPrivateKey1, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
+	ecPrivateKey2, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
 	tests := []struct {
 		name       string
 		publicKey  interface{}
-		privateKey rsa.PrivateKey
+		privateKey crypto.Signer
 		want       bool
 	}{
 		{
 			name:       "with matching public and private keys",
 			publicKey:  privateKey1.Public(),
-			privateKey: *privateKey1,
+			privateKey: privateKey1,
 			want:       true,
 		},
 		{
 			name:       "with non-matching public and private keys",
 			publicKey:  privateKey1.Public(),
-			privateKey: *privateKey2,
+			privateKey: privateKey2,
+			want:       false,
+		},
+		{
+			name:       "with matching ECDSA public and private keys",
+			publicKey:  ecPrivateKey1.Public(),
+			privateKey: ecPrivateKey1,
+			want:       true,
+		},
+		{
+			name:       "with non-matching ECDSA public and private keys",
+			publicKey:  ecPrivateKey1.Public(),
+			privateKey: ecPrivateKey2,
+			want:       false,
+		},
+		{
+			name:       "with public and private keys of different algorithms",
+			publicKey:  privateKey1.Public(),
+			privateKey: ecPrivateKey1,
 			want:       false,
 		},
 	}
@@ -46,3 +73,63 @@ func Test_PrivateMatchesPublicKey(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePEMPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), cryptorand.Reader)
+	require.NoError(t, err)
+	pkcs8 := func(key crypto.Signer) []byte {
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	}
+	encoded := func(key crypto.Signer) []byte {
+		keyBytes, err := EncodePEMPrivateKey(key)
+		require.NoError(t, err)
+		return keyBytes
+	}
+	tests := []struct {
+		name    string
+		pemData []byte
+		want    crypto.Signer
+		wantErr bool
+	}{
+		{
+			name:    "PKCS #1 RSA private key",
+			pemData: encoded(rsaKey),
+			want:    rsaKey,
+		},
+		{
+			name:    "PKCS #8 RSA private key",
+			pemData: pkcs8(rsaKey),
+			want:    rsaKey,
+		},
+		{
+			name:    "SEC 1 ECDSA private key",
+			pemData: encoded(ecKey),
+			want:    ecKey,
+		},
+		{
+			name:    "PKCS #8 ECDSA private key",
+			pemData: pkcs8(ecKey),
+			want:    ecKey,
+		},
+		{
+			name:    "not a PEM private key",
+			pemData: []byte("not a private key"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePEMPrivateKey(tt.pemData)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.Public(), got.Public())
+		})
+	}
+}
@@ -6,6 +6,7 @@ package certificates
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
@@ -43,6 +44,8 @@ type Reconciler struct {
 	CACertRotation RotationParams // to requeue a reconciliation before CA cert expiration
 	CertRotation   RotationParams // to requeue a reconciliation before cert expiration
 
+	PrivateKeyOptions commonv1.PrivateKeyOptions // operator defaults to generate private keys, overridden by the TLS options
+
 	GarbageCollectSecrets bool // if true, delete secrets if TLS is disabled
 }
 
@@ -61,28 +64,44 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 		return nil, results.WithError(r.removeCAAndHTTPCertsSecrets())
 	}
 
-	// reconcile CA certs first
-	httpCa, err := ReconcileCAForOwner(
-		r.K8sClient,
-		r.Namer,
-		r.Owner,
-		r.Labels,
-		HTTPCAType,
-		r.CACertRotation,
-	)
-	if err != nil {
-		return nil, results.WithError(err)
+	// reconcile CA certs first, unless the HTTP certificate is signed by an external issuer
+	var httpCa *CA
+	if !r.TLSOptions.UsesIssuer() {
+		ca, err := ReconcileCAForOwner(
+			r.K8sClient,
+			r.Namer,
+			r.Owner,
+			r.Labels,
+			HTTPCAType,
+			r.CACertRotation,
+			r.privateKeyOptions(),
+		)
+		if err != nil {
+			return nil, results.WithError(err)
+		}
+		// handle CA expiry via requeue
+		results.WithResult(reconcile.Result{
+			RequeueAfter: ShouldRotateIn(time.Now(), ca.Cert.NotAfter, r.CACertRotation.RotateBefore),
+		})
+		httpCa = ca
 	}
-	// handle CA expiry via requeue
-	results.WithResult(reconcile.Result{
-		RequeueAfter: ShouldRotateIn(time.Now(), httpCa.Cert.NotAfter, r.CACertRotation.RotateBefore),
-	})
 
-	// reconcile http certificates: either self-signed or user-provided
-	httpCertificates, err := r.ReconcileInternalHTTPCerts(httpCa)
+	// reconcile http certificates: either self-signed, issued by an external issuer or user-provided
+	httpCertificates, pending, err := r.ReconcileInternalHTTPCerts(httpCa)
 	if err != nil {
 		return nil, results.WithError(err)
 	}
+	if pending {
+		// CertificateRequests are not watched, check again later
+		results.WithIncompleteReconciliation(
+			reconcile.Result{RequeueAfter: CertificateRequestPollInterval},
+			"HTTP certificate is being issued",
+		)
+		if len(httpCertificates.CertPem()) == 0 {
+			return nil, results.WithError(fmt.Errorf("HTTP certificate for %s/%s has not been issued yet by issuer %s",
+				r.Owner.GetNamespace(), r.Owner.GetName(), r.TLSOptions.IssuerRef.Name))
+		}
+	}
 	primaryCert, err := GetPrimaryCertificate(httpCertificates.CertPem())
 	if err != nil {
 		return nil, results.WithError(err)
@@ -96,6 +115,11 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 	return httpCertificates, results
 }
 
+// privateKeyOptions returns the options to generate the private keys of the CA and of the self-signed certificate.
+func (r Reconciler) privateKeyOptions() commonv1.PrivateKeyOptions {
+	return MergePrivateKeyOptions(r.PrivateKeyOptions, r.TLSOptions.PrivateKey)
+}
+
 func (r *Reconciler) removeCAAndHTTPCertsSecrets() error {
 	owner := k8s.ExtractNamespacedName(r.Owner)
 	// remove public certs secret
@@ -24,6 +24,8 @@ const (
 	CertFileName = "tls.crt"
 	// KeyFileName is used for Private Keys inside a secret
 	KeyFileName = "tls.key"
+	// PendingKeyFileName is used for Private Keys waiting for a Certificate to be issued by an external issuer
+	PendingKeyFileName = "tls.pending.key"
 
 	// certificate secrets suffixes
 	certsPublicSecretName   = "certs-public"
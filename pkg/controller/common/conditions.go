@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package common
+
+import (
+	"fmt"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetCondition sets the given condition in the list of conditions, observed for the given generation of the resource.
+// The last transition time of the condition is only updated if its status changes.
+func SetCondition(conditions *[]metav1.Condition, generation int64, condition metav1.Condition) {
+	condition.ObservedGeneration = generation
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// ReconciliationCompleteCondition returns the ReconciliationComplete condition computed from the results of a
+// reconciliation run.
+func ReconciliationCompleteCondition(results *reconciler.Results) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    commonv1.ReconciliationComplete,
+		Status:  metav1.ConditionTrue,
+		Reason:  commonv1.ReconciledReason,
+		Message: "Reconciliation completed",
+	}
+	if reconciled, message := results.IsReconciled(); !reconciled {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = commonv1.ReconciliationInProgressReason
+		if results.HasError() {
+			condition.Reason = commonv1.ReconciliationErrorReason
+		}
+		condition.Message = message
+	}
+	return condition
+}
+
+// RunningDesiredVersionCondition returns the RunningDesiredVersion condition computed from the lowest version
+// currently running and the version specified in the spec of the resource.
+func RunningDesiredVersionCondition(runningVersion, desiredVersion string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   commonv1.RunningDesiredVersion,
+		Status: metav1.ConditionUnknown,
+		Reason: commonv1.VersionUnknownReason,
+	}
+	running, err := version.Parse(runningVersion)
+	if err != nil {
+		condition.Message = "Running version is not known yet"
+		return condition
+	}
+	desired, err := version.Parse(desiredVersion)
+	if err != nil {
+		condition.Message = fmt.Sprintf("Cannot parse desired version %s", desiredVersion)
+		return condition
+	}
+	if !running.EQ(desired) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = commonv1.VersionMismatchReason
+		condition.Message = fmt.Sprintf("Lowest running version is %s, desired version is %s", running, desired)
+		return condition
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = commonv1.DesiredVersionRunningReason
+	condition.Message = fmt.Sprintf("Running version %s", running)
+	return condition
+}
+
+// UpdateConditions sets the conditions common to all the resources managed by the operator in the given list of
+// conditions.
+func UpdateConditions(
+	conditions *[]metav1.Condition,
+	generation int64,
+	results *reconciler.Results,
+	runningVersion, desiredVersion string,
+) {
+	SetCondition(conditions, generation, ReconciliationCompleteCondition(results))
+	SetCondition(conditions, generation, RunningDesiredVersionCondition(runningVersion, desiredVersion))
+}
@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRunningDesiredVersionCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		running    string
+		desired    string
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "running version not known",
+			running:    "",
+			desired:    "7.14.0",
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: commonv1.VersionUnknownReason,
+		},
+		{
+			name:       "upgrade in progress",
+			running:    "7.13.0",
+			desired:    "7.14.0",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: commonv1.VersionMismatchReason,
+		},
+		{
+			name:       "desired version running",
+			running:    "7.14.0",
+			desired:    "7.14.0",
+			wantStatus: metav1.ConditionTrue,
+			wantReason: commonv1.DesiredVersionRunningReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunningDesiredVersionCondition(tt.running, tt.desired)
+			assert.Equal(t, commonv1.RunningDesiredVersion, got.Type)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantReason, got.Reason)
+		})
+	}
+}
+
+func TestReconciliationCompleteCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    *reconciler.Results
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "reconciled",
+			results:    reconciler.NewResult(context.Background()),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: commonv1.ReconciledReason,
+		},
+		{
+			name:       "requeued",
+			results:    reconciler.NewResult(context.Background()).WithResult(reconcile.Result{Requeue: true}),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: commonv1.ReconciliationInProgressReason,
+		},
+		{
+			name:       "error",
+			results:    reconciler.NewResult(context.Background()).WithError(errors.New("boom")),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: commonv1.ReconciliationErrorReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReconciliationCompleteCondition(tt.results)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantReason, got.Reason)
+		})
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	var conditions []metav1.Condition
+	SetCondition(&conditions, 1, RunningDesiredVersionCondition("7.13.0", "7.14.0"))
+	require.Len(t, conditions, 1)
+	transitionTime := conditions[0].LastTransitionTime
+
+	// same status: the transition time is preserved, the observed generation is updated
+	SetCondition(&conditions, 2, RunningDesiredVersionCondition("7.13.1", "7.14.0"))
+	require.Len(t, conditions, 1)
+	assert.Equal(t, transitionTime, conditions[0].LastTransitionTime)
+	assert.Equal(t, int64(2), conditions[0].ObservedGeneration)
+	assert.Contains(t, conditions[0].Message, "7.13.1")
+}
@@ -58,20 +58,11 @@ func NewPodTemplateBuilder(base corev1.PodTemplateSpec, containerName string) *P
 // setDefaults sets up a default Container in the pod template,
 // and disables service account token auto mount.
 func (b *PodTemplateBuilder) setDefaults() *PodTemplateBuilder {
-	// retrieve the existing Container from the pod template
-	getContainer := func() *corev1.Container {
-		for i, c := range b.PodTemplate.Spec.Containers {
-			if c.Name == b.containerName {
-				return &b.PodTemplate.Spec.Containers[i]
-			}
-		}
-		return nil
-	}
-	userContainer := getContainer()
+	userContainer := b.getContainer()
 	if userContainer == nil {
 		// create the default Container if not provided by the user
 		b.PodTemplate.Spec.Containers = append(b.PodTemplate.Spec.Containers, corev1.Container{Name: b.containerName})
-		b.containerDefaulter = container.NewDefaulter(getContainer())
+		b.containerDefaulter = container.NewDefaulter(b.getContainer())
 	} else {
 		b.containerDefaulter = container.NewDefaulter(userContainer)
 	}
@@ -85,6 +76,16 @@ func (b *PodTemplateBuilder) setDefaults() *PodTemplateBuilder {
 	return b
 }
 
+// getContainer retrieves the existing main Container from the pod template.
+func (b *PodTemplateBuilder) getContainer() *corev1.Container {
+	for i, c := range b.PodTemplate.Spec.Containers {
+		if c.Name == b.containerName {
+			return &b.PodTemplate.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
 // WithLabels sets the given labels, but does not override those that already exist.
 func (b *PodTemplateBuilder) WithLabels(labels map[string]string) *PodTemplateBuilder {
 	b.PodTemplate.Labels = maps.MergePreservingExistingKeys(b.PodTemplate.Labels, labels)
@@ -123,6 +124,14 @@ func (b *PodTemplateBuilder) WithAffinity(affinity *corev1.Affinity) *PodTemplat
 	return b
 }
 
+// WithTopologySpreadConstraints sets default topology spread constraints, unless already provided in the template.
+func (b *PodTemplateBuilder) WithTopologySpreadConstraints(constraints ...corev1.TopologySpreadConstraint) *PodTemplateBuilder {
+	if len(b.PodTemplate.Spec.TopologySpreadConstraints) == 0 {
+		b.PodTemplate.Spec.TopologySpreadConstraints = constraints
+	}
+	return b
+}
+
 // WithPorts appends the given ports to the Container ports, unless already provided in the template.
 func (b *PodTemplateBuilder) WithPorts(ports []corev1.ContainerPort) *PodTemplateBuilder {
 	b.containerDefaulter.WithPorts(ports)
@@ -199,6 +208,8 @@ func (b *PodTemplateBuilder) WithInitContainerDefaults(additionalEnvVars ...core
 				WithEnv(ExtendPodDownwardEnvVars(additionalEnvVars...)).
 				Container()
 	}
+	// the containers slice may have been reallocated, make sure the defaulter still points to the main container
+	b.containerDefaulter = container.NewDefaulter(b.getContainer())
 	return b
 }
 
@@ -253,6 +264,36 @@ func (b *PodTemplateBuilder) WithInitContainers(
 	return b
 }
 
+// WithContainers includes the given containers to the pod template.
+//
+// Ordering:
+// - Provided containers are appended to the existing ones in the template.
+// - If a container by the same name already exists in the template, the two containers are merged in place, the values
+// provided by the user take precedence.
+func (b *PodTemplateBuilder) WithContainers(containers ...corev1.Container) *PodTemplateBuilder {
+	for _, c := range containers {
+		index := -1
+		for i, existing := range b.PodTemplate.Spec.Containers {
+			if existing.Name == c.Name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			b.PodTemplate.Spec.Containers = append(b.PodTemplate.Spec.Containers, c)
+			continue
+		}
+		userContainer := b.PodTemplate.Spec.Containers[index]
+		b.PodTemplate.Spec.Containers[index] = container.
+			// Set the container provided by the user as the base.
+			NewDefaulter(userContainer.DeepCopy()).
+			// Inherit all other values from the container built by the controller.
+			From(c).
+			Container()
+	}
+	return b
+}
+
 // WithResources sets up the given resource requirements if both resources limits and requests
 // are nil in the main container.
 // If a zero-value (empty map) for at least one of limits or request is provided, the given resource requirements
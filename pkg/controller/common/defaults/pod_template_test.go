@@ -313,6 +313,52 @@ func TestPodTemplateBuilder_WithAffinity(t *testing.T) {
 	}
 }
 
+func TestPodTemplateBuilder_WithTopologySpreadConstraints(t *testing.T) {
+	defaultConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+	}
+	userConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           2,
+		TopologyKey:       "topology.kubernetes.io/region",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+
+	containerName := "mycontainer"
+	tests := []struct {
+		name        string
+		PodTemplate corev1.PodTemplateSpec
+		constraints []corev1.TopologySpreadConstraint
+		want        []corev1.TopologySpreadConstraint
+	}{
+		{
+			name:        "set default topology spread constraints",
+			PodTemplate: corev1.PodTemplateSpec{},
+			constraints: []corev1.TopologySpreadConstraint{defaultConstraint},
+			want:        []corev1.TopologySpreadConstraint{defaultConstraint},
+		},
+		{
+			name: "don't override user-provided topology spread constraints",
+			PodTemplate: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{userConstraint},
+				},
+			},
+			constraints: []corev1.TopologySpreadConstraint{defaultConstraint},
+			want:        []corev1.TopologySpreadConstraint{userConstraint},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewPodTemplateBuilder(tt.PodTemplate, containerName)
+			if got := b.WithTopologySpreadConstraints(tt.constraints...).PodTemplate.Spec.TopologySpreadConstraints; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PodTemplateBuilder.WithTopologySpreadConstraints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPodTemplateBuilder_WithPorts(t *testing.T) {
 	containerName := "mycontainer"
 	tests := []struct {
@@ -936,6 +982,70 @@ func TestPodTemplateBuilder_WithInitContainers(t *testing.T) {
 	}
 }
 
+func TestPodTemplateBuilder_WithContainers(t *testing.T) {
+	tests := []struct {
+		name        string
+		PodTemplate corev1.PodTemplateSpec
+		containers  []corev1.Container
+		want        []corev1.Container
+	}{
+		{
+			name:        "append provided containers after the main container",
+			PodTemplate: corev1.PodTemplateSpec{},
+			containers:  []corev1.Container{{Name: "sidecar1"}, {Name: "sidecar2"}},
+			want:        []corev1.Container{{Name: "main"}, {Name: "sidecar1"}, {Name: "sidecar2"}},
+		},
+		{
+			name: "merge operator and user-provided containers",
+			PodTemplate: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "sidecar1",
+							Image: "user-image",
+						},
+					},
+				},
+			},
+			containers: []corev1.Container{
+				{
+					Name:  "sidecar1",
+					Image: "dont-override",
+					Args:  []string{"-e"},
+				},
+				{
+					Name:  "sidecar2",
+					Image: "image2",
+				},
+			},
+			want: []corev1.Container{
+				{
+					Name:  "sidecar1",
+					Image: "user-image",
+					Args:  []string{"-e"},
+				},
+				{
+					Name: "main",
+				},
+				{
+					Name:  "sidecar2",
+					Image: "image2",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewPodTemplateBuilder(tt.PodTemplate, "main")
+
+			got := b.WithContainers(tt.containers...).PodTemplate.Spec.Containers
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestPodTemplateBuilder_WithDefaultResources(t *testing.T) {
 	containerName := "default-container"
 	tests := []struct {
@@ -5,9 +5,13 @@
 package operator
 
 const (
+	APIKeyRotateBeforeFlag        = "api-key-rotate-before"
+	APIKeyValidityFlag            = "api-key-validity"
 	AutoPortForwardFlag           = "auto-port-forward"
 	CACertRotateBeforeFlag        = "ca-cert-rotate-before"
 	CACertValidityFlag            = "ca-cert-validity"
+	CertKeyAlgorithmFlag          = "cert-key-algorithm"
+	CertKeySizeFlag               = "cert-key-size"
 	CertRotateBeforeFlag          = "cert-rotate-before"
 	CertValidityFlag              = "cert-validity"
 	ConfigFlag                    = "config"
@@ -6,6 +6,7 @@ package reconciler
 
 import (
 	"context"
+	"strings"
 
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
 	"go.elastic.co/apm"
@@ -38,6 +39,11 @@ type Results struct {
 	currKind   resultKind
 	errors     []error
 	ctx        context.Context
+	// requeued is true if a step explicitly requested a requeue to complete the reconciliation, as opposed to a
+	// periodic requeue (eg. certificates rotation).
+	requeued bool
+	// incompleteReasons explains why the reconciliation is not complete yet.
+	incompleteReasons []string
 }
 
 func NewResult(ctx context.Context) *Results {
@@ -56,6 +62,8 @@ func (r *Results) WithResults(other *Results) *Results {
 	if other != nil {
 		r.mergeResult(other.currKind, other.currResult)
 		r.errors = append(r.errors, other.errors...)
+		r.requeued = r.requeued || other.requeued
+		r.incompleteReasons = append(r.incompleteReasons, other.incompleteReasons...)
 	}
 	return r
 }
@@ -72,9 +80,32 @@ func (r *Results) WithError(err error) *Results {
 func (r *Results) WithResult(res reconcile.Result) *Results {
 	kind := kindOf(res)
 	r.mergeResult(kind, res)
+	r.requeued = r.requeued || res.Requeue
 	return r
 }
 
+// WithIncompleteReconciliation adds a result to the results and records the reason why the reconciliation is not
+// complete yet.
+func (r *Results) WithIncompleteReconciliation(res reconcile.Result, reason string) *Results {
+	r.incompleteReasons = append(r.incompleteReasons, reason)
+	return r.WithResult(res)
+}
+
+// IsReconciled returns true if no error occurred and no step requested a requeue to complete the reconciliation.
+// Otherwise it returns false and a message explaining why the reconciliation is not complete.
+func (r *Results) IsReconciled() (bool, string) {
+	switch {
+	case r.HasError():
+		return false, k8serrors.NewAggregate(r.errors).Error()
+	case len(r.incompleteReasons) > 0:
+		return false, strings.Join(r.incompleteReasons, "; ")
+	case r.requeued:
+		return false, "Reconciliation requeued"
+	default:
+		return true, ""
+	}
+}
+
 // mergeResult updates the current result if the other result has higher priority.
 // Order of priority is: noqueue < specific < generic
 // When there are two specific results, the one with the lowest RequeueAfter takes precedence.
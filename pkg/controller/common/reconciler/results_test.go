@@ -132,3 +132,60 @@ func TestResultsHasError(t *testing.T) {
 	r = r.WithError(errors.New("some error"))
 	require.True(t, r.HasError())
 }
+
+func TestResultsIsReconciled(t *testing.T) {
+	testCases := []struct {
+		name        string
+		results     func() *Results
+		want        bool
+		wantMessage string
+	}{
+		{
+			name:    "no result",
+			results: func() *Results { return &Results{} },
+			want:    true,
+		},
+		{
+			name: "periodic requeue",
+			results: func() *Results {
+				return (&Results{}).WithResult(reconcile.Result{RequeueAfter: 24 * time.Hour})
+			},
+			want: true,
+		},
+		{
+			name: "explicit requeue",
+			results: func() *Results {
+				return (&Results{}).WithResult(reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second})
+			},
+			want:        false,
+			wantMessage: "Reconciliation requeued",
+		},
+		{
+			name: "incomplete reconciliation in nested results",
+			results: func() *Results {
+				nested := (&Results{}).WithIncompleteReconciliation(reconcile.Result{}, "waiting for Pods")
+				return (&Results{}).WithResults(nested)
+			},
+			want:        false,
+			wantMessage: "waiting for Pods",
+		},
+		{
+			name: "error takes precedence",
+			results: func() *Results {
+				return (&Results{ctx: context.Background()}).
+					WithIncompleteReconciliation(reconcile.Result{}, "waiting for Pods").
+					WithError(errors.New("some error"))
+			},
+			want:        false,
+			wantMessage: "some error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reconciled, message := tc.results().IsReconciled()
+			require.Equal(t, tc.want, reconciled)
+			require.Equal(t, tc.wantMessage, message)
+		})
+	}
+}
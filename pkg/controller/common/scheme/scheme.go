@@ -19,6 +19,7 @@ import (
 	entv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1beta1"
 	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1beta1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -63,6 +64,10 @@ func SetupScheme() {
 		if err != nil {
 			panic(err)
 		}
+		err = emsv1alpha1.AddToScheme(clientgoscheme.Scheme)
+		if err != nil {
+			panic(err)
+		}
 	})
 }
 
@@ -104,5 +109,9 @@ func SetupV1beta1Scheme() {
 		if err != nil {
 			panic(err)
 		}
+		err = emsv1alpha1.AddToScheme(clientgoscheme.Scheme)
+		if err != nil {
+			panic(err)
+		}
 	})
 }
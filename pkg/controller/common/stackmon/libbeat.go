@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stackmon
+
+import (
+	"fmt"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// libbeatHTTPHost and libbeatHTTPPort define the local HTTP endpoint exposed by a libbeat based application
+	// (Beats or APM Server) for Metricbeat to collect its monitoring metrics.
+	libbeatHTTPHost = "localhost"
+	libbeatHTTPPort = 5066
+)
+
+// LibbeatMonitoringConfig returns the settings of a libbeat based application (Beats or APM Server) required to
+// expose its metrics to the Metricbeat sidecar, if metrics collection is defined.
+// The legacy internal collection is disabled in favour of Metricbeat.
+func LibbeatMonitoringConfig(resource monitoring.HasMonitoring) *settings.CanonicalConfig {
+	if !monitoring.IsMetricsDefined(resource) {
+		return settings.NewCanonicalConfig()
+	}
+	return settings.MustCanonicalConfig(map[string]interface{}{
+		"http.enabled":       true,
+		"http.host":          libbeatHTTPHost,
+		"http.port":          libbeatHTTPPort,
+		"monitoring.enabled": false,
+	})
+}
+
+// NewLibbeatMetricBeatSidecar returns a Metricbeat sidecar to collect the metrics of a libbeat based application
+// (Beats or APM Server) from its local HTTP endpoint.
+func NewLibbeatMetricBeatSidecar(
+	client k8s.Client,
+	resource monitoring.HasMonitoring,
+	namer name.Namer,
+	imageVersion string,
+) (BeatSidecar, error) {
+	config, err := settings.NewCanonicalConfigFrom(map[string]interface{}{
+		"metricbeat.modules": []interface{}{
+			map[string]interface{}{
+				"module":        "beat",
+				"metricsets":    []string{"stats", "state"},
+				"period":        "10s",
+				"xpack.enabled": true,
+				"hosts":         []string{fmt.Sprintf("http://%s:%d", libbeatHTTPHost, libbeatHTTPPort)},
+			},
+		},
+		"processors": []interface{}{
+			map[string]interface{}{"add_cloud_metadata": map[string]interface{}{}},
+			map[string]interface{}{"add_host_metadata": map[string]interface{}{}},
+		},
+	})
+	if err != nil {
+		return BeatSidecar{}, err
+	}
+	return NewMetricBeatSidecar(client, resource, namer, imageVersion, config)
+}
@@ -0,0 +1,43 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package monitoring
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+)
+
+// HasMonitoring is the interface implemented by an Elastic Stack application that supports Stack Monitoring
+type HasMonitoring interface {
+	metav1.Object
+	GetMonitoringMetricsRefs() []commonv1.ObjectSelector
+	GetMonitoringLogsRefs() []commonv1.ObjectSelector
+	MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association
+}
+
+// IsDefined returns true if the Stack Monitoring is defined for the given resource
+func IsDefined(resource HasMonitoring) bool {
+	return IsMetricsDefined(resource) || IsLogsDefined(resource)
+}
+
+// IsMetricsDefined returns true if the collection of metrics is defined for the given resource
+func IsMetricsDefined(resource HasMonitoring) bool {
+	return areDefined(resource.GetMonitoringMetricsRefs())
+}
+
+// IsLogsDefined returns true if the collection of logs is defined for the given resource
+func IsLogsDefined(resource HasMonitoring) bool {
+	return areDefined(resource.GetMonitoringLogsRefs())
+}
+
+func areDefined(refs []commonv1.ObjectSelector) bool {
+	for _, ref := range refs {
+		if !ref.IsDefined() {
+			return false
+		}
+	}
+	return len(refs) > 0
+}
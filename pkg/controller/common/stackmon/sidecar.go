@@ -0,0 +1,217 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stackmon
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"path"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+	"github.com/pkg/errors"
+)
+
+const (
+	metricbeatContainerName = "metricbeat"
+	filebeatContainerName   = "filebeat"
+
+	configVolumeMountPathTemplate = "/etc/%s-config"
+	caVolumeMountPathTemplate     = "/mnt/elastic-internal/es-monitoring/%s/%s/certs"
+
+	// configHashAnnotationNameTemplate is the template of the annotation holding the hash of a Beat sidecar
+	// configuration, used to rotate the Pods when the configuration changes.
+	configHashAnnotationNameTemplate = "monitoring.k8s.elastic.co/%s-config-hash"
+)
+
+// BeatSidecar represents a Beat container to run as a sidecar of an Elastic Stack application to ship its
+// monitoring data (metrics or logs) to a monitoring Elasticsearch cluster.
+type BeatSidecar struct {
+	Container    corev1.Container
+	ConfigSecret corev1.Secret
+	ConfigHash   hash.Hash32
+	Volumes      []corev1.Volume
+	// beatName is the name of the Beat, independently of the name given to the container
+	beatName string
+}
+
+// NewMetricBeatSidecar returns a Metricbeat sidecar configured with the given base configuration to ship the metrics
+// of the given resource to the monitoring Elasticsearch cluster referenced in its spec.
+func NewMetricBeatSidecar(
+	client k8s.Client,
+	resource monitoring.HasMonitoring,
+	namer name.Namer,
+	imageVersion string,
+	baseConfig *settings.CanonicalConfig,
+	additionalVolumes ...volume.VolumeLike,
+) (BeatSidecar, error) {
+	return newBeatSidecar(client, metricbeatContainerName, container.MetricbeatImage, resource, namer, imageVersion,
+		resource.GetMonitoringMetricsRefs(), baseConfig, additionalVolumes)
+}
+
+// NewFileBeatSidecar returns a Filebeat sidecar configured with the given base configuration to ship the logs
+// of the given resource to the monitoring Elasticsearch cluster referenced in its spec.
+func NewFileBeatSidecar(
+	client k8s.Client,
+	resource monitoring.HasMonitoring,
+	namer name.Namer,
+	imageVersion string,
+	baseConfig *settings.CanonicalConfig,
+	additionalVolumes ...volume.VolumeLike,
+) (BeatSidecar, error) {
+	return newBeatSidecar(client, filebeatContainerName, container.FilebeatImage, resource, namer, imageVersion,
+		resource.GetMonitoringLogsRefs(), baseConfig, additionalVolumes)
+}
+
+func newBeatSidecar(
+	client k8s.Client,
+	beatName string,
+	image container.Image,
+	resource monitoring.HasMonitoring,
+	namer name.Namer,
+	imageVersion string,
+	refs []commonv1.ObjectSelector,
+	baseConfig *settings.CanonicalConfig,
+	additionalVolumes []volume.VolumeLike,
+) (BeatSidecar, error) {
+	if len(refs) == 0 {
+		return BeatSidecar{}, errors.Errorf("no Elasticsearch reference defined for %s", beatName)
+	}
+	// only the first reference is considered, more than one is rejected by the validation
+	assoc := resource.MonitoringAssociation(refs[0])
+
+	outputConfig, caVolume, err := buildOutputConfig(client, assoc, beatName)
+	if err != nil {
+		return BeatSidecar{}, err
+	}
+	config := settings.NewCanonicalConfig()
+	if err := config.MergeWith(baseConfig, outputConfig); err != nil {
+		return BeatSidecar{}, err
+	}
+	configBytes, err := config.Render()
+	if err != nil {
+		return BeatSidecar{}, err
+	}
+
+	configFileName := fmt.Sprintf("%s.yml", beatName)
+	configSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namer.Suffix(resource.GetName(), "monitoring", beatName, "config"),
+			Namespace: resource.GetNamespace(),
+		},
+		Data: map[string][]byte{
+			configFileName: configBytes,
+		},
+	}
+	configHash := fnv.New32a()
+	_, _ = configHash.Write(configBytes)
+
+	configVolume := volume.NewSecretVolume(
+		configSecret.Name,
+		fmt.Sprintf("%s-config", beatName),
+		fmt.Sprintf(configVolumeMountPathTemplate, beatName),
+		configFileName,
+		0444,
+	)
+	vols := append([]volume.VolumeLike{configVolume}, additionalVolumes...)
+	if caVolume != nil {
+		vols = append(vols, caVolume)
+	}
+
+	volumes := make([]corev1.Volume, 0, len(vols))
+	volumeMounts := make([]corev1.VolumeMount, 0, len(vols))
+	for _, v := range vols {
+		volumes = append(volumes, v.Volume())
+		volumeMounts = append(volumeMounts, v.VolumeMount())
+	}
+
+	return BeatSidecar{
+		Container: corev1.Container{
+			Name:         beatName,
+			Image:        container.ImageRepository(image, imageVersion),
+			Args:         []string{"-c", filepath.Join(configVolume.VolumeMount().MountPath, configFileName), "-e"},
+			VolumeMounts: volumeMounts,
+		},
+		ConfigSecret: configSecret,
+		ConfigHash:   configHash,
+		Volumes:      volumes,
+		beatName:     beatName,
+	}, nil
+}
+
+// buildOutputConfig builds the Elasticsearch output section of the Beat configuration, along with the volume
+// containing the CA certificate of the monitoring cluster if TLS is enabled.
+func buildOutputConfig(client k8s.Client, assoc commonv1.Association, beatName string) (*settings.CanonicalConfig, volume.VolumeLike, error) {
+	credentials, err := association.ElasticsearchCredentials(client, assoc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outputConfig := credentials.OutputSettings()
+	outputConfig["hosts"] = []string{assoc.AssociationConf().GetURL()}
+
+	var caVolume volume.VolumeLike
+	if assoc.AssociationConf().CAIsConfigured() {
+		ref := assoc.AssociationRef()
+		caVolume = volume.NewSecretVolumeWithMountPath(
+			assoc.AssociationConf().GetCASecretName(),
+			fmt.Sprintf("%s-es-monitoring-ca", beatName),
+			fmt.Sprintf(caVolumeMountPathTemplate, ref.Namespace, ref.NameOrSecretName()),
+		)
+		outputConfig["ssl.certificate_authorities"] = []string{path.Join(caVolume.VolumeMount().MountPath, certificates.CAFileName)}
+	}
+
+	cfg, err := settings.NewCanonicalConfigFrom(map[string]interface{}{
+		"output.elasticsearch": outputConfig,
+	})
+	return cfg, caVolume, err
+}
+
+// ReconcileConfigSecrets reconciles the secrets holding the configuration of the given Beat sidecars.
+func ReconcileConfigSecrets(c k8s.Client, owner client.Object, labels map[string]string, sidecars ...BeatSidecar) error {
+	for _, sidecar := range sidecars {
+		secret := sidecar.ConfigSecret
+		secret.Labels = common.AddCredentialsLabel(maps.Merge(map[string]string{}, labels))
+		if _, err := reconciler.ReconcileSecret(c, secret, owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithMonitoring updates the given Pod template builder to deploy the given Beat sidecars along with their volumes.
+// The hash of the configuration of each sidecar is added to the Pod annotations to rotate the Pods on changes.
+func WithMonitoring(builder *defaults.PodTemplateBuilder, sidecars ...BeatSidecar) *defaults.PodTemplateBuilder {
+	annotations := map[string]string{}
+	var volumes []corev1.Volume
+	var containers []corev1.Container
+	for _, sidecar := range sidecars {
+		annotations[fmt.Sprintf(configHashAnnotationNameTemplate, sidecar.beatName)] = fmt.Sprint(sidecar.ConfigHash.Sum32())
+		volumes = append(volumes, sidecar.Volumes...)
+		containers = append(containers, sidecar.Container)
+	}
+
+	return builder.
+		WithAnnotations(annotations).
+		WithVolumes(volumes...).
+		WithContainers(containers...)
+}
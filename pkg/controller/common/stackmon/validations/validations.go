@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validations
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+)
+
+const (
+	unsupportedVersionMsg       = "Unsupported version for Stack Monitoring. Required >= %s."
+	invalidElasticsearchRefsMsg = "Only one Elasticsearch reference is supported for %s Stack Monitoring"
+	unsupportedLogsMsg          = "Stack Monitoring of logs is not supported for %s"
+)
+
+var (
+	// MinStackVersion is the minimum Stack version to enable Stack Monitoring on an Elastic Stack application.
+	// This requirement comes from the fact that we configure Metricbeat to collect monitoring data with the
+	// `xpack.enabled` flag of its modules, which is fully supported starting 7.14.
+	MinStackVersion = version.MustParse("7.14.0-SNAPSHOT")
+)
+
+// Validate validates that the resource version is supported for Stack Monitoring and that there is at most one
+// Elasticsearch reference for metrics and at most one for logs, which must be valid association references.
+func Validate(resource monitoring.HasMonitoring, resourceVersion string) field.ErrorList {
+	var errs field.ErrorList
+	if monitoring.IsDefined(resource) {
+		ver, err := version.Parse(resourceVersion)
+		if err != nil || ver.LT(MinStackVersion) {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("version"), resourceVersion,
+				fmt.Sprintf(unsupportedVersionMsg, MinStackVersion)))
+		}
+	}
+	if refs := resource.GetMonitoringMetricsRefs(); len(refs) > 1 {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("monitoring", "metrics", "elasticsearchRefs"),
+			refs, fmt.Sprintf(invalidElasticsearchRefsMsg, "metrics")))
+	}
+	if refs := resource.GetMonitoringLogsRefs(); len(refs) > 1 {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("monitoring", "logs", "elasticsearchRefs"),
+			refs, fmt.Sprintf(invalidElasticsearchRefsMsg, "logs")))
+	}
+	for i, ref := range resource.GetMonitoringMetricsRefs() {
+		errs = append(errs, commonv1.CheckAssociationRef(
+			field.NewPath("spec").Child("monitoring", "metrics", "elasticsearchRefs").Index(i), ref)...)
+	}
+	for i, ref := range resource.GetMonitoringLogsRefs() {
+		errs = append(errs, commonv1.CheckAssociationRef(
+			field.NewPath("spec").Child("monitoring", "logs", "elasticsearchRefs").Index(i), ref)...)
+	}
+	return errs
+}
+
+// ValidateMetricsOnly validates that the collection of logs is not defined for a resource of the given kind, for
+// which only the collection of metrics is supported.
+func ValidateMetricsOnly(resource monitoring.HasMonitoring, kind string) field.ErrorList {
+	if refs := resource.GetMonitoringLogsRefs(); len(refs) > 0 {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("monitoring", "logs", "elasticsearchRefs"),
+			refs, fmt.Sprintf(unsupportedLogsMsg, kind))}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package validations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		es      esv1.Elasticsearch
+		isValid bool
+	}{
+		{
+			name: "without monitoring",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.13.0",
+				},
+			},
+			isValid: true,
+		},
+		{
+			name: "with monitoring",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.14.0",
+					Monitoring: commonv1.Monitoring{
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+						Logs: commonv1.LogsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+					},
+				},
+			},
+			isValid: true,
+		},
+		{
+			name: "with unsupported version",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.13.0",
+					Monitoring: commonv1.Monitoring{
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+					},
+				},
+			},
+			isValid: false,
+		},
+		{
+			name: "with more than one metrics elasticsearch ref",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.14.0",
+					Monitoring: commonv1.Monitoring{
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}, {Name: "m2", Namespace: "c"}},
+						},
+					},
+				},
+			},
+			isValid: false,
+		},
+		{
+			name: "with more than one logs elasticsearch ref",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.14.0",
+					Monitoring: commonv1.Monitoring{
+						Logs: commonv1.LogsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}, {Name: "m2", Namespace: "c"}},
+						},
+					},
+				},
+			},
+			isValid: false,
+		},
+		{
+			name: "with a valid service name",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.14.0",
+					Monitoring: commonv1.Monitoring{
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b", ServiceName: "coordinating"}},
+						},
+					},
+				},
+			},
+			isValid: true,
+		},
+		{
+			name: "with an invalid service name",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "7.14.0",
+					Monitoring: commonv1.Monitoring{
+						Logs: commonv1.LogsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b", ServiceName: "Coordinating_Nodes"}},
+						},
+					},
+				},
+			},
+			isValid: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(&tc.es, tc.es.Spec.Version)
+			assert.Equal(t, tc.isValid, len(err) == 0)
+		})
+	}
+}
+
+func TestValidateMetricsOnly(t *testing.T) {
+	es := esv1.Elasticsearch{
+		Spec: esv1.ElasticsearchSpec{
+			Version: "7.14.0",
+			Monitoring: commonv1.Monitoring{
+				Metrics: commonv1.MetricsMonitoring{
+					ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+				},
+			},
+		},
+	}
+	assert.Empty(t, ValidateMetricsOnly(&es, "Elasticsearch"))
+
+	es.Spec.Monitoring.Logs.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}}
+	assert.Len(t, ValidateMetricsOnly(&es, "Elasticsearch"), 1)
+}
@@ -30,6 +30,11 @@ var (
 	// Elastic Agent was introduced in 7.8.0, but as "experimental release" with no migration path forward, hence
 	// picking higher version as minimal supported.
 	SupportedAgentVersions = MinMaxVersion{Min: From(7, 10, 0), Max: From(8, 99, 99)}
+	// Elastic Agent in Fleet mode relies on Fleet Server and the Fleet setup API of Kibana, which are only usable
+	// on Kubernetes starting 7.14.0.
+	SupportedFleetModeAgentVersions = MinMaxVersion{Min: From(7, 14, 0), Max: From(8, 99, 99)}
+	// Elastic Maps Server was first released as a standalone Docker image in 7.11.0.
+	SupportedMapsVersions = MinMaxVersion{Min: From(7, 11, 0), Max: From(8, 99, 99)}
 )
 
 // MinMaxVersion holds the minimum and maximum supported versions.
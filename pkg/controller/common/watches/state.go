@@ -8,9 +8,11 @@ package watches
 func NewDynamicWatches() DynamicWatches {
 	return DynamicWatches{
 		Secrets:               NewDynamicEnqueueRequest(),
+		ConfigMaps:            NewDynamicEnqueueRequest(),
 		Pods:                  NewDynamicEnqueueRequest(),
 		ElasticsearchClusters: NewDynamicEnqueueRequest(),
 		Kibanas:               NewDynamicEnqueueRequest(),
+		ReferencedResources:   NewDynamicEnqueueRequest(),
 	}
 }
 
@@ -18,7 +20,10 @@ func NewDynamicWatches() DynamicWatches {
 // give each of them an identity.
 type DynamicWatches struct {
 	Secrets               *DynamicEnqueueRequest
+	ConfigMaps            *DynamicEnqueueRequest
 	Pods                  *DynamicEnqueueRequest
 	ElasticsearchClusters *DynamicEnqueueRequest
 	Kibanas               *DynamicEnqueueRequest
+	// ReferencedResources watches resources referenced in associations that do not target Elasticsearch.
+	ReferencedResources *DynamicEnqueueRequest
 }
@@ -29,8 +29,13 @@ type CertificateResources struct {
 	// TrustedHTTPCertificates contains the latest HTTP certificates that should be trusted.
 	TrustedHTTPCertificates []*x509.Certificate
 
-	// TransportCA is the CA used for Transport certificates
+	// TransportCA is the CA used for Transport certificates, nil if they are signed by an external issuer which has
+	// not issued any certificate yet
 	TransportCA *certificates.CA
+
+	// InvalidTransportCertificates holds the Pods whose user-provided transport certificates are missing, invalid or
+	// about to expire.
+	InvalidTransportCertificates transport.InvalidCertificates
 }
 
 // Reconcile reconciles the certificates of a cluster.
@@ -41,6 +46,7 @@ func Reconcile(
 	services []corev1.Service,
 	caRotation certificates.RotationParams,
 	certRotation certificates.RotationParams,
+	privateKeyOptions commonv1.PrivateKeyOptions,
 ) (*CertificateResources, *reconciler.Results) {
 	span, _ := apm.StartSpan(ctx, "reconcile_certs", tracing.SpanTypeApp)
 	defer span.End()
@@ -60,16 +66,17 @@ func Reconcile(
 	// reconcile HTTP CA and cert
 	var httpCerts *certificates.CertificatesSecret
 	httpCerts, results = certificates.Reconciler{
-		K8sClient:      driver.K8sClient(),
-		DynamicWatches: driver.DynamicWatches(),
-		Owner:          &es,
-		TLSOptions:     es.Spec.HTTP.TLS,
-		ExtraHTTPSANs:  extraHTTPSANs,
-		Namer:          esv1.ESNamer,
-		Labels:         certsLabels,
-		Services:       services,
-		CACertRotation: caRotation,
-		CertRotation:   certRotation,
+		K8sClient:         driver.K8sClient(),
+		DynamicWatches:    driver.DynamicWatches(),
+		Owner:             &es,
+		TLSOptions:        es.Spec.HTTP.TLS,
+		ExtraHTTPSANs:     extraHTTPSANs,
+		Namer:             esv1.ESNamer,
+		Labels:            certsLabels,
+		Services:          services,
+		CACertRotation:    caRotation,
+		CertRotation:      certRotation,
+		PrivateKeyOptions: privateKeyOptions,
 		// ES is able to hot-reload TLS certificates: let's keep secrets around even though TLS is disabled.
 		// In case TLS is toggled on/off/on quickly enough, removing the secret would prevent future certs to be available.
 		GarbageCollectSecrets: false,
@@ -78,41 +85,68 @@ func Reconcile(
 		return nil, results
 	}
 
-	// reconcile transport CA and certs
-	transportCA, err := transport.ReconcileOrRetrieveCA(
-		driver,
-		es,
-		certsLabels,
-		caRotation,
-	)
-	if err != nil {
-		return nil, results.WithError(err)
-	}
-	// make sure to requeue before the CA cert expires
-	results.WithResult(reconcile.Result{
-		RequeueAfter: certificates.ShouldRotateIn(time.Now(), transportCA.Cert.NotAfter, caRotation.RotateBefore),
-	})
-
-	// reconcile transport public certs secret
-	if err := transport.ReconcileTransportCertsPublicSecret(driver.K8sClient(), es, transportCA); err != nil {
-		return nil, results.WithError(err)
+	// reconcile transport CA and certs, unless the transport certificates are signed by an external issuer or
+	// provided by the user
+	var transportCA *certificates.CA
+	var nodeCertificates *corev1.Secret
+	switch {
+	case es.Spec.Transport.TLS.UserDefinedNodeCertificates():
+		secret, err := transport.RetrieveNodeCertificatesSecret(driver, es)
+		if err != nil {
+			return nil, results.WithError(err)
+		}
+		nodeCertificates = secret
+	case !es.Spec.Transport.TLS.UsesIssuer():
+		ca, err := transport.ReconcileOrRetrieveCA(
+			driver,
+			es,
+			certsLabels,
+			caRotation,
+			privateKeyOptions,
+		)
+		if err != nil {
+			return nil, results.WithError(err)
+		}
+		// make sure to requeue before the CA cert expires
+		results.WithResult(reconcile.Result{
+			RequeueAfter: certificates.ShouldRotateIn(time.Now(), ca.Cert.NotAfter, caRotation.RotateBefore),
+		})
+		transportCA = ca
 	}
 
 	// reconcile transport certificates
-	transportResults := transport.ReconcileTransportCertificatesSecrets(
+	invalidTransportCertificates, transportResults := transport.ReconcileTransportCertificatesSecrets(
 		driver.K8sClient(),
 		transportCA,
+		nodeCertificates,
 		es,
 		certRotation,
+		privateKeyOptions,
 	)
+	if results.WithResults(transportResults).HasError() {
+		return nil, results
+	}
 
-	// reconcile remote clusters certificate authorities
-	if err := remoteca.Reconcile(driver.K8sClient(), es, *transportCA); err != nil {
-		results.WithError(err)
+	if transportCA == nil {
+		// the CA of an external issuer is only known once transport certificates have been issued, the CA of
+		// user-provided certificates is copied along with them
+		ca, err := transport.RetrieveExternalCA(driver.K8sClient(), es)
+		if err != nil {
+			return nil, results.WithError(err)
+		}
+		transportCA = ca
 	}
 
-	if results.WithResults(transportResults).HasError() {
-		return nil, results
+	if transportCA != nil {
+		// reconcile transport public certs secret
+		if err := transport.ReconcileTransportCertsPublicSecret(driver.K8sClient(), es, transportCA); err != nil {
+			return nil, results.WithError(err)
+		}
+
+		// reconcile remote clusters certificate authorities
+		if err := remoteca.Reconcile(driver.K8sClient(), es, *transportCA); err != nil {
+			return nil, results.WithError(err)
+		}
 	}
 
 	trustedHTTPCertificates, err := certificates.ParsePEMCerts(httpCerts.CertPem())
@@ -121,7 +155,8 @@ func Reconcile(
 	}
 
 	return &CertificateResources{
-		TrustedHTTPCertificates: trustedHTTPCertificates,
-		TransportCA:             transportCA,
+		TrustedHTTPCertificates:      trustedHTTPCertificates,
+		TransportCA:                  transportCA,
+		InvalidTransportCertificates: invalidTransportCertificates,
 	}, results
 }
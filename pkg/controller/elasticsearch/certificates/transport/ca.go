@@ -6,16 +6,20 @@ package transport
 
 import (
 	"context"
+	"sort"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func CustomTransportCertsWatchKey(es types.NamespacedName) string {
@@ -29,6 +33,7 @@ func ReconcileOrRetrieveCA(
 	es esv1.Elasticsearch,
 	labels map[string]string,
 	rotationParams certificates.RotationParams,
+	privateKeyOptions commonv1.PrivateKeyOptions,
 ) (*certificates.CA, error) {
 	esNSN := k8s.ExtractNamespacedName(&es)
 
@@ -58,6 +63,7 @@ func ReconcileOrRetrieveCA(
 			labels,
 			certificates.TransportCAType,
 			rotationParams,
+			privateKeyOptions,
 		)
 	}
 
@@ -88,3 +94,35 @@ func ReconcileOrRetrieveCA(
 
 	return ca, nil
 }
+
+// RetrieveExternalCA returns the CA of the transport certificates signed by an external issuer or provided by the user,
+// as stored along with the certificates. It returns nil if no transport certificate has been issued yet.
+func RetrieveExternalCA(c k8s.Client, es esv1.Elasticsearch) (*certificates.CA, error) {
+	var secrets corev1.SecretList
+	if err := c.List(context.Background(),
+		&secrets,
+		client.InNamespace(es.Namespace),
+		client.MatchingLabels{label.ClusterNameLabelName: es.Name},
+	); err != nil {
+		return nil, err
+	}
+	// sort the secrets to consistently use the same CA while the CA of the issuer is being rotated
+	sort.Slice(secrets.Items, func(i, j int) bool {
+		return secrets.Items[i].Name < secrets.Items[j].Name
+	})
+	for _, secret := range secrets.Items {
+		caPem := secret.Data[certificates.CAFileName]
+		if _, isTransportCertificatesSecret := secret.Labels[label.StatefulSetNameLabelName]; !isTransportCertificatesSecret || len(caPem) == 0 {
+			continue
+		}
+		caCerts, err := certificates.ParsePEMCerts(caPem)
+		if err != nil {
+			return nil, err
+		}
+		if len(caCerts) == 0 {
+			continue
+		}
+		return certificates.NewCA(nil, caCerts[0]), nil
+	}
+	return nil, nil
+}
@@ -63,6 +63,30 @@ func createValidatedCertificateTemplate(
 	return &certificateTemplate, nil
 }
 
+// createCertificateRequestTemplate creates the template of a request for a transport certificate to be signed by an
+// external issuer, with the same subject and SANs as the certificates signed by the operator.
+func createCertificateRequestTemplate(pod corev1.Pod, cluster esv1.Elasticsearch) (*x509.CertificateRequest, error) {
+	generalNames, err := buildGeneralNames(cluster, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	generalNamesBytes, err := certificates.MarshalToSubjectAlternativeNamesData(generalNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:         buildCertificateCommonName(pod, cluster.Name, cluster.Namespace),
+			OrganizationalUnit: []string{cluster.Name},
+		},
+		ExtraExtensions: []pkix.Extension{
+			{Id: certificates.SubjectAlternativeNamesObjectIdentifier, Value: generalNamesBytes},
+		},
+	}, nil
+}
+
 func buildGeneralNames(
 	cluster esv1.Elasticsearch,
 	pod corev1.Pod,
@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/set"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// withIssuerCA returns the given PEM-encoded CA certificates with the ones of the issuer appended, if not already
+// part of them. The CA certificates of an external issuer are rotated independently of the certificates of the pods,
+// which must trust both the previous and the new CA until all of them are issued by the new one.
+func withIssuerCA(caBundle []byte, issuerCA []byte) []byte {
+	current, err := certificates.ParsePEMCerts(caBundle)
+	if err != nil || len(current) == 0 {
+		return issuerCA
+	}
+	issuerCerts, err := certificates.ParsePEMCerts(issuerCA)
+	if err != nil {
+		return caBundle
+	}
+	blocks := make([][]byte, 0, len(current)+len(issuerCerts))
+	for _, cert := range current {
+		blocks = append(blocks, cert.Raw)
+	}
+	for _, cert := range issuerCerts {
+		if !containsCert(current, cert) {
+			blocks = append(blocks, cert.Raw)
+		}
+	}
+	if len(blocks) == len(current) {
+		return caBundle
+	}
+	return certificates.EncodePEMCert(blocks...)
+}
+
+func containsCert(certs []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certs {
+		if bytes.Equal(c.Raw, cert.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneIssuerCAs removes from the transport certificates secrets of the given StatefulSets the CA certificates to
+// which none of the certificates of the pods of the cluster chain anymore. Pods verify the certificates of the pods
+// of all the StatefulSets with their own CA certificates, which must then be pruned as a whole.
+func pruneIssuerCAs(c k8s.Client, es esv1.Elasticsearch, ssetNames set.StringSet) error {
+	secrets := make([]corev1.Secret, 0, len(ssetNames))
+	for ssetName := range ssetNames {
+		var secret corev1.Secret
+		err := c.Get(context.Background(), types.NamespacedName{
+			Namespace: es.Namespace,
+			Name:      esv1.StatefulSetTransportCertificatesSecret(ssetName),
+		}, &secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	var chains [][]*x509.Certificate
+	for _, secret := range secrets {
+		for key, data := range secret.Data {
+			if !strings.HasSuffix(key, "."+certificates.CertFileName) {
+				continue
+			}
+			if chain, err := certificates.ParsePEMCerts(data); err == nil && len(chain) > 0 {
+				chains = append(chains, chain)
+			}
+		}
+	}
+
+	for i := range secrets {
+		secret := &secrets[i]
+		cas, err := certificates.ParsePEMCerts(secret.Data[certificates.CAFileName])
+		if err != nil || len(cas) < 2 {
+			continue
+		}
+		var inUse [][]byte
+		for _, ca := range cas {
+			if anyChainsTo(chains, ca) {
+				inUse = append(inUse, ca.Raw)
+			}
+		}
+		if len(inUse) == 0 || len(inUse) == len(cas) {
+			continue
+		}
+		log.Info("Removing CA certificates not used anymore from transport certificates secret",
+			"namespace", secret.Namespace, "secret_name", secret.Name, "removed", len(cas)-len(inUse))
+		secret.Data[certificates.CAFileName] = certificates.EncodePEMCert(inUse...)
+		if err := c.Update(context.Background(), secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// anyChainsTo returns true if at least one of the given certificate chains is signed by the given CA.
+func anyChainsTo(chains [][]*x509.Certificate, ca *x509.Certificate) bool {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	for _, chain := range chains {
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := chain[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}); err == nil {
+			return true
+		}
+	}
+	return false
+}
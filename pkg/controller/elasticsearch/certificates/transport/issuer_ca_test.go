@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/set"
+)
+
+func newOtherCA(t *testing.T) *certificates.CA {
+	t.Helper()
+	privateKey, err := certificates.GeneratePrivateKey(certificates.DefaultPrivateKeyOptions)
+	require.NoError(t, err)
+	ca, err := certificates.NewSelfSignedCA(certificates.CABuilderOptions{
+		Subject:    pkix.Name{CommonName: "other-common-name"},
+		PrivateKey: privateKey,
+	})
+	require.NoError(t, err)
+	return ca
+}
+
+func Test_withIssuerCA(t *testing.T) {
+	otherCA := newOtherCA(t)
+	otherCABytes := certificates.EncodePEMCert(otherCA.Cert.Raw)
+	bothCABytes := certificates.EncodePEMCert(testCA.Cert.Raw, otherCA.Cert.Raw)
+
+	require.Equal(t, testCABytes, withIssuerCA(nil, testCABytes))
+	require.Equal(t, testCABytes, withIssuerCA(testCABytes, testCABytes))
+	require.Equal(t, bothCABytes, withIssuerCA(testCABytes, otherCABytes))
+	require.Equal(t, bothCABytes, withIssuerCA(bothCABytes, otherCABytes))
+}
+
+func Test_pruneIssuerCAs(t *testing.T) {
+	otherCA := newOtherCA(t)
+	bothCABytes := certificates.EncodePEMCert(testCA.Cert.Raw, otherCA.Cert.Raw)
+	otherCert, err := otherCA.CreateCertificate(*validatedCertificateTemplate)
+	require.NoError(t, err)
+	otherPemCert := certificates.EncodePEMCert(otherCert)
+
+	es := newEsBuilder().addNodeSet("sset1", 1).addNodeSet("sset2", 1).build()
+	secret := func(ssetName string, podCert []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: esv1.StatefulSetTransportCertificatesSecret(ssetName)},
+			Data: map[string][]byte{
+				certificates.CAFileName:                bothCABytes,
+				PodCertFileName(ssetName + "-0"):       podCert,
+				PodKeyFileName(ssetName + "-0"):        testRSAPrivateKeyPEM,
+				PodPendingKeyFileName(ssetName + "-1"): testRSAPrivateKeyPEM,
+			},
+		}
+	}
+	ssets := set.Make("test-es-name-es-sset1", "test-es-name-es-sset2")
+	caBundle := func(c k8s.Client, ssetName string) []byte {
+		var s corev1.Secret
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{
+			Namespace: testNamespace,
+			Name:      esv1.StatefulSetTransportCertificatesSecret(ssetName),
+		}, &s))
+		return s.Data[certificates.CAFileName]
+	}
+
+	// a pod of the second StatefulSet still uses a certificate issued by the previous CA, trusted by all the pods
+	c := k8s.NewFakeClient(secret("test-es-name-es-sset1", otherPemCert), secret("test-es-name-es-sset2", pemCert))
+	require.NoError(t, pruneIssuerCAs(c, *es, ssets))
+	require.Equal(t, bothCABytes, caBundle(c, "test-es-name-es-sset1"))
+	require.Equal(t, bothCABytes, caBundle(c, "test-es-name-es-sset2"))
+
+	// all the certificates are issued by the new CA, the previous one is not trusted anymore
+	c = k8s.NewFakeClient(secret("test-es-name-es-sset1", otherPemCert), secret("test-es-name-es-sset2", otherPemCert))
+	require.NoError(t, pruneIssuerCAs(c, *es, ssets))
+	require.Equal(t, certificates.EncodePEMCert(otherCA.Cert.Raw), caBundle(c, "test-es-name-es-sset1"))
+	require.Equal(t, certificates.EncodePEMCert(otherCA.Cert.Raw), caBundle(c, "test-es-name-es-sset2"))
+}
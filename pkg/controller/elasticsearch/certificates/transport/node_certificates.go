@@ -0,0 +1,161 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodespec"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InvalidCertificates holds the reasons why the user-provided transport certificates of some Pods cannot be used, or
+// must be replaced soon, indexed by Pod name.
+type InvalidCertificates map[string]string
+
+// Merge adds the invalid certificates of other to the receiver.
+func (ic InvalidCertificates) Merge(other InvalidCertificates) InvalidCertificates {
+	for podName, reason := range other {
+		ic[podName] = reason
+	}
+	return ic
+}
+
+// String returns a sorted summary of the invalid certificates.
+func (ic InvalidCertificates) String() string {
+	podNames := make([]string, 0, len(ic))
+	for podName := range ic {
+		podNames = append(podNames, podName)
+	}
+	sort.Strings(podNames)
+	reasons := make([]string, 0, len(podNames))
+	for _, podName := range podNames {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", podName, ic[podName]))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// RetrieveNodeCertificatesSecret returns the secret holding the user-provided transport certificates of the nodes, and
+// watches it to reconcile the cluster when it changes.
+func RetrieveNodeCertificatesSecret(driver driver.Interface, es esv1.Elasticsearch) (*corev1.Secret, error) {
+	esNSN := k8s.ExtractNamespacedName(&es)
+
+	// node certificates and a custom CA are mutually exclusive, they can share the same watch
+	if err := certificates.ReconcileCustomCertWatch(
+		driver.DynamicWatches(),
+		CustomTransportCertsWatchKey(esNSN),
+		esNSN,
+		es.Spec.Transport.TLS.NodeCertificates,
+	); err != nil {
+		return nil, err
+	}
+
+	secret, err := certificates.GetSecretFromRef(driver.K8sClient(), esNSN, es.Spec.Transport.TLS.NodeCertificates)
+	if err != nil {
+		// error should already contain enough context including the name of the secret
+		driver.Recorder().Eventf(&es, corev1.EventTypeWarning, events.EventReasonUnexpected, err.Error())
+		return nil, err
+	}
+	if caCerts, err := certificates.ParsePEMCerts(secret.Data[certificates.CAFileName]); err != nil || len(caCerts) == 0 {
+		err := fmt.Errorf("secret %s/%s must contain the PEM-encoded CA certificate of the transport certificates in %s",
+			secret.Namespace, secret.Name, certificates.CAFileName)
+		driver.Recorder().Eventf(&es, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		return nil, err
+	}
+	return secret, nil
+}
+
+// ensureUserTransportCertificatesSecretContentsForPod copies the certificate provided by the user for a specific pod,
+// or the wildcard certificate if there is none, into the transport certificates secret, and returns it parsed.
+// It returns the reason why the certificate cannot be used if it is missing or invalid, in which case the current
+// certificate of the pod is left unchanged and no certificate is returned, or if it is about to expire.
+func ensureUserTransportCertificatesSecretContentsForPod(
+	es esv1.Elasticsearch,
+	secret *corev1.Secret,
+	pod corev1.Pod,
+	nodeCertificates corev1.Secret,
+	rotateBefore time.Duration,
+) (*x509.Certificate, string) {
+	certData, keyData := userTransportCertificateForPod(nodeCertificates, pod)
+	if certData == nil || keyData == nil {
+		return nil, fmt.Sprintf("no certificate in secret %s/%s", nodeCertificates.Namespace, nodeCertificates.Name)
+	}
+
+	cert, err := validateUserTransportCertificate(es, pod, certData, keyData)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	secret.Data[PodCertFileName(pod.Name)] = certData
+	secret.Data[PodKeyFileName(pod.Name)] = keyData
+
+	if time.Now().After(cert.NotAfter.Add(-rotateBefore)) {
+		return cert, fmt.Sprintf("certificate expires at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+	return cert, ""
+}
+
+// userTransportCertificateForPod returns the certificate and private key provided by the user for a specific pod, or
+// the wildcard ones if there is no dedicated certificate for the pod.
+func userTransportCertificateForPod(nodeCertificates corev1.Secret, pod corev1.Pod) ([]byte, []byte) {
+	if certData, exists := nodeCertificates.Data[PodCertFileName(pod.Name)]; exists {
+		return certData, nodeCertificates.Data[PodKeyFileName(pod.Name)]
+	}
+	return nodeCertificates.Data[certificates.CertFileName], nodeCertificates.Data[certificates.KeyFileName]
+}
+
+// validateUserTransportCertificate checks that the first certificate of the chain belongs to the private key, and
+// that it is valid for one of the addresses of the pod. It returns the parsed certificate.
+func validateUserTransportCertificate(es esv1.Elasticsearch, pod corev1.Pod, certData, keyData []byte) (*x509.Certificate, error) {
+	certs, err := certificates.ParsePEMCerts(certData)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse certificate")
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	privateKey, err := certificates.ParsePEMPrivateKey(keyData)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse private key")
+	}
+	cert := certs[0]
+	if !certificates.PrivateMatchesPublicKey(cert.PublicKey, privateKey) {
+		return nil, errors.New("certificate does not match the private key")
+	}
+
+	addresses := podTransportAddresses(es, pod)
+	for _, address := range addresses {
+		if cert.VerifyHostname(address) == nil {
+			return cert, nil
+		}
+	}
+	return nil, fmt.Errorf("certificate is not valid for any of %s", strings.Join(addresses, ", "))
+}
+
+// podTransportAddresses returns the addresses other nodes can use to reach a pod, one of which must be a SAN of its
+// transport certificate.
+func podTransportAddresses(es esv1.Elasticsearch, pod corev1.Pod) []string {
+	svcName := nodespec.HeadlessServiceName(pod.Labels[label.StatefulSetNameLabelName])
+	addresses := []string{
+		fmt.Sprintf("%s.%s", pod.Name, svcName),
+		fmt.Sprintf("%s.%s.%s.svc", pod.Name, svcName, es.Namespace),
+	}
+	if ip := net.ParseIP(pod.Status.PodIP); ip != nil {
+		addresses = append(addresses, ip.String())
+	}
+	return addresses
+}
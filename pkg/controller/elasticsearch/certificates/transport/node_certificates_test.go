@@ -0,0 +1,191 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transport
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newUserCertificate returns a PEM-encoded certificate signed by the test CA for the test private key.
+func newUserCertificate(t *testing.T, validity time.Duration, dnsNames []string, ips ...net.IP) []byte {
+	t.Helper()
+	certData, err := testCA.CreateCertificate(certificates.ValidatedCertificateTemplate(x509.Certificate{
+		Subject:            pkix.Name{CommonName: "user-provided"},
+		DNSNames:           dnsNames,
+		IPAddresses:        ips,
+		NotBefore:          time.Now().Add(-10 * time.Minute),
+		NotAfter:           time.Now().Add(validity),
+		PublicKeyAlgorithm: x509.RSA,
+		PublicKey:          testRSAPrivateKey.Public(),
+		KeyUsage:           x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}))
+	require.NoError(t, err)
+	return certificates.EncodePEMCert(certData, testCA.Cert.Raw)
+}
+
+func Test_ensureUserTransportCertificatesSecretContentsForPod(t *testing.T) {
+	otherPrivateKey, err := certificates.GeneratePrivateKey(commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm})
+	require.NoError(t, err)
+	otherPrivateKeyPEM, err := certificates.EncodePEMPrivateKey(otherPrivateKey)
+	require.NoError(t, err)
+
+	podCert := newUserCertificate(t, certificates.DefaultCertValidity, []string{"test-pod-name.test-sset"})
+	wildcardCert := newUserCertificate(t, certificates.DefaultCertValidity, []string{"*.test-sset.test-namespace.svc"})
+	ipCert := newUserCertificate(t, certificates.DefaultCertValidity, nil, net.ParseIP(testIP))
+
+	tests := []struct {
+		name         string
+		data         map[string][]byte
+		wantReason   string
+		wantCopied   []byte
+		rotateBefore time.Duration
+	}{
+		{
+			name: "dedicated certificate",
+			data: map[string][]byte{
+				PodCertFileName(testPod.Name): podCert,
+				PodKeyFileName(testPod.Name):  testRSAPrivateKeyPEM,
+				certificates.CertFileName:     wildcardCert,
+				certificates.KeyFileName:      testRSAPrivateKeyPEM,
+			},
+			wantCopied: podCert,
+		},
+		{
+			name: "wildcard certificate",
+			data: map[string][]byte{
+				PodCertFileName("other-pod"): podCert,
+				PodKeyFileName("other-pod"):  testRSAPrivateKeyPEM,
+				certificates.CertFileName:    wildcardCert,
+				certificates.KeyFileName:     testRSAPrivateKeyPEM,
+			},
+			wantCopied: wildcardCert,
+		},
+		{
+			name: "certificate for the IP of the pod",
+			data: map[string][]byte{
+				PodCertFileName(testPod.Name): ipCert,
+				PodKeyFileName(testPod.Name):  testRSAPrivateKeyPEM,
+			},
+			wantCopied: ipCert,
+		},
+		{
+			name: "no certificate for the pod",
+			data: map[string][]byte{
+				PodCertFileName("other-pod"): podCert,
+				PodKeyFileName("other-pod"):  testRSAPrivateKeyPEM,
+			},
+			wantReason: "no certificate in secret test-namespace/node-certs",
+		},
+		{
+			name: "certificate for another pod",
+			data: map[string][]byte{
+				certificates.CertFileName: newUserCertificate(t, certificates.DefaultCertValidity, []string{"other-pod.test-sset"}),
+				certificates.KeyFileName:  testRSAPrivateKeyPEM,
+			},
+			wantReason: "certificate is not valid for any of test-pod-name.test-sset, test-pod-name.test-sset.test-namespace.svc, 1.2.3.4",
+		},
+		{
+			name: "certificate for another private key",
+			data: map[string][]byte{
+				PodCertFileName(testPod.Name): podCert,
+				PodKeyFileName(testPod.Name):  otherPrivateKeyPEM,
+			},
+			wantReason: "certificate does not match the private key",
+		},
+		{
+			name: "certificate about to expire",
+			data: map[string][]byte{
+				PodCertFileName(testPod.Name): podCert,
+				PodKeyFileName(testPod.Name):  testRSAPrivateKeyPEM,
+			},
+			rotateBefore: 2 * certificates.DefaultCertValidity,
+			wantReason:   "certificate expires at",
+			wantCopied:   podCert,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeCertificates := corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "node-certs"},
+				Data:       tt.data,
+			}
+			pod := testPod.DeepCopy()
+			es := testES.DeepCopy()
+			secret := &corev1.Secret{Data: map[string][]byte{}}
+
+			cert, reason := ensureUserTransportCertificatesSecretContentsForPod(*es, secret, *pod, nodeCertificates, tt.rotateBefore)
+			if tt.wantReason == "" {
+				assert.Empty(t, reason)
+			} else {
+				assert.Contains(t, reason, tt.wantReason)
+			}
+			if tt.wantCopied == nil {
+				assert.Nil(t, cert)
+				assert.Empty(t, secret.Data)
+				return
+			}
+			require.NotNil(t, cert)
+			assert.Equal(t, tt.wantCopied, secret.Data[PodCertFileName(pod.Name)])
+			assert.Equal(t, testRSAPrivateKeyPEM, secret.Data[PodKeyFileName(pod.Name)])
+		})
+	}
+}
+
+func TestReconcileTransportCertificatesSecrets_NodeCertificates(t *testing.T) {
+	es := newEsBuilder().addNodeSet("sset1", 2).build()
+	pod0 := newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(0).withIP("1.1.1.2").build()
+	pod1 := newPodBuilder().forEs(testEsName).inNodeSet("sset1").withIndex(1).withIP("1.1.1.3").build()
+	c := k8s.NewFakeClient(pod0, pod1)
+
+	nodeCertificates := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "node-certs"},
+		Data: map[string][]byte{
+			certificates.CAFileName:    testCABytes,
+			PodCertFileName(pod0.Name): newUserCertificate(t, certificates.DefaultCertValidity, []string{pod0.Name + ".test-es-name-es-sset1"}),
+			PodKeyFileName(pod0.Name):  testRSAPrivateKeyPEM,
+		},
+	}
+	rotationParams := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+
+	invalidCertificates, results := ReconcileTransportCertificatesSecrets(
+		c, nil, nodeCertificates, *es, rotationParams, certificates.DefaultPrivateKeyOptions,
+	)
+	require.False(t, results.HasError())
+	// requeue to report the certificate of the first Pod before it expires
+	result, _ := results.Aggregate()
+	assert.True(t, result.RequeueAfter > 0)
+	assert.True(t, result.RequeueAfter < certificates.DefaultCertValidity-certificates.DefaultRotateBefore)
+	// the second Pod has no certificate
+	require.Len(t, invalidCertificates, 1)
+	assert.Contains(t, invalidCertificates, pod1.Name)
+
+	var secrets corev1.SecretList
+	require.NoError(t, c.List(context.Background(), &secrets, label.NewLabelSelectorForElasticsearch(*es), client.InNamespace(testNamespace)))
+	transportCerts := getSecret(secrets, "test-es-name-es-sset1-es-transport-certs")
+	require.NotNil(t, transportCerts)
+	assert.Equal(t, testCABytes, transportCerts.Data[certificates.CAFileName])
+	assert.Equal(t, nodeCertificates.Data[PodCertFileName(pod0.Name)], transportCerts.Data[PodCertFileName(pod0.Name)])
+	assert.Equal(t, testRSAPrivateKeyPEM, transportCerts.Data[PodKeyFileName(pod0.Name)])
+	assert.NotContains(t, transportCerts.Data, PodCertFileName(pod1.Name))
+}
@@ -5,8 +5,8 @@
 package transport
 
 import (
+	"crypto"
 	cryptorand "crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -15,8 +15,11 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
 // PodKeyFileName returns the name of the private key entry for a specific pod in a transport certificates secret.
@@ -29,6 +32,60 @@ func PodCertFileName(podName string) string {
 	return fmt.Sprintf("%s.%s", podName, certificates.CertFileName)
 }
 
+// PodPendingKeyFileName returns the name of the entry of a private key waiting for a certificate to be issued by an
+// external issuer for a specific pod in a transport certificates secret.
+func PodPendingKeyFileName(podName string) string {
+	return fmt.Sprintf("%s.%s", podName, certificates.PendingKeyFileName)
+}
+
+// transportCertificateRequestName returns the name of the CertificateRequest of the transport certificate of a pod.
+func transportCertificateRequestName(podName string) string {
+	return fmt.Sprintf("%s-transport", podName)
+}
+
+// ensureIssuedTransportCertificatesSecretContentsForPod ensures that the transport certificates secret contains a
+// certificate signed by the external issuer referenced in the transport TLS options for a specific pod.
+// It returns true if the certificate has been issued.
+func ensureIssuedTransportCertificatesSecretContentsForPod(
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	secret *corev1.Secret,
+	pod corev1.Pod,
+	rotationParams certificates.RotationParams,
+	privateKeyOptions commonv1.PrivateKeyOptions,
+) (bool, error) {
+	template, err := createCertificateRequestTemplate(pod, es)
+	if err != nil {
+		return false, err
+	}
+
+	_, issued, err := certificates.ReconcileIssuedCertificate(
+		secret.Data,
+		certificates.IssuedCertificateKeys{
+			PrivateKey:        PodKeyFileName(pod.Name),
+			PendingPrivateKey: PodPendingKeyFileName(pod.Name),
+			Certificate:       PodCertFileName(pod.Name),
+		},
+		privateKeyOptions,
+		certificates.CertificateRequestParams{
+			Client:    c,
+			Owner:     &es,
+			Name:      transportCertificateRequestName(pod.Name),
+			Labels:    label.NewLabels(k8s.ExtractNamespacedName(&es)),
+			IssuerRef: *es.Spec.Transport.TLS.IssuerRef,
+			Template:  *template,
+			Rotation:  rotationParams,
+		},
+	)
+	if err != nil || issued == nil {
+		return false, err
+	}
+
+	// trust the CA of the issuer, in addition to the previous ones which may still be used by the other pods
+	secret.Data[certificates.CAFileName] = withIssuerCA(secret.Data[certificates.CAFileName], issued.CA)
+	return true, nil
+}
+
 // ensureTransportCertificatesSecretContentsForPod ensures that the transport certificates secret has the correct
 // content for a specific pod
 func ensureTransportCertificatesSecretContentsForPod(
@@ -37,16 +94,21 @@ func ensureTransportCertificatesSecretContentsForPod(
 	pod corev1.Pod,
 	ca *certificates.CA,
 	rotationParams certificates.RotationParams,
+	privateKeyOptions commonv1.PrivateKeyOptions,
 ) error {
-	// verify that the secret contains a parsable private key, create if it does not exist
-	var privateKey *rsa.PrivateKey
+	// verify that the secret contains a parsable private key matching the private key options, create if it does not exist
+	var privateKey crypto.Signer
 	needsNewPrivateKey := true
 	if privateKeyData, ok := secret.Data[PodKeyFileName(pod.Name)]; ok {
 		storedPrivateKey, err := certificates.ParsePEMPrivateKey(privateKeyData)
-		if err != nil {
+		switch {
+		case err != nil:
 			log.Error(err, "Unable to parse stored private key",
 				"namespace", pod.Namespace, "pod_name", pod.Name)
-		} else {
+		case !certificates.PrivateKeyMatchesOptions(storedPrivateKey, privateKeyOptions):
+			log.Info("Stored private key does not match the private key options, generating a new one",
+				"namespace", pod.Namespace, "pod_name", pod.Name)
+		default:
 			needsNewPrivateKey = false
 			privateKey = storedPrivateKey
 		}
@@ -54,13 +116,17 @@ func ensureTransportCertificatesSecretContentsForPod(
 
 	// if we need a new private key, generate it
 	if needsNewPrivateKey {
-		generatedPrivateKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+		generatedPrivateKey, err := certificates.GeneratePrivateKey(privateKeyOptions)
+		if err != nil {
+			return err
+		}
+		encodedPrivateKey, err := certificates.EncodePEMPrivateKey(generatedPrivateKey)
 		if err != nil {
 			return err
 		}
 
 		privateKey = generatedPrivateKey
-		secret.Data[PodKeyFileName(pod.Name)] = certificates.EncodePEMPrivateKey(*privateKey)
+		secret.Data[PodKeyFileName(pod.Name)] = encodedPrivateKey
 	}
 
 	if shouldIssueNewCertificate(es, *secret, pod, privateKey, ca, rotationParams.RotateBefore) {
@@ -111,7 +177,7 @@ func shouldIssueNewCertificate(
 	es esv1.Elasticsearch,
 	secret corev1.Secret,
 	pod corev1.Pod,
-	privateKey *rsa.PrivateKey,
+	privateKey crypto.Signer,
 	ca *certificates.CA,
 	certReconcileBefore time.Duration,
 ) bool {
@@ -129,8 +195,7 @@ func shouldIssueNewCertificate(
 		return true
 	}
 
-	publicKey, publicKeyOk := cert.PublicKey.(*rsa.PublicKey)
-	if !publicKeyOk || publicKey.N.Cmp(privateKey.PublicKey.N) != 0 || publicKey.E != privateKey.PublicKey.E {
+	if !certificates.PrivateMatchesPublicKey(cert.PublicKey, privateKey) {
 		log.Info(
 			"Certificate belongs do a different public key, should issue new",
 			"namespace", pod.Namespace,
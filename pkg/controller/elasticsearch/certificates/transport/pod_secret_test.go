@@ -5,6 +5,11 @@
 package transport
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"testing"
 	"time"
 
@@ -12,8 +17,12 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
 func Test_shouldIssueNewCertificate(t *testing.T) {
@@ -107,11 +116,12 @@ func Test_shouldIssueNewCertificate(t *testing.T) {
 
 func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 	tests := []struct {
-		name       string
-		secret     *corev1.Secret
-		pod        *corev1.Pod
-		assertions func(t *testing.T, before corev1.Secret, after corev1.Secret)
-		wantErr    func(t *testing.T, err error)
+		name              string
+		secret            *corev1.Secret
+		pod               *corev1.Pod
+		privateKeyOptions commonv1.PrivateKeyOptions
+		assertions        func(t *testing.T, before corev1.Secret, after corev1.Secret)
+		wantErr           func(t *testing.T, err error)
 	}{
 		{
 			name: "no private key in the secret",
@@ -132,7 +142,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			name: "no cert in the secret",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name): certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name): testRSAPrivateKeyPEM,
 				},
 			},
 			assertions: func(t *testing.T, before corev1.Secret, after corev1.Secret) {
@@ -147,7 +157,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			name: "cert does not belong to the key in the secret",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name):  certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name):  testRSAPrivateKeyPEM,
 					PodCertFileName(testPod.Name): certificates.EncodePEMCert(testCA.Cert.Raw),
 				},
 			},
@@ -164,7 +174,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			name: "invalid cert in the secret",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name):  certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name):  testRSAPrivateKeyPEM,
 					PodCertFileName(testPod.Name): []byte("invalid"),
 				},
 			},
@@ -181,7 +191,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 			name: "valid data should not require updating",
 			secret: &corev1.Secret{
 				Data: map[string][]byte{
-					PodKeyFileName(testPod.Name):  certificates.EncodePEMPrivateKey(*testRSAPrivateKey),
+					PodKeyFileName(testPod.Name):  testRSAPrivateKeyPEM,
 					PodCertFileName(testPod.Name): pemCert,
 				},
 			},
@@ -189,6 +199,29 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 				assert.Equal(t, before, after)
 			},
 		},
+		{
+			name: "private key with a different algorithm in the secret",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					PodKeyFileName(testPod.Name):  testRSAPrivateKeyPEM,
+					PodCertFileName(testPod.Name): pemCert,
+				},
+			},
+			privateKeyOptions: commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm},
+			assertions: func(t *testing.T, before corev1.Secret, after corev1.Secret) {
+				// key should be re-generated with the expected algorithm
+				assert.NotEqual(t, before.Data[PodKeyFileName(testPod.Name)], after.Data[PodKeyFileName(testPod.Name)])
+				privateKey, err := certificates.ParsePEMPrivateKey(after.Data[PodKeyFileName(testPod.Name)])
+				require.NoError(t, err)
+				assert.IsType(t, &ecdsa.PrivateKey{}, privateKey)
+
+				// cert should be re-issued for the new key
+				assert.NotEqual(t, after.Data[PodCertFileName(testPod.Name)], before.Data[PodCertFileName(testPod.Name)])
+				certs, err := certificates.ParsePEMCerts(after.Data[PodCertFileName(testPod.Name)])
+				require.NoError(t, err)
+				assert.True(t, certificates.PrivateMatchesPublicKey(certs[0].PublicKey, privateKey))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -210,6 +243,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 					Validity:     certificates.DefaultCertValidity,
 					RotateBefore: certificates.DefaultRotateBefore,
 				},
+				tt.privateKeyOptions,
 			)
 			if tt.wantErr != nil {
 				tt.wantErr(t, err)
@@ -221,3 +255,58 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 		})
 	}
 }
+
+func Test_ensureIssuedTransportCertificatesSecretContentsForPod(t *testing.T) {
+	c := k8s.NewFakeClient()
+	es := testES.DeepCopy()
+	es.Spec.Transport.TLS.IssuerRef = &commonv1.IssuerReference{Name: "my-issuer"}
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	rotationParams := certificates.RotationParams{
+		Validity:     certificates.DefaultCertValidity,
+		RotateBefore: certificates.DefaultRotateBefore,
+	}
+
+	// a certificate is requested for a pending private key
+	issued, err := ensureIssuedTransportCertificatesSecretContentsForPod(c, *es, secret, testPod, rotationParams, certificates.DefaultPrivateKeyOptions)
+	require.NoError(t, err)
+	require.False(t, issued)
+	require.NotEmpty(t, secret.Data[PodPendingKeyFileName(testPod.Name)])
+	require.NotContains(t, secret.Data, PodKeyFileName(testPod.Name))
+	require.NotContains(t, secret.Data, PodCertFileName(testPod.Name))
+
+	certificateRequest := unstructured.Unstructured{}
+	certificateRequest.SetGroupVersionKind(certificates.CertificateRequestGVK)
+	key := types.NamespacedName{Namespace: testNamespace, Name: transportCertificateRequestName(testPod.Name)}
+	require.NoError(t, c.Get(context.Background(), key, &certificateRequest))
+
+	// sign the request as the issuer would do
+	encodedRequest, _, err := unstructured.NestedString(certificateRequest.Object, "spec", "request")
+	require.NoError(t, err)
+	pemRequest, err := base64.StdEncoding.DecodeString(encodedRequest)
+	require.NoError(t, err)
+	block, _ := pem.Decode(pemRequest)
+	require.NotNil(t, block)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(t, err)
+	template, err := createValidatedCertificateTemplate(testPod, *es, csr, certificates.DefaultCertValidity)
+	require.NoError(t, err)
+	cert, err := testCA.CreateCertificate(*template)
+	require.NoError(t, err)
+	certificateRequest.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "reason": "Issued"},
+		},
+		"certificate": base64.StdEncoding.EncodeToString(certificates.EncodePEMCert(cert)),
+		"ca":          base64.StdEncoding.EncodeToString(testCABytes),
+	}
+	require.NoError(t, c.Update(context.Background(), &certificateRequest))
+
+	// the issued certificate and the CA of the issuer are stored in the secret
+	issued, err = ensureIssuedTransportCertificatesSecretContentsForPod(c, *es, secret, testPod, rotationParams, certificates.DefaultPrivateKeyOptions)
+	require.NoError(t, err)
+	require.True(t, issued)
+	require.NotContains(t, secret.Data, PodPendingKeyFileName(testPod.Name))
+	require.NotEmpty(t, secret.Data[PodKeyFileName(testPod.Name)])
+	require.Equal(t, certificates.EncodePEMCert(cert), secret.Data[PodCertFileName(testPod.Name)])
+	require.Equal(t, testCABytes, secret.Data[certificates.CAFileName])
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
@@ -31,22 +32,26 @@ import (
 var log = ulog.Log.WithName("transport")
 
 // ReconcileTransportCertificatesSecrets reconciles the secret containing transport certificates for all nodes in the
-// cluster.
+// cluster. The certificates are copied from the node certificates secret if provided by the user, in which case it
+// returns the Pods whose certificates are missing, invalid or about to expire.
 // Secrets which are not used anymore are deleted as part of the downscale process.
 func ReconcileTransportCertificatesSecrets(
 	c k8s.Client,
 	ca *certificates.CA,
+	nodeCertificates *corev1.Secret,
 	es esv1.Elasticsearch,
 	rotationParams certificates.RotationParams,
-) *reconciler.Results {
+	privateKeyOptions commonv1.PrivateKeyOptions,
+) (InvalidCertificates, *reconciler.Results) {
 	results := &reconciler.Results{}
+	invalidCertificates := InvalidCertificates{}
 
 	// We must create transport certificates for the following StatefulSets:
 	// - the ones that still exist, even if they have been removed from the Spec
 	// - the ones that do not exist yet, but will be created in a later step of the reconciliation
 	actualStatefulSets, err := sset.RetrieveActualStatefulSets(c, k8s.ExtractNamespacedName(&es))
 	if err != nil {
-		return results.WithError(err)
+		return invalidCertificates, results.WithError(err)
 	}
 	ssets := set.Make()
 	for _, actualStatefulSet := range actualStatefulSets {
@@ -56,12 +61,34 @@ func ReconcileTransportCertificatesSecrets(
 		ssets.Add(esv1.StatefulSet(es.Name, nodeSet.Name))
 	}
 
+	pending := false
 	for ssetName := range ssets {
-		if err := reconcileNodeSetTransportCertificatesSecrets(c, ca, es, ssetName, rotationParams); err != nil {
+		ssetPending, ssetInvalidCertificates, ssetResults := reconcileNodeSetTransportCertificatesSecrets(
+			c, ca, nodeCertificates, es, ssetName, rotationParams, privateKeyOptions,
+		)
+		results.WithResults(ssetResults)
+		pending = pending || ssetPending
+		invalidCertificates.Merge(ssetInvalidCertificates)
+	}
+	if nodeCertificates == nil && es.Spec.Transport.TLS.UsesIssuer() && !pending {
+		// all the certificates are issued, the CAs which are not used anymore can be removed
+		if err := pruneIssuerCAs(c, es, ssets); err != nil {
 			results.WithError(err)
 		}
 	}
-	return results
+	if pending {
+		// CertificateRequests are not watched, check again later
+		results.WithIncompleteReconciliation(
+			reconcile.Result{RequeueAfter: certificates.CertificateRequestPollInterval},
+			"transport certificates are being issued",
+		)
+	}
+	if len(invalidCertificates) > 0 {
+		// the user-provided certificates are expected to be fixed, the secret is watched
+		log.Info("Some user-provided transport certificates cannot be used",
+			"namespace", es.Namespace, "es_name", es.Name, "certificates", invalidCertificates.String())
+	}
+	return invalidCertificates, results
 }
 
 // DeleteStatefulSetTransportCertificate removes the Secret which contains the transport certificates of a given Statefulset.
@@ -87,44 +114,79 @@ func DeleteLegacyTransportCertificate(client k8s.Client, es esv1.Elasticsearch)
 }
 
 // reconcileNodeSetTransportCertificatesSecrets reconciles the secret which contains the transport certificates for
-// a given StatefulSet.
+// a given StatefulSet. The CA is nil if the transport certificates are signed by an external issuer, in which case it
+// also returns true if some certificates are still being issued, or if they are provided by the user in the node
+// certificates secret, in which case it also returns the Pods whose certificates cannot be used as is.
+// The returned results include a requeue to handle the expiration of the certificates.
 func reconcileNodeSetTransportCertificatesSecrets(
 	c k8s.Client,
 	ca *certificates.CA,
+	nodeCertificates *corev1.Secret,
 	es esv1.Elasticsearch,
 	ssetName string,
 	rotationParams certificates.RotationParams,
-) error {
+	privateKeyOptions commonv1.PrivateKeyOptions,
+) (bool, InvalidCertificates, *reconciler.Results) {
 	results := &reconciler.Results{}
+	invalidCertificates := InvalidCertificates{}
 	// List all the existing Pods in the nodeSet
 	var pods corev1.PodList
 	matchLabels := label.NewLabelSelectorForStatefulSetName(es.Name, ssetName)
 	ns := client.InNamespace(es.Namespace)
 	if err := c.List(context.Background(), &pods, matchLabels, ns); err != nil {
-		return errors.WithStack(err)
+		return false, invalidCertificates, results.WithError(errors.WithStack(err))
 	}
 
 	secret, err := ensureTransportCertificatesSecretExists(c, es, ssetName)
 	if err != nil {
-		return err
+		return false, invalidCertificates, results.WithError(err)
 	}
 	// defensive copy of the current secret so we can check whether we need to update later on
 	currentTransportCertificatesSecret := secret.DeepCopy()
+	pending := false
 	for _, pod := range pods.Items {
 		if pod.Status.PodIP == "" {
 			log.Info("Skipping pod because it has no IP yet", "namespace", pod.Namespace, "pod_name", pod.Name)
 			continue
 		}
 
-		if err := ensureTransportCertificatesSecretContentsForPod(
-			es, secret, pod, ca, rotationParams,
+		if nodeCertificates != nil {
+			// the user is in charge of the renewal of the certificates, the secret is watched
+			cert, reason := ensureUserTransportCertificatesSecretContentsForPod(
+				es, secret, pod, *nodeCertificates, rotationParams.RotateBefore,
+			)
+			if reason != "" {
+				invalidCertificates[pod.Name] = reason
+			}
+			if cert != nil {
+				// report the certificate as about to expire once in the rotation window
+				results.WithResult(reconcile.Result{
+					RequeueAfter: certificates.ShouldRotateIn(time.Now(), cert.NotAfter, rotationParams.RotateBefore),
+				})
+			}
+			continue
+		}
+
+		if es.Spec.Transport.TLS.UsesIssuer() {
+			issued, err := ensureIssuedTransportCertificatesSecretContentsForPod(
+				c, es, secret, pod, rotationParams, privateKeyOptions,
+			)
+			if err != nil {
+				return pending, invalidCertificates, results.WithError(err)
+			}
+			if !issued {
+				pending = true
+				continue
+			}
+		} else if err := ensureTransportCertificatesSecretContentsForPod(
+			es, secret, pod, ca, rotationParams, privateKeyOptions,
 		); err != nil {
-			return err
+			return pending, invalidCertificates, results.WithError(err)
 		}
 		certCommonName := buildCertificateCommonName(pod, es.Name, es.Namespace)
 		cert := extractTransportCert(*secret, pod, certCommonName)
 		if cert == nil {
-			return errors.New("no certificate found for pod")
+			return pending, invalidCertificates, results.WithError(errors.New("no certificate found for pod"))
 		}
 		// handle cert expiry via requeue
 		results.WithResult(reconcile.Result{
@@ -152,28 +214,47 @@ func reconcileNodeSetTransportCertificatesSecrets(
 	if len(keysToPrune) > 0 {
 		log.Info("Pruning keys from certificates secret", "namespace", es.Namespace, "secret_name", secret.Name, "keys", keysToPrune)
 
+		prunedPods := set.Make()
 		for _, keyToRemove := range keysToPrune {
 			delete(secret.Data, keyToRemove)
+			prunedPods.Add(strings.SplitN(keyToRemove, ".", 2)[0])
 		}
-	}
 
-	caBytes := certificates.EncodePEMCert(ca.Cert.Raw)
+		if es.Spec.Transport.TLS.UsesIssuer() {
+			for podName := range prunedPods {
+				if err := certificates.DeleteCertificateRequest(c, es.Namespace, transportCertificateRequestName(podName)); err != nil {
+					return pending, invalidCertificates, results.WithError(err)
+				}
+			}
+		}
+	}
 
-	// compare with current trusted CA certs.
-	if !bytes.Equal(caBytes, secret.Data[certificates.CAFileName]) {
-		secret.Data[certificates.CAFileName] = caBytes
+	// the CA of an external issuer is set along with the issued certificates, the CA of user-provided certificates
+	// comes from the node certificates secret
+	var caBytes []byte
+	switch {
+	case nodeCertificates != nil:
+		caBytes = nodeCertificates.Data[certificates.CAFileName]
+	case ca != nil:
+		caBytes = certificates.EncodePEMCert(ca.Cert.Raw)
+	}
+	if caBytes != nil {
+		// compare with current trusted CA certs.
+		if !bytes.Equal(caBytes, secret.Data[certificates.CAFileName]) {
+			secret.Data[certificates.CAFileName] = caBytes
+		}
 	}
 
 	if !reflect.DeepEqual(secret, currentTransportCertificatesSecret) {
 		if err := c.Update(context.Background(), secret); err != nil {
-			return err
+			return pending, invalidCertificates, results.WithError(err)
 		}
 		for _, pod := range pods.Items {
 			annotation.MarkPodAsUpdated(c, pod)
 		}
 	}
 
-	return nil
+	return pending, invalidCertificates, results
 }
 
 // ensureTransportCertificatesSecretExists ensures the existence and Labels of the Secret that at a later point
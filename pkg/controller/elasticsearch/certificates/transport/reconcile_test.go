@@ -6,13 +6,11 @@ package transport
 
 import (
 	"context"
-	"reflect"
 	"testing"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/comparison"
-	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/stretchr/testify/assert"
@@ -34,7 +32,6 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 	tests := []struct {
 		name          string
 		args          args
-		want          *reconciler.Results
 		assertSecrets func(t *testing.T, secrets corev1.SecretList)
 	}{
 		{
@@ -57,7 +54,6 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 					newPodBuilder().forEs(testEsName).inNodeSet("sset3").withIndex(3).withIP("1.1.3.5").build(),
 				},
 			},
-			want: &reconciler.Results{},
 			assertSecrets: func(t *testing.T, secrets corev1.SecretList) {
 				// Check that there is 1 Secret per StatefulSet
 				assert.Equal(t, 3, len(secrets.Items))
@@ -112,7 +108,6 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 					newPodBuilder().forEs(testEsName).inNodeSet("sset3").withIndex(1).withIP("1.1.3.3").build(),
 				},
 			},
-			want: &reconciler.Results{},
 			assertSecrets: func(t *testing.T, secrets corev1.SecretList) {
 				// Check that there is 1 Secret per StatefulSet
 				assert.Equal(t, 3, len(secrets.Items))
@@ -153,7 +148,6 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 					newtransportCertsSecretBuilder(testEsName, "sset2").forPodIndices(0, 1, 2).build(), // Pod 2 does not exist
 				},
 			},
-			want: &reconciler.Results{},
 			assertSecrets: func(t *testing.T, secrets corev1.SecretList) {
 				// Check that there is 1 Secret per StatefulSet
 				assert.Equal(t, 2, len(secrets.Items))
@@ -187,9 +181,10 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			k8sClient := k8s.NewFakeClient(tt.args.initialObjects...)
-			if got := ReconcileTransportCertificatesSecrets(k8sClient, tt.args.ca, *tt.args.es, tt.args.rotationParams); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ReconcileTransportCertificatesSecrets() = %v, want %v", got, tt.want)
-			}
+			_, got := ReconcileTransportCertificatesSecrets(k8sClient, tt.args.ca, nil, *tt.args.es, tt.args.rotationParams, certificates.DefaultPrivateKeyOptions)
+			// only a requeue to rotate the certificates is expected
+			reconciled, reason := got.IsReconciled()
+			assert.True(t, reconciled, reason)
 			// Check Secrets
 			var secrets corev1.SecretList
 			matchLabels := label.NewLabelSelectorForElasticsearch(*tt.args.es)
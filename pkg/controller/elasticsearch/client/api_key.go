@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// APIKeyRequest is a request to create an API key.
+type APIKeyRequest struct {
+	// Name of the API key. Several API keys can share the same name.
+	Name string
+	// Expiration is the validity period of the API key.
+	Expiration time.Duration
+	// RoleDescriptors limit the privileges of the API key, by role name.
+	RoleDescriptors map[string]interface{}
+}
+
+// APIKey is an API key, as returned by the create API key API.
+type APIKey struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Expiration is the expiration time of the API key in milliseconds since the epoch.
+	Expiration int64  `json:"expiration,omitempty"`
+	APIKey     string `json:"api_key"`
+}
+
+// Encoded returns the API key encoded as id:api_key, which is the format expected by the Elasticsearch clients of
+// the Elastic Stack.
+func (k APIKey) Encoded() string {
+	return k.ID + ":" + k.APIKey
+}
+
+// ExpirationTime returns the expiration time of the API key, or the zero time if it does not expire.
+func (k APIKey) ExpirationTime() time.Time {
+	if k.Expiration == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, k.Expiration*int64(time.Millisecond))
+}
+
+// APIKeyClient manages the API keys used by other applications of the Elastic Stack to connect to a cluster.
+type APIKeyClient interface {
+	// GetRole returns the descriptor of a native or a built-in role. Roles defined in the roles file of the file realm
+	// are not returned. It returns an error for which IsNotFound is true if the role does not exist.
+	GetRole(ctx context.Context, name string) (map[string]interface{}, error)
+	// CreateAPIKey creates an API key owned by the user of the client, with the privileges of the role descriptors of
+	// the request.
+	CreateAPIKey(ctx context.Context, request APIKeyRequest) (APIKey, error)
+	// InvalidateAPIKeys invalidates all the API keys with the given name.
+	InvalidateAPIKeys(ctx context.Context, name string) error
+}
+
+type createAPIKeyRequest struct {
+	Name            string                 `json:"name"`
+	Expiration      string                 `json:"expiration,omitempty"`
+	RoleDescriptors map[string]interface{} `json:"role_descriptors"`
+}
+
+type invalidateAPIKeysRequest struct {
+	Name string `json:"name"`
+}
+
+func (c *clientV6) GetRole(ctx context.Context, name string) (map[string]interface{}, error) {
+	return c.getRole(ctx, securityPathV6, name)
+}
+
+func (c *clientV6) CreateAPIKey(ctx context.Context, request APIKeyRequest) (APIKey, error) {
+	return c.createAPIKey(ctx, securityPathV6, request)
+}
+
+func (c *clientV6) InvalidateAPIKeys(ctx context.Context, name string) error {
+	return c.invalidateAPIKeys(ctx, securityPathV6, name)
+}
+
+func (c *clientV7) GetRole(ctx context.Context, name string) (map[string]interface{}, error) {
+	return c.getRole(ctx, securityPathV7, name)
+}
+
+func (c *clientV7) CreateAPIKey(ctx context.Context, request APIKeyRequest) (APIKey, error) {
+	return c.createAPIKey(ctx, securityPathV7, request)
+}
+
+func (c *clientV7) InvalidateAPIKeys(ctx context.Context, name string) error {
+	return c.invalidateAPIKeys(ctx, securityPathV7, name)
+}
+
+func (c *clientV6) getRole(ctx context.Context, pathPrefix string, name string) (map[string]interface{}, error) {
+	var response map[string]map[string]interface{}
+	if err := c.get(ctx, fmt.Sprintf("%s/role/%s", pathPrefix, name), &response); err != nil {
+		return nil, err
+	}
+	role, exists := response[name]
+	if !exists {
+		return nil, fmt.Errorf("role %s not found in response", name)
+	}
+	return role, nil
+}
+
+func (c *clientV6) createAPIKey(ctx context.Context, pathPrefix string, request APIKeyRequest) (APIKey, error) {
+	body := createAPIKeyRequest{
+		Name:            request.Name,
+		RoleDescriptors: request.RoleDescriptors,
+	}
+	if request.Expiration > 0 {
+		body.Expiration = fmt.Sprintf("%ds", int64(request.Expiration.Seconds()))
+	}
+	var apiKey APIKey
+	if err := c.post(ctx, pathPrefix+"/api_key", body, &apiKey); err != nil {
+		return APIKey{}, errors.Wrapf(err, "unable to create API key %s", request.Name)
+	}
+	return apiKey, nil
+}
+
+func (c *clientV6) invalidateAPIKeys(ctx context.Context, pathPrefix string, name string) error {
+	if err := c.delete(ctx, pathPrefix+"/api_key", invalidateAPIKeysRequest{Name: name}, nil); err != nil {
+		return errors.Wrapf(err, "unable to invalidate API keys %s", name)
+	}
+	return nil
+}
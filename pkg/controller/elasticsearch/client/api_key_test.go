@@ -0,0 +1,94 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetRole(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), mockResponse(t, http.MethodGet, "/_security/role/ingest_admin", 200, `{
+  "ingest_admin": {
+    "cluster": ["manage_index_templates", "manage_pipeline"],
+    "indices": [],
+    "metadata": {"_reserved": true}
+  }
+}`))
+	role, err := client.GetRole(context.Background(), "ingest_admin")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"cluster":  []interface{}{"manage_index_templates", "manage_pipeline"},
+		"indices":  []interface{}{},
+		"metadata": map[string]interface{}{"_reserved": true},
+	}, role)
+}
+
+func TestClient_CreateAPIKey(t *testing.T) {
+	for _, tt := range []struct {
+		version string
+		path    string
+	}{
+		{version: "6.8.0", path: "/_xpack/security/api_key"},
+		{version: "7.15.0", path: "/_security/api_key"},
+	} {
+		client := NewMockClient(version.MustParse(tt.version), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, tt.path, req.URL.Path)
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			var sent map[string]interface{}
+			require.NoError(t, json.Unmarshal(body, &sent))
+			require.Equal(t, map[string]interface{}{
+				"name":       "default-beat-beat-user",
+				"expiration": "86400s",
+				"role_descriptors": map[string]interface{}{
+					"eck_beat_role": map[string]interface{}{"cluster": []interface{}{"monitor"}},
+				},
+			}, sent)
+			return NewMockResponse(200, req, `{
+  "id": "VuaCfGcBCdbkQm-e5aOx",
+  "name": "default-beat-beat-user",
+  "expiration": 1544068612110,
+  "api_key": "ui2lp2axTNmsyakw9tvNnw"
+}`)
+		})
+		apiKey, err := client.CreateAPIKey(context.Background(), APIKeyRequest{
+			Name:       "default-beat-beat-user",
+			Expiration: 24 * time.Hour,
+			RoleDescriptors: map[string]interface{}{
+				"eck_beat_role": Role{Cluster: []string{"monitor"}},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, APIKey{
+			ID:         "VuaCfGcBCdbkQm-e5aOx",
+			Name:       "default-beat-beat-user",
+			Expiration: 1544068612110,
+			APIKey:     "ui2lp2axTNmsyakw9tvNnw",
+		}, apiKey)
+		require.Equal(t, "VuaCfGcBCdbkQm-e5aOx:ui2lp2axTNmsyakw9tvNnw", apiKey.Encoded())
+		require.Equal(t, time.Unix(1544068612, 110*int64(time.Millisecond)), apiKey.ExpirationTime())
+	}
+}
+
+func TestClient_InvalidateAPIKeys(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/api_key", req.URL.Path)
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name": "default-beat-beat-user"}`, string(body))
+		return NewMockResponse(200, req, `{"invalidated_api_keys": ["VuaCfGcBCdbkQm-e5aOx"], "previously_invalidated_api_keys": [], "error_count": 0}`)
+	})
+	require.NoError(t, client.InvalidateAPIKeys(context.Background(), "default-beat-beat-user"))
+}
@@ -36,6 +36,11 @@ func (c *baseClient) Close() {
 	}
 }
 
+// Version returns the Elasticsearch version this client is targeting.
+func (c *baseClient) Version() version.Version {
+	return c.version
+}
+
 func (c *baseClient) equal(c2 *baseClient) bool {
 	// handle nil case
 	if c2 == nil && c != nil {
@@ -55,13 +55,22 @@ type Role struct {
 // Client captures the information needed to interact with an Elasticsearch cluster via HTTP
 type Client interface {
 	AllocationSetter
+	APIKeyClient
 	AutoscalingClient
+	ClusterResourcesClient
 	ShardLister
 	LicenseClient
+	MLClient
+	SecurityClient
+	ShutdownClient
+	SnapshotClient
 	// Close idle connections in the underlying http client.
 	Close()
 	// Equal returns true if other can be considered as the same client.
 	Equal(other Client) bool
+	// Version returns the Elasticsearch version this client is targeting, which is the lowest version running in the
+	// cluster.
+	Version() version.Version
 	// GetClusterInfo get the cluster information at /
 	GetClusterInfo(ctx context.Context) (Info, error)
 	// GetClusterRoutingAllocation retrieves the cluster routing allocation settings.
@@ -154,6 +163,16 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("%s: %s", e.response.Status, reason)
 }
 
+// IsUnauthorized checks whether the error was an HTTP 401 error.
+func IsUnauthorized(err error) bool {
+	switch err := err.(type) {
+	case *APIError:
+		return err.response.StatusCode == http.StatusUnauthorized
+	default:
+		return false
+	}
+}
+
 // IsForbidden checks whether the error was an HTTP 403 error.
 func IsForbidden(err error) bool {
 	switch err := err.(type) {
@@ -0,0 +1,163 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ResourceDefinition is the untyped body of the request used to create a resource through the Elasticsearch API.
+type ResourceDefinition map[string]interface{}
+
+type indexLifecyclePolicyResult struct {
+	Policy map[string]interface{} `json:"policy"`
+}
+
+type componentTemplatesResponse struct {
+	ComponentTemplates []struct {
+		Name              string             `json:"name"`
+		ComponentTemplate ResourceDefinition `json:"component_template"`
+	} `json:"component_templates"`
+}
+
+type indexTemplatesResponse struct {
+	IndexTemplates []struct {
+		Name          string             `json:"name"`
+		IndexTemplate ResourceDefinition `json:"index_template"`
+	} `json:"index_templates"`
+}
+
+// ClusterResourcesClient manages index lifecycle management policies, component and index templates and
+// ingest pipelines. The definitions are the bodies of the requests used to create the resources. The Get methods
+// return an error for which IsNotFound is true if the resource does not exist.
+type ClusterResourcesClient interface {
+	// GetIndexLifecyclePolicy returns the definition of an index lifecycle management policy.
+	GetIndexLifecyclePolicy(ctx context.Context, name string) (ResourceDefinition, error)
+	// PutIndexLifecyclePolicy creates or updates an index lifecycle management policy.
+	PutIndexLifecyclePolicy(ctx context.Context, name string, definition ResourceDefinition) error
+	// DeleteIndexLifecyclePolicy deletes an index lifecycle management policy.
+	DeleteIndexLifecyclePolicy(ctx context.Context, name string) error
+	// GetComponentTemplate returns the definition of a component template.
+	// Introduced in: Elasticsearch 7.8.0
+	GetComponentTemplate(ctx context.Context, name string) (ResourceDefinition, error)
+	// PutComponentTemplate creates or updates a component template.
+	// Introduced in: Elasticsearch 7.8.0
+	PutComponentTemplate(ctx context.Context, name string, definition ResourceDefinition) error
+	// DeleteComponentTemplate deletes a component template.
+	// Introduced in: Elasticsearch 7.8.0
+	DeleteComponentTemplate(ctx context.Context, name string) error
+	// GetIndexTemplate returns the definition of a composable index template.
+	// Introduced in: Elasticsearch 7.8.0
+	GetIndexTemplate(ctx context.Context, name string) (ResourceDefinition, error)
+	// PutIndexTemplate creates or updates a composable index template.
+	// Introduced in: Elasticsearch 7.8.0
+	PutIndexTemplate(ctx context.Context, name string, definition ResourceDefinition) error
+	// DeleteIndexTemplate deletes a composable index template.
+	// Introduced in: Elasticsearch 7.8.0
+	DeleteIndexTemplate(ctx context.Context, name string) error
+	// GetIngestPipeline returns the definition of an ingest pipeline.
+	GetIngestPipeline(ctx context.Context, name string) (ResourceDefinition, error)
+	// PutIngestPipeline creates or updates an ingest pipeline.
+	PutIngestPipeline(ctx context.Context, name string, definition ResourceDefinition) error
+	// DeleteIngestPipeline deletes an ingest pipeline.
+	DeleteIngestPipeline(ctx context.Context, name string) error
+}
+
+func (c *clientV6) GetIndexLifecyclePolicy(ctx context.Context, name string) (ResourceDefinition, error) {
+	var response map[string]indexLifecyclePolicyResult
+	if err := c.get(ctx, fmt.Sprintf("/_ilm/policy/%s", name), &response); err != nil {
+		return nil, err
+	}
+	result, exists := response[name]
+	if !exists {
+		return nil, fmt.Errorf("index lifecycle policy %s not found in response", name)
+	}
+	return ResourceDefinition{"policy": result.Policy}, nil
+}
+
+func (c *clientV6) PutIndexLifecyclePolicy(ctx context.Context, name string, definition ResourceDefinition) error {
+	if err := c.put(ctx, fmt.Sprintf("/_ilm/policy/%s", name), definition, nil); err != nil {
+		return errors.Wrapf(err, "unable to put index lifecycle policy %s", name)
+	}
+	return nil
+}
+
+func (c *clientV6) DeleteIndexLifecyclePolicy(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_ilm/policy/%s", name), nil, nil)
+}
+
+func (c *clientV7) GetComponentTemplate(ctx context.Context, name string) (ResourceDefinition, error) {
+	var response componentTemplatesResponse
+	if err := c.get(ctx, fmt.Sprintf("/_component_template/%s", name), &response); err != nil {
+		return nil, err
+	}
+	for _, template := range response.ComponentTemplates {
+		if template.Name == name {
+			return template.ComponentTemplate, nil
+		}
+	}
+	return nil, fmt.Errorf("component template %s not found in response", name)
+}
+
+func (c *clientV7) PutComponentTemplate(ctx context.Context, name string, definition ResourceDefinition) error {
+	if err := c.put(ctx, fmt.Sprintf("/_component_template/%s", name), definition, nil); err != nil {
+		return errors.Wrapf(err, "unable to put component template %s", name)
+	}
+	return nil
+}
+
+func (c *clientV7) DeleteComponentTemplate(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_component_template/%s", name), nil, nil)
+}
+
+func (c *clientV7) GetIndexTemplate(ctx context.Context, name string) (ResourceDefinition, error) {
+	var response indexTemplatesResponse
+	if err := c.get(ctx, fmt.Sprintf("/_index_template/%s", name), &response); err != nil {
+		return nil, err
+	}
+	for _, template := range response.IndexTemplates {
+		if template.Name == name {
+			return template.IndexTemplate, nil
+		}
+	}
+	return nil, fmt.Errorf("index template %s not found in response", name)
+}
+
+func (c *clientV7) PutIndexTemplate(ctx context.Context, name string, definition ResourceDefinition) error {
+	if err := c.put(ctx, fmt.Sprintf("/_index_template/%s", name), definition, nil); err != nil {
+		return errors.Wrapf(err, "unable to put index template %s", name)
+	}
+	return nil
+}
+
+func (c *clientV7) DeleteIndexTemplate(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_index_template/%s", name), nil, nil)
+}
+
+func (c *clientV6) GetIngestPipeline(ctx context.Context, name string) (ResourceDefinition, error) {
+	var response map[string]ResourceDefinition
+	if err := c.get(ctx, fmt.Sprintf("/_ingest/pipeline/%s", name), &response); err != nil {
+		return nil, err
+	}
+	pipeline, exists := response[name]
+	if !exists {
+		return nil, fmt.Errorf("ingest pipeline %s not found in response", name)
+	}
+	return pipeline, nil
+}
+
+func (c *clientV6) PutIngestPipeline(ctx context.Context, name string, definition ResourceDefinition) error {
+	if err := c.put(ctx, fmt.Sprintf("/_ingest/pipeline/%s", name), definition, nil); err != nil {
+		return errors.Wrapf(err, "unable to put ingest pipeline %s", name)
+	}
+	return nil
+}
+
+func (c *clientV6) DeleteIngestPipeline(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("/_ingest/pipeline/%s", name), nil, nil)
+}
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/stretchr/testify/require"
+)
+
+func mockResponse(t *testing.T, method, path string, statusCode int, body string) func(req *http.Request) *http.Response {
+	t.Helper()
+	return func(req *http.Request) *http.Response {
+		require.Equal(t, method, req.Method)
+		require.Equal(t, path, req.URL.Path)
+		return NewMockResponse(statusCode, req, body)
+	}
+}
+
+func TestClient_GetIndexLifecyclePolicy(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), mockResponse(t, http.MethodGet, "/_ilm/policy/my-policy", 200, `{
+  "my-policy": {
+    "version": 1,
+    "modified_date": 82392349,
+    "policy": {
+      "phases": {
+        "delete": {
+          "min_age": "30d",
+          "actions": {
+            "delete": {}
+          }
+        }
+      }
+    }
+  }
+}`))
+	policy, err := client.GetIndexLifecyclePolicy(context.Background(), "my-policy")
+	require.NoError(t, err)
+	require.Equal(t, ResourceDefinition{"policy": map[string]interface{}{
+		"phases": map[string]interface{}{
+			"delete": map[string]interface{}{
+				"min_age": "30d",
+				"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+			},
+		},
+	}}, policy)
+}
+
+func TestClient_GetIndexTemplate(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), mockResponse(t, http.MethodGet, "/_index_template/my-template", 200, `{
+  "index_templates": [
+    {
+      "name": "my-template",
+      "index_template": {
+        "index_patterns": ["logs-*"],
+        "composed_of": ["my-mappings"],
+        "priority": 500
+      }
+    }
+  ]
+}`))
+	template, err := client.GetIndexTemplate(context.Background(), "my-template")
+	require.NoError(t, err)
+	require.Equal(t, ResourceDefinition{
+		"index_patterns": []interface{}{"logs-*"},
+		"composed_of":    []interface{}{"my-mappings"},
+		"priority":       float64(500),
+	}, template)
+}
+
+func TestClient_GetIngestPipelineNotFound(t *testing.T) {
+	client := NewMockClient(version.MustParse("6.8.0"), mockResponse(t, http.MethodGet, "/_ingest/pipeline/my-pipeline", 404, `{}`))
+	_, err := client.GetIngestPipeline(context.Background(), "my-pipeline")
+	require.Error(t, err)
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_TemplatesNotSupportedInEs6x(t *testing.T) {
+	client := NewMockClient(version.MustParse("6.8.0"), func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+		return nil
+	})
+	_, err := client.GetComponentTemplate(context.Background(), "t")
+	require.Equal(t, errNotSupportedInEs6x, err)
+	require.Equal(t, errNotSupportedInEs6x, client.PutIndexTemplate(context.Background(), "t", ResourceDefinition{}))
+}
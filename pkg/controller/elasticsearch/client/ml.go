@@ -0,0 +1,84 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// DatafeedStarted is the state of a datafeed which has been started.
+const DatafeedStarted = "started"
+
+// MLInfo models the machine learning information returned by the /_ml/info API.
+type MLInfo struct {
+	// UpgradeMode is true if the machine learning jobs and datafeeds are halted for an upgrade.
+	UpgradeMode bool `json:"upgrade_mode"`
+}
+
+// DatafeedNode is the node a datafeed is assigned to.
+type DatafeedNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// DatafeedStats models the statistics of a single datafeed as returned by the /_ml/datafeeds/_stats API.
+type DatafeedStats struct {
+	DatafeedID string `json:"datafeed_id"`
+	State      string `json:"state"`
+	// Node is nil if the datafeed is not assigned to any node.
+	Node                  *DatafeedNode `json:"node,omitempty"`
+	AssignmentExplanation string        `json:"assignment_explanation,omitempty"`
+}
+
+// DatafeedsStats is the response of the /_ml/datafeeds/_stats API.
+type DatafeedsStats struct {
+	Count     int             `json:"count"`
+	Datafeeds []DatafeedStats `json:"datafeeds"`
+}
+
+// RunningOn returns the IDs of the started datafeeds assigned to the node with the given name.
+func (s DatafeedsStats) RunningOn(nodeName string) []string {
+	var ids []string
+	for _, datafeed := range s.Datafeeds {
+		if datafeed.State == DatafeedStarted && datafeed.Node != nil && datafeed.Node.Name == nodeName {
+			ids = append(ids, datafeed.DatafeedID)
+		}
+	}
+	return ids
+}
+
+// MLClient manages the machine learning jobs of a cluster during rolling upgrades.
+type MLClient interface {
+	// GetMLInfo returns the machine learning information of the cluster, including whether the upgrade mode is set.
+	GetMLInfo(ctx context.Context) (MLInfo, error)
+	// SetMLUpgradeMode halts, or resumes, all the machine learning jobs and datafeeds of the cluster. Enabling the
+	// upgrade mode returns once all the job and datafeed tasks are unassigned from the nodes.
+	// Introduced in: Elasticsearch 6.7.0
+	SetMLUpgradeMode(ctx context.Context, enabled bool) error
+	// GetDatafeedsStats returns the statistics of all the datafeeds of the cluster.
+	GetDatafeedsStats(ctx context.Context) (DatafeedsStats, error)
+}
+
+func (c *clientV6) GetMLInfo(ctx context.Context) (MLInfo, error) {
+	var info MLInfo
+	err := c.get(ctx, "/_ml/info", &info)
+	return info, err
+}
+
+func (c *clientV6) SetMLUpgradeMode(ctx context.Context, enabled bool) error {
+	if err := c.post(ctx, fmt.Sprintf("/_ml/set_upgrade_mode?enabled=%t", enabled), nil, nil); err != nil {
+		return errors.Wrapf(err, "unable to set the machine learning upgrade mode to %t", enabled)
+	}
+	return nil
+}
+
+func (c *clientV6) GetDatafeedsStats(ctx context.Context) (DatafeedsStats, error) {
+	var stats DatafeedsStats
+	err := c.get(ctx, "/_ml/datafeeds/_stats", &stats)
+	return stats, err
+}
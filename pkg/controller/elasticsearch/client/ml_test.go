@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDatafeedsStats = `{
+  "count": 2,
+  "datafeeds": [
+    {
+      "datafeed_id": "datafeed-high_sum_total_sales",
+      "state": "started",
+      "node": {
+        "id": "7bmMXyWCRs-TuPfGJJ_yMw",
+        "name": "es-es-ml-0",
+        "ephemeral_id": "hoXMLZB0RWKfR9UPPUCxXX",
+        "transport_address": "127.0.0.1:9300",
+        "attributes": {}
+      },
+      "assignment_explanation": ""
+    },
+    {
+      "datafeed_id": "datafeed-low_request_rate",
+      "state": "stopped",
+      "assignment_explanation": ""
+    }
+  ]
+}`
+
+func TestClient_GetMLInfo(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_ml/info", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"defaults": {}, "upgrade_mode": true, "native_code": {}, "limits": {}}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	info, err := client.GetMLInfo(context.Background())
+	require.NoError(t, err)
+	require.True(t, info.UpgradeMode)
+}
+
+func TestClient_SetMLUpgradeMode(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		enabled := enabled
+		client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, "/_ml/set_upgrade_mode", req.URL.Path)
+			require.Equal(t, strconv.FormatBool(enabled), req.URL.Query().Get("enabled"))
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+				Header:     make(http.Header),
+				Request:    req,
+			}
+		})
+		require.NoError(t, client.SetMLUpgradeMode(context.Background(), enabled))
+	}
+}
+
+func TestClient_GetDatafeedsStats(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_ml/datafeeds/_stats", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(sampleDatafeedsStats)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	stats, err := client.GetDatafeedsStats(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stats.Datafeeds, 2)
+	require.Equal(t, []string{"datafeed-high_sum_total_sales"}, stats.RunningOn("es-es-ml-0"))
+	require.Empty(t, stats.RunningOn("es-es-ml-1"))
+}
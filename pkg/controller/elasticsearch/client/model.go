@@ -200,7 +200,7 @@ func (s Shard) Key() string {
 // AllocationSettings model a subset of the supported attributes for dynamic Elasticsearch cluster settings.
 type AllocationSettings struct {
 	Cluster ClusterRoutingSettings `json:"cluster,omitempty"`
-} // TODO awareness settings
+}
 
 type ClusterRoutingSettings struct {
 	Routing RoutingSettings `json:"routing,omitempty"`
@@ -373,9 +373,46 @@ type RemoteClusters struct {
 	RemoteClusters map[string]RemoteCluster `json:"remote,omitempty"`
 }
 
-// RemoteClusterSeeds is the set of seeds to use in a remote cluster setting.
+const (
+	// RemoteClusterSniffMode is the connection mode of remote clusters reached through a list of seed nodes.
+	RemoteClusterSniffMode = "sniff"
+	// RemoteClusterProxyMode is the connection mode of remote clusters reached through a single proxy address.
+	RemoteClusterProxyMode = "proxy"
+)
+
+// RemoteCluster is the set of settings of a remote cluster.
 type RemoteCluster struct {
-	Seeds []string `json:"seeds"`
+	Seeds        []string `json:"seeds"`
+	Mode         string   `json:"mode,omitempty"`
+	ProxyAddress string   `json:"proxy_address,omitempty"`
+	ServerName   string   `json:"server_name,omitempty"`
+	// ConnectionModes must be set if Elasticsearch supports connection modes (7.7.0 and above). All the settings
+	// are then serialized, the ones that are not set being explicitly null to be removed from the cluster settings.
+	ConnectionModes bool `json:"-"`
+}
+
+// MarshalJSON serializes the settings of a remote cluster. Settings which do not apply to the connection mode must
+// be removed when the mode is changed, which requires them to be explicitly set to null.
+func (rc RemoteCluster) MarshalJSON() ([]byte, error) {
+	if !rc.ConnectionModes {
+		return json.Marshal(map[string]interface{}{"seeds": rc.Seeds})
+	}
+	settings := map[string]interface{}{
+		"seeds":         rc.Seeds,
+		"mode":          nil,
+		"proxy_address": nil,
+		"server_name":   nil,
+	}
+	if rc.Mode != "" {
+		settings["mode"] = rc.Mode
+	}
+	if rc.ProxyAddress != "" {
+		settings["proxy_address"] = rc.ProxyAddress
+	}
+	if rc.ServerName != "" {
+		settings["server_name"] = rc.ServerName
+	}
+	return json.Marshal(settings)
 }
 
 // Hit represents a single search hit.
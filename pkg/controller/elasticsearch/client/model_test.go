@@ -48,6 +48,56 @@ func TestModel_RemoteCluster(t *testing.T) {
 			},
 			want: `{"persistent":{"cluster":{"remote":{"leader":{"seeds":null}}}}}`,
 		},
+		{
+			name: "Remote cluster in sniff mode",
+			arg: RemoteClustersSettings{
+				PersistentSettings: &SettingsGroup{
+					Cluster: RemoteClusters{
+						RemoteClusters: map[string]RemoteCluster{
+							"leader": {
+								Mode:            RemoteClusterSniffMode,
+								Seeds:           []string{"127.0.0.1:9300"},
+								ConnectionModes: true,
+							},
+						},
+					},
+				},
+			},
+			want: `{"persistent":{"cluster":{"remote":{"leader":{"mode":"sniff","proxy_address":null,"seeds":["127.0.0.1:9300"],"server_name":null}}}}}`,
+		},
+		{
+			name: "Remote cluster in proxy mode",
+			arg: RemoteClustersSettings{
+				PersistentSettings: &SettingsGroup{
+					Cluster: RemoteClusters{
+						RemoteClusters: map[string]RemoteCluster{
+							"leader": {
+								Mode:            RemoteClusterProxyMode,
+								ProxyAddress:    "leader.example.com:9400",
+								ServerName:      "leader.example.com",
+								ConnectionModes: true,
+							},
+						},
+					},
+				},
+			},
+			want: `{"persistent":{"cluster":{"remote":{"leader":{"mode":"proxy","proxy_address":"leader.example.com:9400","seeds":null,"server_name":"leader.example.com"}}}}}`,
+		},
+		{
+			name: "Deleted remote cluster with connection modes",
+			arg: RemoteClustersSettings{
+				PersistentSettings: &SettingsGroup{
+					Cluster: RemoteClusters{
+						RemoteClusters: map[string]RemoteCluster{
+							"leader": {
+								ConnectionModes: true,
+							},
+						},
+					},
+				},
+			},
+			want: `{"persistent":{"cluster":{"remote":{"leader":{"mode":null,"proxy_address":null,"seeds":null,"server_name":null}}}}}`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
+)
+
+const (
+	// securityPathV6 is the path prefix of the security APIs in Elasticsearch 6.x.
+	securityPathV6 = "/_xpack/security"
+	// securityPathV7 is the path prefix of the security APIs from Elasticsearch 7.0.0.
+	securityPathV7 = "/_security"
+)
+
+// NativeUser is a user of the native realm, as created or returned by the user APIs.
+type NativeUser struct {
+	Username string `json:"username,omitempty"`
+	// Password is only set to create the user or to change its password. It is never returned by Elasticsearch.
+	Password string                 `json:"password,omitempty"`
+	Roles    []string               `json:"roles"`
+	FullName string                 `json:"full_name,omitempty"`
+	Email    string                 `json:"email,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Enabled  bool                   `json:"enabled"`
+}
+
+// RoleMapping maps the users authenticated by a realm to roles, as created or returned by the role mapping APIs.
+type RoleMapping struct {
+	Roles    []string               `json:"roles"`
+	Rules    map[string]interface{} `json:"rules"`
+	Enabled  bool                   `json:"enabled"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// SecurityClient manages the native realm users and the role mappings of a cluster. The Get methods return an error
+// for which IsNotFound is true if the user or the role mapping does not exist.
+type SecurityClient interface {
+	// GetNativeUser returns a user of the native realm.
+	GetNativeUser(ctx context.Context, username string) (NativeUser, error)
+	// PutNativeUser creates or updates a user of the native realm. The password of an existing user is left unchanged
+	// if it is not set.
+	PutNativeUser(ctx context.Context, user NativeUser) error
+	// DeleteNativeUser deletes a user of the native realm.
+	DeleteNativeUser(ctx context.Context, username string) error
+	// GetRoleMapping returns a role mapping.
+	GetRoleMapping(ctx context.Context, name string) (RoleMapping, error)
+	// PutRoleMapping creates or updates a role mapping.
+	PutRoleMapping(ctx context.Context, name string, mapping RoleMapping) error
+	// DeleteRoleMapping deletes a role mapping.
+	DeleteRoleMapping(ctx context.Context, name string) error
+	// Authenticate returns an error if a request with the given Authorization header value is not authenticated. The
+	// credentials of the client are not used.
+	Authenticate(ctx context.Context, authorization string) error
+}
+
+// BasicAuthorization returns the value of the Authorization header of the requests authenticated as the given user.
+func BasicAuthorization(user BasicAuth) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user.Name+":"+user.Password))
+}
+
+// BearerAuthorization returns the value of the Authorization header of the requests authenticated with the given
+// bearer token, such as a service account token.
+func BearerAuthorization(token []byte) string {
+	return "Bearer " + string(token)
+}
+
+func (c *clientV6) GetNativeUser(ctx context.Context, username string) (NativeUser, error) {
+	return c.getNativeUser(ctx, securityPathV6, username)
+}
+
+func (c *clientV6) PutNativeUser(ctx context.Context, user NativeUser) error {
+	return c.putNativeUser(ctx, securityPathV6, user)
+}
+
+func (c *clientV6) DeleteNativeUser(ctx context.Context, username string) error {
+	return c.delete(ctx, fmt.Sprintf("%s/user/%s", securityPathV6, username), nil, nil)
+}
+
+func (c *clientV6) GetRoleMapping(ctx context.Context, name string) (RoleMapping, error) {
+	return c.getRoleMapping(ctx, securityPathV6, name)
+}
+
+func (c *clientV6) PutRoleMapping(ctx context.Context, name string, mapping RoleMapping) error {
+	return c.putRoleMapping(ctx, securityPathV6, name, mapping)
+}
+
+func (c *clientV6) DeleteRoleMapping(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("%s/role_mapping/%s", securityPathV6, name), nil, nil)
+}
+
+func (c *clientV6) Authenticate(ctx context.Context, authorization string) error {
+	return c.authenticate(ctx, securityPathV6, authorization)
+}
+
+func (c *clientV7) GetNativeUser(ctx context.Context, username string) (NativeUser, error) {
+	return c.getNativeUser(ctx, securityPathV7, username)
+}
+
+func (c *clientV7) PutNativeUser(ctx context.Context, user NativeUser) error {
+	return c.putNativeUser(ctx, securityPathV7, user)
+}
+
+func (c *clientV7) DeleteNativeUser(ctx context.Context, username string) error {
+	return c.delete(ctx, fmt.Sprintf("%s/user/%s", securityPathV7, username), nil, nil)
+}
+
+func (c *clientV7) GetRoleMapping(ctx context.Context, name string) (RoleMapping, error) {
+	return c.getRoleMapping(ctx, securityPathV7, name)
+}
+
+func (c *clientV7) PutRoleMapping(ctx context.Context, name string, mapping RoleMapping) error {
+	return c.putRoleMapping(ctx, securityPathV7, name, mapping)
+}
+
+func (c *clientV7) DeleteRoleMapping(ctx context.Context, name string) error {
+	return c.delete(ctx, fmt.Sprintf("%s/role_mapping/%s", securityPathV7, name), nil, nil)
+}
+
+func (c *clientV7) Authenticate(ctx context.Context, authorization string) error {
+	return c.authenticate(ctx, securityPathV7, authorization)
+}
+
+func (c *clientV6) getNativeUser(ctx context.Context, pathPrefix string, username string) (NativeUser, error) {
+	var response map[string]NativeUser
+	if err := c.get(ctx, fmt.Sprintf("%s/user/%s", pathPrefix, username), &response); err != nil {
+		return NativeUser{}, err
+	}
+	user, exists := response[username]
+	if !exists {
+		return NativeUser{}, fmt.Errorf("user %s not found in response", username)
+	}
+	return user, nil
+}
+
+func (c *clientV6) putNativeUser(ctx context.Context, pathPrefix string, user NativeUser) error {
+	username := user.Username
+	// the username is part of the path, not of the request body
+	user.Username = ""
+	if err := c.put(ctx, fmt.Sprintf("%s/user/%s", pathPrefix, username), user, nil); err != nil {
+		return errors.Wrapf(err, "unable to put user %s", username)
+	}
+	return nil
+}
+
+func (c *clientV6) getRoleMapping(ctx context.Context, pathPrefix string, name string) (RoleMapping, error) {
+	var response map[string]RoleMapping
+	if err := c.get(ctx, fmt.Sprintf("%s/role_mapping/%s", pathPrefix, name), &response); err != nil {
+		return RoleMapping{}, err
+	}
+	mapping, exists := response[name]
+	if !exists {
+		return RoleMapping{}, fmt.Errorf("role mapping %s not found in response", name)
+	}
+	return mapping, nil
+}
+
+func (c *clientV6) putRoleMapping(ctx context.Context, pathPrefix string, name string, mapping RoleMapping) error {
+	if err := c.put(ctx, fmt.Sprintf("%s/role_mapping/%s", pathPrefix, name), mapping, nil); err != nil {
+		return errors.Wrapf(err, "unable to put role mapping %s", name)
+	}
+	return nil
+}
+
+func (c *clientV6) authenticate(ctx context.Context, pathPrefix string, authorization string) error {
+	request, err := http.NewRequest(http.MethodGet, stringsutil.Concat(c.Endpoint, pathPrefix, "/_authenticate"), http.NoBody)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", authorization)
+	// the credentials of the client would replace the given ones
+	withoutUser := c.baseClient
+	withoutUser.User = BasicAuth{}
+	response, err := withoutUser.doRequest(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetNativeUser(t *testing.T) {
+	body := `{
+  "jacknich": {
+    "username": "jacknich",
+    "roles": ["admin", "other_role1"],
+    "full_name": "Jack Nicholson",
+    "email": "jacknich@example.com",
+    "metadata": {"intelligence": 7},
+    "enabled": true
+  }
+}`
+	for _, tt := range []struct {
+		version string
+		path    string
+	}{
+		{version: "6.8.0", path: "/_xpack/security/user/jacknich"},
+		{version: "7.15.0", path: "/_security/user/jacknich"},
+	} {
+		client := NewMockClient(version.MustParse(tt.version), mockResponse(t, http.MethodGet, tt.path, 200, body))
+		user, err := client.GetNativeUser(context.Background(), "jacknich")
+		require.NoError(t, err)
+		require.Equal(t, NativeUser{
+			Username: "jacknich",
+			Roles:    []string{"admin", "other_role1"},
+			FullName: "Jack Nicholson",
+			Email:    "jacknich@example.com",
+			Metadata: map[string]interface{}{"intelligence": float64(7)},
+			Enabled:  true,
+		}, user)
+	}
+}
+
+func TestClient_GetNativeUser_NotFound(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), mockResponse(t, http.MethodGet, "/_security/user/missing", 404, "{}"))
+	_, err := client.GetNativeUser(context.Background(), "missing")
+	require.True(t, IsNotFound(err))
+}
+
+func TestClient_PutNativeUser(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/user/jacknich", req.URL.Path)
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		var sent map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &sent))
+		require.Equal(t, map[string]interface{}{
+			"password": "l0ng-r4nd0m-p@ssw0rd",
+			"roles":    []interface{}{"admin"},
+			"enabled":  true,
+		}, sent)
+		return NewMockResponse(200, req, `{"created": true}`)
+	})
+	require.NoError(t, client.PutNativeUser(context.Background(), NativeUser{
+		Username: "jacknich",
+		Password: "l0ng-r4nd0m-p@ssw0rd",
+		Roles:    []string{"admin"},
+		Enabled:  true,
+	}))
+}
+
+func TestClient_GetRoleMapping(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), mockResponse(t, http.MethodGet, "/_security/role_mapping/mapping1", 200, `{
+  "mapping1": {
+    "enabled": true,
+    "roles": ["user"],
+    "rules": {"field": {"username": "*"}},
+    "metadata": {}
+  }
+}`))
+	mapping, err := client.GetRoleMapping(context.Background(), "mapping1")
+	require.NoError(t, err)
+	require.Equal(t, RoleMapping{
+		Roles:    []string{"user"},
+		Rules:    map[string]interface{}{"field": map[string]interface{}{"username": "*"}},
+		Enabled:  true,
+		Metadata: map[string]interface{}{},
+	}, mapping)
+}
+
+func TestClient_DeleteRoleMapping(t *testing.T) {
+	client := NewMockClient(version.MustParse("6.8.0"), mockResponse(t, http.MethodDelete, "/_xpack/security/role_mapping/mapping1", 200, `{"found": true}`))
+	require.NoError(t, client.DeleteRoleMapping(context.Background(), "mapping1"))
+}
+
+func TestClient_Authenticate(t *testing.T) {
+	authorization := BasicAuthorization(BasicAuth{Name: "jacknich", Password: "l0ng-r4nd0m-p@ssw0rd"})
+	for _, tt := range []struct {
+		version string
+		path    string
+	}{
+		{version: "6.8.0", path: "/_xpack/security/_authenticate"},
+		{version: "7.15.0", path: "/_security/_authenticate"},
+	} {
+		client := NewMockClientWithUser(version.MustParse(tt.version), BasicAuth{Name: "elastic-internal", Password: "password"}, func(req *http.Request) *http.Response {
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, tt.path, req.URL.Path)
+			username, password, ok := req.BasicAuth()
+			require.True(t, ok)
+			if username != "jacknich" || password != "l0ng-r4nd0m-p@ssw0rd" {
+				return NewMockResponse(401, req, "{}")
+			}
+			return NewMockResponse(200, req, `{"username": "jacknich"}`)
+		})
+		require.NoError(t, client.Authenticate(context.Background(), authorization))
+		err := client.Authenticate(context.Background(), BasicAuthorization(BasicAuth{Name: "jacknich", Password: "previous"}))
+		require.True(t, IsUnauthorized(err))
+	}
+}
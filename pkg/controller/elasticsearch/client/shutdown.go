@@ -0,0 +1,117 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ShutdownType is the type of a node shutdown.
+type ShutdownType string
+
+const (
+	// Restart indicates the node is about to be restarted, its shards are not moved to other nodes.
+	Restart ShutdownType = "restart"
+	// Remove indicates the node is about to be removed from the cluster, its shards are moved to other nodes.
+	Remove ShutdownType = "remove"
+)
+
+// ShutdownStatus is the status of a node shutdown.
+type ShutdownStatus string
+
+const (
+	ShutdownNotStarted ShutdownStatus = "NOT_STARTED"
+	ShutdownInProgress ShutdownStatus = "IN_PROGRESS"
+	ShutdownStalled    ShutdownStatus = "STALLED"
+	ShutdownComplete   ShutdownStatus = "COMPLETE"
+)
+
+// ShardMigration is the status of the migration of the shards of a node being shut down.
+type ShardMigration struct {
+	Status                   ShutdownStatus `json:"status"`
+	ShardMigrationsRemaining int            `json:"shard_migrations_remaining"`
+	Explanation              string         `json:"explanation"`
+}
+
+// PersistentTasks is the status of the migration of the persistent tasks of a node being shut down.
+type PersistentTasks struct {
+	Status ShutdownStatus `json:"status"`
+}
+
+// Plugins is the status of the plugins of a node being shut down.
+type Plugins struct {
+	Status ShutdownStatus `json:"status"`
+}
+
+// NodeShutdown models the shutdown status of a single node as returned by the /_nodes/shutdown API.
+type NodeShutdown struct {
+	NodeID                string          `json:"node_id"`
+	Type                  string          `json:"type"`
+	Reason                string          `json:"reason"`
+	ShutdownStartedMillis int64           `json:"shutdown_startedmillis"`
+	Status                ShutdownStatus  `json:"status"`
+	ShardMigration        ShardMigration  `json:"shard_migration"`
+	PersistentTasks       PersistentTasks `json:"persistent_tasks"`
+	Plugins               Plugins         `json:"plugins"`
+}
+
+// Is returns true if the shutdown is of the given type. The type is returned in upper case by Elasticsearch.
+func (ns NodeShutdown) Is(t ShutdownType) bool {
+	return strings.EqualFold(ns.Type, string(t))
+}
+
+// ShutdownResponse is the response of the /_nodes/shutdown API.
+type ShutdownResponse struct {
+	Nodes []NodeShutdown `json:"nodes"`
+}
+
+// ShutdownRequest is the body of a request to shut down a node.
+type ShutdownRequest struct {
+	Type   ShutdownType `json:"type"`
+	Reason string       `json:"reason"`
+}
+
+// ShutdownClient manages the shutdown of the nodes of a cluster, introduced in Elasticsearch 7.15.0.
+type ShutdownClient interface {
+	// GetShutdown returns the shutdown status of the node with the given ID, or of all the nodes being shut down if
+	// nodeID is nil.
+	GetShutdown(ctx context.Context, nodeID *string) (ShutdownResponse, error)
+	// PutShutdown registers the shutdown of the node with the given ID.
+	PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string) error
+	// DeleteShutdown removes the shutdown registration of the node with the given ID.
+	DeleteShutdown(ctx context.Context, nodeID string) error
+}
+
+func (c *clientV7) GetShutdown(ctx context.Context, nodeID *string) (ShutdownResponse, error) {
+	var response ShutdownResponse
+	path := "/_nodes/shutdown"
+	if nodeID != nil {
+		path = fmt.Sprintf("/_nodes/%s/shutdown", *nodeID)
+	}
+	err := c.get(ctx, path, &response)
+	return response, err
+}
+
+func (c *clientV7) PutShutdown(ctx context.Context, nodeID string, shutdownType ShutdownType, reason string) error {
+	request := ShutdownRequest{
+		Type:   shutdownType,
+		Reason: reason,
+	}
+	if err := c.put(ctx, fmt.Sprintf("/_nodes/%s/shutdown", nodeID), request, nil); err != nil {
+		return errors.Wrapf(err, "unable to request the shutdown of node %s", nodeID)
+	}
+	return nil
+}
+
+func (c *clientV7) DeleteShutdown(ctx context.Context, nodeID string) error {
+	if err := c.delete(ctx, fmt.Sprintf("/_nodes/%s/shutdown", nodeID), nil, nil); err != nil {
+		return errors.Wrapf(err, "unable to delete the shutdown of node %s", nodeID)
+	}
+	return nil
+}
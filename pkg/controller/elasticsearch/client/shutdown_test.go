@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleShutdown = `{
+  "nodes": [
+    {
+      "node_id": "txXw-Kd2Q6K0PbYMAPzH-Q",
+      "type": "RESTART",
+      "reason": "upgrade",
+      "shutdown_startedmillis": 1626264512052,
+      "status": "COMPLETE",
+      "shard_migration": {
+        "status": "COMPLETE",
+        "shard_migrations_remaining": 0,
+        "explanation": "no shard relocation is necessary for a node restart"
+      },
+      "persistent_tasks": {
+        "status": "COMPLETE"
+      },
+      "plugins": {
+        "status": "COMPLETE"
+      }
+    }
+  ]
+}`
+
+func TestClient_GetShutdown(t *testing.T) {
+	nodeID := "txXw-Kd2Q6K0PbYMAPzH-Q"
+	tests := []struct {
+		name         string
+		nodeID       *string
+		expectedPath string
+	}{
+		{
+			name:         "all nodes",
+			expectedPath: "/_nodes/shutdown",
+		},
+		{
+			name:         "single node",
+			nodeID:       &nodeID,
+			expectedPath: "/_nodes/txXw-Kd2Q6K0PbYMAPzH-Q/shutdown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+				require.Equal(t, http.MethodGet, req.Method)
+				require.Equal(t, tt.expectedPath, req.URL.Path)
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(strings.NewReader(sampleShutdown)),
+					Header:     make(http.Header),
+					Request:    req,
+				}
+			})
+			resp, err := client.GetShutdown(context.Background(), tt.nodeID)
+			require.NoError(t, err)
+			require.Len(t, resp.Nodes, 1)
+			require.Equal(t, nodeID, resp.Nodes[0].NodeID)
+			require.True(t, resp.Nodes[0].Is(Restart))
+			require.Equal(t, ShutdownComplete, resp.Nodes[0].Status)
+		})
+	}
+}
+
+func TestClient_PutShutdown(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_nodes/node-id/shutdown", req.URL.Path)
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type":"remove","reason":"downscale"}`, string(body))
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	require.NoError(t, client.PutShutdown(context.Background(), "node-id", Remove, "downscale"))
+}
+
+func TestClient_DeleteShutdown(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_nodes/node-id/shutdown", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	require.NoError(t, client.DeleteShutdown(context.Background(), "node-id"))
+}
+
+func TestClient_ShutdownNotSupportedInEs6x(t *testing.T) {
+	client := NewMockClient(version.MustParse("6.8.0"), func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected request to %s", req.URL.Path)
+		return nil
+	})
+	_, err := client.GetShutdown(context.Background(), nil)
+	require.Error(t, err)
+	require.Error(t, client.PutShutdown(context.Background(), "node-id", Restart, "upgrade"))
+	require.Error(t, client.DeleteShutdown(context.Background(), "node-id"))
+}
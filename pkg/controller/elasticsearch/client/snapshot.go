@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotRepository models a snapshot repository as returned by the /_snapshot API.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// SnapshotRepositories maps the name of the snapshot repositories to their definition.
+type SnapshotRepositories map[string]SnapshotRepository
+
+// SnapshotLifecyclePolicy models the definition of a snapshot lifecycle management policy.
+type SnapshotLifecyclePolicy struct {
+	Name       string                 `json:"name"`
+	Schedule   string                 `json:"schedule"`
+	Repository string                 `json:"repository"`
+	Config     map[string]interface{} `json:"config,omitempty"`
+	Retention  map[string]interface{} `json:"retention,omitempty"`
+}
+
+// SnapshotInvocation models a snapshot attempted by a snapshot lifecycle management policy.
+type SnapshotInvocation struct {
+	SnapshotName string `json:"snapshot_name"`
+	// Time is the time of the snapshot in milliseconds since epoch.
+	Time int64 `json:"time"`
+}
+
+// SnapshotLifecyclePolicyResult models a snapshot lifecycle management policy as returned by the /_slm/policy API.
+type SnapshotLifecyclePolicyResult struct {
+	Version     int64                   `json:"version"`
+	Policy      SnapshotLifecyclePolicy `json:"policy"`
+	LastSuccess *SnapshotInvocation     `json:"last_success,omitempty"`
+	LastFailure *SnapshotInvocation     `json:"last_failure,omitempty"`
+}
+
+// SnapshotLifecyclePolicies maps the name of the snapshot lifecycle management policies to their definition and status.
+type SnapshotLifecyclePolicies map[string]SnapshotLifecyclePolicyResult
+
+// SnapshotClient manages snapshot repositories and snapshot lifecycle management policies.
+type SnapshotClient interface {
+	// GetSnapshotRepositories returns all the snapshot repositories registered in the cluster.
+	GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error)
+	// PutSnapshotRepository registers or updates a snapshot repository.
+	PutSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error
+	// DeleteSnapshotRepository unregisters a snapshot repository. Existing snapshots are not deleted.
+	DeleteSnapshotRepository(ctx context.Context, name string) error
+	// GetSnapshotLifecyclePolicies returns all the snapshot lifecycle management policies of the cluster.
+	// Introduced in: Elasticsearch 7.4.0
+	GetSnapshotLifecyclePolicies(ctx context.Context) (SnapshotLifecyclePolicies, error)
+	// PutSnapshotLifecyclePolicy creates or updates a snapshot lifecycle management policy.
+	// Introduced in: Elasticsearch 7.4.0
+	PutSnapshotLifecyclePolicy(ctx context.Context, name string, policy SnapshotLifecyclePolicy) error
+	// DeleteSnapshotLifecyclePolicy deletes a snapshot lifecycle management policy.
+	// Introduced in: Elasticsearch 7.4.0
+	DeleteSnapshotLifecyclePolicy(ctx context.Context, name string) error
+}
+
+func (c *clientV6) GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error) {
+	var repositories SnapshotRepositories
+	err := c.get(ctx, "/_snapshot", &repositories)
+	return repositories, err
+}
+
+func (c *clientV6) PutSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error {
+	if err := c.put(ctx, fmt.Sprintf("/_snapshot/%s", name), repository, nil); err != nil {
+		return errors.Wrapf(err, "unable to register snapshot repository %s", name)
+	}
+	return nil
+}
+
+func (c *clientV6) DeleteSnapshotRepository(ctx context.Context, name string) error {
+	if err := c.delete(ctx, fmt.Sprintf("/_snapshot/%s", name), nil, nil); err != nil {
+		return errors.Wrapf(err, "unable to delete snapshot repository %s", name)
+	}
+	return nil
+}
+
+func (c *clientV7) GetSnapshotLifecyclePolicies(ctx context.Context) (SnapshotLifecyclePolicies, error) {
+	var policies SnapshotLifecyclePolicies
+	err := c.get(ctx, "/_slm/policy", &policies)
+	return policies, err
+}
+
+func (c *clientV7) PutSnapshotLifecyclePolicy(ctx context.Context, name string, policy SnapshotLifecyclePolicy) error {
+	if err := c.put(ctx, fmt.Sprintf("/_slm/policy/%s", name), policy, nil); err != nil {
+		return errors.Wrapf(err, "unable to put snapshot lifecycle policy %s", name)
+	}
+	return nil
+}
+
+func (c *clientV7) DeleteSnapshotLifecyclePolicy(ctx context.Context, name string) error {
+	if err := c.delete(ctx, fmt.Sprintf("/_slm/policy/%s", name), nil, nil); err != nil {
+		return errors.Wrapf(err, "unable to delete snapshot lifecycle policy %s", name)
+	}
+	return nil
+}
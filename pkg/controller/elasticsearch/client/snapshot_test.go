@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleSnapshotRepositories = `{
+  "my-repository": {
+    "type": "gcs",
+    "settings": {
+      "bucket": "my-bucket",
+      "client": "default"
+    }
+  }
+}`
+
+const sampleSnapshotLifecyclePolicies = `{
+  "daily-snapshots": {
+    "version": 1,
+    "modified_date_millis": 1626264512052,
+    "policy": {
+      "name": "<daily-snap-{now/d}>",
+      "schedule": "0 30 1 * * ?",
+      "repository": "my-repository",
+      "config": {
+        "indices": ["*"]
+      },
+      "retention": {
+        "expire_after": "30d"
+      }
+    },
+    "last_success": {
+      "snapshot_name": "daily-snap-2021.07.14-abcd",
+      "time": 1626264512052
+    },
+    "next_execution_millis": 1626312600000
+  }
+}`
+
+func TestClient_GetSnapshotRepositories(t *testing.T) {
+	client := NewMockClient(version.MustParse("6.8.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_snapshot", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(sampleSnapshotRepositories)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	repositories, err := client.GetSnapshotRepositories(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, SnapshotRepositories{
+		"my-repository": {
+			Type:     "gcs",
+			Settings: map[string]interface{}{"bucket": "my-bucket", "client": "default"},
+		},
+	}, repositories)
+}
+
+func TestClient_PutSnapshotRepository(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_snapshot/my-repository", req.URL.Path)
+		var repository SnapshotRepository
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&repository))
+		require.Equal(t, "fs", repository.Type)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	err := client.PutSnapshotRepository(context.Background(), "my-repository", SnapshotRepository{
+		Type:     "fs",
+		Settings: map[string]interface{}{"location": "/mnt/backups"},
+	})
+	require.NoError(t, err)
+}
+
+func TestClient_GetSnapshotLifecyclePolicies(t *testing.T) {
+	client := NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_slm/policy", req.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader(sampleSnapshotLifecyclePolicies)),
+			Header:     make(http.Header),
+			Request:    req,
+		}
+	})
+	policies, err := client.GetSnapshotLifecyclePolicies(context.Background())
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	policy := policies["daily-snapshots"]
+	require.Equal(t, "my-repository", policy.Policy.Repository)
+	require.Equal(t, "0 30 1 * * ?", policy.Policy.Schedule)
+	require.NotNil(t, policy.LastSuccess)
+	require.Equal(t, int64(1626264512052), policy.LastSuccess.Time)
+	require.Nil(t, policy.LastFailure)
+}
+
+func TestClient_SnapshotLifecyclePoliciesNotSupportedInEs6x(t *testing.T) {
+	client := NewMockClient(version.MustParse("6.8.0"), func(req *http.Request) *http.Response {
+		t.Fatalf("unexpected request %s %s", req.Method, req.URL.Path)
+		return nil
+	})
+	_, err := client.GetSnapshotLifecyclePolicies(context.Background())
+	require.Equal(t, errNotSupportedInEs6x, err)
+	require.Equal(t, errNotSupportedInEs6x, client.PutSnapshotLifecyclePolicy(context.Background(), "p", SnapshotLifecyclePolicy{}))
+	require.Equal(t, errNotSupportedInEs6x, client.DeleteSnapshotLifecyclePolicy(context.Background(), "p"))
+}
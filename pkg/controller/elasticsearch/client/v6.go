@@ -167,6 +167,54 @@ func (c *clientV6) UpdateMLNodesSettings(_ context.Context, _ int32, _ string) e
 	return errNotSupportedInEs6x
 }
 
+func (c *clientV6) GetShutdown(_ context.Context, _ *string) (ShutdownResponse, error) {
+	return ShutdownResponse{}, errNotSupportedInEs6x
+}
+
+func (c *clientV6) PutShutdown(_ context.Context, _ string, _ ShutdownType, _ string) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) DeleteShutdown(_ context.Context, _ string) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) GetComponentTemplate(_ context.Context, _ string) (ResourceDefinition, error) {
+	return nil, errNotSupportedInEs6x
+}
+
+func (c *clientV6) PutComponentTemplate(_ context.Context, _ string, _ ResourceDefinition) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) DeleteComponentTemplate(_ context.Context, _ string) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) GetIndexTemplate(_ context.Context, _ string) (ResourceDefinition, error) {
+	return nil, errNotSupportedInEs6x
+}
+
+func (c *clientV6) PutIndexTemplate(_ context.Context, _ string, _ ResourceDefinition) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) DeleteIndexTemplate(_ context.Context, _ string) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) GetSnapshotLifecyclePolicies(_ context.Context) (SnapshotLifecyclePolicies, error) {
+	return SnapshotLifecyclePolicies{}, errNotSupportedInEs6x
+}
+
+func (c *clientV6) PutSnapshotLifecyclePolicy(_ context.Context, _ string, _ SnapshotLifecyclePolicy) error {
+	return errNotSupportedInEs6x
+}
+
+func (c *clientV6) DeleteSnapshotLifecyclePolicy(_ context.Context, _ string) error {
+	return errNotSupportedInEs6x
+}
+
 func (c *clientV6) ClusterBootstrappedForZen2(ctx context.Context) (bool, error) {
 	// Look at the current master node of the cluster: if it's running version 7.x.x or above,
 	// the cluster has been bootstrapped.
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package clusterresources
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// ManagedResourcesAnnotationName holds the names of the cluster resources which have been created by the operator,
+// grouped by kind, along with the hash of the definition last applied to each of them.
+const ManagedResourcesAnnotationName = "elasticsearch.k8s.elastic.co/managed-cluster-resources"
+
+// managedResources maps the names of the resources to the hash of their last applied definition, per kind.
+type managedResources map[esv1.ClusterResourceKind]map[string]string
+
+func (m managedResources) add(kind esv1.ClusterResourceKind, name string, appliedHash string) {
+	if _, exists := m[kind]; !exists {
+		m[kind] = make(map[string]string)
+	}
+	m[kind][name] = appliedHash
+}
+
+func (m managedResources) remove(kind esv1.ClusterResourceKind, name string) {
+	delete(m[kind], name)
+	if len(m[kind]) == 0 {
+		delete(m, kind)
+	}
+}
+
+// appliedHash returns the hash of the definition last applied to the given resource, or an empty string if unknown.
+func (m managedResources) appliedHash(kind esv1.ClusterResourceKind, name string) string {
+	return m[kind][name]
+}
+
+// getManagedResourcesInAnnotation returns the resources listed in the annotation. An unparseable annotation is
+// considered empty. Resources listed without hash, as in the previous format of the annotation, have an unknown hash.
+func getManagedResourcesInAnnotation(es esv1.Elasticsearch) managedResources {
+	resources := make(managedResources)
+	serialized, exists := es.Annotations[ManagedResourcesAnnotationName]
+	if !exists {
+		return resources
+	}
+	var hashes map[esv1.ClusterResourceKind]map[string]string
+	if err := json.Unmarshal([]byte(serialized), &hashes); err == nil {
+		for kind, kindHashes := range hashes {
+			for name, appliedHash := range kindHashes {
+				resources.add(kind, name, appliedHash)
+			}
+		}
+		return resources
+	}
+	var names map[esv1.ClusterResourceKind][]string
+	if err := json.Unmarshal([]byte(serialized), &names); err != nil {
+		log.Error(err, "Ignoring invalid annotation", "annotation", ManagedResourcesAnnotationName, "namespace", es.Namespace, "es_name", es.Name)
+		return resources
+	}
+	for kind, kindNames := range names {
+		for _, name := range kindNames {
+			resources.add(kind, name, "")
+		}
+	}
+	return resources
+}
+
+// annotateWithManagedResources serializes the managed resources in the annotation, or removes the annotation if there
+// are none. The Elasticsearch resource is only updated if the annotation has changed.
+func annotateWithManagedResources(c k8s.Client, es esv1.Elasticsearch, resources managedResources) error {
+	if reflect.DeepEqual(getManagedResourcesInAnnotation(es), resources) {
+		return nil
+	}
+	// do not mutate the annotations of the caller
+	es = *es.DeepCopy()
+	if len(resources) == 0 {
+		delete(es.Annotations, ManagedResourcesAnnotationName)
+		return c.Update(context.Background(), &es)
+	}
+
+	// maps are serialized with sorted keys
+	serialized, err := json.Marshal(resources)
+	if err != nil {
+		return err
+	}
+	if es.Annotations == nil {
+		es.Annotations = make(map[string]string)
+	}
+	es.Annotations[ManagedResourcesAnnotationName] = string(serialized)
+	return c.Update(context.Background(), &es)
+}
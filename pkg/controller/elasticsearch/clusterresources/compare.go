@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package clusterresources
+
+import (
+	"fmt"
+	"strings"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+)
+
+// matches returns true if the definition of a resource in Elasticsearch is consistent with its expected definition.
+// Elasticsearch may return additional default values, index settings with an `index.` prefix and values as strings:
+// both definitions are flattened to dotted keys and string values, then the expected one is checked to be a subset of
+// the actual one.
+func matches(expected, actual esclient.ResourceDefinition) bool {
+	flatActual := flatten("", map[string]interface{}(actual))
+	for key, value := range flatten("", map[string]interface{}(expected)) {
+		actualValue, exists := flatActual[key]
+		if !exists {
+			actualValue, exists = flatActual[withIndexPrefix(key)]
+		}
+		if !exists || actualValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// flatten returns the given value as a flat map of dotted keys to their string value.
+// Arrays items are keyed by their index. Empty objects and arrays are kept as leaf values.
+func flatten(prefix string, value interface{}) map[string]string {
+	flattened := make(map[string]string)
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			flattened[prefix] = "{}"
+		}
+		for key, nested := range v {
+			for k, s := range flatten(join(key), nested) {
+				flattened[k] = s
+			}
+		}
+	case esclient.ResourceDefinition:
+		return flatten(prefix, map[string]interface{}(v))
+	case []interface{}:
+		if len(v) == 0 {
+			flattened[prefix] = "[]"
+		}
+		for i, nested := range v {
+			for k, s := range flatten(join(fmt.Sprintf("%d", i)), nested) {
+				flattened[k] = s
+			}
+		}
+	default:
+		flattened[prefix] = fmt.Sprintf("%v", v)
+	}
+	return flattened
+}
+
+// withIndexPrefix returns the given key with an `index.` prefix inserted after its `settings` path element, which
+// Elasticsearch adds to the index settings that do not have it.
+func withIndexPrefix(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if part == "settings" && i+1 < len(parts) && parts[i+1] != "index" {
+			return strings.Join(append(parts[:i+1:i+1], append([]string{"index"}, parts[i+1:]...)...), ".")
+		}
+	}
+	return key
+}
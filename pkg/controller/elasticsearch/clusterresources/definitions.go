@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package clusterresources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// DefinitionsWatchName returns the name of the watches on the ConfigMaps and Secrets referenced in the definitions
+// of the cluster resources.
+func DefinitionsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-cluster-resources-definitions", es.Namespace, es.Name)
+}
+
+// watchDefinitions ensures that the ConfigMaps and Secrets referenced in the definitions of the cluster resources
+// are watched for future reconciliations to be triggered on any change.
+func watchDefinitions(es esv1.Elasticsearch, watched watches.DynamicWatches) error {
+	esKey := k8s.ExtractNamespacedName(&es)
+	watchName := DefinitionsWatchName(esKey)
+
+	var configMaps []types.NamespacedName
+	var secretNames []string
+	for _, resources := range es.Spec.ClusterResources.ByKind() {
+		for _, resource := range resources {
+			if resource.DefinitionRef == nil {
+				continue
+			}
+			if resource.DefinitionRef.ConfigMapName != "" {
+				configMaps = append(configMaps, types.NamespacedName{Namespace: es.Namespace, Name: resource.DefinitionRef.ConfigMapName})
+			}
+			if resource.DefinitionRef.SecretName != "" {
+				secretNames = append(secretNames, resource.DefinitionRef.SecretName)
+			}
+		}
+	}
+
+	if err := watches.WatchUserProvidedSecrets(esKey, watched, watchName, secretNames); err != nil {
+		return err
+	}
+	if len(configMaps) == 0 {
+		watched.ConfigMaps.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	return watched.ConfigMaps.AddHandler(watches.NamedWatch{
+		Name:    watchName,
+		Watched: configMaps,
+		Watcher: esKey,
+	})
+}
+
+// getDefinition returns the definition of the resource, either inline or read from the referenced ConfigMap or Secret.
+func getDefinition(c k8s.Client, namespace string, resource esv1.ClusterResource) (esclient.ResourceDefinition, error) {
+	if resource.DefinitionRef == nil {
+		if resource.Definition == nil {
+			return esclient.ResourceDefinition{}, nil
+		}
+		// round-trip through JSON to work with the same types as the ones returned by Elasticsearch
+		return parseDefinition(resource.Definition)
+	}
+
+	ref := resource.DefinitionRef
+	var data []byte
+	var exists bool
+	switch {
+	case ref.ConfigMapName != "":
+		var configMap corev1.ConfigMap
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: ref.ConfigMapName}, &configMap); err != nil {
+			return nil, err
+		}
+		var content string
+		content, exists = configMap.Data[ref.Key]
+		data = []byte(content)
+	case ref.SecretName != "":
+		var secret corev1.Secret
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: ref.SecretName}, &secret); err != nil {
+			return nil, err
+		}
+		data, exists = secret.Data[ref.Key]
+	default:
+		return nil, fmt.Errorf("definition reference of %s must specify a ConfigMap or a Secret", resource.Name)
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s not found in the definition reference of %s", ref.Key, resource.Name)
+	}
+
+	var definition esclient.ResourceDefinition
+	if err := yaml.Unmarshal(data, &definition); err != nil {
+		return nil, fmt.Errorf("invalid definition for %s: %w", resource.Name, err)
+	}
+	return definition, nil
+}
+
+func parseDefinition(in interface{}) (esclient.ResourceDefinition, error) {
+	bytes, err := yaml.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var definition esclient.ResourceDefinition
+	err = yaml.Unmarshal(bytes, &definition)
+	return definition, err
+}
@@ -0,0 +1,175 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package clusterresources
+
+import (
+	"context"
+	"sort"
+
+	"go.elastic.co/apm"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
+)
+
+var log = ulog.Log.WithName("cluster-resources")
+
+// creationOrder is the order in which the kinds of resources are created, so that the resources referenced by
+// others exist first. Resources are deleted in the reverse order.
+var creationOrder = []esv1.ClusterResourceKind{
+	esv1.IndexLifecyclePolicyKind,
+	esv1.ComponentTemplateKind,
+	esv1.IngestPipelineKind,
+	esv1.IndexTemplateKind,
+}
+
+// resourceAPI groups the Elasticsearch API calls used to manage a kind of resources.
+type resourceAPI struct {
+	get    func(ctx context.Context, name string) (esclient.ResourceDefinition, error)
+	put    func(ctx context.Context, name string, definition esclient.ResourceDefinition) error
+	delete func(ctx context.Context, name string) error
+}
+
+func apiFor(esClient esclient.Client, kind esv1.ClusterResourceKind) resourceAPI {
+	switch kind {
+	case esv1.IndexLifecyclePolicyKind:
+		return resourceAPI{get: esClient.GetIndexLifecyclePolicy, put: esClient.PutIndexLifecyclePolicy, delete: esClient.DeleteIndexLifecyclePolicy}
+	case esv1.ComponentTemplateKind:
+		return resourceAPI{get: esClient.GetComponentTemplate, put: esClient.PutComponentTemplate, delete: esClient.DeleteComponentTemplate}
+	case esv1.IndexTemplateKind:
+		return resourceAPI{get: esClient.GetIndexTemplate, put: esClient.PutIndexTemplate, delete: esClient.DeleteIndexTemplate}
+	default:
+		return resourceAPI{get: esClient.GetIngestPipeline, put: esClient.PutIngestPipeline, delete: esClient.DeleteIngestPipeline}
+	}
+}
+
+// Reconcile creates or updates in Elasticsearch the index lifecycle management policies, component and index
+// templates and ingest pipelines declared in the specification. Resources which differ from their definition, for
+// example because they have been modified through the Elasticsearch API, are updated.
+// Resources previously created by the operator but removed from the specification are deleted from Elasticsearch
+// only if pruning is enabled. Resources created by other means are left untouched.
+// It returns the status of each declared resource, and an aggregated error if any resource could not be reconciled.
+func Reconcile(
+	ctx context.Context,
+	c k8s.Client,
+	esClient esclient.Client,
+	watched watches.DynamicWatches,
+	es esv1.Elasticsearch,
+) ([]esv1.ClusterResourceStatus, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_cluster_resources", tracing.SpanTypeApp)
+	defer span.End()
+
+	if err := watchDefinitions(es, watched); err != nil {
+		return nil, err
+	}
+
+	spec := es.Spec.ClusterResources
+	managed := getManagedResourcesInAnnotation(es)
+	if spec.IsEmpty() && len(managed) == 0 {
+		return nil, nil
+	}
+
+	var errs []error
+	var statuses []esv1.ClusterResourceStatus
+	byKind := spec.ByKind()
+	for _, kind := range creationOrder {
+		api := apiFor(esClient, kind)
+		for _, resource := range byKind[kind] {
+			status, appliedHash, err := reconcileResource(c, api, es, kind, resource, managed.appliedHash(kind, resource.Name))
+			if err != nil {
+				errs = append(errs, err)
+			}
+			statuses = append(statuses, status)
+			managed.add(kind, resource.Name, appliedHash)
+		}
+	}
+
+	for i := len(creationOrder) - 1; i >= 0; i-- {
+		kind := creationOrder[i]
+		api := apiFor(esClient, kind)
+		for _, name := range removedResources(managed, byKind, kind) {
+			if !spec.Prune {
+				// stop tracking the resource, it is left untouched in Elasticsearch
+				managed.remove(kind, name)
+				continue
+			}
+			log.Info("Deleting cluster resource", "namespace", es.Namespace, "es_name", es.Name, "kind", kind, "name", name)
+			if err := api.delete(context.Background(), name); err != nil && !esclient.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			}
+			managed.remove(kind, name)
+		}
+	}
+
+	if err := annotateWithManagedResources(c, es, managed); err != nil {
+		errs = append(errs, err)
+	}
+	return statuses, utilerrors.NewAggregate(errs)
+}
+
+// reconcileResource creates or updates a resource in Elasticsearch if its definition has changed since it was last
+// applied, or if it does not match its definition anymore. It returns the hash of the definition applied to the
+// resource, which is the given previously applied hash if the resource could not be updated.
+func reconcileResource(
+	c k8s.Client,
+	api resourceAPI,
+	es esv1.Elasticsearch,
+	kind esv1.ClusterResourceKind,
+	resource esv1.ClusterResource,
+	appliedHash string,
+) (esv1.ClusterResourceStatus, string, error) {
+	status := esv1.ClusterResourceStatus{Kind: kind, Name: resource.Name, Phase: esv1.ClusterResourceAppliedPhase}
+
+	expected, err := getDefinition(c, es.Namespace, resource)
+	if err != nil {
+		status.Phase = esv1.ClusterResourceInvalidPhase
+		status.Message = err.Error()
+		return status, appliedHash, err
+	}
+	expectedHash := hash.HashObject(expected)
+
+	actual, err := api.get(context.Background(), resource.Name)
+	if err != nil && !esclient.IsNotFound(err) {
+		status.Phase = esv1.ClusterResourceFailedPhase
+		status.Message = err.Error()
+		return status, appliedHash, err
+	}
+	// Elasticsearch adds default values to the definitions, which can only be compared as a subset of the actual
+	// ones: fields removed from the definition are detected through the hash of the last applied definition
+	if err == nil && expectedHash == appliedHash && matches(expected, actual) {
+		return status, appliedHash, nil
+	}
+
+	log.Info("Updating cluster resource", "namespace", es.Namespace, "es_name", es.Name, "kind", kind, "name", resource.Name)
+	if err := api.put(context.Background(), resource.Name, expected); err != nil {
+		status.Phase = esv1.ClusterResourceFailedPhase
+		status.Message = err.Error()
+		return status, appliedHash, err
+	}
+	return status, expectedHash, nil
+}
+
+// removedResources returns the sorted names of the managed resources of the given kind which are not in the spec anymore.
+func removedResources(managed managedResources, inSpec map[esv1.ClusterResourceKind][]esv1.ClusterResource, kind esv1.ClusterResourceKind) []string {
+	declared := make(map[string]struct{}, len(inSpec[kind]))
+	for _, resource := range inSpec[kind] {
+		declared[resource.Name] = struct{}{}
+	}
+	var removed []string
+	for name := range managed[kind] {
+		if _, exists := declared[name]; !exists {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
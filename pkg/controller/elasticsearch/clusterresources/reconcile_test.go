@@ -0,0 +1,297 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package clusterresources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// errNotFound is an Elasticsearch API error for a resource which does not exist.
+var errNotFound = func() error {
+	_, err := esclient.NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		return esclient.NewMockResponse(404, req, "{}")
+	}).GetIngestPipeline(context.Background(), "missing")
+	return err
+}()
+
+// fakeESClient stores the ingest pipelines and index templates in memory.
+type fakeESClient struct {
+	esclient.Client
+	pipelines map[string]esclient.ResourceDefinition
+	templates map[string]esclient.ResourceDefinition
+	puts      []string
+	deletes   []string
+}
+
+func (f *fakeESClient) GetIngestPipeline(_ context.Context, name string) (esclient.ResourceDefinition, error) {
+	if pipeline, exists := f.pipelines[name]; exists {
+		return pipeline, nil
+	}
+	return nil, errNotFound
+}
+
+func (f *fakeESClient) PutIngestPipeline(_ context.Context, name string, definition esclient.ResourceDefinition) error {
+	f.puts = append(f.puts, "pipeline/"+name)
+	f.pipelines[name] = definition
+	return nil
+}
+
+func (f *fakeESClient) DeleteIngestPipeline(_ context.Context, name string) error {
+	f.deletes = append(f.deletes, "pipeline/"+name)
+	delete(f.pipelines, name)
+	return nil
+}
+
+func (f *fakeESClient) GetIndexTemplate(_ context.Context, name string) (esclient.ResourceDefinition, error) {
+	if template, exists := f.templates[name]; exists {
+		return template, nil
+	}
+	return nil, errNotFound
+}
+
+func (f *fakeESClient) PutIndexTemplate(_ context.Context, name string, definition esclient.ResourceDefinition) error {
+	f.puts = append(f.puts, "template/"+name)
+	f.templates[name] = definition
+	return nil
+}
+
+func (f *fakeESClient) DeleteIndexTemplate(_ context.Context, name string) error {
+	f.deletes = append(f.deletes, "template/"+name)
+	delete(f.templates, name)
+	return nil
+}
+
+func Test_matches(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected esclient.ResourceDefinition
+		actual   esclient.ResourceDefinition
+		want     bool
+	}{
+		{
+			name:     "identical",
+			expected: esclient.ResourceDefinition{"description": "my pipeline", "processors": []interface{}{map[string]interface{}{"set": map[string]interface{}{"field": "a", "value": "b"}}}},
+			actual:   esclient.ResourceDefinition{"description": "my pipeline", "processors": []interface{}{map[string]interface{}{"set": map[string]interface{}{"field": "a", "value": "b"}}}},
+			want:     true,
+		},
+		{
+			name: "defaults, index prefix and string values added by Elasticsearch",
+			expected: esclient.ResourceDefinition{
+				"index_patterns": []interface{}{"logs-*"},
+				"template": map[string]interface{}{
+					"settings": map[string]interface{}{"number_of_shards": float64(1)},
+				},
+			},
+			actual: esclient.ResourceDefinition{
+				"index_patterns": []interface{}{"logs-*"},
+				"composed_of":    []interface{}{},
+				"template": map[string]interface{}{
+					"settings": map[string]interface{}{"index": map[string]interface{}{"number_of_shards": "1"}},
+				},
+			},
+			want: true,
+		},
+		{
+			name:     "modified in Elasticsearch",
+			expected: esclient.ResourceDefinition{"index_patterns": []interface{}{"logs-*"}},
+			actual:   esclient.ResourceDefinition{"index_patterns": []interface{}{"metrics-*"}},
+			want:     false,
+		},
+		{
+			name:     "missing array item",
+			expected: esclient.ResourceDefinition{"index_patterns": []interface{}{"logs-*", "metrics-*"}},
+			actual:   esclient.ResourceDefinition{"index_patterns": []interface{}{"logs-*"}},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, matches(tt.expected, tt.actual))
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	pipeline := esv1.ClusterResource{
+		Name:       "my-pipeline",
+		Definition: &commonv1.Config{Data: map[string]interface{}{"description": "my pipeline"}},
+	}
+	template := esv1.ClusterResource{
+		Name:          "my-template",
+		DefinitionRef: &esv1.ClusterResourceDefinitionSource{ConfigMapName: "templates", Key: "my-template.yml"},
+	}
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "templates"},
+		Data:       map[string]string{"my-template.yml": "index_patterns: [\"logs-*\"]\npriority: 100\n"},
+	}
+	definitionHash := func(resource esv1.ClusterResource) string {
+		definition, err := getDefinition(k8s.NewFakeClient(&configMap), "ns", resource)
+		require.NoError(t, err)
+		return hash.HashObject(definition)
+	}
+	// annotation listing the pipeline and the template applied with their current definition
+	appliedAnnotation := fmt.Sprintf(`{"IndexTemplate":{"my-template":"%s"},"IngestPipeline":{"my-pipeline":"%s"}}`,
+		definitionHash(template), definitionHash(pipeline))
+	newES := func(annotations map[string]string, spec esv1.ClusterResourcesSpec) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: annotations},
+			Spec:       esv1.ElasticsearchSpec{Version: "7.15.0", ClusterResources: spec},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		es             esv1.Elasticsearch
+		esClient       *fakeESClient
+		wantStatuses   []esv1.ClusterResourceStatus
+		wantErr        bool
+		wantPuts       []string
+		wantDeletes    []string
+		wantAnnotation string
+	}{
+		{
+			name:     "nothing to reconcile",
+			es:       newES(nil, esv1.ClusterResourcesSpec{}),
+			esClient: &fakeESClient{},
+		},
+		{
+			name:     "create resources",
+			es:       newES(nil, esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{pipeline}, IndexTemplates: []esv1.ClusterResource{template}}),
+			esClient: &fakeESClient{pipelines: map[string]esclient.ResourceDefinition{}, templates: map[string]esclient.ResourceDefinition{}},
+			wantStatuses: []esv1.ClusterResourceStatus{
+				{Kind: esv1.IngestPipelineKind, Name: "my-pipeline", Phase: esv1.ClusterResourceAppliedPhase},
+				{Kind: esv1.IndexTemplateKind, Name: "my-template", Phase: esv1.ClusterResourceAppliedPhase},
+			},
+			wantPuts:       []string{"pipeline/my-pipeline", "template/my-template"},
+			wantAnnotation: appliedAnnotation,
+		},
+		{
+			name: "update drifted resources only",
+			es: newES(
+				map[string]string{ManagedResourcesAnnotationName: appliedAnnotation},
+				esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{pipeline}, IndexTemplates: []esv1.ClusterResource{template}},
+			),
+			esClient: &fakeESClient{
+				pipelines: map[string]esclient.ResourceDefinition{"my-pipeline": {"description": "my pipeline", "version": float64(1)}},
+				templates: map[string]esclient.ResourceDefinition{"my-template": {"index_patterns": []interface{}{"logs-*"}, "priority": float64(1)}},
+			},
+			wantStatuses: []esv1.ClusterResourceStatus{
+				{Kind: esv1.IngestPipelineKind, Name: "my-pipeline", Phase: esv1.ClusterResourceAppliedPhase},
+				{Kind: esv1.IndexTemplateKind, Name: "my-template", Phase: esv1.ClusterResourceAppliedPhase},
+			},
+			wantPuts:       []string{"template/my-template"},
+			wantAnnotation: appliedAnnotation,
+		},
+		{
+			name: "update resources whose definition changed",
+			es: newES(
+				map[string]string{ManagedResourcesAnnotationName: fmt.Sprintf(
+					`{"IndexTemplate":{"my-template":"%s"},"IngestPipeline":{"my-pipeline":"1234"}}`, definitionHash(template),
+				)},
+				esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{pipeline}, IndexTemplates: []esv1.ClusterResource{template}},
+			),
+			esClient: &fakeESClient{
+				// the processors have been removed from the definition but are still a superset of it
+				pipelines: map[string]esclient.ResourceDefinition{"my-pipeline": {"description": "my pipeline", "processors": []interface{}{}}},
+				templates: map[string]esclient.ResourceDefinition{"my-template": {"index_patterns": []interface{}{"logs-*"}, "priority": float64(100)}},
+			},
+			wantStatuses: []esv1.ClusterResourceStatus{
+				{Kind: esv1.IngestPipelineKind, Name: "my-pipeline", Phase: esv1.ClusterResourceAppliedPhase},
+				{Kind: esv1.IndexTemplateKind, Name: "my-template", Phase: esv1.ClusterResourceAppliedPhase},
+			},
+			wantPuts:       []string{"pipeline/my-pipeline"},
+			wantAnnotation: appliedAnnotation,
+		},
+		{
+			name: "update resources listed without hash",
+			es: newES(
+				map[string]string{ManagedResourcesAnnotationName: `{"IndexTemplate":["my-template"],"IngestPipeline":["my-pipeline"]}`},
+				esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{pipeline}, IndexTemplates: []esv1.ClusterResource{template}},
+			),
+			esClient: &fakeESClient{
+				pipelines: map[string]esclient.ResourceDefinition{"my-pipeline": {"description": "my pipeline"}},
+				templates: map[string]esclient.ResourceDefinition{"my-template": {"index_patterns": []interface{}{"logs-*"}, "priority": float64(100)}},
+			},
+			wantStatuses: []esv1.ClusterResourceStatus{
+				{Kind: esv1.IngestPipelineKind, Name: "my-pipeline", Phase: esv1.ClusterResourceAppliedPhase},
+				{Kind: esv1.IndexTemplateKind, Name: "my-template", Phase: esv1.ClusterResourceAppliedPhase},
+			},
+			wantPuts:       []string{"pipeline/my-pipeline", "template/my-template"},
+			wantAnnotation: appliedAnnotation,
+		},
+		{
+			name: "removed resources are left untouched without pruning",
+			es: newES(
+				map[string]string{ManagedResourcesAnnotationName: appliedAnnotation},
+				esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{pipeline}},
+			),
+			esClient: &fakeESClient{
+				pipelines: map[string]esclient.ResourceDefinition{"my-pipeline": {"description": "my pipeline"}},
+				templates: map[string]esclient.ResourceDefinition{"my-template": {"index_patterns": []interface{}{"logs-*"}}},
+			},
+			wantStatuses: []esv1.ClusterResourceStatus{
+				{Kind: esv1.IngestPipelineKind, Name: "my-pipeline", Phase: esv1.ClusterResourceAppliedPhase},
+			},
+			wantAnnotation: fmt.Sprintf(`{"IngestPipeline":{"my-pipeline":"%s"}}`, definitionHash(pipeline)),
+		},
+		{
+			name: "removed resources are deleted with pruning",
+			es: newES(
+				map[string]string{ManagedResourcesAnnotationName: appliedAnnotation},
+				esv1.ClusterResourcesSpec{Prune: true},
+			),
+			esClient: &fakeESClient{
+				pipelines: map[string]esclient.ResourceDefinition{"my-pipeline": {"description": "my pipeline"}},
+				templates: map[string]esclient.ResourceDefinition{"my-template": {"index_patterns": []interface{}{"logs-*"}}},
+			},
+			wantDeletes: []string{"template/my-template", "pipeline/my-pipeline"},
+		},
+		{
+			name: "invalid definition reference",
+			es: newES(nil, esv1.ClusterResourcesSpec{IndexTemplates: []esv1.ClusterResource{{
+				Name:          "other-template",
+				DefinitionRef: &esv1.ClusterResourceDefinitionSource{ConfigMapName: "templates", Key: "missing.yml"},
+			}}}),
+			esClient: &fakeESClient{templates: map[string]esclient.ResourceDefinition{}},
+			wantStatuses: []esv1.ClusterResourceStatus{{
+				Kind:    esv1.IndexTemplateKind,
+				Name:    "other-template",
+				Phase:   esv1.ClusterResourceInvalidPhase,
+				Message: "key missing.yml not found in the definition reference of other-template",
+			}},
+			wantErr:        true,
+			wantAnnotation: `{"IndexTemplate":{"other-template":""}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(&tt.es, &configMap)
+			statuses, err := Reconcile(context.Background(), c, tt.esClient, watches.NewDynamicWatches(), tt.es)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantStatuses, statuses)
+			require.Equal(t, tt.wantPuts, tt.esClient.puts)
+			require.Equal(t, tt.wantDeletes, tt.esClient.deletes)
+
+			var es esv1.Elasticsearch
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &es))
+			require.Equal(t, tt.wantAnnotation, es.Annotations[ManagedResourcesAnnotationName])
+		})
+	}
+}
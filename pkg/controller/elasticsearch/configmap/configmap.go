@@ -46,9 +46,10 @@ func ReconcileScriptsConfigMap(ctx context.Context, c k8s.Client, es esv1.Elasti
 	scriptsConfigMap := NewConfigMapWithData(
 		types.NamespacedName{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)},
 		map[string]string{
-			nodespec.ReadinessProbeScriptConfigKey: nodespec.ReadinessProbeScript,
-			nodespec.PreStopHookScriptConfigKey:    nodespec.PreStopHookScript,
-			initcontainer.PrepareFsScriptConfigKey: fsScript,
+			nodespec.ReadinessProbeScriptConfigKey:     nodespec.ReadinessProbeScript,
+			nodespec.PreStopHookScriptConfigKey:        nodespec.PreStopHookScript,
+			initcontainer.PrepareFsScriptConfigKey:     fsScript,
+			initcontainer.ZoneAwarenessScriptConfigKey: initcontainer.ZoneAwarenessScript,
 		},
 	)
 
@@ -6,6 +6,7 @@ package driver
 
 import (
 	"context"
+	"fmt"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
@@ -13,9 +14,9 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/transport"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
-	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/migration"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/version/zen1"
@@ -50,9 +51,9 @@ func HandleDownscale(
 	}
 
 	// migrate data away from nodes that should be removed
-	// if leavingNodes is empty, it clears any existing settings
+	// if leavingNodes is empty, it clears any existing settings or shutdown registrations
 	leavingNodes := leavingNodeNames(downscales)
-	if err := migration.MigrateData(downscaleCtx.parentCtx, downscaleCtx.es, downscaleCtx.esClient, leavingNodes); err != nil {
+	if err := downscaleCtx.nodeShutdown.ReconcileShutdowns(downscaleCtx.parentCtx, leavingNodes); err != nil {
 		return results.WithError(err)
 	}
 
@@ -64,7 +65,7 @@ func HandleDownscale(
 		}
 		if requeue {
 			// retry downscaling this statefulset later
-			results.WithResult(defaultRequeue)
+			results.WithIncompleteReconciliation(defaultRequeue, "Downscale in progress")
 		}
 	}
 
@@ -150,7 +151,7 @@ func attemptDownscale(
 }
 
 // deleteStatefulSetResources deletes the given StatefulSet along with the corresponding
-// headless service, configuration and transport certificates secret.
+// headless service, HTTP service, configuration and transport certificates secret.
 func deleteStatefulSetResources(k8sClient k8s.Client, es esv1.Elasticsearch, statefulSet appsv1.StatefulSet) error {
 	headlessSvc := nodespec.HeadlessService(&es, statefulSet.Name)
 	err := k8sClient.Delete(context.Background(), &headlessSvc)
@@ -158,6 +159,11 @@ func deleteStatefulSetResources(k8sClient k8s.Client, es esv1.Elasticsearch, sta
 		return err
 	}
 
+	err = services.DeleteStatefulSetHTTPService(k8sClient, es.Namespace, statefulSet.Name)
+	if err != nil {
+		return err
+	}
+
 	err = settings.DeleteConfig(k8sClient, es.Namespace, statefulSet.Name)
 	if err != nil && !apierrors.IsNotFound(err) {
 		return err
@@ -188,13 +194,21 @@ func calculatePerformableDownscale(
 	}
 	// iterate on all leaving nodes (ordered by highest ordinal first)
 	for _, node := range downscale.leavingNodeNames() {
-		migrating, err := migration.NodeMayHaveShard(ctx.parentCtx, ctx.es, ctx.shardLister, node)
+		response, err := ctx.nodeShutdown.ShutdownStatus(ctx.parentCtx, node)
 		if err != nil {
 			return performableDownscale, err
 		}
-		if migrating {
-			ssetLogger(downscale.statefulSet).V(1).Info("Data migration not over yet, skipping node deletion", "node", node)
+		if response.Status != esclient.ShutdownComplete {
+			ssetLogger(downscale.statefulSet).V(1).Info("Data migration not over yet, skipping node deletion",
+				"node", node, "status", response.Status, "explanation", response.Explanation)
 			ctx.reconcileState.UpdateElasticsearchMigrating(ctx.resourcesState, ctx.observedState)
+			if response.Status == esclient.ShutdownStalled {
+				ctx.reconcileState.AddEvent(
+					v1.EventTypeWarning,
+					events.EventReasonUnexpected,
+					fmt.Sprintf("Shutdown of node %s stalled: %s", node, response.Explanation),
+				)
+			}
 			// no need to check other nodes since we remove them in order and this one isn't ready anyway
 			return performableDownscale, nil
 		}
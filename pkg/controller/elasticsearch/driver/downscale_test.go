@@ -20,6 +20,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/shutdown"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/pointer"
@@ -147,11 +148,11 @@ func TestHandleDownscale(t *testing.T) {
 		k8sClient:      k8sClient,
 		expectations:   expectations.NewExpectations(k8sClient),
 		reconcileState: reconcile.NewState(esv1.Elasticsearch{}),
-		shardLister: migration.NewFakeShardLister(
+		nodeShutdown: migration.NewShardMigration(es, esClient, migration.NewFakeShardLister(
 			esclient.Shards{
 				{Index: "index-1", Shard: "0", State: esclient.STARTED, NodeName: "ssetData4Replicas-2"},
 			},
-		),
+		)),
 		esClient:  esClient,
 		es:        es,
 		parentCtx: context.Background(),
@@ -225,11 +226,11 @@ func TestHandleDownscale(t *testing.T) {
 	require.NoError(t, k8sClient.Delete(context.Background(), &podsSsetMaster3Replicas[1]))
 
 	// once data migration is over the downscale should continue for next data nodes
-	downscaleCtx.shardLister = migration.NewFakeShardLister(
+	downscaleCtx.nodeShutdown = migration.NewShardMigration(es, esClient, migration.NewFakeShardLister(
 		esclient.Shards{
 			{Index: "index-1", Shard: "0", State: esclient.STARTED, NodeName: "ssetData4Replicas-1"},
 		},
-	)
+	))
 	nodespec.UpdateReplicas(&expectedAfterDownscale[0], pointer.Int32(2))
 	results = HandleDownscale(downscaleCtx, requestedStatefulSets, actual.Items)
 	require.False(t, results.HasError())
@@ -613,7 +614,7 @@ func Test_calculatePerformableDownscale(t *testing.T) {
 			name: "downscale possible from 3 to 2",
 			args: args{
 				ctx: downscaleContext{
-					shardLister: migration.NewFakeShardLister(esclient.Shards{}),
+					nodeShutdown: migration.NewShardMigration(esv1.Elasticsearch{}, &fakeESClient{}, migration.NewFakeShardLister(esclient.Shards{})),
 				},
 				downscale: ssetDownscale{
 					initialReplicas: 3,
@@ -632,13 +633,13 @@ func Test_calculatePerformableDownscale(t *testing.T) {
 			args: args{
 				ctx: downscaleContext{
 					reconcileState: reconcile.NewState(esv1.Elasticsearch{}),
-					shardLister: migration.NewFakeShardLister(esclient.Shards{
+					nodeShutdown: migration.NewShardMigration(esv1.Elasticsearch{}, &fakeESClient{}, migration.NewFakeShardLister(esclient.Shards{
 						{
 							Index:    "index-1",
 							Shard:    "0",
 							NodeName: "default-2",
 						},
-					}),
+					})),
 				},
 				downscale: ssetDownscale{
 					statefulSet:     sset.TestSset{Name: "default"}.Build(),
@@ -654,6 +655,32 @@ func Test_calculatePerformableDownscale(t *testing.T) {
 				finalReplicas:   2,
 			},
 		},
+		{
+			name: "downscale possible: leaving node not a member of the cluster",
+			args: args{
+				ctx: downscaleContext{
+					nodeShutdown: shutdown.NewNodeShutdown(&fakeESClient{
+						nodes: esclient.Nodes{Nodes: map[string]esclient.Node{
+							"id-0": {Name: "default-0"},
+							"id-1": {Name: "default-1"},
+						}},
+						shards: esclient.Shards{{Index: "index-1", Shard: "0", NodeName: "default-0"}},
+					}, esclient.Remove, "downscale", log),
+				},
+				downscale: ssetDownscale{
+					statefulSet:     sset.TestSset{Name: "default"}.Build(),
+					initialReplicas: 3,
+					targetReplicas:  2,
+					finalReplicas:   2,
+				},
+			},
+			want: ssetDownscale{
+				statefulSet:     sset.TestSset{Name: "default"}.Build(),
+				initialReplicas: 3,
+				targetReplicas:  2,
+				finalReplicas:   2,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -719,7 +746,7 @@ func Test_attemptDownscale(t *testing.T) {
 				k8sClient:      k8sClient,
 				expectations:   expectations.NewExpectations(k8sClient),
 				reconcileState: reconcile.NewState(esv1.Elasticsearch{}),
-				shardLister:    migration.NewFakeShardLister(esclient.Shards{}),
+				nodeShutdown:   migration.NewShardMigration(esv1.Elasticsearch{}, &fakeESClient{}, migration.NewFakeShardLister(esclient.Shards{})),
 				esClient:       &fakeESClient{},
 			}
 			// do the downscale
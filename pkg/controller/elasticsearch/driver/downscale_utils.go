@@ -10,8 +10,10 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/expectations"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/migration"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/observer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/shutdown"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	appsv1 "k8s.io/api/apps/v1"
@@ -21,9 +23,9 @@ import (
 // propagated from the main driver.
 type downscaleContext struct {
 	// clients
-	k8sClient   k8s.Client
-	esClient    esclient.Client
-	shardLister esclient.ShardLister
+	k8sClient    k8s.Client
+	esClient     esclient.Client
+	nodeShutdown shutdown.Interface
 	// driver states
 	resourcesState reconcile.ResourcesState
 	observedState  observer.State
@@ -46,10 +48,16 @@ func newDownscaleContext(
 	// ES cluster
 	es esv1.Elasticsearch,
 ) downscaleContext {
+	// use the node shutdown API if supported, fallback to data migration through allocation filtering otherwise
+	nodeShutdown := migration.NewShardMigration(es, esClient, esClient)
+	if supportsNodeShutdown(esClient.Version()) {
+		logger := log.WithValues("namespace", es.Namespace, "es_name", es.Name)
+		nodeShutdown = shutdown.NewNodeShutdown(esClient, esclient.Remove, downscaleShutdownReason, logger)
+	}
 	return downscaleContext{
 		k8sClient:      k8sClient,
 		esClient:       esClient,
-		shardLister:    esClient,
+		nodeShutdown:   nodeShutdown,
 		resourcesState: resourcesState,
 		observedState:  observedState,
 		reconcileState: reconcileState,
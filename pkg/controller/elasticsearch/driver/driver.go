@@ -15,6 +15,7 @@ import (
 	controller "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	commondriver "github.com/elastic/cloud-on-k8s/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
@@ -29,6 +30,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/cleanup"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/clusterresources"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/configmap"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
@@ -38,6 +40,8 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/remotecluster"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/snapshots"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/stackmon"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
@@ -130,22 +134,44 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results.WithError(err)
 	}
 
+	statefulSetHTTPServices, err := services.ReconcileStatefulSetHTTPServices(ctx, d.Client, d.ES)
+	if err != nil {
+		return results.WithError(err)
+	}
+
 	certificateResources, res := certificates.Reconcile(
 		ctx,
 		d,
 		d.ES,
-		[]corev1.Service{*externalService},
+		append([]corev1.Service{*externalService}, statefulSetHTTPServices...),
 		d.OperatorParameters.CACertRotation,
 		d.OperatorParameters.CertRotation,
+		d.OperatorParameters.PrivateKeyOptions,
 	)
 	if results.WithResults(res).HasError() {
 		return results
 	}
+	if d.ES.Spec.Transport.TLS.UserDefinedNodeCertificates() {
+		d.ReconcileState.UpdateTransportCertificatesValid(certificateResources.InvalidTransportCertificates)
+	}
 
-	controllerUser, err := user.ReconcileUsersAndRoles(ctx, d.Client, d.ES, d.DynamicWatches(), d.Recorder())
+	controllerUser, passwordRotation, err := user.ReconcileUsersAndRoles(
+		ctx,
+		d.Client,
+		d.ES,
+		d.DynamicWatches(),
+		d.Recorder(),
+		user.NewCredentialsVerifier(ctx, d.Client, d.OperatorParameters.Dialer, d.ES),
+	)
 	if err != nil {
 		return results.WithError(err)
 	}
+	d.ReconcileState.UpdateDefaultCredentialsAvailable()
+	d.ReconcileState.UpdatePasswordRotation(passwordRotation.Time)
+	if passwordRotation.RotateIn > 0 {
+		// come back when the passwords must be rotated, or to check the progress of an ongoing rotation
+		results.WithResult(controller.Result{RequeueAfter: passwordRotation.RotateIn})
+	}
 
 	resourcesState, err := reconcile.NewResourcesStateFromAPI(d.Client, d.ES)
 	if err != nil {
@@ -178,7 +204,6 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results.WithError(err)
 	}
 
-	// TODO: support user-supplied certificate (non-ca)
 	esClient := d.newElasticsearchClient(
 		resourcesState,
 		controllerUser,
@@ -191,6 +216,7 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	if err != nil {
 		return results.WithError(err)
 	}
+	d.ReconcileState.UpdateElasticsearchReachable(esReachable)
 
 	if esReachable {
 		// reconcile the license
@@ -216,6 +242,40 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		if requeue {
 			results.WithResult(defaultRequeue)
 		}
+
+		// reconcile snapshot repositories and snapshot lifecycle policies
+		policiesStatus, requeue, err := snapshots.Reconcile(ctx, d.Client, esClient, d.ES)
+		if err != nil {
+			msg := "Could not update snapshot repositories or snapshot lifecycle policies in Elasticsearch"
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+			log.Error(err, msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			results.WithResult(defaultRequeue)
+		} else {
+			d.ReconcileState.UpdateSnapshotLifecyclePolicies(policiesStatus)
+		}
+		if requeue {
+			results.WithResult(defaultRequeue)
+		}
+
+		// reconcile index lifecycle policies, templates and ingest pipelines
+		resourcesStatus, err := clusterresources.Reconcile(ctx, d.Client, esClient, d.DynamicWatches(), d.ES)
+		if err != nil {
+			msg := "Could not update index lifecycle policies, templates or ingest pipelines in Elasticsearch"
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+			log.Error(err, msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			results.WithResult(defaultRequeue)
+		}
+		d.ReconcileState.UpdateClusterResources(resourcesStatus)
+
+		// reconcile native realm users and role mappings
+		authStatus, err := user.ReconcileNativeRealm(ctx, d.Client, esClient, d.DynamicWatches(), d.ES)
+		if err != nil {
+			msg := "Could not update native users or role mappings in Elasticsearch"
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+			log.Error(err, msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			results.WithResult(defaultRequeue)
+		}
+		d.ReconcileState.UpdateAuth(authStatus)
 	}
 
 	// Compute seed hosts based on current masters with a podIP
@@ -244,8 +304,25 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		results = results.WithResult(defaultRequeue)
 	}
 
+	// the Stack Monitoring sidecars cannot be configured until all the monitoring associations are configured
+	if !association.AreConfiguredIfSet(d.ES.GetAssociations(), d.Recorder()) {
+		return results
+	}
+	if err := stackmon.ReconcileConfigSecrets(d.Client, d.ES); err != nil {
+		return results.WithError(err)
+	}
+
 	// reconcile StatefulSets and nodes configuration
-	res = d.reconcileNodeSpecs(ctx, esReachable, esClient, d.ReconcileState, observedState, *resourcesState, keystoreResources)
+	res = d.reconcileNodeSpecs(
+		ctx,
+		esReachable,
+		esClient,
+		d.ReconcileState,
+		observedState,
+		*resourcesState,
+		keystoreResources,
+		certificateResources.InvalidTransportCertificates,
+	)
 	results = results.WithResults(res)
 
 	if res.HasError() {
@@ -6,11 +6,13 @@ package driver
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 )
 
@@ -21,6 +23,8 @@ import (
 type fakeESClient struct { //nolint:maligned
 	esclient.Client
 
+	version version.Version
+
 	SetMinimumMasterNodesCalled     bool
 	SetMinimumMasterNodesCalledWith int
 
@@ -45,6 +49,21 @@ type fakeESClient struct { //nolint:maligned
 
 	health                      esclient.Health
 	GetClusterHealthCalledCount int
+
+	shards esclient.Shards
+
+	shutdowns                esclient.ShutdownResponse
+	PutShutdownCalledWith    []string
+	DeleteShutdownCalledWith []string
+
+	mlInfo                       esclient.MLInfo
+	datafeedsStats               esclient.DatafeedsStats
+	SetMLUpgradeModeCalledWith   []bool
+	GetDatafeedsStatsCalledCount int
+}
+
+func (f *fakeESClient) Version() version.Version {
+	return f.version
 }
 
 func (f *fakeESClient) SetMinimumMasterNodes(_ context.Context, n int) error {
@@ -105,6 +124,61 @@ func (f *fakeESClient) GetClusterHealthWaitForAllEvents(_ context.Context) (escl
 	return f.health, nil
 }
 
+func (f *fakeESClient) GetShards(_ context.Context) (esclient.Shards, error) {
+	return f.shards, nil
+}
+
+func (f *fakeESClient) GetShutdown(_ context.Context, nodeID *string) (esclient.ShutdownResponse, error) {
+	if nodeID == nil {
+		return f.shutdowns, nil
+	}
+	var response esclient.ShutdownResponse
+	for _, s := range f.shutdowns.Nodes {
+		if s.NodeID == *nodeID {
+			response.Nodes = append(response.Nodes, s)
+		}
+	}
+	return response, nil
+}
+
+func (f *fakeESClient) PutShutdown(_ context.Context, nodeID string, shutdownType esclient.ShutdownType, reason string) error {
+	f.PutShutdownCalledWith = append(f.PutShutdownCalledWith, nodeID)
+	// simulate Elasticsearch reporting an ongoing shutdown
+	f.shutdowns.Nodes = append(f.shutdowns.Nodes, esclient.NodeShutdown{
+		NodeID: nodeID,
+		Type:   strings.ToUpper(string(shutdownType)),
+		Reason: reason,
+		Status: esclient.ShutdownInProgress,
+	})
+	return nil
+}
+
+func (f *fakeESClient) DeleteShutdown(_ context.Context, nodeID string) error {
+	f.DeleteShutdownCalledWith = append(f.DeleteShutdownCalledWith, nodeID)
+	return nil
+}
+
+func (f *fakeESClient) GetMLInfo(_ context.Context) (esclient.MLInfo, error) {
+	return f.mlInfo, nil
+}
+
+func (f *fakeESClient) SetMLUpgradeMode(_ context.Context, enabled bool) error {
+	f.SetMLUpgradeModeCalledWith = append(f.SetMLUpgradeModeCalledWith, enabled)
+	f.mlInfo.UpgradeMode = enabled
+	if enabled {
+		// simulate Elasticsearch unassigning all the datafeeds
+		for i := range f.datafeedsStats.Datafeeds {
+			f.datafeedsStats.Datafeeds[i].Node = nil
+		}
+	}
+	return nil
+}
+
+func (f *fakeESClient) GetDatafeedsStats(_ context.Context) (esclient.DatafeedsStats, error) {
+	f.GetDatafeedsStatsCalledCount++
+	return f.datafeedsStats, nil
+}
+
 // -- ESState tests
 
 func Test_memoizingNodes_NodesInCluster(t *testing.T) {
@@ -36,6 +36,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	observedState observer.State,
 	resourcesState reconcile.ResourcesState,
 	keystoreResources *keystore.Resources,
+	invalidTransportCertificates map[string]string,
 ) *reconciler.Results {
 	span, ctx := apm.StartSpan(ctx, "reconcile_node_spec", tracing.SpanTypeApp)
 	defer span.End()
@@ -46,7 +47,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	if ok, err := autoscaledResourcesSynced(d.ES); err != nil {
 		return results.WithError(fmt.Errorf("StatefulSet recreation: %w", err))
 	} else if !ok {
-		return results.WithResult(defaultRequeue)
+		return results.WithIncompleteReconciliation(defaultRequeue, "Waiting for autoscaled resources to be updated")
 	}
 
 	// check if actual StatefulSets and corresponding pods match our expectations before applying any change
@@ -70,7 +71,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		// the sset doesn't exist (was just deleted), but the Pods do actually exist.
 		log.V(1).Info("StatefulSets recreation in progress, re-queueing.",
 			"namespace", d.ES.Namespace, "es_name", d.ES.Name, "recreations", recreations)
-		return results.WithResult(defaultRequeue)
+		return results.WithIncompleteReconciliation(defaultRequeue, "StatefulSets recreation in progress")
 	}
 
 	actualStatefulSets, err := sset.RetrieveActualStatefulSets(d.Client, k8s.ExtractNamespacedName(&d.ES))
@@ -78,7 +79,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
-	expectedResources, err := nodespec.BuildExpectedResources(d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext)
+	expectedResources, err := nodespec.BuildExpectedResources(d.Client, d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext)
 	if err != nil {
 		return results.WithError(err)
 	}
@@ -115,8 +116,12 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		results.WithError(err)
 	}
 
-	// Update PDB to account for new replicas.
-	if err := pdb.Reconcile(d.Client, d.ES, actualStatefulSets); err != nil {
+	// Update PDB to account for new replicas and for the shards without replicas, if Elasticsearch can be reached.
+	var shardLister esclient.ShardLister
+	if esReachable {
+		shardLister = esClient
+	}
+	if err := pdb.Reconcile(ctx, d.Client, d.ES, actualStatefulSets, shardLister); err != nil {
 		return results.WithError(err)
 	}
 
@@ -142,7 +147,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	if !esReachable {
 		log.Info("ES cannot be reached yet, re-queuing", "namespace", d.ES.Namespace, "es_name", d.ES.Name)
 		reconcileState.UpdateElasticsearchApplyingChanges(resourcesState.CurrentPods)
-		return results.WithResult(defaultRequeue)
+		return results.WithIncompleteReconciliation(defaultRequeue, "Elasticsearch cannot be reached yet")
 	}
 
 	// Maybe update Zen1 minimum master nodes through the API, corresponding to the current nodes we have.
@@ -190,7 +195,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	}
 
 	// Phase 3: handle rolling upgrades.
-	rollingUpgradesRes := d.handleRollingUpgrades(ctx, esClient, esState, expectedResources.MasterNodesNames())
+	rollingUpgradesRes := d.handleRollingUpgrades(ctx, esClient, esState, expectedResources.MasterNodesNames(), invalidTransportCertificates)
 	results.WithResults(rollingUpgradesRes)
 	if rollingUpgradesRes.HasError() {
 		return results
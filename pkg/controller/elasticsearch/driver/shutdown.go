@@ -0,0 +1,24 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+)
+
+// nodeShutdownMinVersion is the first Elasticsearch version supporting the node shutdown API.
+var nodeShutdownMinVersion = version.MustParse("7.15.0")
+
+const (
+	// downscaleShutdownReason is the reason attached to node shutdowns registered before removing nodes.
+	downscaleShutdownReason = "downscale requested by ECK"
+	// upgradeShutdownReason is the reason attached to node shutdowns registered before restarting nodes.
+	upgradeShutdownReason = "rolling upgrade requested by ECK"
+)
+
+// supportsNodeShutdown returns true if the node shutdown API can be used with the given Elasticsearch version.
+func supportsNodeShutdown(v version.Version) bool {
+	return v.GTE(nodeShutdownMinVersion)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/shutdown"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
@@ -26,6 +27,7 @@ func (d *defaultDriver) handleRollingUpgrades(
 	esClient esclient.Client,
 	esState ESState,
 	expectedMaster []string,
+	invalidTransportCertificates map[string]string,
 ) *reconciler.Results {
 	results := &reconciler.Results{}
 
@@ -61,7 +63,7 @@ func (d *defaultDriver) handleRollingUpgrades(
 	}
 
 	// Maybe upgrade some of the nodes.
-	deletedPods, err := newRollingUpgrade(
+	rollingUpgrade := newRollingUpgrade(
 		ctx,
 		d,
 		statefulSets,
@@ -71,7 +73,9 @@ func (d *defaultDriver) handleRollingUpgrades(
 		actualMasters,
 		podsToUpgrade,
 		healthyPods,
-	).run()
+		invalidTransportCertificates,
+	)
+	deletedPods, err := rollingUpgrade.run()
 	if err != nil {
 		return results.WithError(err)
 	}
@@ -79,9 +83,17 @@ func (d *defaultDriver) handleRollingUpgrades(
 		// Some Pods have just been deleted, we don't need to try to enable shards allocation.
 		return results.WithResult(defaultRequeue)
 	}
+	// Remove the restart shutdown registrations of the nodes which have been upgraded and are back in the cluster.
+	if err := rollingUpgrade.clearShutdowns(); err != nil {
+		return results.WithError(err)
+	}
+	// Resume the machine learning jobs once all the ML nodes have been upgraded.
+	if err := rollingUpgrade.maybeDisableMLUpgradeMode(); err != nil {
+		return results.WithError(err)
+	}
 	if len(podsToUpgrade) > len(deletedPods) {
 		// Some Pods have not been updated, ensure that we retry later
-		results.WithResult(defaultRequeue)
+		results.WithIncompleteReconciliation(defaultRequeue, "Some Pods are pending a rolling upgrade")
 	}
 
 	// Maybe re-enable shards allocation if upgraded nodes are back into the cluster.
@@ -98,6 +110,8 @@ type rollingUpgradeCtx struct {
 	statefulSets    sset.StatefulSetList
 	esClient        esclient.Client
 	shardLister     esclient.ShardLister
+	nodeShutdown    *shutdown.NodeShutdown
+	mlClient        esclient.MLClient
 	esState         ESState
 	expectations    *expectations.Expectations
 	reconcileState  *reconcile.State
@@ -105,6 +119,8 @@ type rollingUpgradeCtx struct {
 	actualMasters   []corev1.Pod
 	podsToUpgrade   []corev1.Pod
 	healthyPods     map[string]corev1.Pod
+	// invalidTransportCertificates holds the Pods whose user-provided transport certificate must not be relied upon
+	invalidTransportCertificates map[string]string
 }
 
 func newRollingUpgrade(
@@ -117,7 +133,19 @@ func newRollingUpgrade(
 	actualMasters []corev1.Pod,
 	podsToUpgrade []corev1.Pod,
 	healthyPods map[string]corev1.Pod,
+	invalidTransportCertificates map[string]string,
 ) rollingUpgradeCtx {
+	// the node shutdown API is used instead of disabling shards allocation if supported by all the nodes
+	var nodeShutdown *shutdown.NodeShutdown
+	if supportsNodeShutdown(esClient.Version()) {
+		logger := log.WithValues("namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		nodeShutdown = shutdown.NewNodeShutdown(esClient, esclient.Restart, upgradeShutdownReason, logger)
+	}
+	// the machine learning jobs are halted while ML nodes are restarted if supported by all the nodes
+	var mlClient esclient.MLClient
+	if supportsMLUpgradeMode(esClient.Version()) {
+		mlClient = esClient
+	}
 	return rollingUpgradeCtx{
 		parentCtx:       ctx,
 		client:          d.Client,
@@ -125,6 +153,8 @@ func newRollingUpgrade(
 		statefulSets:    statefulSets,
 		esClient:        esClient,
 		shardLister:     esClient,
+		nodeShutdown:    nodeShutdown,
+		mlClient:        mlClient,
 		esState:         esState,
 		expectations:    d.Expectations,
 		reconcileState:  d.ReconcileState,
@@ -132,6 +162,8 @@ func newRollingUpgrade(
 		actualMasters:   actualMasters,
 		podsToUpgrade:   podsToUpgrade,
 		healthyPods:     healthyPods,
+
+		invalidTransportCertificates: invalidTransportCertificates,
 	}
 }
 
@@ -305,6 +337,62 @@ func (ctx *rollingUpgradeCtx) prepareClusterForNodeRestart(esClient esclient.Cli
 		return err
 	}
 
-	// TODO: halt ML jobs on that node
 	return nil
 }
+
+// prepareNodesForRestart registers a restart shutdown for the given Pods and returns the ones for which the shutdown
+// is complete and can therefore be deleted. Pods which are not part of the cluster are returned as is since there is
+// nothing to prepare.
+func (ctx *rollingUpgradeCtx) prepareNodesForRestart(pods []corev1.Pod) ([]corev1.Pod, error) {
+	var inCluster []string
+	for _, pod := range pods {
+		if _, healthy := ctx.healthyPods[pod.Name]; healthy {
+			inCluster = append(inCluster, pod.Name)
+		}
+	}
+	if err := ctx.nodeShutdown.RequestShutdowns(ctx.parentCtx, inCluster); err != nil {
+		return nil, err
+	}
+	var ready []corev1.Pod
+	for _, pod := range pods {
+		if _, healthy := ctx.healthyPods[pod.Name]; !healthy {
+			ready = append(ready, pod)
+			continue
+		}
+		response, err := ctx.nodeShutdown.ShutdownStatus(ctx.parentCtx, pod.Name)
+		if err != nil {
+			return nil, err
+		}
+		if response.Status != esclient.ShutdownComplete {
+			log.V(1).Info("Node shutdown not complete yet, delaying pod deletion",
+				"es_name", ctx.ES.Name, "namespace", ctx.ES.Namespace, "pod_name", pod.Name,
+				"status", response.Status, "explanation", response.Explanation)
+			continue
+		}
+		ready = append(ready, pod)
+	}
+	if len(ready) == 0 {
+		return ready, nil
+	}
+	// Request a flush to optimize indices recovery when the node restarts.
+	return ready, doFlush(ctx.parentCtx, ctx.ES, ctx.esClient)
+}
+
+// clearShutdowns removes the restart shutdown registrations of the healthy nodes which do not need to be upgraded
+// anymore. It is a no-op if the node shutdown API is not used.
+func (ctx *rollingUpgradeCtx) clearShutdowns() error {
+	if ctx.nodeShutdown == nil {
+		return nil
+	}
+	toUpgrade := make(map[string]struct{}, len(ctx.podsToUpgrade))
+	for _, pod := range ctx.podsToUpgrade {
+		toUpgrade[pod.Name] = struct{}{}
+	}
+	var upgraded []string
+	for name := range ctx.healthyPods {
+		if _, exists := toUpgrade[name]; !exists {
+			upgraded = append(upgraded, name)
+		}
+	}
+	return ctx.nodeShutdown.ClearShutdowns(ctx.parentCtx, upgraded)
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+)
+
+// MLUpgradeModeAnnotation is set on the Elasticsearch resource while the machine learning upgrade mode is enabled by
+// the operator, so that an upgrade mode enabled by the user is left untouched.
+const MLUpgradeModeAnnotation = "elasticsearch.k8s.elastic.co/ml-upgrade-mode"
+
+// waitForMLDatafeedsToStopPredicate is the name of the predicate preventing the restart of ML nodes until the upgrade
+// mode is enabled and their datafeeds are stopped.
+const waitForMLDatafeedsToStopPredicate = "wait_for_ml_datafeeds_to_stop"
+
+// mlUpgradeModeMinVersion is the first Elasticsearch version supporting the machine learning upgrade mode.
+var mlUpgradeModeMinVersion = version.MustParse("6.7.0")
+
+// supportsMLUpgradeMode returns true if the machine learning upgrade mode can be used with the given Elasticsearch
+// version.
+func supportsMLUpgradeMode(v version.Version) bool {
+	return v.GTE(mlUpgradeModeMinVersion)
+}
+
+// maybeEnableMLUpgradeMode halts the machine learning jobs and datafeeds of the cluster before restarting ML nodes,
+// so that they are not killed abruptly. It is a no-op unless an ML node was selected for restart by all the predicates
+// but the one waiting for its datafeeds to stop, so that the jobs are not halted while ML nodes cannot be restarted.
+func (ctx *rollingUpgradeCtx) maybeEnableMLUpgradeMode(failedPredicates failedPredicates) error {
+	if ctx.mlClient == nil {
+		return nil
+	}
+	mlNodeToRestart := false
+	for _, failed := range failedPredicates {
+		if failed.predicate == waitForMLDatafeedsToStopPredicate {
+			mlNodeToRestart = true
+			break
+		}
+	}
+	if !mlNodeToRestart {
+		return nil
+	}
+	info, err := ctx.mlClient.GetMLInfo(ctx.parentCtx)
+	if err != nil {
+		return err
+	}
+	if info.UpgradeMode {
+		return nil
+	}
+	// record that the upgrade mode is enabled by the operator before enabling it, to disable it once the ML nodes
+	// are upgraded even if the request is interrupted
+	if _, exists := ctx.ES.Annotations[MLUpgradeModeAnnotation]; !exists {
+		if err := ctx.patchMLUpgradeModeAnnotation(true); err != nil {
+			return err
+		}
+	}
+	log.Info("Enabling machine learning upgrade mode", "es_name", ctx.ES.Name, "namespace", ctx.ES.Namespace)
+	return ctx.mlClient.SetMLUpgradeMode(ctx.parentCtx, true)
+}
+
+// maybeDisableMLUpgradeMode resumes the machine learning jobs and datafeeds of the cluster once all the ML nodes run
+// the target version and are back in the cluster. It is a no-op if the upgrade mode was not enabled by the operator.
+func (ctx *rollingUpgradeCtx) maybeDisableMLUpgradeMode() error {
+	if _, exists := ctx.ES.Annotations[MLUpgradeModeAnnotation]; !exists || ctx.mlClient == nil {
+		return nil
+	}
+	for _, pod := range ctx.podsToUpgrade {
+		if label.IsMLNode(pod) {
+			return nil
+		}
+	}
+	pods, err := ctx.statefulSets.GetActualPods(ctx.client)
+	if err != nil {
+		return err
+	}
+	var mlPods []corev1.Pod
+	for _, pod := range pods {
+		if label.IsMLNode(pod) {
+			mlPods = append(mlPods, pod)
+		}
+	}
+	// no ML node may be left if the ML nodes were removed or changed roles during the upgrade
+	for _, pod := range mlPods {
+		if _, healthy := ctx.healthyPods[pod.Name]; !healthy || pod.Labels[label.VersionLabelName] != ctx.ES.Spec.Version {
+			return nil
+		}
+	}
+	info, err := ctx.mlClient.GetMLInfo(ctx.parentCtx)
+	if err != nil {
+		return err
+	}
+	if info.UpgradeMode {
+		log.Info("Disabling machine learning upgrade mode", "es_name", ctx.ES.Name, "namespace", ctx.ES.Namespace)
+		if err := ctx.mlClient.SetMLUpgradeMode(ctx.parentCtx, false); err != nil {
+			return err
+		}
+	}
+	return ctx.patchMLUpgradeModeAnnotation(false)
+}
+
+// patchMLUpgradeModeAnnotation sets or removes the MLUpgradeModeAnnotation with a merge patch, leaving the rest of the
+// Elasticsearch resource untouched. The new resource version is carried over to the status update of the reconciliation.
+func (ctx *rollingUpgradeCtx) patchMLUpgradeModeAnnotation(enabled bool) error {
+	// a null value removes the annotation
+	var value *string
+	if enabled {
+		enabledValue := "true"
+		value = &enabledValue
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{MLUpgradeModeAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := ctx.client.Patch(ctx.parentCtx, &ctx.ES, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return err
+	}
+	ctx.reconcileState.UpdateResourceVersion(ctx.ES.ResourceVersion)
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/migration"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// mlPod returns the Pod of an ML node.
+func mlPod(t testPod) corev1.Pod {
+	pod := t.toPod()
+	label.NodeTypesMLLabelName.Set(true, pod.Labels)
+	return pod
+}
+
+func Test_supportsMLUpgradeMode(t *testing.T) {
+	require.False(t, supportsMLUpgradeMode(version.MustParse("6.6.2")))
+	require.True(t, supportsMLUpgradeMode(version.MustParse("6.7.0")))
+	require.True(t, supportsMLUpgradeMode(version.MustParse("7.15.0")))
+}
+
+func Test_rollingUpgradeCtx_maybeEnableMLUpgradeMode(t *testing.T) {
+	mlSelected := failedPredicates{{pod: "es-ml-0", predicate: waitForMLDatafeedsToStopPredicate}}
+	tests := []struct {
+		name             string
+		noMLClient       bool
+		mlInfo           esclient.MLInfo
+		failedPredicates failedPredicates
+		wantCalls        []bool
+		// wantAnnotated is true if the upgrade mode is expected to be recorded as enabled by the operator
+		wantAnnotated bool
+	}{
+		{
+			name:             "ML upgrade mode not supported",
+			noMLClient:       true,
+			failedPredicates: mlSelected,
+		},
+		{
+			name: "no ML node to restart",
+		},
+		{
+			name:             "ML node to restart blocked by other predicates",
+			failedPredicates: failedPredicates{{pod: "es-ml-0", predicate: "only_restart_healthy_node_if_green_or_yellow"}},
+		},
+		{
+			name:             "ML node selected for restart",
+			failedPredicates: mlSelected,
+			wantCalls:        []bool{true},
+			wantAnnotated:    true,
+		},
+		{
+			name:             "ML upgrade mode already enabled",
+			mlInfo:           esclient.MLInfo{UpgradeMode: true},
+			failedPredicates: mlSelected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{mlInfo: tt.mlInfo}
+			es := newTestES("7.15.0", nil)
+			k8sClient := k8s.NewFakeClient(&es)
+			ctx := rollingUpgradeCtx{
+				parentCtx:      context.Background(),
+				client:         k8sClient,
+				ES:             es,
+				mlClient:       esClient,
+				reconcileState: reconcile.NewState(es),
+			}
+			if tt.noMLClient {
+				ctx.mlClient = nil
+			}
+			require.NoError(t, ctx.maybeEnableMLUpgradeMode(tt.failedPredicates))
+			require.Equal(t, tt.wantCalls, esClient.SetMLUpgradeModeCalledWith)
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &es))
+			_, annotated := es.Annotations[MLUpgradeModeAnnotation]
+			require.Equal(t, tt.wantAnnotated, annotated)
+		})
+	}
+}
+
+func Test_rollingUpgradeCtx_maybeDisableMLUpgradeMode(t *testing.T) {
+	upgradedML := mlPod(newTestPod("es-ml-0").withVersion("7.15.0").inStatefulset("es-ml"))
+	outdatedML := mlPod(newTestPod("es-ml-0").withVersion("7.14.0").inStatefulset("es-ml"))
+	data := newTestPod("es-data-0").withVersion("7.15.0").isData(true).inStatefulset("es-data").toPod()
+	tests := []struct {
+		name          string
+		mlInfo        esclient.MLInfo
+		pods          []corev1.Pod
+		podsToUpgrade []corev1.Pod
+		healthyPods   []corev1.Pod
+		// notAnnotated is true if the upgrade mode was not enabled by the operator
+		notAnnotated  bool
+		wantCalls     []bool
+		wantAnnotated bool
+	}{
+		{
+			name:          "ML node still to upgrade",
+			mlInfo:        esclient.MLInfo{UpgradeMode: true},
+			pods:          []corev1.Pod{outdatedML, data},
+			podsToUpgrade: []corev1.Pod{outdatedML},
+			healthyPods:   []corev1.Pod{outdatedML, data},
+			wantAnnotated: true,
+		},
+		{
+			name:          "ML node not back in the cluster yet",
+			mlInfo:        esclient.MLInfo{UpgradeMode: true},
+			pods:          []corev1.Pod{upgradedML, data},
+			healthyPods:   []corev1.Pod{data},
+			wantAnnotated: true,
+		},
+		{
+			name:          "ML node not running the target version",
+			mlInfo:        esclient.MLInfo{UpgradeMode: true},
+			pods:          []corev1.Pod{outdatedML, data},
+			healthyPods:   []corev1.Pod{outdatedML, data},
+			wantAnnotated: true,
+		},
+		{
+			name:        "all ML nodes upgraded",
+			mlInfo:      esclient.MLInfo{UpgradeMode: true},
+			pods:        []corev1.Pod{upgradedML, data},
+			healthyPods: []corev1.Pod{upgradedML, data},
+			wantCalls:   []bool{false},
+		},
+		{
+			name:        "no ML node left",
+			mlInfo:      esclient.MLInfo{UpgradeMode: true},
+			pods:        []corev1.Pod{data},
+			healthyPods: []corev1.Pod{data},
+			wantCalls:   []bool{false},
+		},
+		{
+			name:        "ML upgrade mode already disabled",
+			pods:        []corev1.Pod{upgradedML, data},
+			healthyPods: []corev1.Pod{upgradedML, data},
+		},
+		{
+			name:         "ML upgrade mode enabled by the user",
+			mlInfo:       esclient.MLInfo{UpgradeMode: true},
+			pods:         []corev1.Pod{upgradedML, data},
+			healthyPods:  []corev1.Pod{upgradedML, data},
+			notAnnotated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{mlInfo: tt.mlInfo}
+			annotations := map[string]string{MLUpgradeModeAnnotation: "true"}
+			if tt.notAnnotated {
+				annotations = nil
+			}
+			es := newTestES("7.15.0", annotations)
+			objs := []runtime.Object{&es}
+			for i := range tt.pods {
+				objs = append(objs, &tt.pods[i])
+			}
+			k8sClient := k8s.NewFakeClient(objs...)
+			ctx := rollingUpgradeCtx{
+				parentCtx: context.Background(),
+				client:    k8sClient,
+				ES:        es,
+				statefulSets: sset.StatefulSetList{
+					sset.TestSset{Name: "es-ml", ClusterName: TestEsName, Namespace: TestEsNamespace, Replicas: 1}.Build(),
+					sset.TestSset{Name: "es-data", ClusterName: TestEsName, Namespace: TestEsNamespace, Replicas: 1}.Build(),
+				},
+				mlClient:       esClient,
+				reconcileState: reconcile.NewState(es),
+				podsToUpgrade:  tt.podsToUpgrade,
+				healthyPods:    podsByName(tt.healthyPods),
+			}
+			require.NoError(t, ctx.maybeDisableMLUpgradeMode())
+			require.Equal(t, tt.wantCalls, esClient.SetMLUpgradeModeCalledWith)
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &es))
+			_, annotated := es.Annotations[MLUpgradeModeAnnotation]
+			require.Equal(t, tt.wantAnnotated, annotated)
+		})
+	}
+}
+
+func TestUpgradePodsDeletion_Delete_MLNodes(t *testing.T) {
+	upgradeTestPods := newUpgradeTestPods(
+		newTestPod("es-ml-0").withVersion("7.14.0").isHealthy(true).needsUpgrade(true).isInCluster(true),
+	)
+	pod := mlPod(upgradeTestPods[0])
+	runningDatafeed := func() esclient.DatafeedsStats {
+		return esclient.DatafeedsStats{Datafeeds: []esclient.DatafeedStats{
+			{DatafeedID: "datafeed", State: esclient.DatafeedStarted, Node: &esclient.DatafeedNode{Name: pod.Name}},
+		}}
+	}
+	tests := []struct {
+		name   string
+		mlInfo esclient.MLInfo
+		health esv1.ElasticsearchHealth
+		// wantDeleted are the Pods expected to be deleted by each call to Delete
+		wantDeleted [][]string
+		wantCalls   []bool
+	}{
+		{
+			name:        "datafeeds are halted by the upgrade mode before the ML node is restarted",
+			health:      esv1.ElasticsearchGreenHealth,
+			wantDeleted: [][]string{nil, {pod.Name}},
+			wantCalls:   []bool{true},
+		},
+		{
+			name:        "datafeed still running",
+			mlInfo:      esclient.MLInfo{UpgradeMode: true},
+			health:      esv1.ElasticsearchGreenHealth,
+			wantDeleted: [][]string{nil, nil},
+		},
+		{
+			name:        "ML node blocked by another predicate",
+			health:      esv1.ElasticsearchRedHealth,
+			wantDeleted: [][]string{nil, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{mlInfo: tt.mlInfo, datafeedsStats: runningDatafeed()}
+			es := upgradeTestPods.toES("7.15.0", 1)
+			k8sClient := k8s.NewFakeClient(&pod, &es)
+			ctx := rollingUpgradeCtx{
+				parentCtx:      context.Background(),
+				client:         k8sClient,
+				ES:             es,
+				statefulSets:   upgradeTestPods.toStatefulSetList(),
+				esClient:       esClient,
+				shardLister:    migration.NewFakeShardLister(esclient.Shards{}),
+				mlClient:       esClient,
+				esState:        &testESState{inCluster: upgradeTestPods.podsInCluster(), health: esclient.Health{Status: tt.health}},
+				expectations:   expectations.NewExpectations(k8sClient),
+				reconcileState: reconcile.NewState(es),
+				podsToUpgrade:  []corev1.Pod{pod},
+				healthyPods:    podsByName([]corev1.Pod{pod}),
+			}
+			for _, wantDeleted := range tt.wantDeleted {
+				deleted, err := ctx.Delete()
+				require.NoError(t, err)
+				require.ElementsMatch(t, wantDeleted, names(deleted))
+			}
+			require.Equal(t, tt.wantCalls, esClient.SetMLUpgradeModeCalledWith)
+		})
+	}
+}
+
+// newTestES returns an Elasticsearch resource with the given version and annotations.
+func newTestES(version string, annotations map[string]string) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: TestEsNamespace, Name: TestEsName, Annotations: annotations},
+		Spec:       esv1.ElasticsearchSpec{Version: version},
+	}
+}
+
+func podsByName(pods []corev1.Pod) map[string]corev1.Pod {
+	result := make(map[string]corev1.Pod, len(pods))
+	for _, pod := range pods {
+		result[pod.Name] = pod
+	}
+	return result
+}
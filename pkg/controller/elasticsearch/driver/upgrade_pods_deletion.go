@@ -22,6 +22,7 @@ import (
 // Do not run this function unless driver expectations are met.
 func (ctx *rollingUpgradeCtx) Delete() ([]corev1.Pod, error) {
 	if len(ctx.podsToUpgrade) == 0 {
+		ctx.reconcileState.UpdateUpgradeBlocked(nil)
 		return nil, nil
 	}
 
@@ -39,19 +40,28 @@ func (ctx *rollingUpgradeCtx) Delete() ([]corev1.Pod, error) {
 		ctx.ES,
 		ctx.esState,
 		ctx.shardLister,
+		ctx.mlClient,
 		ctx.healthyPods,
 		ctx.podsToUpgrade,
 		ctx.expectedMasters,
 		ctx.actualMasters,
+		ctx.invalidTransportCertificates,
 	)
 	log.V(1).Info("Applying predicates",
 		"maxUnavailableReached", maxUnavailableReached,
 		"allowedDeletions", allowedDeletions,
 	)
-	podsToDelete, err := applyPredicates(predicateContext, candidates, maxUnavailableReached, allowedDeletions)
+	podsToDelete, failedPredicates, err := applyPredicates(predicateContext, candidates, maxUnavailableReached, allowedDeletions)
 	if err != nil {
 		return podsToDelete, err
 	}
+	// surface the predicates preventing some Pods from being restarted in the status of the cluster
+	ctx.reconcileState.UpdateUpgradeBlocked(groupByPredicates(failedPredicates))
+
+	// Halt the machine learning jobs once an ML node can be restarted, it is deleted once its datafeeds are stopped.
+	if err := ctx.maybeEnableMLUpgradeMode(failedPredicates); err != nil {
+		return podsToDelete, err
+	}
 
 	if len(podsToDelete) == 0 {
 		log.V(1).Info(
@@ -62,7 +72,13 @@ func (ctx *rollingUpgradeCtx) Delete() ([]corev1.Pod, error) {
 		return podsToDelete, nil
 	}
 
-	if err := ctx.prepareClusterForNodeRestart(ctx.esClient, ctx.esState); err != nil {
+	if ctx.nodeShutdown != nil {
+		// only delete the Pods for which Elasticsearch reports the shutdown as complete
+		podsToDelete, err = ctx.prepareNodesForRestart(podsToDelete)
+		if err != nil {
+			return podsToDelete, err
+		}
+	} else if err := ctx.prepareClusterForNodeRestart(ctx.esClient, ctx.esState); err != nil {
 		return podsToDelete, err
 	}
 	// TODO: If master is changed into a data node (or the opposite) it must be excluded or we should update m_m_n
@@ -22,8 +22,12 @@ type PredicateContext struct {
 	toUpdate               []corev1.Pod
 	esState                ESState
 	shardLister            client.ShardLister
+	mlClient               client.MLClient
 	masterUpdateInProgress bool
 	ctx                    context.Context
+	// invalidTransportCertificates holds the reasons why the user-provided transport certificates of some Pods
+	// cannot be used, indexed by Pod name.
+	invalidTransportCertificates map[string]string
 }
 
 // Predicate is a function that indicates if a Pod can be deleted (or not).
@@ -55,10 +59,12 @@ func NewPredicateContext(
 	es esv1.Elasticsearch,
 	state ESState,
 	shardLister client.ShardLister,
+	mlClient client.MLClient,
 	healthyPods map[string]corev1.Pod,
 	podsToUpgrade []corev1.Pod,
 	masterNodesNames []string,
 	actualMasters []corev1.Pod,
+	invalidTransportCertificates map[string]string,
 ) PredicateContext {
 	return PredicateContext{
 		es:               es,
@@ -68,18 +74,25 @@ func NewPredicateContext(
 		toUpdate:         podsToUpgrade,
 		esState:          state,
 		shardLister:      shardLister,
+		mlClient:         mlClient,
 		ctx:              ctx,
+
+		invalidTransportCertificates: invalidTransportCertificates,
 	}
 }
 
-func applyPredicates(ctx PredicateContext, candidates []corev1.Pod, maxUnavailableReached bool, allowedDeletions int) (deletedPods []corev1.Pod, err error) {
-	var failedPredicates failedPredicates
+func applyPredicates(
+	ctx PredicateContext,
+	candidates []corev1.Pod,
+	maxUnavailableReached bool,
+	allowedDeletions int,
+) (deletedPods []corev1.Pod, failedPredicates failedPredicates, err error) {
 
 Loop:
 	for _, candidate := range candidates {
 		switch predicateErr, err := runPredicates(ctx, candidate, deletedPods, maxUnavailableReached); {
 		case err != nil:
-			return deletedPods, err
+			return deletedPods, failedPredicates, err
 		case predicateErr != nil:
 			// A predicate has failed on this Pod
 			failedPredicates = append(failedPredicates, *predicateErr)
@@ -109,7 +122,7 @@ Loop:
 			"es_name", ctx.es.Name,
 			"failed_predicates", groupByPredicates(failedPredicates))
 	}
-	return deletedPods, nil
+	return deletedPods, failedPredicates, nil
 }
 
 var predicates = [...]Predicate{
@@ -146,6 +159,20 @@ var predicates = [...]Predicate{
 			return true, nil
 		},
 	},
+	{
+		// Do not restart a Pod if its user-provided transport certificate is missing, invalid or about to expire, it
+		// may not be able to join the cluster again.
+		name: "require_valid_transport_certificate",
+		fn: func(
+			context PredicateContext,
+			candidate corev1.Pod,
+			deletedPods []corev1.Pod,
+			maxUnavailableReached bool,
+		) (b bool, e error) {
+			_, invalid := context.invalidTransportCertificates[candidate.Name]
+			return !invalid, nil
+		},
+	},
 	{
 		// If health is not Green or Yellow only allow unhealthy Pods to be restarted.
 		// This is intended to unlock some situations where the cluster is not green and
@@ -406,6 +433,38 @@ var predicates = [...]Predicate{
 			return true, nil
 		},
 	},
+	{
+		// Do not restart an ML node until the machine learning upgrade mode is enabled and its datafeeds are stopped.
+		// This must remain the last predicate: the upgrade mode is only enabled once an ML node passed all the other
+		// ones, so that the machine learning jobs are not halted while ML nodes cannot be restarted.
+		name: waitForMLDatafeedsToStopPredicate,
+		fn: func(
+			context PredicateContext,
+			candidate corev1.Pod,
+			deletedPods []corev1.Pod,
+			maxUnavailableReached bool,
+		) (b bool, e error) {
+			if context.mlClient == nil || !label.IsMLNode(candidate) {
+				return true, nil
+			}
+			if _, healthy := context.healthyPods[candidate.Name]; !healthy {
+				// the node is not running any datafeed if it is not part of the cluster
+				return true, nil
+			}
+			info, err := context.mlClient.GetMLInfo(context.ctx)
+			if err != nil {
+				return false, err
+			}
+			if !info.UpgradeMode {
+				return false, nil
+			}
+			stats, err := context.mlClient.GetDatafeedsStats(context.ctx)
+			if err != nil {
+				return false, err
+			}
+			return len(stats.RunningOn(candidate.Name)) == 0, nil
+		},
+	},
 }
 
 func willBecomeMasterNode(name string, masters []string) bool {
@@ -181,6 +181,8 @@ func TestUpgradePodsDeletion_Delete(t *testing.T) {
 		maxUnavailable  int
 		podFilter       filter
 		esVersion       string
+
+		invalidTransportCertificates map[string]string
 	}
 	tests := []struct {
 		name                         string
@@ -208,6 +210,26 @@ func TestUpgradePodsDeletion_Delete(t *testing.T) {
 			wantErr:                      false,
 			wantShardsAllocationDisabled: true,
 		},
+		{
+			name: "Do not delete a Pod with an invalid user-provided transport certificate",
+			fields: fields{
+				esVersion: "7.5.0",
+				upgradeTestPods: newUpgradeTestPods(
+					newTestPod("master-0").isMaster(true).isData(false).isHealthy(true).needsUpgrade(false).isInCluster(true),
+					newTestPod("node-0").isMaster(false).isData(true).isHealthy(true).needsUpgrade(true).isInCluster(true),
+					newTestPod("node-1").isMaster(false).isData(true).isHealthy(true).needsUpgrade(true).isInCluster(true),
+				),
+				maxUnavailable: 2,
+				shardLister:    migration.NewFakeShardLister(client.Shards{}),
+				health:         client.Health{Status: esv1.ElasticsearchGreenHealth},
+				podFilter:      nothing,
+
+				invalidTransportCertificates: map[string]string{"node-0": "no certificate in secret ns/node-certs"},
+			},
+			deleted:                      []string{"node-1"},
+			wantErr:                      false,
+			wantShardsAllocationDisabled: true,
+		},
 		{
 			name: "All Pods are upgraded",
 			fields: fields{
@@ -664,9 +686,12 @@ func TestUpgradePodsDeletion_Delete(t *testing.T) {
 			shardLister:     tt.fields.shardLister,
 			esState:         esState,
 			expectations:    expectations.NewExpectations(k8sClient),
+			reconcileState:  reconcile.NewState(esv1.Elasticsearch{}),
 			expectedMasters: tt.fields.upgradeTestPods.toMasters(noMutation),
 			podsToUpgrade:   tt.fields.upgradeTestPods.toUpgrade(),
 			healthyPods:     tt.fields.upgradeTestPods.toHealthyPods(),
+
+			invalidTransportCertificates: tt.fields.invalidTransportCertificates,
 		}
 
 		deleted, err := ctx.Delete()
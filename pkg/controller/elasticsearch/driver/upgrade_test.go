@@ -16,6 +16,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/shutdown"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
@@ -231,3 +234,69 @@ func Test_doFlush(t *testing.T) {
 		})
 	}
 }
+
+func Test_rollingUpgradeCtx_prepareNodesForRestart(t *testing.T) {
+	esClient := &fakeESClient{
+		version: version.MustParse("7.15.0"),
+		nodes: esclient.Nodes{Nodes: map[string]esclient.Node{
+			"id-0": {Name: "pod-0"},
+			"id-1": {Name: "pod-1"},
+		}},
+		shutdowns: esclient.ShutdownResponse{Nodes: []esclient.NodeShutdown{
+			{NodeID: "id-0", Type: "RESTART", Status: esclient.ShutdownComplete},
+		}},
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}},
+	}
+	ctx := rollingUpgradeCtx{
+		parentCtx:    context.Background(),
+		ES:           esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "7.15.0"}},
+		esClient:     esClient,
+		nodeShutdown: shutdown.NewNodeShutdown(esClient, esclient.Restart, upgradeShutdownReason, log),
+		// pod-2 is not part of the cluster
+		healthyPods: map[string]corev1.Pod{"pod-0": pods[0], "pod-1": pods[1]},
+	}
+
+	ready, err := ctx.prepareNodesForRestart(pods)
+	require.NoError(t, err)
+	// a shutdown should only be requested for pod-1: pod-0 is already registered, pod-2 is not in the cluster
+	require.Equal(t, []string{"id-1"}, esClient.PutShutdownCalledWith)
+	// pod-1 shutdown is still in progress
+	require.Equal(t, []corev1.Pod{pods[0], pods[2]}, ready)
+	require.True(t, esClient.SyncedFlushCalled)
+}
+
+func Test_rollingUpgradeCtx_clearShutdowns(t *testing.T) {
+	esClient := &fakeESClient{
+		version: version.MustParse("7.15.0"),
+		nodes: esclient.Nodes{Nodes: map[string]esclient.Node{
+			"id-0": {Name: "pod-0"},
+			"id-1": {Name: "pod-1"},
+		}},
+		shutdowns: esclient.ShutdownResponse{Nodes: []esclient.NodeShutdown{
+			{NodeID: "id-0", Type: "RESTART", Status: esclient.ShutdownComplete},
+			{NodeID: "id-1", Type: "RESTART", Status: esclient.ShutdownComplete},
+		}},
+	}
+	pod0 := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-0"}}
+	pod1 := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}}
+	ctx := rollingUpgradeCtx{
+		parentCtx:    context.Background(),
+		esClient:     esClient,
+		nodeShutdown: shutdown.NewNodeShutdown(esClient, esclient.Restart, upgradeShutdownReason, log),
+		healthyPods:  map[string]corev1.Pod{"pod-0": pod0, "pod-1": pod1},
+		// pod-1 has not been upgraded yet
+		podsToUpgrade: []corev1.Pod{pod1},
+	}
+	require.NoError(t, ctx.clearShutdowns())
+	require.Equal(t, []string{"id-0"}, esClient.DeleteShutdownCalledWith)
+
+	// no-op if the node shutdown API is not used
+	legacyClient := &fakeESClient{}
+	legacyCtx := rollingUpgradeCtx{parentCtx: context.Background(), esClient: legacyClient, healthyPods: ctx.healthyPods}
+	require.NoError(t, legacyCtx.clearShutdowns())
+	require.Empty(t, legacyClient.DeleteShutdownCalledWith)
+}
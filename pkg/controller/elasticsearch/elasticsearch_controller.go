@@ -6,9 +6,11 @@ package elasticsearch
 
 import (
 	"context"
+	"fmt"
 	"sync/atomic"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
@@ -23,6 +25,7 @@ import (
 	commonversion "github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/transport"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/clusterresources"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/driver"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/observer"
@@ -125,6 +128,11 @@ func addWatches(c controller.Controller, r *ReconcileElasticsearch) error {
 		return err
 	}
 
+	// Watch user-provided ConfigMaps
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, r.dynamicWatches.ConfigMaps); err != nil {
+		return err
+	}
+
 	// Trigger a reconciliation when observers report a cluster health change
 	if err := c.Watch(observer.WatchClusterHealthChange(r.esObservers), reconciler.GenericEventHandler()); err != nil {
 		return err
@@ -197,6 +205,7 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 
 	state := esreconcile.NewState(es)
 	results := r.internalReconcile(ctx, es, state)
+	state.UpdateConditions(results)
 	err = r.updateStatus(ctx, es, state)
 	if err != nil {
 		if apierrors.IsConflict(err) {
@@ -212,7 +221,7 @@ func (r *ReconcileElasticsearch) fetchElasticsearch(ctx context.Context, request
 	span, _ := apm.StartSpan(ctx, "fetch_elasticsearch", tracing.SpanTypeApp)
 	defer span.End()
 
-	err := r.Get(context.Background(), request.NamespacedName, es)
+	err := association.FetchWithAssociations(ctx, r.Client, request, es)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// Object not found, cleanup in-memory state. Children resources are garbage-collected either by
@@ -253,7 +262,7 @@ func (r *ReconcileElasticsearch) internalReconcile(
 			"es_name", es.Name,
 		)
 		reconcileState.UpdateElasticsearchInvalid(err)
-		return results
+		return results.WithIncompleteReconciliation(reconcile.Result{}, fmt.Sprintf("Invalid manifest: %s", err))
 	}
 
 	err = validation.CheckForWarnings(es)
@@ -324,5 +333,9 @@ func (r *ReconcileElasticsearch) onDelete(es types.NamespacedName) error {
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(transport.CustomTransportCertsWatchKey(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedRolesWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedFileRealmWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.NativeUsersPasswordsWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.ElasticUserPasswordHashWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(clusterresources.DefinitionsWatchName(es))
+	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(clusterresources.DefinitionsWatchName(es))
 	return reconciler.GarbageCollectSoftOwnedSecrets(r.Client, es, esv1.Kind)
 }
@@ -5,6 +5,7 @@
 package initcontainer
 
 import (
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
 	corev1 "k8s.io/api/core/v1"
@@ -17,6 +18,7 @@ const PrepareFilesystemContainerName = "elastic-internal-init-filesystem"
 func NewInitContainers(
 	transportCertificatesVolume volume.SecretVolume,
 	keystoreResources *keystore.Resources,
+	zoneAwareness *esv1.ZoneAwareness,
 ) ([]corev1.Container, error) {
 	var containers []corev1.Container
 	prepareFsContainer, err := NewPrepareFSInitContainer(transportCertificatesVolume)
@@ -25,6 +27,11 @@ func NewInitContainers(
 	}
 	containers = append(containers, prepareFsContainer)
 
+	if zoneAwareness != nil {
+		// must run after the prepare-fs container
+		containers = append(containers, NewZoneAwarenessInitContainer(*zoneAwareness))
+	}
+
 	if keystoreResources != nil {
 		containers = append(containers, keystoreResources.InitContainer)
 	}
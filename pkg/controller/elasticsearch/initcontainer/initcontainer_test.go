@@ -7,6 +7,7 @@ package initcontainer
 import (
 	"testing"
 
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,7 @@ func TestNewInitContainers(t *testing.T) {
 		elasticsearchImage string
 		operatorImage      string
 		keystoreResources  *keystore.Resources
+		zoneAwareness      *esv1.ZoneAwareness
 	}
 	tests := []struct {
 		name                       string
@@ -32,12 +34,32 @@ func TestNewInitContainers(t *testing.T) {
 			},
 			expectedNumberOfContainers: 2,
 		},
+		{
+			name: "with zone awareness",
+			args: args{
+				elasticsearchImage: "es-image",
+				operatorImage:      "op-image",
+				zoneAwareness:      &esv1.ZoneAwareness{},
+			},
+			expectedNumberOfContainers: 2,
+		},
+		{
+			name: "with keystore resources and zone awareness",
+			args: args{
+				elasticsearchImage: "es-image",
+				operatorImage:      "op-image",
+				keystoreResources:  &keystore.Resources{},
+				zoneAwareness:      &esv1.ZoneAwareness{},
+			},
+			expectedNumberOfContainers: 3,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			containers, err := NewInitContainers(
 				volume.SecretVolume{},
 				tt.args.keystoreResources,
+				tt.args.zoneAwareness,
 			)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedNumberOfContainers, len(containers))
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package initcontainer
+
+import (
+	"path"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	esvolume "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ZoneAwarenessContainerName is the name of the container that sets the zone awareness node attribute
+	ZoneAwarenessContainerName = "elastic-internal-init-zone-awareness"
+
+	ZoneAwarenessScriptConfigKey = "zone-awareness.sh"
+
+	EnvZoneAwarenessTopologyKey = "ZONE_AWARENESS_TOPOLOGY_KEY"
+	EnvZoneAwarenessAttribute   = "ZONE_AWARENESS_ATTRIBUTE"
+	EnvConfigSourcePath         = "CONFIG_SOURCE_PATH"
+	EnvConfigTargetPath         = "CONFIG_TARGET_PATH"
+)
+
+// NewZoneAwarenessInitContainer creates an init container that reads the topology label of the k8s node the Pod is
+// scheduled on, and sets it as a node attribute in the Elasticsearch configuration.
+// It must run after the prepare-fs init container, which links the configuration file into the shared config volume.
+func NewZoneAwarenessInitContainer(zoneAwareness esv1.ZoneAwareness) corev1.Container {
+	privileged := false
+	return corev1.Container{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            ZoneAwarenessContainerName,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: &privileged,
+		},
+		Env: defaults.ExtendPodDownwardEnvVars(
+			corev1.EnvVar{Name: EnvZoneAwarenessTopologyKey, Value: zoneAwareness.TopologyKeyOrDefault()},
+			corev1.EnvVar{Name: EnvZoneAwarenessAttribute, Value: zoneAwareness.AttributeOrDefault()},
+			corev1.EnvVar{Name: EnvConfigSourcePath, Value: path.Join(settings.ConfigVolumeMountPath, settings.ConfigFileName)},
+			corev1.EnvVar{Name: EnvConfigTargetPath, Value: path.Join(EsConfigSharedVolume.InitContainerMountPath, settings.ConfigFileName)},
+		),
+		Command:      []string{"bash", "-c", path.Join(esvolume.ScriptsVolumeMountPath, ZoneAwarenessScriptConfigKey)},
+		VolumeMounts: []corev1.VolumeMount{EsConfigSharedVolume.InitContainerVolumeMount()},
+		Resources:    defaultResources,
+	}
+}
+
+// ZoneAwarenessScript reads the topology label from the k8s node object using the Pod service account, since node
+// labels cannot be exposed through the downward API.
+const ZoneAwarenessScript = `#!/usr/bin/env bash
+
+set -euo pipefail
+
+# This script reads the value of the $ZONE_AWARENESS_TOPOLOGY_KEY label of the k8s node $NODE_NAME, and sets it
+# as the $ZONE_AWARENESS_ATTRIBUTE node attribute in a copy of the Elasticsearch configuration file.
+# The Pod service account must be allowed to get k8s nodes.
+
+SERVICE_ACCOUNT_PATH=/var/run/secrets/kubernetes.io/serviceaccount
+
+if [[ ! -f ${SERVICE_ACCOUNT_PATH}/token ]]; then
+	>&2 echo "No service account token mounted, cannot read the labels of the k8s node ${NODE_NAME}"
+	exit 1
+fi
+
+node=$(curl --silent --show-error --fail \
+	--cacert ${SERVICE_ACCOUNT_PATH}/ca.crt \
+	--header "Authorization: Bearer $(cat ${SERVICE_ACCOUNT_PATH}/token)" \
+	"https://${KUBERNETES_SERVICE_HOST}:${KUBERNETES_SERVICE_PORT}/api/v1/nodes/${NODE_NAME}")
+
+# jq is not available in the Elasticsearch image, labels are the first key/value pairs of the node metadata
+value=$(echo "${node}" | tr -d '\n' | { grep -o "\"${ZONE_AWARENESS_TOPOLOGY_KEY}\": *\"[^\"]*\"" || true; } | head -n 1 | sed -e 's/.*: *"\(.*\)"$/\1/')
+
+if [[ -z "${value}" ]]; then
+	>&2 echo "Label ${ZONE_AWARENESS_TOPOLOGY_KEY} not found on k8s node ${NODE_NAME}"
+	exit 1
+fi
+
+echo "Setting node.attr.${ZONE_AWARENESS_ATTRIBUTE} to ${value}"
+
+# replace the link to the configuration file by a copy including the node attribute
+rm -f ${CONFIG_TARGET_PATH}
+cp ${CONFIG_SOURCE_PATH} ${CONFIG_TARGET_PATH}
+echo "" >> ${CONFIG_TARGET_PATH}
+echo "node.attr.${ZONE_AWARENESS_ATTRIBUTE}: ${value}" >> ${CONFIG_TARGET_PATH}
+`
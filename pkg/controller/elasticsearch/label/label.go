@@ -96,6 +96,11 @@ func IsDataNode(pod corev1.Pod) bool {
 	return NodeTypesDataLabelName.HasValue(true, pod.Labels)
 }
 
+// IsMLNode returns true if the pod has the ml node label
+func IsMLNode(pod corev1.Pod) bool {
+	return NodeTypesMLLabelName.HasValue(true, pod.Labels)
+}
+
 // ExtractVersion extracts the Elasticsearch version from the given labels.
 func ExtractVersion(labels map[string]string) (version.Version, error) {
 	return version.FromLabels(labels, VersionLabelName)
@@ -10,11 +10,49 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/shutdown"
 	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 )
 
 var log = ulog.Log.WithName("migrate-data")
 
+// ShardMigration implements the shutdown.Interface based on shard allocation filtering, for Elasticsearch
+// versions that do not support the node shutdown API.
+type ShardMigration struct {
+	es               esv1.Elasticsearch
+	allocationSetter esclient.AllocationSetter
+	shardLister      esclient.ShardLister
+}
+
+var _ shutdown.Interface = &ShardMigration{}
+
+// NewShardMigration creates a new ShardMigration struct that migrates data away from leaving nodes by setting
+// allocation exclusion filters.
+func NewShardMigration(es esv1.Elasticsearch, allocationSetter esclient.AllocationSetter, shardLister esclient.ShardLister) shutdown.Interface {
+	return &ShardMigration{
+		es:               es,
+		allocationSetter: allocationSetter,
+		shardLister:      shardLister,
+	}
+}
+
+// ReconcileShutdowns migrates data away from the leaving nodes by excluding them from shard allocation.
+func (sm *ShardMigration) ReconcileShutdowns(ctx context.Context, leavingNodes []string) error {
+	return MigrateData(ctx, sm.es, sm.allocationSetter, leavingNodes)
+}
+
+// ShutdownStatus returns COMPLETE if the given node does not hold any shards anymore, IN_PROGRESS otherwise.
+func (sm *ShardMigration) ShutdownStatus(ctx context.Context, podName string) (shutdown.NodeShutdownStatus, error) {
+	migrating, err := NodeMayHaveShard(ctx, sm.es, sm.shardLister, podName)
+	if err != nil {
+		return shutdown.NodeShutdownStatus{}, err
+	}
+	if migrating {
+		return shutdown.NodeShutdownStatus{Status: esclient.ShutdownInProgress}, nil
+	}
+	return shutdown.NodeShutdownStatus{Status: esclient.ShutdownComplete}, nil
+}
+
 // NodeMayHaveShard returns true if one of those condition is met:
 // - the given ES Pod is holding at least one shard (primary or replica)
 // - some shards in the cluster don't have a node assigned, in which case we can't be sure about the 1st condition
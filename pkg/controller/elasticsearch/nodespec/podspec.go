@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/container"
@@ -21,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/stackmon"
 	esvolume "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/pointer"
@@ -39,6 +41,7 @@ const defaultFsGroup = 1000
 
 // BuildPodTemplateSpec builds a new PodTemplateSpec for an Elasticsearch node.
 func BuildPodTemplateSpec(
+	client k8s.Client,
 	es esv1.Elasticsearch,
 	nodeSet esv1.NodeSet,
 	cfg settings.CanonicalConfig,
@@ -56,6 +59,7 @@ func BuildPodTemplateSpec(
 	initContainers, err := initcontainer.NewInitContainers(
 		transportCertificatesVolume(esv1.StatefulSet(es.Name, nodeSet.Name)),
 		keystoreResources,
+		es.Spec.ZoneAwareness,
 	)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
@@ -90,9 +94,39 @@ func BuildPodTemplateSpec(
 		WithInitContainerDefaults(corev1.EnvVar{Name: settings.HeadlessServiceName, Value: headlessServiceName}).
 		WithPreStopHook(*NewPreStopHook())
 
+	if es.Spec.ZoneAwareness != nil {
+		builder = withZoneAwareness(builder, es, nodeSet)
+	}
+
+	builder, err = stackmon.WithMonitoring(client, builder, es)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	return builder.PodTemplate, nil
 }
 
+// withZoneAwareness spreads the Pods of the NodeSet across the values of the zone awareness topology key, and mounts
+// the service account token used by the zone awareness init container to read the k8s node labels.
+func withZoneAwareness(builder *defaults.PodTemplateBuilder, es esv1.Elasticsearch, nodeSet esv1.NodeSet) *defaults.PodTemplateBuilder {
+	if nodeSet.PodTemplate.Spec.AutomountServiceAccountToken == nil {
+		// the service account token is not mounted by default, unless explicitly disabled by the user
+		automount := true
+		builder.PodTemplate.Spec.AutomountServiceAccountToken = &automount
+	}
+	return builder.WithTopologySpreadConstraints(corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       es.Spec.ZoneAwareness.TopologyKeyOrDefault(),
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				label.ClusterNameLabelName:     es.Name,
+				label.StatefulSetNameLabelName: esv1.StatefulSet(es.Name, nodeSet.Name),
+			},
+		},
+	})
+}
+
 func getDefaultContainerPorts(es esv1.Elasticsearch) []corev1.ContainerPort {
 	return []corev1.ContainerPort{
 		{Name: es.Spec.HTTP.Protocol(), ContainerPort: network.HTTPPort, Protocol: corev1.ProtocolTCP},
@@ -14,6 +14,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/initcontainer"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/pointer"
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
@@ -167,13 +168,92 @@ func TestBuildPodTemplateSpecWithDefaultSecurityContext(t *testing.T) {
 			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config)
 			require.NoError(t, err)
 
-			actual, err := BuildPodTemplateSpec(es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup)
+			actual, err := BuildPodTemplateSpec(k8s.NewFakeClient(), es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup)
 			require.NoError(t, err)
 			require.Equal(t, tt.wantSecurityContext, actual.Spec.SecurityContext)
 		})
 	}
 }
 
+func TestBuildPodTemplateSpecWithZoneAwareness(t *testing.T) {
+	varFalse := false
+	varTrue := true
+	userConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           2,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+	for _, tt := range []struct {
+		name                  string
+		zoneAwareness         *esv1.ZoneAwareness
+		userAutomountToken    *bool
+		userConstraints       []corev1.TopologySpreadConstraint
+		wantInitContainer     bool
+		wantAutomountToken    *bool
+		wantSpreadConstraints []corev1.TopologySpreadConstraint
+	}{
+		{
+			name:                  "zone awareness not enabled",
+			zoneAwareness:         nil,
+			wantInitContainer:     false,
+			wantAutomountToken:    &varFalse,
+			wantSpreadConstraints: nil,
+		},
+		{
+			name:               "zone awareness enabled",
+			zoneAwareness:      &esv1.ZoneAwareness{TopologyKey: "example.com/rack"},
+			wantInitContainer:  true,
+			wantAutomountToken: &varTrue,
+			wantSpreadConstraints: []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "example.com/rack",
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"elasticsearch.k8s.elastic.co/cluster-name":     "name",
+							"elasticsearch.k8s.elastic.co/statefulset-name": "name-es-nodeset-1",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:                  "zone awareness enabled, user-provided pod template settings",
+			zoneAwareness:         &esv1.ZoneAwareness{},
+			userAutomountToken:    &varFalse,
+			userConstraints:       []corev1.TopologySpreadConstraint{userConstraint},
+			wantInitContainer:     true,
+			wantAutomountToken:    &varFalse,
+			wantSpreadConstraints: []corev1.TopologySpreadConstraint{userConstraint},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			es := *sampleES.DeepCopy()
+			es.Spec.ZoneAwareness = tt.zoneAwareness
+			es.Spec.NodeSets[0].PodTemplate.Spec.AutomountServiceAccountToken = tt.userAutomountToken
+			es.Spec.NodeSets[0].PodTemplate.Spec.TopologySpreadConstraints = tt.userConstraints
+
+			ver := version.MustParse(es.Spec.Version)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config)
+			require.NoError(t, err)
+
+			actual, err := BuildPodTemplateSpec(k8s.NewFakeClient(), es, es.Spec.NodeSets[0], cfg, nil, false)
+			require.NoError(t, err)
+
+			hasInitContainer := false
+			for _, c := range actual.Spec.InitContainers {
+				if c.Name == initcontainer.ZoneAwarenessContainerName {
+					hasInitContainer = true
+				}
+			}
+			require.Equal(t, tt.wantInitContainer, hasInitContainer)
+			require.Equal(t, tt.wantAutomountToken, actual.Spec.AutomountServiceAccountToken)
+			require.Equal(t, tt.wantSpreadConstraints, actual.Spec.TopologySpreadConstraints)
+		})
+	}
+}
+
 func TestBuildPodTemplateSpec(t *testing.T) {
 	nodeSet := sampleES.Spec.NodeSets[0]
 	ver, err := version.Parse(sampleES.Spec.Version)
@@ -181,7 +261,7 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *nodeSet.Config)
 	require.NoError(t, err)
 
-	actual, err := BuildPodTemplateSpec(sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false)
+	actual, err := BuildPodTemplateSpec(k8s.NewFakeClient(), sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false)
 	require.NoError(t, err)
 
 	// build expected PodTemplateSpec
@@ -197,6 +277,7 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 	initContainers, err := initcontainer.NewInitContainers(
 		transportCertificatesVolume(sampleES.Name),
 		nil,
+		nil,
 	)
 	require.NoError(t, err)
 	// should be patched with volume and env
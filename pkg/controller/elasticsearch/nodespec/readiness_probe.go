@@ -77,6 +77,13 @@ ENDPOINT="${READINESS_PROBE_PROTOCOL:-https}://${LOOPBACK}:9200/"
 status=$(curl -o /dev/null -w "%{http_code}" --max-time ${READINESS_PROBE_TIMEOUT} -XGET -g -s -k ${BASIC_AUTH} $ENDPOINT)
 curl_rc=$?
 
+# retry with the fallback password while a password rotation is propagated to the node
+if [[ ${curl_rc} -eq 0 ]] && [[ ${status} == "401" ]] && [ -n "${PROBE_USERNAME}" ] && [ -s "${PROBE_FALLBACK_PASSWORD_PATH}" ]; then
+  PROBE_FALLBACK_PASSWORD=$(<${PROBE_FALLBACK_PASSWORD_PATH})
+  status=$(curl -o /dev/null -w "%{http_code}" --max-time ${READINESS_PROBE_TIMEOUT} -XGET -g -s -k -u "${PROBE_USERNAME}:${PROBE_FALLBACK_PASSWORD}" $ENDPOINT)
+  curl_rc=$?
+fi
+
 if [[ ${curl_rc} -ne 0 ]]; then
   fail "\"curl_rc\": \"${curl_rc}\""
 fi
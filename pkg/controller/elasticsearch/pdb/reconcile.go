@@ -6,83 +6,149 @@ package pdb
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/set"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-// Reconcile ensures that a PodDisruptionBudget exists for this cluster, inheriting the spec content.
-// The default PDB we setup dynamically adapts MinAvailable to the number of nodes in the cluster.
+var log = ulog.Log.WithName("pdb")
+
+const (
+	// masterGroup holds master-eligible nodes, whatever their other roles.
+	masterGroup = "master"
+	// coordinatingGroup holds nodes that are neither master-eligible nor hold data
+	// (coordinating-only, ingest, ml or transform nodes).
+	coordinatingGroup = "coordinating"
+)
+
+// dataGroups associates a group name to the label of the data role its nodes hold, by order of priority.
+var dataGroups = []struct {
+	name  string
+	label common.TrueFalseLabel
+}{
+	{name: "data", label: label.NodeTypesDataLabelName},
+	{name: "data-hot", label: label.NodeTypesDataHotLabelName},
+	{name: "data-warm", label: label.NodeTypesDataWarmLabelName},
+	{name: "data-cold", label: label.NodeTypesDataColdLabelName},
+	{name: "data-content", label: label.NodeTypesDataContentLabelName},
+}
+
+// roleGroup is a set of StatefulSets whose Pods are covered by the same default PDB.
+type roleGroup struct {
+	name         string
+	statefulSets sset.StatefulSetList
+}
+
+// Reconcile ensures that the PodDisruptionBudgets of this cluster exist, inheriting the spec content.
+// By default, we setup one PDB per group of node roles (master nodes, data tiers, other nodes), nodes sharing the
+// master role or a data role being in the same group, and dynamically adapt its MaxUnavailable to the cluster health,
+// to the location of the shards without replicas and to the topology of the group.
+// The shards are retrieved with the given ShardLister, which is nil if Elasticsearch cannot be reached.
+// If the spec provides its own PDB spec, a single PDB with that spec is created instead.
 // If the spec has disabled the default PDB, it will ensure none exist.
-func Reconcile(k8sClient k8s.Client, es esv1.Elasticsearch, statefulSets sset.StatefulSetList) error {
-	expected, err := expectedPDB(es, statefulSets)
+// Existing PDBs are updated in place, and the ones that are not expected anymore are deleted.
+func Reconcile(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	es esv1.Elasticsearch,
+	statefulSets sset.StatefulSetList,
+	shardLister esclient.ShardLister,
+) error {
+	expected, err := expectedPDBs(ctx, es, statefulSets, shardLister)
 	if err != nil {
 		return err
 	}
-	if expected == nil {
-		return deleteDefaultPDB(k8sClient, es)
+	for i := range expected {
+		if err := reconcilePDB(k8sClient, &expected[i]); err != nil {
+			return err
+		}
 	}
+	return deleteUnexpectedPDBs(k8sClient, es, expected)
+}
 
+// reconcilePDB creates or updates the given PDB.
+func reconcilePDB(k8sClient k8s.Client, expected *v1beta1.PodDisruptionBudget) error {
 	// label the PDB with a hash of its content, for comparison purposes
 	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected)
 
-	// reconcile actual vs. expected
-	var actual v1beta1.PodDisruptionBudget
-	err = k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(expected), &actual)
-	if err != nil && apierrors.IsNotFound(err) {
-		return k8sClient.Create(context.Background(), expected)
-	}
+	reconciled := &v1beta1.PodDisruptionBudget{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Client:     k8sClient,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return hash.GetTemplateHashLabel(expected.Labels) != hash.GetTemplateHashLabel(reconciled.Labels)
+		},
+		UpdateReconciled: func() {
+			// PDBs can be updated in place since k8s 1.15
+			reconciled.Labels = expected.Labels
+			reconciled.Annotations = expected.Annotations
+			reconciled.Spec = expected.Spec
+		},
+	})
+}
 
-	if hash.GetTemplateHashLabel(expected.Labels) != hash.GetTemplateHashLabel(actual.Labels) {
-		// Actual does not match expected, let's update the PDB.
-		// PDB Spec cannot be updated, we'll have to delete then recreate.
-		// Which means there is a time window in between where we don't have a PDB anymore.
-		// TODO: this is not true anymore starting k8s 1.15+ and this PR https://github.com/kubernetes/kubernetes/pull/69867
-		if err := deleteDefaultPDB(k8sClient, es); err != nil {
+// deleteUnexpectedPDBs deletes the PDBs owned by this cluster that are not part of the expected ones.
+// This includes the single cluster-wide PDB managed by previous versions of the operator.
+func deleteUnexpectedPDBs(k8sClient k8s.Client, es esv1.Elasticsearch, expected []v1beta1.PodDisruptionBudget) error {
+	var actual v1beta1.PodDisruptionBudgetList
+	if err := k8sClient.List(
+		context.Background(),
+		&actual,
+		client.InNamespace(es.Namespace),
+		client.MatchingLabels{label.ClusterNameLabelName: es.Name},
+	); err != nil {
+		return err
+	}
+	for i := range actual.Items {
+		pdb := actual.Items[i]
+		if isExpected(pdb, expected) || !metav1.IsControlledBy(&pdb, &es) {
+			continue
+		}
+		if err := k8sClient.Delete(context.Background(), &pdb); err != nil && !apierrors.IsNotFound(err) {
 			return err
 		}
-		return k8sClient.Create(context.Background(), expected)
 	}
-
 	return nil
 }
 
-// deleteDefaultPDB deletes the default pdb if it exists.
-func deleteDefaultPDB(k8sClient k8s.Client, es esv1.Elasticsearch) error {
-	// we do this by getting first because that is a local cache read,
-	// versus a Delete call, which would hit the API.
-	pdb := v1beta1.PodDisruptionBudget{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: es.Namespace,
-			Name:      esv1.DefaultPodDisruptionBudget(es.Name),
-		},
-	}
-	if err := k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&pdb), &pdb); err != nil && !apierrors.IsNotFound(err) {
-		return err
-	} else if apierrors.IsNotFound(err) {
-		// already deleted, which is fine
-		return nil
-	}
-	if err := k8sClient.Delete(context.Background(), &pdb); err != nil && !apierrors.IsNotFound(err) {
-		return err
+func isExpected(pdb v1beta1.PodDisruptionBudget, expected []v1beta1.PodDisruptionBudget) bool {
+	for _, e := range expected {
+		if e.Name == pdb.Name {
+			return true
+		}
 	}
-	return nil
+	return false
 }
 
-// expectedPDB returns a PDB according to the given ES spec.
-// It may return nil if the PDB has been explicitly disabled in the ES spec.
-func expectedPDB(es esv1.Elasticsearch, statefulSets sset.StatefulSetList) (*v1beta1.PodDisruptionBudget, error) {
+// expectedPDBs returns the PDBs to create according to the given ES spec.
+// It may return no PDB if the PDB has been explicitly disabled in the ES spec.
+func expectedPDBs(
+	ctx context.Context,
+	es esv1.Elasticsearch,
+	statefulSets sset.StatefulSetList,
+	shardLister esclient.ShardLister,
+) ([]v1beta1.PodDisruptionBudget, error) {
 	template := es.Spec.PodDisruptionBudget.DeepCopy()
 	if template.IsDisabled() {
 		return nil, nil
@@ -91,80 +157,277 @@ func expectedPDB(es esv1.Elasticsearch, statefulSets sset.StatefulSetList) (*v1b
 		template = &commonv1.PodDisruptionBudgetTemplate{}
 	}
 
+	if template.Spec.Selector != nil || template.Spec.MaxUnavailable != nil || template.Spec.MinAvailable != nil {
+		// use the user-defined spec in a single PDB
+		expected, err := newPDB(es, *template, esv1.DefaultPodDisruptionBudget(es.Name))
+		if err != nil {
+			return nil, err
+		}
+		expected.Spec = template.Spec
+		return []v1beta1.PodDisruptionBudget{expected}, nil
+	}
+
+	// set our default spec, one PDB per group of node roles
+	copies := retrieveSingleCopies(ctx, es, shardLister)
+	groups := groupByRoles(statefulSets)
+	pdbs := make([]v1beta1.PodDisruptionBudget, 0, len(groups))
+	for _, group := range groups {
+		expected, err := newPDB(es, *template, esv1.RoleGroupPodDisruptionBudget(es.Name, group.name))
+		if err != nil {
+			return nil, err
+		}
+		expected.Spec = buildPDBSpec(es, statefulSets, group, copies)
+		pdbs = append(pdbs, expected)
+	}
+	return pdbs, nil
+}
+
+// newPDB returns a PDB with the given name and no spec, inheriting the user-provided metadata.
+func newPDB(es esv1.Elasticsearch, template commonv1.PodDisruptionBudgetTemplate, name string) (v1beta1.PodDisruptionBudget, error) {
 	expected := v1beta1.PodDisruptionBudget{
-		ObjectMeta: template.ObjectMeta,
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
 	}
 
 	// inherit user-provided ObjectMeta, but set our own name & namespace
-	expected.Name = esv1.DefaultPodDisruptionBudget(es.Name)
+	expected.Name = name
 	expected.Namespace = es.Namespace
 	// and append our labels
 	expected.Labels = maps.MergePreservingExistingKeys(expected.Labels, label.NewLabels(k8s.ExtractNamespacedName(&es)))
 	// set owner reference for deletion upon ES resource deletion
 	if err := controllerutil.SetControllerReference(&es, &expected, scheme.Scheme); err != nil {
-		return nil, err
+		return v1beta1.PodDisruptionBudget{}, err
 	}
+	return expected, nil
+}
 
-	if template.Spec.Selector != nil || template.Spec.MaxUnavailable != nil || template.Spec.MinAvailable != nil {
-		// use the user-defined spec
-		expected.Spec = template.Spec
-	} else {
-		// set our default spec
-		expected.Spec = buildPDBSpec(es, statefulSets)
+// groupName returns the name of the role group made of the given StatefulSets, after their role of highest priority.
+func groupName(statefulSets sset.StatefulSetList) string {
+	for _, statefulSet := range statefulSets {
+		if label.IsMasterNodeSet(statefulSet) {
+			return masterGroup
+		}
+	}
+	for _, dataGroup := range dataGroups {
+		for _, statefulSet := range statefulSets {
+			if dataGroup.label.HasValue(true, statefulSet.Spec.Template.Labels) {
+				return dataGroup.name
+			}
+		}
+	}
+	return coordinatingGroup
+}
+
+// dataRoles returns the names of the data groups matching the data roles of the given StatefulSet.
+func dataRoles(statefulSet appsv1.StatefulSet) []string {
+	var roles []string
+	for _, dataGroup := range dataGroups {
+		if dataGroup.label.HasValue(true, statefulSet.Spec.Template.Labels) {
+			roles = append(roles, dataGroup.name)
+		}
+	}
+	return roles
+}
+
+// shareRoles returns true if the Pods of the given StatefulSets must not be disrupted at the same time: if they are
+// both master-eligible, if they share a data role, or if they are both neither master-eligible nor hold data.
+// Nodes with the generic data role hold the data of all the tiers.
+func shareRoles(a, b appsv1.StatefulSet) bool {
+	aMaster, bMaster := label.IsMasterNodeSet(a), label.IsMasterNodeSet(b)
+	if aMaster && bMaster {
+		return true
+	}
+	aData, bData := dataRoles(a), dataRoles(b)
+	if len(aData) == 0 || len(bData) == 0 {
+		return len(aData) == 0 && len(bData) == 0 && !aMaster && !bMaster
 	}
+	for _, aRole := range aData {
+		for _, bRole := range bData {
+			if aRole == bRole || aRole == dataGroups[0].name || bRole == dataGroups[0].name {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-	return &expected, nil
+// groupByRoles splits the given StatefulSets into role groups, sorted by order of priority. StatefulSets sharing the
+// master role or a data role, directly or through other StatefulSets, belong to the same group: allowing the
+// disruption of one Pod per group then never allows the disruption of two Pods holding the same data or master role.
+func groupByRoles(statefulSets sset.StatefulSetList) []roleGroup {
+	// union-find of the indices of the StatefulSets sharing roles
+	parents := make([]int, len(statefulSets))
+	for i := range parents {
+		parents[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parents[i] != i {
+			parents[i] = root(parents[i])
+		}
+		return parents[i]
+	}
+	for i := range statefulSets {
+		for j := i + 1; j < len(statefulSets); j++ {
+			if shareRoles(statefulSets[i], statefulSets[j]) {
+				parents[root(j)] = root(i)
+			}
+		}
+	}
+	byRoot := make(map[int]sset.StatefulSetList)
+	for i, statefulSet := range statefulSets {
+		byRoot[root(i)] = append(byRoot[root(i)], statefulSet)
+	}
+
+	byName := make(map[string]sset.StatefulSetList, len(byRoot))
+	for _, groupSsets := range byRoot {
+		byName[groupName(groupSsets)] = groupSsets
+	}
+
+	names := []string{masterGroup}
+	for _, dataGroup := range dataGroups {
+		names = append(names, dataGroup.name)
+	}
+	names = append(names, coordinatingGroup)
+
+	groups := make([]roleGroup, 0, len(byName))
+	for _, name := range names {
+		if groupSsets, exists := byName[name]; exists {
+			groups = append(groups, roleGroup{name: name, statefulSets: groupSsets})
+		}
+	}
+	return groups
+}
+
+// holdsData returns true if at least one of the StatefulSets of the group has a data role.
+func (g roleGroup) holdsData() bool {
+	for _, statefulSet := range g.statefulSets {
+		for _, dataGroup := range dataGroups {
+			if dataGroup.label.HasValue(true, statefulSet.Spec.Template.Labels) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-// buildPDBSpec returns a PDBSpec computed from the current StatefulSets,
+// buildPDBSpec returns a PDBSpec for the given role group computed from the current StatefulSets,
 // considering the cluster health and topology.
-func buildPDBSpec(es esv1.Elasticsearch, statefulSets sset.StatefulSetList) v1beta1.PodDisruptionBudgetSpec {
-	// compute MinAvailable based on the maximum number of Pods we're supposed to have
-	nodeCount := statefulSets.ExpectedNodeCount()
-	// maybe allow some Pods to be disrupted
-	minAvailable := nodeCount - allowedDisruptions(es, statefulSets)
+func buildPDBSpec(
+	es esv1.Elasticsearch,
+	statefulSets sset.StatefulSetList,
+	group roleGroup,
+	copies singleCopies,
+) v1beta1.PodDisruptionBudgetSpec {
+	ssetNames := group.statefulSets.Names().AsSlice()
+	sort.Strings(ssetNames)
 
-	minAvailableIntStr := intstr.IntOrString{Type: intstr.Int, IntVal: minAvailable}
+	// maybe allow some Pods to be disrupted
+	maxUnavailable := intstr.FromInt(int(allowedDisruptions(es, statefulSets, group, copies)))
 
 	return v1beta1.PodDisruptionBudgetSpec{
-		// match all pods for this cluster
+		// match all pods of the StatefulSets in this group
 		Selector: &metav1.LabelSelector{
 			MatchLabels: map[string]string{
 				label.ClusterNameLabelName: es.Name,
 			},
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      label.StatefulSetNameLabelName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   ssetNames,
+				},
+			},
 		},
-		MinAvailable: &minAvailableIntStr,
-		// MaxUnavailable can only be used if the selector matches a builtin controller selector
-		// (eg. Deployments, StatefulSets, etc.). We cannot use it with our own cluster-name selector.
-		MaxUnavailable: nil,
+		// MaxUnavailable can be used since all the selected Pods are managed by StatefulSets
+		MaxUnavailable: &maxUnavailable,
 	}
 }
 
-// allowedDisruptions returns the number of Pods that we allow to be disrupted while keeping the cluster healthy.
-func allowedDisruptions(es esv1.Elasticsearch, actualSsets sset.StatefulSetList) int32 {
+// allowedDisruptions returns the number of Pods of the given role group that we allow to be disrupted
+// while keeping the cluster healthy.
+func allowedDisruptions(es esv1.Elasticsearch, actualSsets sset.StatefulSetList, group roleGroup, copies singleCopies) int32 {
 	if actualSsets.ExpectedNodeCount() == 1 {
 		// single node cluster (not highly-available)
 		// allow the node to be disrupted to ensure K8s nodes operations can be performed
 		return 1
 	}
-	if es.Status.Health != esv1.ElasticsearchGreenHealth {
-		// A non-green cluster may become red if we disrupt one node, don't allow it.
+	if group.holdsData() && es.Status.Health != esv1.ElasticsearchGreenHealth {
+		// A non-green cluster may become red if we disrupt one data node, don't allow it.
 		// The health information we're using here may be out-of-date, that's best effort.
 		return 0
 	}
-	if actualSsets.ExpectedMasterNodesCount() == 1 {
+	if group.holdsData() && copies.heldBy(group) {
+		// A green cluster becomes red if we disrupt the single copy of a shard, typically the primary of an index
+		// without replicas, don't allow it. The health alone does not account for these shards.
+		return 0
+	}
+	if group.statefulSets.ExpectedMasterNodesCount() == 1 {
+		// All master nodes belong to the same group.
 		// There's a risk the single master of the cluster gets removed, don't allow it.
 		return 0
 	}
-	if actualSsets.ExpectedDataNodesCount() == 1 {
-		// There's a risk the single data node of the cluster gets removed, don't allow it.
+	if group.holdsData() && group.statefulSets.ExpectedNodeCount() == 1 {
+		// There's a risk the single data node of this group gets removed, don't allow it.
 		return 0
 	}
-	if actualSsets.ExpectedIngestNodesCount() == 1 {
+	if actualSsets.ExpectedIngestNodesCount() == 1 && group.statefulSets.ExpectedIngestNodesCount() == 1 {
 		// There's a risk the single ingest node of the cluster gets removed, don't allow it.
 		return 0
 	}
-	// Allow one pod (only) to be disrupted on a healthy cluster.
+	// Allow one pod (only) of the group to be disrupted.
 	// We could technically allow more, but the cluster health freshness would become a bigger problem.
 	return 1
 }
+
+// singleCopies gives the nodes holding the only copy of a shard.
+type singleCopies struct {
+	// known is false if the shards could not be retrieved from Elasticsearch.
+	known bool
+	// nodes holds the names of the nodes holding the only started copy of a shard.
+	nodes set.StringSet
+}
+
+// retrieveSingleCopies returns the nodes holding the only started copy of a shard. The shards may have moved once the
+// PDBs are updated, that's best effort.
+func retrieveSingleCopies(ctx context.Context, es esv1.Elasticsearch, shardLister esclient.ShardLister) singleCopies {
+	if shardLister == nil {
+		return singleCopies{}
+	}
+	shards, err := shardLister.GetShards(ctx)
+	if err != nil {
+		log.Error(err, "Cannot retrieve the shards of the cluster", "namespace", es.Namespace, "es_name", es.Name)
+		return singleCopies{}
+	}
+	// a relocating shard is still started on its source node
+	nodesByShard := make(map[string][]string)
+	for _, shard := range shards {
+		if shard.IsStarted() || shard.IsRelocating() {
+			nodesByShard[shard.Key()] = append(nodesByShard[shard.Key()], shard.NodeName)
+		}
+	}
+	copies := singleCopies{known: true, nodes: set.Make()}
+	for _, nodes := range nodesByShard {
+		if len(nodes) == 1 {
+			copies.nodes.Add(nodes[0])
+		}
+	}
+	return copies
+}
+
+// heldBy returns true if a Pod of the given role group may hold the only copy of a shard. The Pods are named after
+// their StatefulSet.
+func (c singleCopies) heldBy(group roleGroup) bool {
+	if !c.known {
+		return true
+	}
+	ssetNames := group.statefulSets.Names()
+	for node := range c.nodes {
+		if !strings.Contains(node, "-") {
+			continue
+		}
+		if ssetName, _, err := sset.StatefulSetName(node); err == nil && ssetNames.Has(ssetName) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ package pdb
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -14,21 +15,49 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/comparison"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/hash"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/migration"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/set"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/policy/v1beta1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+func groupPDB(group string, maxUnavailable int, ssetNames ...string) *v1beta1.PodDisruptionBudget {
+	return &v1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      esv1.RoleGroupPodDisruptionBudget("cluster", group),
+			Namespace: "ns",
+			Labels:    map[string]string{label.ClusterNameLabelName: "cluster", common.TypeLabelName: label.Type},
+		},
+		Spec: v1beta1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label.ClusterNameLabelName: "cluster",
+				},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      label.StatefulSetNameLabelName,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   ssetNames,
+					},
+				},
+			},
+			MaxUnavailable: intStrPtr(intstr.FromInt(maxUnavailable)),
+		},
+	}
+}
+
 func TestReconcile(t *testing.T) {
-	defaultPDB := func() *v1beta1.PodDisruptionBudget {
+	legacyPDB := func() *v1beta1.PodDisruptionBudget {
 		return &v1beta1.PodDisruptionBudget{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      esv1.DefaultPodDisruptionBudget("cluster"),
@@ -42,90 +71,147 @@ func TestReconcile(t *testing.T) {
 						label.ClusterNameLabelName: "cluster",
 					},
 				},
-				MaxUnavailable: nil,
 			},
 		}
 	}
-	defaultEs := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"}}
+	defaultEs := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+		Status:     esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth},
+	}
+	// owned patches the PDB we want with ownerRef and hash label
+	owned := func(pdb *v1beta1.PodDisruptionBudget) *v1beta1.PodDisruptionBudget {
+		return withHashLabel(withOwnerRef(pdb, defaultEs))
+	}
+	masterData := sset.StatefulSetList{sset.TestSset{Name: "default", Namespace: "ns", ClusterName: "cluster", Replicas: 3, Master: true, Data: true}.Build()}
 	type args struct {
 		k8sClient    k8s.Client
 		es           esv1.Elasticsearch
 		statefulSets sset.StatefulSetList
+		shards       esclient.Shards
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantPDB *v1beta1.PodDisruptionBudget
+		name     string
+		args     args
+		wantPDBs []*v1beta1.PodDisruptionBudget
 	}{
 		{
-			name: "no existing pdb: should create one",
+			name: "no existing pdb: should create one per role group",
 			args: args{
-				k8sClient:    k8s.NewFakeClient(),
-				es:           defaultEs,
-				statefulSets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Master: true, Data: true}.Build()},
+				k8sClient: k8s.NewFakeClient(),
+				es:        defaultEs,
+				statefulSets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Namespace: "ns", ClusterName: "cluster", Replicas: 3, Master: true}.Build(),
+					sset.TestSset{Name: "data-a", Namespace: "ns", ClusterName: "cluster", Replicas: 2, Data: true}.Build(),
+					sset.TestSset{Name: "data-b", Namespace: "ns", ClusterName: "cluster", Replicas: 2, Data: true}.Build(),
+					sset.TestSset{Name: "coord", Namespace: "ns", ClusterName: "cluster", Replicas: 2}.Build(),
+				},
+			},
+			wantPDBs: []*v1beta1.PodDisruptionBudget{
+				owned(groupPDB("coordinating", 1, "coord")),
+				owned(groupPDB("data", 1, "data-a", "data-b")),
+				owned(groupPDB("master", 1, "masters")),
+			},
+		},
+		{
+			name: "shard without replicas: should not allow the disruption of its role group",
+			args: args{
+				k8sClient: k8s.NewFakeClient(),
+				es:        defaultEs,
+				statefulSets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Namespace: "ns", ClusterName: "cluster", Replicas: 3, Master: true}.Build(),
+					sset.TestSset{Name: "data", Namespace: "ns", ClusterName: "cluster", Replicas: 2, Data: true}.Build(),
+				},
+				shards: esclient.Shards{
+					{Index: "index", Shard: "0", State: esclient.STARTED, NodeName: "data-1", Type: esclient.Primary},
+				},
+			},
+			wantPDBs: []*v1beta1.PodDisruptionBudget{
+				owned(groupPDB("data", 0, "data")),
+				owned(groupPDB("master", 1, "masters")),
 			},
-			wantPDB: defaultPDB(),
 		},
 		{
 			name: "pdb already exists: should remain unmodified",
 			args: args{
-				k8sClient:    k8s.NewFakeClient(withHashLabel(withOwnerRef(defaultPDB(), defaultEs))),
+				k8sClient:    k8s.NewFakeClient(owned(groupPDB("master", 1, "default"))),
 				es:           defaultEs,
-				statefulSets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Master: true, Data: true}.Build()},
+				statefulSets: masterData,
+			},
+			wantPDBs: []*v1beta1.PodDisruptionBudget{owned(groupPDB("master", 1, "default"))},
+		},
+		{
+			name: "pdb needs a MaxUnavailable update: should be updated in place",
+			args: args{
+				k8sClient:    k8s.NewFakeClient(owned(groupPDB("master", 1, "default"))),
+				es:           esv1.Elasticsearch{ObjectMeta: defaultEs.ObjectMeta},
+				statefulSets: masterData,
 			},
-			wantPDB: defaultPDB(),
+			wantPDBs: []*v1beta1.PodDisruptionBudget{owned(groupPDB("master", 0, "default"))},
 		},
 		{
-			name: "pdb needs a MinAvailable update",
+			name: "legacy cluster-wide pdb: should be replaced by role group pdbs",
 			args: args{
-				k8sClient:    k8s.NewFakeClient(defaultPDB()),
+				k8sClient:    k8s.NewFakeClient(withOwnerRef(legacyPDB(), defaultEs)),
 				es:           defaultEs,
-				statefulSets: sset.StatefulSetList{sset.TestSset{Replicas: 5, Master: true, Data: true}.Build()},
+				statefulSets: masterData,
 			},
-			wantPDB: &v1beta1.PodDisruptionBudget{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      esv1.DefaultPodDisruptionBudget("cluster"),
-					Namespace: "ns",
-					Labels:    map[string]string{label.ClusterNameLabelName: "cluster", common.TypeLabelName: label.Type},
-				},
-				Spec: v1beta1.PodDisruptionBudgetSpec{
-					MinAvailable: intStrPtr(intstr.FromInt(5)),
-					Selector: &metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							label.ClusterNameLabelName: "cluster",
-						},
-					},
-					MaxUnavailable: nil,
+			wantPDBs: []*v1beta1.PodDisruptionBudget{owned(groupPDB("master", 1, "default"))},
+		},
+		{
+			name: "pdb of a role group that does not exist anymore: should be deleted",
+			args: args{
+				k8sClient:    k8s.NewFakeClient(withOwnerRef(groupPDB("data", 1, "data"), defaultEs)),
+				es:           defaultEs,
+				statefulSets: masterData,
+			},
+			wantPDBs: []*v1beta1.PodDisruptionBudget{owned(groupPDB("master", 1, "default"))},
+		},
+		{
+			name: "pdb not owned by the cluster: should be left untouched",
+			args: args{
+				k8sClient:    k8s.NewFakeClient(legacyPDB()),
+				es:           defaultEs,
+				statefulSets: masterData,
+			},
+			wantPDBs: []*v1beta1.PodDisruptionBudget{legacyPDB(), owned(groupPDB("master", 1, "default"))},
+		},
+		{
+			name: "user-provided pdb spec: should replace role group pdbs",
+			args: args{
+				k8sClient: k8s.NewFakeClient(withOwnerRef(groupPDB("master", 1, "default"), defaultEs)),
+				es: esv1.Elasticsearch{
+					ObjectMeta: defaultEs.ObjectMeta,
+					Spec: esv1.ElasticsearchSpec{PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{
+						Spec: legacyPDB().Spec,
+					}},
 				},
+				statefulSets: masterData,
 			},
+			wantPDBs: []*v1beta1.PodDisruptionBudget{owned(legacyPDB())},
 		},
 		{
-			name: "pdb disabled in the ES spec: should delete the existing one",
+			name: "pdb disabled in the ES spec: should delete the existing ones",
 			args: args{
-				k8sClient: k8s.NewFakeClient(defaultPDB()),
+				k8sClient: k8s.NewFakeClient(withOwnerRef(groupPDB("master", 1, "default"), defaultEs)),
 				es: esv1.Elasticsearch{
-					ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"},
+					ObjectMeta: defaultEs.ObjectMeta,
 					Spec:       esv1.ElasticsearchSpec{PodDisruptionBudget: &commonv1.PodDisruptionBudgetTemplate{}},
 				},
-				statefulSets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Master: true, Data: true}.Build()},
+				statefulSets: masterData,
 			},
-			wantPDB: nil,
+			wantPDBs: nil,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := Reconcile(tt.args.k8sClient, tt.args.es, tt.args.statefulSets)
+			err := Reconcile(context.Background(), tt.args.k8sClient, tt.args.es, tt.args.statefulSets, migration.NewFakeShardLister(tt.args.shards))
+			require.NoError(t, err)
+			var retrieved v1beta1.PodDisruptionBudgetList
+			err = tt.args.k8sClient.List(context.Background(), &retrieved, client.InNamespace(tt.args.es.Namespace))
 			require.NoError(t, err)
-			pdbNsn := types.NamespacedName{Namespace: tt.args.es.Namespace, Name: esv1.DefaultPodDisruptionBudget(tt.args.es.Name)}
-			var retrieved v1beta1.PodDisruptionBudget
-			err = tt.args.k8sClient.Get(context.Background(), pdbNsn, &retrieved)
-			if tt.wantPDB == nil {
-				require.True(t, errors.IsNotFound(err))
-			} else {
-				// patch the PDB we want with ownerRef and hash label
-				tt.wantPDB = withHashLabel(withOwnerRef(tt.wantPDB, tt.args.es))
-				require.NoError(t, err)
-				comparison.RequireEqual(t, tt.wantPDB, &retrieved)
+			require.Len(t, retrieved.Items, len(tt.wantPDBs))
+			for i, want := range tt.wantPDBs {
+				comparison.RequireEqual(t, want, &retrieved.Items[i])
 			}
 		})
 	}
@@ -147,7 +233,7 @@ func intStrPtr(intStr intstr.IntOrString) *intstr.IntOrString {
 	return &intStr
 }
 
-func Test_expectedPDB(t *testing.T) {
+func Test_expectedPDBs(t *testing.T) {
 	type args struct {
 		es           esv1.Elasticsearch
 		statefulSets sset.StatefulSetList
@@ -155,7 +241,7 @@ func Test_expectedPDB(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want *v1beta1.PodDisruptionBudget
+		want []*v1beta1.PodDisruptionBudget
 	}{
 		{
 			name: "PDB disabled in the spec",
@@ -166,27 +252,18 @@ func Test_expectedPDB(t *testing.T) {
 			want: nil,
 		},
 		{
-			name: "Build default PDB",
+			name: "Build default PDBs",
 			args: args{
-				es:           esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"}},
-				statefulSets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Master: true, Data: true}.Build()},
-			},
-			want: &v1beta1.PodDisruptionBudget{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      esv1.DefaultPodDisruptionBudget("cluster"),
-					Namespace: "ns",
-					Labels:    map[string]string{label.ClusterNameLabelName: "cluster", common.TypeLabelName: label.Type},
-				},
-				Spec: v1beta1.PodDisruptionBudgetSpec{
-					MinAvailable: intStrPtr(intstr.FromInt(3)),
-					Selector: &metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							label.ClusterNameLabelName: "cluster",
-						},
-					},
-					MaxUnavailable: nil,
+				es: esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "ns"}},
+				statefulSets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Replicas: 3, Master: true}.Build(),
+					sset.TestSset{Name: "data", Replicas: 3, Data: true}.Build(),
 				},
 			},
+			want: []*v1beta1.PodDisruptionBudget{
+				groupPDB("master", 1, "masters"),
+				groupPDB("data", 0, "data"),
+			},
 		},
 		{
 			name: "Inherit user-provided labels",
@@ -200,24 +277,22 @@ func Test_expectedPDB(t *testing.T) {
 							}},
 					},
 				},
-				statefulSets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Master: true, Data: true}.Build()},
-			},
-			want: &v1beta1.PodDisruptionBudget{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      esv1.DefaultPodDisruptionBudget("cluster"),
-					Namespace: "ns",
-					Labels:    map[string]string{"a": "b", "c": "d", label.ClusterNameLabelName: "cluster", common.TypeLabelName: label.Type},
-				},
-				Spec: v1beta1.PodDisruptionBudgetSpec{
-					MinAvailable: intStrPtr(intstr.FromInt(3)),
-					Selector: &metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							label.ClusterNameLabelName: "cluster",
-						},
-					},
-					MaxUnavailable: nil,
+				statefulSets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Replicas: 3, Master: true}.Build(),
+					sset.TestSset{Name: "data", Replicas: 3, Data: true}.Build(),
 				},
 			},
+			want: func() []*v1beta1.PodDisruptionBudget {
+				pdbs := []*v1beta1.PodDisruptionBudget{
+					groupPDB("master", 1, "masters"),
+					groupPDB("data", 0, "data"),
+				}
+				for _, pdb := range pdbs {
+					pdb.Labels["a"] = "b"
+					pdb.Labels["c"] = "d"
+				}
+				return pdbs
+			}(),
 		},
 		{
 			name: "Use user-provided PDB spec",
@@ -231,37 +306,124 @@ func Test_expectedPDB(t *testing.T) {
 				},
 				statefulSets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Master: true, Data: true}.Build()},
 			},
-			want: &v1beta1.PodDisruptionBudget{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      esv1.DefaultPodDisruptionBudget("cluster"),
-					Namespace: "ns",
-					Labels:    map[string]string{label.ClusterNameLabelName: "cluster", common.TypeLabelName: label.Type},
-				},
-				Spec: v1beta1.PodDisruptionBudgetSpec{
-					MinAvailable: intStrPtr(intstr.FromInt(42)),
+			want: []*v1beta1.PodDisruptionBudget{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      esv1.DefaultPodDisruptionBudget("cluster"),
+						Namespace: "ns",
+						Labels:    map[string]string{label.ClusterNameLabelName: "cluster", common.TypeLabelName: label.Type},
+					},
+					Spec: v1beta1.PodDisruptionBudgetSpec{
+						MinAvailable: intStrPtr(intstr.FromInt(42)),
+					},
 				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.want != nil {
+			var want []v1beta1.PodDisruptionBudget
+			for _, pdb := range tt.want {
 				// set owner ref
-				tt.want = withOwnerRef(tt.want, tt.args.es)
+				want = append(want, *withOwnerRef(pdb, tt.args.es))
 			}
-			got, err := expectedPDB(tt.args.es, tt.args.statefulSets)
+			got, err := expectedPDBs(context.Background(), tt.args.es, tt.args.statefulSets, migration.NewFakeShardLister(nil))
 			require.NoError(t, err)
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("expectedPDB() got = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expectedPDBs() got = %v, want %v", got, want)
 			}
 		})
 	}
 }
 
+func withRoles(statefulSet appsv1.StatefulSet, roles ...common.TrueFalseLabel) appsv1.StatefulSet {
+	for _, role := range roles {
+		role.Set(true, statefulSet.Spec.Template.Labels)
+	}
+	return statefulSet
+}
+
+func Test_groupByRoles(t *testing.T) {
+	master := sset.TestSset{Name: "master", Master: true}.Build()
+	masterData := sset.TestSset{Name: "master-data", Master: true, Data: true}.Build()
+	masterHot := withRoles(sset.TestSset{Name: "master-hot", Master: true}.Build(), label.NodeTypesDataHotLabelName)
+	data := sset.TestSset{Name: "data", Data: true}.Build()
+	hot := withRoles(sset.TestSset{Name: "hot"}.Build(), label.NodeTypesDataHotLabelName, label.NodeTypesDataContentLabelName)
+	warm := withRoles(sset.TestSset{Name: "warm"}.Build(), label.NodeTypesDataWarmLabelName)
+	cold := withRoles(sset.TestSset{Name: "cold"}.Build(), label.NodeTypesDataColdLabelName)
+	content := withRoles(sset.TestSset{Name: "content"}.Build(), label.NodeTypesDataContentLabelName)
+	ingest := sset.TestSset{Name: "ingest", Ingest: true}.Build()
+	ml := withRoles(sset.TestSset{Name: "ml"}.Build(), label.NodeTypesMLLabelName)
+
+	tests := []struct {
+		name         string
+		statefulSets sset.StatefulSetList
+		want         []roleGroup
+	}{
+		{
+			name:         "dedicated master nodes and data tiers",
+			statefulSets: sset.StatefulSetList{ml, warm, ingest, hot, master, cold},
+			want: []roleGroup{
+				{name: "master", statefulSets: sset.StatefulSetList{master}},
+				{name: "data-hot", statefulSets: sset.StatefulSetList{hot}},
+				{name: "data-warm", statefulSets: sset.StatefulSetList{warm}},
+				{name: "data-cold", statefulSets: sset.StatefulSetList{cold}},
+				{name: "coordinating", statefulSets: sset.StatefulSetList{ml, ingest}},
+			},
+		},
+		{
+			name:         "data tiers sharing a data role",
+			statefulSets: sset.StatefulSetList{content, warm, hot},
+			want: []roleGroup{
+				{name: "data-hot", statefulSets: sset.StatefulSetList{content, hot}},
+				{name: "data-warm", statefulSets: sset.StatefulSetList{warm}},
+			},
+		},
+		{
+			name:         "generic data role shared with all the data tiers",
+			statefulSets: sset.StatefulSetList{master, warm, data, hot},
+			want: []roleGroup{
+				{name: "master", statefulSets: sset.StatefulSetList{master}},
+				{name: "data", statefulSets: sset.StatefulSetList{warm, data, hot}},
+			},
+		},
+		{
+			name:         "master nodes holding data",
+			statefulSets: sset.StatefulSetList{ml, content, warm, ingest, hot, masterData},
+			want: []roleGroup{
+				{name: "master", statefulSets: sset.StatefulSetList{content, warm, hot, masterData}},
+				{name: "coordinating", statefulSets: sset.StatefulSetList{ml, ingest}},
+			},
+		},
+		{
+			name:         "master nodes sharing a data tier",
+			statefulSets: sset.StatefulSetList{warm, hot, master, masterHot, content},
+			want: []roleGroup{
+				{name: "master", statefulSets: sset.StatefulSetList{hot, master, masterHot, content}},
+				{name: "data-warm", statefulSets: sset.StatefulSetList{warm}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, groupByRoles(tt.statefulSets))
+		})
+	}
+}
+
 func Test_allowedDisruptions(t *testing.T) {
+	hot := withRoles(sset.TestSset{Name: "hot", Replicas: 3}.Build(), label.NodeTypesDataHotLabelName)
+	warm := withRoles(sset.TestSset{Name: "warm", Replicas: 3}.Build(), label.NodeTypesDataWarmLabelName)
+	// the primary of an index without replicas, held by the second warm node
+	singleCopyOnWarm := esclient.Shards{
+		{Index: "index", Shard: "0", State: esclient.STARTED, NodeName: "warm-1", Type: esclient.Primary},
+	}
 	type args struct {
 		es          esv1.Elasticsearch
 		actualSsets sset.StatefulSetList
+		group       int
+		shards      esclient.Shards
+		shardsErr   error
 	}
 	tests := []struct {
 		name string
@@ -272,7 +434,7 @@ func Test_allowedDisruptions(t *testing.T) {
 			name: "no health reported: no disruption allowed",
 			args: args{
 				es:          esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{}},
-				actualSsets: sset.StatefulSetList{sset.TestSset{Replicas: 3}.Build()},
+				actualSsets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Data: true}.Build()},
 			},
 			want: 0,
 		},
@@ -280,7 +442,7 @@ func Test_allowedDisruptions(t *testing.T) {
 			name: "yellow health: no disruption allowed",
 			args: args{
 				es:          esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchYellowHealth}},
-				actualSsets: sset.StatefulSetList{sset.TestSset{Replicas: 3}.Build()},
+				actualSsets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Data: true}.Build()},
 			},
 			want: 0,
 		},
@@ -308,6 +470,17 @@ func Test_allowedDisruptions(t *testing.T) {
 			},
 			want: 1,
 		},
+		{
+			name: "yellow health but dedicated master nodes: 1 disruption allowed",
+			args: args{
+				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchYellowHealth}},
+				actualSsets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Replicas: 3, Master: true}.Build(),
+					sset.TestSset{Name: "data", Replicas: 3, Data: true}.Build(),
+				},
+			},
+			want: 1,
+		},
 		{
 			name: "single-node cluster (not high-available): 1 disruption allowed",
 			args: args{
@@ -321,20 +494,33 @@ func Test_allowedDisruptions(t *testing.T) {
 			args: args{
 				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
 				actualSsets: sset.StatefulSetList{
-					sset.TestSset{Replicas: 1, Master: true, Data: false}.Build(),
-					sset.TestSset{Replicas: 3, Master: false, Data: true}.Build(),
+					sset.TestSset{Name: "masters", Replicas: 1, Master: true, Data: false}.Build(),
+					sset.TestSset{Name: "data", Replicas: 3, Master: false, Data: true}.Build(),
 				},
 			},
 			want: 0,
 		},
+		{
+			name: "green health but only 1 master: disruption allowed for data nodes",
+			args: args{
+				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
+				actualSsets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Replicas: 1, Master: true, Data: false}.Build(),
+					sset.TestSset{Name: "data", Replicas: 3, Master: false, Data: true}.Build(),
+				},
+				group: 1,
+			},
+			want: 1,
+		},
 		{
 			name: "green health but only 1 data node: 0 disruption allowed",
 			args: args{
 				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
 				actualSsets: sset.StatefulSetList{
-					sset.TestSset{Replicas: 3, Master: true, Data: false}.Build(),
-					sset.TestSset{Replicas: 1, Master: false, Data: true}.Build(),
+					sset.TestSset{Name: "masters", Replicas: 3, Master: true, Data: false}.Build(),
+					sset.TestSset{Name: "data", Replicas: 1, Master: false, Data: true}.Build(),
 				},
+				group: 1,
 			},
 			want: 0,
 		},
@@ -343,18 +529,120 @@ func Test_allowedDisruptions(t *testing.T) {
 			args: args{
 				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
 				actualSsets: sset.StatefulSetList{
-					sset.TestSset{Replicas: 3, Master: true, Data: true, Ingest: false}.Build(),
-					sset.TestSset{Replicas: 1, Ingest: true, Data: true}.Build(),
+					sset.TestSset{Name: "masters", Replicas: 3, Master: true, Data: true, Ingest: false}.Build(),
+					sset.TestSset{Name: "coord", Replicas: 1, Ingest: true}.Build(),
 				},
+				group: 1,
 			},
 			want: 0,
 		},
+		{
+			name: "green health but a shard without replicas in the group: 0 disruption allowed",
+			args: args{
+				es:          esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
+				actualSsets: sset.StatefulSetList{hot, warm},
+				group:       1,
+				shards:      singleCopyOnWarm,
+			},
+			want: 0,
+		},
+		{
+			name: "green health and a shard without replicas in another group: 1 disruption allowed",
+			args: args{
+				es:          esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
+				actualSsets: sset.StatefulSetList{hot, warm},
+				group:       0,
+				shards:      singleCopyOnWarm,
+			},
+			want: 1,
+		},
+		{
+			name: "green health but the shards cannot be retrieved: 0 disruption allowed",
+			args: args{
+				es:          esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
+				actualSsets: sset.StatefulSetList{sset.TestSset{Replicas: 3, Master: true, Data: true}.Build()},
+				shardsErr:   errors.New("cannot reach Elasticsearch"),
+			},
+			want: 0,
+		},
+		{
+			name: "shards cannot be retrieved but dedicated master nodes: 1 disruption allowed",
+			args: args{
+				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
+				actualSsets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Replicas: 3, Master: true}.Build(),
+					sset.TestSset{Name: "data", Replicas: 3, Data: true}.Build(),
+				},
+				shardsErr: errors.New("cannot reach Elasticsearch"),
+			},
+			want: 1,
+		},
+		{
+			name: "green health and 2 coordinating nodes: 1 disruption allowed",
+			args: args{
+				es: esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth}},
+				actualSsets: sset.StatefulSetList{
+					sset.TestSset{Name: "masters", Replicas: 3, Master: true, Data: true}.Build(),
+					sset.TestSset{Name: "coord", Replicas: 2}.Build(),
+				},
+				group: 1,
+			},
+			want: 1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := allowedDisruptions(tt.args.es, tt.args.actualSsets); got != tt.want {
+			group := groupByRoles(tt.args.actualSsets)[tt.args.group]
+			copies := retrieveSingleCopies(context.Background(), tt.args.es, migration.NewFakeShardListerWithError(tt.args.shards, tt.args.shardsErr))
+			if got := allowedDisruptions(tt.args.es, tt.args.actualSsets, group, copies); got != tt.want {
 				t.Errorf("allowedDisruptions() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_retrieveSingleCopies(t *testing.T) {
+	tests := []struct {
+		name        string
+		shardLister esclient.ShardLister
+		want        singleCopies
+	}{
+		{
+			name:        "Elasticsearch cannot be reached",
+			shardLister: nil,
+			want:        singleCopies{},
+		},
+		{
+			name:        "shards cannot be retrieved",
+			shardLister: migration.NewFakeShardListerWithError(nil, errors.New("error")),
+			want:        singleCopies{},
+		},
+		{
+			name: "shards with replicas",
+			shardLister: migration.NewFakeShardLister(esclient.Shards{
+				{Index: "index", Shard: "0", State: esclient.STARTED, NodeName: "node-0", Type: esclient.Primary},
+				{Index: "index", Shard: "0", State: esclient.STARTED, NodeName: "node-1", Type: esclient.Replica},
+				{Index: "index", Shard: "1", State: esclient.RELOCATING, NodeName: "node-1", Type: esclient.Primary},
+				{Index: "index", Shard: "1", State: esclient.STARTED, NodeName: "node-2", Type: esclient.Replica},
+			}),
+			want: singleCopies{known: true, nodes: set.Make()},
+		},
+		{
+			name: "shards without replicas, or whose replicas are not started",
+			shardLister: migration.NewFakeShardLister(esclient.Shards{
+				{Index: "no-replicas", Shard: "0", State: esclient.STARTED, NodeName: "node-0", Type: esclient.Primary},
+				{Index: "relocating", Shard: "0", State: esclient.RELOCATING, NodeName: "node-1", Type: esclient.Primary},
+				{Index: "initializing", Shard: "0", State: esclient.STARTED, NodeName: "node-1", Type: esclient.Primary},
+				{Index: "initializing", Shard: "0", State: esclient.INITIALIZING, NodeName: "node-2", Type: esclient.Replica},
+				{Index: "unassigned", Shard: "0", State: esclient.STARTED, NodeName: "node-2", Type: esclient.Primary},
+				{Index: "unassigned", Shard: "0", State: esclient.UNASSIGNED, Type: esclient.Replica},
+			}),
+			want: singleCopies{known: true, nodes: set.Make("node-0", "node-1", "node-2")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, retrieveSingleCopies(context.Background(), esv1.Elasticsearch{}, tt.shardLister))
+		})
+	}
+}
@@ -5,16 +5,23 @@
 package reconcile
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/observer"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var log = ulog.Log.WithName("elasticsearch-controller")
@@ -29,7 +36,10 @@ type State struct {
 
 // NewState creates a new reconcile state based on the given cluster
 func NewState(c esv1.Elasticsearch) *State {
-	return &State{Recorder: events.NewRecorder(), cluster: c, status: *c.Status.DeepCopy()}
+	status := *c.Status.DeepCopy()
+	// the status about to be computed reflects the current generation of the spec
+	status.ObservedGeneration = c.Generation
+	return &State{Recorder: events.NewRecorder(), cluster: c, status: status}
 }
 
 // AvailableElasticsearchNodes filters a slice of pods for the ones that are ready.
@@ -91,6 +101,138 @@ func (s *State) updateWithPhase(
 	return s
 }
 
+// ReportCondition sets the given condition in the status of the Elasticsearch resource.
+func (s *State) ReportCondition(condition metav1.Condition) {
+	common.SetCondition(&s.status.Conditions, s.cluster.Generation, condition)
+}
+
+// UpdateConditions reports in the resource status whether the reconciliation is complete, based on its results, and
+// whether the desired version is running.
+func (s *State) UpdateConditions(results *reconciler.Results) {
+	s.ReportCondition(common.ReconciliationCompleteCondition(results))
+	s.ReportCondition(common.RunningDesiredVersionCondition(s.status.Version, s.cluster.Spec.Version))
+}
+
+// UpdateElasticsearchReachable reports in the resource status whether the Elasticsearch HTTP service is reachable.
+func (s *State) UpdateElasticsearchReachable(reachable bool) {
+	condition := metav1.Condition{
+		Type:    esv1.ElasticsearchIsReachable,
+		Status:  metav1.ConditionTrue,
+		Reason:  esv1.ServiceReadyReason,
+		Message: "Elasticsearch HTTP service has ready endpoints",
+	}
+	if !reachable {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = esv1.ServiceNotReadyReason
+		condition.Message = "Elasticsearch HTTP service has no ready endpoint"
+	}
+	s.ReportCondition(condition)
+}
+
+// UpdateSnapshotLifecyclePolicies reports in the resource status the outcome of the last snapshots of the snapshot
+// lifecycle policies declared in the specification.
+func (s *State) UpdateSnapshotLifecyclePolicies(policies []esv1.SnapshotLifecyclePolicyStatus) {
+	s.status.SnapshotLifecyclePolicies = policies
+}
+
+// UpdateClusterResources reports in the resource status the state of the index lifecycle policies, templates and
+// ingest pipelines declared in the specification.
+func (s *State) UpdateClusterResources(resources []esv1.ClusterResourceStatus) {
+	s.status.ClusterResources = resources
+}
+
+// UpdateAuth reports in the resource status the state of the native realm users and of the role mappings declared in
+// the specification.
+func (s *State) UpdateAuth(resources []esv1.ClusterResourceStatus) {
+	s.status.Auth = resources
+}
+
+// UpdatePasswordRotation reports in the resource status the time the passwords generated by the operator were last
+// rotated, if known.
+func (s *State) UpdatePasswordRotation(rotationTime time.Time) {
+	if rotationTime.IsZero() {
+		return
+	}
+	s.status.LastPasswordRotationTime = &metav1.Time{Time: rotationTime}
+}
+
+// UpdateDefaultCredentialsAvailable reports in the resource status whether the operator generates the password of the
+// elastic user, or no default credentials exist because the elastic user is disabled or its password hash is provided.
+func (s *State) UpdateDefaultCredentialsAvailable() {
+	condition := metav1.Condition{
+		Type:    esv1.DefaultCredentialsAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  esv1.GeneratedPasswordReason,
+		Message: fmt.Sprintf("The password of the elastic user is stored in Secret %s", esv1.ElasticUserSecret(s.cluster.Name)),
+	}
+	switch {
+	case s.cluster.Spec.Auth.ElasticUserDisabled():
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = esv1.ElasticUserDisabledReason
+		condition.Message = "The elastic user is disabled"
+	case s.cluster.Spec.Auth.ElasticUserPasswordHashSource() != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = esv1.PasswordHashProvidedReason
+		condition.Message = fmt.Sprintf(
+			"The password hash of the elastic user is provided by Secret %s",
+			s.cluster.Spec.Auth.ElasticUserPasswordHashSource().SecretName,
+		)
+	}
+	s.ReportCondition(condition)
+}
+
+// UpdateUpgradeBlocked reports in the resource status the Pods that cannot be restarted during a rolling upgrade,
+// grouped by the name of the predicates that prevent their restart.
+func (s *State) UpdateUpgradeBlocked(podsByPredicates map[string][]string) {
+	condition := metav1.Condition{
+		Type:    esv1.UpgradeBlocked,
+		Status:  metav1.ConditionFalse,
+		Reason:  esv1.NoPredicateFailureReason,
+		Message: "No Pod restart is blocked",
+	}
+	if len(podsByPredicates) > 0 {
+		predicates := make([]string, 0, len(podsByPredicates))
+		for predicate := range podsByPredicates {
+			predicates = append(predicates, predicate)
+		}
+		sort.Strings(predicates)
+		failures := make([]string, 0, len(predicates))
+		for _, predicate := range predicates {
+			failures = append(failures, fmt.Sprintf("%s: %s", predicate, strings.Join(podsByPredicates[predicate], ", ")))
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = esv1.PredicatesFailedReason
+		condition.Message = fmt.Sprintf("Cannot restart some Pods: %s", strings.Join(failures, "; "))
+	}
+	s.ReportCondition(condition)
+}
+
+// UpdateTransportCertificatesValid reports in the resource status the Pods whose user-provided transport certificate
+// is missing, invalid or about to expire, along with the reason, indexed by Pod name.
+func (s *State) UpdateTransportCertificatesValid(invalidCertificates map[string]string) {
+	condition := metav1.Condition{
+		Type:    esv1.TransportCertificatesValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  esv1.ValidCertificatesReason,
+		Message: "All transport certificates are valid",
+	}
+	if len(invalidCertificates) > 0 {
+		podNames := make([]string, 0, len(invalidCertificates))
+		for podName := range invalidCertificates {
+			podNames = append(podNames, podName)
+		}
+		sort.Strings(podNames)
+		failures := make([]string, 0, len(podNames))
+		for _, podName := range podNames {
+			failures = append(failures, fmt.Sprintf("%s: %s", podName, invalidCertificates[podName]))
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = esv1.InvalidCertificatesReason
+		condition.Message = fmt.Sprintf("Invalid transport certificates: %s", strings.Join(failures, "; "))
+	}
+	s.ReportCondition(condition)
+}
+
 // UpdateElasticsearchState updates the Elasticsearch section of the state resource status based on the given pods.
 func (s *State) UpdateElasticsearchState(
 	resourcesState ResourcesState,
@@ -149,6 +291,12 @@ func (s *State) Apply() ([]events.Event, *esv1.Elasticsearch) {
 	return s.Events(), &s.cluster
 }
 
+// UpdateResourceVersion records the resource version of the Elasticsearch resource after it was modified during the
+// reconciliation, so that the status update does not conflict with the operator's own changes.
+func (s *State) UpdateResourceVersion(resourceVersion string) {
+	s.cluster.ResourceVersion = resourceVersion
+}
+
 func (s *State) UpdateElasticsearchInvalid(err error) {
 	s.status.Phase = esv1.ElasticsearchResourceInvalid
 	s.AddEvent(corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
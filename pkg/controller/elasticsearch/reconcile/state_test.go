@@ -5,6 +5,7 @@
 package reconcile
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -197,6 +198,23 @@ func TestState_Apply(t *testing.T) {
 				Phase:          esv1.ElasticsearchApplyingChangesPhase,
 			},
 		},
+		{
+			name: "observed generation updated on spec change",
+			cluster: esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: esv1.ElasticsearchStatus{
+					Health:             esv1.ElasticsearchGreenHealth,
+					Phase:              esv1.ElasticsearchReadyPhase,
+					ObservedGeneration: 1,
+				},
+			},
+			wantEvents: []events.Event{},
+			wantStatus: &esv1.ElasticsearchStatus{
+				Health:             esv1.ElasticsearchGreenHealth,
+				Phase:              esv1.ElasticsearchReadyPhase,
+				ObservedGeneration: 2,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -417,3 +435,73 @@ func TestState_fetchMinRunningVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestState_UpdateUpgradeBlocked(t *testing.T) {
+	s := NewState(esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Generation: 3}})
+
+	s.UpdateUpgradeBlocked(map[string][]string{
+		"require_started_replica":                               {"es-default-1"},
+		"do_not_restart_healthy_node_if_MaxUnavailable_reached": {"es-default-0", "es-default-2"},
+	})
+	condition := s.status.Conditions[0]
+	assert.Equal(t, esv1.UpgradeBlocked, condition.Type)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, esv1.PredicatesFailedReason, condition.Reason)
+	assert.Equal(t, "Cannot restart some Pods: do_not_restart_healthy_node_if_MaxUnavailable_reached: es-default-0, es-default-2; require_started_replica: es-default-1", condition.Message)
+	assert.Equal(t, int64(3), condition.ObservedGeneration)
+
+	s.UpdateUpgradeBlocked(nil)
+	assert.Len(t, s.status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, s.status.Conditions[0].Status)
+	assert.Equal(t, esv1.NoPredicateFailureReason, s.status.Conditions[0].Reason)
+}
+
+func TestState_UpdateTransportCertificatesValid(t *testing.T) {
+	s := NewState(esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Generation: 2}})
+
+	s.UpdateTransportCertificatesValid(map[string]string{
+		"es-default-1": "no certificate in secret ns/node-certs",
+		"es-default-0": "certificate expires at 2021-05-01T00:00:00Z",
+	})
+	condition := s.status.Conditions[0]
+	assert.Equal(t, esv1.TransportCertificatesValid, condition.Type)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, esv1.InvalidCertificatesReason, condition.Reason)
+	assert.Equal(t, "Invalid transport certificates: es-default-0: certificate expires at 2021-05-01T00:00:00Z; es-default-1: no certificate in secret ns/node-certs", condition.Message)
+	assert.Equal(t, int64(2), condition.ObservedGeneration)
+
+	s.UpdateTransportCertificatesValid(nil)
+	assert.Len(t, s.status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionTrue, s.status.Conditions[0].Status)
+	assert.Equal(t, esv1.ValidCertificatesReason, s.status.Conditions[0].Reason)
+}
+
+func TestState_UpdateDefaultCredentialsAvailable(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es"}}
+	s := NewState(es)
+	s.UpdateDefaultCredentialsAvailable()
+	assert.Equal(t, esv1.DefaultCredentialsAvailable, s.status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, s.status.Conditions[0].Status)
+	assert.Equal(t, "The password of the elastic user is stored in Secret es-es-elastic-user", s.status.Conditions[0].Message)
+
+	es.Spec.Auth.ElasticUser = &esv1.ElasticUser{Disabled: true}
+	s = NewState(es)
+	s.UpdateDefaultCredentialsAvailable()
+	assert.Equal(t, metav1.ConditionFalse, s.status.Conditions[0].Status)
+	assert.Equal(t, esv1.ElasticUserDisabledReason, s.status.Conditions[0].Reason)
+
+	es.Spec.Auth.ElasticUser = &esv1.ElasticUser{PasswordHashSecretRef: &esv1.PasswordHashSource{SecretName: "hashes", Key: "elastic"}}
+	s = NewState(es)
+	s.UpdateDefaultCredentialsAvailable()
+	assert.Equal(t, metav1.ConditionFalse, s.status.Conditions[0].Status)
+	assert.Equal(t, esv1.PasswordHashProvidedReason, s.status.Conditions[0].Reason)
+	assert.Equal(t, "The password hash of the elastic user is provided by Secret hashes", s.status.Conditions[0].Message)
+}
+
+func TestState_UpdateResourceVersion(t *testing.T) {
+	s := NewState(esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"}})
+	s.UpdateResourceVersion("2")
+	s.UpdateElasticsearchInvalid(errors.New("invalid"))
+	_, cluster := s.Apply()
+	assert.Equal(t, "2", cluster.ResourceVersion)
+}
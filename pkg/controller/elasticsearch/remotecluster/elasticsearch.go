@@ -12,6 +12,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -100,21 +101,21 @@ func updateSettingsInternal(
 		}
 	}
 
+	connectionModes := supportsConnectionModes(es)
 	remoteClustersToUpdate := make([]string, 0, len(remoteClustersInSpec)) // only used for logging
 	// remoteClustersToApply are clusters to add (or update) based on what is specified in the Elasticsearch spec.
 	remoteClustersToApply := make(map[string]esclient.RemoteCluster)
 	for name, remoteCluster := range remoteClustersInSpec {
 		remoteClustersToUpdate = append(remoteClustersToUpdate, name)
 		// Declare remote cluster in ES
-		seedHosts := []string{services.ExternalTransportServiceHost(remoteCluster.ElasticsearchRef.NamespacedName())}
-		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: seedHosts}
+		remoteClustersToApply[name] = expectedRemoteClusterSettings(remoteCluster, connectionModes)
 		// Ensure this cluster is tracked in the annotation
 		remoteClustersInAnnotation[name] = struct{}{}
 	}
 
 	// RemoteClusters to remove from Elasticsearch
 	for _, name := range remoteClustersToDelete {
-		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: nil}
+		remoteClustersToApply[name] = esclient.RemoteCluster{Seeds: nil, ConnectionModes: connectionModes}
 	}
 
 	// Update the annotation
@@ -140,6 +141,37 @@ func updateSettingsInternal(
 	return requeue, nil
 }
 
+// supportsConnectionModes returns true if the version of Elasticsearch supports the sniff and proxy connection modes.
+func supportsConnectionModes(es esv1.Elasticsearch) bool {
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return false
+	}
+	return v.GTE(version.From(7, 7, 0))
+}
+
+// expectedRemoteClusterSettings returns the settings of a remote cluster declared in the Elasticsearch spec.
+// Remote clusters managed by ECK are reached through their transport service, while the other ones are reached
+// either through the provided seeds, or through the provided proxy address.
+func expectedRemoteClusterSettings(remoteCluster esv1.RemoteCluster, connectionModes bool) esclient.RemoteCluster {
+	settings := esclient.RemoteCluster{ConnectionModes: connectionModes}
+	switch {
+	case remoteCluster.ElasticsearchRef.IsDefined():
+		settings.Seeds = []string{services.ExternalTransportServiceHost(remoteCluster.ElasticsearchRef.NamespacedName())}
+	case remoteCluster.ProxyAddress != "":
+		settings.Mode = esclient.RemoteClusterProxyMode
+		settings.ProxyAddress = remoteCluster.ProxyAddress
+		settings.ServerName = remoteCluster.ServerName
+		return settings
+	default:
+		settings.Seeds = remoteCluster.Seeds
+	}
+	if connectionModes {
+		settings.Mode = esclient.RemoteClusterSniffMode
+	}
+	return settings
+}
+
 // getRemoteClustersInElasticsearch returns all the remote clusters currently declared in Elasticsearch
 func getRemoteClustersInElasticsearch(esClient esclient.Client) (map[string]struct{}, error) {
 	remoteClustersInEs := make(map[string]struct{})
@@ -158,6 +190,10 @@ func getRemoteClustersInElasticsearch(esClient esclient.Client) (map[string]stru
 func getRemoteClustersInSpec(es esv1.Elasticsearch) map[string]esv1.RemoteCluster {
 	remoteClusters := make(map[string]esv1.RemoteCluster)
 	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if remoteCluster.IsExternal() {
+			remoteClusters[remoteCluster.Name] = remoteCluster
+			continue
+		}
 		if !remoteCluster.ElasticsearchRef.IsDefined() {
 			continue
 		}
@@ -108,6 +108,11 @@ func newEsWithRemoteClusters(
 	}
 }
 
+func withVersion(version string, es *esv1.Elasticsearch) *esv1.Elasticsearch {
+	es.Spec.Version = version
+	return es
+}
+
 type fakeLicenseChecker struct {
 	enterpriseFeaturesEnabled bool
 }
@@ -400,6 +405,93 @@ func TestUpdateSettings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Create remote clusters not managed by ECK",
+			args: args{
+				esClient:       &fakeESClient{existingSettings: emptySettings},
+				licenseChecker: &fakeLicenseChecker{true},
+				es: withVersion("7.10.0", newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					nil,
+					esv1.RemoteCluster{
+						Name:             "ns2-es2",
+						ElasticsearchRef: commonv1.ObjectSelector{Name: "es2", Namespace: "ns2"},
+					},
+					esv1.RemoteCluster{
+						Name:  "on-prem",
+						Seeds: []string{"10.0.0.1:9300", "10.0.0.2:9300"},
+					},
+					esv1.RemoteCluster{
+						Name:         "cloud",
+						ProxyAddress: "cloud.example.com:9400",
+						ServerName:   "cloud.example.com",
+					},
+				)),
+			},
+			wantAnnotation: "cloud,ns2-es2,on-prem",
+			wantEsCalled:   true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"ns2-es2": {
+								Mode:            esclient.RemoteClusterSniffMode,
+								Seeds:           []string{"es2-es-transport.ns2.svc:9300"},
+								ConnectionModes: true,
+							},
+							"on-prem": {
+								Mode:            esclient.RemoteClusterSniffMode,
+								Seeds:           []string{"10.0.0.1:9300", "10.0.0.2:9300"},
+								ConnectionModes: true,
+							},
+							"cloud": {
+								Mode:            esclient.RemoteClusterProxyMode,
+								ProxyAddress:    "cloud.example.com:9400",
+								ServerName:      "cloud.example.com",
+								ConnectionModes: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Remove previously managed cluster in proxy mode",
+			args: args{
+				esClient: &fakeESClient{
+					existingSettings: esclient.RemoteClustersSettings{
+						PersistentSettings: &esclient.SettingsGroup{
+							Cluster: esclient.RemoteClusters{
+								RemoteClusters: map[string]esclient.RemoteCluster{
+									"cloud": {Mode: esclient.RemoteClusterProxyMode, ProxyAddress: "cloud.example.com:9400"},
+								},
+							},
+						},
+					},
+				},
+				licenseChecker: &fakeLicenseChecker{true},
+				es: withVersion("7.10.0", newEsWithRemoteClusters(
+					"ns1",
+					"es1",
+					map[string]string{
+						"elasticsearch.k8s.elastic.co/managed-remote-clusters": `cloud`,
+					},
+				)),
+			},
+			wantRequeue:    true,
+			wantAnnotation: "cloud",
+			wantEsCalled:   true,
+			wantSettings: esclient.RemoteClustersSettings{
+				PersistentSettings: &esclient.SettingsGroup{
+					Cluster: esclient.RemoteClusters{
+						RemoteClusters: map[string]esclient.RemoteCluster{
+							"cloud": {ConnectionModes: true},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "No valid license to create a new remote cluster",
 			args: args{
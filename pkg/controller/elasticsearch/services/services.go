@@ -10,13 +10,18 @@ import (
 	"math/rand"
 	"strconv"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/stringsutil"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -90,6 +95,37 @@ func NewExternalService(es esv1.Elasticsearch) *corev1.Service {
 	svc.ObjectMeta.Name = ExternalServiceName(es.Name)
 
 	labels := label.NewLabels(nsn)
+	if svc.Spec.Selector != nil {
+		// a user-provided selector, for example on the node roles labels, is restricted to the Pods of this cluster
+		svc.Spec.Selector = maps.MergePreservingExistingKeys(maps.Merge(map[string]string{}, svc.Spec.Selector), labels)
+	}
+	ports := []corev1.ServicePort{
+		{
+			Name:     es.Spec.HTTP.Protocol(),
+			Protocol: corev1.ProtocolTCP,
+			Port:     network.HTTPPort,
+		},
+	}
+
+	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+}
+
+// NewStatefulSetHTTPService returns the optional HTTP service dedicated to the Pods of the given StatefulSet, built
+// from the service template of the corresponding NodeSet.
+func NewStatefulSetHTTPService(es esv1.Elasticsearch, ssetName string, template commonv1.ServiceTemplate) *corev1.Service {
+	nsn := k8s.ExtractNamespacedName(&es)
+
+	svc := corev1.Service{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+
+	svc.ObjectMeta.Namespace = es.Namespace
+	svc.ObjectMeta.Name = esv1.StatefulSetHTTPService(ssetName)
+
+	labels := label.NewStatefulSetLabels(nsn, ssetName)
+	// the selector cannot be customized, the service always targets the Pods of the StatefulSet
+	svc.Spec.Selector = nil
 	ports := []corev1.ServicePort{
 		{
 			Name:     es.Spec.HTTP.Protocol(),
@@ -101,6 +137,56 @@ func NewExternalService(es esv1.Elasticsearch) *corev1.Service {
 	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
 }
 
+// NewStatefulSetHTTPServices returns the optional HTTP services dedicated to the Pods of the NodeSets of the given
+// cluster which specify a service template.
+func NewStatefulSetHTTPServices(es esv1.Elasticsearch) []corev1.Service {
+	var svcs []corev1.Service
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.Service == nil {
+			continue
+		}
+		svcs = append(svcs, *NewStatefulSetHTTPService(es, esv1.StatefulSet(es.Name, nodeSet.Name), *nodeSet.Service))
+	}
+	return svcs
+}
+
+// ReconcileStatefulSetHTTPServices reconciles the HTTP services dedicated to the Pods of the NodeSets which specify a
+// service template, and deletes the ones of the NodeSets which do not specify a service template anymore.
+func ReconcileStatefulSetHTTPServices(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) ([]corev1.Service, error) {
+	expected := NewStatefulSetHTTPServices(es)
+	reconciled := make([]corev1.Service, 0, len(expected))
+	for i := range expected {
+		svc, err := common.ReconcileService(ctx, c, &expected[i], &es)
+		if err != nil {
+			return nil, err
+		}
+		reconciled = append(reconciled, *svc)
+	}
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.Service != nil {
+			continue
+		}
+		if err := DeleteStatefulSetHTTPService(c, es.Namespace, esv1.StatefulSet(es.Name, nodeSet.Name)); err != nil {
+			return nil, err
+		}
+	}
+	return reconciled, nil
+}
+
+// DeleteStatefulSetHTTPService deletes the HTTP service dedicated to the Pods of the given StatefulSet, if any.
+func DeleteStatefulSetHTTPService(c k8s.Client, namespace string, ssetName string) error {
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      esv1.StatefulSetHTTPService(ssetName),
+		},
+	}
+	if err := c.Delete(context.Background(), &svc); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 // IsServiceReady checks if a service has one or more ready endpoints.
 func IsServiceReady(c k8s.Client, service corev1.Service) (bool, error) {
 	endpoints := corev1.Endpoints{}
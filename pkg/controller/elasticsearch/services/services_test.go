@@ -5,6 +5,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
@@ -13,6 +14,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/compare"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -210,6 +212,28 @@ func TestNewExternalService(t *testing.T) {
 				return svc
 			},
 		},
+		{
+			name: "user-provided selector is restricted to the cluster",
+			httpConf: commonv1.HTTPConfig{
+				Service: commonv1.ServiceTemplate{
+					Spec: corev1.ServiceSpec{
+						Selector: map[string]string{
+							string(label.NodeTypesIngestLabelName): "true",
+						},
+					},
+				},
+				TLS: commonv1.TLSOptions{
+					SelfSignedCertificate: &commonv1.SelfSignedCertificate{
+						Disabled: true,
+					},
+				},
+			},
+			wantSvc: func() corev1.Service {
+				svc := mkHTTPService()
+				svc.Spec.Selector[string(label.NodeTypesIngestLabelName)] = "true"
+				return svc
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -340,3 +364,68 @@ func TestNewTransportService(t *testing.T) {
 		})
 	}
 }
+
+func TestNewStatefulSetHTTPService(t *testing.T) {
+	es := mkElasticsearch(commonv1.HTTPConfig{})
+	template := commonv1.ServiceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"my-custom": "annotation"},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeLoadBalancer,
+			Selector: map[string]string{"ignored": "selector"},
+		},
+	}
+	want := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "elasticsearch-test-es-ingest-es-http",
+			Namespace:   "test",
+			Annotations: map[string]string{"my-custom": "annotation"},
+			Labels: map[string]string{
+				label.ClusterNameLabelName:     "elasticsearch-test",
+				common.TypeLabelName:           label.Type,
+				label.StatefulSetNameLabelName: "elasticsearch-test-es-ingest",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{
+				{
+					Name:     "https",
+					Protocol: corev1.ProtocolTCP,
+					Port:     network.HTTPPort,
+				},
+			},
+			Selector: map[string]string{
+				label.ClusterNameLabelName:     "elasticsearch-test",
+				common.TypeLabelName:           label.Type,
+				label.StatefulSetNameLabelName: "elasticsearch-test-es-ingest",
+			},
+		},
+	}
+	got := NewStatefulSetHTTPService(es, "elasticsearch-test-es-ingest", template)
+	require.Nil(t, deep.Equal(*got, want))
+	// the template is left untouched
+	require.Equal(t, map[string]string{"ignored": "selector"}, template.Spec.Selector)
+}
+
+func TestReconcileStatefulSetHTTPServices(t *testing.T) {
+	es := mkElasticsearch(commonv1.HTTPConfig{})
+	es.Spec.NodeSets = []esv1.NodeSet{
+		{Name: "ingest", Service: &commonv1.ServiceTemplate{}},
+		{Name: "data"},
+	}
+	// service left over from a previous template of the data NodeSet
+	leftover := NewStatefulSetHTTPService(es, "elasticsearch-test-es-data", commonv1.ServiceTemplate{})
+	c := k8s.NewFakeClient(leftover)
+
+	reconciled, err := ReconcileStatefulSetHTTPServices(context.Background(), c, es)
+	require.NoError(t, err)
+	require.Len(t, reconciled, 1)
+	assert.Equal(t, "elasticsearch-test-es-ingest-es-http", reconciled[0].Name)
+
+	var svcs corev1.ServiceList
+	require.NoError(t, c.List(context.Background(), &svcs))
+	require.Len(t, svcs.Items, 1)
+	assert.Equal(t, "elasticsearch-test-es-ingest-es-http", svcs.Items[0].Name)
+}
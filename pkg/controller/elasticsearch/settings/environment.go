@@ -8,10 +8,11 @@ package settings
 const (
 	EnvEsJavaOpts = "ES_JAVA_OPTS"
 
-	EnvProbePasswordPath      = "PROBE_PASSWORD_PATH"
-	EnvProbeUsername          = "PROBE_USERNAME"
-	EnvReadinessProbeProtocol = "READINESS_PROBE_PROTOCOL"
-	HeadlessServiceName       = "HEADLESS_SERVICE_NAME"
+	EnvProbePasswordPath         = "PROBE_PASSWORD_PATH"
+	EnvProbeFallbackPasswordPath = "PROBE_FALLBACK_PASSWORD_PATH"
+	EnvProbeUsername             = "PROBE_USERNAME"
+	EnvReadinessProbeProtocol    = "READINESS_PROBE_PROTOCOL"
+	HeadlessServiceName          = "HEADLESS_SERVICE_NAME"
 
 	// These are injected as env var into the ES pod at runtime,
 	// to be referenced in ES configuration file
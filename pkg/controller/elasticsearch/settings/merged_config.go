@@ -7,6 +7,7 @@ package settings
 import (
 	"fmt"
 	"path"
+	"strings"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
@@ -25,23 +26,23 @@ const nodeAttrK8sNodeName = "k8s_node_name"
 var nodeAttrNodeName = fmt.Sprintf("%s.%s", esv1.NodeAttr, nodeAttrK8sNodeName)
 
 // NewMergedESConfig merges user provided Elasticsearch configuration with configuration derived from the given
-// parameters. The user provided config overrides have precedence over the ECK config.
+// parameters. Additional ECK managed configurations (eg. for stack monitoring) can be provided.
+// The user provided config overrides have precedence over the ECK config.
 func NewMergedESConfig(
 	clusterName string,
 	ver version.Version,
 	ipFamily corev1.IPFamily,
 	httpConfig commonv1.HTTPConfig,
 	userConfig commonv1.Config,
+	managedConfigs ...*common.CanonicalConfig,
 ) (CanonicalConfig, error) {
 	userCfg, err := common.NewCanonicalConfigFrom(userConfig.Data)
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
 	config := baseConfig(clusterName, ver, ipFamily).CanonicalConfig
-	err = config.MergeWith(
-		xpackConfig(ver, httpConfig).CanonicalConfig,
-		userCfg,
-	)
+	configs := append([]*common.CanonicalConfig{xpackConfig(ver, httpConfig).CanonicalConfig}, managedConfigs...)
+	err = config.MergeWith(append(configs, userCfg)...)
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
@@ -79,6 +80,23 @@ func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamil
 	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
 }
 
+// ZoneAwarenessConfig returns the configuration bit related to shard allocation awareness based on the topology of
+// the k8s nodes. The node attribute itself is set by an init container, once the Pod is scheduled on a k8s node.
+func ZoneAwarenessConfig(zoneAwareness *esv1.ZoneAwareness) *common.CanonicalConfig {
+	if zoneAwareness == nil {
+		return common.NewCanonicalConfig()
+	}
+	attribute := zoneAwareness.AttributeOrDefault()
+	cfg := map[string]interface{}{
+		// keep the awareness of the k8s node the pod is running on
+		esv1.ShardAwarenessAttributes: strings.Join([]string{nodeAttrK8sNodeName, attribute}, ","),
+	}
+	if len(zoneAwareness.ForcedValues) > 0 {
+		cfg[fmt.Sprintf("%s.%s.values", esv1.ShardAwarenessForce, attribute)] = strings.Join(zoneAwareness.ForcedValues, ",")
+	}
+	return common.MustCanonicalConfig(cfg)
+}
+
 // xpackConfig returns the configuration bit related to XPack settings
 func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig) *CanonicalConfig {
 	// enable x-pack security, including TLS
@@ -10,6 +10,7 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
@@ -225,3 +226,58 @@ func TestNewMergedESConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestZoneAwarenessConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		zoneAwareness *esv1.ZoneAwareness
+		want          *common.CanonicalConfig
+	}{
+		{
+			name:          "zone awareness not enabled",
+			zoneAwareness: nil,
+			want:          common.NewCanonicalConfig(),
+		},
+		{
+			name:          "default attribute",
+			zoneAwareness: &esv1.ZoneAwareness{},
+			want: common.MustCanonicalConfig(map[string]interface{}{
+				esv1.ShardAwarenessAttributes: "k8s_node_name,zone",
+			}),
+		},
+		{
+			name: "forced awareness with a custom attribute",
+			zoneAwareness: &esv1.ZoneAwareness{
+				TopologyKey:  "example.com/rack",
+				Attribute:    "rack",
+				ForcedValues: []string{"rack1", "rack2"},
+			},
+			want: common.MustCanonicalConfig(map[string]interface{}{
+				esv1.ShardAwarenessAttributes:                            "k8s_node_name,rack",
+				"cluster.routing.allocation.awareness.force.rack.values": "rack1,rack2",
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ZoneAwarenessConfig(tt.zoneAwareness)
+			require.Empty(t, got.Diff(tt.want, nil))
+		})
+	}
+}
+
+func TestNewMergedESConfig_ZoneAwareness(t *testing.T) {
+	cfg, err := NewMergedESConfig(
+		"clusterName",
+		version.MustParse("7.10.0"),
+		corev1.IPv4Protocol,
+		commonv1.HTTPConfig{},
+		commonv1.Config{},
+		ZoneAwarenessConfig(&esv1.ZoneAwareness{}),
+	)
+	require.NoError(t, err)
+	cfgBytes, err := cfg.Render()
+	require.NoError(t, err)
+	// the zone attribute is added to the default k8s node name attribute
+	require.Equal(t, 1, bytes.Count(cfgBytes, []byte("attributes: k8s_node_name,zone")))
+}
@@ -0,0 +1,196 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package shutdown
+
+import (
+	"context"
+	"fmt"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+)
+
+// NodeShutdown implements the shutdown.Interface with the Elasticsearch node shutdown API. It is not safe to call
+// methods on this struct concurrently from multiple go-routines.
+type NodeShutdown struct {
+	c           esclient.Client
+	typ         esclient.ShutdownType
+	reason      string
+	podToNodeID map[string]string
+	shutdowns   map[string]esclient.NodeShutdown
+	log         logr.Logger
+}
+
+var _ Interface = &NodeShutdown{}
+
+// NewNodeShutdown creates a new NodeShutdown struct restricted to one type of shutdown (typ). Shutdown state and
+// node IDs are lazily loaded from Elasticsearch on first use.
+func NewNodeShutdown(c esclient.Client, typ esclient.ShutdownType, reason string, l logr.Logger) *NodeShutdown {
+	return &NodeShutdown{
+		c:      c,
+		typ:    typ,
+		reason: reason,
+		log:    l,
+	}
+}
+
+func (ns *NodeShutdown) initOnce(ctx context.Context) error {
+	if ns.shutdowns != nil {
+		return nil
+	}
+	nodes, err := ns.c.GetNodes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "while retrieving node IDs for shutdown")
+	}
+	podToNodeID := make(map[string]string, len(nodes.Nodes))
+	for id, n := range nodes.Nodes {
+		podToNodeID[n.Name] = id
+	}
+	r, err := ns.c.GetShutdown(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "while retrieving node shutdown status")
+	}
+	shutdowns := make(map[string]esclient.NodeShutdown, len(r.Nodes))
+	for _, s := range r.Nodes {
+		shutdowns[s.NodeID] = s
+	}
+	ns.podToNodeID = podToNodeID
+	ns.shutdowns = shutdowns
+	return nil
+}
+
+func (ns *NodeShutdown) lookupNodeID(podName string) (string, error) {
+	nodeID, exists := ns.podToNodeID[podName]
+	if !exists {
+		return "", fmt.Errorf("node %s currently not member of the cluster", podName)
+	}
+	return nodeID, nil
+}
+
+// ReconcileShutdowns cancels ongoing shutdowns of the configured type for nodes that are not part of leavingNodes
+// and requests a shutdown for the nodes in leavingNodes that do not have one yet.
+func (ns *NodeShutdown) ReconcileShutdowns(ctx context.Context, leavingNodes []string) error {
+	if err := ns.initOnce(ctx); err != nil {
+		return err
+	}
+	leaving := make(map[string]struct{}, len(leavingNodes))
+	for _, name := range leavingNodes {
+		if nodeID, exists := ns.podToNodeID[name]; exists {
+			leaving[nodeID] = struct{}{}
+		}
+	}
+	// cancel shutdowns of nodes that are no longer expected to leave the cluster
+	for nodeID, s := range ns.shutdowns {
+		if _, stillLeaving := leaving[nodeID]; stillLeaving || !s.Is(ns.typ) {
+			continue
+		}
+		ns.log.Info("Cancelling node shutdown", "node_id", nodeID, "type", ns.typ)
+		if err := ns.c.DeleteShutdown(ctx, nodeID); err != nil {
+			return err
+		}
+		delete(ns.shutdowns, nodeID)
+	}
+	return ns.RequestShutdowns(ctx, leavingNodes)
+}
+
+// RequestShutdowns requests a shutdown of the configured type for the given nodes, unless one is already in progress.
+// Nodes that are not currently members of the cluster are ignored.
+func (ns *NodeShutdown) RequestShutdowns(ctx context.Context, podNames []string) error {
+	if err := ns.initOnce(ctx); err != nil {
+		return err
+	}
+	for _, podName := range podNames {
+		nodeID, err := ns.lookupNodeID(podName)
+		if err != nil {
+			// the node is not in the cluster (anymore), nothing to shut down
+			continue
+		}
+		if s, exists := ns.shutdowns[nodeID]; exists && s.Is(ns.typ) {
+			continue
+		}
+		ns.log.Info("Requesting node shutdown", "node", podName, "node_id", nodeID, "type", ns.typ, "reason", ns.reason)
+		if err := ns.c.PutShutdown(ctx, nodeID, ns.typ, ns.reason); err != nil {
+			return err
+		}
+		r, err := ns.c.GetShutdown(ctx, &nodeID)
+		if err != nil {
+			return errors.Wrapf(err, "while retrieving shutdown status of node %s", podName)
+		}
+		for _, s := range r.Nodes {
+			ns.shutdowns[s.NodeID] = s
+		}
+	}
+	return nil
+}
+
+// ShutdownStatus returns the shutdown status of the given node as reported by Elasticsearch. Nodes that are not
+// currently members of the cluster, such as crash-looping Pods, cannot be shut down: they are safe to remove unless
+// some shards are unassigned, in which case they may be holding the only copy of these shards.
+func (ns *NodeShutdown) ShutdownStatus(ctx context.Context, podName string) (NodeShutdownStatus, error) {
+	if err := ns.initOnce(ctx); err != nil {
+		return NodeShutdownStatus{}, err
+	}
+	nodeID, err := ns.lookupNodeID(podName)
+	if err != nil {
+		return ns.nonMemberShutdownStatus(ctx, podName)
+	}
+	s, exists := ns.shutdowns[nodeID]
+	if !exists || !s.Is(ns.typ) {
+		return NodeShutdownStatus{}, fmt.Errorf("no shutdown of type %s in progress for node %s", ns.typ, podName)
+	}
+	return NodeShutdownStatus{
+		Status:      s.Status,
+		Explanation: s.ShardMigration.Explanation,
+	}, nil
+}
+
+// nonMemberShutdownStatus returns the shutdown status of a node which is not a member of the cluster, similar to the
+// shard migration based shutdown: COMPLETE unless some shards are unassigned.
+func (ns *NodeShutdown) nonMemberShutdownStatus(ctx context.Context, podName string) (NodeShutdownStatus, error) {
+	shards, err := ns.c.GetShards(ctx)
+	if err != nil {
+		return NodeShutdownStatus{}, errors.Wrap(err, "while retrieving shards")
+	}
+	for _, shard := range shards {
+		if shard.NodeName == "" {
+			return NodeShutdownStatus{
+				Status: esclient.ShutdownInProgress,
+				Explanation: fmt.Sprintf(
+					"node %s is not a member of the cluster and shard %s of index %s is unassigned", podName, shard.Shard, shard.Index,
+				),
+			}, nil
+		}
+	}
+	ns.log.V(1).Info("Node not a member of the cluster, considering its shutdown complete", "node", podName)
+	return NodeShutdownStatus{
+		Status:      esclient.ShutdownComplete,
+		Explanation: fmt.Sprintf("node %s is not a member of the cluster", podName),
+	}, nil
+}
+
+// ClearShutdowns removes the shutdown registrations of the configured type for the given nodes, typically once
+// they have been restarted.
+func (ns *NodeShutdown) ClearShutdowns(ctx context.Context, podNames []string) error {
+	if err := ns.initOnce(ctx); err != nil {
+		return err
+	}
+	for _, podName := range podNames {
+		nodeID, err := ns.lookupNodeID(podName)
+		if err != nil {
+			continue
+		}
+		s, exists := ns.shutdowns[nodeID]
+		if !exists || !s.Is(ns.typ) {
+			continue
+		}
+		ns.log.Info("Removing node shutdown", "node", podName, "node_id", nodeID, "type", ns.typ)
+		if err := ns.c.DeleteShutdown(ctx, nodeID); err != nil {
+			return err
+		}
+		delete(ns.shutdowns, nodeID)
+	}
+	return nil
+}
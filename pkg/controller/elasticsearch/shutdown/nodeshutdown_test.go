@@ -0,0 +1,171 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package shutdown
+
+import (
+	"context"
+	"testing"
+
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeShutdownClient struct {
+	esclient.Client
+	nodes     esclient.Nodes
+	shutdowns []esclient.NodeShutdown
+	shards    esclient.Shards
+
+	getShutdownCalls int
+	putShutdowns     []string
+	deleteShutdowns  []string
+}
+
+func (f *fakeShutdownClient) GetNodes(_ context.Context) (esclient.Nodes, error) {
+	return f.nodes, nil
+}
+
+func (f *fakeShutdownClient) GetShards(_ context.Context) (esclient.Shards, error) {
+	return f.shards, nil
+}
+
+func (f *fakeShutdownClient) GetShutdown(_ context.Context, nodeID *string) (esclient.ShutdownResponse, error) {
+	f.getShutdownCalls++
+	var r esclient.ShutdownResponse
+	for _, s := range f.shutdowns {
+		if nodeID == nil || s.NodeID == *nodeID {
+			r.Nodes = append(r.Nodes, s)
+		}
+	}
+	return r, nil
+}
+
+func (f *fakeShutdownClient) PutShutdown(_ context.Context, nodeID string, typ esclient.ShutdownType, reason string) error {
+	f.putShutdowns = append(f.putShutdowns, nodeID)
+	f.shutdowns = append(f.shutdowns, esclient.NodeShutdown{
+		NodeID: nodeID,
+		Type:   string(typ),
+		Reason: reason,
+		Status: esclient.ShutdownInProgress,
+	})
+	return nil
+}
+
+func (f *fakeShutdownClient) DeleteShutdown(_ context.Context, nodeID string) error {
+	f.deleteShutdowns = append(f.deleteShutdowns, nodeID)
+	return nil
+}
+
+func newFakeShutdownClient(shutdowns ...esclient.NodeShutdown) *fakeShutdownClient {
+	return &fakeShutdownClient{
+		nodes: esclient.Nodes{Nodes: map[string]esclient.Node{
+			"id-0": {Name: "pod-0"},
+			"id-1": {Name: "pod-1"},
+			"id-2": {Name: "pod-2"},
+		}},
+		shutdowns: shutdowns,
+	}
+}
+
+func TestNodeShutdown_ReconcileShutdowns(t *testing.T) {
+	tests := []struct {
+		name         string
+		shutdowns    []esclient.NodeShutdown
+		leavingNodes []string
+		wantPut      []string
+		wantDelete   []string
+	}{
+		{
+			name:         "no leaving nodes, no shutdowns",
+			leavingNodes: nil,
+		},
+		{
+			name:         "request shutdowns for leaving nodes",
+			leavingNodes: []string{"pod-2", "pod-1"},
+			wantPut:      []string{"id-2", "id-1"},
+		},
+		{
+			name:         "do not request a shutdown twice",
+			shutdowns:    []esclient.NodeShutdown{{NodeID: "id-2", Type: "REMOVE", Status: esclient.ShutdownInProgress}},
+			leavingNodes: []string{"pod-2"},
+		},
+		{
+			name:         "ignore nodes which are not part of the cluster",
+			leavingNodes: []string{"pod-3"},
+		},
+		{
+			name: "cancel shutdowns of nodes which are not leaving anymore",
+			shutdowns: []esclient.NodeShutdown{
+				{NodeID: "id-2", Type: "REMOVE", Status: esclient.ShutdownInProgress},
+				{NodeID: "id-1", Type: "REMOVE", Status: esclient.ShutdownInProgress},
+			},
+			leavingNodes: []string{"pod-2"},
+			wantDelete:   []string{"id-1"},
+		},
+		{
+			name:       "do not cancel shutdowns of another type",
+			shutdowns:  []esclient.NodeShutdown{{NodeID: "id-1", Type: "RESTART", Status: esclient.ShutdownComplete}},
+			wantDelete: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeShutdownClient(tt.shutdowns...)
+			ns := NewNodeShutdown(c, esclient.Remove, "downscale", ulog.Log)
+			require.NoError(t, ns.ReconcileShutdowns(context.Background(), tt.leavingNodes))
+			require.Equal(t, tt.wantPut, c.putShutdowns)
+			require.Equal(t, tt.wantDelete, c.deleteShutdowns)
+		})
+	}
+}
+
+func TestNodeShutdown_ShutdownStatus(t *testing.T) {
+	c := newFakeShutdownClient(
+		esclient.NodeShutdown{
+			NodeID:         "id-0",
+			Type:           "REMOVE",
+			Status:         esclient.ShutdownStalled,
+			ShardMigration: esclient.ShardMigration{Explanation: "no other node to allocate shards to"},
+		},
+		esclient.NodeShutdown{NodeID: "id-1", Type: "RESTART", Status: esclient.ShutdownComplete},
+	)
+	ns := NewNodeShutdown(c, esclient.Remove, "downscale", ulog.Log)
+
+	status, err := ns.ShutdownStatus(context.Background(), "pod-0")
+	require.NoError(t, err)
+	require.Equal(t, NodeShutdownStatus{
+		Status:      esclient.ShutdownStalled,
+		Explanation: "no other node to allocate shards to",
+	}, status)
+
+	// shutdown of another type
+	_, err = ns.ShutdownStatus(context.Background(), "pod-1")
+	require.Error(t, err)
+	// node not in the cluster, holding no shards
+	c.shards = esclient.Shards{{Index: "index", Shard: "0", State: esclient.STARTED, NodeName: "pod-0"}}
+	status, err = ns.ShutdownStatus(context.Background(), "pod-3")
+	require.NoError(t, err)
+	require.Equal(t, esclient.ShutdownComplete, status.Status)
+	// node not in the cluster, which may hold unassigned shards
+	c.shards = append(c.shards, esclient.Shard{Index: "index", Shard: "1", State: esclient.UNASSIGNED})
+	status, err = ns.ShutdownStatus(context.Background(), "pod-3")
+	require.NoError(t, err)
+	require.Equal(t, esclient.ShutdownInProgress, status.Status)
+
+	// the shutdown state is only retrieved once
+	require.Equal(t, 1, c.getShutdownCalls)
+}
+
+func TestNodeShutdown_ClearShutdowns(t *testing.T) {
+	c := newFakeShutdownClient(
+		esclient.NodeShutdown{NodeID: "id-0", Type: "RESTART", Status: esclient.ShutdownComplete},
+		esclient.NodeShutdown{NodeID: "id-1", Type: "REMOVE", Status: esclient.ShutdownComplete},
+	)
+	ns := NewNodeShutdown(c, esclient.Restart, "upgrade", ulog.Log)
+	require.NoError(t, ns.ClearShutdowns(context.Background(), []string{"pod-0", "pod-1", "pod-2", "pod-3"}))
+	// only the restart shutdown of pod-0 should be removed
+	require.Equal(t, []string{"id-0"}, c.deleteShutdowns)
+}
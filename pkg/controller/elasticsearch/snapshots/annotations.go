@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshots
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// ManagedRepositoriesAnnotationName holds the list of the snapshot repositories which have been registered by the operator.
+	ManagedRepositoriesAnnotationName = "elasticsearch.k8s.elastic.co/managed-snapshot-repositories"
+	// ManagedPoliciesAnnotationName holds the list of the snapshot lifecycle policies which have been created by the operator.
+	ManagedPoliciesAnnotationName = "elasticsearch.k8s.elastic.co/managed-snapshot-policies"
+)
+
+// getNamesInAnnotation returns the set of names serialized in the given annotation.
+// If the annotation does not exist the set is empty but not nil.
+func getNamesInAnnotation(es esv1.Elasticsearch, annotation string) map[string]struct{} {
+	names := make(map[string]struct{})
+	serializedNames, ok := es.Annotations[annotation]
+	if !ok || strings.TrimSpace(serializedNames) == "" {
+		return names
+	}
+	for _, name := range strings.Split(serializedNames, ",") {
+		names[name] = struct{}{}
+	}
+	return names
+}
+
+// setNamesInAnnotation serializes the given set of names in the annotation, or removes the annotation if the set is empty.
+// It returns true if the annotations of the Elasticsearch resource have been modified.
+func setNamesInAnnotation(es *esv1.Elasticsearch, annotation string, names map[string]struct{}) bool {
+	current, exists := es.Annotations[annotation]
+	if len(names) == 0 {
+		if !exists {
+			return false
+		}
+		delete(es.Annotations, annotation)
+		return true
+	}
+
+	serialized := make([]string, 0, len(names))
+	for name := range names {
+		serialized = append(serialized, name)
+	}
+	sort.Strings(serialized)
+	expected := strings.Join(serialized, ",")
+	if exists && current == expected {
+		return false
+	}
+
+	if es.Annotations == nil {
+		es.Annotations = make(map[string]string)
+	}
+	es.Annotations[annotation] = expected
+	return true
+}
+
+// annotateWithManagedResources tracks the snapshot repositories and policies managed by the operator in the annotations
+// of the Elasticsearch resource. The resource is only updated if an annotation has changed.
+func annotateWithManagedResources(c k8s.Client, es esv1.Elasticsearch, repositories, policies map[string]struct{}) error {
+	// do not mutate the annotations of the caller
+	es = *es.DeepCopy()
+	repositoriesChanged := setNamesInAnnotation(&es, ManagedRepositoriesAnnotationName, repositories)
+	policiesChanged := setNamesInAnnotation(&es, ManagedPoliciesAnnotationName, policies)
+	if !repositoriesChanged && !policiesChanged {
+		return nil
+	}
+	return c.Update(context.Background(), &es)
+}
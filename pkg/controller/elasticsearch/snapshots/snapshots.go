@@ -0,0 +1,290 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
+	"go.elastic.co/apm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var log = ulog.Log.WithName("snapshots")
+
+// Reconcile registers the snapshot repositories and creates the snapshot lifecycle policies declared in the
+// Elasticsearch specification. Repositories and policies previously managed by the operator but removed from the
+// specification are deleted from Elasticsearch, while the ones created by other means are left untouched.
+// It returns the status of the declared policies, and a boolean to indicate if a requeue should be scheduled to
+// sync the annotations on the Elasticsearch resource once the deleted resources are actually gone.
+func Reconcile(
+	ctx context.Context,
+	c k8s.Client,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+) ([]esv1.SnapshotLifecyclePolicyStatus, bool, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_snapshots", tracing.SpanTypeApp)
+	defer span.End()
+
+	repositoriesInAnnotation := getNamesInAnnotation(es, ManagedRepositoriesAnnotationName)
+	policiesInAnnotation := getNamesInAnnotation(es, ManagedPoliciesAnnotationName)
+	spec := es.Spec.Snapshots
+	if len(spec.Repositories) == 0 && len(spec.Policies) == 0 &&
+		len(repositoriesInAnnotation) == 0 && len(policiesInAnnotation) == 0 {
+		// nothing is or has ever been managed by the operator
+		return nil, false, nil
+	}
+
+	// Policies depend on repositories: policies are updated after the repositories are registered,
+	// and deleted before the repositories are unregistered.
+	repositoriesToDelete, err := reconcileRepositories(esClient, es, repositoriesInAnnotation)
+	if err != nil {
+		return nil, true, err
+	}
+	policiesToDelete, err := reconcilePolicies(esClient, es, policiesInAnnotation)
+	if err != nil {
+		return nil, true, err
+	}
+
+	// Update the annotations before deleting anything so that nothing created by the operator is ever forgotten
+	if err := annotateWithManagedResources(c, es, repositoriesInAnnotation, policiesInAnnotation); err != nil {
+		return nil, true, err
+	}
+
+	for _, name := range policiesToDelete {
+		log.Info("Deleting snapshot lifecycle policy", "namespace", es.Namespace, "es_name", es.Name, "policy", name)
+		if err := esClient.DeleteSnapshotLifecyclePolicy(context.Background(), name); err != nil {
+			return nil, true, err
+		}
+	}
+	for _, name := range repositoriesToDelete {
+		log.Info("Deleting snapshot repository", "namespace", es.Namespace, "es_name", es.Name, "repository", name)
+		if err := esClient.DeleteSnapshotRepository(context.Background(), name); err != nil {
+			return nil, true, err
+		}
+	}
+
+	statuses, err := policiesStatus(esClient, es)
+	if err != nil {
+		return nil, true, err
+	}
+
+	// Since the annotations are updated before Elasticsearch we should requeue to sync them
+	// if some repositories or policies have been deleted.
+	return statuses, len(policiesToDelete) > 0 || len(repositoriesToDelete) > 0, nil
+}
+
+// reconcileRepositories registers or updates the repositories declared in the spec and tracks them in the given set.
+// It returns the repositories to delete, which are the ones in the set but neither in the spec nor in Elasticsearch.
+// Repositories in the set which do not exist anymore in Elasticsearch are removed from the set.
+func reconcileRepositories(
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	repositoriesInAnnotation map[string]struct{},
+) ([]string, error) {
+	repositoriesInEs, err := esClient.GetSnapshotRepositories(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	repositoriesInSpec := make(map[string]struct{}, len(es.Spec.Snapshots.Repositories))
+	for _, repository := range es.Spec.Snapshots.Repositories {
+		repositoriesInSpec[repository.Name] = struct{}{}
+		repositoriesInAnnotation[repository.Name] = struct{}{}
+
+		expected := expectedRepository(repository)
+		if actual, exists := repositoriesInEs[repository.Name]; exists && repositoryEqual(expected, actual) {
+			continue
+		}
+		log.Info("Registering snapshot repository", "namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name)
+		if err := esClient.PutSnapshotRepository(context.Background(), repository.Name, expected); err != nil {
+			return nil, err
+		}
+	}
+
+	return namesToDelete(repositoriesInAnnotation, repositoriesInSpec, func(name string) bool {
+		_, exists := repositoriesInEs[name]
+		return exists
+	}), nil
+}
+
+// reconcilePolicies creates or updates the policies declared in the spec and tracks them in the given set.
+// It returns the policies to delete, which are the ones in the set but neither in the spec nor in Elasticsearch.
+// Policies in the set which do not exist anymore in Elasticsearch are removed from the set.
+func reconcilePolicies(
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	policiesInAnnotation map[string]struct{},
+) ([]string, error) {
+	if len(es.Spec.Snapshots.Policies) == 0 && len(policiesInAnnotation) == 0 {
+		// do not call the snapshot lifecycle management API which may not be available in this version
+		return nil, nil
+	}
+
+	policiesInEs, err := esClient.GetSnapshotLifecyclePolicies(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	policiesInSpec := make(map[string]struct{}, len(es.Spec.Snapshots.Policies))
+	for _, policy := range es.Spec.Snapshots.Policies {
+		policiesInSpec[policy.Name] = struct{}{}
+		policiesInAnnotation[policy.Name] = struct{}{}
+
+		expected := expectedPolicy(policy)
+		if actual, exists := policiesInEs[policy.Name]; exists {
+			equal, err := policyEqual(expected, actual.Policy)
+			if err != nil {
+				return nil, err
+			}
+			if equal {
+				continue
+			}
+		}
+		log.Info("Updating snapshot lifecycle policy", "namespace", es.Namespace, "es_name", es.Name, "policy", policy.Name)
+		if err := esClient.PutSnapshotLifecyclePolicy(context.Background(), policy.Name, expected); err != nil {
+			return nil, err
+		}
+	}
+
+	return namesToDelete(policiesInAnnotation, policiesInSpec, func(name string) bool {
+		_, exists := policiesInEs[name]
+		return exists
+	}), nil
+}
+
+// namesToDelete returns the sorted names which are in the annotation but not in the spec, and still exist in Elasticsearch.
+// Names which neither are in the spec nor exist in Elasticsearch are removed from the annotation.
+func namesToDelete(inAnnotation, inSpec map[string]struct{}, inElasticsearch func(string) bool) []string {
+	var toDelete []string
+	for name := range inAnnotation {
+		if _, exists := inSpec[name]; exists {
+			continue
+		}
+		if inElasticsearch(name) {
+			toDelete = append(toDelete, name)
+		} else {
+			// we don't need to track it anymore
+			delete(inAnnotation, name)
+		}
+	}
+	sort.Strings(toDelete)
+	return toDelete
+}
+
+// policiesStatus returns the time of the last successful and failed snapshots of the policies declared in the spec.
+func policiesStatus(esClient esclient.Client, es esv1.Elasticsearch) ([]esv1.SnapshotLifecyclePolicyStatus, error) {
+	if len(es.Spec.Snapshots.Policies) == 0 {
+		return nil, nil
+	}
+	policiesInEs, err := esClient.GetSnapshotLifecyclePolicies(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]esv1.SnapshotLifecyclePolicyStatus, 0, len(es.Spec.Snapshots.Policies))
+	for _, policy := range es.Spec.Snapshots.Policies {
+		status := esv1.SnapshotLifecyclePolicyStatus{Name: policy.Name}
+		if actual, exists := policiesInEs[policy.Name]; exists {
+			status.LastSuccess = invocationTime(actual.LastSuccess)
+			status.LastFailure = invocationTime(actual.LastFailure)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func invocationTime(invocation *esclient.SnapshotInvocation) *metav1.Time {
+	if invocation == nil {
+		return nil
+	}
+	t := metav1.NewTime(time.Unix(0, invocation.Time*int64(time.Millisecond)).UTC())
+	return &t
+}
+
+func expectedRepository(repository esv1.SnapshotRepository) esclient.SnapshotRepository {
+	expected := esclient.SnapshotRepository{Type: repository.Type}
+	if repository.Settings != nil {
+		expected.Settings = repository.Settings.Data
+	}
+	return expected
+}
+
+// repositoryEqual compares the expected repository with the one registered in Elasticsearch.
+// Elasticsearch returns all the settings as strings, the settings are compared in their flattened string form.
+func repositoryEqual(expected, actual esclient.SnapshotRepository) bool {
+	return expected.Type == actual.Type &&
+		reflect.DeepEqual(flattenSettings("", expected.Settings), flattenSettings("", actual.Settings))
+}
+
+// flattenSettings returns the settings as a flat map of dotted keys to their string value.
+func flattenSettings(prefix string, settings map[string]interface{}) map[string]string {
+	flattened := make(map[string]string)
+	for k, v := range settings {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			for nk, nv := range flattenSettings(key, nested) {
+				flattened[nk] = nv
+			}
+			continue
+		}
+		flattened[key] = fmt.Sprintf("%v", v)
+	}
+	return flattened
+}
+
+func expectedPolicy(policy esv1.SnapshotLifecyclePolicy) esclient.SnapshotLifecyclePolicy {
+	snapshotName := policy.SnapshotName
+	if snapshotName == "" {
+		snapshotName = fmt.Sprintf("<%s-{now/d}>", policy.Name)
+	}
+	expected := esclient.SnapshotLifecyclePolicy{
+		Name:       snapshotName,
+		Schedule:   policy.Schedule,
+		Repository: policy.Repository,
+	}
+	if policy.Config != nil {
+		expected.Config = policy.Config.Data
+	}
+	if policy.Retention != nil {
+		expected.Retention = policy.Retention.Data
+	}
+	return expected
+}
+
+// policyEqual compares the expected policy with the one stored in Elasticsearch, once both are normalized through
+// their JSON representation.
+func policyEqual(expected, actual esclient.SnapshotLifecyclePolicy) (bool, error) {
+	normalizedExpected, err := normalize(expected)
+	if err != nil {
+		return false, err
+	}
+	normalizedActual, err := normalize(actual)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(normalizedExpected, normalizedActual), nil
+}
+
+func normalize(policy esclient.SnapshotLifecyclePolicy) (map[string]interface{}, error) {
+	bytes, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	err = json.Unmarshal(bytes, &normalized)
+	return normalized, err
+}
@@ -0,0 +1,209 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package snapshots
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	repositories esclient.SnapshotRepositories
+	policies     esclient.SnapshotLifecyclePolicies
+
+	putRepositories     []string
+	deletedRepositories []string
+	putPolicies         []string
+	deletedPolicies     []string
+	slmCalled           bool
+}
+
+func (f *fakeESClient) GetSnapshotRepositories(_ context.Context) (esclient.SnapshotRepositories, error) {
+	return f.repositories, nil
+}
+
+func (f *fakeESClient) PutSnapshotRepository(_ context.Context, name string, _ esclient.SnapshotRepository) error {
+	f.putRepositories = append(f.putRepositories, name)
+	return nil
+}
+
+func (f *fakeESClient) DeleteSnapshotRepository(_ context.Context, name string) error {
+	f.deletedRepositories = append(f.deletedRepositories, name)
+	return nil
+}
+
+func (f *fakeESClient) GetSnapshotLifecyclePolicies(_ context.Context) (esclient.SnapshotLifecyclePolicies, error) {
+	f.slmCalled = true
+	return f.policies, nil
+}
+
+func (f *fakeESClient) PutSnapshotLifecyclePolicy(_ context.Context, name string, _ esclient.SnapshotLifecyclePolicy) error {
+	f.slmCalled = true
+	f.putPolicies = append(f.putPolicies, name)
+	return nil
+}
+
+func (f *fakeESClient) DeleteSnapshotLifecyclePolicy(_ context.Context, name string) error {
+	f.slmCalled = true
+	f.deletedPolicies = append(f.deletedPolicies, name)
+	return nil
+}
+
+func newEs(annotations map[string]string, snapshots esv1.SnapshotsSpec) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "es1", Annotations: annotations},
+		Spec:       esv1.ElasticsearchSpec{Snapshots: snapshots},
+	}
+}
+
+var (
+	fsRepository = esv1.SnapshotRepository{
+		Name:     "backups",
+		Type:     "fs",
+		Settings: &commonv1.Config{Data: map[string]interface{}{"location": "/mnt/backups", "compress": true}},
+	}
+	dailyPolicy = esv1.SnapshotLifecyclePolicy{
+		Name:       "daily",
+		Schedule:   "0 30 1 * * ?",
+		Repository: "backups",
+		Retention:  &commonv1.Config{Data: map[string]interface{}{"expire_after": "30d"}},
+	}
+)
+
+func TestReconcile(t *testing.T) {
+	lastSuccess := time.Date(2021, 7, 14, 12, 8, 32, 0, time.UTC)
+	tests := []struct {
+		name                    string
+		es                      esv1.Elasticsearch
+		esClient                *fakeESClient
+		wantStatuses            []esv1.SnapshotLifecyclePolicyStatus
+		wantRequeue             bool
+		wantPutRepositories     []string
+		wantDeletedRepositories []string
+		wantPutPolicies         []string
+		wantDeletedPolicies     []string
+		wantSLMCalled           bool
+		wantAnnotations         map[string]string
+	}{
+		{
+			name:     "nothing to manage",
+			es:       newEs(nil, esv1.SnapshotsSpec{}),
+			esClient: &fakeESClient{},
+		},
+		{
+			name:                "register a repository without calling the SLM API",
+			es:                  newEs(nil, esv1.SnapshotsSpec{Repositories: []esv1.SnapshotRepository{fsRepository}}),
+			esClient:            &fakeESClient{repositories: esclient.SnapshotRepositories{}},
+			wantPutRepositories: []string{"backups"},
+			wantAnnotations:     map[string]string{ManagedRepositoriesAnnotationName: "backups"},
+		},
+		{
+			name: "repository and policy already up to date",
+			es: newEs(
+				map[string]string{ManagedRepositoriesAnnotationName: "backups", ManagedPoliciesAnnotationName: "daily"},
+				esv1.SnapshotsSpec{Repositories: []esv1.SnapshotRepository{fsRepository}, Policies: []esv1.SnapshotLifecyclePolicy{dailyPolicy}},
+			),
+			esClient: &fakeESClient{
+				repositories: esclient.SnapshotRepositories{
+					// Elasticsearch returns the settings as strings
+					"backups": {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/backups", "compress": "true"}},
+				},
+				policies: esclient.SnapshotLifecyclePolicies{
+					"daily": {
+						Policy: esclient.SnapshotLifecyclePolicy{
+							Name:       "<daily-{now/d}>",
+							Schedule:   "0 30 1 * * ?",
+							Repository: "backups",
+							Retention:  map[string]interface{}{"expire_after": "30d"},
+						},
+						LastSuccess: &esclient.SnapshotInvocation{SnapshotName: "daily-2021.07.14", Time: lastSuccess.UnixNano() / int64(time.Millisecond)},
+					},
+				},
+			},
+			wantStatuses: []esv1.SnapshotLifecyclePolicyStatus{
+				{Name: "daily", LastSuccess: &metav1.Time{Time: lastSuccess}},
+			},
+			wantSLMCalled: true,
+			wantAnnotations: map[string]string{
+				ManagedRepositoriesAnnotationName: "backups",
+				ManagedPoliciesAnnotationName:     "daily",
+			},
+		},
+		{
+			name: "update a policy and delete the ones removed from the spec",
+			es: newEs(
+				map[string]string{ManagedRepositoriesAnnotationName: "backups,old,gone", ManagedPoliciesAnnotationName: "daily,hourly"},
+				esv1.SnapshotsSpec{Repositories: []esv1.SnapshotRepository{fsRepository}, Policies: []esv1.SnapshotLifecyclePolicy{dailyPolicy}},
+			),
+			esClient: &fakeESClient{
+				repositories: esclient.SnapshotRepositories{
+					"backups":      {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/backups", "compress": "true"}},
+					"old":          {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/old"}},
+					"user-managed": {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/user"}},
+				},
+				policies: esclient.SnapshotLifecyclePolicies{
+					"daily": {Policy: esclient.SnapshotLifecyclePolicy{
+						Name:       "<daily-{now/d}>",
+						Schedule:   "0 0 1 * * ?",
+						Repository: "backups",
+					}},
+					"hourly": {Policy: esclient.SnapshotLifecyclePolicy{Name: "<hourly-{now/d}>", Schedule: "0 0 * * * ?", Repository: "old"}},
+				},
+			},
+			wantStatuses:            []esv1.SnapshotLifecyclePolicyStatus{{Name: "daily"}},
+			wantRequeue:             true,
+			wantPutPolicies:         []string{"daily"},
+			wantDeletedPolicies:     []string{"hourly"},
+			wantDeletedRepositories: []string{"old"},
+			wantSLMCalled:           true,
+			wantAnnotations: map[string]string{
+				ManagedRepositoriesAnnotationName: "backups,old",
+				ManagedPoliciesAnnotationName:     "daily,hourly",
+			},
+		},
+		{
+			name: "remove the annotations once everything is deleted",
+			es: newEs(
+				map[string]string{ManagedRepositoriesAnnotationName: "old", ManagedPoliciesAnnotationName: "hourly"},
+				esv1.SnapshotsSpec{},
+			),
+			esClient: &fakeESClient{
+				repositories: esclient.SnapshotRepositories{},
+				policies:     esclient.SnapshotLifecyclePolicies{},
+			},
+			wantSLMCalled:   true,
+			wantAnnotations: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(&tt.es)
+			statuses, requeue, err := Reconcile(context.Background(), c, tt.esClient, tt.es)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatuses, statuses)
+			require.Equal(t, tt.wantRequeue, requeue)
+			require.Equal(t, tt.wantPutRepositories, tt.esClient.putRepositories)
+			require.Equal(t, tt.wantDeletedRepositories, tt.esClient.deletedRepositories)
+			require.Equal(t, tt.wantPutPolicies, tt.esClient.putPolicies)
+			require.Equal(t, tt.wantDeletedPolicies, tt.esClient.deletedPolicies)
+			require.Equal(t, tt.wantSLMCalled, tt.esClient.slmCalled)
+
+			var es esv1.Elasticsearch
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &es))
+			for _, annotation := range []string{ManagedRepositoriesAnnotationName, ManagedPoliciesAnnotationName} {
+				require.Equal(t, tt.wantAnnotations[annotation], es.Annotations[annotation])
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stackmon
+
+import (
+	"fmt"
+	"path/filepath"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/network"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	esvolume "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// filebeatDataVolumeName is the name of the volume holding the Filebeat registry
+	filebeatDataVolumeName = "filebeat-data"
+	filebeatDataMountPath  = "/usr/share/filebeat/data"
+)
+
+var (
+	// metricbeatMetricsets are the Elasticsearch metricsets collected by Metricbeat
+	metricbeatMetricsets = []string{
+		"ccr",
+		"cluster_stats",
+		"enrich",
+		"index",
+		"index_recovery",
+		"index_summary",
+		"ml_job",
+		"node_stats",
+		"shard",
+	}
+
+	// filebeatConfig is the Filebeat configuration to collect the logs of the local Elasticsearch node
+	filebeatConfig = settings.MustParseConfig([]byte(`filebeat.modules:
+- module: elasticsearch
+  server:
+    enabled: true
+    var.paths:
+    - /usr/share/elasticsearch/logs/*_server.json
+  gc:
+    enabled: true
+    var.paths:
+    - /usr/share/elasticsearch/logs/gc.log.[0-9]*
+    - /usr/share/elasticsearch/logs/gc.log
+  audit:
+    enabled: true
+    var.paths:
+    - /usr/share/elasticsearch/logs/*_audit.json
+  slowlog:
+    enabled: true
+    var.paths:
+    - /usr/share/elasticsearch/logs/*_index_search_slowlog.json
+    - /usr/share/elasticsearch/logs/*_index_indexing_slowlog.json
+  deprecation:
+    enabled: true
+    var.paths:
+    - /usr/share/elasticsearch/logs/*_deprecation.json
+processors:
+- add_cloud_metadata: {}
+- add_host_metadata: {}
+`))
+)
+
+// httpCertificatesVolume returns the volume holding the HTTP certificates of Elasticsearch, shared with the
+// Metricbeat sidecar to verify the certificate of the local Elasticsearch node.
+func httpCertificatesVolume(es esv1.Elasticsearch) volume.SecretVolume {
+	return volume.NewSecretVolumeWithMountPath(
+		certificates.InternalCertsSecretName(esv1.ESNamer, es.Name),
+		esvolume.HTTPCertificatesSecretVolumeName,
+		esvolume.HTTPCertificatesSecretVolumeMountPath,
+	)
+}
+
+// metricbeatConfig builds the Metricbeat configuration to collect the metrics of the local Elasticsearch node.
+func metricbeatConfig(client k8s.Client, es esv1.Elasticsearch) (*settings.CanonicalConfig, error) {
+	password, err := user.GetMonitoringUserPassword(client, k8s.ExtractNamespacedName(&es))
+	if err != nil {
+		return nil, err
+	}
+
+	module := map[string]interface{}{
+		"module":        "elasticsearch",
+		"metricsets":    metricbeatMetricsets,
+		"period":        "10s",
+		"xpack.enabled": true,
+		"hosts":         []string{fmt.Sprintf("%s://localhost:%d", es.Spec.HTTP.Protocol(), network.HTTPPort)},
+		"username":      user.MonitoringUserName,
+		"password":      password,
+	}
+	if es.Spec.HTTP.TLS.Enabled() {
+		module["ssl.certificate_authorities"] = []string{
+			filepath.Join(esvolume.HTTPCertificatesSecretVolumeMountPath, certificates.CAFileName),
+		}
+		// the certificate is not issued for localhost, only verify that it is signed by the CA
+		module["ssl.verification_mode"] = "certificate"
+	}
+
+	return settings.NewCanonicalConfigFrom(map[string]interface{}{
+		"metricbeat.modules": []interface{}{module},
+		"processors": []interface{}{
+			map[string]interface{}{"add_cloud_metadata": map[string]interface{}{}},
+			map[string]interface{}{"add_host_metadata": map[string]interface{}{}},
+		},
+	})
+}
+
+// Metricbeat returns the Metricbeat sidecar to collect the metrics of the Elasticsearch cluster.
+func Metricbeat(client k8s.Client, es esv1.Elasticsearch) (stackmon.BeatSidecar, error) {
+	config, err := metricbeatConfig(client, es)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err
+	}
+	return stackmon.NewMetricBeatSidecar(client, &es, esv1.ESNamer, es.Spec.Version, config, httpCertificatesVolume(es))
+}
+
+// Filebeat returns the Filebeat sidecar to collect the logs of the Elasticsearch cluster.
+func Filebeat(client k8s.Client, es esv1.Elasticsearch) (stackmon.BeatSidecar, error) {
+	return stackmon.NewFileBeatSidecar(client, &es, esv1.ESNamer, es.Spec.Version, filebeatConfig,
+		volume.NewEmptyDirVolume(esvolume.ElasticsearchLogsVolumeName, esvolume.ElasticsearchLogsMountPath),
+		volume.NewEmptyDirVolume(filebeatDataVolumeName, filebeatDataMountPath),
+	)
+}
@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stackmon
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// esLogStyleEnvVarKey is the environment variable to configure the style of the Elasticsearch logs,
+	// `file` is required for Filebeat to read the logs from the logs directory.
+	esLogStyleEnvVarKey = "ES_LOG_STYLE"
+)
+
+// MonitoringConfig returns the Elasticsearch settings required to enable the collection of monitoring data
+// by Metricbeat, if metrics collection is defined.
+func MonitoringConfig(es esv1.Elasticsearch) *settings.CanonicalConfig {
+	if !monitoring.IsMetricsDefined(&es) {
+		return settings.NewCanonicalConfig()
+	}
+	return settings.MustCanonicalConfig(map[string]interface{}{
+		esv1.XPackMonitoringCollectionEnabled: true,
+		// the legacy internal collection is disabled in favour of Metricbeat
+		esv1.XPackMonitoringElasticsearchCollectionEnabled: false,
+	})
+}
+
+// sidecars returns the Beat sidecars to deploy in the Elasticsearch Pods according to the monitoring spec.
+func sidecars(client k8s.Client, es esv1.Elasticsearch) ([]stackmon.BeatSidecar, error) {
+	var sidecars []stackmon.BeatSidecar
+	if monitoring.IsMetricsDefined(&es) {
+		b, err := Metricbeat(client, es)
+		if err != nil {
+			return nil, err
+		}
+		sidecars = append(sidecars, b)
+	}
+	if monitoring.IsLogsDefined(&es) {
+		b, err := Filebeat(client, es)
+		if err != nil {
+			return nil, err
+		}
+		sidecars = append(sidecars, b)
+	}
+	return sidecars, nil
+}
+
+// ReconcileConfigSecrets reconciles the secrets holding the configuration of the Beat sidecars.
+func ReconcileConfigSecrets(client k8s.Client, es esv1.Elasticsearch) error {
+	sidecars, err := sidecars(client, es)
+	if err != nil {
+		return err
+	}
+	return stackmon.ReconcileConfigSecrets(client, &es, label.NewLabels(k8s.ExtractNamespacedName(&es)), sidecars...)
+}
+
+// WithMonitoring updates the Elasticsearch Pod template builder to deploy Metricbeat and Filebeat in sidecar containers
+// in the Elasticsearch pod and injects the volumes for the beat configurations and the ES CA certificates.
+func WithMonitoring(client k8s.Client, builder *defaults.PodTemplateBuilder, es esv1.Elasticsearch) (*defaults.PodTemplateBuilder, error) {
+	// No monitoring defined, skip
+	if !monitoring.IsDefined(&es) {
+		return builder, nil
+	}
+
+	if monitoring.IsLogsDefined(&es) {
+		// enable Stack logging to write the logs to disk
+		builder.WithEnv(corev1.EnvVar{Name: esLogStyleEnvVarKey, Value: "file"})
+	}
+
+	sidecars, err := sidecars(client, es)
+	if err != nil {
+		return nil, err
+	}
+	return stackmon.WithMonitoring(builder, sidecars...), nil
+}
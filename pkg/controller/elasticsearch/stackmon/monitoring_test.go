@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stackmon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func TestMonitoringConfig(t *testing.T) {
+	keys := []string{esv1.XPackMonitoringCollectionEnabled, esv1.XPackMonitoringElasticsearchCollectionEnabled}
+
+	es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "7.14.0"}}
+	assert.Empty(t, MonitoringConfig(es).HasKeys(keys))
+
+	es.Spec.Monitoring.Metrics.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring"}}
+	assert.ElementsMatch(t, keys, MonitoringConfig(es).HasKeys(keys))
+}
+
+func TestWithMonitoring(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sample",
+			Namespace: "aerospace",
+		},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "7.14.0",
+			Monitoring: commonv1.Monitoring{
+				Metrics: commonv1.MetricsMonitoring{
+					ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "monitoring"}},
+				},
+				Logs: commonv1.LogsMonitoring{
+					ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "monitoring"}},
+				},
+			},
+		},
+	}
+	es.MonitoringAssociation(commonv1.ObjectSelector{Name: "monitoring"}).SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "sample-es-monitoring-user",
+		AuthSecretKey:  "aerospace-sample-es-monitoring-user",
+		CASecretName:   "sample-es-monitoring-ca",
+		URL:            "https://monitoring-es-http.aerospace.svc:9200",
+		Version:        "7.14.0",
+	})
+
+	client := k8s.NewFakeClient(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: esv1.InternalUsersSecret(es.Name), Namespace: es.Namespace},
+			Data:       map[string][]byte{user.MonitoringUserName: []byte("1234567890")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sample-es-monitoring-user", Namespace: es.Namespace},
+			Data:       map[string][]byte{"aerospace-sample-es-monitoring-user": []byte("0987654321")},
+		},
+	)
+
+	builder := defaults.NewPodTemplateBuilder(corev1.PodTemplateSpec{}, esv1.ElasticsearchContainerName)
+	builder, err := WithMonitoring(client, builder, es)
+	require.NoError(t, err)
+
+	containers := builder.PodTemplate.Spec.Containers
+	require.Len(t, containers, 3)
+	assert.Equal(t, esv1.ElasticsearchContainerName, containers[0].Name)
+	assert.Equal(t, "metricbeat", containers[1].Name)
+	assert.Equal(t, "filebeat", containers[2].Name)
+	assert.Contains(t, containers[0].Env, corev1.EnvVar{Name: esLogStyleEnvVarKey, Value: "file"})
+	assert.Contains(t, builder.PodTemplate.Annotations, "monitoring.k8s.elastic.co/metricbeat-config-hash")
+	assert.Contains(t, builder.PodTemplate.Annotations, "monitoring.k8s.elastic.co/filebeat-config-hash")
+
+	volumeNames := make([]string, 0, len(builder.PodTemplate.Spec.Volumes))
+	for _, v := range builder.PodTemplate.Spec.Volumes {
+		volumeNames = append(volumeNames, v.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"metricbeat-config", "metricbeat-es-monitoring-ca", "elastic-internal-http-certificates",
+		"filebeat-config", "filebeat-es-monitoring-ca", "elasticsearch-logs", "filebeat-data",
+	}, volumeNames)
+}
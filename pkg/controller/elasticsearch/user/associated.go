@@ -20,6 +20,9 @@ import (
 const (
 	// AssociatedUserType is used to annotate an associated user secret, most likely created by an association controller.
 	AssociatedUserType = "user"
+	// AssociatedAPIKeyType is used to annotate a secret which keeps track of the API keys created by an association
+	// controller. The name of the secret is the name of the API keys.
+	AssociatedAPIKeyType = "api-key"
 
 	// UserNameField is the field in the secret that contains the username.
 	UserNameField = "name"
@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/network"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/services"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/net"
+)
+
+// NewElasticsearchClient returns a client for the given Elasticsearch cluster, authenticated as the controller user,
+// to be used by controllers other than the Elasticsearch controller.
+func NewElasticsearchClient(
+	ctx context.Context,
+	c k8s.Client,
+	dialer net.Dialer,
+	es esv1.Elasticsearch,
+) (esclient.Client, error) {
+	defer tracing.Span(&ctx)()
+	url := services.ExternalServiceURL(es)
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return nil, err
+	}
+	// Get user Secret
+	var controllerUserSecret corev1.Secret
+	key := types.NamespacedName{
+		Namespace: es.Namespace,
+		Name:      esv1.InternalUsersSecret(es.Name),
+	}
+	if err := c.Get(context.Background(), key, &controllerUserSecret); err != nil {
+		return nil, err
+	}
+	password, ok := controllerUserSecret.Data[ControllerUserName]
+	if !ok {
+		return nil, fmt.Errorf("controller user %s not found in Secret %s/%s", ControllerUserName, key.Namespace, key.Name)
+	}
+
+	caCerts, err := publicCACertificates(c, es)
+	if err != nil {
+		return nil, err
+	}
+	return esclient.NewElasticsearchClient(
+		dialer,
+		url,
+		esclient.BasicAuth{
+			Name:     ControllerUserName,
+			Password: string(password),
+		},
+		v,
+		caCerts,
+		esclient.Timeout(es),
+	), nil
+}
+
+// publicCACertificates returns the CA certificates of the HTTP layer of the given Elasticsearch cluster.
+func publicCACertificates(c k8s.Client, es esv1.Elasticsearch) ([]*x509.Certificate, error) {
+	var caSecret corev1.Secret
+	key := types.NamespacedName{
+		Namespace: es.Namespace,
+		Name:      certificates.PublicCertsSecretName(esv1.ESNamer, es.Name),
+	}
+	if err := c.Get(context.Background(), key, &caSecret); err != nil {
+		return nil, err
+	}
+	trustedCerts, ok := caSecret.Data[certificates.CertFileName]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in Secret %s/%s", certificates.CertFileName, key.Namespace, key.Name)
+	}
+	return certificates.ParsePEMCerts(trustedCerts)
+}
+
+// CredentialsVerifier returns true if the given credentials, as the value of an Authorization header, are accepted by
+// all the ready nodes of an Elasticsearch cluster.
+type CredentialsVerifier func(authorization string) (bool, error)
+
+// NewCredentialsVerifier returns a CredentialsVerifier which authenticates the credentials on every ready Pod of the
+// given Elasticsearch cluster. Each node reloads the file realm and the service tokens separately once the Secret
+// volume is updated: new credentials can only be handed over to clients once all of them accept them.
+// Pods which are not ready or cannot be reached are not waited for, as they may stay so for an unbounded time while
+// the nodes that already reloaded the credentials reject the previous ones. They get the new credentials from the
+// Secret volume as well.
+func NewCredentialsVerifier(ctx context.Context, c k8s.Client, dialer net.Dialer, es esv1.Elasticsearch) CredentialsVerifier {
+	return func(authorization string) (bool, error) {
+		v, err := version.Parse(es.Spec.Version)
+		if err != nil {
+			return false, err
+		}
+		caCerts, err := publicCACertificates(c, es)
+		if err != nil {
+			return false, err
+		}
+		var pods corev1.PodList
+		if err := c.List(context.Background(), &pods, client.InNamespace(es.Namespace), label.NewLabelSelectorForElasticsearch(es)); err != nil {
+			return false, err
+		}
+		accepted := 0
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning || !k8s.IsPodReady(pod) {
+				continue
+			}
+			esClient := esclient.NewElasticsearchClient(dialer, podURL(pod), esclient.BasicAuth{}, v, caCerts, esclient.Timeout(es))
+			err := esClient.Authenticate(ctx, authorization)
+			esClient.Close()
+			switch {
+			case err == nil:
+				accepted++
+			case esclient.IsUnauthorized(err):
+				log.V(1).Info("Credentials not accepted yet", "namespace", pod.Namespace, "pod_name", pod.Name)
+				return false, nil
+			default:
+				log.V(1).Info("Cannot verify credentials, skipping Pod", "namespace", pod.Namespace, "pod_name", pod.Name, "error", err.Error())
+			}
+		}
+		// at least one node must accept the credentials for them to be usable
+		return accepted > 0, nil
+	}
+}
+
+// podURL returns the URL of the HTTP layer of the given Elasticsearch Pod.
+func podURL(pod corev1.Pod) string {
+	return fmt.Sprintf(
+		"%s://%s.%s.%s:%d",
+		pod.Labels[label.HTTPSchemeLabelName], pod.Name, pod.Labels[label.StatefulSetNameLabelName], pod.Namespace, network.HTTPPort,
+	)
+}
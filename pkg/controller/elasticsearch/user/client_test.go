@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gonet "net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// podDialer routes the connections to each Pod to the address of the test server simulating it, and fails to connect
+// to the Pods without server.
+type podDialer map[string]string
+
+func (d podDialer) DialContext(ctx context.Context, network, addr string) (gonet.Conn, error) {
+	podName := strings.SplitN(addr, ".", 2)[0]
+	serverAddr, exists := d[podName]
+	if !exists {
+		return nil, errors.New("connection refused")
+	}
+	var dialer gonet.Dialer
+	return dialer.DialContext(ctx, network, serverAddr)
+}
+
+func TestNewCredentialsVerifier(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: "7.15.0"},
+	}
+	// servers accepting the given Authorization header only
+	newServer := func(accepted string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/_security/_authenticate" || r.Header.Get("Authorization") != accepted {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		}))
+	}
+	upToDate := newServer("new")
+	defer upToDate.Close()
+	outdated := newServer("old")
+	defer outdated.Close()
+
+	readyConditions := []corev1.PodCondition{
+		{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+		{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+	}
+	pod := func(name string, phase corev1.PodPhase, conditions []corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: es.Namespace,
+				Name:      name,
+				Labels: map[string]string{
+					label.ClusterNameLabelName:     es.Name,
+					label.StatefulSetNameLabelName: "es-es-default",
+					label.HTTPSchemeLabelName:      "http",
+				},
+			},
+			Status: corev1.PodStatus{Phase: phase, Conditions: conditions},
+		}
+	}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: certificates.PublicCertsSecretName(esv1.ESNamer, es.Name)},
+		Data:       map[string][]byte{certificates.CertFileName: nil},
+	}
+	dialer := podDialer{
+		"up-to-date": upToDate.Listener.Addr().String(),
+		"outdated":   outdated.Listener.Addr().String(),
+	}
+
+	tests := []struct {
+		name string
+		pods []runtime.Object
+		want bool
+	}{
+		{
+			name: "accepted by all the ready Pods",
+			pods: []runtime.Object{pod("up-to-date", corev1.PodRunning, readyConditions)},
+			want: true,
+		},
+		{
+			name: "rejected by a ready Pod",
+			pods: []runtime.Object{
+				pod("up-to-date", corev1.PodRunning, readyConditions),
+				pod("outdated", corev1.PodRunning, readyConditions),
+			},
+			want: false,
+		},
+		{
+			name: "rejected by a Pod which is not ready",
+			pods: []runtime.Object{
+				pod("up-to-date", corev1.PodRunning, readyConditions),
+				pod("outdated", corev1.PodRunning, nil),
+			},
+			want: true,
+		},
+		{
+			name: "a running Pod cannot be reached",
+			pods: []runtime.Object{
+				pod("up-to-date", corev1.PodRunning, readyConditions),
+				pod("unreachable", corev1.PodRunning, readyConditions),
+			},
+			want: true,
+		},
+		{
+			name: "no Pod can be reached",
+			pods: []runtime.Object{pod("unreachable", corev1.PodRunning, readyConditions)},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(append(tt.pods, caSecret)...)
+			accepted, err := NewCredentialsVerifier(context.Background(), c, dialer, es)("new")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, accepted)
+		})
+	}
+}
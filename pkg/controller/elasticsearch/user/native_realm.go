@@ -0,0 +1,414 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.elastic.co/apm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// ManagedAuthResourcesAnnotationName holds the names of the native users and role mappings which have been created by
+// the operator, grouped by kind. Native users are associated with the version of the secret entry holding the password
+// they were last given.
+const ManagedAuthResourcesAnnotationName = "elasticsearch.k8s.elastic.co/managed-auth-resources"
+
+// NativeUsersPasswordsWatchName returns the watch registered for the secrets holding the passwords of the native users.
+func NativeUsersPasswordsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-native-users-passwords", es.Namespace, es.Name)
+}
+
+// managedAuthResources maps the names of the resources created by the operator, per kind, to the version of the
+// secret entry holding their password for native users, or to an empty string.
+type managedAuthResources map[esv1.ClusterResourceKind]map[string]string
+
+func (m managedAuthResources) get(kind esv1.ClusterResourceKind, name string) (string, bool) {
+	value, exists := m[kind][name]
+	return value, exists
+}
+
+func (m managedAuthResources) set(kind esv1.ClusterResourceKind, name string, value string) {
+	if _, exists := m[kind]; !exists {
+		m[kind] = make(map[string]string)
+	}
+	m[kind][name] = value
+}
+
+func (m managedAuthResources) remove(kind esv1.ClusterResourceKind, name string) {
+	delete(m[kind], name)
+	if len(m[kind]) == 0 {
+		delete(m, kind)
+	}
+}
+
+// removed returns the sorted names of the managed resources of the given kind which are not in the given names.
+func (m managedAuthResources) removed(kind esv1.ClusterResourceKind, names []string) []string {
+	declared := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		declared[name] = struct{}{}
+	}
+	var removed []string
+	for name := range m[kind] {
+		if _, exists := declared[name]; !exists {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// getManagedAuthResources returns the resources listed in the annotation. An unparseable annotation is considered empty.
+func getManagedAuthResources(es esv1.Elasticsearch) managedAuthResources {
+	resources := make(managedAuthResources)
+	serialized, exists := es.Annotations[ManagedAuthResourcesAnnotationName]
+	if !exists {
+		return resources
+	}
+	if err := json.Unmarshal([]byte(serialized), &resources); err != nil {
+		log.Error(err, "Ignoring invalid annotation", "annotation", ManagedAuthResourcesAnnotationName, "namespace", es.Namespace, "es_name", es.Name)
+		return make(managedAuthResources)
+	}
+	return resources
+}
+
+// annotateWithManagedAuthResources serializes the managed resources in the annotation, or removes the annotation if
+// there are none. The Elasticsearch resource is only updated if the annotation has changed.
+func annotateWithManagedAuthResources(c k8s.Client, es *esv1.Elasticsearch, resources managedAuthResources) error {
+	if reflect.DeepEqual(getManagedAuthResources(*es), resources) {
+		return nil
+	}
+	if len(resources) == 0 {
+		delete(es.Annotations, ManagedAuthResourcesAnnotationName)
+		return c.Update(context.Background(), es)
+	}
+	serialized, err := json.Marshal(resources)
+	if err != nil {
+		return err
+	}
+	if es.Annotations == nil {
+		es.Annotations = make(map[string]string)
+	}
+	es.Annotations[ManagedAuthResourcesAnnotationName] = string(serialized)
+	return c.Update(context.Background(), es)
+}
+
+// ReconcileNativeRealm creates or updates in Elasticsearch the native users and the role mappings declared in the
+// specification, and deletes the ones previously created by the operator which have been removed from it.
+// Native users and role mappings which already exist in Elasticsearch, but have not been created by the operator,
+// are left untouched and reported with the Conflict phase.
+// The password of a native user is only set when the user is created or when its referenced secret changes, so
+// that a password changed through the Elasticsearch API is not reset at every reconciliation.
+// It returns the status of each declared resource, and an aggregated error if any resource could not be reconciled.
+func ReconcileNativeRealm(
+	ctx context.Context,
+	c k8s.Client,
+	esClient esclient.SecurityClient,
+	watched watches.DynamicWatches,
+	es esv1.Elasticsearch,
+) ([]esv1.ClusterResourceStatus, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_native_realm", tracing.SpanTypeApp)
+	defer span.End()
+
+	if err := watchNativeUsersPasswords(es, watched); err != nil {
+		return nil, err
+	}
+
+	auth := es.Spec.Auth
+	managed := getManagedAuthResources(es)
+	if len(auth.NativeUsers) == 0 && len(auth.RoleMappings) == 0 && len(managed) == 0 {
+		return nil, nil
+	}
+	// the annotations of the resource may be updated twice, do not mutate the ones of the caller
+	es = *es.DeepCopy()
+
+	var errs []error
+	var statuses []esv1.ClusterResourceStatus
+	passwords := make(map[string][]byte, len(auth.NativeUsers))
+	passwordVersions := make(map[string]string, len(auth.NativeUsers))
+	usernames := make([]string, 0, len(auth.NativeUsers))
+	for _, user := range auth.NativeUsers {
+		usernames = append(usernames, user.Username)
+		password, passwordVersion, err := getNativeUserPassword(c, es.Namespace, user.PasswordSecretRef)
+		if err != nil {
+			statuses = append(statuses, invalidStatus(esv1.NativeUserKind, user.Username, err))
+			errs = append(errs, err)
+			continue
+		}
+		status, err := claim(managed, esv1.NativeUserKind, user.Username, func() error {
+			_, err := esClient.GetNativeUser(context.Background(), user.Username)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+		statuses = append(statuses, status)
+		passwords[user.Username] = password
+		passwordVersions[user.Username] = passwordVersion
+	}
+	mappingNames := make([]string, 0, len(auth.RoleMappings))
+	for _, mapping := range auth.RoleMappings {
+		mappingNames = append(mappingNames, mapping.Name)
+		status, err := claim(managed, esv1.RoleMappingKind, mapping.Name, func() error {
+			_, err := esClient.GetRoleMapping(context.Background(), mapping.Name)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	// record the resources about to be created before creating them, so that they are not considered as created by
+	// someone else if the annotation cannot be updated afterwards
+	if err := annotateWithManagedAuthResources(c, &es, managed); err != nil {
+		return nil, err
+	}
+
+	for i, user := range auth.NativeUsers {
+		if statuses[i].Phase != esv1.ClusterResourceAppliedPhase {
+			continue
+		}
+		passwordVersion := passwordVersions[user.Username]
+		if err := reconcileNativeUser(esClient, managed, es, user, passwords[user.Username], passwordVersion); err != nil {
+			statuses[i] = failedStatus(esv1.NativeUserKind, user.Username, err)
+			errs = append(errs, err)
+			continue
+		}
+		managed.set(esv1.NativeUserKind, user.Username, passwordVersion)
+	}
+	for i, mapping := range auth.RoleMappings {
+		j := len(auth.NativeUsers) + i
+		if statuses[j].Phase != esv1.ClusterResourceAppliedPhase {
+			continue
+		}
+		if err := reconcileRoleMapping(esClient, es, mapping); err != nil {
+			statuses[j] = failedStatus(esv1.RoleMappingKind, mapping.Name, err)
+			errs = append(errs, err)
+		}
+	}
+
+	for _, name := range managed.removed(esv1.NativeUserKind, usernames) {
+		log.Info("Deleting native user", "namespace", es.Namespace, "es_name", es.Name, "username", name)
+		if err := esClient.DeleteNativeUser(context.Background(), name); err != nil && !esclient.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		managed.remove(esv1.NativeUserKind, name)
+	}
+	for _, name := range managed.removed(esv1.RoleMappingKind, mappingNames) {
+		log.Info("Deleting role mapping", "namespace", es.Namespace, "es_name", es.Name, "role_mapping", name)
+		if err := esClient.DeleteRoleMapping(context.Background(), name); err != nil && !esclient.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		managed.remove(esv1.RoleMappingKind, name)
+	}
+
+	if err := annotateWithManagedAuthResources(c, &es, managed); err != nil {
+		errs = append(errs, err)
+	}
+	return statuses, utilerrors.NewAggregate(errs)
+}
+
+// watchNativeUsersPasswords ensures that the secrets holding the passwords of the native users are watched for future
+// reconciliations to be triggered on any change.
+func watchNativeUsersPasswords(es esv1.Elasticsearch, watched watches.DynamicWatches) error {
+	esKey := k8s.ExtractNamespacedName(&es)
+	secretNames := make([]string, 0, len(es.Spec.Auth.NativeUsers))
+	for _, user := range es.Spec.Auth.NativeUsers {
+		secretNames = append(secretNames, user.PasswordSecretRef.SecretName)
+	}
+	return watches.WatchUserProvidedSecrets(esKey, watched, NativeUsersPasswordsWatchName(esKey), secretNames)
+}
+
+// getNativeUserPassword returns the password of a native user read from the referenced secret, along with the version
+// of the secret entry holding it.
+func getNativeUserPassword(c k8s.Client, namespace string, ref esv1.NativeUserPasswordSource) ([]byte, string, error) {
+	var secret corev1.Secret
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: ref.SecretName}, &secret); err != nil {
+		return nil, "", err
+	}
+	password, exists := secret.Data[ref.Key]
+	if !exists || len(password) == 0 {
+		return nil, "", fmt.Errorf("key %s not found in secret %s", ref.Key, ref.SecretName)
+	}
+	return password, passwordVersion(secret, ref.Key), nil
+}
+
+// passwordVersion identifies the version of a secret entry holding a password, without revealing anything about the
+// password itself. It changes whenever the secret is updated or replaced.
+func passwordVersion(secret corev1.Secret, key string) string {
+	return fmt.Sprintf("%s/%s/%s", secret.UID, secret.ResourceVersion, key)
+}
+
+// claim checks whether a resource declared in the specification can be managed by the operator. A resource which does
+// not exist yet in Elasticsearch is added to the managed resources. A resource which exists but has not been created
+// by the operator is reported with the Conflict phase.
+func claim(
+	managed managedAuthResources,
+	kind esv1.ClusterResourceKind,
+	name string,
+	get func() error,
+) (esv1.ClusterResourceStatus, error) {
+	status := esv1.ClusterResourceStatus{Kind: kind, Name: name, Phase: esv1.ClusterResourceAppliedPhase}
+	if _, isManaged := managed.get(kind, name); isManaged {
+		return status, nil
+	}
+	err := get()
+	switch {
+	case err == nil:
+		status.Phase = esv1.ClusterResourceConflictPhase
+		status.Message = fmt.Sprintf("%s %s already exists in Elasticsearch and has not been created by the operator", kind, name)
+		return status, nil
+	case esclient.IsNotFound(err):
+		managed.set(kind, name, "")
+		return status, nil
+	default:
+		return failedStatus(kind, name, err), err
+	}
+}
+
+// reconcileNativeUser creates or updates a native user in Elasticsearch. The password is only set if the user does not
+// exist or if the secret entry holding it has changed since the password was last applied by the operator.
+func reconcileNativeUser(
+	esClient esclient.SecurityClient,
+	managed managedAuthResources,
+	es esv1.Elasticsearch,
+	user esv1.NativeUser,
+	password []byte,
+	passwordVersion string,
+) error {
+	metadata, err := configData(user.Metadata)
+	if err != nil {
+		return err
+	}
+	expected := esclient.NativeUser{
+		Username: user.Username,
+		Roles:    user.Roles,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Metadata: metadata,
+		Enabled:  true,
+	}
+
+	if appliedVersion, _ := managed.get(esv1.NativeUserKind, user.Username); appliedVersion == passwordVersion {
+		actual, err := esClient.GetNativeUser(context.Background(), user.Username)
+		if err != nil && !esclient.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if nativeUserMatches(expected, actual) {
+				return nil
+			}
+			// keep the current password
+			log.Info("Updating native user", "namespace", es.Namespace, "es_name", es.Name, "username", user.Username)
+			return esClient.PutNativeUser(context.Background(), expected)
+		}
+	}
+
+	log.Info("Setting native user password", "namespace", es.Namespace, "es_name", es.Name, "username", user.Username)
+	expected.Password = string(password)
+	return esClient.PutNativeUser(context.Background(), expected)
+}
+
+// reconcileRoleMapping creates or updates a role mapping in Elasticsearch if it does not match its specification.
+func reconcileRoleMapping(esClient esclient.SecurityClient, es esv1.Elasticsearch, mapping esv1.RoleMapping) error {
+	rules, err := configData(mapping.Rules)
+	if err != nil {
+		return err
+	}
+	metadata, err := configData(mapping.Metadata)
+	if err != nil {
+		return err
+	}
+	expected := esclient.RoleMapping{
+		Roles:    mapping.Roles,
+		Rules:    rules,
+		Enabled:  mapping.IsEnabled(),
+		Metadata: metadata,
+	}
+
+	actual, err := esClient.GetRoleMapping(context.Background(), mapping.Name)
+	if err != nil && !esclient.IsNotFound(err) {
+		return err
+	}
+	if err == nil && roleMappingMatches(expected, actual) {
+		return nil
+	}
+	log.Info("Updating role mapping", "namespace", es.Namespace, "es_name", es.Name, "role_mapping", mapping.Name)
+	return esClient.PutRoleMapping(context.Background(), mapping.Name, expected)
+}
+
+func nativeUserMatches(expected, actual esclient.NativeUser) bool {
+	return sameItems(expected.Roles, actual.Roles) &&
+		expected.FullName == actual.FullName &&
+		expected.Email == actual.Email &&
+		sameData(expected.Metadata, actual.Metadata) &&
+		expected.Enabled == actual.Enabled
+}
+
+func roleMappingMatches(expected, actual esclient.RoleMapping) bool {
+	return sameItems(expected.Roles, actual.Roles) &&
+		sameData(expected.Rules, actual.Rules) &&
+		sameData(expected.Metadata, actual.Metadata) &&
+		expected.Enabled == actual.Enabled
+}
+
+// sameItems returns true if both slices contain the same items, in any order.
+func sameItems(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return reflect.DeepEqual(sortedA, sortedB)
+}
+
+// sameData returns true if both maps are deeply equal, considering nil and empty maps as equal.
+func sameData(a, b map[string]interface{}) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// configData returns the data of the given config, round-tripped through JSON to work with the same types as the
+// ones returned by Elasticsearch.
+func configData(config *commonv1.Config) (map[string]interface{}, error) {
+	if config == nil {
+		return nil, nil
+	}
+	bytes, err := json.Marshal(config.Data)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	err = json.Unmarshal(bytes, &data)
+	return data, err
+}
+
+func invalidStatus(kind esv1.ClusterResourceKind, name string, err error) esv1.ClusterResourceStatus {
+	return esv1.ClusterResourceStatus{Kind: kind, Name: name, Phase: esv1.ClusterResourceInvalidPhase, Message: err.Error()}
+}
+
+func failedStatus(kind esv1.ClusterResourceKind, name string, err error) esv1.ClusterResourceStatus {
+	return esv1.ClusterResourceStatus{Kind: kind, Name: name, Phase: esv1.ClusterResourceFailedPhase, Message: err.Error()}
+}
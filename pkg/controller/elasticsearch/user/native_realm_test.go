@@ -0,0 +1,247 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// errNotFound is an Elasticsearch API error for a resource which does not exist.
+var errNotFound = func() error {
+	_, err := esclient.NewMockClient(version.MustParse("7.15.0"), func(req *http.Request) *http.Response {
+		return esclient.NewMockResponse(404, req, "{}")
+	}).GetNativeUser(context.Background(), "missing")
+	return err
+}()
+
+// fakeSecurityClient stores the native users and the role mappings in memory.
+type fakeSecurityClient struct {
+	users    map[string]esclient.NativeUser
+	mappings map[string]esclient.RoleMapping
+	puts     []string
+	deletes  []string
+}
+
+func (f *fakeSecurityClient) GetNativeUser(_ context.Context, username string) (esclient.NativeUser, error) {
+	if user, exists := f.users[username]; exists {
+		return user, nil
+	}
+	return esclient.NativeUser{}, errNotFound
+}
+
+func (f *fakeSecurityClient) PutNativeUser(_ context.Context, user esclient.NativeUser) error {
+	put := "user/" + user.Username
+	if user.Password != "" {
+		put += ":" + user.Password
+	}
+	f.puts = append(f.puts, put)
+	user.Password = ""
+	f.users[user.Username] = user
+	return nil
+}
+
+func (f *fakeSecurityClient) DeleteNativeUser(_ context.Context, username string) error {
+	f.deletes = append(f.deletes, "user/"+username)
+	delete(f.users, username)
+	return nil
+}
+
+func (f *fakeSecurityClient) GetRoleMapping(_ context.Context, name string) (esclient.RoleMapping, error) {
+	if mapping, exists := f.mappings[name]; exists {
+		return mapping, nil
+	}
+	return esclient.RoleMapping{}, errNotFound
+}
+
+func (f *fakeSecurityClient) PutRoleMapping(_ context.Context, name string, mapping esclient.RoleMapping) error {
+	f.puts = append(f.puts, "role_mapping/"+name)
+	f.mappings[name] = mapping
+	return nil
+}
+
+func (f *fakeSecurityClient) DeleteRoleMapping(_ context.Context, name string) error {
+	f.deletes = append(f.deletes, "role_mapping/"+name)
+	delete(f.mappings, name)
+	return nil
+}
+
+func (f *fakeSecurityClient) Authenticate(_ context.Context, _ string) error {
+	return nil
+}
+
+func TestReconcileNativeRealm(t *testing.T) {
+	nativeUser := esv1.NativeUser{
+		Username:          "jacknich",
+		PasswordSecretRef: esv1.NativeUserPasswordSource{SecretName: "passwords", Key: "jacknich"},
+		Roles:             []string{"superuser"},
+	}
+	roleMapping := esv1.RoleMapping{
+		Name:  "saml-users",
+		Roles: []string{"viewer"},
+		Rules: &commonv1.Config{Data: map[string]interface{}{"field": map[string]interface{}{"realm.name": "saml1"}}},
+	}
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "passwords", UID: "3f0c8a2e", ResourceVersion: "42"},
+		Data:       map[string][]byte{"jacknich": []byte("l0ng-r4nd0m-p@ssw0rd")},
+	}
+	esUser := esclient.NativeUser{Username: "jacknich", Roles: []string{"superuser"}, Enabled: true}
+	esRoleMapping := esclient.RoleMapping{
+		Roles:   []string{"viewer"},
+		Rules:   map[string]interface{}{"field": map[string]interface{}{"realm.name": "saml1"}},
+		Enabled: true,
+	}
+	// the version of the secret entry holding the password, which does not reveal the password
+	managedUser := `{"NativeUser":{"jacknich":"3f0c8a2e/42/jacknich"}}`
+	managedUserAndMapping := `{"NativeUser":{"jacknich":"3f0c8a2e/42/jacknich"},"RoleMapping":{"saml-users":""}}`
+	newES := func(annotations map[string]string, auth esv1.Auth) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: annotations},
+			Spec:       esv1.ElasticsearchSpec{Version: "7.15.0", Auth: auth},
+		}
+	}
+	applied := func(kind esv1.ClusterResourceKind, name string) esv1.ClusterResourceStatus {
+		return esv1.ClusterResourceStatus{Kind: kind, Name: name, Phase: esv1.ClusterResourceAppliedPhase}
+	}
+
+	tests := []struct {
+		name           string
+		es             esv1.Elasticsearch
+		esClient       *fakeSecurityClient
+		wantStatuses   []esv1.ClusterResourceStatus
+		wantErr        bool
+		wantPuts       []string
+		wantDeletes    []string
+		wantAnnotation string
+	}{
+		{
+			name:     "nothing to reconcile",
+			es:       newES(nil, esv1.Auth{}),
+			esClient: &fakeSecurityClient{},
+		},
+		{
+			name: "create native user and role mapping",
+			es:   newES(nil, esv1.Auth{NativeUsers: []esv1.NativeUser{nativeUser}, RoleMappings: []esv1.RoleMapping{roleMapping}}),
+			esClient: &fakeSecurityClient{
+				users:    map[string]esclient.NativeUser{},
+				mappings: map[string]esclient.RoleMapping{},
+			},
+			wantStatuses:   []esv1.ClusterResourceStatus{applied(esv1.NativeUserKind, "jacknich"), applied(esv1.RoleMappingKind, "saml-users")},
+			wantPuts:       []string{"user/jacknich:l0ng-r4nd0m-p@ssw0rd", "role_mapping/saml-users"},
+			wantAnnotation: managedUserAndMapping,
+		},
+		{
+			name: "up-to-date native user and role mapping",
+			es: newES(
+				map[string]string{ManagedAuthResourcesAnnotationName: managedUserAndMapping},
+				esv1.Auth{NativeUsers: []esv1.NativeUser{nativeUser}, RoleMappings: []esv1.RoleMapping{roleMapping}},
+			),
+			esClient: &fakeSecurityClient{
+				users:    map[string]esclient.NativeUser{"jacknich": esUser},
+				mappings: map[string]esclient.RoleMapping{"saml-users": esRoleMapping},
+			},
+			wantStatuses:   []esv1.ClusterResourceStatus{applied(esv1.NativeUserKind, "jacknich"), applied(esv1.RoleMappingKind, "saml-users")},
+			wantAnnotation: managedUserAndMapping,
+		},
+		{
+			name: "update drifted native user without resetting its password",
+			es: newES(
+				map[string]string{ManagedAuthResourcesAnnotationName: managedUser},
+				esv1.Auth{NativeUsers: []esv1.NativeUser{nativeUser}},
+			),
+			esClient: &fakeSecurityClient{
+				users: map[string]esclient.NativeUser{"jacknich": {Username: "jacknich", Roles: []string{"viewer"}, Enabled: true}},
+			},
+			wantStatuses:   []esv1.ClusterResourceStatus{applied(esv1.NativeUserKind, "jacknich")},
+			wantPuts:       []string{"user/jacknich"},
+			wantAnnotation: managedUser,
+		},
+		{
+			name: "update the password of a native user when the secret changes",
+			es: newES(
+				map[string]string{ManagedAuthResourcesAnnotationName: `{"NativeUser":{"jacknich":"3f0c8a2e/41/jacknich"}}`},
+				esv1.Auth{NativeUsers: []esv1.NativeUser{nativeUser}},
+			),
+			esClient: &fakeSecurityClient{
+				users: map[string]esclient.NativeUser{"jacknich": esUser},
+			},
+			wantStatuses:   []esv1.ClusterResourceStatus{applied(esv1.NativeUserKind, "jacknich")},
+			wantPuts:       []string{"user/jacknich:l0ng-r4nd0m-p@ssw0rd"},
+			wantAnnotation: managedUser,
+		},
+		{
+			name: "existing native user and role mapping not created by the operator are left untouched",
+			es:   newES(nil, esv1.Auth{NativeUsers: []esv1.NativeUser{nativeUser}, RoleMappings: []esv1.RoleMapping{roleMapping}}),
+			esClient: &fakeSecurityClient{
+				users:    map[string]esclient.NativeUser{"jacknich": {Username: "jacknich", Roles: []string{"viewer"}, Enabled: true}},
+				mappings: map[string]esclient.RoleMapping{"saml-users": {Roles: []string{"editor"}, Enabled: true}},
+			},
+			wantStatuses: []esv1.ClusterResourceStatus{
+				{
+					Kind:    esv1.NativeUserKind,
+					Name:    "jacknich",
+					Phase:   esv1.ClusterResourceConflictPhase,
+					Message: "NativeUser jacknich already exists in Elasticsearch and has not been created by the operator",
+				},
+				{
+					Kind:    esv1.RoleMappingKind,
+					Name:    "saml-users",
+					Phase:   esv1.ClusterResourceConflictPhase,
+					Message: "RoleMapping saml-users already exists in Elasticsearch and has not been created by the operator",
+				},
+			},
+		},
+		{
+			name: "removed native user and role mapping are deleted",
+			es:   newES(map[string]string{ManagedAuthResourcesAnnotationName: managedUserAndMapping}, esv1.Auth{}),
+			esClient: &fakeSecurityClient{
+				users:    map[string]esclient.NativeUser{"jacknich": esUser},
+				mappings: map[string]esclient.RoleMapping{"saml-users": esRoleMapping},
+			},
+			wantDeletes: []string{"user/jacknich", "role_mapping/saml-users"},
+		},
+		{
+			name: "missing password",
+			es: newES(nil, esv1.Auth{NativeUsers: []esv1.NativeUser{{
+				Username:          "rdeniro",
+				PasswordSecretRef: esv1.NativeUserPasswordSource{SecretName: "passwords", Key: "rdeniro"},
+			}}}),
+			esClient: &fakeSecurityClient{users: map[string]esclient.NativeUser{}},
+			wantStatuses: []esv1.ClusterResourceStatus{{
+				Kind:    esv1.NativeUserKind,
+				Name:    "rdeniro",
+				Phase:   esv1.ClusterResourceInvalidPhase,
+				Message: "key rdeniro not found in secret passwords",
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(&tt.es, &secret)
+			statuses, err := ReconcileNativeRealm(context.Background(), c, tt.esClient, watches.NewDynamicWatches(), tt.es)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantStatuses, statuses)
+			require.Equal(t, tt.wantPuts, tt.esClient.puts)
+			require.Equal(t, tt.wantDeletes, tt.esClient.deletes)
+
+			var es esv1.Elasticsearch
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &es))
+			require.Equal(t, tt.wantAnnotation, es.Annotations[ManagedAuthResourcesAnnotationName])
+		})
+	}
+}
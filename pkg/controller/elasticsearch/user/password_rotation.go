@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+)
+
+const (
+	// RotatePasswordsAnnotation can be set on an Elasticsearch resource to an RFC 3339 timestamp to request the rotation
+	// of the passwords which have not been rotated since that time: the passwords of the elastic user, of the internal
+	// users of the operator and of the users created for associations. A timestamp in the future schedules the rotation.
+	RotatePasswordsAnnotation = "elasticsearch.k8s.elastic.co/rotate-passwords"
+	// PasswordRotationTimeAnnotation is set on the secrets holding passwords generated by the operator, to the time the
+	// passwords were last generated.
+	PasswordRotationTimeAnnotation = "elasticsearch.k8s.elastic.co/password-rotation-time"
+
+	// RotationCheckInterval is the interval at which an ongoing rotation checks whether the new credentials are accepted
+	// by all the nodes, or whether the clients have stopped using the previous ones.
+	RotationCheckInterval = 10 * time.Second
+)
+
+// PasswordRotation describes the rotation of generated passwords.
+type PasswordRotation struct {
+	// Time of the last rotation, which is the zero time if unknown.
+	Time time.Time
+	// RotateIn is the duration after which the passwords must be rotated, or 0 if no rotation is scheduled.
+	RotateIn time.Duration
+}
+
+// Merge returns the rotation of the passwords described by both rotations: the time of the oldest rotation, and the
+// duration until the next one.
+func (p PasswordRotation) Merge(other PasswordRotation) PasswordRotation {
+	merged := p
+	if merged.Time.IsZero() || (!other.Time.IsZero() && other.Time.Before(merged.Time)) {
+		merged.Time = other.Time
+	}
+	if merged.RotateIn == 0 || (other.RotateIn > 0 && other.RotateIn < merged.RotateIn) {
+		merged.RotateIn = other.RotateIn
+	}
+	return merged
+}
+
+// PasswordRotationTime returns the time the passwords held in the given secret were last generated. It defaults to the
+// creation time of the secret if it does not hold this information.
+func PasswordRotationTime(secret corev1.Secret) time.Time {
+	if rotationTime, err := time.Parse(time.RFC3339, secret.Annotations[PasswordRotationTimeAnnotation]); err == nil {
+		return rotationTime
+	}
+	return secret.CreationTimestamp.Time
+}
+
+// PasswordRotationDue returns true if passwords generated at the given time must be rotated now, according to the
+// rotation interval and the rotation annotation of the given Elasticsearch resource. Otherwise it returns the duration
+// after which the passwords must be rotated, or 0 if no rotation is scheduled.
+func PasswordRotationDue(es esv1.Elasticsearch, lastRotation time.Time, now time.Time) (bool, time.Duration) {
+	var next time.Time
+	if requested, err := time.Parse(time.RFC3339, es.Annotations[RotatePasswordsAnnotation]); err == nil && requested.After(lastRotation) {
+		next = requested
+	}
+	if interval := es.Spec.Auth.PasswordRotationInterval; interval != nil && interval.Duration > 0 {
+		if scheduled := lastRotation.Add(interval.Duration); next.IsZero() || scheduled.Before(next) {
+			next = scheduled
+		}
+	}
+	switch {
+	case next.IsZero():
+		return false, 0
+	case !now.Before(next):
+		return true, 0
+	default:
+		return false, next.Sub(now)
+	}
+}
+
+// NewPasswordRotation returns the state of the rotation of passwords generated at the given time.
+func NewPasswordRotation(es esv1.Elasticsearch, now time.Time) PasswordRotation {
+	now = now.Truncate(time.Second)
+	_, rotateIn := PasswordRotationDue(es, now, now)
+	return PasswordRotation{Time: now, RotateIn: rotateIn}
+}
+
+// Annotations returns the annotations recording the rotation on the secret holding the passwords.
+func (p PasswordRotation) Annotations() map[string]string {
+	if p.Time.IsZero() {
+		return nil
+	}
+	return map[string]string{PasswordRotationTimeAnnotation: p.Time.UTC().Format(time.RFC3339)}
+}
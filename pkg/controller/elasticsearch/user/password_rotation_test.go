@@ -0,0 +1,211 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user/filerealm"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+var (
+	lastRotation = time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	now          = lastRotation.Add(48 * time.Hour)
+)
+
+func esWithRotation(annotation string, interval time.Duration) esv1.Elasticsearch {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	if annotation != "" {
+		es.Annotations = map[string]string{RotatePasswordsAnnotation: annotation}
+	}
+	if interval > 0 {
+		es.Spec.Auth.PasswordRotationInterval = &metav1.Duration{Duration: interval}
+	}
+	return es
+}
+
+func TestPasswordRotationDue(t *testing.T) {
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		wantDue      bool
+		wantRotateIn time.Duration
+	}{
+		{
+			name: "no rotation",
+			es:   esWithRotation("", 0),
+		},
+		{
+			name: "invalid annotation",
+			es:   esWithRotation("yesterday", 0),
+		},
+		{
+			name: "rotation requested before the last rotation",
+			es:   esWithRotation("2021-05-31T10:00:00Z", 0),
+		},
+		{
+			name:    "rotation requested after the last rotation",
+			es:      esWithRotation("2021-06-02T10:00:00Z", 0),
+			wantDue: true,
+		},
+		{
+			name:         "rotation scheduled in the future",
+			es:           esWithRotation("2021-06-03T12:00:00Z", 0),
+			wantRotateIn: 2 * time.Hour,
+		},
+		{
+			name:    "rotation interval elapsed",
+			es:      esWithRotation("", 24*time.Hour),
+			wantDue: true,
+		},
+		{
+			name:         "rotation interval not elapsed",
+			es:           esWithRotation("", 72*time.Hour),
+			wantRotateIn: 24 * time.Hour,
+		},
+		{
+			name:         "scheduled rotation before the end of the rotation interval",
+			es:           esWithRotation("2021-06-03T12:00:00Z", 72*time.Hour),
+			wantRotateIn: 2 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, rotateIn := PasswordRotationDue(tt.es, lastRotation, now)
+			require.Equal(t, tt.wantDue, due)
+			require.Equal(t, tt.wantRotateIn, rotateIn)
+		})
+	}
+}
+
+func TestPasswordRotation_Merge(t *testing.T) {
+	require.Equal(t,
+		PasswordRotation{Time: lastRotation, RotateIn: time.Hour},
+		PasswordRotation{Time: now, RotateIn: time.Hour}.Merge(PasswordRotation{Time: lastRotation, RotateIn: 2 * time.Hour}),
+	)
+	require.Equal(t,
+		PasswordRotation{Time: now, RotateIn: time.Hour},
+		PasswordRotation{}.Merge(PasswordRotation{Time: now, RotateIn: time.Hour}),
+	)
+	require.Equal(t,
+		PasswordRotation{Time: now, RotateIn: time.Hour},
+		PasswordRotation{Time: now, RotateIn: time.Hour}.Merge(PasswordRotation{}),
+	)
+}
+
+func TestNewPasswordRotation(t *testing.T) {
+	es := esWithRotation("2021-06-02T10:00:00Z", 0)
+
+	// secret created before the requested rotation time, without annotation
+	require.Equal(t, lastRotation, PasswordRotationTime(corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: lastRotation}},
+	}))
+
+	rotation := NewPasswordRotation(es, now)
+	require.Equal(t, PasswordRotation{Time: now}, rotation)
+	require.Equal(t, map[string]string{PasswordRotationTimeAnnotation: "2021-06-03T10:00:00Z"}, rotation.Annotations())
+
+	// passwords already rotated
+	rotationTime := PasswordRotationTime(corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: rotation.Annotations()}})
+	require.Equal(t, now, rotationTime)
+	due, _ := PasswordRotationDue(es, rotationTime, now.Add(time.Minute))
+	require.False(t, due)
+}
+
+// acceptCredentials is a CredentialsVerifier for a cluster whose nodes accept all the credentials.
+func acceptCredentials(string) (bool, error) {
+	return true, nil
+}
+
+func Test_reconcileInternalUsers_rotation(t *testing.T) {
+	secretRef := types.NamespacedName{Namespace: "ns", Name: esv1.InternalUsersSecret("es")}
+	pendingRef := types.NamespacedName{Namespace: "ns", Name: esv1.PendingPasswordsSecret("es")}
+	c := k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   secretRef.Namespace,
+			Name:        secretRef.Name,
+			Annotations: PasswordRotation{Time: lastRotation}.Annotations(),
+		},
+		Data: map[string][]byte{
+			ControllerUserName: []byte("controllerUserPassword"),
+			ProbeUserName:      []byte("probeUserPassword"),
+			MonitoringUserName: []byte("monitoringUserPassword"),
+		},
+	})
+	getSecret := func(key types.NamespacedName) corev1.Secret {
+		var secret corev1.Secret
+		require.NoError(t, c.Get(context.Background(), key, &secret))
+		return secret
+	}
+	var accepted []string
+	verify := func(authorization string) (bool, error) {
+		accepted = append(accepted, authorization)
+		return len(accepted) > 1, nil
+	}
+
+	// rotation requested after the passwords were generated: new passwords are staged, the current ones are kept
+	es := esWithRotation(time.Now().Add(-time.Minute).UTC().Format(time.RFC3339), 0)
+	staged, rotation, err := reconcileInternalUsers(c, es, filerealm.New(), verify)
+	require.NoError(t, err)
+	require.Equal(t, PasswordRotation{Time: lastRotation, RotateIn: RotationCheckInterval}, rotation)
+	require.Equal(t, []byte("controllerUserPassword"), staged[0].Password)
+	require.Equal(t, []byte("probeUserPassword"), staged[1].Password)
+	pending := getSecret(pendingRef)
+	for _, u := range staged {
+		require.NotEqual(t, u.Password, u.PendingPassword)
+		require.Equal(t, u.PendingPassword, pending.Data[u.Name])
+		// the file realm holds the hash of the pending password
+		require.NoError(t, bcrypt.CompareHashAndPassword(u.PasswordHash, u.PendingPassword))
+	}
+	secret := getSecret(secretRef)
+	require.Equal(t, []byte("controllerUserPassword"), secret.Data[ControllerUserName])
+	require.Equal(t, []byte("probeUserPassword"), secret.Data[ProbeUserName])
+	require.Equal(t, staged[1].PendingPassword, secret.Data[ProbeUserFallbackPasswordKey])
+	require.Equal(t, PasswordRotation{Time: lastRotation}.Annotations(), secret.Annotations)
+
+	// the pending passwords are not accepted by all the nodes yet
+	got, rotation, err := reconcileInternalUsers(c, es, staged.fileRealm(), verify)
+	require.NoError(t, err)
+	require.Equal(t, PasswordRotation{Time: lastRotation, RotateIn: RotationCheckInterval}, rotation)
+	require.Equal(t, staged, got)
+	require.Equal(t, []string{esclient.BasicAuthorization(esclient.BasicAuth{Name: ControllerUserName, Password: string(staged[0].PendingPassword)})}, accepted)
+
+	// the pending passwords are accepted by all the nodes: they replace the current ones
+	got, rotation, err = reconcileInternalUsers(c, es, staged.fileRealm(), verify)
+	require.NoError(t, err)
+	require.True(t, rotation.Time.After(lastRotation))
+	require.Zero(t, rotation.RotateIn)
+	for i, u := range got {
+		require.Equal(t, staged[i].PendingPassword, u.Password)
+		require.Nil(t, u.PendingPassword)
+		require.Equal(t, staged[i].PasswordHash, u.PasswordHash)
+	}
+	err = c.Get(context.Background(), pendingRef, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+	secret = getSecret(secretRef)
+	require.Equal(t, got[0].Password, secret.Data[ControllerUserName])
+	require.Equal(t, got[1].Password, secret.Data[ProbeUserName])
+	require.Equal(t, []byte("probeUserPassword"), secret.Data[ProbeUserFallbackPasswordKey])
+	require.Equal(t, rotation.Annotations(), secret.Annotations)
+
+	// the previous probe password is kept as fallback
+	got, rotation, err = reconcileInternalUsers(c, es, got.fileRealm(), verify)
+	require.NoError(t, err)
+	require.Zero(t, rotation.RotateIn)
+	require.Equal(t, []byte("probeUserPassword"), getSecret(secretRef).Data[ProbeUserFallbackPasswordKey])
+	require.Equal(t, staged[1].PendingPassword, got[1].Password)
+}
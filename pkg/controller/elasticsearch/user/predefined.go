@@ -5,19 +5,26 @@
 package user
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"time"
 
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user/filerealm"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -28,10 +35,66 @@ const (
 	ControllerUserName = "elastic-internal"
 	// ProbeUserName is used for the Elasticsearch readiness probe.
 	ProbeUserName = "elastic-internal-probe"
+	// MonitoringUserName is used for the Elasticsearch monitoring.
+	MonitoringUserName = "elastic-internal-monitoring"
+
+	// ProbeUserFallbackPasswordKey is the key of the internal users secret holding the password the readiness probe
+	// retries with if the probe user password is rejected: the pending password during a rotation, the previous
+	// password afterwards.
+	ProbeUserFallbackPasswordKey = ProbeUserName + "-fallback"
 )
 
+// IsReservedUsername returns true if the given username is the one of a user managed by the operator in the file realm.
+func IsReservedUsername(username string) bool {
+	switch username {
+	case ElasticUserName, ControllerUserName, ProbeUserName, MonitoringUserName:
+		return true
+	default:
+		return false
+	}
+}
+
+// ElasticUserPasswordHashWatchName returns the watch registered for the secret holding the user-provided password
+// hash of the elastic user.
+func ElasticUserPasswordHashWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-elastic-user-password-hash", es.Namespace, es.Name)
+}
+
 // reconcileElasticUser reconciles a single secret holding the "elastic" user password.
-func reconcileElasticUser(c k8s.Client, es esv1.Elasticsearch, existingFileRealm filerealm.Realm) (users, error) {
+// No secret is reconciled if the elastic user is disabled or if its password hash is provided by the user: the elastic
+// user is then either not returned, or returned with the user-provided hash and without password.
+func reconcileElasticUser(
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	existingFileRealm filerealm.Realm,
+	watched watches.DynamicWatches,
+	recorder record.EventRecorder,
+	verify CredentialsVerifier,
+) (users, PasswordRotation, error) {
+	esKey := k8s.ExtractNamespacedName(&es)
+	hashSource := es.Spec.Auth.ElasticUserPasswordHashSource()
+	var secretNames []string
+	if hashSource != nil {
+		secretNames = []string{hashSource.SecretName}
+	}
+	if err := watches.WatchUserProvidedSecrets(esKey, watched, ElasticUserPasswordHashWatchName(esKey), secretNames); err != nil {
+		return nil, PasswordRotation{}, err
+	}
+
+	if es.Spec.Auth.ElasticUserDisabled() || hashSource != nil {
+		// do not leave a generated password behind
+		if err := deleteElasticUserSecret(c, es); err != nil {
+			return nil, PasswordRotation{}, err
+		}
+	}
+	if es.Spec.Auth.ElasticUserDisabled() {
+		return nil, PasswordRotation{}, nil
+	}
+	if hashSource != nil {
+		elasticUser, err := userProvidedElasticUser(c, es, *hashSource, recorder)
+		return elasticUser, PasswordRotation{}, err
+	}
+
 	return reconcilePredefinedUsers(
 		c,
 		es,
@@ -43,11 +106,54 @@ func reconcileElasticUser(c k8s.Client, es esv1.Elasticsearch, existingFileRealm
 		// Don't set an ownerRef for the elastic user secret, likely to be copied into different namespaces.
 		// See https://github.com/elastic/cloud-on-k8s/issues/3986.
 		false,
+		verify,
 	)
 }
 
+// userProvidedElasticUser returns the elastic user with the password hash held in the referenced secret. No user is
+// returned if the secret or the hash does not exist.
+func userProvidedElasticUser(
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	hashSource esv1.PasswordHashSource,
+	recorder record.EventRecorder,
+) (users, error) {
+	var secret corev1.Secret
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: hashSource.SecretName}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			handleSecretNotFound(recorder, es, hashSource.SecretName)
+			return nil, nil
+		}
+		return nil, err
+	}
+	hash, exists := secret.Data[hashSource.Key]
+	if !exists || len(hash) == 0 {
+		handleInvalidSecretData(recorder, es, hashSource.SecretName, errors.Errorf("password hash not found in key %s", hashSource.Key))
+		return nil, nil
+	}
+	return users{
+		{Name: ElasticUserName, PasswordHash: hash, Roles: []string{SuperUserBuiltinRole}},
+	}, nil
+}
+
+// deleteElasticUserSecret deletes the secret holding the generated password of the elastic user, if any.
+func deleteElasticUserSecret(c k8s.Client, es esv1.Elasticsearch) error {
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ElasticUserSecret(es.Name)},
+	}
+	if err := c.Delete(context.Background(), &secret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 // reconcileInternalUsers reconciles a single secret holding the internal users passwords.
-func reconcileInternalUsers(c k8s.Client, es esv1.Elasticsearch, existingFileRealm filerealm.Realm) (users, error) {
+func reconcileInternalUsers(
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	existingFileRealm filerealm.Realm,
+	verify CredentialsVerifier,
+) (users, PasswordRotation, error) {
 	return reconcilePredefinedUsers(
 		c,
 		es,
@@ -55,14 +161,35 @@ func reconcileInternalUsers(c k8s.Client, es esv1.Elasticsearch, existingFileRea
 		users{
 			{Name: ControllerUserName, Roles: []string{SuperUserBuiltinRole}},
 			{Name: ProbeUserName, Roles: []string{ProbeUserRole}},
+			{Name: MonitoringUserName, Roles: []string{RemoteMonitoringCollectorBuiltinRole}},
 		},
 		esv1.InternalUsersSecret(es.Name),
 		true,
+		verify,
 	)
 }
 
+// GetMonitoringUserPassword returns the password of the internal monitoring user, used by the Metricbeat sidecar
+// to collect metrics from the local Elasticsearch node.
+func GetMonitoringUserPassword(c k8s.Client, es types.NamespacedName) (string, error) {
+	var secret corev1.Secret
+	secretNsn := types.NamespacedName{Namespace: es.Namespace, Name: esv1.InternalUsersSecret(es.Name)}
+	if err := c.Get(context.Background(), secretNsn, &secret); err != nil {
+		return "", err
+	}
+	password, exists := secret.Data[MonitoringUserName]
+	if !exists {
+		return "", errors.Errorf("auth secret key %s doesn't exist", MonitoringUserName)
+	}
+	return string(password), nil
+}
+
 // reconcilePredefinedUsers reconciles a secret with the given name holding the given users.
 // It attempts to reuse passwords from pre-existing secrets, and reuse hashes from pre-existing file realms.
+// Passwords due for rotation are not replaced right away: new passwords are staged in the pending passwords secret and
+// only their hashes are written to the file realm. They replace the passwords held in the secret once all the nodes
+// accept them, so that the clients of the cluster keep using the previous ones until then. The rotation of the
+// passwords held in the secret is returned.
 func reconcilePredefinedUsers(
 	c k8s.Client,
 	es esv1.Elasticsearch,
@@ -70,31 +197,40 @@ func reconcilePredefinedUsers(
 	users users,
 	secretName string,
 	setOwnerRef bool,
-) (users, error) {
+	verify CredentialsVerifier,
+) (users, PasswordRotation, error) {
 	secretNsn := types.NamespacedName{Namespace: es.Namespace, Name: secretName}
+	var existing corev1.Secret
+	if err := c.Get(context.Background(), secretNsn, &existing); err != nil && !apierrors.IsNotFound(err) {
+		return nil, PasswordRotation{}, err
+	}
 
 	// build users, reusing existing passwords and bcrypt hashes if possible
-	var err error
-	users, err = reuseOrGeneratePassword(c, users, secretNsn)
+	users, rotation, rotate := reuseOrGeneratePassword(es, users, existing)
+	users, rotation, err := rotatePasswords(c, es, users, rotation, rotate, verify)
 	if err != nil {
-		return nil, err
+		return nil, PasswordRotation{}, err
 	}
 	users, err = reuseOrGenerateHash(users, existingFileRealm)
 	if err != nil {
-		return nil, err
+		return nil, PasswordRotation{}, err
 	}
 
 	// reconcile secret
 	secretData := make(map[string][]byte, len(users))
 	for _, u := range users {
 		secretData[u.Name] = u.Password
+		if u.Name == ProbeUserName {
+			secretData[ProbeUserFallbackPasswordKey] = probeFallbackPassword(existing, u)
+		}
 	}
 
 	expected := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: secretNsn.Namespace,
-			Name:      secretNsn.Name,
-			Labels:    common.AddCredentialsLabel(label.NewLabels(k8s.ExtractNamespacedName(&es))),
+			Namespace:   secretNsn.Namespace,
+			Name:        secretNsn.Name,
+			Labels:      common.AddCredentialsLabel(label.NewLabels(k8s.ExtractNamespacedName(&es))),
+			Annotations: rotation.Annotations(),
 		},
 		Data: secretData,
 	}
@@ -104,43 +240,171 @@ func reconcilePredefinedUsers(
 	} else {
 		_, err = reconciler.ReconcileSecretNoOwnerRef(c, expected, &es)
 	}
-	return users, err
+	return users, rotation, err
 }
 
 // reuseOrGeneratePassword updates the users with existing passwords reused from the existing K8s secret,
-// or generates new passwords.
-func reuseOrGeneratePassword(c k8s.Client, users users, secretRef types.NamespacedName) (users, error) {
-	var secret corev1.Secret
-	err := c.Get(context.Background(), secretRef, &secret)
-	if err != nil && !apierrors.IsNotFound(err) {
-		return nil, err
-	}
-	// default to an empty secret
-	if apierrors.IsNotFound(err) {
-		secret = corev1.Secret{}
-	}
-	if secret.Data == nil {
-		secret.Data = map[string][]byte{}
+// or generates new passwords. It returns the rotation of the passwords held in the secret, and whether they are due
+// for rotation.
+func reuseOrGeneratePassword(es esv1.Elasticsearch, users users, existing corev1.Secret) (users, PasswordRotation, bool) {
+	var rotation PasswordRotation
+	var rotate bool
+	if existing.Name == "" {
+		// the secret does not exist yet
+		rotation = NewPasswordRotation(es, time.Now())
+	} else {
+		lastRotation := PasswordRotationTime(existing)
+		var rotateIn time.Duration
+		rotate, rotateIn = PasswordRotationDue(es, lastRotation, time.Now())
+		rotation = PasswordRotation{Time: lastRotation, RotateIn: rotateIn}
 	}
 	// either reuse the password or generate a new one
 	for i, u := range users {
-		if password, exists := secret.Data[u.Name]; exists {
+		if password, exists := existing.Data[u.Name]; exists {
 			users[i].Password = password
 		} else {
 			users[i].Password = common.FixedLengthRandomPasswordBytes()
 		}
 	}
-	return users, nil
+	return users, rotation, rotate
+}
+
+// rotatePasswords stages new passwords for the users if their passwords are due for rotation. If new passwords are
+// already staged, it replaces the passwords of the users by the staged ones once they are accepted by all the nodes.
+// It returns the users along with the rotation of their passwords.
+func rotatePasswords(
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	users users,
+	rotation PasswordRotation,
+	rotate bool,
+	verify CredentialsVerifier,
+) (users, PasswordRotation, error) {
+	pending, err := getPendingPasswords(c, es)
+	if err != nil {
+		return nil, PasswordRotation{}, err
+	}
+	var staged bool
+	for i, u := range users {
+		if password, exists := pending[u.Name]; exists {
+			users[i].PendingPassword = password
+			staged = true
+		}
+	}
+
+	switch {
+	case staged:
+		accepted, err := pendingPasswordsAccepted(users, verify)
+		if err != nil {
+			return nil, PasswordRotation{}, err
+		}
+		if !accepted {
+			// keep using the current passwords, and check again soon
+			rotation.RotateIn = RotationCheckInterval
+			return users, rotation, nil
+		}
+		log.Info("Switching to the rotated passwords", "namespace", es.Namespace, "es_name", es.Name)
+		for i, u := range users {
+			if u.PendingPassword == nil {
+				continue
+			}
+			users[i].Password = u.PendingPassword
+			users[i].PendingPassword = nil
+			delete(pending, u.Name)
+		}
+		rotation = NewPasswordRotation(es, time.Now())
+	case rotate:
+		log.Info("Rotating passwords", "namespace", es.Namespace, "es_name", es.Name)
+		for i, u := range users {
+			users[i].PendingPassword = common.FixedLengthRandomPasswordBytes()
+			pending[u.Name] = users[i].PendingPassword
+		}
+		// the passwords are switched once the nodes accept them
+		rotation.RotateIn = RotationCheckInterval
+	default:
+		return users, rotation, nil
+	}
+	return users, rotation, reconcilePendingPasswords(c, es, pending)
+}
+
+// pendingPasswordsAccepted returns true if the pending passwords of the users are accepted by all the nodes.
+func pendingPasswordsAccepted(users users, verify CredentialsVerifier) (bool, error) {
+	for _, u := range users {
+		if u.PendingPassword == nil {
+			continue
+		}
+		accepted, err := verify(esclient.BasicAuthorization(esclient.BasicAuth{Name: u.Name, Password: string(u.PendingPassword)}))
+		if err != nil || !accepted {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// probeFallbackPassword returns the password the readiness probe retries with if the password of the probe user is
+// rejected, given the existing internal users secret. Nodes may see the updated file realm and the updated probe
+// password at different times: the fallback password is the pending password during a rotation, and the previous
+// password once the rotation is over.
+func probeFallbackPassword(existing corev1.Secret, probeUser user) []byte {
+	previous := existing.Data[ProbeUserName]
+	switch {
+	case probeUser.PendingPassword != nil:
+		return probeUser.PendingPassword
+	case previous != nil && !bytes.Equal(previous, probeUser.Password):
+		return previous
+	case existing.Data[ProbeUserFallbackPasswordKey] != nil:
+		return existing.Data[ProbeUserFallbackPasswordKey]
+	default:
+		return probeUser.Password
+	}
+}
+
+// getPendingPasswords returns the passwords generated by an ongoing rotation, indexed by user name.
+func getPendingPasswords(c k8s.Client, es esv1.Elasticsearch) (map[string][]byte, error) {
+	var secret corev1.Secret
+	err := c.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: esv1.PendingPasswordsSecret(es.Name)}, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if secret.Data == nil {
+		return map[string][]byte{}, nil
+	}
+	return secret.Data, nil
+}
+
+// reconcilePendingPasswords stores the given pending passwords, or deletes the secret holding them if there are none.
+func reconcilePendingPasswords(c k8s.Client, es esv1.Elasticsearch, passwords map[string][]byte) error {
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: es.Namespace,
+			Name:      esv1.PendingPasswordsSecret(es.Name),
+			Labels:    common.AddCredentialsLabel(label.NewLabels(k8s.ExtractNamespacedName(&es))),
+		},
+		Data: passwords,
+	}
+	if len(passwords) == 0 {
+		if err := c.Delete(context.Background(), &expected); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	_, err := reconciler.ReconcileSecret(c, expected, &es)
+	return err
 }
 
 // reuseOrGenerateHash updates the users with existing hashes from the given file realm, or generates new ones.
+// The hash is the one of the pending password if any.
 func reuseOrGenerateHash(users users, fileRealm filerealm.Realm) (users, error) {
 	for i, u := range users {
+		password := u.Password
+		if u.PendingPassword != nil {
+			password = u.PendingPassword
+		}
 		existingHash := fileRealm.PasswordHashForUser(u.Name)
-		if bcrypt.CompareHashAndPassword(existingHash, u.Password) == nil {
+		if bcrypt.CompareHashAndPassword(existingHash, password) == nil {
 			users[i].PasswordHash = existingHash
 		} else {
-			hash, err := bcrypt.GenerateFromPassword(u.Password, bcrypt.DefaultCost)
+			hash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
 			if err != nil {
 				return nil, err
 			}
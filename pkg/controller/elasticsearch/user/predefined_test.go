@@ -14,9 +14,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 )
 
 func Test_reconcileElasticUser(t *testing.T) {
@@ -104,7 +106,7 @@ func Test_reconcileElasticUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient(tt.existingSecrets...)
-			got, err := reconcileElasticUser(c, es, tt.existingFileRealm)
+			got, _, err := reconcileElasticUser(c, es, tt.existingFileRealm, initDynamicWatches(), record.NewFakeRecorder(10), acceptCredentials)
 			require.NoError(t, err)
 			// check returned user
 			require.Len(t, got, 1)
@@ -123,6 +125,72 @@ func Test_reconcileElasticUser(t *testing.T) {
 	}
 }
 
+func Test_reconcileElasticUser_userProvided(t *testing.T) {
+	hashSource := esv1.PasswordHashSource{SecretName: "elastic-password-hash", Key: "hash"}
+	generatedSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esv1.ElasticUserSecret("es")},
+			Data:       map[string][]byte{ElasticUserName: []byte("existingPassword")},
+		}
+	}
+	tests := []struct {
+		name            string
+		elasticUser     *esv1.ElasticUser
+		existingSecrets []runtime.Object
+		want            users
+	}{
+		{
+			name:            "elastic user disabled",
+			elasticUser:     &esv1.ElasticUser{Disabled: true},
+			existingSecrets: []runtime.Object{generatedSecret()},
+			want:            nil,
+		},
+		{
+			name:        "user-provided password hash",
+			elasticUser: &esv1.ElasticUser{PasswordHashSecretRef: &hashSource},
+			existingSecrets: []runtime.Object{
+				generatedSecret(),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: hashSource.SecretName},
+					Data:       map[string][]byte{hashSource.Key: []byte("$2a$10$lwsLdS0ZSyUv73WNdaRaTe8X9oeft4BoqjxtNHHH7LP7m1YImnvr6")},
+				},
+			},
+			want: users{{
+				Name:         ElasticUserName,
+				PasswordHash: []byte("$2a$10$lwsLdS0ZSyUv73WNdaRaTe8X9oeft4BoqjxtNHHH7LP7m1YImnvr6"),
+				Roles:        []string{SuperUserBuiltinRole},
+			}},
+		},
+		{
+			name:        "user-provided password hash not found",
+			elasticUser: &esv1.ElasticUser{PasswordHashSecretRef: &hashSource},
+			existingSecrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: hashSource.SecretName},
+					Data:       map[string][]byte{"other-key": []byte("hash")},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec:       esv1.ElasticsearchSpec{Auth: esv1.Auth{ElasticUser: tt.elasticUser}},
+			}
+			c := k8s.NewFakeClient(tt.existingSecrets...)
+			got, rotation, err := reconcileElasticUser(c, es, filerealm.New(), initDynamicWatches(), record.NewFakeRecorder(10), acceptCredentials)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, PasswordRotation{}, rotation)
+			// no generated password should be left behind
+			err = c.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: esv1.ElasticUserSecret(es.Name)}, &corev1.Secret{})
+			require.True(t, apierrors.IsNotFound(err))
+		})
+	}
+}
+
 func Test_reconcileInternalUsers(t *testing.T) {
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
 	tests := []struct {
@@ -215,26 +283,33 @@ func Test_reconcileInternalUsers(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient(tt.existingSecrets...)
-			got, err := reconcileInternalUsers(c, es, tt.existingFileRealm)
+			got, _, err := reconcileInternalUsers(c, es, tt.existingFileRealm, acceptCredentials)
 			require.NoError(t, err)
 			// check returned users
-			require.Len(t, got, 2)
+			require.Len(t, got, 3)
 			controllerUser := got[0]
 			probeUser := got[1]
+			monitoringUser := got[2]
 			// names and roles are always the same
 			require.Equal(t, ControllerUserName, controllerUser.Name)
 			require.Equal(t, []string{SuperUserBuiltinRole}, controllerUser.Roles)
 			require.Equal(t, ProbeUserName, probeUser.Name)
 			require.Equal(t, []string{ProbeUserRole}, probeUser.Roles)
+			require.Equal(t, MonitoringUserName, monitoringUser.Name)
+			require.Equal(t, []string{RemoteMonitoringCollectorBuiltinRole}, monitoringUser.Roles)
 			// passwords and hash should always match
 			require.NoError(t, bcrypt.CompareHashAndPassword(controllerUser.PasswordHash, controllerUser.Password))
 			require.NoError(t, bcrypt.CompareHashAndPassword(probeUser.PasswordHash, probeUser.Password))
+			require.NoError(t, bcrypt.CompareHashAndPassword(monitoringUser.PasswordHash, monitoringUser.Password))
 			// reconciled secret should have the updated passwords
 			var secret corev1.Secret
 			err = c.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: esv1.InternalUsersSecret(es.Name)}, &secret)
 			require.NoError(t, err)
 			require.Equal(t, controllerUser.Password, secret.Data[ControllerUserName])
 			require.Equal(t, probeUser.Password, secret.Data[ProbeUserName])
+			require.Equal(t, monitoringUser.Password, secret.Data[MonitoringUserName])
+			// the probe does not need to fall back to another password
+			require.Equal(t, probeUser.Password, secret.Data[ProbeUserFallbackPasswordKey])
 		})
 	}
 }
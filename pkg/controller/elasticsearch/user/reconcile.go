@@ -30,41 +30,51 @@ var log = ulog.Log.WithName("elasticsearch-user")
 
 // ReconcileUsersAndRoles fetches all users and roles and aggregates them into a single
 // Kubernetes secret mounted in the Elasticsearch Pods.
-// That secret contains the file realm files (`users` and `users_roles`) and the file roles (`roles.yml`).
+// That secret contains the file realm files (`users` and `users_roles`), the file roles (`roles.yml`) and the
+// service account tokens (`service_tokens`).
 // Users are aggregated from various sources:
-// - predefined users include the controller user, the probe user, and the public-facing elastic user
+// - predefined users include the controller user, the probe user, and the public-facing elastic user unless disabled
 // - associated users come from resource associations (eg. Kibana or APMServer)
 // - user-provided users from file realms referenced in the Elasticsearch spec
 // Roles are aggregated from:
 // - predefined roles (for the probe user)
 // - user-provided roles referenced in the Elasticsearch spec
+// Service account tokens come from resource associations (eg. Kibana or Fleet Server).
+// It also returns the rotation of the passwords of the predefined users, the given verifier checks whether the
+// passwords of an ongoing rotation are accepted by all the nodes.
 func ReconcileUsersAndRoles(
 	ctx context.Context,
 	c k8s.Client,
 	es esv1.Elasticsearch,
 	watched watches.DynamicWatches,
 	recorder record.EventRecorder,
-) (esclient.BasicAuth, error) {
+	verify CredentialsVerifier,
+) (esclient.BasicAuth, PasswordRotation, error) {
 	span, _ := apm.StartSpan(ctx, "reconcile_users", tracing.SpanTypeApp)
 	defer span.End()
 
 	// build aggregate roles and file realms
 	roles, err := aggregateRoles(c, es, watched, recorder)
 	if err != nil {
-		return esclient.BasicAuth{}, err
+		return esclient.BasicAuth{}, PasswordRotation{}, err
 	}
-	fileRealm, controllerUser, err := aggregateFileRealm(c, es, watched, recorder)
+	fileRealm, controllerUser, rotation, err := aggregateFileRealm(c, es, watched, recorder, verify)
 	if err != nil {
-		return esclient.BasicAuth{}, err
+		return esclient.BasicAuth{}, PasswordRotation{}, err
+	}
+
+	serviceTokens, err := retrieveServiceAccountTokens(c, es)
+	if err != nil {
+		return esclient.BasicAuth{}, PasswordRotation{}, err
 	}
 
 	// reconcile the aggregate secret
-	if err := reconcileRolesFileRealmSecret(c, es, roles, fileRealm); err != nil {
-		return esclient.BasicAuth{}, err
+	if err := reconcileRolesFileRealmSecret(c, es, roles, fileRealm, serviceTokens); err != nil {
+		return esclient.BasicAuth{}, PasswordRotation{}, err
 	}
 
 	// return the controller user for next reconciliation steps to interact with Elasticsearch
-	return controllerUser, nil
+	return controllerUser, rotation, nil
 }
 
 func getExistingFileRealm(c k8s.Client, es esv1.Elasticsearch) (filerealm.Realm, error) {
@@ -75,42 +85,44 @@ func getExistingFileRealm(c k8s.Client, es esv1.Elasticsearch) (filerealm.Realm,
 	return filerealm.FromSecret(secret)
 }
 
-// aggregateFileRealm builds a single file realm from multiple ones, and returns the controller user credentials.
+// aggregateFileRealm builds a single file realm from multiple ones, and returns the controller user credentials along
+// with the rotation of the passwords of the predefined users.
 func aggregateFileRealm(
 	c k8s.Client,
 	es esv1.Elasticsearch,
 	watched watches.DynamicWatches,
 	recorder record.EventRecorder,
-) (filerealm.Realm, esclient.BasicAuth, error) {
+	verify CredentialsVerifier,
+) (filerealm.Realm, esclient.BasicAuth, PasswordRotation, error) {
 	// retrieve existing file realm to reuse predefined users password hashes if possible
 	existingFileRealm, err := getExistingFileRealm(c, es)
 	if err != nil && apierrors.IsNotFound(err) {
 		// no secret yet, work with an empty file realm
 		existingFileRealm = filerealm.New()
 	} else if err != nil {
-		return filerealm.Realm{}, esclient.BasicAuth{}, err
+		return filerealm.Realm{}, esclient.BasicAuth{}, PasswordRotation{}, err
 	}
 
 	// reconcile predefined users
-	elasticUser, err := reconcileElasticUser(c, es, existingFileRealm)
+	elasticUser, elasticUserRotation, err := reconcileElasticUser(c, es, existingFileRealm, watched, recorder, verify)
 	if err != nil {
-		return filerealm.Realm{}, esclient.BasicAuth{}, err
+		return filerealm.Realm{}, esclient.BasicAuth{}, PasswordRotation{}, err
 	}
-	internalUsers, err := reconcileInternalUsers(c, es, existingFileRealm)
+	internalUsers, internalUsersRotation, err := reconcileInternalUsers(c, es, existingFileRealm, verify)
 	if err != nil {
-		return filerealm.Realm{}, esclient.BasicAuth{}, err
+		return filerealm.Realm{}, esclient.BasicAuth{}, PasswordRotation{}, err
 	}
 
 	// fetch associated users
 	associatedUsers, err := retrieveAssociatedUsers(c, es)
 	if err != nil {
-		return filerealm.Realm{}, esclient.BasicAuth{}, err
+		return filerealm.Realm{}, esclient.BasicAuth{}, PasswordRotation{}, err
 	}
 
 	// watch & fetch user-provided file realm & roles
 	userProvidedFileRealm, err := reconcileUserProvidedFileRealm(c, es, watched, recorder)
 	if err != nil {
-		return filerealm.Realm{}, esclient.BasicAuth{}, err
+		return filerealm.Realm{}, esclient.BasicAuth{}, PasswordRotation{}, err
 	}
 
 	// merge all file realms together, the last one having precedence
@@ -124,9 +136,9 @@ func aggregateFileRealm(
 	// grab the controller user credentials for later use
 	controllerCreds, err := internalUsers.credentialsFor(ControllerUserName)
 	if err != nil {
-		return filerealm.Realm{}, esclient.BasicAuth{}, err
+		return filerealm.Realm{}, esclient.BasicAuth{}, PasswordRotation{}, err
 	}
-	return fileRealm, controllerCreds, nil
+	return fileRealm, controllerCreds, elasticUserRotation.Merge(internalUsersRotation), nil
 }
 
 func aggregateRoles(
@@ -148,14 +160,22 @@ func RolesFileRealmSecretKey(es esv1.Elasticsearch) types.NamespacedName {
 	return types.NamespacedName{Namespace: es.Namespace, Name: esv1.RolesAndFileRealmSecret(es.Name)}
 }
 
-// reconcileRolesFileRealmSecret creates or updates the single secret holding the file realm and the file-based roles.
-func reconcileRolesFileRealmSecret(c k8s.Client, es esv1.Elasticsearch, roles RolesFileContent, fileRealm filerealm.Realm) error {
+// reconcileRolesFileRealmSecret creates or updates the single secret holding the file realm, the file-based roles and
+// the service account tokens.
+func reconcileRolesFileRealmSecret(
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	roles RolesFileContent,
+	fileRealm filerealm.Realm,
+	serviceTokens []byte,
+) error {
 	secretData := fileRealm.FileBytes()
 	rolesBytes, err := roles.FileBytes()
 	if err != nil {
 		return err
 	}
 	secretData[RolesFile] = rolesBytes
+	secretData[ServiceTokensFile] = serviceTokens
 
 	expected := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
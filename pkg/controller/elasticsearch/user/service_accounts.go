@@ -0,0 +1,200 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ServiceAccountTokenType is used to annotate a secret holding the hash of a service account token, most likely
+	// created by an association controller.
+	ServiceAccountTokenType = "service-account-token"
+
+	// ServiceTokensFile is the name of the file holding the hashes of the service account tokens in the ES config dir.
+	ServiceTokensFile = "service_tokens"
+
+	// ServiceAccountField is the field in the secret that contains the name of the service account, eg. elastic/kibana.
+	ServiceAccountField = "serviceAccount"
+	// ServiceAccountTokenNameField is the field in the secret that contains the name of the token.
+	ServiceAccountTokenNameField = "name"
+	// ServiceAccountTokenHashField is the field in the secret that contains the hash of the token.
+	ServiceAccountTokenHashField = "hash"
+
+	// pbkdf2StretchPrefix identifies the hashing algorithm used by Elasticsearch for service account tokens: the
+	// SHA-512 hash of the secret is hashed with PBKDF2, using HMAC-SHA512 as pseudorandom function.
+	pbkdf2StretchPrefix = "{PBKDF2_STRETCH}"
+	pbkdf2Iterations    = 10000
+	pbkdf2KeyLength     = 32
+	pbkdf2SaltLength    = 32
+)
+
+// serviceAccountTokenPrefix is the prefix of all the service account tokens once decoded. It is made of a magic byte
+// followed by the version of the token format.
+var serviceAccountTokenPrefix = []byte{0x0, 0x1, 0x0, 0x1}
+
+// ServiceAccountToken is a token used to authenticate as an Elasticsearch service account.
+type ServiceAccountToken struct {
+	// ServiceAccount is the fully qualified name of the service account, eg. elastic/kibana.
+	ServiceAccount string
+	// Name of the token, unique for a given service account.
+	Name string
+	// Secret is the secret part of the token.
+	Secret []byte
+}
+
+// NewServiceAccountToken generates a new token for the given service account.
+func NewServiceAccountToken(serviceAccount, name string) ServiceAccountToken {
+	return ServiceAccountToken{
+		ServiceAccount: serviceAccount,
+		Name:           name,
+		Secret:         common.FixedLengthRandomPasswordBytes(),
+	}
+}
+
+// ParseServiceAccountToken parses a token in the format returned by Bearer.
+func ParseServiceAccountToken(bearer []byte) (ServiceAccountToken, error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(string(bearer), "="))
+	if err != nil {
+		return ServiceAccountToken{}, errors.Wrap(err, "invalid service account token")
+	}
+	if !bytes.HasPrefix(decoded, serviceAccountTokenPrefix) {
+		return ServiceAccountToken{}, errors.New("invalid service account token prefix")
+	}
+	parts := strings.SplitN(string(decoded[len(serviceAccountTokenPrefix):]), ":", 2)
+	if len(parts) != 2 {
+		return ServiceAccountToken{}, errors.New("service account token secret not found")
+	}
+	qualifiedName, secret := parts[0], parts[1]
+	separator := strings.LastIndex(qualifiedName, "/")
+	if separator < 0 {
+		return ServiceAccountToken{}, errors.New("service account token name not found")
+	}
+	return ServiceAccountToken{
+		ServiceAccount: qualifiedName[:separator],
+		Name:           qualifiedName[separator+1:],
+		Secret:         []byte(secret),
+	}, nil
+}
+
+// QualifiedName returns the name of the token prefixed by the name of its service account, eg. elastic/kibana/token.
+func (t ServiceAccountToken) QualifiedName() string {
+	return t.ServiceAccount + "/" + t.Name
+}
+
+// Bearer returns the token as expected in the Authorization header of the requests to Elasticsearch.
+func (t ServiceAccountToken) Bearer() []byte {
+	token := append(append([]byte{}, serviceAccountTokenPrefix...), []byte(t.QualifiedName()+":")...)
+	token = append(token, t.Secret...)
+	return []byte(base64.RawStdEncoding.EncodeToString(token))
+}
+
+// Hash returns a new hash of the token secret, in the format expected by Elasticsearch in the service_tokens file.
+func (t ServiceAccountToken) Hash() []byte {
+	salt := common.RandomBytes(pbkdf2SaltLength)
+	return []byte(fmt.Sprintf("%s%d$%s$%s",
+		pbkdf2StretchPrefix,
+		pbkdf2Iterations,
+		base64.StdEncoding.EncodeToString(salt),
+		base64.StdEncoding.EncodeToString(pbkdf2StretchKey(t.Secret, salt, pbkdf2Iterations)),
+	))
+}
+
+// MatchesHash returns true if the given hash is a hash of the token secret.
+func (t ServiceAccountToken) MatchesHash(hash []byte) bool {
+	parts := strings.Split(strings.TrimPrefix(string(hash), pbkdf2StretchPrefix), "$")
+	if !bytes.HasPrefix(hash, []byte(pbkdf2StretchPrefix)) || len(parts) != 3 {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	key, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(key, pbkdf2StretchKey(t.Secret, salt, iterations)) == 1
+}
+
+func pbkdf2StretchKey(secret []byte, salt []byte, iterations int) []byte {
+	sha := sha512.Sum512(secret)
+	return pbkdf2.Key([]byte(hex.EncodeToString(sha[:])), salt, iterations, pbkdf2KeyLength, sha512.New)
+}
+
+// ServiceAccountTokenLabels returns labels matching the service account tokens for the given es resource.
+func ServiceAccountTokenLabels(es esv1.Elasticsearch) map[string]string {
+	return map[string]string{
+		label.ClusterNameLabelName: es.Name,
+		common.TypeLabelName:       ServiceAccountTokenType,
+	}
+}
+
+// retrieveServiceAccountTokens fetches the hashes of the service account tokens created by the association controllers,
+// and returns them in the format of the service_tokens file.
+func retrieveServiceAccountTokens(c k8s.Client, es esv1.Elasticsearch) ([]byte, error) {
+	var tokenSecrets corev1.SecretList
+	if err := c.List(context.Background(),
+		&tokenSecrets,
+		client.InNamespace(es.Namespace),
+		client.MatchingLabels(ServiceAccountTokenLabels(es)),
+	); err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(tokenSecrets.Items))
+	for _, secret := range tokenSecrets.Items {
+		line, err := serviceTokensFileLine(secret)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	// sort for consistent comparison
+	sort.Strings(lines)
+
+	var file bytes.Buffer
+	for _, line := range lines {
+		file.WriteString(line)
+		file.WriteString("\n")
+	}
+	return file.Bytes(), nil
+}
+
+// serviceTokensFileLine returns the line of the service_tokens file for the token held in the given secret.
+func serviceTokensFileLine(secret corev1.Secret) (string, error) {
+	values := make(map[string]string, 3)
+	for _, field := range []string{ServiceAccountField, ServiceAccountTokenNameField, ServiceAccountTokenHashField} {
+		value, exists := secret.Data[field]
+		if !exists {
+			return "", fmt.Errorf(fieldNotFound, field, secret.Namespace, secret.Name)
+		}
+		values[field] = string(value)
+	}
+	token := ServiceAccountToken{ServiceAccount: values[ServiceAccountField], Name: values[ServiceAccountTokenNameField]}
+	return token.QualifiedName() + ":" + values[ServiceAccountTokenHashField], nil
+}
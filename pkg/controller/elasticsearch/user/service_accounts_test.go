@@ -0,0 +1,118 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func TestParseServiceAccountToken(t *testing.T) {
+	// sample token from the Elasticsearch documentation
+	token, err := ParseServiceAccountToken([]byte("AAEAAWVsYXN0aWMvZmxlZXQtc2VydmVyL3Rva2VuMTpyNXdkYmRib1FTZTl2R09Ld2FKR0F3"))
+	require.NoError(t, err)
+	require.Equal(t, ServiceAccountToken{
+		ServiceAccount: "elastic/fleet-server",
+		Name:           "token1",
+		Secret:         []byte("r5wdbdboQSe9vGOKwaJGAw"),
+	}, token)
+	require.Equal(t, "elastic/fleet-server/token1", token.QualifiedName())
+	require.Equal(t, "AAEAAWVsYXN0aWMvZmxlZXQtc2VydmVyL3Rva2VuMTpyNXdkYmRib1FTZTl2R09Ld2FKR0F3", string(token.Bearer()))
+
+	for _, invalid := range []string{"", "not base64!", "ZWxhc3RpYy9raWJhbmEvdG9rZW46c2VjcmV0", "AAEAAWVsYXN0aWMva2liYW5h"} {
+		_, err := ParseServiceAccountToken([]byte(invalid))
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestServiceAccountToken_Hash(t *testing.T) {
+	token := NewServiceAccountToken("elastic/kibana", "ns_kb-kibana-user")
+	require.Len(t, token.Secret, 24)
+
+	parsed, err := ParseServiceAccountToken(token.Bearer())
+	require.NoError(t, err)
+	require.Equal(t, token, parsed)
+
+	hash := token.Hash()
+	require.True(t, strings.HasPrefix(string(hash), "{PBKDF2_STRETCH}10000$"))
+	require.True(t, token.MatchesHash(hash))
+	// hashes are salted
+	require.NotEqual(t, hash, token.Hash())
+
+	other := NewServiceAccountToken("elastic/kibana", "ns_kb-kibana-user")
+	require.False(t, other.MatchesHash(hash))
+	require.False(t, token.MatchesHash([]byte("{PBKDF2_STRETCH}10000$invalid")))
+	require.False(t, token.MatchesHash(nil))
+}
+
+func Test_retrieveServiceAccountTokens(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns"},
+	}
+	tokenSecret := func(name, serviceAccount, hash string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: es.Namespace,
+				Name:      name,
+				Labels:    ServiceAccountTokenLabels(es),
+			},
+			Data: map[string][]byte{
+				ServiceAccountField:          []byte(serviceAccount),
+				ServiceAccountTokenNameField: []byte(name),
+				ServiceAccountTokenHashField: []byte(hash),
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		secrets []runtime.Object
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no service account token",
+			want: "",
+		},
+		{
+			name: "sorted service account tokens",
+			secrets: []runtime.Object{
+				tokenSecret("ns_kb-kibana-user", "elastic/kibana", "hash1"),
+				tokenSecret("ns_agent-agent-user", "elastic/fleet-server", "hash2"),
+			},
+			want: "elastic/fleet-server/ns_agent-agent-user:hash2\nelastic/kibana/ns_kb-kibana-user:hash1\n",
+		},
+		{
+			name: "invalid secret",
+			secrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: es.Namespace,
+					Name:      "invalid",
+					Labels:    ServiceAccountTokenLabels(es),
+				},
+				Data: map[string][]byte{ServiceAccountField: []byte("elastic/kibana")},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := retrieveServiceAccountTokens(k8s.NewFakeClient(tt.secrets...), es)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -13,10 +13,12 @@ import (
 
 // user is a convenience struct to represent a file realm user.
 type user struct {
-	Name         string
-	Password     []byte
-	PasswordHash []byte
-	Roles        []string
+	Name     string
+	Password []byte
+	// PendingPassword is generated by an ongoing rotation, it replaces Password once all the nodes accept it.
+	PendingPassword []byte
+	PasswordHash    []byte
+	Roles           []string
 }
 
 // Realm builds a file realm representation of this user.
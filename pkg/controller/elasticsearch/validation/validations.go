@@ -7,11 +7,16 @@ package validation
 import (
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
+	"time"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	stackmonvalidations "github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	esversion "github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/pkg/utils/log"
@@ -24,21 +29,50 @@ var log = ulog.Log.WithName("es-validation")
 const (
 	autoscalingVersionMsg    = "autoscaling is not available in this version of Elasticsearch"
 	cfgInvalidMsg            = "Configuration invalid"
+	composableTemplatesMsg   = "component and composable index templates are not available in this version of Elasticsearch"
+	definitionRefInvalidMsg  = "exactly one of configMapName or secretName must be specified"
+	definitionRequiredMsg    = "exactly one of definition or definitionRef must be specified"
+	duplicateClusterResource = "Names must be unique among the resources of the same kind"
+	duplicateNativeUser      = "Native user names must be unique"
 	duplicateNodeSets        = "NodeSet names must be unique"
+	duplicateRoleMapping     = "Role mapping names must be unique"
+	duplicateSnapshotPolicy  = "Snapshot lifecycle policy names must be unique"
+	duplicateSnapshotRepo    = "Snapshot repository names must be unique"
+	elasticUserDisabledMsg   = "passwordHashSecretRef cannot be specified if the elastic user is disabled"
 	invalidNamesErrMsg       = "Elasticsearch configuration would generate resources with invalid names"
+	issuerRefCertificateMsg  = "issuerRef cannot be specified along with a user-provided certificate"
 	invalidSanIPErrMsg       = "Invalid SAN IP address. Must be a valid IPv4 address"
 	masterRequiredMsg        = "Elasticsearch needs to have at least one master node"
 	mixedRoleConfigMsg       = "Detected a combination of node.roles and %s. Use only node.roles"
 	noDowngradesMsg          = "Downgrades are not supported"
+	nodeCertificatesCAMsg    = "nodeCertificates cannot be specified along with a user-provided CA certificate"
 	nodeRolesInOldVersionMsg = "node.roles setting is not available in this version of Elasticsearch"
 	parseStoredVersionErrMsg = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
 	parseVersionErrMsg       = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
+	passwordRotationMsg      = "passwordRotationInterval must be at least %s"
+	passwordRotationTimeMsg  = "rotation time must be an RFC 3339 timestamp"
+	proxyModeVersionMsg      = "remote clusters in proxy mode are not available in this version of Elasticsearch"
 	pvcImmutableErrMsg       = "volume claim templates can only have their storage requests increased, if the storage class allows volume expansion. Any other change is forbidden"
+	reservedNativeUserMsg    = "username is reserved for the users managed by the operator"
+	remoteClusterCAMsg       = "certificateAuthorities can only be specified for remote clusters not managed by ECK"
+	remoteClusterSecretMsg   = "secretName cannot be used to reference a remote cluster, use seeds or proxyAddress instead"
+	remoteClusterSourceMsg   = "only one of elasticsearchRef, seeds or proxyAddress can be specified"
+	roleMappingRulesMsg      = "rules must be specified"
+	serverNameMsg            = "serverName can only be specified along with proxyAddress"
+	slmVersionMsg            = "snapshot lifecycle management is not available in this version of Elasticsearch"
 	unsupportedConfigErrMsg  = "Configuration setting is reserved for internal use. User-configured use is unsupported"
 	unsupportedUpgradeMsg    = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
 	unsupportedVersionMsg    = "Unsupported version"
+	zoneAttributeConfigMsg   = "node attribute is set by the operator when zone awareness is enabled"
+	zoneAttributeInvalidMsg  = "attribute must only contain alphanumeric characters, '-' or '_'"
 )
 
+var zoneAttributeRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// minPasswordRotationInterval prevents the passwords from being rotated faster than they can be propagated to the
+// Elasticsearch nodes and to the associated resources.
+const minPasswordRotationInterval = time.Hour
+
 type validation func(esv1.Elasticsearch) field.ErrorList
 
 // validations are the validation funcs that apply to creates or updates
@@ -48,7 +82,18 @@ var validations = []validation{
 	hasCorrectNodeRoles,
 	supportedVersion,
 	validSanIP,
+	validPrivateKey,
+	validIssuerRefs,
+	validNodeCertificates,
 	validAutoscalingConfiguration,
+	validMonitoring,
+	validSnapshots,
+	validClusterResources,
+	validNativeRealm,
+	validPasswordRotation,
+	validElasticUser,
+	validRemoteClusters,
+	validZoneAwareness,
 }
 
 type updateValidation func(esv1.Elasticsearch, esv1.Elasticsearch) field.ErrorList
@@ -88,6 +133,11 @@ func validName(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validMonitoring checks that the Stack Monitoring configuration is supported.
+func validMonitoring(es esv1.Elasticsearch) field.ErrorList {
+	return stackmonvalidations.Validate(&es, es.Spec.Version)
+}
+
 func supportedVersion(es esv1.Elasticsearch) field.ErrorList {
 	ver, err := version.Parse(es.Spec.Version)
 	if err != nil {
@@ -203,6 +253,253 @@ func validSanIP(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validPrivateKey checks that the algorithm and size of the HTTP private key are supported.
+func validPrivateKey(es esv1.Elasticsearch) field.ErrorList {
+	return commonv1.CheckPrivateKey(es.Spec.HTTP)
+}
+
+// validIssuerRefs checks that an external issuer is not specified along with a user-provided certificate for the HTTP
+// layer, or a user-provided CA or node certificates for the transport layer.
+func validIssuerRefs(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	if es.Spec.HTTP.TLS.IssuerRef != nil && es.Spec.HTTP.TLS.Certificate.SecretName != "" {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("http", "tls", "issuerRef"), es.Spec.HTTP.TLS.IssuerRef.Name, issuerRefCertificateMsg))
+	}
+	transportTLS := es.Spec.Transport.TLS
+	if transportTLS.IssuerRef != nil && (transportTLS.UserDefinedCA() || transportTLS.UserDefinedNodeCertificates()) {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("transport", "tls", "issuerRef"), transportTLS.IssuerRef.Name, issuerRefCertificateMsg))
+	}
+	return errs
+}
+
+// validNodeCertificates checks that user-provided transport certificates for the nodes are not specified along with a
+// user-provided CA, which would be used to sign certificates generated by the operator.
+func validNodeCertificates(es esv1.Elasticsearch) field.ErrorList {
+	transportTLS := es.Spec.Transport.TLS
+	if transportTLS.UserDefinedNodeCertificates() && transportTLS.UserDefinedCA() {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("transport", "tls", "nodeCertificates"), transportTLS.NodeCertificates.SecretName, nodeCertificatesCAMsg)}
+	}
+	return nil
+}
+
+// validSnapshots checks that the names of the snapshot repositories and policies are unique, and that snapshot
+// lifecycle policies are only declared for Elasticsearch 7.4.0 and above.
+func validSnapshots(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	snapshotsPath := field.NewPath("spec").Child("snapshots")
+
+	repositories := make(map[string]struct{}, len(es.Spec.Snapshots.Repositories))
+	for i, repository := range es.Spec.Snapshots.Repositories {
+		if _, exists := repositories[repository.Name]; exists {
+			errs = append(errs, field.Invalid(snapshotsPath.Child("repositories").Index(i).Child("name"), repository.Name, duplicateSnapshotRepo))
+		}
+		repositories[repository.Name] = struct{}{}
+	}
+
+	if len(es.Spec.Snapshots.Policies) == 0 {
+		return errs
+	}
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return append(errs, field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, parseVersionErrMsg))
+	}
+	if !v.GTE(version.From(7, 4, 0)) {
+		errs = append(errs, field.Invalid(snapshotsPath.Child("policies"), es.Spec.Version, slmVersionMsg))
+	}
+
+	policies := make(map[string]struct{}, len(es.Spec.Snapshots.Policies))
+	for i, policy := range es.Spec.Snapshots.Policies {
+		if _, exists := policies[policy.Name]; exists {
+			errs = append(errs, field.Invalid(snapshotsPath.Child("policies").Index(i).Child("name"), policy.Name, duplicateSnapshotPolicy))
+		}
+		policies[policy.Name] = struct{}{}
+	}
+	return errs
+}
+
+// validClusterResources checks that the names of the cluster resources are unique per kind, that each resource
+// has exactly one definition source, and that templates are only declared for Elasticsearch 7.8.0 and above.
+func validClusterResources(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	resourcesPath := field.NewPath("spec").Child("clusterResources")
+	spec := es.Spec.ClusterResources
+
+	if len(spec.ComponentTemplates) > 0 || len(spec.IndexTemplates) > 0 {
+		v, err := version.Parse(es.Spec.Version)
+		if err != nil {
+			return append(errs, field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, parseVersionErrMsg))
+		}
+		if !v.GTE(version.From(7, 8, 0)) {
+			errs = append(errs, field.Invalid(resourcesPath, es.Spec.Version, composableTemplatesMsg))
+		}
+	}
+
+	for _, kind := range []struct {
+		field     string
+		resources []esv1.ClusterResource
+	}{
+		{field: "indexLifecyclePolicies", resources: spec.IndexLifecyclePolicies},
+		{field: "componentTemplates", resources: spec.ComponentTemplates},
+		{field: "indexTemplates", resources: spec.IndexTemplates},
+		{field: "ingestPipelines", resources: spec.IngestPipelines},
+	} {
+		names := make(map[string]struct{}, len(kind.resources))
+		for i, resource := range kind.resources {
+			resourcePath := resourcesPath.Child(kind.field).Index(i)
+			if _, exists := names[resource.Name]; exists {
+				errs = append(errs, field.Invalid(resourcePath.Child("name"), resource.Name, duplicateClusterResource))
+			}
+			names[resource.Name] = struct{}{}
+
+			if (resource.Definition == nil) == (resource.DefinitionRef == nil) {
+				errs = append(errs, field.Invalid(resourcePath, resource.Name, definitionRequiredMsg))
+				continue
+			}
+			if ref := resource.DefinitionRef; ref != nil && (ref.ConfigMapName == "") == (ref.SecretName == "") {
+				errs = append(errs, field.Invalid(resourcePath.Child("definitionRef"), resource.Name, definitionRefInvalidMsg))
+			}
+		}
+	}
+	return errs
+}
+
+// validNativeRealm checks that the native users and the role mappings have unique names, that native users do not
+// use the names of the users managed by the operator, and that role mappings have rules.
+func validNativeRealm(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	authPath := field.NewPath("spec").Child("auth")
+
+	usernames := make(map[string]struct{}, len(es.Spec.Auth.NativeUsers))
+	for i, nativeUser := range es.Spec.Auth.NativeUsers {
+		usernamePath := authPath.Child("nativeUsers").Index(i).Child("username")
+		if _, exists := usernames[nativeUser.Username]; exists {
+			errs = append(errs, field.Invalid(usernamePath, nativeUser.Username, duplicateNativeUser))
+		}
+		usernames[nativeUser.Username] = struct{}{}
+		if user.IsReservedUsername(nativeUser.Username) {
+			errs = append(errs, field.Invalid(usernamePath, nativeUser.Username, reservedNativeUserMsg))
+		}
+	}
+
+	names := make(map[string]struct{}, len(es.Spec.Auth.RoleMappings))
+	for i, mapping := range es.Spec.Auth.RoleMappings {
+		mappingPath := authPath.Child("roleMappings").Index(i)
+		if _, exists := names[mapping.Name]; exists {
+			errs = append(errs, field.Invalid(mappingPath.Child("name"), mapping.Name, duplicateRoleMapping))
+		}
+		names[mapping.Name] = struct{}{}
+		if mapping.Rules == nil || len(mapping.Rules.Data) == 0 {
+			errs = append(errs, field.Required(mappingPath.Child("rules"), roleMappingRulesMsg))
+		}
+	}
+	return errs
+}
+
+// validPasswordRotation checks that the passwords are not rotated more often than the minimal rotation interval, and
+// that the rotation annotation holds a valid timestamp.
+func validPasswordRotation(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	if interval := es.Spec.Auth.PasswordRotationInterval; interval != nil && interval.Duration < minPasswordRotationInterval {
+		errs = append(errs, field.Invalid(
+			field.NewPath("spec").Child("auth").Child("passwordRotationInterval"),
+			interval.Duration.String(),
+			fmt.Sprintf(passwordRotationMsg, minPasswordRotationInterval),
+		))
+	}
+	if value, exists := es.Annotations[user.RotatePasswordsAnnotation]; exists {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			errs = append(errs, field.Invalid(
+				field.NewPath("metadata").Child("annotations").Key(user.RotatePasswordsAnnotation),
+				value,
+				passwordRotationTimeMsg,
+			))
+		}
+	}
+	return errs
+}
+
+// validElasticUser checks that the elastic user is not both disabled and created from a user-provided password hash.
+func validElasticUser(es esv1.Elasticsearch) field.ErrorList {
+	elasticUser := es.Spec.Auth.ElasticUser
+	if elasticUser == nil || !elasticUser.Disabled || elasticUser.PasswordHashSecretRef == nil {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(
+		field.NewPath("spec").Child("auth").Child("elasticUser").Child("passwordHashSecretRef"),
+		elasticUserDisabledMsg,
+	)}
+}
+
+// validRemoteClusters checks that each remote cluster is either referenced as an Elasticsearch resource, or reached
+// through seeds or a proxy address, and that the proxy mode is only used with Elasticsearch 7.7.0 and above.
+func validRemoteClusters(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, remoteCluster := range es.Spec.RemoteClusters {
+		remoteClusterPath := field.NewPath("spec").Child("remoteClusters").Index(i)
+		sources := 0
+		for _, defined := range []bool{
+			remoteCluster.ElasticsearchRef.IsDefined(),
+			len(remoteCluster.Seeds) > 0,
+			remoteCluster.ProxyAddress != "",
+		} {
+			if defined {
+				sources++
+			}
+		}
+		if sources > 1 {
+			errs = append(errs, field.Invalid(remoteClusterPath, remoteCluster.Name, remoteClusterSourceMsg))
+		}
+		if remoteCluster.ElasticsearchRef.IsExternal() {
+			errs = append(errs, field.Forbidden(remoteClusterPath.Child("elasticsearchRef", "secretName"), remoteClusterSecretMsg))
+		}
+		if remoteCluster.ServerName != "" && remoteCluster.ProxyAddress == "" {
+			errs = append(errs, field.Invalid(remoteClusterPath.Child("serverName"), remoteCluster.ServerName, serverNameMsg))
+		}
+		if remoteCluster.CertificateAuthorities.SecretName != "" && remoteCluster.ElasticsearchRef.IsDefined() {
+			errs = append(errs, field.Invalid(remoteClusterPath.Child("certificateAuthorities"), remoteCluster.CertificateAuthorities.SecretName, remoteClusterCAMsg))
+		}
+		if remoteCluster.ProxyAddress == "" {
+			continue
+		}
+		v, err := version.Parse(es.Spec.Version)
+		if err != nil {
+			return append(errs, field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, parseVersionErrMsg))
+		}
+		if !v.GTE(version.From(7, 7, 0)) {
+			errs = append(errs, field.Invalid(remoteClusterPath.Child("proxyAddress"), es.Spec.Version, proxyModeVersionMsg))
+		}
+	}
+	return errs
+}
+
+// validZoneAwareness checks that the zone awareness attribute is a valid node attribute name, which is not already
+// set in the configuration of the NodeSets.
+func validZoneAwareness(es esv1.Elasticsearch) field.ErrorList {
+	if es.Spec.ZoneAwareness == nil {
+		return nil
+	}
+	attribute := es.Spec.ZoneAwareness.AttributeOrDefault()
+	if !zoneAttributeRegexp.MatchString(attribute) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("zoneAwareness").Child("attribute"), attribute, zoneAttributeInvalidMsg)}
+	}
+	var errs field.ErrorList
+	attributeSetting := fmt.Sprintf("%s.%s", esv1.NodeAttr, attribute)
+	for i, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.Config == nil {
+			continue
+		}
+		config, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+		if err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("nodeSets").Index(i).Child("config"), nodeSet.Config, cfgInvalidMsg))
+			continue
+		}
+		if len(config.HasKeys([]string{attributeSetting})) > 0 {
+			errs = append(errs, field.Forbidden(field.NewPath("spec").Child("nodeSets").Index(i).Child("config").Child(attributeSetting), zoneAttributeConfigMsg))
+		}
+	}
+	return errs
+}
+
 func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
 	nodeSets := es.Spec.NodeSets
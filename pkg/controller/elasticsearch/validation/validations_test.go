@@ -6,9 +6,11 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/user"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -316,6 +318,163 @@ func Test_validSanIP(t *testing.T) {
 	}
 }
 
+func Test_validPrivateKey(t *testing.T) {
+	withPrivateKey := func(privateKey *commonv1.PrivateKeyOptions) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			Spec: esv1.ElasticsearchSpec{
+				HTTP: commonv1.HTTPConfig{
+					TLS: commonv1.TLSOptions{PrivateKey: privateKey},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:         "no private key options: OK",
+			es:           withPrivateKey(nil),
+			expectErrors: false,
+		},
+		{
+			name:         "ECDSA without size: OK",
+			es:           withPrivateKey(&commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm}),
+			expectErrors: false,
+		},
+		{
+			name:         "RSA 3072: OK",
+			es:           withPrivateKey(&commonv1.PrivateKeyOptions{Algorithm: commonv1.RSAPrivateKeyAlgorithm, Size: 3072}),
+			expectErrors: false,
+		},
+		{
+			name:         "ECDSA 4096: NOT OK",
+			es:           withPrivateKey(&commonv1.PrivateKeyOptions{Algorithm: commonv1.ECDSAPrivateKeyAlgorithm, Size: 4096}),
+			expectErrors: true,
+		},
+		{
+			name:         "RSA 1024: NOT OK",
+			es:           withPrivateKey(&commonv1.PrivateKeyOptions{Size: 1024}),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := validPrivateKey(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validPrivateKey(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec)
+			}
+		})
+	}
+}
+
+func Test_validIssuerRefs(t *testing.T) {
+	issuerRef := &commonv1.IssuerReference{Name: "my-issuer"}
+	userCertificate := commonv1.SecretRef{SecretName: "my-cert"}
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:         "no issuerRef: OK",
+			es:           esv1.Elasticsearch{},
+			expectErrors: false,
+		},
+		{
+			name: "HTTP and transport issuerRef: OK",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					HTTP:      commonv1.HTTPConfig{TLS: commonv1.TLSOptions{IssuerRef: issuerRef}},
+					Transport: esv1.TransportConfig{TLS: esv1.TransportTLSOptions{IssuerRef: issuerRef}},
+				},
+			},
+			expectErrors: false,
+		},
+		{
+			name: "HTTP issuerRef and certificate: NOT OK",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					HTTP: commonv1.HTTPConfig{TLS: commonv1.TLSOptions{IssuerRef: issuerRef, Certificate: userCertificate}},
+				},
+			},
+			expectErrors: true,
+		},
+		{
+			name: "transport issuerRef and certificate: NOT OK",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Transport: esv1.TransportConfig{TLS: esv1.TransportTLSOptions{IssuerRef: issuerRef, Certificate: userCertificate}},
+				},
+			},
+			expectErrors: true,
+		},
+		{
+			name: "transport issuerRef and node certificates: NOT OK",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Transport: esv1.TransportConfig{TLS: esv1.TransportTLSOptions{IssuerRef: issuerRef, NodeCertificates: userCertificate}},
+				},
+			},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := validIssuerRefs(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validIssuerRefs(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec)
+			}
+		})
+	}
+}
+
+func Test_validNodeCertificates(t *testing.T) {
+	withTransportTLS := func(tls esv1.TransportTLSOptions) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			Spec: esv1.ElasticsearchSpec{
+				Transport: esv1.TransportConfig{TLS: tls},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:         "no node certificates: OK",
+			es:           withTransportTLS(esv1.TransportTLSOptions{Certificate: commonv1.SecretRef{SecretName: "my-ca"}}),
+			expectErrors: false,
+		},
+		{
+			name:         "node certificates: OK",
+			es:           withTransportTLS(esv1.TransportTLSOptions{NodeCertificates: commonv1.SecretRef{SecretName: "my-certs"}}),
+			expectErrors: false,
+		},
+		{
+			name: "node certificates and CA: NOT OK",
+			es: withTransportTLS(esv1.TransportTLSOptions{
+				Certificate:      commonv1.SecretRef{SecretName: "my-ca"},
+				NodeCertificates: commonv1.SecretRef{SecretName: "my-certs"},
+			}),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := validNodeCertificates(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validNodeCertificates(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec)
+			}
+		})
+	}
+}
+
 func TestValidation_noDowngrades(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -496,3 +655,420 @@ func es(v string) esv1.Elasticsearch {
 		Spec: esv1.ElasticsearchSpec{Version: v},
 	}
 }
+
+func Test_validSnapshots(t *testing.T) {
+	policy := esv1.SnapshotLifecyclePolicy{Name: "daily", Schedule: "0 30 1 * * ?", Repository: "backups"}
+	repository := esv1.SnapshotRepository{Name: "backups", Type: "fs"}
+	tests := []struct {
+		name         string
+		version      string
+		snapshots    esv1.SnapshotsSpec
+		expectErrors bool
+	}{
+		{
+			name:    "no snapshots: OK",
+			version: "6.8.0",
+		},
+		{
+			name:      "repository in 6.x: OK",
+			version:   "6.8.0",
+			snapshots: esv1.SnapshotsSpec{Repositories: []esv1.SnapshotRepository{repository}},
+		},
+		{
+			name:    "repository and policy: OK",
+			version: "7.4.0",
+			snapshots: esv1.SnapshotsSpec{
+				Repositories: []esv1.SnapshotRepository{repository},
+				Policies:     []esv1.SnapshotLifecyclePolicy{policy},
+			},
+		},
+		{
+			name:         "policy before 7.4.0: NOT OK",
+			version:      "7.3.2",
+			snapshots:    esv1.SnapshotsSpec{Policies: []esv1.SnapshotLifecyclePolicy{policy}},
+			expectErrors: true,
+		},
+		{
+			name:         "duplicate repositories: NOT OK",
+			version:      "7.10.0",
+			snapshots:    esv1.SnapshotsSpec{Repositories: []esv1.SnapshotRepository{repository, repository}},
+			expectErrors: true,
+		},
+		{
+			name:         "duplicate policies: NOT OK",
+			version:      "7.10.0",
+			snapshots:    esv1.SnapshotsSpec{Policies: []esv1.SnapshotLifecyclePolicy{policy, policy}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es(tt.version)
+			es.Spec.Snapshots = tt.snapshots
+			actual := validSnapshots(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validSnapshots(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.snapshots)
+			}
+		})
+	}
+}
+
+func Test_validClusterResources(t *testing.T) {
+	inline := esv1.ClusterResource{Name: "logs", Definition: &commonv1.Config{Data: map[string]interface{}{"index_patterns": []interface{}{"logs-*"}}}}
+	fromConfigMap := esv1.ClusterResource{Name: "metrics", DefinitionRef: &esv1.ClusterResourceDefinitionSource{ConfigMapName: "templates", Key: "metrics.json"}}
+	tests := []struct {
+		name         string
+		version      string
+		resources    esv1.ClusterResourcesSpec
+		expectErrors bool
+	}{
+		{
+			name:    "no cluster resources: OK",
+			version: "6.8.0",
+		},
+		{
+			name:      "ingest pipelines and policies in 6.x: OK",
+			version:   "6.8.0",
+			resources: esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{inline}, IndexLifecyclePolicies: []esv1.ClusterResource{fromConfigMap}},
+		},
+		{
+			name:      "index templates: OK",
+			version:   "7.8.0",
+			resources: esv1.ClusterResourcesSpec{IndexTemplates: []esv1.ClusterResource{inline, fromConfigMap}},
+		},
+		{
+			name:         "index templates before 7.8.0: NOT OK",
+			version:      "7.7.1",
+			resources:    esv1.ClusterResourcesSpec{IndexTemplates: []esv1.ClusterResource{inline}},
+			expectErrors: true,
+		},
+		{
+			name:         "duplicate names: NOT OK",
+			version:      "7.10.0",
+			resources:    esv1.ClusterResourcesSpec{ComponentTemplates: []esv1.ClusterResource{inline, inline}},
+			expectErrors: true,
+		},
+		{
+			name:    "same name for different kinds: OK",
+			version: "7.10.0",
+			resources: esv1.ClusterResourcesSpec{
+				ComponentTemplates: []esv1.ClusterResource{inline},
+				IndexTemplates:     []esv1.ClusterResource{inline},
+			},
+		},
+		{
+			name:         "no definition: NOT OK",
+			version:      "7.10.0",
+			resources:    esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{{Name: "empty"}}},
+			expectErrors: true,
+		},
+		{
+			name:    "both definition and definitionRef: NOT OK",
+			version: "7.10.0",
+			resources: esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{
+				{Name: "both", Definition: inline.Definition, DefinitionRef: fromConfigMap.DefinitionRef},
+			}},
+			expectErrors: true,
+		},
+		{
+			name:    "definitionRef to both a ConfigMap and a Secret: NOT OK",
+			version: "7.10.0",
+			resources: esv1.ClusterResourcesSpec{IngestPipelines: []esv1.ClusterResource{
+				{Name: "both", DefinitionRef: &esv1.ClusterResourceDefinitionSource{ConfigMapName: "cm", SecretName: "secret", Key: "key"}},
+			}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es(tt.version)
+			es.Spec.ClusterResources = tt.resources
+			actual := validClusterResources(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validClusterResources(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.resources)
+			}
+		})
+	}
+}
+
+func Test_validNativeRealm(t *testing.T) {
+	password := esv1.NativeUserPasswordSource{SecretName: "passwords", Key: "jacknich"}
+	rules := &commonv1.Config{Data: map[string]interface{}{"field": map[string]interface{}{"realm.name": "saml1"}}}
+	tests := []struct {
+		name         string
+		auth         esv1.Auth
+		expectErrors bool
+	}{
+		{
+			name: "no native users nor role mappings: OK",
+		},
+		{
+			name: "native users and role mappings: OK",
+			auth: esv1.Auth{
+				NativeUsers: []esv1.NativeUser{
+					{Username: "jacknich", PasswordSecretRef: password, Roles: []string{"superuser"}},
+					{Username: "rdeniro", PasswordSecretRef: password},
+				},
+				RoleMappings: []esv1.RoleMapping{{Name: "saml-users", Roles: []string{"viewer"}, Rules: rules}},
+			},
+		},
+		{
+			name: "duplicate usernames: NOT OK",
+			auth: esv1.Auth{NativeUsers: []esv1.NativeUser{
+				{Username: "jacknich", PasswordSecretRef: password},
+				{Username: "jacknich", PasswordSecretRef: password},
+			}},
+			expectErrors: true,
+		},
+		{
+			name:         "username of a user managed by the operator: NOT OK",
+			auth:         esv1.Auth{NativeUsers: []esv1.NativeUser{{Username: "elastic-internal", PasswordSecretRef: password}}},
+			expectErrors: true,
+		},
+		{
+			name: "duplicate role mapping names: NOT OK",
+			auth: esv1.Auth{RoleMappings: []esv1.RoleMapping{
+				{Name: "saml-users", Roles: []string{"viewer"}, Rules: rules},
+				{Name: "saml-users", Roles: []string{"editor"}, Rules: rules},
+			}},
+			expectErrors: true,
+		},
+		{
+			name:         "role mapping without rules: NOT OK",
+			auth:         esv1.Auth{RoleMappings: []esv1.RoleMapping{{Name: "saml-users", Roles: []string{"viewer"}}}},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es("7.15.0")
+			es.Spec.Auth = tt.auth
+			actual := validNativeRealm(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validNativeRealm(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.auth)
+			}
+		})
+	}
+}
+
+func Test_validPasswordRotation(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		interval     *metav1.Duration
+		expectErrors bool
+	}{
+		{
+			name: "no rotation: OK",
+		},
+		{
+			name:        "rotation annotation and interval: OK",
+			annotations: map[string]string{user.RotatePasswordsAnnotation: "2021-06-01T10:00:00Z"},
+			interval:    &metav1.Duration{Duration: 30 * 24 * time.Hour},
+		},
+		{
+			name:         "interval too short: NOT OK",
+			interval:     &metav1.Duration{Duration: time.Minute},
+			expectErrors: true,
+		},
+		{
+			name:         "invalid rotation annotation: NOT OK",
+			annotations:  map[string]string{user.RotatePasswordsAnnotation: "now"},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es("7.15.0")
+			es.Annotations = tt.annotations
+			es.Spec.Auth.PasswordRotationInterval = tt.interval
+			actual := validPasswordRotation(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validPasswordRotation(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_validElasticUser(t *testing.T) {
+	hashSource := &esv1.PasswordHashSource{SecretName: "elastic-password-hash", Key: "hash"}
+	tests := []struct {
+		name         string
+		elasticUser  *esv1.ElasticUser
+		expectErrors bool
+	}{
+		{
+			name: "default elastic user: OK",
+		},
+		{
+			name:        "elastic user disabled: OK",
+			elasticUser: &esv1.ElasticUser{Disabled: true},
+		},
+		{
+			name:        "user-provided password hash: OK",
+			elasticUser: &esv1.ElasticUser{PasswordHashSecretRef: hashSource},
+		},
+		{
+			name:         "elastic user disabled with a user-provided password hash: NOT OK",
+			elasticUser:  &esv1.ElasticUser{Disabled: true, PasswordHashSecretRef: hashSource},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es("7.15.0")
+			es.Spec.Auth.ElasticUser = tt.elasticUser
+			actual := validElasticUser(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validElasticUser(). Name: %v, actual %v, wanted: %v", tt.name, actual, tt.expectErrors)
+			}
+		})
+	}
+}
+
+func Test_validRemoteClusters(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		remoteClusters []esv1.RemoteCluster
+		expectErrors   bool
+	}{
+		{
+			name:    "no remote clusters: OK",
+			version: "6.8.0",
+		},
+		{
+			name:    "elasticsearchRef and seeds in 6.x: OK",
+			version: "6.8.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "eck", ElasticsearchRef: commonv1.ObjectSelector{Name: "es2"}},
+				{Name: "on-prem", Seeds: []string{"10.0.0.1:9300"}, CertificateAuthorities: commonv1.SecretRef{SecretName: "on-prem-ca"}},
+			},
+		},
+		{
+			name:    "proxy mode: OK",
+			version: "7.7.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "cloud", ProxyAddress: "cloud.example.com:9400", ServerName: "cloud.example.com"},
+			},
+		},
+		{
+			name:    "proxy mode before 7.7.0: NOT OK",
+			version: "7.6.2",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "cloud", ProxyAddress: "cloud.example.com:9400"},
+			},
+			expectErrors: true,
+		},
+		{
+			name:    "elasticsearchRef to a secret: NOT OK",
+			version: "7.10.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "external", ElasticsearchRef: commonv1.ObjectSelector{SecretName: "external-es"}},
+			},
+			expectErrors: true,
+		},
+		{
+			name:    "both elasticsearchRef and seeds: NOT OK",
+			version: "7.10.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "both", ElasticsearchRef: commonv1.ObjectSelector{Name: "es2"}, Seeds: []string{"10.0.0.1:9300"}},
+			},
+			expectErrors: true,
+		},
+		{
+			name:    "both seeds and proxyAddress: NOT OK",
+			version: "7.10.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "both", Seeds: []string{"10.0.0.1:9300"}, ProxyAddress: "cloud.example.com:9400"},
+			},
+			expectErrors: true,
+		},
+		{
+			name:    "serverName without proxyAddress: NOT OK",
+			version: "7.10.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "on-prem", Seeds: []string{"10.0.0.1:9300"}, ServerName: "on-prem.example.com"},
+			},
+			expectErrors: true,
+		},
+		{
+			name:    "certificateAuthorities with elasticsearchRef: NOT OK",
+			version: "7.10.0",
+			remoteClusters: []esv1.RemoteCluster{
+				{Name: "eck", ElasticsearchRef: commonv1.ObjectSelector{Name: "es2"}, CertificateAuthorities: commonv1.SecretRef{SecretName: "ca"}},
+			},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es(tt.version)
+			es.Spec.RemoteClusters = tt.remoteClusters
+			actual := validRemoteClusters(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validRemoteClusters(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.remoteClusters)
+			}
+		})
+	}
+}
+
+func Test_validZoneAwareness(t *testing.T) {
+	tests := []struct {
+		name          string
+		zoneAwareness *esv1.ZoneAwareness
+		config        map[string]interface{}
+		expectErrors  bool
+	}{
+		{
+			name: "no zone awareness: OK",
+			config: map[string]interface{}{
+				"node.attr.zone": "europe-west1-b",
+			},
+		},
+		{
+			name:          "default attribute: OK",
+			zoneAwareness: &esv1.ZoneAwareness{},
+			config: map[string]interface{}{
+				"node.attr.rack": "rack1",
+			},
+		},
+		{
+			name:          "custom attribute: OK",
+			zoneAwareness: &esv1.ZoneAwareness{TopologyKey: "example.com/rack", Attribute: "k8s_rack"},
+		},
+		{
+			name:          "invalid attribute: NOT OK",
+			zoneAwareness: &esv1.ZoneAwareness{Attribute: "k8s.zone"},
+			expectErrors:  true,
+		},
+		{
+			name:          "attribute set in the NodeSet config: NOT OK",
+			zoneAwareness: &esv1.ZoneAwareness{},
+			config: map[string]interface{}{
+				"node.attr.zone": "europe-west1-b",
+			},
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es("7.10.0")
+			es.Spec.ZoneAwareness = tt.zoneAwareness
+			if tt.config != nil {
+				es.Spec.NodeSets = []esv1.NodeSet{{Name: "default", Config: &commonv1.Config{Data: tt.config}}}
+			}
+			actual := validZoneAwareness(es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed validZoneAwareness(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.zoneAwareness)
+			}
+		})
+	}
+}
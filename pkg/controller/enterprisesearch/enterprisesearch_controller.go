@@ -175,7 +175,7 @@ func (r *ReconcileEnterpriseSearch) Reconcile(ctx context.Context, request recon
 		return reconcile.Result{}, tracing.CaptureError(ctx, fmt.Errorf("updating controller version: %w", err))
 	}
 
-	if !association.IsConfiguredIfSet(&ent, r.recorder) {
+	if !association.AreConfiguredIfSet(ent.GetAssociations(), r.recorder) {
 		return reconcile.Result{}, nil
 	}
 
@@ -220,6 +220,7 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		Services:              []corev1.Service{*svc},
 		CACertRotation:        r.CACertRotation,
 		CertRotation:          r.CertRotation,
+		PrivateKeyOptions:     r.PrivateKeyOptions,
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
@@ -259,7 +260,7 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		return reconcile.Result{}, fmt.Errorf("reconcile deployment: %w", err)
 	}
 
-	err = r.updateStatus(ent, deploy, svc.Name)
+	err = r.updateStatus(ent, deploy, svc.Name, results)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("updating status: %w", err)
 	}
@@ -280,16 +281,24 @@ func (r *ReconcileEnterpriseSearch) validate(ctx context.Context, ent *entv1.Ent
 	return nil
 }
 
-func (r *ReconcileEnterpriseSearch) updateStatus(ent entv1.EnterpriseSearch, deploy appsv1.Deployment, svcName string) error {
+func (r *ReconcileEnterpriseSearch) updateStatus(
+	ent entv1.EnterpriseSearch,
+	deploy appsv1.Deployment,
+	svcName string,
+	results *reconciler.Results,
+) error {
 	pods, err := k8s.PodsMatchingLabels(r.K8sClient(), ent.Namespace, map[string]string{EnterpriseSearchNameLabelName: ent.Name})
 	if err != nil {
 		return err
 	}
 	newStatus := entv1.EnterpriseSearchStatus{
-		DeploymentStatus: common.DeploymentStatus(ent.Status.DeploymentStatus, deploy, pods, VersionLabelName),
-		ExternalService:  svcName,
-		Association:      ent.Status.Association,
+		DeploymentStatus:             common.DeploymentStatus(ent.Status.DeploymentStatus, deploy, pods, VersionLabelName),
+		ExternalService:              svcName,
+		Association:                  ent.Status.Association,
+		MonitoringAssociationsStatus: ent.Status.MonitoringAssociationsStatus,
 	}
+	common.UpdateConditions(&newStatus.Conditions, ent.Generation, results, newStatus.Version, ent.Spec.Version)
+	newStatus.ObservedGeneration = ent.Generation
 
 	if reflect.DeepEqual(newStatus, ent.Status) {
 		return nil // nothing to do
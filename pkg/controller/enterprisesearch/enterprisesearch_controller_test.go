@@ -25,6 +25,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
 	entName "github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
 	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
@@ -319,6 +320,27 @@ func (f *fakeClientStatusCall) Status() client.StatusWriter {
 	return f.Client.Status()
 }
 
+// reconciledConditions returns the conditions expected once the given generation of an Enterprise Search with no
+// known version is reconciled.
+func reconciledConditions(generation int64) []metav1.Condition {
+	return []metav1.Condition{
+		{
+			Type:               commonv1.ReconciliationComplete,
+			Status:             metav1.ConditionTrue,
+			Reason:             commonv1.ReconciledReason,
+			Message:            "Reconciliation completed",
+			ObservedGeneration: generation,
+		},
+		{
+			Type:               commonv1.RunningDesiredVersion,
+			Status:             metav1.ConditionUnknown,
+			Reason:             commonv1.VersionUnknownReason,
+			Message:            "Running version is not known yet",
+			ObservedGeneration: generation,
+		},
+	}
+}
+
 func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -347,6 +369,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 					AvailableNodes: 3,
 					Version:        "",
 					Health:         "green",
+					Conditions:     reconciledConditions(0),
 				},
 				ExternalService: "http-service",
 			},
@@ -371,12 +394,43 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 					AvailableNodes: 3,
 					Version:        "",
 					Health:         "green",
+					Conditions:     reconciledConditions(0),
 				},
 				ExternalService: "http-service",
 				Association:     commonv1.AssociationEstablished,
 			},
 			wantStatusUpdateCalled: true,
 		},
+		{
+			name: "preserve existing monitoring association status",
+			ent: entv1.EnterpriseSearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ent"},
+				Status: entv1.EnterpriseSearchStatus{MonitoringAssociationsStatus: commonv1.AssociationStatusMap{
+					"ns/monitoring": commonv1.AssociationEstablished,
+				}}},
+			deploy: appsv1.Deployment{Status: appsv1.DeploymentStatus{
+				AvailableReplicas: 3,
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:   appsv1.DeploymentAvailable,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			}},
+			svcName: "http-service",
+			wantStatus: entv1.EnterpriseSearchStatus{
+				DeploymentStatus: commonv1.DeploymentStatus{
+					AvailableNodes: 3,
+					Version:        "",
+					Health:         "green",
+					Conditions:     reconciledConditions(0),
+				},
+				ExternalService: "http-service",
+				MonitoringAssociationsStatus: commonv1.AssociationStatusMap{
+					"ns/monitoring": commonv1.AssociationEstablished,
+				},
+			},
+			wantStatusUpdateCalled: true,
+		},
 		{
 			name: "red health if deployment not available",
 			ent:  entv1.EnterpriseSearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ent"}},
@@ -395,6 +449,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 					AvailableNodes: 3,
 					Version:        "",
 					Health:         "red",
+					Conditions:     reconciledConditions(0),
 				},
 				ExternalService: "http-service",
 			},
@@ -408,6 +463,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 						AvailableNodes: 3,
 						Version:        "",
 						Health:         "green",
+						Conditions:     reconciledConditions(0),
 					},
 					ExternalService: "http-service",
 				}},
@@ -426,6 +482,42 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 					AvailableNodes: 4,
 					Version:        "",
 					Health:         "green",
+					Conditions:     reconciledConditions(0),
+				},
+				ExternalService: "http-service",
+			},
+			wantStatusUpdateCalled: true,
+		},
+		{
+			name: "update the observed generation",
+			ent: entv1.EnterpriseSearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ent", Generation: 2},
+				Status: entv1.EnterpriseSearchStatus{
+					DeploymentStatus: commonv1.DeploymentStatus{
+						AvailableNodes:     3,
+						Version:            "",
+						Health:             "green",
+						Conditions:         reconciledConditions(1),
+						ObservedGeneration: 1,
+					},
+					ExternalService: "http-service",
+				}},
+			deploy: appsv1.Deployment{Status: appsv1.DeploymentStatus{
+				AvailableReplicas: 3,
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:   appsv1.DeploymentAvailable,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			}},
+			svcName: "http-service",
+			wantStatus: entv1.EnterpriseSearchStatus{
+				DeploymentStatus: commonv1.DeploymentStatus{
+					AvailableNodes:     3,
+					Version:            "",
+					Health:             "green",
+					Conditions:         reconciledConditions(2),
+					ObservedGeneration: 2,
 				},
 				ExternalService: "http-service",
 			},
@@ -439,6 +531,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 						AvailableNodes: 3,
 						Version:        "",
 						Health:         "green",
+						Conditions:     reconciledConditions(0),
 					},
 					ExternalService: "http-service",
 				}},
@@ -457,6 +550,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 					AvailableNodes: 3,
 					Version:        "",
 					Health:         "green",
+					Conditions:     reconciledConditions(0),
 				},
 				ExternalService: "http-service",
 			},
@@ -470,6 +564,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 						AvailableNodes: 3,
 						Version:        "",
 						Health:         "green",
+						Conditions:     reconciledConditions(0),
 					},
 					ExternalService: "http-service",
 				}},
@@ -488,6 +583,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 					AvailableNodes: 3,
 					Version:        "",
 					Health:         "red",
+					Conditions:     reconciledConditions(0),
 				},
 				ExternalService: "http-service",
 			},
@@ -503,7 +599,7 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 				Client:   c,
 				recorder: fakeRecorder,
 			}
-			err := r.updateStatus(tt.ent, tt.deploy, tt.svcName)
+			err := r.updateStatus(tt.ent, tt.deploy, tt.svcName, reconciler.NewResult(context.Background()))
 			require.NoError(t, err)
 
 			require.Equal(t, tt.wantStatusUpdateCalled, c.updateCalled)
@@ -511,6 +607,10 @@ func TestReconcileEnterpriseSearch_updateStatus(t *testing.T) {
 			var updatedEnt entv1.EnterpriseSearch
 			err = c.Get(context.Background(), k8s.ExtractNamespacedName(&tt.ent), &updatedEnt)
 			require.NoError(t, err)
+			// ignore the transition time of the conditions set during the test
+			for i := range updatedEnt.Status.Conditions {
+				updatedEnt.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+			}
 			require.Equal(t, tt.wantStatus, updatedEnt.Status)
 
 			if tt.wantEvent {
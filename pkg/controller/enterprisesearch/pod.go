@@ -16,6 +16,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
 const (
@@ -52,7 +53,7 @@ var (
 	}
 )
 
-func newPodSpec(ent entv1.EnterpriseSearch, configHash string) corev1.PodTemplateSpec {
+func newPodSpec(client k8s.Client, ent entv1.EnterpriseSearch, configHash string) (corev1.PodTemplateSpec, error) {
 	// ensure the Pod gets rotated on config change
 	labels := map[string]string{ConfigHashLabelName: configHash}
 
@@ -78,7 +79,12 @@ func newPodSpec(ent entv1.EnterpriseSearch, configHash string) corev1.PodTemplat
 	builder = withESCertsVolume(builder, ent)
 	builder = withHTTPCertsVolume(builder, ent)
 
-	return builder.PodTemplate
+	builder, err := withMonitoring(client, builder, ent)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	return builder.PodTemplate, nil
 }
 
 func withESCertsVolume(builder *defaults.PodTemplateBuilder, ent entv1.EnterpriseSearch) *defaults.PodTemplateBuilder {
@@ -10,7 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_newPodSpec(t *testing.T) {
@@ -43,7 +45,8 @@ func Test_newPodSpec(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := newPodSpec(tt.ent, "amFpbWVsZXNjaGF0c2V0dm91cz8=")
+			got, err := newPodSpec(k8s.NewFakeClient(), tt.ent, "amFpbWVsZXNjaGF0c2V0dm91cz8=")
+			require.NoError(t, err)
 			tt.assertions(got)
 		})
 	}
@@ -0,0 +1,103 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	"fmt"
+	"path/filepath"
+
+	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/enterprisesearch/name"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+// metricbeatConfig builds the Metricbeat configuration to collect the metrics of the local Enterprise Search instance.
+// Metricbeat uses the credentials of the Enterprise Search user in the associated Elasticsearch cluster.
+func metricbeatConfig(client k8s.Client, ent entv1.EnterpriseSearch) (*settings.CanonicalConfig, error) {
+	module := map[string]interface{}{
+		"module":        "enterprisesearch",
+		"metricsets":    []string{"health", "stats"},
+		"period":        "10s",
+		"xpack.enabled": true,
+		"hosts":         []string{fmt.Sprintf("%s://localhost:%d", ent.Spec.HTTP.Protocol(), HTTPPort)},
+	}
+	if ent.AssociationConf().IsConfigured() {
+		username, password, err := association.ElasticsearchAuthSettings(client, &ent)
+		if err != nil {
+			return nil, err
+		}
+		module["username"] = username
+		module["password"] = password
+	}
+	if ent.Spec.HTTP.TLS.Enabled() {
+		module["ssl.certificate_authorities"] = []string{
+			filepath.Join(httpCertificatesVolume(ent).VolumeMount().MountPath, certificates.CAFileName),
+		}
+		// the certificate is not issued for localhost, only verify that it is signed by the CA
+		module["ssl.verification_mode"] = "certificate"
+	}
+
+	return settings.NewCanonicalConfigFrom(map[string]interface{}{
+		"metricbeat.modules": []interface{}{module},
+		"processors": []interface{}{
+			map[string]interface{}{"add_cloud_metadata": map[string]interface{}{}},
+			map[string]interface{}{"add_host_metadata": map[string]interface{}{}},
+		},
+	})
+}
+
+// httpCertificatesVolume returns the volume holding the HTTP certificates of Enterprise Search.
+func httpCertificatesVolume(ent entv1.EnterpriseSearch) volume.SecretVolume {
+	return certificates.HTTPCertSecretVolume(name.EntNamer, ent.Name)
+}
+
+// monitoringSidecars returns the Beat sidecars to deploy in the Enterprise Search Pods according to the monitoring spec.
+// Only the collection of metrics is supported.
+func monitoringSidecars(client k8s.Client, ent entv1.EnterpriseSearch) ([]stackmon.BeatSidecar, error) {
+	if !monitoring.IsMetricsDefined(&ent) {
+		return nil, nil
+	}
+	config, err := metricbeatConfig(client, ent)
+	if err != nil {
+		return nil, err
+	}
+	var volumes []volume.VolumeLike
+	if ent.Spec.HTTP.TLS.Enabled() {
+		volumes = append(volumes, httpCertificatesVolume(ent))
+	}
+	metricbeat, err := stackmon.NewMetricBeatSidecar(client, &ent, name.EntNamer, ent.Spec.Version, config, volumes...)
+	if err != nil {
+		return nil, err
+	}
+	return []stackmon.BeatSidecar{metricbeat}, nil
+}
+
+// reconcileMonitoringConfigSecrets reconciles the secrets holding the configuration of the Beat sidecars.
+func reconcileMonitoringConfigSecrets(client k8s.Client, ent entv1.EnterpriseSearch) error {
+	sidecars, err := monitoringSidecars(client, ent)
+	if err != nil {
+		return err
+	}
+	return stackmon.ReconcileConfigSecrets(client, &ent, Labels(ent.Name), sidecars...)
+}
+
+// withMonitoring updates the Enterprise Search Pod template builder to deploy Metricbeat in a sidecar container.
+func withMonitoring(client k8s.Client, builder *defaults.PodTemplateBuilder, ent entv1.EnterpriseSearch) (*defaults.PodTemplateBuilder, error) {
+	if !monitoring.IsDefined(&ent) {
+		return builder, nil
+	}
+	sidecars, err := monitoringSidecars(client, ent)
+	if err != nil {
+		return nil, err
+	}
+	return stackmon.WithMonitoring(builder, sidecars...), nil
+}
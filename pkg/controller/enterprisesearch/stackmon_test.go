@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package enterprisesearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func Test_newPodSpec_withMonitoring(t *testing.T) {
+	ent := entv1.EnterpriseSearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "ns"},
+		Spec: entv1.EnterpriseSearchSpec{
+			Version: "7.14.0",
+			Monitoring: commonv1.Monitoring{
+				Metrics: commonv1.MetricsMonitoring{
+					ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "monitoring"}},
+				},
+			},
+		},
+	}
+	ent.MonitoringAssociation(commonv1.ObjectSelector{Name: "monitoring"}).SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "sample-ent-monitoring-user",
+		AuthSecretKey:  "ns-sample-ent-monitoring-user",
+		CASecretName:   "sample-ent-monitoring-ca",
+		URL:            "https://monitoring-es-http.ns.svc:9200",
+		Version:        "7.14.0",
+	})
+	client := k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample-ent-monitoring-user", Namespace: "ns"},
+		Data:       map[string][]byte{"ns-sample-ent-monitoring-user": []byte("1234567890")},
+	})
+
+	pod, err := newPodSpec(client, ent, "amFpbWVsZXNjaGF0c2V0dm91cz8=")
+	require.NoError(t, err)
+	require.Len(t, pod.Spec.Containers, 2)
+	assert.Equal(t, entv1.EnterpriseSearchContainerName, pod.Spec.Containers[0].Name)
+	assert.Equal(t, "metricbeat", pod.Spec.Containers[1].Name)
+	assert.Contains(t, pod.Annotations, "monitoring.k8s.elastic.co/metricbeat-config-hash")
+
+	require.NoError(t, reconcileMonitoringConfigSecrets(client, ent))
+	var secret corev1.Secret
+	nsn := types.NamespacedName{Namespace: "ns", Name: "sample-ent-monitoring-metricbeat-config"}
+	require.NoError(t, client.Get(context.Background(), nsn, &secret))
+	assert.Contains(t, string(secret.Data["metricbeat.yml"]), "enterprisesearch")
+}
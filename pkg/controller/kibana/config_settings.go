@@ -35,6 +35,8 @@ const (
 
 	// esCertsVolumeMountPath is the directory containing Elasticsearch certificates.
 	esCertsVolumeMountPath = "/usr/share/kibana/config/elasticsearch-certs"
+	// entCertsVolumeMountPath is the directory containing Enterprise Search certificates.
+	entCertsVolumeMountPath = "/usr/share/kibana/config/ent-certs"
 )
 
 // Constants to use for the Kibana configuration settings.
@@ -51,14 +53,21 @@ const (
 	ElasticsearchSslCertificateAuthorities = "elasticsearch.ssl.certificateAuthorities"
 	ElasticsearchSslVerificationMode       = "elasticsearch.ssl.verificationMode"
 
-	ElasticsearchUsername = "elasticsearch.username"
-	ElasticsearchPassword = "elasticsearch.password"
+	ElasticsearchUsername            = "elasticsearch.username"
+	ElasticsearchPassword            = "elasticsearch.password"
+	ElasticsearchServiceAccountToken = "elasticsearch.serviceAccountToken"
 
 	ElasticsearchHosts = "elasticsearch.hosts"
 
 	ServerSSLEnabled     = "server.ssl.enabled"
 	ServerSSLCertificate = "server.ssl.certificate"
 	ServerSSLKey         = "server.ssl.key"
+
+	MapEmsURL = "map.emsUrl"
+
+	EnterpriseSearchHost                      = "enterpriseSearch.host"
+	EnterpriseSearchSslCertificateAuthorities = "enterpriseSearch.ssl.certificateAuthorities"
+	EnterpriseSearchSslVerificationMode       = "enterpriseSearch.ssl.verificationMode"
 )
 
 // CanonicalConfig contains configuration for Kibana ("kibana.yml"),
@@ -96,6 +105,9 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 	cfg := settings.MustCanonicalConfig(baseSettings(&kb, ipFamily))
 	kibanaTLSCfg := settings.MustCanonicalConfig(kibanaTLSSettings(kb))
 	versionSpecificCfg := VersionDefaults(&kb, v)
+	monitoringCfg := monitoringConfig(kb)
+	mapsCfg := settings.MustCanonicalConfig(mapsSettings(kb))
+	entCfg := settings.MustCanonicalConfig(enterpriseSearchSettings(kb))
 
 	if !kb.RequiresAssociation() {
 		// merge the configuration with userSettings last so they take precedence
@@ -103,13 +115,16 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 			reusableSettings,
 			versionSpecificCfg,
 			kibanaTLSCfg,
+			monitoringCfg,
+			mapsCfg,
+			entCfg,
 			userSettings); err != nil {
 			return CanonicalConfig{}, err
 		}
 		return CanonicalConfig{cfg}, nil
 	}
 
-	username, password, err := association.ElasticsearchAuthSettings(client, &kb)
+	credentials, err := association.ElasticsearchCredentials(client, &kb)
 	if err != nil {
 		return CanonicalConfig{}, err
 	}
@@ -120,12 +135,10 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 		versionSpecificCfg,
 		kibanaTLSCfg,
 		settings.MustCanonicalConfig(elasticsearchTLSSettings(kb)),
-		settings.MustCanonicalConfig(
-			map[string]interface{}{
-				ElasticsearchUsername: username,
-				ElasticsearchPassword: password,
-			},
-		),
+		settings.MustCanonicalConfig(elasticsearchAuthSettings(credentials)),
+		monitoringCfg,
+		mapsCfg,
+		entCfg,
 		userSettings,
 	)
 	if err != nil {
@@ -135,6 +148,20 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 	return CanonicalConfig{cfg}, nil
 }
 
+// elasticsearchAuthSettings returns the settings used by Kibana to authenticate against Elasticsearch, either with a
+// service account token or with a username and a password.
+func elasticsearchAuthSettings(credentials association.Credentials) map[string]interface{} {
+	if credentials.ServiceAccountToken != "" {
+		return map[string]interface{}{
+			ElasticsearchServiceAccountToken: credentials.ServiceAccountToken,
+		}
+	}
+	return map[string]interface{}{
+		ElasticsearchUsername: credentials.Username,
+		ElasticsearchPassword: credentials.Password,
+	}
+}
+
 // Some previously-unsupported keys cause Kibana to error out even if the values are empty. ucfg cannot ignore fields easily so this is necessary to
 // support older versions
 func filterConfigSettings(kb kbv1.Kibana, cfg *settings.CanonicalConfig) (*settings.CanonicalConfig, error) {
@@ -247,6 +274,33 @@ func kibanaTLSSettings(kb kbv1.Kibana) map[string]interface{} {
 	}
 }
 
+// mapsSettings returns the settings pointing Kibana to the associated Elastic Maps Server, if any.
+func mapsSettings(kb kbv1.Kibana) map[string]interface{} {
+	assocConf := kbv1.NewKibanaMapsAssociation(&kb).AssociationConf()
+	if !assocConf.URLIsConfigured() {
+		return nil
+	}
+	return map[string]interface{}{
+		MapEmsURL: assocConf.GetURL(),
+	}
+}
+
+// enterpriseSearchSettings returns the settings pointing Kibana to the associated Enterprise Search, if any.
+func enterpriseSearchSettings(kb kbv1.Kibana) map[string]interface{} {
+	assocConf := kbv1.NewKibanaEntAssociation(&kb).AssociationConf()
+	if !assocConf.URLIsConfigured() {
+		return nil
+	}
+	cfg := map[string]interface{}{
+		EnterpriseSearchHost: assocConf.GetURL(),
+	}
+	if assocConf.GetCACertProvided() {
+		cfg[EnterpriseSearchSslCertificateAuthorities] = path.Join(entCertsVolumeMountPath, certificates.CAFileName)
+		cfg[EnterpriseSearchSslVerificationMode] = "certificate"
+	}
+	return cfg
+}
+
 func elasticsearchTLSSettings(kb kbv1.Kibana) map[string]interface{} {
 	cfg := map[string]interface{}{
 		ElasticsearchSslVerificationMode: "certificate",
@@ -268,3 +322,12 @@ func esCaCertSecretVolume(kb kbv1.Kibana) volume.SecretVolume {
 		esCertsVolumeMountPath,
 	)
 }
+
+// entCaCertSecretVolume returns a SecretVolume to hold the Enterprise Search CA certs for the given Kibana resource.
+func entCaCertSecretVolume(kb kbv1.Kibana) volume.SecretVolume {
+	return volume.NewSecretVolumeWithMountPath(
+		kbv1.NewKibanaEntAssociation(&kb).AssociationConf().GetCASecretName(),
+		"ent-certs",
+		entCertsVolumeMountPath,
+	)
+}
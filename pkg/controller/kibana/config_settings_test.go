@@ -276,6 +276,95 @@ func TestNewConfigSettings(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "with Association using a service account token",
+			args: args{
+				kb: func() kbv1.Kibana {
+					kb := mkKibana()
+					kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "test-es"}
+					kb.SetAssociationConf(&commonv1.AssociationConf{
+						AuthSecretName:          "auth-secret",
+						AuthSecretKey:           "kb-token",
+						AuthServiceAccountToken: true,
+						CASecretName:            "ca-secret",
+						CACertProvided:          true,
+						URL:                     "https://es-url:9200",
+					})
+					return kb
+				},
+				client: k8s.NewFakeClient(
+					existingSecret,
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "auth-secret",
+							Namespace: mkKibana().Namespace,
+						},
+						Data: map[string][]byte{
+							"kb-token": []byte("token"),
+						},
+					},
+				),
+				ipFamily: corev1.IPv4Protocol,
+			},
+			want: func() []byte {
+				cfg, err := settings.ParseConfig(defaultConfig)
+				require.NoError(t, err)
+				assocCfg, err := settings.ParseConfig(associationConfig)
+				require.NoError(t, err)
+				require.NoError(t, cfg.MergeWith(assocCfg))
+				_, err = (*ucfg.Config)(cfg).Remove(ElasticsearchUsername, -1, settings.Options...)
+				require.NoError(t, err)
+				_, err = (*ucfg.Config)(cfg).Remove(ElasticsearchPassword, -1, settings.Options...)
+				require.NoError(t, err)
+				require.NoError(t, (*ucfg.Config)(cfg).SetString(ElasticsearchServiceAccountToken, -1, "token", settings.Options...))
+				bytes, err := cfg.Render()
+				require.NoError(t, err)
+				return bytes
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "with Elastic Maps Server association",
+			args: args{
+				client: k8s.NewFakeClient(existingSecret),
+				kb: func() kbv1.Kibana {
+					kb := mkKibana()
+					kb.Spec.MapsRef = commonv1.ObjectSelector{Name: "test-ems"}
+					kbv1.NewKibanaMapsAssociation(&kb).SetAssociationConf(&commonv1.AssociationConf{
+						AuthSecretName: commonv1.NoAuthRequiredValue,
+						URL:            "https://test-ems-ems-http.default.svc:8080",
+					})
+					return kb
+				},
+				ipFamily: corev1.IPv4Protocol,
+			},
+			want: append(defaultConfig, []byte(`map.emsUrl: https://test-ems-ems-http.default.svc:8080`)...),
+		},
+		{
+			name: "with Enterprise Search association",
+			args: args{
+				client: k8s.NewFakeClient(existingSecret),
+				kb: func() kbv1.Kibana {
+					kb := mkKibana()
+					kb.Spec.EnterpriseSearchRef = commonv1.ObjectSelector{Name: "test-ent"}
+					kbv1.NewKibanaEntAssociation(&kb).SetAssociationConf(&commonv1.AssociationConf{
+						AuthSecretName: commonv1.NoAuthRequiredValue,
+						CACertProvided: true,
+						CASecretName:   "ent-ca-secret",
+						URL:            "https://test-ent-ent-http.default.svc:3002",
+					})
+					return kb
+				},
+				ipFamily: corev1.IPv4Protocol,
+			},
+			want: append(defaultConfig, []byte(`
+enterpriseSearch:
+  host: https://test-ent-ent-http.default.svc:3002
+  ssl:
+    certificateAuthorities: /usr/share/kibana/config/ent-certs/ca.crt
+    verificationMode: certificate
+`)...),
+		},
 		{
 			name: "with user config",
 			args: args{
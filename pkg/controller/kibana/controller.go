@@ -199,6 +199,8 @@ func (r *ReconcileKibana) doReconcile(ctx context.Context, request reconcile.Req
 
 	state := NewState(request, kb)
 	results := driver.Reconcile(ctx, &state, kb, r.params)
+	common.UpdateConditions(&state.Kibana.Status.Conditions, kb.Generation, results, state.Kibana.Status.Version, kb.Spec.Version)
+	state.Kibana.Status.ObservedGeneration = kb.Generation
 
 	// update status
 	err = r.updateStatus(ctx, state)
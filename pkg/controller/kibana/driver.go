@@ -96,7 +96,7 @@ func (d *driver) Reconcile(
 	params operator.Parameters,
 ) *reconciler.Results {
 	results := reconciler.NewResult(ctx)
-	if !association.IsConfiguredIfSet(kb, d.recorder) {
+	if !association.AreConfiguredIfSet(kb.GetAssociations(), d.recorder) {
 		return results
 	}
 
@@ -116,6 +116,7 @@ func (d *driver) Reconcile(
 		Services:              []corev1.Service{*svc},
 		CACertRotation:        params.CACertRotation,
 		CertRotation:          params.CertRotation,
+		PrivateKeyOptions:     params.PrivateKeyOptions,
 		GarbageCollectSecrets: true,
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
@@ -137,6 +138,10 @@ func (d *driver) Reconcile(
 		return results.WithError(err)
 	}
 
+	if err := reconcileMonitoringConfigSecrets(d.client, *kb); err != nil {
+		return results.WithError(err)
+	}
+
 	span, _ := apm.StartSpan(ctx, "reconcile_deployment", tracing.SpanTypeApp)
 	defer span.End()
 
@@ -200,7 +205,10 @@ func (d *driver) deploymentParams(kb *kbv1.Kibana) (deployment.Params, error) {
 		return deployment.Params{}, err
 	}
 
-	kibanaPodSpec := NewPodTemplateSpec(*kb, keystoreResources, d.buildVolumes(kb))
+	kibanaPodSpec, err := NewPodTemplateSpec(d.client, *kb, keystoreResources, d.buildVolumes(kb))
+	if err != nil {
+		return deployment.Params{}, err
+	}
 
 	// Build a checksum of the configuration, which we can use to cause the Deployment to roll Kibana
 	// instances in case of any change in the CA file, secure settings or credentials contents.
@@ -267,6 +275,11 @@ func (d *driver) buildVolumes(kb *kbv1.Kibana) []commonvolume.VolumeLike {
 		volumes = append(volumes, esCertsVolume)
 	}
 
+	if kbv1.NewKibanaEntAssociation(kb).AssociationConf().CAIsConfigured() {
+		entCertsVolume := entCaCertSecretVolume(*kb)
+		volumes = append(volumes, entCertsVolume)
+	}
+
 	if kb.Spec.HTTP.TLS.Enabled() {
 		httpCertsVolume := certificates.HTTPCertSecretVolume(Namer, kb.Name)
 		volumes = append(volumes, httpCertsVolume)
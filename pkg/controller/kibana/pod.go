@@ -16,6 +16,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/pod"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
 )
 
 const (
@@ -69,7 +70,7 @@ func readinessProbe(useTLS bool) corev1.Probe {
 	}
 }
 
-func NewPodTemplateSpec(kb kbv1.Kibana, keystore *keystore.Resources, volumes []volume.VolumeLike) corev1.PodTemplateSpec {
+func NewPodTemplateSpec(client k8s.Client, kb kbv1.Kibana, keystore *keystore.Resources, volumes []volume.VolumeLike) (corev1.PodTemplateSpec, error) {
 	labels := NewLabels(kb.Name)
 	labels[KibanaVersionLabelName] = kb.Spec.Version
 
@@ -93,7 +94,12 @@ func NewPodTemplateSpec(kb kbv1.Kibana, keystore *keystore.Resources, volumes []
 			WithInitContainers(keystore.InitContainer)
 	}
 
-	return builder.WithInitContainerDefaults().PodTemplate
+	builder, err := withMonitoring(client, builder.WithInitContainerDefaults(), kb)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	return builder.PodTemplate, nil
 }
 
 // GetKibanaContainer returns the Kibana container from the given podSpec.
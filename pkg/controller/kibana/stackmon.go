@@ -0,0 +1,186 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package kibana
+
+import (
+	"fmt"
+	"path/filepath"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+const (
+	// XpackMonitoringKibanaCollectionEnabled controls the legacy internal collection of Kibana monitoring data
+	XpackMonitoringKibanaCollectionEnabled = "monitoring.kibana.collection.enabled"
+
+	// LogsVolumeName is the name of the volume holding the Kibana logs, shared with the Filebeat sidecar
+	LogsVolumeName      = "kibana-logs"
+	LogsVolumeMountPath = "/usr/share/kibana/logs"
+
+	// filebeatDataVolumeName is the name of the volume holding the Filebeat registry
+	filebeatDataVolumeName = "filebeat-data"
+	filebeatDataMountPath  = "/usr/share/filebeat/data"
+)
+
+var (
+	// logsVolume is the volume where Kibana writes its logs for Filebeat to read them
+	logsVolume = volume.NewEmptyDirVolume(LogsVolumeName, LogsVolumeMountPath)
+
+	// filebeatConfig is the Filebeat configuration to collect the logs of the local Kibana instance
+	filebeatConfig = settings.MustParseConfig([]byte(`filebeat.modules:
+- module: kibana
+  log:
+    enabled: true
+    var.paths:
+    - /usr/share/kibana/logs/*.json
+processors:
+- add_cloud_metadata: {}
+- add_host_metadata: {}
+`))
+)
+
+// monitoringConfig returns the Kibana settings required to collect its monitoring data with Metricbeat and its logs
+// with Filebeat, according to the monitoring spec.
+func monitoringConfig(kb kbv1.Kibana) *settings.CanonicalConfig {
+	cfg := settings.NewCanonicalConfig()
+	if monitoring.IsMetricsDefined(&kb) {
+		// the legacy internal collection is disabled in favour of Metricbeat
+		_ = cfg.MergeWith(settings.MustCanonicalConfig(map[string]interface{}{
+			XpackMonitoringKibanaCollectionEnabled: false,
+		}))
+	}
+	if monitoring.IsLogsDefined(&kb) {
+		// write the logs as JSON to disk in addition to the default console appender
+		_ = cfg.MergeWith(settings.MustCanonicalConfig(map[string]interface{}{
+			"logging.appenders.file": map[string]interface{}{
+				"type":     "file",
+				"fileName": filepath.Join(LogsVolumeMountPath, "kibana.json"),
+				"layout": map[string]interface{}{
+					"type": "json",
+				},
+			},
+			"logging.root.appenders": []string{"default", "file"},
+		}))
+	}
+	return cfg
+}
+
+// metricbeatConfig builds the Metricbeat configuration to collect the metrics of the local Kibana instance.
+// Metricbeat uses the credentials of the Kibana user, or the service account token of Kibana, in the associated
+// Elasticsearch cluster.
+func metricbeatConfig(client k8s.Client, kb kbv1.Kibana) (*settings.CanonicalConfig, error) {
+	module := map[string]interface{}{
+		"module":        "kibana",
+		"metricsets":    []string{"stats"},
+		"period":        "10s",
+		"xpack.enabled": true,
+		"hosts":         []string{fmt.Sprintf("%s://localhost:%d", kb.Spec.HTTP.Protocol(), HTTPPort)},
+	}
+	if kb.AssociationConf().IsConfigured() {
+		credentials, err := association.ElasticsearchCredentials(client, &kb)
+		if err != nil {
+			return nil, err
+		}
+		if credentials.ServiceAccountToken != "" {
+			module["headers"] = map[string]interface{}{
+				"Authorization": "Bearer " + credentials.ServiceAccountToken,
+			}
+		} else {
+			module["username"] = credentials.Username
+			module["password"] = credentials.Password
+		}
+	}
+	if kb.Spec.HTTP.TLS.Enabled() {
+		module["ssl.certificate_authorities"] = []string{
+			filepath.Join(certificates.HTTPCertificatesSecretVolumeMountPath, certificates.CAFileName),
+		}
+		// the certificate is not issued for localhost, only verify that it is signed by the CA
+		module["ssl.verification_mode"] = "certificate"
+	}
+
+	return settings.NewCanonicalConfigFrom(map[string]interface{}{
+		"metricbeat.modules": []interface{}{module},
+		"processors": []interface{}{
+			map[string]interface{}{"add_cloud_metadata": map[string]interface{}{}},
+			map[string]interface{}{"add_host_metadata": map[string]interface{}{}},
+		},
+	})
+}
+
+// metricbeat returns the Metricbeat sidecar to collect the metrics of Kibana.
+func metricbeat(client k8s.Client, kb kbv1.Kibana) (stackmon.BeatSidecar, error) {
+	config, err := metricbeatConfig(client, kb)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err
+	}
+	var volumes []volume.VolumeLike
+	if kb.Spec.HTTP.TLS.Enabled() {
+		volumes = append(volumes, certificates.HTTPCertSecretVolume(Namer, kb.Name))
+	}
+	return stackmon.NewMetricBeatSidecar(client, &kb, Namer, kb.Spec.Version, config, volumes...)
+}
+
+// filebeat returns the Filebeat sidecar to collect the logs of Kibana.
+func filebeat(client k8s.Client, kb kbv1.Kibana) (stackmon.BeatSidecar, error) {
+	return stackmon.NewFileBeatSidecar(client, &kb, Namer, kb.Spec.Version, filebeatConfig,
+		logsVolume,
+		volume.NewEmptyDirVolume(filebeatDataVolumeName, filebeatDataMountPath),
+	)
+}
+
+// monitoringSidecars returns the Beat sidecars to deploy in the Kibana Pods according to the monitoring spec.
+func monitoringSidecars(client k8s.Client, kb kbv1.Kibana) ([]stackmon.BeatSidecar, error) {
+	var sidecars []stackmon.BeatSidecar
+	if monitoring.IsMetricsDefined(&kb) {
+		b, err := metricbeat(client, kb)
+		if err != nil {
+			return nil, err
+		}
+		sidecars = append(sidecars, b)
+	}
+	if monitoring.IsLogsDefined(&kb) {
+		b, err := filebeat(client, kb)
+		if err != nil {
+			return nil, err
+		}
+		sidecars = append(sidecars, b)
+	}
+	return sidecars, nil
+}
+
+// reconcileMonitoringConfigSecrets reconciles the secrets holding the configuration of the Beat sidecars.
+func reconcileMonitoringConfigSecrets(client k8s.Client, kb kbv1.Kibana) error {
+	sidecars, err := monitoringSidecars(client, kb)
+	if err != nil {
+		return err
+	}
+	return stackmon.ReconcileConfigSecrets(client, &kb, NewLabels(kb.Name), sidecars...)
+}
+
+// withMonitoring updates the Kibana Pod template builder to deploy Metricbeat and Filebeat in sidecar containers
+// and shares the logs volume between Kibana and Filebeat.
+func withMonitoring(client k8s.Client, builder *defaults.PodTemplateBuilder, kb kbv1.Kibana) (*defaults.PodTemplateBuilder, error) {
+	if !monitoring.IsDefined(&kb) {
+		return builder, nil
+	}
+
+	if monitoring.IsLogsDefined(&kb) {
+		builder.WithVolumes(logsVolume.Volume()).WithVolumeMounts(logsVolume.VolumeMount())
+	}
+
+	sidecars, err := monitoringSidecars(client, kb)
+	if err != nil {
+		return nil, err
+	}
+	return stackmon.WithMonitoring(builder, sidecars...), nil
+}
@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package kibana
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func sampleMonitoredKibana(metrics, logs bool) kbv1.Kibana {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "ns"},
+		Spec:       kbv1.KibanaSpec{Version: "7.14.0"},
+	}
+	if metrics {
+		kb.Spec.Monitoring.Metrics.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring"}}
+	}
+	if logs {
+		kb.Spec.Monitoring.Logs.ElasticsearchRefs = []commonv1.ObjectSelector{{Name: "monitoring"}}
+	}
+	return kb
+}
+
+func Test_monitoringConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		kb             kbv1.Kibana
+		wantCollection bool
+		wantFileLogs   bool
+	}{
+		{
+			name: "no monitoring",
+			kb:   sampleMonitoredKibana(false, false),
+		},
+		{
+			name:           "metrics only",
+			kb:             sampleMonitoredKibana(true, false),
+			wantCollection: true,
+		},
+		{
+			name:         "logs only",
+			kb:           sampleMonitoredKibana(false, true),
+			wantFileLogs: true,
+		},
+		{
+			name:           "metrics and logs",
+			kb:             sampleMonitoredKibana(true, true),
+			wantCollection: true,
+			wantFileLogs:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg map[string]interface{}
+			require.NoError(t, monitoringConfig(tt.kb).Unpack(&cfg))
+			_, hasCollection := cfg["monitoring"]
+			assert.Equal(t, tt.wantCollection, hasCollection)
+			_, hasLogging := cfg["logging"]
+			assert.Equal(t, tt.wantFileLogs, hasLogging)
+		})
+	}
+}
+
+func TestNewPodTemplateSpec_withMonitoring(t *testing.T) {
+	kb := sampleMonitoredKibana(true, true)
+	client := k8s.NewFakeClient()
+
+	pod, err := NewPodTemplateSpec(client, kb, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, pod.Spec.Containers, 3)
+	assert.Equal(t, kbv1.KibanaContainerName, pod.Spec.Containers[0].Name)
+	assert.Equal(t, "metricbeat", pod.Spec.Containers[1].Name)
+	assert.Equal(t, "filebeat", pod.Spec.Containers[2].Name)
+	assert.Contains(t, pod.Annotations, "monitoring.k8s.elastic.co/metricbeat-config-hash")
+	assert.Contains(t, pod.Annotations, "monitoring.k8s.elastic.co/filebeat-config-hash")
+	// the logs volume is shared between Kibana and Filebeat
+	assert.Contains(t, pod.Spec.Containers[0].VolumeMounts, logsVolume.VolumeMount())
+	assert.Contains(t, pod.Spec.Containers[2].VolumeMounts, logsVolume.VolumeMount())
+
+	require.NoError(t, reconcileMonitoringConfigSecrets(client, kb))
+	for _, name := range []string{"sample-kb-monitoring-metricbeat-config", "sample-kb-monitoring-filebeat-config"} {
+		var secret corev1.Secret
+		require.NoError(t, client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: name}, &secret))
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package maps
+
+import (
+	"path/filepath"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	netutil "github.com/elastic/cloud-on-k8s/pkg/utils/net"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ESCertsPath     = "/mnt/elastic-internal/es-certs"
+	ConfigMountPath = "/usr/src/app/server/config/elastic-maps-server.yml"
+	ConfigFilename  = "elastic-maps-server.yml"
+)
+
+func configSecretVolume(ems emsv1alpha1.ElasticMapsServer) volume.SecretVolume {
+	return volume.NewSecretVolume(Config(ems.Name), "config", ConfigMountPath, ConfigFilename, 0444)
+}
+
+// reconcileConfig reconciles the configuration of Elastic Maps Server: it generates the right configuration and
+// stores it in a secret that is kept up to date.
+func reconcileConfig(driver driver.Interface, ems emsv1alpha1.ElasticMapsServer, ipFamily corev1.IPFamily) (corev1.Secret, error) {
+	cfg, err := newConfig(driver.K8sClient(), ems, ipFamily)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	cfgBytes, err := cfg.Render()
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	// Reconcile the configuration in a secret
+	expectedConfigSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ems.Namespace,
+			Name:      Config(ems.Name),
+			Labels:    common.AddCredentialsLabel(Labels(ems.Name)),
+		},
+		Data: map[string][]byte{
+			ConfigFilename: cfgBytes,
+		},
+	}
+
+	return reconciler.ReconcileSecret(driver.K8sClient(), expectedConfigSecret, &ems)
+}
+
+// newConfig builds a single merged config from:
+// - ECK-managed default configuration
+// - association configuration (eg. ES credentials)
+// - TLS settings configuration
+// - user-provided plaintext configuration
+// In case of duplicate settings, the last one takes precedence.
+func newConfig(c k8s.Client, ems emsv1alpha1.ElasticMapsServer, ipFamily corev1.IPFamily) (*settings.CanonicalConfig, error) {
+	cfg := defaultConfig(ipFamily)
+
+	associationCfg, err := associationConfig(c, ems)
+	if err != nil {
+		return nil, err
+	}
+
+	specConfig := ems.Spec.Config
+	if specConfig == nil {
+		specConfig = &commonv1.Config{}
+	}
+	userProvidedCfg, err := settings.NewCanonicalConfigFrom(specConfig.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// merge with user settings last so they take precedence
+	err = cfg.MergeWith(tlsConfig(ems), associationCfg, userProvidedCfg)
+	return cfg, err
+}
+
+func defaultConfig(ipFamily corev1.IPFamily) *settings.CanonicalConfig {
+	return settings.MustCanonicalConfig(map[string]interface{}{
+		"host": netutil.InAddrAnyFor(ipFamily).String(),
+	})
+}
+
+func associationConfig(c k8s.Client, ems emsv1alpha1.ElasticMapsServer) (*settings.CanonicalConfig, error) {
+	if !ems.AssociationConf().IsConfigured() {
+		return settings.NewCanonicalConfig(), nil
+	}
+
+	username, password, err := association.ElasticsearchAuthSettings(c, &ems)
+	if err != nil {
+		return nil, err
+	}
+	cfg := settings.MustCanonicalConfig(map[string]string{
+		"elasticsearch.host":     ems.AssociationConf().URL,
+		"elasticsearch.username": username,
+		"elasticsearch.password": password,
+	})
+
+	if ems.AssociationConf().CAIsConfigured() {
+		if err := cfg.MergeWith(settings.MustCanonicalConfig(map[string]interface{}{
+			"elasticsearch.ssl.verificationMode":       "certificate",
+			"elasticsearch.ssl.certificateAuthorities": filepath.Join(ESCertsPath, certificates.CertFileName),
+		})); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func tlsConfig(ems emsv1alpha1.ElasticMapsServer) *settings.CanonicalConfig {
+	if !ems.Spec.HTTP.TLS.Enabled() {
+		return settings.NewCanonicalConfig()
+	}
+	certsDir := certificates.HTTPCertSecretVolume(EMSNamer, ems.Name).VolumeMount().MountPath
+	return settings.MustCanonicalConfig(map[string]interface{}{
+		"ssl.enabled":     true,
+		"ssl.certificate": filepath.Join(certsDir, certificates.CertFileName),
+		"ssl.key":         filepath.Join(certsDir, certificates.KeyFileName),
+	})
+}
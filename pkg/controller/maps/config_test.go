@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package maps
+
+import (
+	"testing"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func mkMapsServer(mutate func(ems *emsv1alpha1.ElasticMapsServer)) emsv1alpha1.ElasticMapsServer {
+	ems := emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "sample",
+		},
+		Spec: emsv1alpha1.MapsSpec{
+			Version: "7.12.0",
+		},
+	}
+	if mutate != nil {
+		mutate(&ems)
+	}
+	return ems
+}
+
+func Test_newConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		ems         emsv1alpha1.ElasticMapsServer
+		runtimeObjs []runtime.Object
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "default config with TLS",
+			ems:  mkMapsServer(nil),
+			want: `host: 0.0.0.0
+ssl:
+  certificate: /mnt/elastic-internal/http-certs/tls.crt
+  enabled: true
+  key: /mnt/elastic-internal/http-certs/tls.key
+`,
+		},
+		{
+			name: "TLS disabled",
+			ems: mkMapsServer(func(ems *emsv1alpha1.ElasticMapsServer) {
+				ems.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+			}),
+			want: `host: 0.0.0.0
+`,
+		},
+		{
+			name: "user-provided config takes precedence",
+			ems: mkMapsServer(func(ems *emsv1alpha1.ElasticMapsServer) {
+				ems.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+				ems.Spec.Config = &commonv1.Config{Data: map[string]interface{}{
+					"host":    "127.0.0.1",
+					"logging": map[string]interface{}{"level": "debug"},
+				}}
+			}),
+			want: `host: 127.0.0.1
+logging:
+  level: debug
+`,
+		},
+		{
+			name: "with Elasticsearch association",
+			ems: mkMapsServer(func(ems *emsv1alpha1.ElasticMapsServer) {
+				ems.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+				ems.SetAssociationConf(&commonv1.AssociationConf{
+					AuthSecretName: "sample-maps-user",
+					AuthSecretKey:  "ns-sample-maps-user",
+					CACertProvided: true,
+					CASecretName:   "sample-maps-es-ca",
+					URL:            "https://elasticsearch-sample-es-http.default.svc:9200",
+				})
+			}),
+			runtimeObjs: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "sample-maps-user", Namespace: "ns"},
+					Data:       map[string][]byte{"ns-sample-maps-user": []byte("mypassword")},
+				},
+			},
+			want: `elasticsearch:
+  host: https://elasticsearch-sample-es-http.default.svc:9200
+  password: mypassword
+  ssl:
+    certificateAuthorities: /mnt/elastic-internal/es-certs/tls.crt
+    verificationMode: certificate
+  username: ns-sample-maps-user
+host: 0.0.0.0
+`,
+		},
+		{
+			name: "Elasticsearch association with missing credentials",
+			ems: mkMapsServer(func(ems *emsv1alpha1.ElasticMapsServer) {
+				ems.SetAssociationConf(&commonv1.AssociationConf{
+					AuthSecretName: "sample-maps-user",
+					AuthSecretKey:  "ns-sample-maps-user",
+					URL:            "https://elasticsearch-sample-es-http.default.svc:9200",
+				})
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newConfig(k8s.NewFakeClient(tt.runtimeObjs...), tt.ems, corev1.IPv4Protocol)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			gotBytes, err := got.Render()
+			require.NoError(t, err)
+			wantCfg, err := settings.ParseConfig([]byte(tt.want))
+			require.NoError(t, err)
+			wantBytes, err := wantCfg.Render()
+			require.NoError(t, err)
+			require.Equal(t, string(wantBytes), string(gotBytes))
+		})
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package maps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/elastic/cloud-on-k8s/pkg/about"
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+)
+
+func TestReconcileMapsServer_Reconcile_Unmanaged(t *testing.T) {
+	// unmanaged resource, should do nothing
+	sample := emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sample", Annotations: map[string]string{
+			common.ManagedAnnotation: "false",
+		}},
+		Spec: emsv1alpha1.MapsSpec{Version: "7.15.0"},
+	}
+	r := &ReconcileMapsServer{
+		Client: k8s.NewFakeClient(&sample),
+	}
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "sample", Namespace: "ns"}})
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, result)
+}
+
+func TestReconcileMapsServer_Reconcile_NotFound(t *testing.T) {
+	// resource not found, should clear watches
+	r := &ReconcileMapsServer{
+		Client:         k8s.NewFakeClient(),
+		dynamicWatches: watches.NewDynamicWatches(),
+	}
+	// simulate a custom http tls secret
+	nsn := types.NamespacedName{Name: "sample", Namespace: "ns"}
+	require.NoError(t, watches.WatchUserProvidedSecrets(nsn, r.dynamicWatches, certificates.CertificateWatchKey(EMSNamer, nsn.Name), []string{"user-tls-secret"}))
+	require.NotEmpty(t, r.dynamicWatches.Secrets.Registrations())
+
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: nsn})
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, result)
+
+	// watch should have been cleared out
+	require.Empty(t, r.dynamicWatches.Secrets.Registrations())
+}
+
+func TestReconcileMapsServer_Reconcile_AssociationNotConfigured(t *testing.T) {
+	// an Elasticsearch ref is specified, but its configuration is not set: should do nothing
+	sample := emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sample"},
+		Spec: emsv1alpha1.MapsSpec{
+			Version:          "7.15.0",
+			ElasticsearchRef: commonv1.ObjectSelector{Namespace: "ns", Name: "es"},
+		},
+	}
+	fakeRecorder := record.NewFakeRecorder(10)
+	r := &ReconcileMapsServer{
+		Client:         k8s.NewFakeClient(&sample),
+		dynamicWatches: watches.NewDynamicWatches(),
+		recorder:       fakeRecorder,
+	}
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "sample", Namespace: "ns"}})
+	require.NoError(t, err)
+	// should just requeue until the resource is updated
+	require.Equal(t, reconcile.Result{}, res)
+	// an event should be emitted
+	e := <-fakeRecorder.Events
+	require.Equal(t, "Warning AssociationError Association backend for elasticsearch is not configured", e)
+}
+
+func TestReconcileMapsServer_Reconcile_InvalidResource(t *testing.T) {
+	// Elastic Maps Server is not available as a standalone image before 7.11.0
+	sample := emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sample"},
+		Spec:       emsv1alpha1.MapsSpec{Version: "7.10.0"},
+	}
+	fakeRecorder := record.NewFakeRecorder(10)
+	r := &ReconcileMapsServer{
+		Client:   k8s.NewFakeClient(&sample),
+		recorder: fakeRecorder,
+	}
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "sample", Namespace: "ns"}})
+	// should return an error
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "spec.version: Invalid value")
+	require.Equal(t, reconcile.Result{}, res)
+	// an event should be emitted
+	e := <-fakeRecorder.Events
+	require.Contains(t, e, "spec.version: Invalid value")
+}
+
+func TestReconcileMapsServer_Reconcile_Create_Update_Resources(t *testing.T) {
+	sample := emsv1alpha1.ElasticMapsServer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sample", Generation: 2},
+		Spec: emsv1alpha1.MapsSpec{
+			Version: "7.15.0",
+			Count:   3,
+		},
+	}
+	// a Pod already running the expected version
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "sample-ems-pod",
+		Labels:    map[string]string{NameLabelName: sample.Name, VersionLabelName: "7.15.0"},
+	}}
+	r := &ReconcileMapsServer{
+		Client:         k8s.NewFakeClient(&sample, &pod),
+		dynamicWatches: watches.NewDynamicWatches(),
+		recorder:       record.NewFakeRecorder(10),
+		Parameters:     operator.Parameters{OperatorInfo: about.OperatorInfo{BuildInfo: about.BuildInfo{Version: "1.0.0"}}},
+	}
+
+	checkResources := func() {
+		// should create a service
+		var service corev1.Service
+		err := r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: HTTPService(sample.Name)}, &service)
+		require.NoError(t, err)
+		require.Equal(t, int32(8080), service.Spec.Ports[0].Port)
+		require.Equal(t, "https", service.Spec.Ports[0].Name)
+
+		// should create internal ca, internal http certs secret, public http certs secret
+		for _, name := range []string{"sample-ems-http-ca-internal", "sample-ems-http-certs-internal", "sample-ems-http-certs-public"} {
+			var secret corev1.Secret
+			err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: name}, &secret)
+			require.NoError(t, err)
+			require.NotEmpty(t, secret.Data)
+		}
+
+		// should create a secret for the configuration
+		var config corev1.Secret
+		err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: Config(sample.Name)}, &config)
+		require.NoError(t, err)
+		require.Contains(t, string(config.Data[ConfigFilename]), "host:")
+
+		// should create a 3-replicas deployment
+		var dep appsv1.Deployment
+		err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: Deployment(sample.Name)}, &dep)
+		require.NoError(t, err)
+		require.Equal(t, int32(3), *dep.Spec.Replicas)
+		// with the config hash and version labels set
+		require.NotEmpty(t, dep.Spec.Template.Labels[ConfigHashLabelName])
+		require.Equal(t, "7.15.0", dep.Spec.Template.Labels[VersionLabelName])
+
+		// should update the status for the current generation
+		var ems emsv1alpha1.ElasticMapsServer
+		err = r.Client.Get(context.Background(), k8s.ExtractNamespacedName(&sample), &ems)
+		require.NoError(t, err)
+		require.Equal(t, HTTPService(sample.Name), ems.Status.ExternalService)
+		require.Equal(t, "7.15.0", ems.Status.Version)
+		require.Equal(t, int64(2), ems.Status.ObservedGeneration)
+		// ignore the transition time of the conditions set during the test
+		for i := range ems.Status.Conditions {
+			ems.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+		}
+		require.Equal(t, []metav1.Condition{
+			{
+				Type:               commonv1.ReconciliationComplete,
+				Status:             metav1.ConditionTrue,
+				Reason:             commonv1.ReconciledReason,
+				Message:            "Reconciliation completed",
+				ObservedGeneration: 2,
+			},
+			{
+				Type:               commonv1.RunningDesiredVersion,
+				Status:             metav1.ConditionTrue,
+				Reason:             commonv1.DesiredVersionRunningReason,
+				Message:            "Running version 7.15.0",
+				ObservedGeneration: 2,
+			},
+		}, ems.Status.Conditions)
+	}
+
+	// first call
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "sample", Namespace: "ns"}})
+	require.NoError(t, err)
+	// should requeue for cert expiration
+	require.NotZero(t, res.RequeueAfter)
+	// all resources should be created
+	checkResources()
+
+	// call-again: no-op
+	res, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "sample", Namespace: "ns"}})
+	require.NoError(t, err)
+	require.NotZero(t, res.RequeueAfter)
+	// all resources should be the same
+	checkResources()
+
+	// modify the deployment: 2 replicas instead of 3
+	var dep appsv1.Deployment
+	err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: Deployment(sample.Name)}, &dep)
+	require.NoError(t, err)
+	replicas := int32(2)
+	dep.Spec.Replicas = &replicas
+	err = r.Client.Update(context.Background(), &dep)
+	require.NoError(t, err)
+	// delete the http service
+	var service corev1.Service
+	err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: HTTPService(sample.Name)}, &service)
+	require.NoError(t, err)
+	err = r.Client.Delete(context.Background(), &service)
+	require.NoError(t, err)
+	// delete the configuration secret entry
+	var config corev1.Secret
+	err = r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: Config(sample.Name)}, &config)
+	require.NoError(t, err)
+	config.Data = nil
+	err = r.Client.Update(context.Background(), &config)
+	require.NoError(t, err)
+
+	// call again: all resources should be updated to revert our manual changes above
+	res, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "sample", Namespace: "ns"}})
+	require.NoError(t, err)
+	require.NotZero(t, res.RequeueAfter)
+	// all resources should be the same
+	checkResources()
+
+	// resource should be annotated with controller version
+	var updated emsv1alpha1.ElasticMapsServer
+	err = r.Client.Get(context.Background(), k8s.ExtractNamespacedName(&sample), &updated)
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", updated.Annotations[annotation.ControllerVersionAnnotation])
+}
+
+type fakeClientStatusCall struct {
+	updateCalled bool
+	k8s.Client
+}
+
+func (f *fakeClientStatusCall) Status() client.StatusWriter {
+	f.updateCalled = true // Status() has been requested for update
+	return f.Client.Status()
+}
+
+// reconciledConditions returns the conditions expected once the given generation of an Elastic Maps Server with no
+// known version is reconciled.
+func reconciledConditions(generation int64) []metav1.Condition {
+	return []metav1.Condition{
+		{
+			Type:               commonv1.ReconciliationComplete,
+			Status:             metav1.ConditionTrue,
+			Reason:             commonv1.ReconciledReason,
+			Message:            "Reconciliation completed",
+			ObservedGeneration: generation,
+		},
+		{
+			Type:               commonv1.RunningDesiredVersion,
+			Status:             metav1.ConditionUnknown,
+			Reason:             commonv1.VersionUnknownReason,
+			Message:            "Running version is not known yet",
+			ObservedGeneration: generation,
+		},
+	}
+}
+
+func TestReconcileMapsServer_updateStatus(t *testing.T) {
+	availableDeployment := appsv1.Deployment{Status: appsv1.DeploymentStatus{
+		AvailableReplicas: 3,
+		Conditions: []appsv1.DeploymentCondition{
+			{
+				Type:   appsv1.DeploymentAvailable,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	}}
+	unavailableDeployment := appsv1.Deployment{Status: appsv1.DeploymentStatus{
+		AvailableReplicas: 3,
+		Conditions: []appsv1.DeploymentCondition{
+			{
+				Type:   appsv1.DeploymentAvailable,
+				Status: corev1.ConditionFalse,
+			},
+		},
+	}}
+	greenStatus := func(generation int64) emsv1alpha1.MapsStatus {
+		return emsv1alpha1.MapsStatus{
+			DeploymentStatus: commonv1.DeploymentStatus{
+				AvailableNodes:     3,
+				Health:             "green",
+				Conditions:         reconciledConditions(generation),
+				ObservedGeneration: generation,
+			},
+			ExternalService: "http-service",
+		}
+	}
+
+	tests := []struct {
+		name                   string
+		ems                    emsv1alpha1.ElasticMapsServer
+		deploy                 appsv1.Deployment
+		wantStatus             emsv1alpha1.MapsStatus
+		wantEvent              bool
+		wantStatusUpdateCalled bool
+	}{
+		{
+			name:                   "happy path",
+			ems:                    emsv1alpha1.ElasticMapsServer{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ems"}},
+			deploy:                 availableDeployment,
+			wantStatus:             greenStatus(0),
+			wantStatusUpdateCalled: true,
+		},
+		{
+			name: "preserve existing association status",
+			ems: emsv1alpha1.ElasticMapsServer{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ems"},
+				Status: emsv1alpha1.MapsStatus{AssociationStatus: commonv1.AssociationEstablished}},
+			deploy: availableDeployment,
+			wantStatus: func() emsv1alpha1.MapsStatus {
+				status := greenStatus(0)
+				status.AssociationStatus = commonv1.AssociationEstablished
+				return status
+			}(),
+			wantStatusUpdateCalled: true,
+		},
+		{
+			name: "update the observed generation",
+			ems: emsv1alpha1.ElasticMapsServer{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ems", Generation: 2},
+				Status: greenStatus(1)},
+			deploy:                 availableDeployment,
+			wantStatus:             greenStatus(2),
+			wantStatusUpdateCalled: true,
+		},
+		{
+			name: "don't do a status update if not necessary",
+			ems: emsv1alpha1.ElasticMapsServer{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ems"},
+				Status: greenStatus(0)},
+			deploy:                 availableDeployment,
+			wantStatus:             greenStatus(0),
+			wantStatusUpdateCalled: false,
+		},
+		{
+			name: "emit an event when health goes from green to red",
+			ems: emsv1alpha1.ElasticMapsServer{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ems"},
+				Status: greenStatus(0)},
+			deploy: unavailableDeployment,
+			wantStatus: func() emsv1alpha1.MapsStatus {
+				status := greenStatus(0)
+				status.Health = "red"
+				return status
+			}(),
+			wantEvent:              true,
+			wantStatusUpdateCalled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &fakeClientStatusCall{Client: k8s.NewFakeClient(&tt.ems)}
+			fakeRecorder := record.NewFakeRecorder(10)
+			r := &ReconcileMapsServer{
+				Client:   c,
+				recorder: fakeRecorder,
+			}
+			err := r.updateStatus(tt.ems, tt.deploy, "http-service", reconciler.NewResult(context.Background()))
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantStatusUpdateCalled, c.updateCalled)
+
+			var updatedEms emsv1alpha1.ElasticMapsServer
+			err = c.Get(context.Background(), k8s.ExtractNamespacedName(&tt.ems), &updatedEms)
+			require.NoError(t, err)
+			// ignore the transition time of the conditions set during the test
+			for i := range updatedEms.Status.Conditions {
+				updatedEms.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+			}
+			require.Equal(t, tt.wantStatus, updatedEms.Status)
+
+			if tt.wantEvent {
+				<-fakeRecorder.Events
+			} else {
+				// no event expected
+				select {
+				case e := <-fakeRecorder.Events:
+					require.Fail(t, "no event expected but got one", "event", e)
+				default:
+					// ok
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package maps
+
+import (
+	"context"
+
+	"go.elastic.co/apm"
+	appsv1 "k8s.io/api/apps/v1"
+
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+)
+
+func (r *ReconcileMapsServer) reconcileDeployment(
+	ctx context.Context,
+	ems emsv1alpha1.ElasticMapsServer,
+	configHash string,
+) (appsv1.Deployment, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_deployment", tracing.SpanTypeApp)
+	defer span.End()
+
+	deploy := deployment.New(deploymentParams(ems, configHash))
+	return deployment.Reconcile(r.K8sClient(), deploy, &ems)
+}
+
+func deploymentParams(ems emsv1alpha1.ElasticMapsServer, configHash string) deployment.Params {
+	podSpec := newPodSpec(ems, configHash)
+
+	deploymentLabels := Labels(ems.Name)
+
+	podLabels := maps.Merge(Labels(ems.Name), VersionLabels(ems))
+	// merge with user-provided labels
+	podSpec.Labels = maps.MergePreservingExistingKeys(podSpec.Labels, podLabels)
+
+	return deployment.Params{
+		Name:            Deployment(ems.Name),
+		Namespace:       ems.Namespace,
+		Replicas:        ems.Spec.Count,
+		Selector:        deploymentLabels,
+		Labels:          deploymentLabels,
+		PodTemplateSpec: podSpec,
+		Strategy:        appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType},
+	}
+}
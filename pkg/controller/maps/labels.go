@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package maps
+
+import (
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common"
+)
+
+const (
+	// Type represents the Elastic Maps Server type.
+	Type = "maps"
+	// NameLabelName used to represent an Elastic Maps Server in k8s resources.
+	NameLabelName = "maps.k8s.elastic.co/name"
+	// NamespaceLabelName used to represent an Elastic Maps Server in k8s resources.
+	NamespaceLabelName = "maps.k8s.elastic.co/namespace"
+	// VersionLabelName is a label used to track the version of an Elastic Maps Server Pod.
+	VersionLabelName = "maps.k8s.elastic.co/version"
+)
+
+// Labels returns labels that identify the given Elastic Maps Server resource.
+func Labels(emsName string) map[string]string {
+	return map[string]string{
+		NameLabelName:        emsName,
+		common.TypeLabelName: Type,
+	}
+}
+
+func VersionLabels(ems emsv1alpha1.ElasticMapsServer) map[string]string {
+	return map[string]string{
+		VersionLabelName: ems.Spec.Version,
+	}
+}
@@ -120,6 +120,7 @@ func deleteAllRemoteCa(ctx context.Context, r *ReconcileRemoteCa, es types.Names
 			results.WithError(err)
 		}
 	}
+	removeExternalWatches(r, es)
 	return results.Aggregate()
 }
 
@@ -135,11 +136,13 @@ func doReconcile(
 		return reconcile.Result{}, err
 	}
 
+	externalRemoteClusters := getExternalRemoteClusters(localEs)
+
 	enabled, err := r.licenseChecker.EnterpriseFeaturesEnabled()
 	if err != nil {
 		return defaultRequeue, err
 	}
-	if !enabled && len(expectedRemoteClusters) > 0 {
+	if !enabled && len(expectedRemoteClusters)+len(externalRemoteClusters) > 0 {
 		log.V(1).Info(
 			"Remote cluster controller is an enterprise feature. Enterprise features are disabled",
 			"namespace", localEs.Namespace, "es_name", localEs.Name,
@@ -192,6 +195,9 @@ func doReconcile(
 		)
 		results.WithError(deleteCertificateAuthorities(ctx, r, localClusterKey, toDelete))
 	}
+
+	// Copy the CA of the remote clusters not managed by ECK
+	results.WithResults(reconcileExternalCertificateAuthorities(ctx, r, localEs, externalRemoteClusters))
 	return results.WithResult(association.RequeueRbacCheck(r.accessReviewer)).Aggregate()
 }
 
@@ -267,6 +273,10 @@ func remoteClustersInvolvedWith(
 		return nil, err
 	}
 	for _, remoteCA := range remoteCAList.Items {
+		if _, isExternal := remoteCA.Labels[ExternalRemoteClusterLabelName]; isExternal {
+			// CA of a remote cluster not managed by ECK, see reconcileExternalCertificateAuthorities
+			continue
+		}
 		remoteNs := remoteCA.Labels[RemoteClusterNamespaceLabelName]
 		remoteEs := remoteCA.Labels[RemoteClusterNameLabelName]
 		currentRemoteClusters[types.NamespacedName{
@@ -29,8 +29,9 @@ import (
 )
 
 type clusterBuilder struct {
-	name, namespace string
-	remoteClusters  []commonv1.ObjectSelector
+	name, namespace        string
+	remoteClusters         []commonv1.ObjectSelector
+	externalRemoteClusters []esv1.RemoteCluster
 }
 
 func newClusteBuilder(namespace, name string) *clusterBuilder {
@@ -48,6 +49,15 @@ func (cb *clusterBuilder) withRemoteCluster(namespace, name string) *clusterBuil
 	return cb
 }
 
+func (cb *clusterBuilder) withExternalRemoteCluster(name, caSecretName string) *clusterBuilder {
+	cb.externalRemoteClusters = append(cb.externalRemoteClusters, esv1.RemoteCluster{
+		Name:                   name,
+		Seeds:                  []string{name + ".example.com:9300"},
+		CertificateAuthorities: commonv1.SecretRef{SecretName: caSecretName},
+	})
+	return cb
+}
+
 func (cb *clusterBuilder) build() *esv1.Elasticsearch {
 	remoteClusters := make([]esv1.RemoteCluster, len(cb.remoteClusters))
 	i := 0
@@ -59,6 +69,7 @@ func (cb *clusterBuilder) build() *esv1.Elasticsearch {
 			}}
 		i++
 	}
+	remoteClusters = append(remoteClusters, cb.externalRemoteClusters...)
 
 	return &esv1.Elasticsearch{
 		ObjectMeta: v1.ObjectMeta{
@@ -134,6 +145,37 @@ func remoteCa(localNamespace, localName, remoteNamespace, remoteName string) *co
 	}
 }
 
+// userCa builds a Secret provided by the user with the CA of a remote cluster not managed by ECK
+func userCa(namespace, name string, ca []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Data: map[string][]byte{
+			certificates.CAFileName: ca,
+		},
+	}
+}
+
+// externalRemoteCa builds an expected copy of the CA of a remote cluster not managed by ECK
+func externalRemoteCa(localNamespace, localName, remoteClusterName string, ca []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: localNamespace,
+			Name:      externalRemoteCASecretName(localName, remoteClusterName),
+			Labels: map[string]string{
+				"common.k8s.elastic.co/type":                                "remote-ca",
+				"elasticsearch.k8s.elastic.co/cluster-name":                 localName,
+				"elasticsearch.k8s.elastic.co/external-remote-cluster-name": remoteClusterName,
+			},
+		},
+		Data: map[string][]byte{
+			certificates.CAFileName: ca,
+		},
+	}
+}
+
 func withDataCert(caSecret *corev1.Secret, newCa []byte) *corev1.Secret {
 	caSecret.Data[certificates.CAFileName] = newCa
 	return caSecret
@@ -394,6 +436,101 @@ func TestReconcileRemoteCa_Reconcile(t *testing.T) {
 			want:    reconcile.Result{},
 			wantErr: false,
 		},
+		{
+			name: "Remote cluster not managed by ECK, CA provided by the user is copied",
+			fields: fields{
+				clusters: []runtime.Object{
+					newClusteBuilder("ns1", "es1").
+						withRemoteCluster("ns2", "es2").
+						withExternalRemoteCluster("on_prem", "on-prem-ca").
+						build(),
+					fakePublicCa("ns1", "es1"),
+					newClusteBuilder("ns2", "es2").build(),
+					fakePublicCa("ns2", "es2"),
+					userCa("ns1", "on-prem-ca", []byte("on-prem")),
+				},
+				accessReviewer: &fakeAccessReviewer{allowed: true},
+				licenseChecker: &fakeLicenseChecker{enterpriseFeaturesEnabled: true},
+			},
+			args: args{
+				request: reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      "es1",
+						Namespace: "ns1",
+					},
+				},
+			},
+			expectedSecrets: []*corev1.Secret{
+				remoteCa("ns1", "es1", "ns2", "es2"),
+				remoteCa("ns2", "es2", "ns1", "es1"),
+				externalRemoteCa("ns1", "es1", "on_prem", []byte("on-prem")),
+			},
+			want:    reconcile.Result{},
+			wantErr: false,
+		},
+		{
+			name: "Remote cluster not managed by ECK, CA provided by the user has been updated",
+			fields: fields{
+				clusters: []runtime.Object{
+					newClusteBuilder("ns1", "es1").withExternalRemoteCluster("on_prem", "on-prem-ca").build(),
+					fakePublicCa("ns1", "es1"),
+					userCa("ns1", "on-prem-ca", []byte("on-prem-rotated")),
+					externalRemoteCa("ns1", "es1", "on_prem", []byte("on-prem")),
+				},
+				accessReviewer: &fakeAccessReviewer{allowed: true},
+				licenseChecker: &fakeLicenseChecker{enterpriseFeaturesEnabled: true},
+			},
+			args: args{
+				request: reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      "es1",
+						Namespace: "ns1",
+					},
+				},
+			},
+			expectedSecrets: []*corev1.Secret{
+				externalRemoteCa("ns1", "es1", "on_prem", []byte("on-prem-rotated")),
+			},
+			want:    reconcile.Result{},
+			wantErr: false,
+		},
+		{
+			name: "Remote cluster not managed by ECK has been removed, the copy of its CA is deleted",
+			fields: fields{
+				clusters: []runtime.Object{
+					newClusteBuilder("ns1", "es1").withRemoteCluster("ns2", "es2").build(),
+					fakePublicCa("ns1", "es1"),
+					newClusteBuilder("ns2", "es2").build(),
+					fakePublicCa("ns2", "es2"),
+					remoteCa("ns1", "es1", "ns2", "es2"),
+					remoteCa("ns2", "es2", "ns1", "es1"),
+					userCa("ns1", "on-prem-ca", []byte("on-prem")),
+					externalRemoteCa("ns1", "es1", "on_prem", []byte("on-prem")),
+				},
+				accessReviewer: &fakeAccessReviewer{allowed: true},
+				licenseChecker: &fakeLicenseChecker{enterpriseFeaturesEnabled: true},
+			},
+			args: args{
+				request: reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      "es1",
+						Namespace: "ns1",
+					},
+				},
+			},
+			expectedSecrets: []*corev1.Secret{
+				remoteCa("ns1", "es1", "ns2", "es2"),
+				remoteCa("ns2", "es2", "ns1", "es1"),
+			},
+			unexpectedSecrets: []types.NamespacedName{
+				{
+					Namespace: "ns1",
+					Name:      externalRemoteCASecretName("es1", "on_prem"),
+				},
+			},
+			want:    reconcile.Result{},
+			wantErr: false,
+		},
 		{
 			name: "Association is not allowed, existing remote ca are removed",
 			fields: fields{
@@ -0,0 +1,182 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package remoteca
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.elastic.co/apm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/pkg/controller/elasticsearch/certificates/remoteca"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/pkg/utils/maps"
+)
+
+const (
+	// ExternalRemoteClusterLabelName is used to identify a Secret which contains the CA of a remote cluster not managed
+	// by ECK. Its value is the name of the remote cluster in the Elasticsearch spec.
+	ExternalRemoteClusterLabelName = "elasticsearch.k8s.elastic.co/external-remote-cluster-name"
+	// externalRemoteCASecretSuffix is the suffix of the Secrets containing the CA of a remote cluster not managed by ECK.
+	externalRemoteCASecretSuffix = "external-remote-ca"
+
+	EventReasonExternalCaCertNotFound = "ExternalCaCertNotFound"
+)
+
+// getExternalRemoteClusters returns the remote clusters not managed by ECK for which a CA has been provided, by name.
+func getExternalRemoteClusters(es *esv1.Elasticsearch) map[string]esv1.RemoteCluster {
+	externalRemoteClusters := make(map[string]esv1.RemoteCluster)
+	for _, remoteCluster := range es.Spec.RemoteClusters {
+		if !remoteCluster.IsExternal() || remoteCluster.CertificateAuthorities.SecretName == "" {
+			continue
+		}
+		externalRemoteClusters[remoteCluster.Name] = remoteCluster
+	}
+	return externalRemoteClusters
+}
+
+// reconcileExternalCertificateAuthorities copies the CA certificates provided by the user for the remote clusters
+// not managed by ECK, so they can be trusted by the local cluster. Unlike for remote clusters managed by ECK, the
+// trust relationship is not symmetrical: the CA of the local cluster must be trusted by the remote cluster out of band.
+func reconcileExternalCertificateAuthorities(
+	ctx context.Context,
+	r *ReconcileRemoteCa,
+	es *esv1.Elasticsearch,
+	externalRemoteClusters map[string]esv1.RemoteCluster,
+) *reconciler.Results {
+	span, _ := apm.StartSpan(ctx, "reconcile_external_remote_ca", tracing.SpanTypeApp)
+	defer span.End()
+	results := &reconciler.Results{}
+	esKey := k8s.ExtractNamespacedName(es)
+
+	for name, remoteCluster := range externalRemoteClusters {
+		caSecretKey := types.NamespacedName{Namespace: es.Namespace, Name: remoteCluster.CertificateAuthorities.SecretName}
+		// Watch the Secret provided by the user to update the trusted certificates on changes.
+		if err := r.watches.Secrets.AddHandler(watches.NamedWatch{
+			Name:    externalWatchName(esKey, name),
+			Watched: []types.NamespacedName{caSecretKey},
+			Watcher: esKey,
+		}); err != nil {
+			return results.WithError(err)
+		}
+
+		var caSecret corev1.Secret
+		if err := r.Client.Get(context.Background(), caSecretKey, &caSecret); err != nil {
+			if !errors.IsNotFound(err) {
+				return results.WithError(err)
+			}
+			// The Secret is watched, a new reconciliation is triggered when it is created.
+			r.recorder.Event(es, corev1.EventTypeWarning, EventReasonExternalCaCertNotFound, externalCaCertMissingError(caSecretKey))
+			continue
+		}
+		ca := caSecret.Data[certificates.CAFileName]
+		if len(ca) == 0 {
+			r.recorder.Event(es, corev1.EventTypeWarning, EventReasonExternalCaCertNotFound, externalCaCertMissingError(caSecretKey))
+			continue
+		}
+
+		expected := corev1.Secret{
+			ObjectMeta: externalRemoteCAObjectMeta(es, name),
+			Data: map[string][]byte{
+				certificates.CAFileName: ca,
+			},
+		}
+		if _, err := reconciler.ReconcileSecret(r.Client, expected, es); err != nil {
+			return results.WithError(err)
+		}
+	}
+
+	return results.WithError(deleteUnexpectedExternalCertificateAuthorities(r, esKey, externalRemoteClusters))
+}
+
+// deleteUnexpectedExternalCertificateAuthorities deletes the copies of the CA of the remote clusters not managed by ECK
+// which are not expected anymore.
+func deleteUnexpectedExternalCertificateAuthorities(
+	r *ReconcileRemoteCa,
+	es types.NamespacedName,
+	externalRemoteClusters map[string]esv1.RemoteCluster,
+) error {
+	var externalCAList corev1.SecretList
+	if err := r.Client.List(context.Background(),
+		&externalCAList,
+		client.InNamespace(es.Namespace),
+		remoteca.Labels(es.Name),
+	); err != nil {
+		return err
+	}
+	for i := range externalCAList.Items {
+		externalCA := externalCAList.Items[i]
+		name, isExternal := externalCA.Labels[ExternalRemoteClusterLabelName]
+		if !isExternal {
+			// CA of a remote cluster managed by ECK
+			continue
+		}
+		if _, expected := externalRemoteClusters[name]; expected {
+			continue
+		}
+		log.V(1).Info("Deleting external remote CA",
+			"namespace", es.Namespace,
+			"es_name", es.Name,
+			"remote_cluster", name,
+		)
+		if err := r.Client.Delete(context.Background(), &externalCA); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.watches.Secrets.RemoveHandlerForKey(externalWatchName(es, name))
+	}
+	return nil
+}
+
+// removeExternalWatches removes the watches on the CA Secrets of the remote clusters not managed by ECK.
+// The copies of these CA are owned by the Elasticsearch resource and garbage collected along with it.
+func removeExternalWatches(r *ReconcileRemoteCa, es types.NamespacedName) {
+	prefix := externalWatchName(es, "")
+	for _, registration := range r.watches.Secrets.Registrations() {
+		if strings.HasPrefix(registration, prefix) {
+			r.watches.Secrets.RemoveHandlerForKey(registration)
+		}
+	}
+}
+
+func externalWatchName(es types.NamespacedName, remoteClusterName string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", es.Namespace, es.Name, externalRemoteCASecretSuffix, remoteClusterName)
+}
+
+func externalCaCertMissingError(secret types.NamespacedName) string {
+	return fmt.Sprintf("Cannot find CA certificate in Secret %s/%s under the %s key", secret.Namespace, secret.Name, certificates.CAFileName)
+}
+
+func externalRemoteCAObjectMeta(owner *esv1.Elasticsearch, remoteClusterName string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      externalRemoteCASecretName(owner.Name, remoteClusterName),
+		Namespace: owner.Namespace,
+		Labels: maps.Merge(
+			map[string]string{
+				ExternalRemoteClusterLabelName: remoteClusterName,
+			},
+			remoteca.Labels(owner.Name),
+		),
+	}
+}
+
+// externalRemoteCASecretName returns the name of the Secret that contains the transport CA of a remote cluster not
+// managed by ECK. Remote cluster names may contain characters which are not allowed in resource names.
+func externalRemoteCASecretName(localClusterName string, remoteClusterName string) string {
+	return esv1.ESNamer.Suffix(
+		fmt.Sprintf("%s-%s", localClusterName, strings.ReplaceAll(strings.ToLower(remoteClusterName), "_", "-")),
+		externalRemoteCASecretSuffix,
+	)
+}
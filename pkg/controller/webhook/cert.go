@@ -6,7 +6,6 @@ package webhook
 
 import (
 	cryptorand "crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"time"
@@ -36,6 +35,9 @@ func (w *Params) shouldRenewCertificates(serverCertificates *corev1.Secret, webh
 	if !certificates.CanReuseCA(serverCA, w.Rotation.RotateBefore) {
 		return true
 	}
+	if !certificates.PrivateKeyMatchesOptions(serverCA.PrivateKey, w.PrivateKeyOptions) {
+		return true
+	}
 	// Read the certificate in the webhook configuration
 	for _, webhook := range webhookConfiguration.Webhooks {
 		caBytes := webhook.ClientConfig.CABundle
@@ -74,7 +76,8 @@ func (w *Params) newCertificates(webhookConf *v1beta1.ValidatingWebhookConfigura
 			CommonName:         "elastic-webhook-ca",
 			OrganizationalUnit: []string{"elastic-webhook"},
 		},
-		ExpireIn: &w.Rotation.Validity,
+		ExpireIn:          &w.Rotation.Validity,
+		PrivateKeyOptions: w.PrivateKeyOptions,
 	})
 	if err != nil {
 		return webhookCertificates, err
@@ -82,11 +85,14 @@ func (w *Params) newCertificates(webhookConf *v1beta1.ValidatingWebhookConfigura
 	webhookCertificates.caCert = certificates.EncodePEMCert(ca.Cert.Raw)
 
 	// Create a new certificate for the webhook server
-	privateKey, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	privateKey, err := certificates.GeneratePrivateKey(w.PrivateKeyOptions)
+	if err != nil {
+		return webhookCertificates, err
+	}
+	webhookCertificates.serverKey, err = certificates.EncodePEMPrivateKey(privateKey)
 	if err != nil {
 		return webhookCertificates, err
 	}
-	webhookCertificates.serverKey = certificates.EncodePEMPrivateKey(*privateKey)
 
 	csr, err := x509.CreateCertificateRequest(cryptorand.Reader, &x509.CertificateRequest{}, privateKey)
 	if err != nil {
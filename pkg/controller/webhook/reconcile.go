@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/pkg/controller/common/certificates"
 )
 
@@ -20,7 +21,8 @@ type Params struct {
 	SecretName string
 
 	// Certificate options
-	Rotation certificates.RotationParams
+	Rotation          certificates.RotationParams
+	PrivateKeyOptions commonv1.PrivateKeyOptions
 }
 
 // ReconcileResources reconciles the certificates used by the webhook client and the webhook server.
@@ -290,6 +290,9 @@ func TestNewReporter(t *testing.T) {
     kibanas:
       pod_count: 0
       resource_count: 2
+    maps:
+      pod_count: 0
+      resource_count: 0
 `),
 	}
 
@@ -141,10 +141,14 @@ func TestCustomTransportCA(t *testing.T) {
 						if err != nil {
 							return err
 						}
+						privateKey, err := certificates.EncodePEMPrivateKey(ca.PrivateKey)
+						if err != nil {
+							return err
+						}
 
 						caSecret := mkTestSecret(
 							certificates.EncodePEMCert(ca.Cert.Raw),
-							certificates.EncodePEMPrivateKey(*ca.PrivateKey),
+							privateKey,
 						)
 						_, err = reconciler.ReconcileSecret(k.Client, caSecret, nil)
 						return err
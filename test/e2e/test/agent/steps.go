@@ -120,10 +120,14 @@ func (b Builder) CheckK8sTestSteps(k *test.K8sClient) test.StepList {
 				}
 				// don't check association statuses that may vary across tests
 				agent.Status.ElasticsearchAssociationsStatus = nil
+				// don't check the conditions whose messages and transition times vary across tests
+				agent.Status.Conditions = nil
 
 				expected := agentv1alpha1.AgentStatus{
 					Version: b.Agent.Spec.Version,
 					Health:  "green",
+					// the status must reflect the latest spec
+					ObservedGeneration: agent.Generation,
 				}
 				if b.Agent.Spec.Deployment != nil {
 					expectedReplicas := pointer.Int32OrDefault(b.Agent.Spec.Deployment.Replicas, int32(1))
@@ -7,6 +7,7 @@ package apmserver
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	apmv1 "github.com/elastic/cloud-on-k8s/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
@@ -215,6 +216,9 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 			// don't check association statuses that may vary across tests
 			as.Status.ElasticsearchAssociationStatus = ""
 			as.Status.KibanaAssociationStatus = ""
+			as.Status.MonitoringAssociationsStatus = nil
+			// don't check the conditions whose messages and transition times vary across tests
+			as.Status.Conditions = nil
 
 			expected := apmv1.ApmServerStatus{
 				ExternalService:       b.ApmServer.Name + "-apm-http",
@@ -223,9 +227,11 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 					AvailableNodes: b.ApmServer.Spec.Count,
 					Version:        b.ApmServer.Spec.Version,
 					Health:         "green",
+					// the status must reflect the latest spec
+					ObservedGeneration: as.Generation,
 				},
 			}
-			if as.Status != expected {
+			if !reflect.DeepEqual(as.Status, expected) {
 				return fmt.Errorf("expected status %+v but got %+v", expected, as.Status)
 			}
 			return nil
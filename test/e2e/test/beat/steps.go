@@ -7,6 +7,7 @@ package beat
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/pkg/apis/beat/v1beta1"
@@ -119,10 +120,15 @@ func (b Builder) CheckK8sTestSteps(k *test.K8sClient) test.StepList {
 				// don't check association statuses that may vary across tests
 				beat.Status.ElasticsearchAssociationStatus = ""
 				beat.Status.KibanaAssociationStatus = ""
+				beat.Status.MonitoringAssociationsStatus = nil
+				// don't check the conditions whose messages and transition times vary across tests
+				beat.Status.Conditions = nil
 
 				expected := beatv1beta1.BeatStatus{
 					Version: b.Beat.Spec.Version,
 					Health:  "green",
+					// the status must reflect the latest spec
+					ObservedGeneration: beat.Generation,
 				}
 				if b.Beat.Spec.Deployment != nil {
 					expectedReplicas := pointer.Int32OrDefault(b.Beat.Spec.Deployment.Replicas, int32(1))
@@ -133,7 +139,7 @@ func (b Builder) CheckK8sTestSteps(k *test.K8sClient) test.StepList {
 					beat.Status.ExpectedNodes = 0
 					beat.Status.AvailableNodes = 0
 				}
-				if beat.Status != expected {
+				if !reflect.DeepEqual(beat.Status, expected) {
 					return fmt.Errorf("expected status %+v but got %+v", expected, beat.Status)
 				}
 				return nil
@@ -43,6 +43,7 @@ func (b Builder) CheckK8sTestSteps(k *test.K8sClient) test.StepList {
 		CheckTransportCertificateAuthority(b, k),
 		CheckExpectedPodsEventuallyReady(b, k),
 		CheckESVersion(b, k),
+		CheckESObservedGeneration(b, k),
 		CheckServices(b, k),
 		CheckServicesEndpoints(b, k),
 		CheckSecrets(b, k),
@@ -308,6 +309,23 @@ func CheckESVersion(b Builder, k *test.K8sClient) test.Step {
 	}
 }
 
+// CheckESObservedGeneration checks that the ES status eventually reflects the latest generation of the spec
+func CheckESObservedGeneration(b Builder, k *test.K8sClient) test.Step {
+	return test.Step{
+		Name: "ES status should report the latest observed generation",
+		Test: test.Eventually(func() error {
+			var es esv1.Elasticsearch
+			if err := k.Client.Get(context.Background(), k8s.ExtractNamespacedName(&b.Elasticsearch), &es); err != nil {
+				return err
+			}
+			if es.Status.ObservedGeneration != es.Generation {
+				return fmt.Errorf("observed generation %d in status does not match generation %d", es.Status.ObservedGeneration, es.Generation)
+			}
+			return nil
+		}),
+	}
+}
+
 // CheckClusterHealth checks that the given ES status reports a green ES health
 func CheckClusterHealth(b Builder, k *test.K8sClient) test.Step {
 	return test.Step{
@@ -7,6 +7,7 @@ package enterprisesearch
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	entv1 "github.com/elastic/cloud-on-k8s/pkg/apis/enterprisesearch/v1"
@@ -222,16 +223,22 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 			if err := k.Client.Get(context.Background(), k8s.ExtractNamespacedName(&b.EnterpriseSearch), &ent); err != nil {
 				return err
 			}
+			// don't check the monitoring association statuses that may vary across tests
+			ent.Status.MonitoringAssociationsStatus = nil
+			// don't check the conditions whose messages and transition times vary across tests
+			ent.Status.Conditions = nil
 			expected := entv1.EnterpriseSearchStatus{
 				DeploymentStatus: commonv1.DeploymentStatus{
 					AvailableNodes: b.EnterpriseSearch.Spec.Count,
 					Version:        b.EnterpriseSearch.Spec.Version,
 					Health:         "green",
+					// the status must reflect the latest spec
+					ObservedGeneration: ent.Generation,
 				},
 				ExternalService: b.EnterpriseSearch.Name + "-ent-http",
 				Association:     commonv1.AssociationEstablished,
 			}
-			if ent.Status != expected {
+			if !reflect.DeepEqual(ent.Status, expected) {
 				return fmt.Errorf("expected status %+v but got %+v", expected, ent.Status)
 			}
 			return nil
@@ -7,6 +7,7 @@ package kibana
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/pkg/apis/kibana/v1"
@@ -209,15 +210,20 @@ func CheckStatus(b Builder, k *test.K8sClient) test.Step {
 			}
 			// don't check the association status that may vary across tests
 			kb.Status.AssociationStatus = ""
+			kb.Status.MonitoringAssociationsStatus = nil
+			// don't check the conditions whose messages and transition times vary across tests
+			kb.Status.Conditions = nil
 			expected := kbv1.KibanaStatus{
 				DeploymentStatus: commonv1.DeploymentStatus{
 					AvailableNodes: b.Kibana.Spec.Count,
 					Version:        b.Kibana.Spec.Version,
 					Health:         "green",
+					// the status must reflect the latest spec
+					ObservedGeneration: kb.Generation,
 				},
 				AssociationStatus: "",
 			}
-			if kb.Status != expected {
+			if !reflect.DeepEqual(kb.Status, expected) {
 				return fmt.Errorf("expected status %+v but got %+v", expected, kb.Status)
 			}
 			return nil